@@ -0,0 +1,519 @@
+// Package catalog contains a generator for data catalog metadata of GraphQL
+// Documents. Types are described as datasets, and their fields as the
+// fields of a dataset's schema, so they can be ingested by DataHub, as
+// metadata change events, or by OpenLineage consumers, as dataset events.
+//
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/ir"
+	"github.com/gqlc/graphql/ast"
+	"go.uber.org/zap"
+)
+
+// Formats the metadata can be written as.
+const (
+	DataHub     = "DATAHUB"
+	OpenLineage = "OPENLINEAGE"
+)
+
+// Producer identifies gqlc as the producer of OpenLineage events.
+const Producer = "https://github.com/gqlc/gqlc"
+
+// OpenLineage schema URLs of the events and facets written.
+const (
+	datasetEventURL       = "https://openlineage.io/spec/2-0-2/OpenLineage.json#/$defs/DatasetEvent"
+	schemaFacetURL        = "https://openlineage.io/spec/facets/1-1-1/SchemaDatasetFacet.json#/$defs/SchemaDatasetFacet"
+	documentationFacetURL = "https://openlineage.io/spec/facets/1-0-1/DocumentationDatasetFacet.json#/$defs/DocumentationDatasetFacet"
+)
+
+// deprecatedTag tags deprecated fields in DataHub.
+const deprecatedTag = "urn:li:tag:deprecated"
+
+// now returns the time events are stamped with.
+var now = time.Now
+
+// Options contains the options for the catalog generator.
+type Options struct {
+	// Format is either DATAHUB or OPENLINEAGE (default: DATAHUB)
+	Format string
+
+	// Namespace datasets are named in (default: the document name)
+	Namespace string
+
+	// Platform of DataHub datasets (default: graphql)
+	Platform string
+
+	// Env is the DataHub environment, or fabric, of datasets (default: PROD)
+	Env string
+}
+
+// Generator generates data catalog metadata for a GraphQL schema.
+type Generator struct {
+	bytes.Buffer
+
+	log *zap.Logger
+}
+
+// Generate generates the metadata of every object, interface and input type
+// in the given document, except for the root operation types. It's named
+// after the document and its format e.g. test.gql generates
+// test.datahub.json, or test.openlineage.jsonl.
+//
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "catalog",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("catalog").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	gOpts, err := getOptions(doc, opts)
+	if err != nil {
+		return
+	}
+
+	g.log.Info("generating metadata")
+	s, err := ir.Load(doc)
+	if err != nil {
+		return
+	}
+
+	ds := datasets(s)
+	switch gOpts.Format {
+	case DataHub:
+		err = g.writeDataHub(gOpts, ds)
+	case OpenLineage:
+		err = g.writeOpenLineage(gOpts, ds)
+	default:
+		err = fmt.Errorf("unknown catalog format: %s", gOpts.Format)
+	}
+	if err != nil {
+		return
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	f, err := gCtx.Open(fileName(doc, gOpts.Format))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	_, err = g.WriteTo(f)
+	return
+}
+
+func docName(doc *ast.Document) string {
+	base := filepath.Base(doc.Name)
+	name := base[:len(base)-len(filepath.Ext(base))]
+	if name == "" {
+		name = "schema"
+	}
+	return name
+}
+
+// fileName returns the name of the metadata generated for a document.
+func fileName(doc *ast.Document, format string) string {
+	if format == OpenLineage {
+		return docName(doc) + ".openlineage.jsonl"
+	}
+	return docName(doc) + "." + strings.ToLower(format) + ".json"
+}
+
+// dataset is a type, described as a dataset.
+type dataset struct {
+	name  string
+	kind  string
+	descr string
+
+	fields []*field
+	sdl    string
+}
+
+// field is a field of a dataset.
+type field struct {
+	name     string
+	typ      string
+	nullable bool
+	descr    string
+
+	// base is the named type at the bottom of the field's type
+	base string
+	list bool
+
+	deprecated bool
+}
+
+// datasets returns a dataset for every object, interface and input type of
+// a schema, except for the root operation types, which don't describe
+// data.
+//
+func datasets(s *ir.Schema) (ds []*dataset) {
+	for _, t := range s.Types() {
+		d := &dataset{name: t.Name(), descr: t.Description()}
+		switch t.Kind() {
+		case ir.Object:
+			if s.IsRoot(t) {
+				continue
+			}
+
+			d.kind = "object"
+			for _, f := range t.Fields() {
+				d.fields = append(d.fields, newField(f.Name(), f.Type(), f.Description(), f))
+			}
+		case ir.Interface:
+			d.kind = "interface"
+			for _, f := range t.Fields() {
+				d.fields = append(d.fields, newField(f.Name(), f.Type(), f.Description(), f))
+			}
+		case ir.Input:
+			d.kind = "input"
+			for _, f := range t.InputFields() {
+				d.fields = append(d.fields, newField(f.Name(), f.Type(), f.Description(), f))
+			}
+		default:
+			continue
+		}
+
+		d.sdl = sdl(d)
+		ds = append(ds, d)
+	}
+	return
+}
+
+// deprecatable is a field, or an input field.
+type deprecatable interface {
+	Deprecated() (string, bool)
+}
+
+func newField(name string, typ ir.Type, descr string, dep deprecatable) *field {
+	f := &field{
+		name:     name,
+		typ:      typ.String(),
+		nullable: ir.IsNullable(typ),
+		descr:    descr,
+		list:     ir.IsList(typ),
+	}
+
+	f.base = ir.Unwrap(typ).Name()
+	if ir.Unwrap(typ).Kind() == ir.Enum {
+		f.base = "enum"
+	}
+
+	_, f.deprecated = dep.Deprecated()
+	return f
+}
+
+// sdl returns the raw schema of a dataset i.e. its type declaration.
+func sdl(d *dataset) string {
+	var b strings.Builder
+	switch d.kind {
+	case "input":
+		b.WriteString("input ")
+	case "interface":
+		b.WriteString("interface ")
+	default:
+		b.WriteString("type ")
+	}
+	b.WriteString(d.name)
+	b.WriteString(" {\n")
+	for _, f := range d.fields {
+		b.WriteString("  ")
+		b.WriteString(f.name)
+		b.WriteString(": ")
+		b.WriteString(f.typ)
+		b.WriteByte('\n')
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// dataHubTypes maps GraphQL types to DataHub field types. Every other
+// scalar is a string, since that's how they're serialized most often.
+//
+var dataHubTypes = map[string]string{
+	"Int":     "NumberType",
+	"Float":   "NumberType",
+	"String":  "StringType",
+	"ID":      "StringType",
+	"Boolean": "BooleanType",
+	"enum":    "EnumType",
+}
+
+// dataHubType returns the DataHub type of a field.
+func dataHubType(f *field, records map[string]bool) string {
+	switch {
+	case f.list:
+		return "ArrayType"
+	case records[f.base]:
+		return "RecordType"
+	}
+
+	t, ok := dataHubTypes[f.base]
+	if !ok {
+		return "StringType"
+	}
+	return t
+}
+
+// writeDataHub writes the datasets as a file of DataHub metadata change
+// events, which DataHub's file source ingests.
+//
+func (g *Generator) writeDataHub(gOpts *Options, ds []*dataset) error {
+	records := make(map[string]bool, len(ds))
+	for _, d := range ds {
+		records[d.name] = true
+	}
+
+	platform := "urn:li:dataPlatform:" + gOpts.Platform
+	events := make([]object, 0, len(ds))
+	for _, d := range ds {
+		fields := make([]object, 0, len(d.fields))
+		for _, f := range d.fields {
+			fo := object{
+				{"fieldPath", f.name},
+				{"nullable", f.nullable},
+				{"type", object{{"type", object{{"com.linkedin.pegasus2avro.schema." + dataHubType(f, records), object{}}}}}},
+				{"nativeDataType", f.typ},
+				{"recursive", f.base == d.name},
+			}
+			if f.descr != "" {
+				fo = append(fo, member{"description", f.descr})
+			}
+			if f.deprecated {
+				fo = append(fo, member{"globalTags", object{{"tags", []object{{{"tag", deprecatedTag}}}}}})
+			}
+			fields = append(fields, fo)
+		}
+
+		props := object{{"customProperties", object{{"kind", d.kind}}}}
+		if d.descr != "" {
+			props = append(props, member{"description", d.descr})
+		}
+
+		urn := fmt.Sprintf("urn:li:dataset:(%s,%s.%s,%s)", platform, gOpts.Namespace, d.name, gOpts.Env)
+		events = append(events, object{
+			{"auditHeader", nil},
+			{"proposedSnapshot", object{{"com.linkedin.pegasus2avro.metadata.snapshot.DatasetSnapshot", object{
+				{"urn", urn},
+				{"aspects", []object{
+					{{"com.linkedin.pegasus2avro.dataset.DatasetProperties", props}},
+					{{"com.linkedin.pegasus2avro.schema.SchemaMetadata", object{
+						{"schemaName", d.name},
+						{"platform", platform},
+						{"version", 0},
+						{"hash", ""},
+						{"platformSchema", object{{"com.linkedin.pegasus2avro.schema.OtherSchema", object{{"rawSchema", d.sdl}}}}},
+						{"fields", fields},
+					}}},
+				}},
+			}}}},
+			{"proposedDelta", nil},
+		})
+	}
+	return g.encode(events)
+}
+
+// writeOpenLineage writes the datasets as OpenLineage dataset events, one
+// per line, each with a schema facet and, if the type is described, a
+// documentation facet.
+//
+func (g *Generator) writeOpenLineage(gOpts *Options, ds []*dataset) error {
+	eventTime := now().UTC().Format(time.RFC3339)
+
+	for _, d := range ds {
+		fields := make([]object, 0, len(d.fields))
+		for _, f := range d.fields {
+			fo := object{{"name", f.name}, {"type", f.typ}}
+			if f.descr != "" {
+				fo = append(fo, member{"description", f.descr})
+			}
+			fields = append(fields, fo)
+		}
+
+		facets := object{{"schema", object{
+			{"_producer", Producer},
+			{"_schemaURL", schemaFacetURL},
+			{"fields", fields},
+		}}}
+		if d.descr != "" {
+			facets = append(facets, member{"documentation", object{
+				{"_producer", Producer},
+				{"_schemaURL", documentationFacetURL},
+				{"description", d.descr},
+			}})
+		}
+
+		b, err := marshal(object{
+			{"eventTime", eventTime},
+			{"producer", Producer},
+			{"schemaURL", datasetEventURL},
+			{"dataset", object{
+				{"namespace", gOpts.Namespace},
+				{"name", d.name},
+				{"facets", facets},
+			}},
+		})
+		if err != nil {
+			return err
+		}
+
+		g.Write(b)
+		g.WriteByte('\n')
+	}
+	return nil
+}
+
+func (g *Generator) encode(v interface{}) error {
+	b, err := marshal(v)
+	if err != nil {
+		return err
+	}
+
+	err = json.Indent(&g.Buffer, b, "", "  ")
+	if err != nil {
+		return err
+	}
+	g.WriteByte('\n')
+	return nil
+}
+
+// member is a single key/value pair of an object.
+type member struct {
+	key string
+	val interface{}
+}
+
+// object is a JSON object which keeps the order of its members, so the
+// metadata reads like the examples of each format.
+//
+type object []member
+
+// MarshalJSON implements the json.Marshaler interface.
+func (o object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+
+		val, err := marshal(m.val)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(val)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Format:    DataHub,
+		Namespace: docName(doc),
+		Platform:  "graphql",
+		Env:       "PROD",
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "catalog" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		cOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range cOpts.Fields {
+			v := arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+			switch arg.Key.Name {
+			case "format":
+				gOpts.Format = v
+			case "namespace":
+				gOpts.Namespace = unquote(v)
+			case "platform":
+				gOpts.Platform = unquote(v)
+			case "env":
+				gOpts.Env = unquote(v)
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts != nil {
+		if f, ok := opts["format"].(string); ok {
+			gOpts.Format = f
+		}
+		if n, ok := opts["namespace"].(string); ok {
+			gOpts.Namespace = unquote(n)
+		}
+		if p, ok := opts["platform"].(string); ok {
+			gOpts.Platform = unquote(p)
+		}
+		if e, ok := opts["env"].(string); ok {
+			gOpts.Env = unquote(e)
+		}
+	}
+
+	gOpts.Format = strings.ToUpper(strings.Trim(gOpts.Format, `"`))
+	gOpts.Env = strings.ToUpper(gOpts.Env)
+	if gOpts.Namespace == "" || gOpts.Platform == "" {
+		return gOpts, fmt.Errorf("namespace and platform must not be empty")
+	}
+	return
+}
+
+func unquote(s string) string {
+	u, err := strconv.Unquote(s)
+	if err != nil {
+		return strings.Trim(s, `"`)
+	}
+	return u
+}
@@ -0,0 +1,407 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/ir"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "test.datahub.json", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, parser.ParseComments)
+	if err != nil {
+		panic(err)
+	}
+
+	// Keep event times stable
+	now = func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected catalog output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected catalog output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+const datasetsSrc = `schema {
+	query: Root
+}
+
+type Root {
+	node: Node
+}
+
+"A thing with an id."
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	"Their role."
+	role: Role @deprecated
+	friends: [User!]
+	best: User
+	joined: Time
+}
+
+input Filter {
+	limit: Int = 10
+}
+
+enum Role {
+	ADMIN
+}
+
+scalar Time`
+
+func TestDatasets(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(datasetsSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	s, err := ir.Load(doc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ds := datasets(s)
+
+	var names []string
+	for _, d := range ds {
+		names = append(names, d.name+":"+d.kind)
+	}
+	if s := strings.Join(names, ","); s != "Node:interface,User:object,Filter:input" {
+		t.Fatalf("expected datasets Node, User and Filter, but got: %s", s)
+	}
+
+	if ds[0].descr != "A thing with an id." {
+		t.Errorf("unexpected description: %q", ds[0].descr)
+	}
+
+	records := map[string]bool{"Node": true, "User": true, "Filter": true}
+	testCases := []struct {
+		Field    string
+		Type     string
+		HubType  string
+		Nullable bool
+	}{
+		{Field: "id", Type: "ID!", HubType: "StringType"},
+		{Field: "role", Type: "Role", HubType: "EnumType", Nullable: true},
+		{Field: "friends", Type: "[User!]", HubType: "ArrayType", Nullable: true},
+		{Field: "best", Type: "User", HubType: "RecordType", Nullable: true},
+		{Field: "joined", Type: "Time", HubType: "StringType", Nullable: true},
+	}
+
+	user := ds[1]
+	if len(user.fields) != len(testCases) {
+		t.Fatalf("expected %d fields, but got: %d", len(testCases), len(user.fields))
+	}
+	for i, testCase := range testCases {
+		f := user.fields[i]
+		if f.name != testCase.Field || f.typ != testCase.Type || f.nullable != testCase.Nullable {
+			t.Errorf("expected field %s: %s (nullable: %v), but got: %s: %s (nullable: %v)", testCase.Field, testCase.Type, testCase.Nullable, f.name, f.typ, f.nullable)
+		}
+		if typ := dataHubType(f, records); typ != testCase.HubType {
+			t.Errorf("expected %s to be a %s, but got: %s", f.name, testCase.HubType, typ)
+		}
+	}
+
+	if !user.fields[1].deprecated || user.fields[1].descr != "Their role." {
+		t.Errorf("expected role to be deprecated and described, but got: %#v", user.fields[1])
+	}
+	if user.fields[3].list || user.fields[3].base != "User" {
+		t.Errorf("expected best to be a User, but got: %#v", user.fields[3])
+	}
+
+	ex := "type User {\n  id: ID!\n  role: Role\n  friends: [User!]\n  best: User\n  joined: Time\n}"
+	if user.sdl != ex {
+		t.Errorf("expected raw schema:\n%s\nbut got:\n%s", ex, user.sdl)
+	}
+}
+
+func TestOpenLineage(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(`"A person."
+type User {
+	"Their name."
+	name: String!
+	age: Int
+}
+
+input Filter {
+	name: String
+}`), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err = new(Generator).Generate(ctx, doc, map[string]interface{}{"format": "openlineage", "namespace": "graphql://api"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := `{"eventTime":"2020-01-02T03:04:05Z","producer":"https://github.com/gqlc/gqlc","schemaURL":"https://openlineage.io/spec/2-0-2/OpenLineage.json#/$defs/DatasetEvent","dataset":{"namespace":"graphql://api","name":"User","facets":{"schema":{"_producer":"https://github.com/gqlc/gqlc","_schemaURL":"https://openlineage.io/spec/facets/1-1-1/SchemaDatasetFacet.json#/$defs/SchemaDatasetFacet","fields":[{"name":"name","type":"String!","description":"Their name."},{"name":"age","type":"Int"}]},"documentation":{"_producer":"https://github.com/gqlc/gqlc","_schemaURL":"https://openlineage.io/spec/facets/1-0-1/DocumentationDatasetFacet.json#/$defs/DocumentationDatasetFacet","description":"A person."}}}}
+{"eventTime":"2020-01-02T03:04:05Z","producer":"https://github.com/gqlc/gqlc","schemaURL":"https://openlineage.io/spec/2-0-2/OpenLineage.json#/$defs/DatasetEvent","dataset":{"namespace":"graphql://api","name":"Filter","facets":{"schema":{"_producer":"https://github.com/gqlc/gqlc","_schemaURL":"https://openlineage.io/spec/facets/1-1-1/SchemaDatasetFacet.json#/$defs/SchemaDatasetFacet","fields":[{"name":"name","type":"String"}]}}}}
+`
+	gen.CompareBytes(t, []byte(ex), b.Bytes())
+}
+
+func TestOptions(t *testing.T) {
+	doc := &ast.Document{
+		Name: "dir/api.gql",
+		Directives: []*ast.DirectiveLit{
+			{
+				Name: "catalog",
+				Args: &ast.CallExpr{Args: []*ast.Arg{{
+					Name: &ast.Ident{Name: "options"},
+					Value: &ast.Arg_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+						Fields: []*ast.ObjLit_Pair{
+							{
+								Key: &ast.Ident{Name: "format"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_IDENT, Value: OpenLineage}}},
+							},
+							{
+								Key: &ast.Ident{Name: "platform"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: `"hasura"`}}},
+							},
+							{
+								Key: &ast.Ident{Name: "env"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: `"dev"`}}},
+							},
+						},
+					}}}},
+				}}},
+			},
+		},
+	}
+
+	gOpts, err := getOptions(doc, map[string]interface{}{"format": "datahub"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := Options{Format: DataHub, Namespace: "api", Platform: "hasura", Env: "DEV"}
+	if *gOpts != ex {
+		t.Errorf("expected options: %#v, but got: %#v", ex, gOpts)
+	}
+
+	gOpts, err = getOptions(doc, map[string]interface{}{"namespace": `"shop"`})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.Format != OpenLineage || gOpts.Namespace != "shop" {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+
+	for format, ex := range map[string]string{DataHub: "api.datahub.json", OpenLineage: "api.openlineage.jsonl"} {
+		if name := fileName(doc, format); name != ex {
+			t.Errorf("expected file name %s, but got: %s", ex, name)
+		}
+	}
+
+	_, err = getOptions(doc, map[string]interface{}{"platform": ""})
+	if err == nil {
+		t.Error("expected error for empty platform")
+	}
+
+	err = new(Generator).Generate(gen.WithContext(context.Background(), gen.TestCtx{Writer: ioutil.Discard}), doc, map[string]interface{}{"format": "atlas"})
+	if err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := &Generator{}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := &Generator{}
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `type Query {
+	me: User
+}
+
+"A user."
+type User {
+	name: String!
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, nil)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Print(b.String())
+	// Output:
+	// [
+	//   {
+	//     "auditHeader": null,
+	//     "proposedSnapshot": {
+	//       "com.linkedin.pegasus2avro.metadata.snapshot.DatasetSnapshot": {
+	//         "urn": "urn:li:dataset:(urn:li:dataPlatform:graphql,example.User,PROD)",
+	//         "aspects": [
+	//           {
+	//             "com.linkedin.pegasus2avro.dataset.DatasetProperties": {
+	//               "customProperties": {
+	//                 "kind": "object"
+	//               },
+	//               "description": "A user."
+	//             }
+	//           },
+	//           {
+	//             "com.linkedin.pegasus2avro.schema.SchemaMetadata": {
+	//               "schemaName": "User",
+	//               "platform": "urn:li:dataPlatform:graphql",
+	//               "version": 0,
+	//               "hash": "",
+	//               "platformSchema": {
+	//                 "com.linkedin.pegasus2avro.schema.OtherSchema": {
+	//                   "rawSchema": "type User {\n  name: String!\n}"
+	//                 }
+	//               },
+	//               "fields": [
+	//                 {
+	//                   "fieldPath": "name",
+	//                   "nullable": false,
+	//                   "type": {
+	//                     "type": {
+	//                       "com.linkedin.pegasus2avro.schema.StringType": {}
+	//                     }
+	//                   },
+	//                   "nativeDataType": "String!",
+	//                   "recursive": false
+	//                 }
+	//               ]
+	//             }
+	//           }
+	//         ]
+	//       }
+	//     },
+	//     "proposedDelta": null
+	//   }
+	// ]
+}
@@ -0,0 +1,101 @@
+// types.go contains the GraphQL types this generator supports
+
+package catalog
+
+import (
+	"github.com/gqlc/gqlc/types"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+var catalogTypes = []*ast.TypeDecl{
+	{
+		Tok: token.Token_DIRECTIVE,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "catalog"},
+			Type: &ast.TypeSpec_Directive{Directive: &ast.DirectiveType{
+				Locs: []*ast.DirectiveLocation{{Loc: ast.DirectiveLocation_DOCUMENT}},
+				Args: &ast.InputValueList{
+					List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "options"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "CatalogOptions"},
+							},
+						},
+					},
+				},
+			}},
+		}},
+	},
+	{
+		Tok: token.Token_INPUT,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "CatalogOptions"},
+			Type: &ast.TypeSpec_Input{Input: &ast.InputType{
+				Fields: &ast.InputValueList{
+					List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "format"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "CatalogFormat"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_IDENT,
+								Value: DataHub,
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "namespace"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+						},
+						{
+							Name: &ast.Ident{Name: "platform"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_STRING,
+								Value: "\"graphql\"",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "env"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_STRING,
+								Value: "\"PROD\"",
+							}},
+						},
+					},
+				},
+			}},
+		}},
+	},
+	{
+		Tok: token.Token_ENUM,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "CatalogFormat"},
+			Type: &ast.TypeSpec_Enum{Enum: &ast.EnumType{
+				Values: &ast.FieldList{
+					List: []*ast.Field{
+						{
+							Name: &ast.Ident{Name: DataHub},
+						},
+						{
+							Name: &ast.Ident{Name: OpenLineage},
+						},
+					},
+				},
+			}},
+		}},
+	},
+}
+
+func init() {
+	types.Register(catalogTypes...)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"runtime/debug"
 
+	"github.com/gqlc/gqlc/codemod"
 	"github.com/gqlc/gqlc/gen"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
@@ -31,8 +32,9 @@ type CommandLine struct {
 	prefix string
 	fs     afero.Fs
 
-	cmds []cmder
-	gens []genConfig
+	cmds     []cmder
+	gens     []genConfig
+	codemods []codemod.Codemod
 }
 
 type cmder interface {
@@ -89,27 +91,41 @@ func (c *CommandLine) RegisterGenerator(g gen.Generator, name, opt, help string)
 	})
 }
 
+// AddCodemods registers codemods which are applied, in order, to the
+// schema before any generators are run. They are applied before any
+// codemods listed in a config file.
+//
+func (c *CommandLine) AddCodemods(cms ...codemod.Codemod) {
+	c.codemods = append(c.codemods, cms...)
+}
+
 func wrapPanic(err error, stack []byte) error {
 	return fmt.Errorf("gqlc: recovered from unexpected panic: %w\n\n%s", err, stack)
 }
 
+// panicError returns the error of a recovered panic, with the stack of the
+// goroutine which panicked. It must be called by the deferred function
+// which recovered it.
+//
+func panicError(r interface{}) error {
+	stack := debug.Stack()
+
+	rerr, ok := r.(error)
+	if ok {
+		return wrapPanic(rerr, stack)
+	}
+	return wrapPanic(fmt.Errorf("%#v", r), stack)
+}
+
 // Run executes the compiler
 func (c *CommandLine) Run(args []string) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			stack := debug.Stack()
-
-			rerr, ok := r.(error)
-			if ok {
-				err = wrapPanic(rerr, stack)
-				return
-			}
-
-			err = wrapPanic(fmt.Errorf("%#v", r), stack)
+			err = panicError(r)
 		}
 	}()
 
-	cmd := c.addCommand(c.newVersionCmd()).build()
+	cmd := c.addCommand(c.newVersionCmd(), c.newSearchCmd(), c.newRenameCmd(), c.newDiffCmd(), c.newInferCmd(), c.newConvertCmd(), c.newExplainCmd(), c.newHistoryCmd()).build()
 
 	cmd.SetArgs(args[1:])
 	return cmd.Execute()
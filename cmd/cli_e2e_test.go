@@ -8,9 +8,24 @@ import (
 	"testing"
 
 	"github.com/gqlc/compiler"
+	"github.com/gqlc/gqlc/csharp"
+	"github.com/gqlc/gqlc/diagram"
 	"github.com/gqlc/gqlc/doc"
 	"github.com/gqlc/gqlc/golang"
+	"github.com/gqlc/gqlc/gqlgen"
+	"github.com/gqlc/gqlc/introspection"
+	"github.com/gqlc/gqlc/java"
 	"github.com/gqlc/gqlc/js"
+	"github.com/gqlc/gqlc/jsonschema"
+	"github.com/gqlc/gqlc/kotlin"
+	"github.com/gqlc/gqlc/openapi"
+	"github.com/gqlc/gqlc/proto"
+	"github.com/gqlc/gqlc/python"
+	"github.com/gqlc/gqlc/rust"
+	"github.com/gqlc/gqlc/sdl"
+	"github.com/gqlc/gqlc/sql"
+	"github.com/gqlc/gqlc/swift"
+	"github.com/gqlc/gqlc/ts"
 	"github.com/gqlc/graphql/ast"
 	"github.com/spf13/afero"
 )
@@ -35,6 +50,12 @@ var GOLDENS = []goldenSuite{
 		out:   "/out/test.go",
 		opts:  "descriptions=true",
 	},
+	{
+		name:  "java",
+		input: "../java/test.gql",
+		ex:    "../java/Test.java",
+		out:   "/out/Test.java",
+	},
 	{
 		name:  "js",
 		input: "../js/test.gql",
@@ -42,6 +63,90 @@ var GOLDENS = []goldenSuite{
 		out:   "/out/test.js",
 		opts:  "descriptions=true",
 	},
+	{
+		name:  "ts",
+		input: "../ts/test.gql",
+		ex:    "../ts/test.ts",
+		out:   "/out/test.ts",
+	},
+	{
+		name:  "py",
+		input: "../python/test.gql",
+		ex:    "../python/test.py",
+		out:   "/out/test.py",
+	},
+	{
+		name:  "kt",
+		input: "../kotlin/test.gql",
+		ex:    "../kotlin/Test.kt",
+		out:   "/out/Test.kt",
+	},
+	{
+		name:  "rs",
+		input: "../rust/test.gql",
+		ex:    "../rust/test.rs",
+		out:   "/out/test.rs",
+	},
+	{
+		name:  "swift",
+		input: "../swift/test.gql",
+		ex:    "../swift/Test.swift",
+		out:   "/out/Test.swift",
+	},
+	{
+		name:  "cs",
+		input: "../csharp/test.gql",
+		ex:    "../csharp/Test.cs",
+		out:   "/out/Test.cs",
+	},
+	{
+		name:  "proto",
+		input: "../proto/test.gql",
+		ex:    "../proto/test.proto",
+		out:   "/out/test.proto",
+	},
+	{
+		name:  "jsonschema",
+		input: "../jsonschema/test.gql",
+		ex:    "../jsonschema/testdata/Point.schema.json",
+		out:   "/out/Point.schema.json",
+	},
+	{
+		name:  "openapi",
+		input: "../openapi/test.gql",
+		ex:    "../openapi/test.openapi.json",
+		out:   "/out/test.openapi.json",
+	},
+	{
+		name:  "sql",
+		input: "../sql/test.gql",
+		ex:    "../sql/test.sql",
+		out:   "/out/test.sql",
+	},
+	{
+		name:  "introspection",
+		input: "../introspection/test.gql",
+		ex:    "../introspection/test.introspection.json",
+		out:   "/out/test.introspection.json",
+	},
+	{
+		name:  "sdl",
+		input: "../sdl/test.gql",
+		ex:    "../sdl/testdata/test.gql",
+		out:   "/out/test.graphql",
+	},
+	{
+		name:  "diagram",
+		input: "../diagram/test.gql",
+		ex:    "../diagram/test.mmd",
+		out:   "/out/test.mmd",
+	},
+	{
+		name:  "gqlgen",
+		input: "../gqlgen/test.gql",
+		ex:    "../gqlgen/test.gotxt",
+		out:   "/out/models_gen.go",
+	},
 }
 
 var testTypes = []*ast.TypeDecl{
@@ -174,6 +279,20 @@ func TestE2E(t *testing.T) {
 	cli := NewCLI(WithFS(fs))
 	cli.AllowPlugins("gqlc-gen-")
 
+	// Register C# generator
+	cli.RegisterGenerator(new(csharp.Generator),
+		"cs_out",
+		"cs_opt",
+		"Generate C# source.",
+	)
+
+	// Register Diagram generator
+	cli.RegisterGenerator(new(diagram.Generator),
+		"diagram_out",
+		"diagram_opt",
+		"Generate Mermaid or Graphviz class diagrams.",
+	)
+
 	// Register Documentation generator
 	cli.RegisterGenerator(new(doc.Generator),
 		"doc_out",
@@ -188,6 +307,27 @@ func TestE2E(t *testing.T) {
 		"Generate Go source.",
 	)
 
+	// Register gqlgen generator
+	cli.RegisterGenerator(new(gqlgen.Generator),
+		"gqlgen_out",
+		"gqlgen_opt",
+		"Generate gqlgen models and config.",
+	)
+
+	// Register Introspection generator
+	cli.RegisterGenerator(new(introspection.Generator),
+		"introspection_out",
+		"introspection_opt",
+		"Generate introspection query results.",
+	)
+
+	// Register Java generator
+	cli.RegisterGenerator(new(java.Generator),
+		"java_out",
+		"java_opt",
+		"Generate Java source.",
+	)
+
 	// Register Javascript generator
 	cli.RegisterGenerator(new(js.Generator),
 		"js_out",
@@ -195,6 +335,76 @@ func TestE2E(t *testing.T) {
 		"Generate Javascript source.",
 	)
 
+	// Register JSON Schema generator
+	cli.RegisterGenerator(new(jsonschema.Generator),
+		"jsonschema_out",
+		"jsonschema_opt",
+		"Generate JSON Schemas.",
+	)
+
+	// Register Kotlin generator
+	cli.RegisterGenerator(new(kotlin.Generator),
+		"kt_out",
+		"kt_opt",
+		"Generate Kotlin source.",
+	)
+
+	// Register OpenAPI generator
+	cli.RegisterGenerator(new(openapi.Generator),
+		"openapi_out",
+		"openapi_opt",
+		"Generate OpenAPI documents.",
+	)
+
+	// Register Protocol Buffers generator
+	cli.RegisterGenerator(new(proto.Generator),
+		"proto_out",
+		"proto_opt",
+		"Generate Protocol Buffers definitions.",
+	)
+
+	// Register Python generator
+	cli.RegisterGenerator(new(python.Generator),
+		"py_out",
+		"py_opt",
+		"Generate Python source.",
+	)
+
+	// Register Rust generator
+	cli.RegisterGenerator(new(rust.Generator),
+		"rs_out",
+		"rs_opt",
+		"Generate Rust source.",
+	)
+
+	// Register SDL generator
+	cli.RegisterGenerator(new(sdl.Generator),
+		"sdl_out",
+		"sdl_opt",
+		"Generate formatted GraphQL SDL.",
+	)
+
+	// Register SQL generator
+	cli.RegisterGenerator(new(sql.Generator),
+		"sql_out",
+		"sql_opt",
+		"Generate SQL tables.",
+	)
+
+	// Register Swift generator
+	cli.RegisterGenerator(new(swift.Generator),
+		"swift_out",
+		"swift_opt",
+		"Generate Swift source.",
+	)
+
+	// Register TypeScript generator
+	cli.RegisterGenerator(new(ts.Generator),
+		"ts_out",
+		"ts_opt",
+		"Generate TypeScript source.",
+	)
+
 	for _, gold := range GOLDENS {
 		args := []string{
 			"gqlc",
@@ -231,8 +441,17 @@ func TestE2E(t *testing.T) {
 func initFs(fs afero.Fs, goldens []goldenSuite) (err error) {
 	for _, gold := range GOLDENS {
 		dname := gold.name
-		if dname == "go" {
+		switch dname {
+		case "go":
 			dname = "golang"
+		case "kt":
+			dname = "kotlin"
+		case "py":
+			dname = "python"
+		case "rs":
+			dname = "rust"
+		case "cs":
+			dname = "csharp"
 		}
 
 		err = fs.Mkdir(dname, os.ModeDir)
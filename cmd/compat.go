@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	goparser "go/parser"
+	"go/printer"
+	gotoken "go/token"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// compatCheck generates into memory, on top of the files committed from a
+// previous version of gqlc, so the code generated by this version can be
+// compared against them without overwriting them.
+//
+type compatCheck struct {
+	w io.Writer
+
+	base  afero.Fs
+	layer afero.Fs
+
+	mu    sync.Mutex
+	files []string
+}
+
+func initCompat(fs afero.Fs, cc **compatCheck) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		compat, err := cmd.Flags().GetBool("compat")
+		if !compat || err != nil {
+			return err
+		}
+
+		*cc = newCompatCheck(fs, cmd.OutOrStdout())
+		return nil
+	}
+}
+
+func newCompatCheck(fs afero.Fs, w io.Writer) *compatCheck {
+	return &compatCheck{
+		w:     w,
+		base:  fs,
+		layer: afero.NewMemMapFs(),
+	}
+}
+
+// fs returns the filesystem generators write to, which reads through to
+// the committed files.
+//
+func (c *compatCheck) fs() afero.Fs {
+	return afero.NewCopyOnWriteFs(afero.NewReadOnlyFs(c.base), c.layer)
+}
+
+// add records a generated file, if it hasn't been already.
+func (c *compatCheck) add(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, f := range c.files {
+		if f == name {
+			return
+		}
+	}
+	c.files = append(c.files, name)
+}
+
+// apiSymbol is a declaration in generated code, which code using it may
+// depend on.
+//
+type apiSymbol struct {
+	// key identifies the symbol in a file e.g. Query.Resolve for a Go method
+	key string
+
+	// sig is the declaration, without its body
+	sig string
+
+	// shape is sig without the name, so renamed symbols can be matched up
+	shape string
+}
+
+// apiChange is a difference between the symbols of a file generated by
+// two versions of gqlc.
+//
+type apiChange struct {
+	kind     string
+	old, new string
+}
+
+// report compares every generated file with the committed one, and
+// prints the differences between their symbols.
+//
+func (c *compatCheck) report() error {
+	sort.Strings(c.files)
+
+	var changedFiles int
+	var b bytes.Buffer
+	for _, name := range c.files {
+		newSrc, err := afero.ReadFile(c.layer, name)
+		if err != nil {
+			return err
+		}
+
+		oldSrc, err := afero.ReadFile(c.base, name)
+		if err != nil {
+			changedFiles++
+			fmt.Fprintf(&b, "\n%s: new file\n", name)
+			continue
+		}
+		if bytes.Equal(oldSrc, newSrc) {
+			continue
+		}
+		changedFiles++
+
+		changes, ok := compareSymbols(name, oldSrc, newSrc)
+		if !ok {
+			fmt.Fprintf(&b, "\n%s: changed\n", name)
+			continue
+		}
+		if len(changes) == 0 {
+			fmt.Fprintf(&b, "\n%s: changed, but no declarations did\n", name)
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n%s:\n", name)
+		for _, ch := range changes {
+			switch ch.kind {
+			case "added":
+				fmt.Fprintf(&b, "  %-8s %s\n", ch.kind, ch.new)
+			case "removed":
+				fmt.Fprintf(&b, "  %-8s %s\n", ch.kind, ch.old)
+			default:
+				fmt.Fprintf(&b, "  %-8s %s\n  %-8s -> %s\n", ch.kind, ch.old, "", ch.new)
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(c.w, "compared %d generated files with those committed, %d changed\n", len(c.files), changedFiles)
+	if err != nil {
+		return err
+	}
+	_, err = b.WriteTo(c.w)
+	return err
+}
+
+// compareSymbols lists the declarations renamed, changed, removed or
+// added between two versions of a file. It returns false if the symbols
+// of the file can't be found, e.g. it's documentation.
+//
+func compareSymbols(name string, oldSrc, newSrc []byte) ([]apiChange, bool) {
+	oldSyms, ok := fileSymbols(name, oldSrc)
+	if !ok {
+		return nil, false
+	}
+	newSyms, ok := fileSymbols(name, newSrc)
+	if !ok {
+		return nil, false
+	}
+
+	newByKey := make(map[string]apiSymbol, len(newSyms))
+	for _, s := range newSyms {
+		newByKey[s.key] = s
+	}
+	oldByKey := make(map[string]apiSymbol, len(oldSyms))
+	for _, s := range oldSyms {
+		oldByKey[s.key] = s
+	}
+
+	var changes, removedSyms []apiChange
+	var removedShapes []string
+	for _, o := range oldSyms {
+		n, ok := newByKey[o.key]
+		if !ok {
+			removedSyms = append(removedSyms, apiChange{kind: "removed", old: o.sig})
+			removedShapes = append(removedShapes, o.shape)
+			continue
+		}
+		if o.sig != n.sig {
+			changes = append(changes, apiChange{kind: "changed", old: o.sig, new: n.sig})
+		}
+	}
+
+	var addedSyms []apiChange
+	for _, n := range newSyms {
+		if _, ok := oldByKey[n.key]; ok {
+			continue
+		}
+
+		// A symbol which was removed, and added back under a new name
+		// with the same shape, was renamed
+		renamed := false
+		for i, shape := range removedShapes {
+			if shape == n.shape && removedSyms[i].kind == "removed" {
+				removedSyms[i].kind, removedSyms[i].new = "renamed", n.sig
+				renamed = true
+				break
+			}
+		}
+		if !renamed {
+			addedSyms = append(addedSyms, apiChange{kind: "added", new: n.sig})
+		}
+	}
+
+	// Renames go first, since they're the most likely to break code
+	sort.SliceStable(removedSyms, func(i, j int) bool {
+		return removedSyms[i].kind == "renamed" && removedSyms[j].kind != "renamed"
+	})
+
+	changes = append(changes, removedSyms...)
+	return append(changes, addedSyms...), true
+}
+
+// fileSymbols returns the declarations of a generated file. Go files are
+// parsed for their exported declarations, and the declarations of other
+// languages are found line by line.
+//
+func fileSymbols(name string, src []byte) ([]apiSymbol, bool) {
+	switch filepath.Ext(name) {
+	case ".go":
+		return goSymbols(src)
+	case ".md", ".markdown", ".html", ".txt", ".adoc", ".json", ".yaml", ".yml", ".csv", ".svg":
+		return nil, false
+	}
+	return declSymbols(src), true
+}
+
+func goSymbols(src []byte) (syms []apiSymbol, ok bool) {
+	fset := gotoken.NewFileSet()
+	f, err := goparser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil, false
+	}
+
+	// sym prints node, and then node with its name replaced, for its shape
+	sym := func(key string, name *goast.Ident, prefix string, node interface{}) apiSymbol {
+		s := apiSymbol{key: key, sig: prefix + printGo(fset, node)}
+
+		n := name.Name
+		name.Name = "_"
+		s.shape = prefix + printGo(fset, node)
+		name.Name = n
+		return s
+	}
+
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *goast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+
+			key := d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				recv := recvName(d.Recv.List[0].Type)
+				if !goast.IsExported(recv) {
+					continue
+				}
+				key = recv + "." + key
+			}
+
+			d.Body = nil
+			syms = append(syms, sym(key, d.Name, "", d))
+		case *goast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *goast.TypeSpec:
+					if s.Name.IsExported() {
+						syms = append(syms, sym(s.Name.Name, s.Name, "type ", s))
+					}
+				case *goast.ValueSpec:
+					for _, n := range s.Names {
+						if !n.IsExported() {
+							continue
+						}
+
+						vs := &goast.ValueSpec{Names: []*goast.Ident{n}, Type: s.Type}
+						syms = append(syms, sym(n.Name, n, d.Tok.String()+" ", vs))
+					}
+				}
+			}
+		}
+	}
+	return syms, true
+}
+
+// recvName returns the name of a method receiver's type.
+func recvName(expr goast.Expr) string {
+	switch v := expr.(type) {
+	case *goast.StarExpr:
+		return recvName(v.X)
+	case *goast.IndexExpr:
+		return recvName(v.X)
+	case *goast.Ident:
+		return v.Name
+	}
+	return ""
+}
+
+// printGo prints a Go node on a single line.
+func printGo(fset *gotoken.FileSet, node interface{}) string {
+	var b bytes.Buffer
+	printer.Fprint(&b, fset, node)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// declRe matches the start of a declaration in the languages gqlc
+// generates, e.g. export function name, pub struct Name or data class Name.
+//
+var declRe = regexp.MustCompile(`^\s*(?:(?:export|default|declare|public|private|protected|internal|open|pub(?:\([a-z]+\))?|static|abstract|final|sealed|data|async|readonly)\s+)*(?:function\*?|class|interface|type|enum|var|let|const|def|struct|trait|fn|fun|val|message|service|rpc|object|protocol|record|typealias|namespace|union|input|scalar)\s+([A-Za-z_$][\w$]*)`)
+
+// declSymbols finds the declarations in src, a line at a time. Their
+// signature is the line they're on, without any body it opens.
+//
+func declSymbols(src []byte) (syms []apiSymbol) {
+	seen := make(map[string]int)
+	for _, line := range strings.Split(string(src), "\n") {
+		m := declRe.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		name := line[m[2]:m[3]]
+
+		// Declarations are keyed by their kind and name, and how many of
+		// them came before, since a name may be declared more than once
+		// e.g. in different scopes.
+		//
+		key := strings.TrimSpace(line[:m[2]]) + " " + name
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key = fmt.Sprintf("%s#%d", key, n)
+		}
+
+		syms = append(syms, apiSymbol{
+			key:   key,
+			sig:   trimDecl(line),
+			shape: trimDecl(line[:m[2]] + "_" + line[m[3]:]),
+		})
+	}
+	return
+}
+
+// trimDecl trims the body a declaration opens from the end of its line.
+func trimDecl(line string) string {
+	return strings.TrimRight(strings.TrimSpace(line), " \t{([:;")
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/spf13/afero"
+)
+
+func TestCompareSymbols(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		File     string
+		Old, New string
+		Changes  []apiChange
+	}{
+		{
+			Name: "Go",
+			File: "schema.go",
+			Old: `package schema
+
+type Query struct {
+	Hello string
+}
+
+func (q *Query) Resolve(ctx context.Context) error { return nil }
+
+func NewQuery() *Query { return nil }
+
+func Old() {}
+
+func unexported() {}
+
+const Version = "1"
+`,
+			New: `package schema
+
+type Query struct {
+	Hello string
+	World string
+}
+
+func (q *Query) Resolve(ctx context.Context, args Args) error { return nil }
+
+func MakeQuery() *Query { return nil }
+
+func unexported(a int) {}
+
+const Version = "2"
+
+var Schema string
+`,
+			Changes: []apiChange{
+				{kind: "changed", old: "type Query struct { Hello string }", new: "type Query struct { Hello string World string }"},
+				{kind: "changed", old: "func (q *Query) Resolve(ctx context.Context) error", new: "func (q *Query) Resolve(ctx context.Context, args Args) error"},
+				{kind: "renamed", old: "func NewQuery() *Query", new: "func MakeQuery() *Query"},
+				{kind: "removed", old: "func Old()"},
+				{kind: "added", new: "var Schema string"},
+			},
+		},
+		{
+			Name: "JS",
+			File: "schema.js",
+			Old: `var QueryType = new GraphQLObjectType({
+  name: 'Query'
+});
+
+function resolve(source) {
+}
+
+export class Server {
+}
+`,
+			New: `var QueryObject = new GraphQLObjectType({
+  name: 'Query'
+});
+
+function resolve(source, args) {
+}
+`,
+			Changes: []apiChange{
+				{kind: "changed", old: "function resolve(source)", new: "function resolve(source, args)"},
+				{kind: "renamed", old: "var QueryType = new GraphQLObjectType", new: "var QueryObject = new GraphQLObjectType"},
+				{kind: "removed", old: "export class Server"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			changes, ok := compareSymbols(testCase.File, []byte(testCase.Old), []byte(testCase.New))
+			if !ok {
+				subT.Fatal("expected the symbols to be found")
+			}
+
+			if len(changes) != len(testCase.Changes) {
+				subT.Fatalf("expected %d changes, but got: %v", len(testCase.Changes), changes)
+			}
+			for i, ch := range changes {
+				if ch != testCase.Changes[i] {
+					subT.Errorf("expected: %v\nbut got: %v", testCase.Changes[i], ch)
+				}
+			}
+		})
+	}
+
+	t.Run("Docs", func(subT *testing.T) {
+		if _, ok := compareSymbols("index.md", []byte("# A"), []byte("# B")); ok {
+			subT.Error("expected no symbols for documentation")
+		}
+	})
+}
+
+func TestRun_Compat(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/compat/a.gql", []byte(`type A {
+	name: String
+}`), 0644)
+	afero.WriteFile(fs, "/out/a.js", []byte("function getA(source) {\n}\n"), 0644)
+
+	g := newMockGenerator(t)
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, doc *ast.Document, _ interface{}) error {
+		for name, src := range map[string]string{
+			"a.js":  "function fetchA(source) {\n}\n",
+			"b.js":  "function getB() {\n}\n",
+			"a.txt": "unchanged",
+		} {
+			f, err := gen.Context(ctx).Open(name)
+			if err != nil {
+				return err
+			}
+
+			_, err = f.Write([]byte(src))
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	var b bytes.Buffer
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners: []generator{{Generator: g, outDir: "/out"}},
+			ipaths: []string{"/compat"},
+			jobs:   1,
+			compat: newCompatCheck(fs, &b),
+		},
+	}
+	afero.WriteFile(fs, "/out/a.txt", []byte("unchanged"), 0644)
+
+	err := cmd.run(fs, "a.gql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ex := `compared 3 generated files with those committed, 2 changed
+
+/out/a.js:
+  renamed  function getA(source)
+           -> function fetchA(source)
+
+/out/b.js: new file
+`
+	if b.String() != ex {
+		t.Errorf("expected:\n%s\nbut got:\n%s", ex, b.String())
+	}
+
+	// The committed code mustn't be overwritten
+	src, _ := afero.ReadFile(fs, "/out/a.js")
+	if !strings.HasPrefix(string(src), "function getA") {
+		t.Errorf("expected the committed code to be kept, but got: %s", src)
+	}
+	if ok, _ := afero.Exists(fs, "/out/b.js"); ok {
+		t.Error("expected no new files to be written")
+	}
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/gqlc/gqlc/codemod"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// defaultConfigFile is loaded, if it exists, when no --config flag is given.
+const defaultConfigFile = "gqlc.yaml"
+
+// config represents a gqlc.yaml file.
+type config struct {
+	Codemods   []codemodConfig            `yaml:"codemods"`
+	Generators map[string]generatorConfig `yaml:"generators"`
+}
+
+// generatorConfig configures a generator by its name, e.g. js for --js_out.
+type generatorConfig struct {
+	Post commands `yaml:"post"`
+}
+
+// commands is either a single command or a list of them.
+type commands []string
+
+func (c *commands) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var cmd string
+	if err := unmarshal(&cmd); err == nil {
+		*c = commands{cmd}
+		return nil
+	}
+
+	var cmds []string
+	if err := unmarshal(&cmds); err != nil {
+		return fmt.Errorf("%s must be a command or a list of commands", postOpt)
+	}
+	*c = cmds
+	return nil
+}
+
+type codemodConfig struct {
+	Name string                 `yaml:"name"`
+	Args map[string]interface{} `yaml:"args"`
+}
+
+// loadConfig reads and decodes a gqlc.yaml file.
+func loadConfig(fs afero.Fs, name string) (*config, error) {
+	b, err := afero.ReadFile(fs, name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := new(config)
+	err = yaml.UnmarshalStrict(b, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("gqlc: invalid config file %s: %w", name, err)
+	}
+	return cfg, nil
+}
+
+// pipeline builds the configured codemods in order.
+func (cfg *config) pipeline() (p codemod.Pipeline, err error) {
+	for i, cc := range cfg.Codemods {
+		if cc.Name == "" {
+			return nil, fmt.Errorf("gqlc: codemod %d is missing a name", i)
+		}
+
+		c, err := codemod.New(cc.Name, cc.Args)
+		if err != nil {
+			return nil, err
+		}
+		p = append(p, c)
+	}
+	return
+}
+
+// postProcessors sets the post option of each generator which the config
+// file gives post-processors for, unless it's already given on the command
+// line.
+//
+func (cfg *config) postProcessors(geners []generator) {
+	for i, g := range geners {
+		gc, ok := cfg.Generators[g.name]
+		if !ok || len(gc.Post) == 0 {
+			continue
+		}
+		if _, set := g.opts[postOpt]; set {
+			continue
+		}
+
+		if g.opts == nil {
+			geners[i].opts = make(map[string]interface{})
+		}
+		geners[i].opts[postOpt] = []string(gc.Post)
+	}
+}
+
+// readConfig loads the config file given by the --config flag, or the
+// default config file, if it exists. A nil config is returned if neither
+// is found.
+//
+func readConfig(fs afero.Fs, cmd *cobra.Command) (*config, error) {
+	name, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return nil, err
+	}
+
+	if !cmd.Flags().Changed("config") {
+		exists, err := afero.Exists(fs, name)
+		if !exists || err != nil {
+			return nil, err
+		}
+	}
+
+	return loadConfig(fs, name)
+}
+
+// initCodemods loads the codemods from the config file, if any, and
+// appends them to any codemods registered through the Go API.
+//
+func initCodemods(fs afero.Fs, base []codemod.Codemod, p *codemod.Pipeline) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		*p = append((*p)[:0], base...)
+
+		cfg, err := readConfig(fs, cmd)
+		if cfg == nil || err != nil {
+			return err
+		}
+
+		cms, err := cfg.pipeline()
+		if err != nil {
+			return err
+		}
+		*p = append(*p, cms...)
+		return nil
+	}
+}
+
+// initConfigPost loads the post-processors of each generator from the
+// config file, if any.
+//
+func initConfigPost(fs afero.Fs, geners *[]generator) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cfg, err := readConfig(fs, cmd)
+		if cfg == nil || err != nil {
+			return err
+		}
+
+		cfg.postProcessors(*geners)
+		return nil
+	}
+}
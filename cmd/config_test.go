@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gqlc/compiler"
+	"github.com/gqlc/gqlc/codemod"
+	"github.com/gqlc/graphql/ast"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func TestInitCodemods(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Config string
+		Args   []string
+		Len    int
+		Err    bool
+	}{
+		{
+			Name: "NoConfig",
+			Len:  1,
+		},
+		{
+			Name: "Default",
+			Config: `codemods:
+  - name: prefixTypes
+    args:
+      prefix: V1
+  - name: stripFields
+    args:
+      directive: internal
+`,
+			Len: 3,
+		},
+		{
+			Name: "MissingFile",
+			Args: []string{"--config", "other.yaml"},
+			Err:  true,
+		},
+		{
+			Name:   "UnknownKey",
+			Config: "codemod:\n  - name: prefixTypes\n",
+			Err:    true,
+		},
+		{
+			Name:   "UnknownCodemod",
+			Config: "codemods:\n  - name: nope\n",
+			Err:    true,
+		},
+		{
+			Name:   "MissingName",
+			Config: "codemods:\n  - args: {prefix: V1}\n",
+			Err:    true,
+		},
+	}
+
+	base := []codemod.Codemod{codemod.Func(func(compiler.IR) error { return nil })}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			fs := afero.NewMemMapFs()
+			if testCase.Config != "" {
+				afero.WriteFile(fs, defaultConfigFile, []byte(testCase.Config), 0644)
+			}
+
+			cmd := &cobra.Command{}
+			cmd.Flags().String("config", defaultConfigFile, "")
+			if err := cmd.ParseFlags(testCase.Args); err != nil {
+				subT.Error(err)
+				return
+			}
+
+			var p codemod.Pipeline
+			err := initCodemods(fs, base, &p)(cmd, nil)
+			if testCase.Err {
+				if err == nil {
+					subT.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if len(p) != testCase.Len {
+				subT.Errorf("expected %d codemods, but got: %d", testCase.Len, len(p))
+			}
+		})
+	}
+}
+
+func TestInitConfigPost(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Config string
+		Opts   map[string]interface{}
+		Post   []postProcessor
+		Err    bool
+	}{
+		{
+			Name: "NoConfig",
+		},
+		{
+			Name:   "Command",
+			Config: "generators:\n  js:\n    post: prettier --write\n",
+			Post:   []postProcessor{{name: "prettier", args: []string{"--write"}}},
+		},
+		{
+			Name: "Commands",
+			Config: `generators:
+  js:
+    post:
+      - prettier --config "my cfg.json" --write
+      - eslint --fix
+`,
+			Post: []postProcessor{
+				{name: "prettier", args: []string{"--config", "my cfg.json", "--write"}},
+				{name: "eslint", args: []string{"--fix"}},
+			},
+		},
+		{
+			Name:   "OtherGenerator",
+			Config: "generators:\n  go:\n    post: gofmt -w\n",
+		},
+		{
+			Name:   "CommandLine",
+			Config: "generators:\n  js:\n    post: prettier --write\n",
+			Opts:   map[string]interface{}{postOpt: "eslint --fix"},
+			Post:   []postProcessor{{name: "eslint", args: []string{"--fix"}}},
+		},
+		{
+			Name:   "NotACommand",
+			Config: "generators:\n  js:\n    post: {cmd: prettier}\n",
+			Err:    true,
+		},
+		{
+			Name:   "UnknownKey",
+			Config: "generators:\n  js:\n    pre: prettier --write\n",
+			Err:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			fs := afero.NewMemMapFs()
+			if testCase.Config != "" {
+				afero.WriteFile(fs, defaultConfigFile, []byte(testCase.Config), 0644)
+			}
+
+			cmd := &cobra.Command{}
+			cmd.Flags().String("config", defaultConfigFile, "")
+
+			geners := []generator{{name: "js", opts: testCase.Opts}}
+			err := initConfigPost(fs, &geners)(cmd, nil)
+			if testCase.Err {
+				if err == nil {
+					subT.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			pps, err := getPostProcessors(geners[0].opts)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+			if !reflect.DeepEqual(pps, testCase.Post) {
+				subT.Errorf("expected post-processors: %v, but got: %v", testCase.Post, pps)
+			}
+		})
+	}
+}
+
+func TestRun_Codemods(t *testing.T) {
+	prefix, err := codemod.PrefixTypes("V1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := newMockGenerator(t)
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+			name := doc.Types[0].Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Name.Name
+			if name != "V1Version" {
+				t.Errorf("expected codemods to be applied before generating but got type: %s", name)
+			}
+			return nil
+		})
+
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners:   []generator{{Generator: g, name: "test", outDir: "/out"}},
+			codemods: codemod.Pipeline{prefix},
+		},
+	}
+
+	err = cmd.run(testFs, "/home/graphql/imports/thr.gql")
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"golang.org/x/term"
+)
+
+// hashFile records the hashes of the files generated into an output
+// directory, in the format of sha256sum, so edits made to them since
+// can be told apart from what gqlc wrote. Scaffolded files are recorded
+// as once instead, since they're never overwritten.
+//
+const hashFile = ".gqlc.sum"
+
+// onceHash is recorded in place of the hash of a scaffolded file
+const onceHash = "once"
+
+// What to do with a file which was modified since it was generated
+const (
+	modifiedPrompt = iota
+	modifiedForce
+	modifiedSkip
+)
+
+// outputHashes keeps generators from overwriting files which were edited
+// by hand since they were generated. Depending on its policy, they're
+// overwritten, skipped or the user is asked which, and without anyone to
+// ask, generating fails.
+//
+type outputHashes struct {
+	fs     afero.Fs
+	policy int
+
+	in  *bufio.Reader
+	out io.Writer
+
+	// interactive is whether there's a user to prompt
+	interactive bool
+
+	mu sync.Mutex
+
+	// dirs maps output directories to the hashes recorded in them
+	dirs map[string]map[string]string
+
+	// written maps the files generated to their output directory
+	written map[string]string
+
+	// scaffolded are the written files which are only written once
+	scaffolded map[string]bool
+}
+
+func initOutputHashes(fs afero.Fs, oh **outputHashes) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+
+		skip, err := cmd.Flags().GetBool("skip-modified")
+		if err != nil {
+			return err
+		}
+
+		policy := modifiedPrompt
+		switch {
+		case force && skip:
+			return fmt.Errorf("gqlc: --force and --skip-modified can't be used together")
+		case force:
+			policy = modifiedForce
+		case skip:
+			policy = modifiedSkip
+		}
+
+		*oh = newOutputHashes(fs, policy, cmd.InOrStdin(), cmd.ErrOrStderr())
+		return nil
+	}
+}
+
+func newOutputHashes(fs afero.Fs, policy int, in io.Reader, out io.Writer) *outputHashes {
+	return &outputHashes{
+		fs:          fs,
+		policy:      policy,
+		in:          bufio.NewReader(in),
+		out:         out,
+		interactive: isTerminal(in),
+		dirs:        make(map[string]map[string]string),
+		written:     make(map[string]string),
+		scaffolded:  make(map[string]bool),
+	}
+}
+
+// isTerminal reports whether r is a terminal someone can answer prompts
+// from. Readers which aren't files are assumed to be scripted answers.
+//
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return true
+	}
+
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// check reports whether fname, in the output directory dir, should be
+// written to, which it shouldn't if it was modified since it was generated
+// and isn't to be overwritten.
+//
+func (h *outputHashes) check(dir, fname string) (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.written[fname]; ok {
+		return true, nil
+	}
+
+	ok, err := h.overwrite(dir, fname)
+	if ok && err == nil {
+		h.written[fname] = dir
+	}
+	return ok, err
+}
+
+func (h *outputHashes) overwrite(dir, fname string) (bool, error) {
+	hashes, err := h.load(dir)
+	if err != nil {
+		return false, err
+	}
+
+	name := relName(dir, fname)
+	recorded, ok := hashes[name]
+	if !ok {
+		return true, nil
+	}
+
+	sum, err := hashOf(h.fs, fname)
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if recorded == onceHash {
+		zap.S().Infow("keeping scaffolded file", "file", fname)
+		return false, nil
+	}
+	if sum == recorded {
+		return true, nil
+	}
+
+	switch {
+	case h.policy == modifiedForce:
+		zap.S().Infow("overwriting modified file", "file", fname)
+		return true, nil
+	case h.policy == modifiedSkip:
+		zap.S().Infow("skipping modified file", "file", fname)
+		return false, nil
+	case !h.interactive:
+		return false, fmt.Errorf("gqlc: %s was modified since it was generated, pass --force to overwrite it or --skip-modified to keep it", fname)
+	}
+
+	for {
+		fmt.Fprintf(h.out, "%s was modified since it was generated. Overwrite it? [y]es, [n]o, [a]ll, [s]kip all: ", fname)
+
+		answer, err := h.in.ReadString('\n')
+		if err != nil && answer == "" {
+			return false, fmt.Errorf("gqlc: %s was modified since it was generated, and no answer was given whether to overwrite it", fname)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		case "a", "all":
+			h.policy = modifiedForce
+			return true, nil
+		case "s", "skip":
+			h.policy = modifiedSkip
+			return false, nil
+		}
+	}
+}
+
+// scaffold records that fname is only written once.
+func (h *outputHashes) scaffold(fname string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.scaffolded[fname] = true
+}
+
+// load reads the hashes recorded in dir, if it hasn't been already.
+func (h *outputHashes) load(dir string) (map[string]string, error) {
+	if hashes, ok := h.dirs[dir]; ok {
+		return hashes, nil
+	}
+
+	hashes := make(map[string]string)
+	b, err := afero.ReadFile(h.fs, filepath.Join(dir, hashFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	for i, line := range strings.Split(string(b), "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, "  ", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("gqlc: %s:%d: malformed hash", filepath.Join(dir, hashFile), i+1)
+		}
+		hashes[filepath.FromSlash(fields[1])] = fields[0]
+	}
+
+	h.dirs[dir] = hashes
+	return hashes, nil
+}
+
+// save records the hashes of the files generated, as they were left by
+// any post-processors, along with those of the files which weren't.
+//
+func (h *outputHashes) save() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	changed := make(map[string]bool)
+	for fname, dir := range h.written {
+		sum := onceHash
+		if !h.scaffolded[fname] {
+			var err error
+			sum, err = hashOf(h.fs, fname)
+			if os.IsNotExist(err) {
+				// It couldn't be opened, e.g. since it exceeded a limit
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		hashes, err := h.load(dir)
+		if err != nil {
+			return err
+		}
+		hashes[relName(dir, fname)] = sum
+		changed[dir] = true
+	}
+
+	for dir := range changed {
+		hashes := h.dirs[dir]
+
+		names := make([]string, 0, len(hashes))
+		for name := range hashes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var b strings.Builder
+		for _, name := range names {
+			fmt.Fprintf(&b, "%s  %s\n", hashes[name], filepath.ToSlash(name))
+		}
+
+		err := afero.WriteFile(h.fs, filepath.Join(dir, hashFile), []byte(b.String()), 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	h.written = make(map[string]string)
+	h.scaffolded = make(map[string]bool)
+	return nil
+}
+
+func relName(dir, fname string) string {
+	name, err := filepath.Rel(dir, fname)
+	if err != nil {
+		return fname
+	}
+	return name
+}
+
+func hashOf(fs afero.Fs, name string) (string, error) {
+	b, err := afero.ReadFile(fs, name)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// discard is written to instead of a file which is being kept.
+type discard struct{}
+
+func (discard) Write(p []byte) (int, error) { return ioutil.Discard.Write(p) }
+func (discard) Close() error                { return nil }
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/spf13/afero"
+)
+
+func TestRun_Modified(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Policy int
+		In     string
+		Src    string
+		Prompt bool
+		Err    string
+	}{
+		{
+			Name:   "Force",
+			Policy: modifiedForce,
+			Src:    "generated",
+		},
+		{
+			Name:   "Skip",
+			Policy: modifiedSkip,
+			Src:    "edited",
+		},
+		{
+			Name:   "Yes",
+			Policy: modifiedPrompt,
+			In:     "y\n",
+			Src:    "generated",
+			Prompt: true,
+		},
+		{
+			Name:   "No",
+			Policy: modifiedPrompt,
+			In:     "maybe\nn\n",
+			Src:    "edited",
+			Prompt: true,
+		},
+		{
+			Name:   "NoAnswer",
+			Policy: modifiedPrompt,
+			Src:    "edited",
+			Prompt: true,
+			Err:    "gqlc: /out/a.js was modified since it was generated, and no answer was given whether to overwrite it",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			fs := afero.NewMemMapFs()
+			afero.WriteFile(fs, "/in/a.gql", []byte("type A"), 0644)
+
+			g := newMockGenerator(subT)
+			g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).DoAndReturn(func(ctx context.Context, doc *ast.Document, _ interface{}) error {
+				f, err := gen.Context(ctx).Open("a.js")
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+
+				_, err = f.Write([]byte("generated"))
+				return err
+			})
+
+			var prompts bytes.Buffer
+			cmd := &gqlcCmd{
+				cfg: &gqlcConfig{
+					geners: []generator{{Generator: g, outDir: "/out"}},
+					ipaths: []string{"/in"},
+					jobs:   1,
+					hashes: newOutputHashes(fs, testCase.Policy, strings.NewReader(testCase.In), &prompts),
+				},
+			}
+
+			err := cmd.run(fs, "a.gql")
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			afero.WriteFile(fs, "/out/a.js", []byte("edited"), 0644)
+
+			err = cmd.run(fs, "a.gql")
+			if testCase.Err != "" {
+				if err == nil || err.Error() != testCase.Err {
+					subT.Errorf("expected error: %s\nbut got: %v", testCase.Err, err)
+				}
+			} else if err != nil {
+				subT.Fatal(err)
+			}
+
+			src, _ := afero.ReadFile(fs, "/out/a.js")
+			if string(src) != testCase.Src {
+				subT.Errorf("expected: %s\nbut got: %s", testCase.Src, src)
+			}
+
+			if testCase.Prompt != strings.Contains(prompts.String(), "Overwrite it?") {
+				subT.Errorf("expected prompt: %v, but got: %q", testCase.Prompt, prompts.String())
+			}
+		})
+	}
+
+	t.Run("NotInteractive", func(subT *testing.T) {
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "/out/.gqlc.sum", []byte("0000  a.js\n"), 0644)
+		afero.WriteFile(fs, "/out/a.js", []byte("edited"), 0644)
+
+		f, w, err := os.Pipe()
+		if err != nil {
+			subT.Fatal(err)
+		}
+		defer f.Close()
+		defer w.Close()
+
+		h := newOutputHashes(fs, modifiedPrompt, f, new(bytes.Buffer))
+		ok, err := h.check("/out", "/out/a.js")
+		if ok || err == nil || !strings.Contains(err.Error(), "pass --force to overwrite it") {
+			subT.Errorf("expected to fail without a terminal, but got: %v, %v", ok, err)
+		}
+	})
+
+	t.Run("Unrecorded", func(subT *testing.T) {
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "/out/a.js", []byte("handwritten"), 0644)
+
+		h := newOutputHashes(fs, modifiedPrompt, strings.NewReader(""), new(bytes.Buffer))
+		ok, err := h.check("/out", "/out/a.js")
+		if !ok || err != nil {
+			subT.Errorf("expected files which weren't generated to be written, but got: %v, %v", ok, err)
+		}
+	})
+}
+
+func TestRun_Scaffold(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/in/a.gql", []byte("type A"), 0644)
+
+	g := newMockGenerator(t)
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).DoAndReturn(func(ctx context.Context, doc *ast.Document, _ interface{}) error {
+		gCtx := gen.Context(ctx)
+		for name, scaffold := range map[string]bool{"a.js": false, "a.resolvers.js": true} {
+			open := gCtx.Open
+			if scaffold {
+				open = func(name string) (io.WriteCloser, error) { return gen.Scaffold(gCtx, name) }
+			}
+
+			f, err := open(name)
+			if err == gen.ErrScaffolded {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			_, err = f.Write([]byte("generated"))
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners: []generator{{Generator: g, outDir: "/out"}},
+			ipaths: []string{"/in"},
+			jobs:   1,
+			hashes: newOutputHashes(fs, modifiedPrompt, strings.NewReader(""), new(bytes.Buffer)),
+		},
+	}
+
+	err := cmd.run(fs, "a.gql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum, _ := afero.ReadFile(fs, "/out/.gqlc.sum")
+	if !strings.Contains(string(sum), "once  a.resolvers.js\n") {
+		t.Errorf("expected the resolvers to be recorded as scaffolded, but got:\n%s", sum)
+	}
+
+	// Editing a scaffolded file isn't a conflict
+	afero.WriteFile(fs, "/out/a.resolvers.js", []byte("implemented"), 0644)
+
+	err = cmd.run(fs, "a.gql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, _ := afero.ReadFile(fs, "/out/a.resolvers.js")
+	if string(src) != "implemented" {
+		t.Errorf("expected the scaffolded file to be kept, but got: %s", src)
+	}
+
+	sum2, _ := afero.ReadFile(fs, "/out/.gqlc.sum")
+	if string(sum) != string(sum2) {
+		t.Errorf("expected the recorded hashes to be the same, but got:\n%s", sum2)
+	}
+}
+
+func TestRun_HashesOnError(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/in/a.gql", []byte("type A"), 0644)
+
+	g := newMockGenerator(t)
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(ctx context.Context, doc *ast.Document, _ interface{}) error {
+		f, err := gen.Context(ctx).Open("a.js")
+		if err != nil {
+			return err
+		}
+		f.Write([]byte("generated"))
+		f.Close()
+
+		return errors.New("failed after writing")
+	})
+
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners: []generator{{Generator: g, outDir: "/out"}},
+			ipaths: []string{"/in"},
+			jobs:   1,
+			hashes: newOutputHashes(fs, modifiedPrompt, strings.NewReader(""), new(bytes.Buffer)),
+		},
+	}
+
+	err := cmd.run(fs, "a.gql")
+	if err == nil || err.Error() != "failed after writing" {
+		t.Fatalf("expected the generator to fail, but got: %v", err)
+	}
+
+	// The file which was written mustn't look modified to the next run
+	sum, _ := afero.ReadFile(fs, "/out/.gqlc.sum")
+	if !strings.Contains(string(sum), "  a.js\n") {
+		t.Errorf("expected the written file to be recorded, but got:\n%s", sum)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	// The null device is a character device, but can't answer prompts
+	f, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if isTerminal(f) {
+		t.Errorf("expected %s not to be a terminal", os.DevNull)
+	}
+	if !isTerminal(strings.NewReader("y\n")) {
+		t.Error("expected scripted answers to be prompted for")
+	}
+}
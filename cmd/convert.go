@@ -1,4 +1,5 @@
-// convert.go contains a converter from JSON introspection results to IDL.
+// convert.go contains the convert command and a converter from JSON
+// introspection results to IDL.
 
 package cmd
 
@@ -7,9 +8,115 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"path/filepath"
 	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func (c *CommandLine) newConvertCmd() *baseCmd {
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "convert files...",
+		Short: "Convert existing API definitions to a GraphQL schema",
+		Long: `convert generates a GraphQL schema from the existing definitions of an API,
+so that a schema can be bootstrapped from, and kept in sync with, them.
+
+Go source files, or directories of them, are converted by their type
+declarations. Exported structs become object types, with a field for each
+exported field, named by its graphql or json tag. Declarations may be marked
+instead, in which case only the marked ones are converted:
+
+	//gqlc:type            object type
+	//gqlc:input [Name]    input type, optionally renamed
+	//gqlc:enum            enum of the constants of a named type
+
+Fields may be skipped with graphql:"-" and have their type changed with
+graphql:"name,type=ID", graphql:",nonnull" or graphql:",nullable".
+
+OpenAPI 3 documents, in YAML or JSON, are converted by their component
+schemas, which become types, and their GET operations, which become fields
+of the Query type. Operations are named by their operationId, or path.
+
+Compiled protobuf FileDescriptorSets, e.g. from protoc --descriptor_set_out,
+are converted by their messages, which become types, enums and services.
+Methods without side effects, by their idempotency level or name, become
+fields of the Query type, server streaming methods fields of Subscription
+and the rest fields of Mutation. The fields of a request message become the
+arguments of its method, and every type and root field is annotated with
+the @proto or @grpc directive, which records where it came from along with
+its options. Pass --include_source_info to protoc to keep comments.`,
+		Example: `gqlc convert ./models -o schema.gql
+gqlc convert openapi.yaml -o schema.gql
+gqlc convert api.pb -o schema.gql`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			src, err := convertSources(c.fs, args...)
+			if err != nil {
+				return err
+			}
+
+			return writeSchema(c.fs, cmd.OutOrStdout(), out, src)
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&out, "output", "o", "", "File to write the schema to. If not given, it's printed.")
+
+	return &baseCmd{Command: cmd}
+}
+
+// Kinds of definitions which can be converted
+const (
+	sourceGo      = "go"
+	sourceOpenAPI = "openapi"
+	sourceProto   = "proto"
 )
 
+// convertSources converts the given files to SDL. They must all hold the
+// same kind of definitions.
+//
+func convertSources(fs afero.Fs, paths ...string) (string, error) {
+	kind := sourceKind(fs, paths[0])
+	for _, p := range paths[1:] {
+		if k := sourceKind(fs, p); k != kind {
+			return "", fmt.Errorf("gqlc: %s and %s can't be converted together", paths[0], p)
+		}
+	}
+
+	switch kind {
+	case sourceGo:
+		return convertGo(fs, paths...)
+	case sourceOpenAPI:
+		return convertOpenAPI(fs, paths...)
+	case sourceProto:
+		return convertProto(fs, paths...)
+	}
+	return "", fmt.Errorf("gqlc: don't know how to convert: %s", paths[0])
+}
+
+// sourceKind returns the kind of definitions a file holds.
+func sourceKind(fs afero.Fs, name string) string {
+	if info, err := fs.Stat(name); err == nil && info.IsDir() {
+		return sourceGo
+	}
+
+	switch filepath.Ext(name) {
+	case ".go":
+		return sourceGo
+	case ".pb", ".binpb", ".desc", ".protoset":
+		return sourceProto
+	case ".yaml", ".yml", ".json":
+		if isOpenAPI(fs, name) {
+			return sourceOpenAPI
+		}
+	}
+	return ""
+}
+
 type inputValue struct {
 	Name         string `json:"name"`
 	Description  string `json:"description"`
@@ -0,0 +1,419 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// Diff output formats
+const (
+	diffText     = "text"
+	diffMarkdown = "markdown"
+)
+
+func (c *CommandLine) newDiffCmd() *baseCmd {
+	gc := &gqlcCmd{
+		cfg: &gqlcConfig{
+			client: defaultClient,
+		},
+	}
+
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "diff old new",
+		Short: "Summarize the changes between two versions of a schema",
+		Long: `diff compares two versions of a schema and lists the types, fields,
+arguments, input fields, enum values and directives which were added,
+removed, deprecated or had their type changed.
+
+Each change is given a severity: breaking changes will break existing
+clients, dangerous changes may break clients which aren't prepared for
+them, e.g. new enum values, and safe changes are backwards compatible.
+
+With --format=markdown, the summary is printed as tables which are
+suitable for posting as a pull request comment.`,
+		Example: `gqlc diff --format=markdown main/schema.gql schema.gql`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			err := cobra.ExactArgs(2)(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			return validateFilenames(cmd, args)
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) (err error) {
+			gc.cfg.ipaths, err = cmd.Flags().GetStringSlice("import_path")
+			if err != nil {
+				return
+			}
+
+			switch format {
+			case diffText, diffMarkdown:
+				return nil
+			}
+			return fmt.Errorf("gqlc: unknown diff format: %s", format)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return gc.diff(c.fs, cmd.OutOrStdout(), format, args[0], args[1])
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringSliceP("import_path", "I", []string{"."}, `Specify the directory in which to search for
+imports.  May be specified multiple times;
+directories will be searched in order.  If not
+given, the current working directory is used.`)
+	cmd.Flags().StringVar(&format, "format", diffText, "Output format. Either text or markdown.")
+
+	return &baseCmd{Command: cmd}
+}
+
+func (c *gqlcCmd) diff(fs afero.Fs, w io.Writer, format, oldFile, newFile string) error {
+	oldSyms, err := c.loadSymbols(fs, oldFile)
+	if err != nil {
+		return err
+	}
+
+	newSyms, err := c.loadSymbols(fs, newFile)
+	if err != nil {
+		return err
+	}
+
+	changes := diffSymbols(oldSyms, newSyms)
+	if format == diffMarkdown {
+		return writeMarkdownDiff(w, changes)
+	}
+	return writeTextDiff(w, changes)
+}
+
+// loadSymbols parses a schema, along with its imports, and indexes its
+// members by schema coordinate.
+//
+func (c *gqlcCmd) loadSymbols(fs afero.Fs, filename string) (map[string]*symbol, error) {
+	docMap := make(map[string]*ast.Document)
+	err := c.parseInputFiles(fs, token.NewDocSet(), docMap, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]*ast.Document, 0, len(docMap))
+	for _, doc := range docMap {
+		docs = append(docs, doc)
+	}
+
+	syms := make(map[string]*symbol)
+	for _, sym := range collectSymbols(docs) {
+		syms[sym.coord] = sym
+	}
+	return syms, nil
+}
+
+// severity is how likely a change is to break existing clients.
+type severity int
+
+const (
+	safe severity = iota
+	dangerous
+	breaking
+)
+
+var severityNames = [...]string{
+	safe:      "safe",
+	dangerous: "dangerous",
+	breaking:  "breaking",
+}
+
+var severityEmoji = [...]string{
+	safe:      "🟢",
+	dangerous: "🟡",
+	breaking:  "🔴",
+}
+
+// changeKind groups changes in a summary.
+type changeKind int
+
+const (
+	added changeKind = iota
+	removed
+	changed
+	deprecated
+)
+
+var changeTitles = [...]string{
+	added:      "Added",
+	removed:    "Removed",
+	changed:    "Changed",
+	deprecated: "Deprecated",
+}
+
+// change is a single difference between two versions of a schema.
+type change struct {
+	kind   changeKind
+	sev    severity
+	coord  string
+	member string
+	detail string
+}
+
+// diffSymbols compares the members of two schemas. Members of an added or
+// removed type, and arguments of an added or removed field, are left out
+// since they're implied by their parent.
+//
+func diffSymbols(oldSyms, newSyms map[string]*symbol) (changes []change) {
+	for coord, o := range oldSyms {
+		n, ok := newSyms[coord]
+		if !ok {
+			if owner := ownerCoord(o); owner != coord && newSyms[owner] == nil {
+				continue
+			}
+
+			changes = append(changes, change{kind: removed, sev: breaking, coord: coord, member: memberKind(o)})
+			continue
+		}
+
+		if o.kind == typeSym && o.tok != n.tok {
+			changes = append(changes, change{
+				kind:   changed,
+				sev:    breaking,
+				coord:  coord,
+				member: memberKind(n),
+				detail: fmt.Sprintf("kind changed from %s to %s", memberKind(o), memberKind(n)),
+			})
+		}
+
+		if o.typ != nil && n.typ != nil && typeString(o.typ) != typeString(n.typ) {
+			changes = append(changes, change{
+				kind:   changed,
+				sev:    typeChangeSeverity(n, typeString(o.typ), typeString(n.typ)),
+				coord:  coord,
+				member: memberKind(n),
+				detail: fmt.Sprintf("type changed from `%s` to `%s`", typeString(o.typ), typeString(n.typ)),
+			})
+		}
+
+		reason, isDeprecated := deprecationReason(n.dirs)
+		if _, wasDeprecated := deprecationReason(o.dirs); isDeprecated && !wasDeprecated {
+			changes = append(changes, change{kind: deprecated, sev: dangerous, coord: coord, member: memberKind(n), detail: reason})
+		}
+	}
+
+	for coord, n := range newSyms {
+		if _, ok := oldSyms[coord]; ok {
+			continue
+		}
+		if owner := ownerCoord(n); owner != coord && oldSyms[owner] == nil {
+			continue
+		}
+
+		changes = append(changes, change{kind: added, sev: additionSeverity(n), coord: coord, member: memberKind(n)})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].kind != changes[j].kind {
+			return changes[i].kind < changes[j].kind
+		}
+		return changes[i].coord < changes[j].coord
+	})
+	return
+}
+
+// ownerCoord returns the coordinate of the member which owns a symbol
+// i.e. the field of an argument, or the type of any other member.
+//
+func ownerCoord(sym *symbol) string {
+	switch sym.kind {
+	case typeSym, directiveSym:
+		return sym.coord
+	case argSym:
+		return sym.coord[:strings.IndexByte(sym.coord, '(')]
+	}
+	return sym.parent
+}
+
+// additionSeverity classifies an added member. Required arguments and input
+// fields break existing callers and new enum values may not be handled by
+// existing clients.
+//
+func additionSeverity(sym *symbol) severity {
+	switch sym.kind {
+	case argSym, inputFieldSym:
+		if _, ok := sym.typ.(*ast.NonNull); ok && sym.def == nil {
+			return breaking
+		}
+	case enumValueSym:
+		return dangerous
+	}
+	return safe
+}
+
+// typeChangeSeverity classifies a type change. Output types may only become
+// stricter, i.e. non-null, whereas input types may only become looser.
+//
+func typeChangeSeverity(sym *symbol, oldType, newType string) severity {
+	switch sym.kind {
+	case fieldSym:
+		if newType == oldType+"!" {
+			return safe
+		}
+	case argSym, inputFieldSym:
+		if oldType == newType+"!" {
+			return safe
+		}
+	}
+	return breaking
+}
+
+var tokKinds = map[token.Token]string{
+	token.Token_SCALAR:    "scalar",
+	token.Token_TYPE:      "object",
+	token.Token_INTERFACE: "interface",
+	token.Token_UNION:     "union",
+	token.Token_ENUM:      "enum",
+	token.Token_INPUT:     "input",
+}
+
+// memberKind describes a symbol e.g. object, field or enum value.
+func memberKind(sym *symbol) string {
+	switch sym.kind {
+	case fieldSym:
+		return "field"
+	case argSym:
+		return "argument"
+	case inputFieldSym:
+		return "input field"
+	case enumValueSym:
+		return "enum value"
+	case directiveSym:
+		return "directive"
+	}
+	return tokKinds[sym.tok]
+}
+
+// defaultDeprecationReason is the default reason of the @deprecated directive.
+const defaultDeprecationReason = "No longer supported"
+
+// deprecationReason returns the reason given to an applied @deprecated directive.
+func deprecationReason(dirs []*ast.DirectiveLit) (string, bool) {
+	for _, d := range dirs {
+		if d.Name != "deprecated" {
+			continue
+		}
+
+		if d.Args == nil {
+			return defaultDeprecationReason, true
+		}
+
+		for _, arg := range d.Args.Args {
+			if arg.Name.Name != "reason" {
+				continue
+			}
+
+			if b, ok := arg.Value.(*ast.Arg_BasicLit); ok {
+				reason, err := strconv.Unquote(b.BasicLit.Value)
+				if err != nil {
+					reason = strings.Trim(b.BasicLit.Value, `"`)
+				}
+				return reason, true
+			}
+		}
+		return defaultDeprecationReason, true
+	}
+	return "", false
+}
+
+func writeTextDiff(w io.Writer, changes []change) error {
+	for _, c := range changes {
+		msg := strings.ToLower(changeTitles[c.kind])
+		if c.kind == changed {
+			msg = strings.Replace(c.detail, "`", "", -1)
+		}
+
+		_, err := fmt.Fprintf(w, "%-9s %s %s %s\n", severityNames[c.sev], c.member, c.coord, msg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeMarkdownDiff(w io.Writer, changes []change) error {
+	var b strings.Builder
+	b.WriteString("## Schema Changes\n\n")
+
+	if len(changes) == 0 {
+		b.WriteString("No changes.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	b.WriteString(severitySummary(changes))
+	b.WriteString("\n")
+
+	groups := make([][]change, len(changeTitles))
+	for _, c := range changes {
+		groups[c.kind] = append(groups[c.kind], c)
+	}
+
+	for kind, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+
+		b.WriteString("\n### ")
+		b.WriteString(changeTitles[kind])
+		b.WriteString("\n\n")
+
+		detailed := changeKind(kind) == changed || changeKind(kind) == deprecated
+		switch changeKind(kind) {
+		case changed:
+			b.WriteString("| | Member | Kind | Change |\n|---|---|---|---|\n")
+		case deprecated:
+			b.WriteString("| | Member | Kind | Reason |\n|---|---|---|---|\n")
+		default:
+			b.WriteString("| | Member | Kind |\n|---|---|---|\n")
+		}
+
+		for _, c := range group {
+			fmt.Fprintf(&b, "| %s | `%s` | %s |", severityEmoji[c.sev], c.coord, c.member)
+			if detailed {
+				fmt.Fprintf(&b, " %s |", escapeCell(c.detail))
+			}
+			b.WriteByte('\n')
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// severitySummary counts changes by severity e.g. 🔴 1 breaking · 🟢 2 safe.
+func severitySummary(changes []change) string {
+	var counts [len(severityNames)]int
+	for _, c := range changes {
+		counts[c.sev]++
+	}
+
+	var summary []string
+	for _, sev := range []severity{breaking, dangerous, safe} {
+		if counts[sev] > 0 {
+			summary = append(summary, fmt.Sprintf("%s %d %s", severityEmoji[sev], counts[sev], severityNames[sev]))
+		}
+	}
+	return strings.Join(summary, " · ")
+}
+
+// escapeCell escapes text for use in a markdown table cell.
+func escapeCell(s string) string {
+	s = strings.Replace(s, "|", `\|`, -1)
+	return strings.Replace(s, "\n", " ", -1)
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+var diffOldSchema = []byte(`type Query {
+	user(id: ID!): User
+	users(first: Int): [User]
+}
+
+type User {
+	id: ID!
+	name: String
+	email: String
+}
+
+type Session {
+	token: String!
+}
+
+enum Role {
+	ADMIN
+}
+
+input UserFilter {
+	name: String!
+}
+`)
+
+var diffNewSchema = []byte(`type Query {
+	user(id: ID!, active: Boolean!): User
+	users(first: Int, after: String): [User]
+	search(text: String!): [User]
+}
+
+type User {
+	id: ID!
+	name: String!
+	email: String @deprecated(reason: "Use | contacts.")
+	role: Role
+}
+
+union Session = User
+
+enum Role {
+	ADMIN
+	MEMBER
+}
+
+input UserFilter {
+	name: String
+}
+
+input Page {
+	first: Int!
+}
+`)
+
+func TestDiff(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/old/schema.gql", diffOldSchema, 0644)
+	afero.WriteFile(fs, "/new/schema.gql", diffNewSchema, 0644)
+
+	testCases := []struct {
+		Name   string
+		Format string
+		Out    string
+	}{
+		{
+			Name:   "Text",
+			Format: "text",
+			Out: `safe      input Page added
+safe      field Query.search added
+breaking  argument Query.user(active:) added
+safe      argument Query.users(after:) added
+dangerous enum value Role.MEMBER added
+safe      field User.role added
+breaking  field Session.token removed
+breaking  union Session kind changed from object to union
+safe      field User.name type changed from String to String!
+safe      input field UserFilter.name type changed from String! to String
+dangerous field User.email deprecated
+`,
+		},
+		{
+			Name:   "Markdown",
+			Format: "markdown",
+			Out: "## Schema Changes\n\n🔴 3 breaking · 🟡 2 dangerous · 🟢 6 safe\n" +
+				"\n### Added\n\n| | Member | Kind |\n|---|---|---|\n" +
+				"| 🟢 | `Page` | input |\n" +
+				"| 🟢 | `Query.search` | field |\n" +
+				"| 🔴 | `Query.user(active:)` | argument |\n" +
+				"| 🟢 | `Query.users(after:)` | argument |\n" +
+				"| 🟡 | `Role.MEMBER` | enum value |\n" +
+				"| 🟢 | `User.role` | field |\n" +
+				"\n### Removed\n\n| | Member | Kind |\n|---|---|---|\n" +
+				"| 🔴 | `Session.token` | field |\n" +
+				"\n### Changed\n\n| | Member | Kind | Change |\n|---|---|---|---|\n" +
+				"| 🔴 | `Session` | union | kind changed from object to union |\n" +
+				"| 🟢 | `User.name` | field | type changed from `String` to `String!` |\n" +
+				"| 🟢 | `UserFilter.name` | input field | type changed from `String!` to `String` |\n" +
+				"\n### Deprecated\n\n| | Member | Kind | Reason |\n|---|---|---|---|\n" +
+				"| 🟡 | `User.email` | field | Use \\| contacts. |\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			var b bytes.Buffer
+
+			c := NewCLI(WithFS(fs))
+			cmd := c.newDiffCmd()
+			cmd.SetOut(&b)
+			cmd.SetArgs([]string{"--format", testCase.Format, "/old/schema.gql", "/new/schema.gql"})
+
+			err := cmd.Execute()
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if b.String() != testCase.Out {
+				subT.Errorf("expected:\n%s\nbut got:\n%s", testCase.Out, b.String())
+			}
+		})
+	}
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/old/schema.gql", diffOldSchema, 0644)
+
+	var b bytes.Buffer
+	c := NewCLI(WithFS(fs))
+	cmd := c.newDiffCmd()
+	cmd.SetOut(&b)
+	cmd.SetArgs([]string{"--format=markdown", "/old/schema.gql", "/old/schema.gql"})
+
+	err := cmd.Execute()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if ex := "## Schema Changes\n\nNo changes.\n"; b.String() != ex {
+		t.Errorf("expected:\n%s\nbut got:\n%s", ex, b.String())
+	}
+}
+
+func TestDiff_UnknownFormat(t *testing.T) {
+	c := NewCLI(WithFS(afero.NewMemMapFs()))
+	cmd := c.newDiffCmd()
+	cmd.SetArgs([]string{"--format=html", "/old/schema.gql", "/new/schema.gql"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error")
+	}
+}
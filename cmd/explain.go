@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func (c *CommandLine) newExplainCmd() *baseCmd {
+	// Anything written while resolving the configuration, e.g. the output
+	// directories, is only written to memory.
+	//
+	fs := afero.NewCopyOnWriteFs(afero.NewReadOnlyFs(c.fs), afero.NewMemMapFs())
+
+	cc := c.newGqlcCmd(c.gens, fs, c.prefix)
+	cc.Use = "explain"
+	cc.Short = "Print the configuration and generation plan, without generating anything"
+	cc.Long = `explain takes the same flags and files as gqlc and prints what they
+resolve to, without generating anything: the configuration, after the
+config file and flags are applied, the documents which would be compiled,
+along with their imports, and which generator would generate each document
+into which directory, with the options it would be given after those of
+the document's directives are applied.`
+	cc.Example = "gqlc explain -I . --doc_out ./docs --js_out ./jsservice api.gql"
+	cc.RunE = func(cmd *cobra.Command, args []string) error {
+		return cc.explain(fs, cmd, c.codemods != nil, cmd.Flags().Args()...)
+	}
+
+	return &baseCmd{Command: cc.Command}
+}
+
+// explain prints the resolved configuration, documents and generation plan.
+func (c *gqlcCmd) explain(fs afero.Fs, cmd *cobra.Command, builtinCodemods bool, filenames ...string) error {
+	dset := token.NewDocSet()
+	docMap := make(map[string]*ast.Document, len(filenames))
+	err := c.parseInputFiles(fs, dset, docMap, filenames...)
+	if err != nil {
+		return err
+	}
+
+	// Imports are listed as they're written, before they're resolved
+	names := make([]string, 0, len(docMap))
+	imports := make(map[string][]string, len(docMap))
+	for name, doc := range docMap {
+		names = append(names, name)
+		imports[name] = getImports(doc)
+	}
+	sort.Strings(names)
+
+	err = c.checkImportCycles(dset, docMap)
+	if err != nil {
+		return err
+	}
+
+	docs := make([]*ast.Document, 0, len(docMap))
+	for _, name := range names {
+		docs = append(docs, docMap[name])
+	}
+	resolveImportPaths(docs)
+
+	out := cmd.OutOrStdout()
+	err = c.explainConfig(fs, out, cmd, builtinCodemods)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\nDocuments:")
+	for i, name := range names {
+		fmt.Fprintf(out, "  %s (%s)\n", docs[i].Name, c.files[name])
+		if len(imports[name]) > 0 {
+			fmt.Fprintf(out, "    imports: %s\n", strings.Join(imports[name], ", "))
+		}
+	}
+
+	fmt.Fprintln(out, "\nPlan:")
+	if len(c.cfg.geners) == 0 {
+		fmt.Fprintln(out, "  no generators, pass one with e.g. --doc_out")
+	}
+	for _, g := range c.cfg.geners {
+		for _, doc := range docs {
+			fmt.Fprintf(out, "  %s: %s -> %s\n", g.name, doc.Name, g.outDir)
+			for _, opt := range effectiveOptions(g, doc) {
+				fmt.Fprintf(out, "    %s\n", opt)
+			}
+		}
+	}
+	return nil
+}
+
+// explainConfig prints the configuration resolved from the config file and flags.
+func (c *gqlcCmd) explainConfig(fs afero.Fs, out io.Writer, cmd *cobra.Command, builtinCodemods bool) error {
+	configFile, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return err
+	}
+
+	var codemods []string
+	if builtinCodemods {
+		codemods = append(codemods, "built in")
+	}
+	exists, _ := afero.Exists(fs, configFile)
+	if exists {
+		cfg, err := loadConfig(fs, configFile)
+		if err != nil {
+			return err
+		}
+
+		for _, cm := range cfg.Codemods {
+			codemods = append(codemods, cm.Name)
+		}
+	} else {
+		configFile += " (not found)"
+	}
+
+	policy := "prompt"
+	switch {
+	case c.cfg.hashes == nil:
+	case c.cfg.hashes.policy == modifiedForce:
+		policy = "overwrite"
+	case c.cfg.hashes.policy == modifiedSkip:
+		policy = "skip"
+	}
+
+	fmt.Fprintln(out, "Configuration:")
+	for _, kv := range [][2]string{
+		{"config", configFile},
+		{"codemods", listOrNone(codemods)},
+		{"import paths", listOrNone(c.cfg.ipaths)},
+		{"operations", listOrNone(c.cfg.ops)},
+		{"jobs", strconv.Itoa(c.cfg.jobs)},
+		{"keep going", strconv.FormatBool(c.cfg.keepGoing)},
+		{"allow import cycles", strconv.FormatBool(c.cfg.allowImportCycles)},
+		{"modified files", policy},
+		{"max files", limitString(c.cfg.limits.maxFiles, strconv.FormatInt(c.cfg.limits.maxFiles, 10))},
+		{"max output bytes", limitString(c.cfg.limits.maxBytes, formatSize(c.cfg.limits.maxBytes))},
+		{"max file bytes", limitString(c.cfg.limits.maxFileBytes, formatSize(c.cfg.limits.maxFileBytes))},
+		{"max depth", limitString(int64(c.cfg.maxDepth), strconv.Itoa(c.cfg.maxDepth))},
+	} {
+		fmt.Fprintf(out, "  %-20s %s\n", kv[0]+":", kv[1])
+	}
+	return nil
+}
+
+func listOrNone(l []string) string {
+	if len(l) == 0 {
+		return "none"
+	}
+	return strings.Join(l, ", ")
+}
+
+func limitString(n int64, s string) string {
+	if n == 0 {
+		return "no limit"
+	}
+	return s
+}
+
+// effectiveOptions returns the options a generator is given for a document,
+// formatted as key=value and followed by where they're set. Options given on
+// the command line take precedence over those of the generator's document
+// directive, just as generators read them.
+//
+func effectiveOptions(g generator, doc *ast.Document) []string {
+	opts := make(map[string]string)
+	for _, d := range doc.Directives {
+		if d.Name != optionDirective(g.name) || d.Args == nil || len(d.Args.Args) == 0 {
+			continue
+		}
+
+		arg, ok := d.Args.Args[0].Value.(*ast.Arg_CompositeLit)
+		if !ok {
+			continue
+		}
+		obj, ok := arg.CompositeLit.Value.(*ast.CompositeLit_ObjLit)
+		if !ok {
+			continue
+		}
+
+		for _, p := range obj.ObjLit.Fields {
+			opts[p.Key.Name] = litString(p.Val) + " (doc)"
+		}
+	}
+
+	for k, v := range g.opts {
+		src := " (flag)"
+		if _, ok := opts[k]; ok {
+			src = " (flag, over doc)"
+		}
+		opts[k] = optString(v) + src
+	}
+
+	keys := make([]string, 0, len(opts))
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	strs := make([]string, len(keys))
+	for i, k := range keys {
+		strs[i] = k + "=" + opts[k]
+	}
+	return strs
+}
+
+// optString formats an option given on the command line.
+func optString(v interface{}) string {
+	switch w := v.(type) {
+	case string:
+		return strings.Trim(w, `"`)
+	case []string:
+		strs := make([]string, len(w))
+		for i, s := range w {
+			strs[i] = strings.Trim(s, `"`)
+		}
+		return "[" + strings.Join(strs, ", ") + "]"
+	}
+	return fmt.Sprint(v)
+}
+
+// litString formats an option given by a directive.
+func litString(lit *ast.CompositeLit) string {
+	switch v := lit.Value.(type) {
+	case *ast.CompositeLit_BasicLit:
+		return strings.Trim(v.BasicLit.Value, `"`)
+	case *ast.CompositeLit_ListLit:
+		var strs []string
+		switch l := v.ListLit.List.(type) {
+		case *ast.ListLit_BasicList:
+			for _, b := range l.BasicList.Values {
+				strs = append(strs, strings.Trim(b.Value, `"`))
+			}
+		case *ast.ListLit_CompositeList:
+			for _, c := range l.CompositeList.Values {
+				strs = append(strs, litString(c))
+			}
+		}
+		return "[" + strings.Join(strs, ", ") + "]"
+	case *ast.CompositeLit_ObjLit:
+		strs := make([]string, len(v.ObjLit.Fields))
+		for i, p := range v.ObjLit.Fields {
+			strs[i] = p.Key.Name + ": " + litString(p.Val)
+		}
+		return "{" + strings.Join(strs, ", ") + "}"
+	}
+	return ""
+}
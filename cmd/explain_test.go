@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestExplain(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/in/api.gql", []byte(`@import(paths: ["common.gql"])
+@doc(options: {title: "API", operations: ["a.graphql", "b.graphql"]})
+
+type Query { a: A }`), 0644)
+	afero.WriteFile(fs, "/in/common.gql", []byte(`type A { b: String }`), 0644)
+	afero.WriteFile(fs, "/gqlc.yaml", []byte("codemods:\n  - name: prefixTypes\n    args: {prefix: V1}\n"), 0644)
+
+	var b bytes.Buffer
+	c := NewCLI(WithFS(fs))
+	c.RegisterGenerator(newMockGenerator(t), "doc_out", "doc_opt", "Generate documentation.")
+	cmd := c.newExplainCmd()
+	cmd.SetOut(&b)
+	cmd.SetArgs([]string{"-I", "/in", "-j", "2", "--config", "/gqlc.yaml", "--max-file-bytes", "1MiB", "--doc_out", "/out", "--doc_opt", "html=true,title=Docs", "api.gql"})
+
+	err := cmd.Execute()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := `Configuration:
+  config:              /gqlc.yaml
+  codemods:            prefixTypes
+  import paths:        /in
+  operations:          none
+  jobs:                2
+  keep going:          false
+  allow import cycles: false
+  modified files:      prompt
+  max files:           no limit
+  max output bytes:    no limit
+  max file bytes:      1MiB
+  max depth:           100
+
+Documents:
+  api (/in/api.gql)
+    imports: common.gql
+  common (/in/common.gql)
+
+Plan:
+  doc: api -> /out
+    html=true (flag)
+    operations=[a.graphql, b.graphql] (doc)
+    title=Docs (flag, over doc)
+  doc: common -> /out
+    html=true (flag)
+    title=Docs (flag)
+`
+	if b.String() != ex {
+		t.Errorf("expected:\n%s\nbut got:\n%s", ex, b.String())
+	}
+
+	if exists, _ := afero.DirExists(fs, "/out"); exists {
+		t.Error("expected explain not to create the output directory")
+	}
+}
@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+// includeOpt and excludeOpt are the reserved generator options used to
+// scope the types a generator sees e.g. --go_opt exclude=@internal
+//
+const (
+	includeOpt = "include"
+	excludeOpt = "exclude"
+)
+
+// filterKinds maps the kind terms of a filter to their declaration token.
+var filterKinds = map[string]token.Token{
+	"scalar":    token.Token_SCALAR,
+	"type":      token.Token_TYPE,
+	"object":    token.Token_TYPE,
+	"interface": token.Token_INTERFACE,
+	"union":     token.Token_UNION,
+	"enum":      token.Token_ENUM,
+	"input":     token.Token_INPUT,
+	"directive": token.Token_DIRECTIVE,
+}
+
+// filterTerm matches type declarations by either a name pattern,
+// a kind e.g. enum, or an applied directive e.g. @internal.
+//
+type filterTerm struct {
+	name      string
+	kind      token.Token
+	directive string
+}
+
+func (t filterTerm) match(d *ast.TypeDecl, ts *ast.TypeSpec) bool {
+	switch {
+	case t.directive != "":
+		return hasDirective(ts.Directives, t.directive)
+	case t.kind != token.Token_UNKNOWN:
+		return d.Tok == t.kind
+	}
+
+	ok, _ := path.Match(t.name, ts.Name.Name)
+	return ok
+}
+
+// typeFilter scopes the types of a set of documents for a single generator.
+type typeFilter struct {
+	include []filterTerm
+	exclude []filterTerm
+}
+
+// getTypeFilter extracts any include/exclude filters from a generators
+// options. The options are removed so that they never reach the generator.
+//
+func getTypeFilter(opts map[string]interface{}) (*typeFilter, error) {
+	include, err := getFilterTerms(opts, includeOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude, err := getFilterTerms(opts, excludeOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+	return &typeFilter{include: include, exclude: exclude}, nil
+}
+
+// getFilterTerms parses the terms of a filter option. Terms are comma
+// separated and the option may be given multiple times.
+//
+func getFilterTerms(opts map[string]interface{}, opt string) (terms []filterTerm, err error) {
+	v, ok := opts[opt]
+	if !ok {
+		return nil, nil
+	}
+	delete(opts, opt)
+
+	var vals []string
+	switch w := v.(type) {
+	case string:
+		vals = append(vals, w)
+	case []string:
+		vals = w
+	default:
+		return nil, fmt.Errorf("gqlc: %s option must be a list of type names, kinds or directives", opt)
+	}
+
+	for _, val := range vals {
+		for _, s := range strings.Split(strings.Trim(val, `"`), ",") {
+			s = strings.TrimSpace(s)
+			switch {
+			case s == "":
+				continue
+			case strings.HasPrefix(s, "@"):
+				if len(s) == 1 {
+					return nil, fmt.Errorf("gqlc: %s option contains an empty directive name", opt)
+				}
+				terms = append(terms, filterTerm{directive: s[1:]})
+			case filterKinds[s] != token.Token_UNKNOWN:
+				terms = append(terms, filterTerm{kind: filterKinds[s]})
+			default:
+				if _, err = path.Match(s, ""); err != nil {
+					return nil, fmt.Errorf("gqlc: %s option contains an invalid pattern: %s", opt, s)
+				}
+				terms = append(terms, filterTerm{name: s})
+			}
+		}
+	}
+	return
+}
+
+// keep reports whether a type declaration passes the filter.
+func (f *typeFilter) keep(d *ast.TypeDecl) bool {
+	ts := declSpec(d)
+	if ts == nil || ts.Name == nil {
+		// The schema is always kept and pruned instead
+		return true
+	}
+
+	if len(f.include) > 0 && !matchAny(f.include, d, ts) {
+		return false
+	}
+	return !matchAny(f.exclude, d, ts)
+}
+
+func matchAny(terms []filterTerm, d *ast.TypeDecl, ts *ast.TypeSpec) bool {
+	for _, t := range terms {
+		if t.match(d, ts) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply returns filtered copies of the given documents, leaving the originals
+// untouched for other generators. Any fields, arguments, enum values or input
+// fields which have an excluded directive applied are removed, as are any
+// references to removed types.
+//
+func (f *typeFilter) apply(docs []*ast.Document) []*ast.Document {
+	out := make([]*ast.Document, len(docs))
+	removed := make(map[string]bool)
+
+	for i, doc := range docs {
+		doc = proto.Clone(doc).(*ast.Document)
+
+		n := 0
+		for _, d := range doc.Types {
+			if f.keep(d) {
+				doc.Types[n] = d
+				n++
+				continue
+			}
+
+			removed[declSpec(d).Name.Name] = true
+		}
+		doc.Types = doc.Types[:n]
+
+		out[i] = doc
+	}
+
+	var dirs []string
+	for _, t := range f.exclude {
+		if t.directive != "" {
+			dirs = append(dirs, t.directive)
+		}
+	}
+
+	p := &pruner{removed: removed, dirs: dirs}
+	for _, doc := range out {
+		for _, d := range doc.Types {
+			ts := declSpec(d)
+			if ts == nil {
+				continue
+			}
+			p.prune(ts)
+
+			// Cloning doesn't preserve the schema being shared with Types
+			if _, ok := ts.Type.(*ast.TypeSpec_Schema); ok && doc.Schema != nil {
+				doc.Schema = d
+			}
+		}
+	}
+	return out
+}
+
+// pruner removes the members of a type which reference removed
+// types or have any of the given directives applied.
+//
+type pruner struct {
+	removed map[string]bool
+	dirs    []string
+}
+
+func (p *pruner) excluded(dirs []*ast.DirectiveLit) bool {
+	for _, d := range p.dirs {
+		if hasDirective(dirs, d) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *pruner) prune(ts *ast.TypeSpec) {
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Schema:
+		p.pruneFields(v.Schema.RootOps)
+	case *ast.TypeSpec_Object:
+		v.Object.Interfaces = p.pruneIdents(v.Object.Interfaces)
+		p.pruneFields(v.Object.Fields)
+	case *ast.TypeSpec_Interface:
+		p.pruneFields(v.Interface.Fields)
+	case *ast.TypeSpec_Union:
+		v.Union.Members = p.pruneIdents(v.Union.Members)
+	case *ast.TypeSpec_Enum:
+		p.pruneFields(v.Enum.Values)
+	case *ast.TypeSpec_Input:
+		p.pruneInputs(v.Input.Fields)
+	case *ast.TypeSpec_Directive:
+		p.pruneInputs(v.Directive.Args)
+	}
+}
+
+func (p *pruner) pruneIdents(ids []*ast.Ident) []*ast.Ident {
+	n := 0
+	for _, id := range ids {
+		if !p.removed[id.Name] {
+			ids[n] = id
+			n++
+		}
+	}
+	return ids[:n]
+}
+
+func (p *pruner) pruneFields(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+
+	n := 0
+	for _, f := range fields.List {
+		if p.excluded(f.Directives) || p.removed[namedType(fieldType(f))] {
+			continue
+		}
+
+		p.pruneInputs(f.Args)
+		fields.List[n] = f
+		n++
+	}
+	fields.List = fields.List[:n]
+}
+
+func (p *pruner) pruneInputs(args *ast.InputValueList) {
+	if args == nil {
+		return
+	}
+
+	n := 0
+	for _, a := range args.List {
+		if !p.excluded(a.Directives) && !p.removed[namedType(inputValueType(a))] {
+			args.List[n] = a
+			n++
+		}
+	}
+	args.List = args.List[:n]
+}
+
+func hasDirective(dirs []*ast.DirectiveLit, name string) bool {
+	for _, d := range dirs {
+		if d.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+func TestGetTypeFilter(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Opts   map[string]interface{}
+		Filter *typeFilter
+		Err    bool
+	}{
+		{
+			Name: "None",
+			Opts: map[string]interface{}{"descriptions": true},
+		},
+		{
+			Name: "Quoted",
+			Opts: map[string]interface{}{"include": `"Query, Mutation"`},
+			Filter: &typeFilter{
+				include: []filterTerm{{name: "Query"}, {name: "Mutation"}},
+			},
+		},
+		{
+			Name: "Multiple",
+			Opts: map[string]interface{}{
+				"include": []string{"enum", "User*"},
+				"exclude": "@internal",
+			},
+			Filter: &typeFilter{
+				include: []filterTerm{{kind: token.Token_ENUM}, {name: "User*"}},
+				exclude: []filterTerm{{directive: "internal"}},
+			},
+		},
+		{
+			Name: "BadPattern",
+			Opts: map[string]interface{}{"exclude": "User["},
+			Err:  true,
+		},
+		{
+			Name: "EmptyDirective",
+			Opts: map[string]interface{}{"exclude": `"@"`},
+			Err:  true,
+		},
+		{
+			Name: "NotAString",
+			Opts: map[string]interface{}{"include": true},
+			Err:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			f, err := getTypeFilter(testCase.Opts)
+			if testCase.Err {
+				if err == nil {
+					subT.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			for _, opt := range []string{includeOpt, excludeOpt} {
+				if _, exists := testCase.Opts[opt]; exists {
+					subT.Errorf("expected %s option to be removed", opt)
+				}
+			}
+
+			if !reflect.DeepEqual(f, testCase.Filter) {
+				subT.Errorf("expected: %#v, but got: %#v", testCase.Filter, f)
+			}
+		})
+	}
+}
+
+func TestTypeFilter_Apply(t *testing.T) {
+	src := `schema {
+	query: Query
+	mutation: Mutation
+}
+
+type Query {
+	user(id: ID!, trace: Boolean @internal): User
+	audit: Audit
+}
+
+type Mutation {
+	ban(id: ID!): User
+}
+
+type User implements Node @public {
+	id: ID!
+	email: String @internal
+	role: Role
+}
+
+type Audit @internal {
+	user: User
+}
+
+interface Node {
+	id: ID!
+}
+
+union Entry = User | Audit
+
+enum Role {
+	ADMIN
+	DEBUG @internal
+}`
+
+	testCases := []struct {
+		Name  string
+		Opts  map[string]interface{}
+		Types map[string][]string
+	}{
+		{
+			Name: "ExcludeDirective",
+			Opts: map[string]interface{}{"exclude": "@internal"},
+			Types: map[string][]string{
+				"":         {"query", "mutation"},
+				"Query":    {"user(id)"},
+				"Mutation": {"ban(id)"},
+				"User":     {"Node", "id", "role"},
+				"Node":     {"id"},
+				"Entry":    {"User"},
+				"Role":     {"ADMIN"},
+			},
+		},
+		{
+			Name: "IncludeNames",
+			Opts: map[string]interface{}{"include": "Query,User"},
+			Types: map[string][]string{
+				"":      {"query"},
+				"Query": {"user(id,trace)"},
+				"User":  {"id", "email"},
+			},
+		},
+		{
+			Name: "IncludeKindExcludeName",
+			Opts: map[string]interface{}{"include": []string{"type", "@public"}, "exclude": "*ion"},
+			Types: map[string][]string{
+				"":      {"query"},
+				"Query": {"user(id,trace)", "audit"},
+				"User":  {"id", "email"},
+				"Audit": {"user"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(src), 0)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			f, err := getTypeFilter(testCase.Opts)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			out := f.apply([]*ast.Document{doc})
+			if len(doc.Types) != 8 {
+				subT.Errorf("expected original document to be untouched but it has %d types", len(doc.Types))
+			}
+			if out[0].Schema != out[0].Types[0] {
+				subT.Error("expected schema to be relinked to the filtered types")
+			}
+
+			types := make(map[string][]string)
+			for _, d := range out[0].Types {
+				ts := declSpec(d)
+
+				var name string
+				if ts.Name != nil {
+					name = ts.Name.Name
+				}
+				types[name] = filteredMembers(ts)
+			}
+
+			if !reflect.DeepEqual(types, testCase.Types) {
+				subT.Errorf("expected: %v, but got: %v", testCase.Types, types)
+			}
+		})
+	}
+}
+
+// filteredMembers lists the members of a type for comparison e.g. field(arg)
+func filteredMembers(ts *ast.TypeSpec) (members []string) {
+	fields := func(fl *ast.FieldList) {
+		if fl == nil {
+			return
+		}
+
+		for _, f := range fl.List {
+			m := f.Name.Name
+			if f.Args != nil {
+				var args []string
+				for _, a := range f.Args.List {
+					args = append(args, a.Name.Name)
+				}
+				m += "(" + strings.Join(args, ",") + ")"
+			}
+			members = append(members, m)
+		}
+	}
+
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Schema:
+		fields(v.Schema.RootOps)
+	case *ast.TypeSpec_Object:
+		for _, id := range v.Object.Interfaces {
+			members = append(members, id.Name)
+		}
+		fields(v.Object.Fields)
+	case *ast.TypeSpec_Interface:
+		fields(v.Interface.Fields)
+	case *ast.TypeSpec_Union:
+		for _, id := range v.Union.Members {
+			members = append(members, id.Name)
+		}
+	case *ast.TypeSpec_Enum:
+		fields(v.Enum.Values)
+	}
+	return
+}
@@ -68,6 +68,7 @@ func (f *headerFlag) Set(val string) error {
 // genFlag represents a Generator flag: *_out
 type genFlag struct {
 	g    gen.Generator
+	name string
 	opts map[string]interface{}
 
 	geners  *[]generator
@@ -105,7 +106,12 @@ func (f genFlag) Set(arg string) (err error) {
 	}
 
 	*f.outDirs = append(*f.outDirs, *outDir)
-	*f.geners = append(*f.geners, generator{Generator: f.g, opts: f.opts, outDir: *outDir})
+	*f.geners = append(*f.geners, generator{
+		Generator: f.g,
+		name:      strings.TrimSuffix(f.name, "_out"),
+		opts:      f.opts,
+		outDir:    *outDir,
+	})
 	return
 }
 
@@ -200,28 +206,49 @@ func parseValue(p *fparser, key string, opts map[string]interface{}) (val interf
 		if valStr == "true" || valStr == "false" {
 			return parseBool(p, opts, key)
 		}
+		if p.Peek() == '=' {
+			return parseString(opts, key, valStr+p.binding())
+		}
 
 		fallthrough
 	case scanner.String:
-		valStr := p.TokenText()
-		oldV, ok := opts[key]
-		if !ok {
-			val = valStr
-			break
-		}
-
-		oldS, isS := oldV.([]string)
-		if !isS {
-			val = append(oldS, oldV.(string), valStr)
-			break
+		return parseString(opts, key, p.TokenText())
+	case '@':
+		if p.Scan() != scanner.Ident {
+			p.errorf("gqlc: expected directive name in generator option, %s", key)
 		}
-		val = append(oldS, valStr)
+		return parseString(opts, key, "@"+p.TokenText())
 	default:
 		p.errorf("gqlc: unexpected character in generator option, %s, value: %s", key, string(tt))
 	}
 	return val
 }
 
+// binding scans the rest of a value which binds a name to something, e.g.
+// scalars=DateTime=time.Time, which runs up to the next option, or the
+// output directory, so it may contain dots and slashes.
+//
+func (p *fparser) binding() string {
+	var b strings.Builder
+	for r := p.Peek(); r != ',' && r != ':' && r != scanner.EOF; r = p.Peek() {
+		b.WriteRune(p.Next())
+	}
+	return b.String()
+}
+
+func parseString(opts map[string]interface{}, key, valStr string) (val interface{}) {
+	oldV, ok := opts[key]
+	if !ok {
+		return valStr
+	}
+
+	oldS, isS := oldV.([]string)
+	if !isS {
+		return append(oldS, oldV.(string), valStr)
+	}
+	return append(oldS, valStr)
+}
+
 func parseInt(p *fparser, opts map[string]interface{}, key string) (val interface{}) {
 	valStr := p.TokenText()
 	oldV, ok := opts[key]
@@ -50,6 +50,11 @@ func TestGenOptFlag(t *testing.T) {
 			Arg:  `testStrings="1",testStrings="2",testStrings="3":`,
 			Opts: map[string]interface{}{"testStrings": []string{`"1"`, `"2"`, `"3"`}},
 		},
+		{
+			Name: "EscapedString",
+			Arg:  `post="prettier --config \"my cfg.json\""`,
+			Opts: map[string]interface{}{"post": `"prettier --config \"my cfg.json\""`},
+		},
 		{
 			Name: "MultiBool",
 			Arg:  "testBools=true,testBools=false,testBools=true",
@@ -60,6 +65,24 @@ func TestGenOptFlag(t *testing.T) {
 			Arg:  "testIdents=one,testIdents=two,testIdents=three:",
 			Opts: map[string]interface{}{"testIdents": []string{"one", "two", "three"}},
 		},
+		{
+			Name: "Directive",
+			Arg:  "exclude=@internal,exclude=@beta:",
+			Opts: map[string]interface{}{"exclude": []string{"@internal", "@beta"}},
+		},
+		{
+			Name: "Binding",
+			Arg:  "scalars=DateTime=time.Time,scalars=UUID=github.com/google/uuid.UUID,b=1:",
+			Opts: map[string]interface{}{
+				"scalars": []string{"DateTime=time.Time", "UUID=github.com/google/uuid.UUID"},
+				"b":       int64(1),
+			},
+		},
+		{
+			Name: "MalformedDirective",
+			Arg:  "exclude=@1:",
+			Err:  "gqlc: expected directive name in generator option, exclude",
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -0,0 +1,501 @@
+// gostruct.go contains the conversion of Go struct definitions to SDL.
+
+package cmd
+
+import (
+	"fmt"
+	goast "go/ast"
+	goparser "go/parser"
+	gotoken "go/token"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/afero"
+)
+
+// goDirective marks a Go type declaration for conversion e.g.
+//
+//	//gqlc:type
+//	//gqlc:input UserInput
+//	//gqlc:enum
+//
+const goDirective = "//gqlc:"
+
+// goDecl is a Go type declaration which is converted to a GraphQL type.
+type goDecl struct {
+	name    string
+	gqlName string
+	kind    string
+	doc     string
+
+	// directive is set if the declaration was marked for conversion
+	directive bool
+
+	typ goast.Expr
+}
+
+// goConverter converts Go struct definitions into object and input types,
+// and named types with constants into enums.
+//
+type goConverter struct {
+	fset  *gotoken.FileSet
+	decls map[string]*goDecl
+	order []*goDecl
+
+	// annotated is set once any declaration has a gqlc directive, after
+	// which only annotated declarations are converted.
+	//
+	annotated bool
+
+	// values are the names of the constants of each type
+	values map[string][]string
+
+	scalars map[string]bool
+}
+
+func newGoConverter() *goConverter {
+	return &goConverter{
+		fset:    gotoken.NewFileSet(),
+		decls:   make(map[string]*goDecl),
+		values:  make(map[string][]string),
+		scalars: make(map[string]bool),
+	}
+}
+
+// convertGo converts the Go files, or packages in the given directories, to SDL.
+func convertGo(fs afero.Fs, paths ...string) (string, error) {
+	c := newGoConverter()
+	for _, p := range paths {
+		names := []string{p}
+		if info, err := fs.Stat(p); err != nil {
+			return "", err
+		} else if info.IsDir() {
+			infos, err := afero.ReadDir(fs, p)
+			if err != nil {
+				return "", err
+			}
+
+			names = names[:0]
+			for _, info := range infos {
+				name := info.Name()
+				if info.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+					continue
+				}
+				names = append(names, filepath.Join(p, name))
+			}
+		}
+
+		for _, name := range names {
+			src, err := afero.ReadFile(fs, name)
+			if err != nil {
+				return "", err
+			}
+
+			if err = c.addFile(name, src); err != nil {
+				return "", err
+			}
+		}
+	}
+	return c.schema(), nil
+}
+
+// addFile adds the type and constant declarations of a Go file.
+func (c *goConverter) addFile(name string, src []byte) error {
+	f, err := goparser.ParseFile(c.fset, name, src, goparser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("gqlc: %w", err)
+	}
+
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*goast.GenDecl)
+		if !ok {
+			continue
+		}
+
+		switch gd.Tok {
+		case gotoken.TYPE:
+			for _, spec := range gd.Specs {
+				ts := spec.(*goast.TypeSpec)
+
+				doc := ts.Doc
+				if doc == nil && len(gd.Specs) == 1 {
+					doc = gd.Doc
+				}
+				c.addType(ts, doc)
+			}
+		case gotoken.CONST:
+			c.addConsts(gd)
+		}
+	}
+	return nil
+}
+
+func (c *goConverter) addType(ts *goast.TypeSpec, doc *goast.CommentGroup) {
+	d := &goDecl{name: ts.Name.Name, gqlName: ts.Name.Name, typ: ts.Type}
+	if doc != nil {
+		d.doc = doc.Text()
+
+		for _, com := range doc.List {
+			if !strings.HasPrefix(com.Text, goDirective) {
+				continue
+			}
+
+			args := strings.Fields(strings.TrimPrefix(com.Text, goDirective))
+			if len(args) == 0 {
+				continue
+			}
+
+			c.annotated = true
+			d.directive = true
+			d.kind = args[0]
+			if len(args) > 1 {
+				d.gqlName = args[1]
+			}
+		}
+	}
+
+	if d.kind == "" {
+		if _, ok := ts.Type.(*goast.StructType); ok && ts.Name.IsExported() {
+			d.kind = "type"
+		}
+	}
+
+	c.decls[d.name] = d
+	c.order = append(c.order, d)
+}
+
+// addConsts adds the constants of a declaration as the values of their
+// type, in case it's an enum. Untyped constants in a group repeat the type
+// of the last typed one, as they do with iota.
+//
+func (c *goConverter) addConsts(gd *goast.GenDecl) {
+	var typ string
+	for _, spec := range gd.Specs {
+		vs := spec.(*goast.ValueSpec)
+		if vs.Type != nil {
+			typ = ""
+			if ident, ok := vs.Type.(*goast.Ident); ok {
+				typ = ident.Name
+			}
+		} else if len(vs.Values) > 0 {
+			typ = ""
+		}
+		if typ == "" {
+			continue
+		}
+
+		for _, name := range vs.Names {
+			if name.Name == "_" || !name.IsExported() {
+				continue
+			}
+			c.values[typ] = append(c.values[typ], enumValueName(typ, name.Name))
+		}
+	}
+}
+
+// schema returns the converted types as SDL.
+func (c *goConverter) schema() string {
+	var types strings.Builder
+	for _, d := range c.order {
+		if !c.converted(d) {
+			continue
+		}
+
+		writeDescr(&types, "", d.doc)
+		switch d.kind {
+		case "enum":
+			types.WriteString("enum " + d.gqlName + " {\n")
+			for _, v := range c.values[d.name] {
+				types.WriteString("\t" + v + "\n")
+			}
+			types.WriteString("}\n\n")
+			continue
+		case "input":
+			types.WriteString("input ")
+		default:
+			types.WriteString("type ")
+		}
+
+		types.WriteString(d.gqlName + " {\n")
+		st, _ := d.typ.(*goast.StructType)
+		c.writeFields(&types, st, d.kind == "input")
+		types.WriteString("}\n\n")
+	}
+
+	var b strings.Builder
+	names := make([]string, 0, len(c.scalars))
+	for s := range c.scalars {
+		names = append(names, s)
+	}
+	sort.Strings(names)
+	for _, s := range names {
+		b.WriteString("scalar " + s + "\n\n")
+	}
+
+	b.WriteString(types.String())
+	return b.String()
+}
+
+// converted reports whether a declaration is converted.
+func (c *goConverter) converted(d *goDecl) bool {
+	if d.kind == "" || c.annotated && !d.directive {
+		return false
+	}
+
+	switch d.kind {
+	case "type", "input":
+		_, ok := d.typ.(*goast.StructType)
+		return ok
+	case "enum":
+		return len(c.values[d.name]) > 0
+	}
+	return false
+}
+
+// writeFields writes the fields of a struct, including those of any
+// embedded structs, as encoding/json would.
+//
+func (c *goConverter) writeFields(b *strings.Builder, st *goast.StructType, input bool) {
+	if st == nil {
+		return
+	}
+
+	for _, f := range st.Fields.List {
+		tag := reflect.StructTag("")
+		if f.Tag != nil {
+			tag = reflect.StructTag(strings.Trim(f.Tag.Value, "`"))
+		}
+
+		name, opts := fieldTag(tag)
+		if name == "-" {
+			continue
+		}
+
+		if len(f.Names) == 0 {
+			// Embedded struct
+			if name == "" {
+				if d, ok := c.decls[goTypeName(f.Type)]; ok {
+					st, _ := d.typ.(*goast.StructType)
+					c.writeFields(b, st, input)
+				}
+				continue
+			}
+			f.Names = []*goast.Ident{goast.NewIdent(goTypeName(f.Type))}
+		}
+
+		var doc string
+		if f.Doc != nil {
+			doc = f.Doc.Text()
+		} else if f.Comment != nil {
+			doc = f.Comment.Text()
+		}
+
+		for _, n := range f.Names {
+			if !n.IsExported() {
+				continue
+			}
+
+			fname := name
+			if fname == "" {
+				fname = lowerCamel(n.Name)
+			}
+
+			typ, nonNull := c.gqlType(f.Type, n.Name)
+			for _, opt := range opts {
+				switch {
+				case opt == "nonnull":
+					nonNull = true
+				case opt == "nullable":
+					nonNull = false
+				case strings.HasPrefix(opt, "type="):
+					typ = strings.TrimPrefix(opt, "type=")
+				}
+			}
+			if nonNull {
+				typ += "!"
+			}
+
+			writeDescr(b, "\t", doc)
+			b.WriteString("\t" + fname + ": " + typ)
+			if reason, ok := deprecation(doc); ok && !input {
+				b.WriteString(" @deprecated(reason: " + quote(reason) + ")")
+			}
+			b.WriteByte('\n')
+		}
+	}
+}
+
+// fieldTag returns the name and options of a field from its graphql tag,
+// or its json tag if it doesn't have one.
+//
+func fieldTag(tag reflect.StructTag) (string, []string) {
+	v, ok := tag.Lookup("graphql")
+	if !ok {
+		v = tag.Get("json")
+		if i := strings.IndexByte(v, ','); i >= 0 {
+			v = v[:i]
+		}
+	}
+
+	parts := strings.Split(v, ",")
+	return parts[0], parts[1:]
+}
+
+// gqlType returns the GraphQL type of a Go type and whether it's non-null.
+// Pointers, slices, maps and interfaces may be nil, so they're nullable.
+//
+func (c *goConverter) gqlType(expr goast.Expr, field string) (string, bool) {
+	switch v := expr.(type) {
+	case *goast.StarExpr:
+		typ, _ := c.gqlType(v.X, field)
+		return typ, false
+	case *goast.ArrayType:
+		if ident, ok := v.Elt.(*goast.Ident); ok && ident.Name == "byte" {
+			return "String", v.Len != nil
+		}
+
+		elem, nonNull := c.gqlType(v.Elt, "")
+		if nonNull {
+			elem += "!"
+		}
+		return "[" + elem + "]", v.Len != nil
+	case *goast.MapType, *goast.InterfaceType:
+		c.scalars["JSON"] = true
+		return "JSON", false
+	case *goast.SelectorExpr:
+		if pkg, ok := v.X.(*goast.Ident); ok && pkg.Name == "time" && v.Sel.Name == "Time" {
+			c.scalars["DateTime"] = true
+			return "DateTime", true
+		}
+
+		// Types of other packages are unknown, so they're left as scalars
+		c.scalars[v.Sel.Name] = true
+		return v.Sel.Name, true
+	case *goast.Ident:
+		if typ, ok := goBasicTypes[v.Name]; ok {
+			if (typ == "String" || typ == "Int") && isIDField(field) {
+				typ = "ID"
+			}
+			return typ, true
+		}
+
+		d, ok := c.decls[v.Name]
+		if !ok {
+			c.scalars[v.Name] = true
+			return v.Name, true
+		}
+		if c.converted(d) {
+			return d.gqlName, true
+		}
+
+		// A named type which isn't converted is its underlying type
+		return c.gqlType(d.typ, field)
+	}
+
+	c.scalars["JSON"] = true
+	return "JSON", false
+}
+
+var goBasicTypes = map[string]string{
+	"string":  "String",
+	"bool":    "Boolean",
+	"int":     "Int",
+	"int8":    "Int",
+	"int16":   "Int",
+	"int32":   "Int",
+	"int64":   "Int",
+	"uint":    "Int",
+	"uint8":   "Int",
+	"uint16":  "Int",
+	"uint32":  "Int",
+	"uint64":  "Int",
+	"byte":    "Int",
+	"rune":    "Int",
+	"float32": "Float",
+	"float64": "Float",
+}
+
+// isIDField reports whether a Go field name is an identifier e.g. ID, UserID.
+func isIDField(name string) bool {
+	return name == "ID" || name == "Id" || strings.HasSuffix(name, "ID")
+}
+
+// goTypeName returns the name of a, possibly pointer, named type.
+func goTypeName(expr goast.Expr) string {
+	switch v := expr.(type) {
+	case *goast.StarExpr:
+		return goTypeName(v.X)
+	case *goast.Ident:
+		return v.Name
+	case *goast.SelectorExpr:
+		return v.Sel.Name
+	}
+	return ""
+}
+
+// lowerCamel lowers the first word of a Go name e.g. ID becomes id
+// and HTTPServer becomes httpServer.
+//
+func lowerCamel(name string) string {
+	r := []rune(name)
+	for i := range r {
+		if i > 0 && i+1 < len(r) && unicode.IsLower(r[i+1]) {
+			break
+		}
+		if !unicode.IsUpper(r[i]) {
+			break
+		}
+		r[i] = unicode.ToLower(r[i])
+	}
+	return string(r)
+}
+
+// enumValueName returns the name of an enum value from the name of its
+// constant, without the name of its type e.g. RoleSuperAdmin becomes SUPER_ADMIN.
+//
+func enumValueName(typ, name string) string {
+	if trimmed := strings.TrimPrefix(name, typ); trimmed != "" && trimmed != name {
+		name = trimmed
+	}
+
+	var b strings.Builder
+	r := []rune(name)
+	for i, c := range r {
+		if i > 0 && unicode.IsUpper(c) && (unicode.IsLower(r[i-1]) || i+1 < len(r) && unicode.IsLower(r[i+1])) && r[i-1] != '_' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(c))
+	}
+	return b.String()
+}
+
+// deprecation returns the reason from a "Deprecated: " paragraph of a doc comment.
+func deprecation(doc string) (string, bool) {
+	for _, para := range strings.Split(doc, "\n\n") {
+		if strings.HasPrefix(para, "Deprecated: ") {
+			return strings.Join(strings.Fields(strings.TrimPrefix(para, "Deprecated: ")), " "), true
+		}
+	}
+	return "", false
+}
+
+// writeDescr writes a doc comment as a block string description.
+func writeDescr(b *strings.Builder, indent, doc string) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+
+	b.WriteString(indent + `"""` + "\n")
+	for _, line := range strings.Split(strings.ReplaceAll(doc, `"""`, `\"""`), "\n") {
+		b.WriteString(indent + line + "\n")
+	}
+	b.WriteString(indent + `"""` + "\n")
+}
+
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
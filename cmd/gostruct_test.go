@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+var goModels = []byte(`package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// User is a member of the site.
+type User struct {
+	ID        string
+	Name      string    ` + "`json:\"name\"`" + `
+	Email     *string   ` + "`json:\"email,omitempty\"`" + `
+	CreatedAt time.Time ` + "`json:\"created_at\"`" + `
+	Roles     []Role
+	Friends   []*User
+	Token     uuid.UUID
+	Meta      map[string]interface{}
+	password  string
+	Secret    string ` + "`json:\"-\"`" + `
+	Score     int64 ` + "`graphql:\"score,nullable\"`" + `
+
+	// Nick is what friends call them.
+	//
+	// Deprecated: Use Name.
+	Nick string
+
+	Timestamps
+}
+
+type Timestamps struct {
+	UpdatedAt time.Time
+}
+
+type Role string
+
+const (
+	RoleAdmin      Role = "admin"
+	RoleSuperAdmin Role = "super_admin"
+)
+`)
+
+var goAnnotated = []byte(`package models
+
+//gqlc:type
+type Post struct {
+	Title  string
+	Status Status
+}
+
+//gqlc:input NewPost
+type PostInput struct {
+	Title string
+}
+
+//gqlc:enum
+type Status int
+
+const (
+	Draft Status = iota
+	Published
+)
+
+type Ignored struct {
+	A string
+}
+`)
+
+func TestConvert_Go(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		Files map[string][]byte
+		Args  []string
+		Ex    string
+	}{
+		{
+			Name:  "Exported",
+			Files: map[string][]byte{"/models/user.go": goModels, "/models/user_test.go": []byte("package models\n\ntype Fake struct{}\n")},
+			Args:  []string{"/models"},
+			Ex: `scalar DateTime
+
+scalar JSON
+
+scalar UUID
+
+type Timestamps {
+  updatedAt: DateTime!
+}
+
+"User is a member of the site."
+type User {
+  id: ID!
+  name: String!
+  email: String
+  created_at: DateTime!
+  roles: [String!]
+  friends: [User]
+  token: UUID!
+  meta: JSON
+  score: Int
+  """
+  Nick is what friends call them.
+
+  Deprecated: Use Name.
+  """
+  nick: String! @deprecated(reason: "Use Name.")
+  updatedAt: DateTime!
+}
+`,
+		},
+		{
+			Name:  "Annotated",
+			Files: map[string][]byte{"/post.go": goAnnotated},
+			Args:  []string{"/post.go"},
+			Ex: `type Post {
+  title: String!
+  status: Status!
+}
+
+enum Status {
+  DRAFT
+  PUBLISHED
+}
+
+input NewPost {
+  title: String!
+}
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			fs := afero.NewMemMapFs()
+			for name, b := range testCase.Files {
+				afero.WriteFile(fs, name, b, 0644)
+			}
+
+			var b bytes.Buffer
+			c := NewCLI(WithFS(fs))
+			cmd := c.newConvertCmd()
+			cmd.SetOut(&b)
+			cmd.SetArgs(testCase.Args)
+
+			err := cmd.Execute()
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if b.String() != testCase.Ex {
+				subT.Errorf("expected:\n%s\nbut got:\n%s", testCase.Ex, b.String())
+			}
+		})
+	}
+}
+
+func TestConvert_Unknown(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/api.raml", []byte("#%RAML 1.0"), 0644)
+	afero.WriteFile(fs, "/user.go", goModels, 0644)
+
+	for _, args := range [][]string{{"/api.raml"}, {"/user.go", "/api.raml"}} {
+		c := NewCLI(WithFS(fs))
+		cmd := c.newConvertCmd()
+		cmd.SetArgs(args)
+
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("expected an error for: %v", args)
+		}
+	}
+}
+
+func TestEnumValueName(t *testing.T) {
+	testCases := map[[2]string]string{
+		{"Role", "RoleSuperAdmin"}: "SUPER_ADMIN",
+		{"Role", "Role"}:           "ROLE",
+		{"Status", "Draft"}:        "DRAFT",
+		{"Kind", "KindHTTPServer"}: "HTTP_SERVER",
+	}
+
+	for in, ex := range testCases {
+		if name := enumValueName(in[0], in[1]); name != ex {
+			t.Errorf("expected %s to be %s, but got: %s", in[1], ex, name)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+// history.go contains the history command, which generates documentation for
+// every version of a schema.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/scanner"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// latestVersion is the directory the newest version is also generated to.
+const latestVersion = "latest"
+
+// schemaVersion is a named version of a schema.
+type schemaVersion struct {
+	name string
+	file string
+}
+
+func (c *CommandLine) newHistoryCmd() *baseCmd {
+	gc := &gqlcCmd{
+		cfg: &gqlcConfig{
+			client: defaultClient,
+			jobs:   1,
+		},
+	}
+
+	var (
+		out     string
+		gitPath string
+		opts    = make(map[string]interface{})
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history [name=]schema...",
+		Short: "Generate documentation for every version of a schema",
+		Long: `history generates the documentation of each version of a schema, oldest
+first, to a directory of its own under the output directory. The newest
+version is also generated to latest/, so links to it don't go stale.
+
+Each version is named by the name given before its file, e.g. v1=old.gql, or
+by its file otherwise. With --git, each argument is a git revision, e.g. a tag,
+instead and the schema is read from the given path as of that revision.
+
+Every version after the first is given a CHANGELOG.md, which lists what
+changed since the version before it like the diff command does, and an
+index.md links to the documentation and changelog of each version.`,
+		Example: `gqlc history -o docs v1=v1.gql v2=schema.gql
+gqlc history --git schema.gql --doc_opt html -o docs v1.0.0 v2.0.0 main`,
+		Args: cobra.MinimumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) (err error) {
+			gc.cfg.ipaths, err = cmd.Flags().GetStringSlice("import_path")
+			return
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g := c.docGenerator()
+			if g == nil {
+				return fmt.Errorf("gqlc: history requires the doc generator")
+			}
+
+			versions, fs, err := readVersions(c.fs, gitPath, args...)
+			if err != nil {
+				return err
+			}
+			return gc.history(fs, g, opts, out, versions)
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringSliceP("import_path", "I", []string{"."}, `Specify the directory in which to search for
+imports.  May be specified multiple times;
+directories will be searched in order.  If not
+given, the current working directory is used.`)
+	cmd.Flags().StringVarP(&out, "output", "o", ".", "Directory to write the documentation to.")
+	cmd.Flags().StringVar(&gitPath, "git", "", "Read the schema from this path at each git revision given.")
+	cmd.Flags().Var(genFlag{
+		opts:  opts,
+		fp:    &fparser{Scanner: new(scanner.Scanner)},
+		isOpt: true,
+	}, "doc_opt", "Pass additional options to the doc generator.")
+
+	return &baseCmd{Command: cmd}
+}
+
+// docGenerator returns the registered documentation generator, if any.
+func (c *CommandLine) docGenerator() gen.Generator {
+	for _, cfg := range c.gens {
+		if cfg.name == "doc_out" {
+			return cfg.g
+		}
+	}
+	return nil
+}
+
+// gitShow reads a file as of a git revision.
+var gitShow = defaultGitShow
+
+func defaultGitShow(rev, path string) ([]byte, error) {
+	return execCommand(context.Background(), "git", "show", rev+":"+path).Output()
+}
+
+// readVersions names each version given on the command line. Versions
+// read from git are written to an in-memory layer over fs, which is returned
+// for them to be parsed from.
+//
+func readVersions(fs afero.Fs, gitPath string, args ...string) ([]schemaVersion, afero.Fs, error) {
+	if gitPath != "" {
+		fs = afero.NewCopyOnWriteFs(afero.NewReadOnlyFs(fs), afero.NewMemMapFs())
+	}
+
+	versions := make([]schemaVersion, 0, len(args))
+	seen := make(map[string]bool, len(args))
+	for _, arg := range args {
+		v := schemaVersion{file: arg}
+		if i := strings.IndexByte(arg, '='); i > -1 {
+			v.name, v.file = arg[:i], arg[i+1:]
+		}
+
+		if gitPath != "" {
+			if v.name == "" {
+				v.name = v.file
+			}
+
+			b, err := gitShow(v.file, gitPath)
+			if err != nil {
+				return nil, nil, fmt.Errorf("gqlc: couldn't read %s at %s: %w", gitPath, v.file, err)
+			}
+
+			v.file = filepath.Join(filepath.Dir(gitPath), v.name, filepath.Base(gitPath))
+			if err = fs.MkdirAll(filepath.Dir(v.file), 0755); err != nil {
+				return nil, nil, err
+			}
+			if err = afero.WriteFile(fs, v.file, b, 0644); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if v.name == "" {
+			v.name = strings.TrimSuffix(filepath.Base(v.file), filepath.Ext(v.file))
+		}
+		v.name = strings.Replace(v.name, "/", "-", -1)
+
+		switch {
+		case v.name == latestVersion:
+			return nil, nil, fmt.Errorf("gqlc: version name is reserved: %s", v.name)
+		case seen[v.name]:
+			return nil, nil, fmt.Errorf("gqlc: version is given more than once: %s", v.name)
+		}
+		seen[v.name] = true
+
+		versions = append(versions, v)
+	}
+	return versions, fs, nil
+}
+
+// history generates the documentation of each version, along with the
+// changes since the version before it and an index of them.
+//
+func (c *gqlcCmd) history(fs afero.Fs, g gen.Generator, opts map[string]interface{}, out string, versions []schemaVersion) error {
+	changes := make([][]change, len(versions))
+
+	var prev map[string]*symbol
+	for i, v := range versions {
+		err := c.generateVersion(fs, g, opts, filepath.Join(out, v.name), v.file)
+		if err != nil {
+			return err
+		}
+
+		syms, err := c.loadSymbols(fs, v.file)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			changes[i] = diffSymbols(prev, syms)
+			err = writeChangelog(fs, filepath.Join(out, v.name, "CHANGELOG.md"), versions[i-1].name, v.name, changes[i])
+			if err != nil {
+				return err
+			}
+		}
+		prev = syms
+	}
+
+	last := versions[len(versions)-1]
+	err := c.generateVersion(fs, g, opts, filepath.Join(out, latestVersion), last.file)
+	if err != nil {
+		return err
+	}
+
+	err = fs.MkdirAll(out, 0755)
+	if err != nil {
+		return err
+	}
+
+	f, err := fs.Create(filepath.Join(out, "index.md"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return writeHistoryIndex(f, versions, changes)
+}
+
+// generateVersion generates the documentation of a single version.
+func (c *gqlcCmd) generateVersion(fs afero.Fs, g gen.Generator, opts map[string]interface{}, dir, file string) error {
+	vc := &gqlcCmd{
+		cfg: &gqlcConfig{
+			ipaths: c.cfg.ipaths,
+			client: c.cfg.client,
+			jobs:   c.cfg.jobs,
+			geners: []generator{{
+				Generator: g,
+				name:      "doc",
+				opts:      opts,
+				outDir:    dir,
+			}},
+		},
+	}
+	return vc.run(fs, file)
+}
+
+// writeChangelog writes the changes made in a version as Markdown.
+func writeChangelog(fs afero.Fs, name, prev, version string, changes []change) error {
+	err := fs.MkdirAll(filepath.Dir(name), 0755)
+	if err != nil {
+		return err
+	}
+
+	f, err := fs.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "# %s\n\nChanges since %s.\n\n", version, prev)
+	if err != nil {
+		return err
+	}
+	return writeMarkdownDiff(f, changes)
+}
+
+// writeHistoryIndex writes an index of every version, newest first.
+func writeHistoryIndex(w io.Writer, versions []schemaVersion, changes [][]change) error {
+	var b strings.Builder
+	b.WriteString("# Versions\n\n| Version | Documentation | Changes |\n|---|---|---|\n")
+
+	last := versions[len(versions)-1].name
+	fmt.Fprintf(&b, "| %s (%s) | [%s](%s/) | |\n", latestVersion, last, latestVersion, latestVersion)
+
+	for i := len(versions) - 1; i >= 0; i-- {
+		v := versions[i]
+		fmt.Fprintf(&b, "| %s | [%s](%s/) |", v.name, v.name, v.name)
+		if i > 0 {
+			summary := severitySummary(changes[i])
+			if summary == "" {
+				summary = "No changes"
+			}
+			fmt.Fprintf(&b, " [%s](%s/CHANGELOG.md) |", summary, v.name)
+		} else {
+			b.WriteString(" |")
+		}
+		b.WriteByte('\n')
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/spf13/afero"
+)
+
+func TestHistory(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/in/v1.gql", diffOldSchema, 0644)
+	afero.WriteFile(fs, "/in/v2.gql", diffNewSchema, 0644)
+
+	g := newMockGenerator(t)
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).Times(3).DoAndReturn(func(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+		if opts["html"] != true {
+			return fmt.Errorf("expected the doc options to be passed on, but got: %v", opts)
+		}
+
+		f, err := gen.Context(ctx).Open(doc.Name + ".md")
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = fmt.Fprintf(f, "%d types", len(doc.Types))
+		return err
+	})
+
+	versions, vfs, err := readVersions(fs, "", "v1=/in/v1.gql", "/in/v2.gql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &gqlcCmd{cfg: &gqlcConfig{jobs: 1}}
+	err = c.history(vfs, g, map[string]interface{}{"html": true}, "/docs", versions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"/docs/v1/v1.md", "/docs/v2/v2.md", "/docs/latest/v2.md", "/docs/v2/CHANGELOG.md"} {
+		if ok, _ := afero.Exists(fs, name); !ok {
+			t.Errorf("expected %s to be written", name)
+		}
+	}
+	if ok, _ := afero.Exists(fs, "/docs/v1/CHANGELOG.md"); ok {
+		t.Error("expected the first version to not have a changelog")
+	}
+
+	latest, _ := afero.ReadFile(fs, "/docs/latest/v2.md")
+	v2, _ := afero.ReadFile(fs, "/docs/v2/v2.md")
+	if string(latest) != string(v2) {
+		t.Errorf("expected latest to be the newest version, but got: %s", latest)
+	}
+
+	changelog, _ := afero.ReadFile(fs, "/docs/v2/CHANGELOG.md")
+	if !strings.HasPrefix(string(changelog), "# v2\n\nChanges since v1.\n\n## Schema Changes\n\n🔴 3 breaking") {
+		t.Errorf("unexpected changelog:\n%s", changelog)
+	}
+
+	index, _ := afero.ReadFile(fs, "/docs/index.md")
+	ex := `# Versions
+
+| Version | Documentation | Changes |
+|---|---|---|
+| latest (v2) | [latest](latest/) | |
+| v2 | [v2](v2/) | [🔴 3 breaking · 🟡 2 dangerous · 🟢 6 safe](v2/CHANGELOG.md) |
+| v1 | [v1](v1/) | |
+`
+	if string(index) != ex {
+		t.Errorf("expected index:\n%s\nbut got:\n%s", ex, index)
+	}
+}
+
+func TestReadVersions(t *testing.T) {
+	gitShow = func(rev, path string) ([]byte, error) {
+		if rev == "missing" {
+			return nil, fmt.Errorf("unknown revision")
+		}
+		return []byte("# " + rev + "\ntype Query { a: String }"), nil
+	}
+	defer func() { gitShow = defaultGitShow }()
+
+	testCases := []struct {
+		Name  string
+		Git   string
+		Args  []string
+		Names []string
+		Err   string
+	}{
+		{
+			Name:  "Files",
+			Args:  []string{"one=a.gql", "b.gql"},
+			Names: []string{"one", "b"},
+		},
+		{
+			Name:  "Git",
+			Git:   "api/schema.gql",
+			Args:  []string{"v1.0.0", "release/2", "head=main"},
+			Names: []string{"v1.0.0", "release-2", "head"},
+		},
+		{
+			Name: "GitError",
+			Git:  "schema.gql",
+			Args: []string{"missing"},
+			Err:  "gqlc: couldn't read schema.gql at missing: unknown revision",
+		},
+		{
+			Name: "Reserved",
+			Args: []string{"latest=a.gql"},
+			Err:  "gqlc: version name is reserved: latest",
+		},
+		{
+			Name: "Duplicate",
+			Args: []string{"a.gql", "a=b.gql"},
+			Err:  "gqlc: version is given more than once: a",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			versions, fs, err := readVersions(afero.NewMemMapFs(), testCase.Git, testCase.Args...)
+			if testCase.Err != "" {
+				if err == nil || err.Error() != testCase.Err {
+					subT.Errorf("expected error: %s, but got: %v", testCase.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			var names []string
+			for _, v := range versions {
+				names = append(names, v.name)
+
+				if testCase.Git == "" {
+					continue
+				}
+
+				b, err := afero.ReadFile(fs, v.file)
+				if err != nil {
+					subT.Error(err)
+					continue
+				}
+				if !strings.HasPrefix(string(b), "# ") {
+					subT.Errorf("expected %s to hold the schema at its revision, but got: %s", v.file, b)
+				}
+			}
+
+			if strings.Join(names, ",") != strings.Join(testCase.Names, ",") {
+				subT.Errorf("expected versions: %v, but got: %v", testCase.Names, names)
+			}
+		})
+	}
+}
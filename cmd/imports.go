@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gqlc/gqlc/diag"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+// importEdge is a single path given to the @import directive of a document.
+type importEdge struct {
+	from, to string
+	pos      token.Position
+
+	dir *ast.DirectiveLit
+	lit *ast.BasicLit
+}
+
+// importCycleError is a chain of imports which leads back to the
+// document it started from.
+//
+type importCycleError struct {
+	chain []importEdge
+}
+
+func (e *importCycleError) Error() string {
+	var b strings.Builder
+	b.WriteString("import cycle: ")
+	b.WriteString(e.chain[0].from)
+	for _, edge := range e.chain {
+		b.WriteString(" → ")
+		b.WriteString(edge.to)
+	}
+
+	for _, edge := range e.chain {
+		fmt.Fprintf(&b, "\n\t%s:%d:%d: imports %s", edge.from, edge.pos.Line, edge.pos.Column, edge.to)
+	}
+	return b.String()
+}
+
+// checkImportCycles reports the first import cycle found or, if cycles are
+// allowed, breaks every cycle by dropping the import which closes it.
+//
+func (c *gqlcCmd) checkImportCycles(dset *token.DocSet, docs map[string]*ast.Document) error {
+	for {
+		cycle := findImportCycle(dset, docs)
+		if cycle == nil {
+			return nil
+		}
+
+		last := cycle.chain[len(cycle.chain)-1]
+		if !c.cfg.allowImportCycles {
+			return c.cycleDiagnostic(cycle)
+		}
+
+		d := c.cycleDiagnostic(cycle)
+		d.Severity = diag.Warning
+		d.Pos = d.Related[len(d.Related)-1].Pos
+		d.Msg = fmt.Sprintf("ignoring import of %s to break %s", last.to, strings.SplitN(cycle.Error(), "\n", 2)[0])
+		c.cfg.report.report(*d)
+
+		removeImport(last.dir, last.lit)
+	}
+}
+
+// cycleDiagnostic points an import cycle at the import which starts it,
+// relating it to every import along the way.
+//
+func (c *gqlcCmd) cycleDiagnostic(cycle *importCycleError) *diag.Diagnostic {
+	pos := func(edge importEdge) diag.Pos {
+		file := c.files[edge.from]
+		if file == "" {
+			file = edge.from
+		}
+		return diag.Pos{File: file, Line: edge.pos.Line, Column: edge.pos.Column}
+	}
+
+	d := &diag.Diagnostic{
+		Severity: diag.Error,
+		Code:     codeImportCycle,
+		Pos:      pos(cycle.chain[0]),
+		Msg:      cycle.Error(),
+	}
+	for _, edge := range cycle.chain {
+		d.Related = append(d.Related, diag.Related{Pos: pos(edge), Msg: "imports " + edge.to})
+	}
+	return d
+}
+
+// findImportCycle walks the import graph depth first. Documents are
+// visited by name and their imports in the order they're declared, so the
+// same cycle is always found first.
+//
+func findImportCycle(dset *token.DocSet, docs map[string]*ast.Document) *importCycleError {
+	names := make([]string, 0, len(docs))
+	for name := range docs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(docs))
+
+	var stack []importEdge
+	var visit func(name string) *importCycleError
+	visit = func(name string) *importCycleError {
+		state[name] = visiting
+
+		for _, edge := range importEdges(dset, name, docs[name]) {
+			if _, ok := docs[edge.to]; !ok {
+				// Unknown imports are reported by the compiler
+				continue
+			}
+
+			switch state[edge.to] {
+			case visiting:
+				start := len(stack)
+				for edge.from != edge.to && stack[start-1].from != edge.to {
+					start--
+				}
+				if edge.from != edge.to {
+					start--
+				}
+
+				chain := append(append([]importEdge{}, stack[start:]...), edge)
+				return &importCycleError{chain: chain}
+			case unvisited:
+				stack = append(stack, edge)
+				if cycle := visit(edge.to); cycle != nil {
+					return cycle
+				}
+				stack = stack[:len(stack)-1]
+			}
+		}
+
+		state[name] = visited
+		return nil
+	}
+
+	for _, name := range names {
+		if state[name] != unvisited {
+			continue
+		}
+
+		if cycle := visit(name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// importEdges returns the imports of a document, which are named by the
+// base of their path, like the documents they refer to.
+//
+func importEdges(dset *token.DocSet, name string, doc *ast.Document) (edges []importEdge) {
+	for _, dir := range doc.Directives {
+		if dir.Name != "import" || dir.Args == nil {
+			continue
+		}
+
+		for _, lit := range importLits(dir) {
+			edges = append(edges, importEdge{
+				from: name,
+				to:   filepath.Base(strings.Trim(lit.Value, "\"")),
+				pos:  dset.Position(token.Pos(lit.ValuePos)),
+				dir:  dir,
+				lit:  lit,
+			})
+		}
+	}
+	return
+}
+
+// importLits returns the paths given to an @import directive.
+func importLits(dir *ast.DirectiveLit) (paths []*ast.BasicLit) {
+	for _, arg := range dir.Args.Args {
+		compLit := arg.Value.(*ast.Arg_CompositeLit).CompositeLit
+		listLit := compLit.Value.(*ast.CompositeLit_ListLit).ListLit.List
+
+		switch v := listLit.(type) {
+		case *ast.ListLit_BasicList:
+			paths = append(paths, v.BasicList.Values...)
+		case *ast.ListLit_CompositeList:
+			for _, c := range v.CompositeList.Values {
+				paths = append(paths, c.Value.(*ast.CompositeLit_BasicLit).BasicLit)
+			}
+		}
+	}
+	return
+}
+
+// removeImport removes a path from an @import directive.
+func removeImport(dir *ast.DirectiveLit, lit *ast.BasicLit) {
+	for _, arg := range dir.Args.Args {
+		compLit := arg.Value.(*ast.Arg_CompositeLit).CompositeLit
+		listLit := compLit.Value.(*ast.CompositeLit_ListLit).ListLit.List
+
+		switch v := listLit.(type) {
+		case *ast.ListLit_BasicList:
+			vals := v.BasicList.Values[:0]
+			for _, p := range v.BasicList.Values {
+				if p != lit {
+					vals = append(vals, p)
+				}
+			}
+			v.BasicList.Values = vals
+		case *ast.ListLit_CompositeList:
+			vals := v.CompositeList.Values[:0]
+			for _, c := range v.CompositeList.Values {
+				if c.Value.(*ast.CompositeLit_BasicLit).BasicLit != lit {
+					vals = append(vals, c)
+				}
+			}
+			v.CompositeList.Values = vals
+		}
+	}
+}
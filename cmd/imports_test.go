@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gqlc/gqlc/diag"
+	"github.com/spf13/afero"
+)
+
+func newCycleFs() afero.Fs {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/cycles/a.gql", []byte(`@import(paths: ["b.gql"])
+
+type Query {
+	b: B
+}
+
+type A {
+	name: String
+}`), 0644)
+	afero.WriteFile(fs, "/cycles/b.gql", []byte(`@import(paths: ["c.gql"])
+
+type B {
+	c: C
+}`), 0644)
+	afero.WriteFile(fs, "/cycles/c.gql", []byte(`@import(paths: ["a.gql"])
+
+type C {
+	a: A
+}`), 0644)
+	afero.WriteFile(fs, "/cycles/sub.gql", []byte(`@import(paths: ["self.gql"])
+
+type Sub {
+	self: Self
+}`), 0644)
+	afero.WriteFile(fs, "/cycles/self.gql", []byte(`@import(paths: ["self.gql"])
+
+type Self {
+	name: String
+}`), 0644)
+	return fs
+}
+
+func TestCheckImportCycles(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Args   []string
+		Expect []string
+	}{
+		{
+			Name: "Chain",
+			Args: []string{"a.gql"},
+			Expect: []string{
+				"import cycle: a.gql → b.gql → c.gql → a.gql",
+				"a.gql:1:17: imports b.gql",
+				"b.gql:1:17: imports c.gql",
+				"c.gql:1:17: imports a.gql",
+			},
+		},
+		{
+			Name: "MidChain",
+			Args: []string{"b.gql"},
+			Expect: []string{
+				"import cycle: a.gql → b.gql → c.gql → a.gql",
+			},
+		},
+		{
+			Name: "Self",
+			Args: []string{"self.gql"},
+			Expect: []string{
+				"import cycle: self.gql → self.gql",
+				"self.gql:1:17: imports self.gql",
+			},
+		},
+		{
+			Name: "SelfViaImport",
+			Args: []string{"sub.gql"},
+			Expect: []string{
+				"import cycle: self.gql → self.gql",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			cmd := &gqlcCmd{cfg: &gqlcConfig{ipaths: []string{"/cycles"}}}
+
+			err := cmd.run(newCycleFs(), testCase.Args...)
+			if err == nil {
+				subT.Fatal("expected import cycle error")
+			}
+
+			derr, ok := err.(*diag.Diagnostic)
+			if !ok {
+				subT.Fatalf("expected diagnostic but got: %T", err)
+			}
+			if derr.Pos.Line != 1 {
+				subT.Errorf("expected line 1 but got: %d", derr.Pos.Line)
+			}
+			if len(derr.Related) != strings.Count(err.Error(), "\n") {
+				subT.Errorf("expected an import to be related for each in the cycle, but got: %v", derr.Related)
+			}
+
+			for _, s := range testCase.Expect {
+				if !strings.Contains(err.Error(), s) {
+					subT.Errorf("expected error to contain: %s\ngot: %s", s, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckImportCycles_Allow(t *testing.T) {
+	g := newMockGenerator(t)
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners:            []generator{{Generator: g}},
+			ipaths:            []string{"/cycles"},
+			allowImportCycles: true,
+		},
+	}
+
+	err := cmd.run(newCycleFs(), "a.gql")
+	if err != nil {
+		t.Error(err)
+	}
+}
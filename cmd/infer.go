@@ -0,0 +1,465 @@
+// infer.go contains the inference of a starter schema from example JSON payloads.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gqlc/gqlc/sdl"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func (c *CommandLine) newInferCmd() *baseCmd {
+	var out, root string
+
+	cmd := &cobra.Command{
+		Use:   "infer files...",
+		Short: "Infer a starter schema from example JSON payloads",
+		Long: `infer generates a GraphQL schema from example JSON responses, such as
+those of an existing REST API. Every payload is a sample of the root type,
+and nested objects become types named after the keys they're found under.
+
+Samples are merged, so the more payloads that are given, the better the
+guesses are: a field is only non-null if it had a value in every sample.
+Numbers become Int or Float, keys named id, or ending in Id, become ID and
+strings holding RFC 3339 timestamps or dates become the DateTime and Date
+scalars. Fields whose type couldn't be told, e.g. they were always null,
+are described as such.
+
+The schema is only a starting point, and should be reviewed before it's used.`,
+		Example: `gqlc infer user.json users.json -o schema.gql`,
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return inferSchema(c.fs, cmd.OutOrStdout(), out, root, args...)
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&out, "output", "o", "", "File to write the schema to. If not given, it's printed.")
+	cmd.Flags().StringVar(&root, "root", "Query", "Name of the type each payload is a sample of.")
+
+	return &baseCmd{Command: cmd}
+}
+
+func inferSchema(fs afero.Fs, w io.Writer, out, root string, filenames ...string) error {
+	inf := newInferrer(root)
+	for _, name := range filenames {
+		f, err := fs.Open(name)
+		if err != nil {
+			return err
+		}
+
+		v, err := decodeJSON(json.NewDecoder(f))
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("gqlc: %s: %w", name, err)
+		}
+
+		if err = inf.payload(v); err != nil {
+			return fmt.Errorf("gqlc: %s: %w", name, err)
+		}
+	}
+
+	return writeSchema(fs, w, out, inf.schema())
+}
+
+// writeSchema formats the SDL of a generated schema and writes it to the
+// named file, or w if there isn't one.
+//
+func writeSchema(fs afero.Fs, w io.Writer, out, src string) error {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "schema", strings.NewReader(src), 0)
+	if err != nil {
+		return fmt.Errorf("gqlc: generated an invalid schema: %w\n%s", err, src)
+	}
+
+	b := sdl.Format(doc, nil)
+	if out == "" {
+		_, err = w.Write(b)
+		return err
+	}
+	return afero.WriteFile(fs, out, b, 0644)
+}
+
+// jsonField is a key of a JSON object, which are kept in order so the
+// fields of a type are in the order they appear in its samples.
+//
+type jsonField struct {
+	key string
+	val interface{}
+}
+
+// decodeJSON decodes a JSON value as: nil, bool, json.Number, string,
+// []interface{} or, for objects, []jsonField.
+//
+func decodeJSON(dec *json.Decoder) (interface{}, error) {
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	d, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch d {
+	case '{':
+		var fields []jsonField
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			val, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, jsonField{key: key.(string), val: val})
+		}
+		if fields == nil {
+			fields = []jsonField{}
+		}
+		_, err = dec.Token()
+		return fields, err
+	case '[':
+		list := []interface{}{}
+		for dec.More() {
+			val, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, val)
+		}
+		_, err = dec.Token()
+		return list, err
+	}
+	return nil, fmt.Errorf("unexpected %s", d)
+}
+
+// Kinds of JSON values seen for a shape
+const (
+	seenBool = 1 << iota
+	seenInt
+	seenFloat
+	seenString
+	seenObject
+	seenList
+)
+
+// shape is everything which has been seen of a value across all samples.
+type shape struct {
+	kinds int
+	null  bool
+
+	// strings counts the string values, and dates and dateTimes those
+	// which are dates and RFC 3339 timestamps.
+	//
+	strings, dates, dateTimes int
+
+	obj  *objShape
+	elem *shape
+}
+
+// objShape is an object type, whose fields have been seen in samples samples.
+type objShape struct {
+	name    string
+	samples int
+
+	fields []*fieldShape
+	byKey  map[string]*fieldShape
+}
+
+type fieldShape struct {
+	key  string
+	seen int
+	shape
+}
+
+// inferrer merges JSON samples into object types.
+type inferrer struct {
+	root  *objShape
+	types map[string]*objShape
+	order []*objShape
+}
+
+func newInferrer(root string) *inferrer {
+	inf := &inferrer{types: make(map[string]*objShape)}
+	inf.root = inf.object(root)
+	return inf
+}
+
+// object returns the type with the given name, creating it if need be.
+// Objects found under the same key share a type.
+//
+func (inf *inferrer) object(name string) *objShape {
+	o, ok := inf.types[name]
+	if !ok {
+		o = &objShape{name: name, byKey: make(map[string]*fieldShape)}
+		inf.types[name] = o
+		inf.order = append(inf.order, o)
+	}
+	return o
+}
+
+// payload adds a payload, which is either an object or a list of them, as
+// samples of the root type.
+//
+func (inf *inferrer) payload(v interface{}) error {
+	switch v := v.(type) {
+	case []jsonField:
+		inf.addObject(inf.root, v)
+		return nil
+	case []interface{}:
+		for _, e := range v {
+			obj, ok := e.([]jsonField)
+			if !ok {
+				return fmt.Errorf("expected a list of objects")
+			}
+			inf.addObject(inf.root, obj)
+		}
+		return nil
+	}
+	return fmt.Errorf("expected a JSON object, or a list of objects")
+}
+
+func (inf *inferrer) addObject(o *objShape, fields []jsonField) {
+	o.samples++
+	for _, f := range fields {
+		fs, ok := o.byKey[f.key]
+		if !ok {
+			fs = &fieldShape{key: f.key}
+			o.byKey[f.key] = fs
+			o.fields = append(o.fields, fs)
+		}
+		fs.seen++
+
+		inf.sample(&fs.shape, typeName(f.key), f.val)
+	}
+}
+
+// sample adds a value to a shape. name is given to the type of any
+// object found, which is singular for the elements of a list.
+//
+func (inf *inferrer) sample(s *shape, name string, v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		s.null = true
+	case bool:
+		s.kinds |= seenBool
+	case json.Number:
+		if i, err := v.Int64(); err == nil && i >= math.MinInt32 && i <= math.MaxInt32 {
+			s.kinds |= seenInt
+		} else {
+			s.kinds |= seenFloat
+		}
+	case string:
+		s.kinds |= seenString
+		s.strings++
+		if _, err := time.Parse(time.RFC3339, v); err == nil {
+			s.dateTimes++
+		} else if _, err := time.Parse("2006-01-02", v); err == nil {
+			s.dates++
+		}
+	case []jsonField:
+		s.kinds |= seenObject
+		if s.obj == nil {
+			s.obj = inf.object(name)
+		}
+		inf.addObject(s.obj, v)
+	case []interface{}:
+		s.kinds |= seenList
+		if s.elem == nil {
+			s.elem = new(shape)
+		}
+		for _, e := range v {
+			inf.sample(s.elem, singular(name), e)
+		}
+	}
+}
+
+// Notes for fields whose type is only a guess
+const (
+	noteUnknown = "Only null, or empty lists, were seen for this field, so its type is a guess."
+	noteMixed   = "Values of more than one type were seen for this field."
+)
+
+// typeOf returns the GraphQL type of a shape, without its outer non-null,
+// and a note if the type is a guess.
+//
+func (s *shape) typeOf(key string, scalars map[string]bool) (typ, note string) {
+	switch s.kinds {
+	case 0:
+		return "String", noteUnknown
+	case seenBool:
+		return "Boolean", ""
+	case seenInt:
+		if isIDKey(key) {
+			return "ID", ""
+		}
+		return "Int", ""
+	case seenInt | seenFloat, seenFloat:
+		return "Float", ""
+	case seenString:
+		switch {
+		case isIDKey(key):
+			return "ID", ""
+		case s.dateTimes == s.strings:
+			scalars["DateTime"] = true
+			return "DateTime", ""
+		case s.dates == s.strings:
+			scalars["Date"] = true
+			return "Date", ""
+		}
+		return "String", ""
+	case seenObject:
+		return s.obj.name, ""
+	case seenList:
+		elem, note := s.elem.typeOf(key, scalars)
+		if s.elem.kinds != 0 && !s.elem.null {
+			elem += "!"
+		}
+		return "[" + elem + "]", note
+	}
+	return "String", noteMixed
+}
+
+// isIDKey reports whether a key names an identifier e.g. id, userId, user_id.
+func isIDKey(key string) bool {
+	return key == "id" || key == "ID" || strings.HasSuffix(key, "Id") || strings.HasSuffix(key, "ID") || strings.HasSuffix(key, "_id")
+}
+
+var scalarDescrs = map[string]string{
+	"DateTime": "DateTime is an RFC 3339 timestamp e.g. 2006-01-02T15:04:05Z.",
+	"Date":     "Date is a calendar date e.g. 2006-01-02.",
+}
+
+// schema returns the inferred types as SDL.
+func (inf *inferrer) schema() string {
+	scalars := make(map[string]bool)
+
+	var types strings.Builder
+	for _, o := range inf.order {
+		types.WriteString("\ntype ")
+		types.WriteString(o.name)
+		types.WriteString(" {\n")
+
+		if len(o.fields) == 0 {
+			// An object type must have fields, but nothing is known of this one
+			types.WriteString("\t\"" + noteUnknown + "\"\n\t_: String\n")
+		}
+
+		for _, f := range o.fields {
+			typ, note := f.typeOf(f.key, scalars)
+			if f.seen == o.samples && !f.null && f.kinds != 0 {
+				typ += "!"
+			}
+
+			name := fieldName(f.key)
+			if name != f.key {
+				note = strings.TrimSpace(note + " Named " + f.key + " in the JSON payload.")
+			}
+			if note != "" {
+				types.WriteString("\t\"" + note + "\"\n")
+			}
+
+			types.WriteString("\t" + name + ": " + typ + "\n")
+		}
+		types.WriteString("}\n")
+	}
+
+	var b strings.Builder
+	for _, s := range []string{"Date", "DateTime"} {
+		if scalars[s] {
+			b.WriteString("\n\"" + scalarDescrs[s] + "\"\nscalar " + s + "\n")
+		}
+	}
+	b.WriteString(types.String())
+	return b.String()
+}
+
+// reservedTypes can't be used as the names of inferred types.
+var reservedTypes = map[string]bool{
+	"Int":      true,
+	"Float":    true,
+	"String":   true,
+	"Boolean":  true,
+	"ID":       true,
+	"Date":     true,
+	"DateTime": true,
+}
+
+// typeName returns the name of the type of objects found under a key
+// e.g. shipping_address becomes ShippingAddress.
+//
+func typeName(key string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range key {
+		if !isNameRune(r) || r == '_' {
+			upper = true
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(r) {
+			b.WriteByte('T')
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	name := b.String()
+	if name == "" || reservedTypes[name] {
+		name += "Object"
+	}
+	return name
+}
+
+// singular naively makes a plural type name singular, for the elements
+// of a list e.g. Addresses becomes Address and Categories becomes Category.
+//
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && !strings.HasSuffix(name, "us") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name
+}
+
+// fieldName makes a JSON key a valid GraphQL name.
+func fieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		if isNameRune(r) {
+			return r
+		}
+		return '_'
+	}, key)
+
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	return name
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
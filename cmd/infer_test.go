@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+var inferUsers = []byte(`[
+	{
+		"id": "1",
+		"name": "Ada",
+		"age": 36,
+		"score": 9.5,
+		"admin": true,
+		"createdAt": "2020-01-02T15:04:05Z",
+		"birthday": "1815-12-10",
+		"address": {"street": "1 Main St", "zip": null},
+		"addresses": [{"street": "1 Main St"}],
+		"categories": ["math"],
+		"nickname": null,
+		"2fa-enabled": false
+	},
+	{
+		"id": "2",
+		"name": "Alan",
+		"age": 41,
+		"score": 10,
+		"admin": false,
+		"createdAt": "2020-01-03T15:04:05Z",
+		"birthday": "1912-06-23",
+		"address": {"street": "2 Main St", "zip": "12345"},
+		"addresses": [],
+		"categories": ["math", null],
+		"nickname": null
+	}
+]`)
+
+func TestInfer(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/users.json", inferUsers, 0644)
+	afero.WriteFile(fs, "/user.json", []byte(`{"id": "3", "name": "Grace", "extra": 1}`), 0644)
+
+	var b bytes.Buffer
+	c := NewCLI(WithFS(fs))
+	cmd := c.newInferCmd()
+	cmd.SetOut(&b)
+	cmd.SetArgs([]string{"/users.json", "/user.json"})
+
+	err := cmd.Execute()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := `"Date is a calendar date e.g. 2006-01-02."
+scalar Date
+
+"DateTime is an RFC 3339 timestamp e.g. 2006-01-02T15:04:05Z."
+scalar DateTime
+
+type Address {
+  street: String!
+  zip: String
+}
+
+type Query {
+  id: ID!
+  name: String!
+  age: Int
+  score: Float
+  admin: Boolean
+  createdAt: DateTime
+  birthday: Date
+  address: Address
+  addresses: [Address!]
+  categories: [String]
+  """
+  Only null, or empty lists, were seen for this field, so its type is a guess.
+  """
+  nickname: String
+  "Named 2fa-enabled in the JSON payload."
+  _2fa_enabled: Boolean
+  extra: Int
+}
+`
+	if b.String() != ex {
+		t.Errorf("expected:\n%s\nbut got:\n%s", ex, b.String())
+	}
+}
+
+func TestInfer_Output(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/user.json", []byte(`{"id": 1, "tags": [], "meta": {}, "value": [1, "a"]}`), 0644)
+
+	c := NewCLI(WithFS(fs))
+	cmd := c.newInferCmd()
+	cmd.SetArgs([]string{"--root", "User", "-o", "/schema.gql", "/user.json"})
+
+	err := cmd.Execute()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	b, err := afero.ReadFile(fs, "/schema.gql")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := `type Meta {
+  """
+  Only null, or empty lists, were seen for this field, so its type is a guess.
+  """
+  _: String
+}
+
+type User {
+  id: ID!
+  """
+  Only null, or empty lists, were seen for this field, so its type is a guess.
+  """
+  tags: [String]!
+  meta: Meta!
+  "Values of more than one type were seen for this field."
+  value: [String!]!
+}
+`
+	if string(b) != ex {
+		t.Errorf("expected:\n%s\nbut got:\n%s", ex, b)
+	}
+}
+
+func TestInfer_InvalidPayload(t *testing.T) {
+	testCases := []struct {
+		Name    string
+		Payload string
+	}{
+		{Name: "Scalar", Payload: `"hello"`},
+		{Name: "ListOfScalars", Payload: `[1, 2]`},
+		{Name: "Malformed", Payload: `{"id": }`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			fs := afero.NewMemMapFs()
+			afero.WriteFile(fs, "/data.json", []byte(testCase.Payload), 0644)
+
+			c := NewCLI(WithFS(fs))
+			cmd := c.newInferCmd()
+			cmd.SetArgs([]string{"/data.json"})
+
+			if err := cmd.Execute(); err == nil {
+				subT.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	testCases := map[string]string{
+		"shipping_address": "ShippingAddress",
+		"lineItems":        "LineItems",
+		"2fa":              "T2fa",
+		"string":           "StringObject",
+		"$":                "Object",
+	}
+
+	for key, ex := range testCases {
+		if name := typeName(key); name != ex {
+			t.Errorf("expected %s to be named %s, but got: %s", key, ex, name)
+		}
+	}
+}
+
+func TestSingular(t *testing.T) {
+	testCases := map[string]string{
+		"Categories": "Category",
+		"Addresses":  "Address",
+		"Boxes":      "Box",
+		"Users":      "User",
+		"Status":     "Status",
+		"Class":      "Class",
+		"Data":       "Data",
+	}
+
+	for name, ex := range testCases {
+		if s := singular(name); s != ex {
+			t.Errorf("expected %s to be %s, but got: %s", name, ex, s)
+		}
+	}
+}
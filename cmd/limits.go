@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// outputLimits caps how much output generators may write over a whole
+// run, so a runaway plugin or template fails instead of filling the disk.
+// A limit of 0 means no limit. It's shared by every document and
+// generator, which may write concurrently.
+//
+type outputLimits struct {
+	maxFiles     int64
+	maxBytes     int64
+	maxFileBytes int64
+
+	files int64
+	bytes int64
+}
+
+// reset clears what's been counted towards the limits.
+func (l *outputLimits) reset() {
+	atomic.StoreInt64(&l.files, 0)
+	atomic.StoreInt64(&l.bytes, 0)
+}
+
+// open counts a file towards the limits, if it fits.
+func (l *outputLimits) open(name string) error {
+	if l == nil {
+		return nil
+	}
+
+	n := atomic.AddInt64(&l.files, 1)
+	if l.maxFiles > 0 && n > l.maxFiles {
+		return fmt.Errorf("gqlc: writing %s exceeds the output limit of %d files", name, l.maxFiles)
+	}
+	return nil
+}
+
+// writer counts the bytes written to w towards the limits.
+func (l *outputLimits) writer(name string, w io.WriteCloser) io.WriteCloser {
+	if l == nil || (l.maxBytes <= 0 && l.maxFileBytes <= 0) {
+		return w
+	}
+	return &limitWriter{WriteCloser: w, name: name, limits: l}
+}
+
+type limitWriter struct {
+	io.WriteCloser
+
+	name   string
+	limits *outputLimits
+	n      int64
+}
+
+// Write refuses to write p if it would exceed a limit, so a file is never
+// left with more than its limit.
+//
+func (w *limitWriter) Write(p []byte) (int, error) {
+	size := int64(len(p))
+	if max := w.limits.maxFileBytes; max > 0 && w.n+size > max {
+		return 0, fmt.Errorf("gqlc: %s exceeds the output limit of %s per file", w.name, formatSize(max))
+	}
+
+	total := atomic.AddInt64(&w.limits.bytes, size)
+	if max := w.limits.maxBytes; max > 0 && total > max {
+		atomic.AddInt64(&w.limits.bytes, -size)
+		return 0, fmt.Errorf("gqlc: writing %s exceeds the output limit of %s in total", w.name, formatSize(max))
+	}
+
+	n, err := w.WriteCloser.Write(p)
+	w.n += int64(n)
+	if n < len(p) {
+		atomic.AddInt64(&w.limits.bytes, int64(n)-size)
+	}
+	return n, err
+}
+
+// sizeUnits are the suffixes a size may be given with, from the binary
+// units down to bytes, so the first unit which divides a size is the
+// one it's formatted with.
+//
+var sizeUnits = []struct {
+	suffix string
+	n      int64
+}{
+	{suffix: "GiB", n: 1 << 30},
+	{suffix: "MiB", n: 1 << 20},
+	{suffix: "KiB", n: 1 << 10},
+	{suffix: "GB", n: 1e9},
+	{suffix: "MB", n: 1e6},
+	{suffix: "KB", n: 1e3},
+	{suffix: "B", n: 1},
+}
+
+// parseSize parses a number of bytes, which may have a unit e.g. 10MB or 512KiB.
+func parseSize(s string) (int64, error) {
+	num, unit := s, int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			num, unit = strings.TrimSpace(strings.TrimSuffix(s, u.suffix)), u.n
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size: %s", s)
+	}
+	return n * unit, nil
+}
+
+// formatSize formats a number of bytes with the largest unit it's a
+// whole number of.
+//
+func formatSize(n int64) string {
+	for _, u := range sizeUnits {
+		if n >= u.n && n%u.n == 0 {
+			return strconv.FormatInt(n/u.n, 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(n, 10) + "B"
+}
+
+// sizeFlag represents a flag for setting a number of bytes.
+//
+// format: 1048576, 1MiB or 1MB
+//
+type sizeFlag struct {
+	value *int64
+}
+
+func (f sizeFlag) String() string {
+	if f.value == nil || *f.value == 0 {
+		return "0"
+	}
+	return formatSize(*f.value)
+}
+
+func (sizeFlag) Type() string { return "size" }
+
+func (f sizeFlag) Set(val string) (err error) {
+	*f.value, err = parseSize(val)
+	return
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/js"
+	"github.com/gqlc/graphql/ast"
+	"github.com/spf13/afero"
+)
+
+func TestParseSize(t *testing.T) {
+	testCases := []struct {
+		In  string
+		N   int64
+		Out string
+		Err bool
+	}{
+		{In: "0", N: 0, Out: "0B"},
+		{In: "1000", N: 1000, Out: "1KB"},
+		{In: "1024", N: 1024, Out: "1KiB"},
+		{In: "10MB", N: 10e6, Out: "10MB"},
+		{In: "512KiB", N: 512 << 10, Out: "512KiB"},
+		{In: "2 GiB", N: 2 << 30, Out: "2GiB"},
+		{In: "100B", N: 100, Out: "100B"},
+		{In: "1.5MB", Err: true},
+		{In: "-1", Err: true},
+		{In: "MB", Err: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.In, func(subT *testing.T) {
+			n, err := parseSize(testCase.In)
+			if testCase.Err {
+				if err == nil {
+					subT.Errorf("expected an error, but got: %d", n)
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if n != testCase.N {
+				subT.Errorf("expected %d, but got: %d", testCase.N, n)
+			}
+			if s := formatSize(n); s != testCase.Out {
+				subT.Errorf("expected %s, but got: %s", testCase.Out, s)
+			}
+		})
+	}
+}
+
+func TestGenCtx_Limits(t *testing.T) {
+	write := func(ctx *genCtx, name, s string) error {
+		f, err := ctx.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = f.Write([]byte(s))
+		return err
+	}
+
+	testCases := []struct {
+		Name   string
+		Limits outputLimits
+		Files  []string
+		Err    string
+	}{
+		{
+			Name:  "NoLimits",
+			Files: []string{"aaaa", "bbbb", "cccc"},
+		},
+		{
+			Name:   "MaxFiles",
+			Limits: outputLimits{maxFiles: 2},
+			Files:  []string{"a", "b", "c"},
+			Err:    "gqlc: writing out/2.txt exceeds the output limit of 2 files",
+		},
+		{
+			Name:   "MaxFileBytes",
+			Limits: outputLimits{maxFileBytes: 4},
+			Files:  []string{"aaaa", "bbbbb"},
+			Err:    "gqlc: out/1.txt exceeds the output limit of 4B per file",
+		},
+		{
+			Name:   "MaxBytes",
+			Limits: outputLimits{maxBytes: 1 << 10},
+			Files:  []string{strings.Repeat("a", 1000), strings.Repeat("b", 100)},
+			Err:    "gqlc: writing out/1.txt exceeds the output limit of 1KiB in total",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			fs := afero.NewMemMapFs()
+			ctx := &genCtx{fs: fs, dir: "out", limits: &testCase.Limits}
+
+			var err error
+			var i int
+			for ; i < len(testCase.Files); i++ {
+				err = write(ctx, fmt.Sprintf("%d.txt", i), testCase.Files[i])
+				if err != nil {
+					break
+				}
+			}
+
+			if testCase.Err == "" {
+				if err != nil {
+					subT.Error(err)
+				}
+				return
+			}
+			if err == nil || err.Error() != testCase.Err {
+				subT.Fatalf("expected error: %s\nbut got: %v", testCase.Err, err)
+			}
+
+			// The write which exceeded a limit mustn't be written
+			b, _ := afero.ReadFile(fs, fmt.Sprintf("out/%d.txt", i))
+			if len(b) != 0 {
+				subT.Errorf("expected the write over the limit to be refused, but got: %s", b)
+			}
+		})
+	}
+}
+
+func TestRun_Limits(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/limits/a.gql", []byte(`type A {
+	name: String
+}`), 0644)
+
+	g := newMockGenerator(t)
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, doc *ast.Document, _ interface{}) error {
+		f, err := gen.Context(ctx).Open(doc.Name + ".txt")
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = f.Write([]byte(strings.Repeat("a", 2048)))
+		return err
+	}).Times(2)
+
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners: []generator{{Generator: g, outDir: "/out"}},
+			ipaths: []string{"/limits"},
+			jobs:   1,
+			limits: outputLimits{maxFileBytes: 1 << 10},
+		},
+	}
+
+	err := cmd.run(fs, "a.gql")
+	if err == nil || !strings.Contains(err.Error(), "exceeds the output limit of 1KiB per file") {
+		t.Errorf("expected the output limit to be exceeded, but got: %v", err)
+	}
+
+	// Every run has its own count
+	cmd.cfg.limits = outputLimits{maxFiles: 1}
+	if err = cmd.run(fs, "a.gql"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRun_LimitsJS(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/limits/a.gql", []byte(`type A {
+	name: String
+}`), 0644)
+	afero.WriteFile(fs, "/limits/b.gql", []byte(`type B {
+	name: String
+}`), 0644)
+
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners: []generator{{Generator: new(js.Generator), name: "js", opts: map[string]interface{}{}, outDir: "/out"}},
+			ipaths: []string{"/limits"},
+			jobs:   1,
+			limits: outputLimits{maxFiles: 1},
+		},
+	}
+
+	// The generator mustn't use the file it couldn't open
+	err := cmd.run(fs, "a.gql", "b.gql")
+	if err == nil || !strings.Contains(err.Error(), "exceeds the output limit of 1 files") {
+		t.Errorf("expected the output limit to be exceeded, but got: %v", err)
+	}
+}
+
+func TestRun_MaxDepth(t *testing.T) {
+	// A field type, and argument default, which nest n levels deep
+	deep := func(n int) string {
+		l, r := strings.Repeat("[", n), strings.Repeat("]", n)
+		return fmt.Sprintf("type Query {\n\ta(b: %sInt%s = %s1%s): %sInt%s\n}\n", l, r, l, r, l, r)
+	}
+
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/depth/a.gql", []byte(deep(150)), 0644)
+
+	var maxDepth int
+	g := newMockGenerator(t)
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, doc *ast.Document, _ interface{}) error {
+		maxDepth = gen.MaxDepth(gen.Context(ctx))
+		return nil
+	}).Times(1)
+
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners:   []generator{{Generator: g, outDir: "/out"}},
+			ipaths:   []string{"/depth"},
+			jobs:     1,
+			maxDepth: gen.DefaultMaxDepth,
+		},
+	}
+
+	err := cmd.run(fs, "a.gql")
+	if err == nil {
+		t.Fatal("expected the document to be too deep")
+	}
+	expected := "gqlc: /depth/a.gql: type Query > field a: type is nested 150 levels deep, which exceeds the maximum depth of 100"
+	if !strings.HasPrefix(err.Error(), expected) || !strings.HasSuffix(err.Error(), "see --max-depth") {
+		t.Errorf("expected %q, but got: %q", expected, err)
+	}
+
+	// The limit can be raised, and is passed on to generators
+	cmd = &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners:   []generator{{Generator: g, outDir: "/out"}},
+			ipaths:   []string{"/depth"},
+			jobs:     1,
+			maxDepth: 200,
+		},
+	}
+	if err = cmd.run(fs, "a.gql"); err != nil {
+		t.Fatal(err)
+	}
+	if maxDepth != 200 {
+		t.Errorf("expected generators to be given a max depth of 200, but got: %d", maxDepth)
+	}
+}
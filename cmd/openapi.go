@@ -0,0 +1,508 @@
+// openapi.go contains the conversion of OpenAPI 3 documents to SDL.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+type openAPIDoc struct {
+	OpenAPI    string       `yaml:"openapi"`
+	Swagger    string       `yaml:"swagger"`
+	Paths      openAPIPaths `yaml:"paths"`
+	Components struct {
+		Schemas    openAPISchemas               `yaml:"schemas"`
+		Parameters map[string]*openAPIParameter `yaml:"parameters"`
+	} `yaml:"components"`
+}
+
+type openAPIPathItem struct {
+	Get        *openAPIOperation   `yaml:"get"`
+	Parameters []*openAPIParameter `yaml:"parameters"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `yaml:"operationId"`
+	Summary     string                      `yaml:"summary"`
+	Description string                      `yaml:"description"`
+	Deprecated  bool                        `yaml:"deprecated"`
+	Parameters  []*openAPIParameter         `yaml:"parameters"`
+	Responses   map[string]*openAPIResponse `yaml:"responses"`
+}
+
+type openAPIParameter struct {
+	Ref         string         `yaml:"$ref"`
+	Name        string         `yaml:"name"`
+	In          string         `yaml:"in"`
+	Description string         `yaml:"description"`
+	Required    bool           `yaml:"required"`
+	Schema      *openAPISchema `yaml:"schema"`
+}
+
+type openAPIResponse struct {
+	Content map[string]struct {
+		Schema *openAPISchema `yaml:"schema"`
+	} `yaml:"content"`
+}
+
+type openAPISchema struct {
+	Ref         string           `yaml:"$ref"`
+	Type        string           `yaml:"type"`
+	Format      string           `yaml:"format"`
+	Description string           `yaml:"description"`
+	Nullable    bool             `yaml:"nullable"`
+	Deprecated  bool             `yaml:"deprecated"`
+	Required    []string         `yaml:"required"`
+	Properties  openAPISchemas   `yaml:"properties"`
+	Items       *openAPISchema   `yaml:"items"`
+	Enum        []interface{}    `yaml:"enum"`
+	AllOf       []*openAPISchema `yaml:"allOf"`
+	OneOf       []*openAPISchema `yaml:"oneOf"`
+	AnyOf       []*openAPISchema `yaml:"anyOf"`
+}
+
+// openAPISchemas are named schemas, in the order they're declared.
+type openAPISchemas struct {
+	names   []string
+	schemas map[string]*openAPISchema
+}
+
+func (s *openAPISchemas) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var keys yaml.MapSlice
+	if err := unmarshal(&keys); err != nil {
+		return err
+	}
+	for _, item := range keys {
+		s.names = append(s.names, fmt.Sprint(item.Key))
+	}
+	return unmarshal(&s.schemas)
+}
+
+// openAPIPaths are path items, in the order they're declared.
+type openAPIPaths struct {
+	paths []string
+	items map[string]*openAPIPathItem
+}
+
+func (p *openAPIPaths) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var keys yaml.MapSlice
+	if err := unmarshal(&keys); err != nil {
+		return err
+	}
+	for _, item := range keys {
+		p.paths = append(p.paths, fmt.Sprint(item.Key))
+	}
+	return unmarshal(&p.items)
+}
+
+// isOpenAPI reports whether a JSON or YAML file is an OpenAPI document.
+func isOpenAPI(fs afero.Fs, name string) bool {
+	b, err := afero.ReadFile(fs, name)
+	if err != nil {
+		return false
+	}
+
+	var doc struct {
+		OpenAPI string `yaml:"openapi"`
+		Swagger string `yaml:"swagger"`
+	}
+	return yaml.Unmarshal(b, &doc) == nil && (doc.OpenAPI != "" || doc.Swagger != "")
+}
+
+// convertOpenAPI converts OpenAPI 3 documents to SDL. Component schemas
+// become types, and GET operations become fields of the Query type.
+//
+func convertOpenAPI(fs afero.Fs, names ...string) (string, error) {
+	c := &openAPIConverter{
+		types:   make(map[string]bool),
+		scalars: make(map[string]bool),
+	}
+
+	for _, name := range names {
+		b, err := afero.ReadFile(fs, name)
+		if err != nil {
+			return "", err
+		}
+
+		var doc openAPIDoc
+		if err = yaml.Unmarshal(b, &doc); err != nil {
+			return "", fmt.Errorf("gqlc: %s: %w", name, err)
+		}
+		if !strings.HasPrefix(doc.OpenAPI, "3.") {
+			return "", fmt.Errorf("gqlc: %s: only OpenAPI 3 documents are supported", name)
+		}
+
+		c.doc = &doc
+		for _, name := range doc.Components.Schemas.names {
+			c.component(name, doc.Components.Schemas.schemas[name])
+		}
+		for _, path := range doc.Paths.paths {
+			c.path(path, doc.Paths.items[path])
+		}
+	}
+	return c.schema(), nil
+}
+
+type openAPIConverter struct {
+	doc *openAPIDoc
+
+	decls   strings.Builder
+	query   strings.Builder
+	types   map[string]bool
+	scalars map[string]bool
+}
+
+// refName returns the name of the type a reference to a component schema refers to.
+func refName(ref string) string {
+	return typeName(ref[strings.LastIndexByte(ref, '/')+1:])
+}
+
+// component converts a component schema into a type.
+func (c *openAPIConverter) component(name string, s *openAPISchema) {
+	if !declares(s) {
+		// Other schemas are inlined wherever they're referred to
+		return
+	}
+	c.typeOf(typeName(name), s)
+}
+
+// declares reports whether a schema is converted into a type of its own.
+func declares(s *openAPISchema) bool {
+	switch {
+	case s == nil, s.Ref != "":
+		return false
+	case len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+		return true
+	case s.Type == "string":
+		return len(s.Enum) > 0
+	}
+	return (s.Type == "" || s.Type == "object") && (len(s.Properties.names) > 0 || len(s.AllOf) > 0)
+}
+
+// typeOf returns the GraphQL type of a schema, declaring any types it
+// defines inline, which are named by the given name.
+//
+func (c *openAPIConverter) typeOf(name string, s *openAPISchema) string {
+	if s == nil {
+		c.scalars["JSON"] = true
+		return "JSON"
+	}
+	if s.Ref != "" {
+		rs := c.doc.Components.Schemas.schemas[s.Ref[strings.LastIndexByte(s.Ref, '/')+1:]]
+		if rs != nil && !declares(rs) {
+			return c.typeOf(name, rs)
+		}
+		return refName(s.Ref)
+	}
+
+	switch s.Type {
+	case "string":
+		switch {
+		case len(s.Enum) > 0:
+			return c.enum(name, s)
+		case s.Format == "date-time":
+			c.scalars["DateTime"] = true
+			return "DateTime"
+		case s.Format == "date":
+			c.scalars["Date"] = true
+			return "Date"
+		}
+		return "String"
+	case "integer":
+		return "Int"
+	case "number":
+		return "Float"
+	case "boolean":
+		return "Boolean"
+	case "array":
+		elem := c.typeOf(singular(name), s.Items)
+		if s.Items != nil && !s.Items.Nullable {
+			elem += "!"
+		}
+		return "[" + elem + "]"
+	}
+
+	switch {
+	case len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+		if n := c.union(name, s); n != "" {
+			return n
+		}
+	case len(s.Properties.names) > 0 || len(s.AllOf) > 0:
+		return c.object(name, s)
+	}
+
+	// Free-form objects, and schemas without a type, can hold anything
+	c.scalars["JSON"] = true
+	return "JSON"
+}
+
+// properties returns the properties of a schema, including those of the
+// schemas it's composed of with allOf. owners are the names of the types
+// the properties are declared by, which inline types are named after.
+//
+func (c *openAPIConverter) properties(s *openAPISchema, owner string, props *openAPISchemas, owners map[string]string, required map[string]bool) {
+	if s.Ref != "" {
+		ref := s.Ref[strings.LastIndexByte(s.Ref, '/')+1:]
+		if rs, ok := c.doc.Components.Schemas.schemas[ref]; ok {
+			c.properties(rs, refName(ref), props, owners, required)
+		}
+		return
+	}
+
+	for _, sub := range s.AllOf {
+		c.properties(sub, owner, props, owners, required)
+	}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	for _, name := range s.Properties.names {
+		if _, ok := props.schemas[name]; !ok {
+			props.names = append(props.names, name)
+		}
+		props.schemas[name] = s.Properties.schemas[name]
+		owners[name] = owner
+	}
+}
+
+func (c *openAPIConverter) object(name string, s *openAPISchema) string {
+	if c.types[name] {
+		return name
+	}
+	c.types[name] = true
+
+	props := openAPISchemas{schemas: make(map[string]*openAPISchema)}
+	owners := make(map[string]string)
+	required := make(map[string]bool)
+	c.properties(s, name, &props, owners, required)
+
+	var b strings.Builder
+	writeDescr(&b, "", s.Description)
+	b.WriteString("type " + name + " {\n")
+	for _, prop := range props.names {
+		ps := props.schemas[prop]
+
+		typ := c.typeOf(owners[prop]+typeName(prop), ps)
+		if (typ == "String" || typ == "Int") && isIDKey(prop) {
+			typ = "ID"
+		}
+		if required[prop] && (ps == nil || !ps.Nullable) {
+			typ += "!"
+		}
+
+		c.field(&b, prop, typ, ps)
+	}
+	b.WriteString("}\n\n")
+
+	c.decls.WriteString(b.String())
+	return name
+}
+
+// field writes a field with the description and deprecation of its schema.
+func (c *openAPIConverter) field(b *strings.Builder, name, typ string, s *openAPISchema) {
+	var descr string
+	if s != nil {
+		descr = s.Description
+	}
+
+	fname := fieldName(name)
+	if fname != name {
+		descr = strings.TrimSpace(descr + "\n\nNamed " + name + " in the OpenAPI document.")
+	}
+
+	writeDescr(b, "\t", descr)
+	b.WriteString("\t" + fname + ": " + typ)
+	if s != nil && s.Deprecated {
+		b.WriteString(" @deprecated")
+	}
+	b.WriteByte('\n')
+}
+
+func (c *openAPIConverter) enum(name string, s *openAPISchema) string {
+	if c.types[name] {
+		return name
+	}
+	c.types[name] = true
+
+	var b strings.Builder
+	writeDescr(&b, "", s.Description)
+	b.WriteString("enum " + name + " {\n")
+	for _, v := range s.Enum {
+		b.WriteString("\t" + enumName(fmt.Sprint(v)) + "\n")
+	}
+	b.WriteString("}\n\n")
+
+	c.decls.WriteString(b.String())
+	return name
+}
+
+// enumName makes an enum value a valid GraphQL name e.g. in-progress
+// becomes IN_PROGRESS.
+//
+func enumName(v string) string {
+	name := strings.ToUpper(fieldName(v))
+	switch name {
+	case "TRUE", "FALSE", "NULL":
+		name = "_" + name
+	}
+	return name
+}
+
+// union converts a oneOf, or anyOf, of objects into a union. Unions can
+// only have object members, so an empty name is returned otherwise.
+//
+func (c *openAPIConverter) union(name string, s *openAPISchema) string {
+	if c.types[name] {
+		return name
+	}
+
+	schemas := s.OneOf
+	if len(schemas) == 0 {
+		schemas = s.AnyOf
+	}
+
+	members := make([]string, 0, len(schemas))
+	for i, m := range schemas {
+		switch {
+		case m.Ref != "":
+			members = append(members, refName(m.Ref))
+		case len(m.Properties.names) > 0:
+			members = append(members, c.object(fmt.Sprintf("%s%d", name, i+1), m))
+		default:
+			return ""
+		}
+	}
+	c.types[name] = true
+
+	var b strings.Builder
+	writeDescr(&b, "", s.Description)
+	b.WriteString("union " + name + " = " + strings.Join(members, " | ") + "\n\n")
+
+	c.decls.WriteString(b.String())
+	return name
+}
+
+// path converts the GET operation of a path into a field of the Query type.
+func (c *openAPIConverter) path(path string, item *openAPIPathItem) {
+	if item == nil || item.Get == nil {
+		return
+	}
+	op := item.Get
+
+	name := op.OperationID
+	if name == "" {
+		name = pathFieldName(path)
+	}
+	name = lowerCamel(fieldName(name))
+
+	descr := op.Summary
+	if op.Description != "" {
+		descr = strings.TrimSpace(descr + "\n\n" + op.Description)
+	}
+	writeDescr(&c.query, "\t", descr)
+
+	c.query.WriteString("\t" + name)
+
+	var args []string
+	for _, p := range append(item.Parameters, op.Parameters...) {
+		if p.Ref != "" {
+			p = c.doc.Components.Parameters[p.Ref[strings.LastIndexByte(p.Ref, '/')+1:]]
+		}
+		if p == nil || p.In != "path" && p.In != "query" {
+			continue
+		}
+
+		typ := c.typeOf(typeName(name)+typeName(p.Name), p.Schema)
+		if (typ == "String" || typ == "Int") && isIDKey(p.Name) {
+			typ = "ID"
+		}
+		if p.Required {
+			typ += "!"
+		}
+		args = append(args, fieldName(p.Name)+": "+typ)
+	}
+	if len(args) > 0 {
+		c.query.WriteString("(" + strings.Join(args, ", ") + ")")
+	}
+
+	c.query.WriteString(": " + c.typeOf(typeName(name)+"Response", responseSchema(op)))
+	if op.Deprecated {
+		c.query.WriteString(" @deprecated")
+	}
+	c.query.WriteByte('\n')
+}
+
+// responseSchema returns the JSON schema of the successful response of an operation.
+func responseSchema(op *openAPIOperation) *openAPISchema {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range append(codes, "default") {
+		if !strings.HasPrefix(code, "2") && code != "default" {
+			continue
+		}
+
+		resp, ok := op.Responses[code]
+		if !ok || resp == nil {
+			continue
+		}
+
+		if content, ok := resp.Content["application/json"]; ok {
+			return content.Schema
+		}
+		for mime, content := range resp.Content {
+			if strings.HasSuffix(mime, "json") {
+				return content.Schema
+			}
+		}
+	}
+	return nil
+}
+
+// pathFieldName names the field of an operation without an id after its
+// path e.g. /users/{id}/posts becomes usersByIdPosts.
+//
+func pathFieldName(path string) string {
+	var b strings.Builder
+	for _, seg := range strings.Split(path, "/") {
+		if seg == "" {
+			continue
+		}
+
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			b.WriteString("By")
+			seg = seg[1 : len(seg)-1]
+		}
+		b.WriteString(typeName(seg))
+	}
+	return b.String()
+}
+
+// schema returns the converted types as SDL.
+func (c *openAPIConverter) schema() string {
+	var b strings.Builder
+
+	names := make([]string, 0, len(c.scalars))
+	for s := range c.scalars {
+		names = append(names, s)
+	}
+	sort.Strings(names)
+	for _, s := range names {
+		b.WriteString("scalar " + s + "\n\n")
+	}
+
+	b.WriteString(c.decls.String())
+	if c.query.Len() > 0 {
+		b.WriteString("type Query {\n")
+		b.WriteString(c.query.String())
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
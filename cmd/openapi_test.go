@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+var openAPISpec = []byte(`openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: Lists pets.
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - $ref: '#/components/parameters/Tag'
+      responses:
+        '200':
+          description: A page of pets.
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      operationId: createPet
+      responses:
+        '201':
+          description: Created.
+  /pets/{petId}/owner:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      deprecated: true
+      responses:
+        '200':
+          description: The owner.
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+components:
+  parameters:
+    Tag:
+      name: tag
+      in: query
+      schema:
+        type: string
+  schemas:
+    Pet:
+      description: A pet.
+      required: [id, name]
+      properties:
+        id:
+          type: string
+        name:
+          type: string
+        status:
+          type: string
+          enum: [available, on-hold]
+        born:
+          type: string
+          format: date
+        tags:
+          type: array
+          items:
+            type: string
+        attrs:
+          type: object
+        kind:
+          $ref: '#/components/schemas/Animal'
+        email:
+          $ref: '#/components/schemas/Email'
+    Dog:
+      allOf:
+        - $ref: '#/components/schemas/Pet'
+        - properties:
+            barks:
+              type: boolean
+    Cat:
+      properties:
+        meows:
+          type: boolean
+          deprecated: true
+    Animal:
+      oneOf:
+        - $ref: '#/components/schemas/Dog'
+        - $ref: '#/components/schemas/Cat'
+    Email:
+      type: string
+      format: email
+`)
+
+func TestConvert_OpenAPI(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/openapi.yaml", openAPISpec, 0644)
+
+	var b bytes.Buffer
+	c := NewCLI(WithFS(fs))
+	cmd := c.newConvertCmd()
+	cmd.SetOut(&b)
+	cmd.SetArgs([]string{"/openapi.yaml"})
+
+	err := cmd.Execute()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := `scalar Date
+
+scalar JSON
+
+type Cat {
+  meows: Boolean @deprecated
+}
+
+type Dog {
+  id: ID!
+  name: String!
+  status: PetStatus
+  born: Date
+  tags: [String!]
+  attrs: JSON
+  kind: Animal
+  email: String
+  barks: Boolean
+}
+
+"A pet."
+type Pet {
+  id: ID!
+  name: String!
+  status: PetStatus
+  born: Date
+  tags: [String!]
+  attrs: JSON
+  kind: Animal
+  email: String
+}
+
+type PetsByPetIdOwnerResponse {
+  name: String
+}
+
+type Query {
+  "Lists pets."
+  listPets(limit: Int, tag: String): [Pet!]
+  petsByPetIdOwner(petId: ID!): PetsByPetIdOwnerResponse @deprecated
+}
+
+union Animal = Dog | Cat
+
+enum PetStatus {
+  AVAILABLE
+  ON_HOLD
+}
+`
+	if b.String() != ex {
+		t.Errorf("expected:\n%s\nbut got:\n%s", ex, b.String())
+	}
+}
+
+func TestConvert_Swagger(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/swagger.json", []byte(`{"swagger": "2.0", "paths": {}}`), 0644)
+
+	c := NewCLI(WithFS(fs))
+	cmd := c.newConvertCmd()
+	cmd.SetArgs([]string{"/swagger.json"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for a Swagger 2.0 document")
+	}
+}
+
+func TestPathFieldName(t *testing.T) {
+	testCases := map[string]string{
+		"/users":            "Users",
+		"/users/{id}":       "UsersById",
+		"/users/{id}/posts": "UsersByIdPosts",
+		"/v1/order-items/":  "V1OrderItems",
+	}
+
+	for path, ex := range testCases {
+		if name := pathFieldName(path); name != ex {
+			t.Errorf("expected %s to be named %s, but got: %s", path, ex, name)
+		}
+	}
+}
@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/internal/oplex"
+	"github.com/gqlc/graphql/ast"
+	"github.com/spf13/afero"
+)
+
+// opWalker walks the operations and fragments of an executable document
+// while tracking the schema type each selection set applies to.
+//
+type opWalker struct {
+	toks []oplex.Token
+	i    int
+
+	// fields maps a type name to its field names and their named types
+	fields map[string]map[string]string
+
+	// roots maps an operation type e.g. query to its root type
+	roots map[string]string
+
+	// typeRef is called for every named type reference
+	typeRef func(tok oplex.Token)
+
+	// field is called for every field selection along with the
+	// type it is selected on, which may be empty if unknown.
+	//
+	field func(parent string, tok oplex.Token)
+}
+
+// newOpWalker returns a walker for documents against the schema described by the given symbols.
+func newOpWalker(syms []*symbol, roots map[string]string) *opWalker {
+	w := &opWalker{
+		fields: make(map[string]map[string]string),
+		roots:  roots,
+	}
+
+	for _, sym := range syms {
+		if sym.kind != fieldSym {
+			continue
+		}
+
+		fields, ok := w.fields[sym.parent]
+		if !ok {
+			fields = make(map[string]string)
+			w.fields[sym.parent] = fields
+		}
+		fields[sym.name] = namedType(sym.typ)
+	}
+	return w
+}
+
+// walk walks the given document tokens.
+func (w *opWalker) walk(toks []oplex.Token) (err error) {
+	w.toks, w.i = toks, 0
+
+	defer func() {
+		if r := recover(); r != nil {
+			oerr, ok := r.(oplex.Error)
+			if !ok {
+				panic(r)
+			}
+			err = oerr
+		}
+	}()
+
+	for w.peek().Kind != oplex.EOF {
+		w.definition()
+	}
+	return
+}
+
+func (w *opWalker) errorf(format string, args ...interface{}) {
+	panic(oplex.Error{Off: w.peek().Off, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (w *opWalker) peek() oplex.Token { return w.toks[w.i] }
+
+func (w *opWalker) next() oplex.Token {
+	tok := w.toks[w.i]
+	if tok.Kind != oplex.EOF {
+		w.i++
+	}
+	return tok
+}
+
+func (w *opWalker) is(val string) bool {
+	tok := w.peek()
+	return tok.Kind == oplex.Punct && tok.Val == val
+}
+
+func (w *opWalker) expect(val string) {
+	if !w.is(val) {
+		w.errorf("expected %q but found %q", val, w.peek().Val)
+	}
+	w.next()
+}
+
+func (w *opWalker) name() oplex.Token {
+	if w.peek().Kind != oplex.Name {
+		w.errorf("expected name but found %q", w.peek().Val)
+	}
+	return w.next()
+}
+
+func (w *opWalker) definition() {
+	if w.is("{") {
+		w.selectionSet(w.roots["query"])
+		return
+	}
+
+	tok := w.name()
+	switch tok.Val {
+	case "query", "mutation", "subscription":
+		if w.peek().Kind == oplex.Name {
+			w.next()
+		}
+		if w.is("(") {
+			w.variableDefinitions()
+		}
+		w.directives()
+		w.selectionSet(w.roots[tok.Val])
+	case "fragment":
+		w.name()
+		if on := w.name(); on.Val != "on" {
+			w.errorf("expected type condition for fragment")
+		}
+		typ := w.namedType()
+		w.directives()
+		w.selectionSet(typ)
+	default:
+		w.i--
+		w.errorf("unexpected %q, only operations and fragments are allowed", tok.Val)
+	}
+}
+
+func (w *opWalker) variableDefinitions() {
+	w.expect("(")
+	for !w.is(")") {
+		w.expect("$")
+		w.name()
+		w.expect(":")
+		w.typ()
+		if w.is("=") {
+			w.next()
+			w.value()
+		}
+		w.directives()
+	}
+	w.next()
+}
+
+func (w *opWalker) typ() {
+	if w.is("[") {
+		w.next()
+		w.typ()
+		w.expect("]")
+	} else {
+		w.namedType()
+	}
+
+	if w.is("!") {
+		w.next()
+	}
+}
+
+func (w *opWalker) namedType() string {
+	tok := w.name()
+	if w.typeRef != nil {
+		w.typeRef(tok)
+	}
+	return tok.Val
+}
+
+func (w *opWalker) directives() {
+	for w.is("@") {
+		w.next()
+		w.name()
+		if w.is("(") {
+			w.arguments()
+		}
+	}
+}
+
+func (w *opWalker) arguments() {
+	w.expect("(")
+	for !w.is(")") {
+		w.name()
+		w.expect(":")
+		w.value()
+	}
+	w.next()
+}
+
+func (w *opWalker) value() {
+	tok := w.next()
+	switch {
+	case tok.Kind == oplex.Value, tok.Kind == oplex.Name:
+	case tok.Val == "$":
+		w.name()
+	case tok.Val == "[":
+		for !w.is("]") {
+			w.value()
+		}
+		w.next()
+	case tok.Val == "{":
+		for !w.is("}") {
+			w.name()
+			w.expect(":")
+			w.value()
+		}
+		w.next()
+	default:
+		w.i--
+		w.errorf("expected value but found %q", tok.Val)
+	}
+}
+
+func (w *opWalker) selectionSet(parent string) {
+	w.expect("{")
+	for !w.is("}") {
+		if w.peek().Kind == oplex.EOF {
+			w.errorf("unterminated selection set")
+		}
+		w.selection(parent)
+	}
+	w.next()
+}
+
+func (w *opWalker) selection(parent string) {
+	if w.is("...") {
+		w.next()
+
+		switch {
+		case w.peek().Kind == oplex.Name && w.peek().Val == "on":
+			w.next()
+			typ := w.namedType()
+			w.directives()
+			w.selectionSet(typ)
+		case w.peek().Kind == oplex.Name:
+			w.next()
+			w.directives()
+		default:
+			w.directives()
+			w.selectionSet(parent)
+		}
+		return
+	}
+
+	field := w.name()
+	if w.is(":") {
+		w.next()
+		field = w.name()
+	}
+	if w.field != nil {
+		w.field(parent, field)
+	}
+
+	if w.is("(") {
+		w.arguments()
+	}
+	w.directives()
+	if w.is("{") {
+		w.selectionSet(w.fields[parent][field.Val])
+	}
+}
+
+// readOperations reads the executable documents given with --ops and
+// validates them against the schema docs, so generators can trust the
+// fields they select exist. Directories are read for every .gql and
+// .graphql file in them, in lexical order.
+//
+func (c *gqlcCmd) readOperations(fs afero.Fs, docs []*ast.Document) ([]gen.OperationDocument, error) {
+	if len(c.cfg.ops) == 0 {
+		return nil, nil
+	}
+
+	w := newOpWalker(collectSymbols(docs), rootTypes(docs))
+	w.field = func(parent string, tok oplex.Token) {
+		fields, ok := w.fields[parent]
+		if !ok || strings.HasPrefix(tok.Val, "__") {
+			return
+		}
+		if _, ok = fields[tok.Val]; !ok {
+			panic(oplex.Error{Off: tok.Off, Msg: fmt.Sprintf("%s has no field %s", parent, tok.Val)})
+		}
+	}
+
+	ops := make([]gen.OperationDocument, 0, len(c.cfg.ops))
+	for _, name := range c.cfg.ops {
+		fname, err := normFilePath(fs, c.cfg.ipaths, name)
+		if err != nil {
+			return nil, err
+		}
+		if fname == "" {
+			return nil, fmt.Errorf("could not resolve file path: %s", name)
+		}
+
+		dir, err := afero.IsDir(fs, fname)
+		if err != nil {
+			return nil, err
+		}
+		if !dir {
+			op, err := readOperation(fs, w, name, fname)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, op)
+			continue
+		}
+
+		err = afero.Walk(fs, fname, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			if ext := filepath.Ext(path); ext != ".gql" && ext != ".graphql" {
+				return nil
+			}
+
+			rel, err := filepath.Rel(fname, path)
+			if err != nil {
+				return err
+			}
+			op, err := readOperation(fs, w, filepath.Join(name, rel), path)
+			if err != nil {
+				return err
+			}
+			ops = append(ops, op)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ops, nil
+}
+
+// readOperation reads an executable document, named name, from the file
+// fname and validates it with w.
+//
+func readOperation(fs afero.Fs, w *opWalker, name, fname string) (gen.OperationDocument, error) {
+	src, err := afero.ReadFile(fs, fname)
+	if err != nil {
+		return gen.OperationDocument{}, err
+	}
+
+	toks, err := oplex.Lex(string(src))
+	if err == nil {
+		err = w.walk(toks)
+	}
+	if oerr, ok := err.(oplex.Error); ok {
+		line, col := lineCol(src, oerr.Off)
+		return gen.OperationDocument{}, fmt.Errorf("gqlc: %s:%d:%d: %s", fname, line, col, oerr.Msg)
+	}
+	return gen.OperationDocument{Name: name, Source: string(src)}, nil
+}
+
+// validateOps checks the paths given with --ops are local executable
+// documents, or directories of them, which are those without an extension.
+//
+func validateOps(paths []string) error {
+	files := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if filepath.Ext(p) == "" && !strings.HasPrefix(p, "http") && !strings.HasPrefix(p, "ws") {
+			continue
+		}
+		files = append(files, p)
+	}
+	return validateLocalFiles(files)
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/internal/oplex"
+	"github.com/gqlc/graphql/ast"
+	"github.com/spf13/afero"
+)
+
+func TestOpWalker_Walk(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Src  string
+		Refs []string
+		Err  bool
+	}{
+		{
+			Name: "Shorthand",
+			Src:  `{ a { b } }`,
+			Refs: []string{"Query.a", "A.b"},
+		},
+		{
+			Name: "Fragments",
+			Src: `mutation M($in: In!) { m(in: $in) { ...on A { b } ... @skip(if: true) { b } } }
+fragment F on A { b }`,
+			Refs: []string{"In", "Mutation.m", "A", "A.b", "A.b", "A", "A.b"},
+		},
+		{
+			Name: "ObjectValue",
+			Src:  `{ a(in: {x: [1, 2], y: {z: ENUM}}) { b } }`,
+			Refs: []string{"Query.a", "A.b"},
+		},
+		{
+			Name: "Definition",
+			Src:  `type Query { a: A }`,
+			Err:  true,
+		},
+		{
+			Name: "Unterminated",
+			Src:  `{ a { b }`,
+			Err:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			var refs []string
+			w := &opWalker{
+				fields: map[string]map[string]string{
+					"Query":    {"a": "A"},
+					"Mutation": {"m": "A"},
+				},
+				roots:   map[string]string{"query": "Query", "mutation": "Mutation"},
+				typeRef: func(tok oplex.Token) { refs = append(refs, tok.Val) },
+				field: func(parent string, tok oplex.Token) {
+					refs = append(refs, parent+"."+tok.Val)
+				},
+			}
+
+			toks, err := oplex.Lex(testCase.Src)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			err = w.walk(toks)
+			if testCase.Err {
+				if err == nil {
+					subT.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if !reflect.DeepEqual(testCase.Refs, refs) {
+				subT.Errorf("expected: %v, but got: %v", testCase.Refs, refs)
+			}
+		})
+	}
+}
+
+func TestRun_Operations(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Src  string
+		Err  string
+	}{
+		{
+			Name: "Valid",
+			Src:  `query Me { me { name __typename } }`,
+		},
+		{
+			Name: "UnknownField",
+			Src: `query Me {
+  me { email }
+}`,
+			Err: "gqlc: /in/ops.graphql:2:8: User has no field email",
+		},
+		{
+			Name: "Definition",
+			Src:  `type Query { me: User }`,
+			Err:  `gqlc: /in/ops.graphql:1:1: unexpected "type", only operations and fragments are allowed`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			fs := afero.NewMemMapFs()
+			afero.WriteFile(fs, "/in/a.gql", []byte("type Query { me: User }\ntype User { name: String }"), 0644)
+			afero.WriteFile(fs, "/in/ops.graphql", []byte(testCase.Src), 0644)
+
+			var ops []gen.OperationDocument
+			g := newMockGenerator(subT)
+			g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, doc *ast.Document, _ interface{}) error {
+				ops = gen.Operations(gen.Context(ctx))
+				return nil
+			})
+
+			cmd := &gqlcCmd{
+				cfg: &gqlcConfig{
+					geners: []generator{{Generator: g, outDir: "/out"}},
+					ipaths: []string{"/in"},
+					ops:    []string{"ops.graphql"},
+					jobs:   1,
+				},
+			}
+
+			err := cmd.run(fs, "a.gql")
+			if testCase.Err != "" {
+				if err == nil || err.Error() != testCase.Err {
+					subT.Errorf("expected error: %s\nbut got: %v", testCase.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			ex := []gen.OperationDocument{{Name: "ops.graphql", Source: testCase.Src}}
+			if !reflect.DeepEqual(ex, ops) {
+				subT.Errorf("expected operations: %v, but got: %v", ex, ops)
+			}
+		})
+	}
+}
+
+func TestRun_OperationsDir(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/in/a.gql", []byte("type Query { me: User }\ntype User { name: String }"), 0644)
+	afero.WriteFile(fs, "/in/ops/me.graphql", []byte("query Me { me { ...UserFields } }"), 0644)
+	afero.WriteFile(fs, "/in/ops/fragments/user.gql", []byte("fragment UserFields on User { name }"), 0644)
+	afero.WriteFile(fs, "/in/ops/README.md", []byte("# Operations"), 0644)
+
+	var ops []gen.OperationDocument
+	g := newMockGenerator(t)
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, doc *ast.Document, _ interface{}) error {
+		ops = gen.Operations(gen.Context(ctx))
+		return nil
+	})
+
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners: []generator{{Generator: g, outDir: "/out"}},
+			ipaths: []string{"/in"},
+			ops:    []string{"ops"},
+			jobs:   1,
+		},
+	}
+
+	err := cmd.run(fs, "a.gql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ex := []gen.OperationDocument{
+		{Name: filepath.Join("ops", "fragments", "user.gql"), Source: "fragment UserFields on User { name }"},
+		{Name: filepath.Join("ops", "me.graphql"), Source: "query Me { me { ...UserFields } }"},
+	}
+	if !reflect.DeepEqual(ex, ops) {
+		t.Errorf("expected operations: %v, but got: %v", ex, ops)
+	}
+}
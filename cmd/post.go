@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// postOpt is the reserved generator option used to attach post-processors
+// to a generator e.g. --js_opt post="prettier --write"
+//
+const postOpt = "post"
+
+// execCommand is swapped out in tests.
+var execCommand = exec.CommandContext
+
+// postProcessor is an external command which is run over
+// every file a generator writes for a document.
+//
+type postProcessor struct {
+	name string
+	args []string
+}
+
+// getPostProcessors extracts any post-processors from a generators
+// options. The option is removed so that it never reaches the generator.
+//
+func getPostProcessors(opts map[string]interface{}) ([]postProcessor, error) {
+	v, ok := opts[postOpt]
+	if !ok {
+		return nil, nil
+	}
+	delete(opts, postOpt)
+
+	var cmds []string
+	switch w := v.(type) {
+	case string:
+		cmds = append(cmds, w)
+	case []string:
+		cmds = w
+	default:
+		return nil, fmt.Errorf("gqlc: %s option must be a command string", postOpt)
+	}
+
+	pps := make([]postProcessor, 0, len(cmds))
+	for _, c := range cmds {
+		if s, err := strconv.Unquote(c); err == nil {
+			c = s
+		}
+
+		words, err := splitCommand(c)
+		if err != nil {
+			return nil, err
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("gqlc: empty %s command", postOpt)
+		}
+
+		pps = append(pps, postProcessor{name: words[0], args: words[1:]})
+	}
+	return pps, nil
+}
+
+// splitCommand splits a command into words like a POSIX shell does, so
+// quoting, or escaping with a backslash, lets words contain spaces e.g.
+// prettier --config "my cfg.json"
+//
+func splitCommand(cmd string) (words []string, err error) {
+	var b strings.Builder
+	var quote byte
+	inWord := false
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+				continue
+			}
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+				continue
+			}
+			// Only these characters are escaped within double quotes
+			if c == '\\' && i+1 < len(cmd) && strings.IndexByte("\"\\$`", cmd[i+1]) >= 0 {
+				i++
+				c = cmd[i]
+			}
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, b.String())
+				b.Reset()
+				inWord = false
+			}
+			continue
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+			continue
+		case c == '\\' && i+1 < len(cmd):
+			i++
+			c = cmd[i]
+		}
+
+		b.WriteByte(c)
+		inWord = true
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("gqlc: unterminated %c quote in %s command: %s", quote, postOpt, cmd)
+	}
+
+	if inWord {
+		words = append(words, b.String())
+	}
+	return
+}
+
+// run executes the post-processor with the given files appended to its arguments.
+func (p postProcessor) run(ctx context.Context, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	args := make([]string, 0, len(p.args)+len(files))
+	args = append(args, p.args...)
+	args = append(args, files...)
+
+	zap.L().Info("running post-processor", zap.String("cmd", p.name), zap.Strings("files", files))
+	out, err := execCommand(ctx, p.name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("post-processor %s failed: %w\n%s", p.name, err, out)
+	}
+	return nil
+}
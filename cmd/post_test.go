@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+)
+
+func TestGetPostProcessors(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Opts map[string]interface{}
+		PPs  []postProcessor
+		Err  bool
+	}{
+		{
+			Name: "None",
+			Opts: map[string]interface{}{"descriptions": true},
+		},
+		{
+			Name: "Single",
+			Opts: map[string]interface{}{"post": `"prettier --write"`},
+			PPs:  []postProcessor{{name: "prettier", args: []string{"--write"}}},
+		},
+		{
+			Name: "Multiple",
+			Opts: map[string]interface{}{"post": []string{`"gofmt -w"`, "goimports"}},
+			PPs: []postProcessor{
+				{name: "gofmt", args: []string{"-w"}},
+				{name: "goimports", args: []string{}},
+			},
+		},
+		{
+			Name: "QuotedArg",
+			Opts: map[string]interface{}{"post": `"prettier --config \"my cfg.json\""`},
+			PPs:  []postProcessor{{name: "prettier", args: []string{"--config", "my cfg.json"}}},
+		},
+		{
+			Name: "SingleQuotedArg",
+			Opts: map[string]interface{}{"post": `"sed -i 's/a b/c/'"`},
+			PPs:  []postProcessor{{name: "sed", args: []string{"-i", "s/a b/c/"}}},
+		},
+		{
+			Name: "EscapedSpace",
+			Opts: map[string]interface{}{"post": `fmt my\ file`},
+			PPs:  []postProcessor{{name: "fmt", args: []string{"my file"}}},
+		},
+		{
+			Name: "UnterminatedQuote",
+			Opts: map[string]interface{}{"post": `"prettier --config \"my cfg.json"`},
+			Err:  true,
+		},
+		{
+			Name: "Empty",
+			Opts: map[string]interface{}{"post": `""`},
+			Err:  true,
+		},
+		{
+			Name: "NotAString",
+			Opts: map[string]interface{}{"post": true},
+			Err:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			pps, err := getPostProcessors(testCase.Opts)
+			if testCase.Err {
+				if err == nil {
+					subT.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if _, exists := testCase.Opts[postOpt]; exists {
+				subT.Errorf("expected %s option to be removed", postOpt)
+			}
+
+			if !reflect.DeepEqual(pps, testCase.PPs) {
+				subT.Errorf("expected: %v, but got: %v", testCase.PPs, pps)
+			}
+		})
+	}
+}
+
+// TestHelperProcess isn't a real test. It's used as a stand-in for post-processor commands.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GQLC_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	args := os.Args
+	for len(args) > 0 {
+		if args[0] == "--" {
+			args = args[1:]
+			break
+		}
+		args = args[1:]
+	}
+
+	if args[0] == "fail" {
+		fmt.Fprint(os.Stderr, "bad file")
+		os.Exit(1)
+	}
+}
+
+func TestRun_PostProcessors(t *testing.T) {
+	var ran [][]string
+	execCommand = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		ran = append(ran, append([]string{name}, args...))
+
+		cs := append([]string{"-test.run=TestHelperProcess", "--", name}, args...)
+		cmd := exec.CommandContext(ctx, os.Args[0], cs...)
+		cmd.Env = append(os.Environ(), "GQLC_WANT_HELPER_PROCESS=1")
+		return cmd
+	}
+	defer func() { execCommand = exec.CommandContext }()
+
+	writeFile := func(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+		if _, exists := opts[postOpt]; exists {
+			return errors.New("post option should not be passed to generator")
+		}
+
+		f, err := gen.Context(ctx).Open(doc.Name + ".txt")
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	}
+
+	t.Run("Success", func(subT *testing.T) {
+		ran = ran[:0]
+
+		g := newMockGenerator(subT)
+		g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(writeFile)
+
+		cmd := &gqlcCmd{
+			cfg: &gqlcConfig{
+				geners: []generator{{
+					Generator: g,
+					name:      "test",
+					opts:      map[string]interface{}{"post": []string{`"fmt -w"`, "lint"}},
+					outDir:    "/out",
+				}},
+			},
+		}
+
+		err := cmd.run(testFs, "/home/graphql/imports/thr.gql")
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ex := [][]string{
+			{"fmt", "-w", "/out/thr.txt"},
+			{"lint", "/out/thr.txt"},
+		}
+		if !reflect.DeepEqual(ex, ran) {
+			subT.Errorf("expected: %v, but got: %v", ex, ran)
+		}
+	})
+
+	t.Run("Failure", func(subT *testing.T) {
+		ran = ran[:0]
+
+		g := newMockGenerator(subT)
+		g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(writeFile)
+
+		cmd := &gqlcCmd{
+			cfg: &gqlcConfig{
+				geners: []generator{{
+					Generator: g,
+					name:      "test",
+					opts:      map[string]interface{}{"post": "fail"},
+					outDir:    "/out",
+				}},
+			},
+		}
+
+		err := cmd.run(testFs, "/home/graphql/imports/thr.gql")
+		if err == nil {
+			subT.Error("expected post-processor error")
+			return
+		}
+
+		gerr, ok := err.(gen.GeneratorError)
+		if !ok {
+			subT.Errorf("expected gen.GeneratorError, but got: %T", err)
+			return
+		}
+		if gerr.GenName != "test" || gerr.DocName != "thr" || !strings.Contains(gerr.Msg, "bad file") {
+			subT.Errorf("unexpected error: %s", gerr)
+		}
+	})
+}
@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/gqlc/compiler"
-	"github.com/gqlc/compiler/spec"
 	"github.com/gqlc/graphql/ast"
 	"github.com/gqlc/graphql/token"
 	"github.com/spf13/afero"
@@ -66,7 +65,7 @@ func (c *gqlcCmd) validatePluginTypes(fs afero.Fs) func(*cobra.Command, []string
 			return err
 		}
 
-		errs := compiler.CheckTypes(docsIR, spec.Validator, compiler.ImportValidator)
+		errs := compiler.CheckTypes(docsIR, typeCheckers...)
 		if len(errs) > 0 {
 			// TODO: Compound errs
 			return nil
@@ -0,0 +1,499 @@
+// protobuf.go contains the conversion of compiled protobuf descriptors to SDL.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/spf13/afero"
+)
+
+// protoDirectives are declared by converted schemas, so that gateways can
+// map fields back to their gRPC methods and proto options aren't lost.
+//
+const protoDirectives = `"""
+proto is the protobuf definition a type, or field, was converted from.
+options are its non-default options, in the protobuf text format.
+"""
+directive @proto(name: String, options: String) on OBJECT | INPUT_OBJECT | ENUM | ENUM_VALUE | FIELD_DEFINITION | INPUT_FIELD_DEFINITION
+
+"grpc is the gRPC method a root field calls."
+directive @grpc(service: String!, method: String!, options: String) on FIELD_DEFINITION
+
+`
+
+// queryPrefixes mark methods without side effects, which become fields of
+// the Query type, when they don't declare their idempotency level.
+//
+var queryPrefixes = []string{"Get", "List", "Search", "Find", "Lookup", "Query", "Count", "Check", "BatchGet"}
+
+// Descriptor field numbers used by source code info paths
+const (
+	fileMessageTag = 4
+	fileEnumTag    = 5
+	fileServiceTag = 6
+	messageField   = 2
+	messageNested  = 3
+	messageEnum    = 4
+	enumValueTag   = 2
+	serviceMethod  = 2
+)
+
+type protoMessage struct {
+	name     string
+	fullName string
+	desc     *descriptor.DescriptorProto
+	path     []int32
+	file     *protoFile
+}
+
+type protoEnum struct {
+	name     string
+	fullName string
+	desc     *descriptor.EnumDescriptorProto
+	path     []int32
+	file     *protoFile
+}
+
+type protoFile struct {
+	desc     *descriptor.FileDescriptorProto
+	comments map[string]string
+}
+
+// comment returns the leading comment of the element at the given path.
+func (f *protoFile) comment(path []int32) string {
+	return f.comments[pathKey(path)]
+}
+
+func (f *protoFile) proto3() bool { return f.desc.GetSyntax() == "proto3" }
+
+func pathKey(path []int32) string {
+	var b strings.Builder
+	for _, p := range path {
+		b.WriteString(strconv.Itoa(int(p)))
+		b.WriteByte('.')
+	}
+	return b.String()
+}
+
+// protoConverter converts messages to types, enums to enums and service
+// methods to fields of the root operation types.
+//
+type protoConverter struct {
+	messages map[string]*protoMessage
+	enums    map[string]*protoEnum
+	order    []interface{}
+
+	inputs  map[string]bool
+	decls   strings.Builder
+	scalars map[string]bool
+
+	query, mutation, subscription strings.Builder
+}
+
+// convertProto converts FileDescriptorSets, e.g. from protoc --descriptor_set_out, to SDL.
+func convertProto(fs afero.Fs, names ...string) (string, error) {
+	c := &protoConverter{
+		messages: make(map[string]*protoMessage),
+		enums:    make(map[string]*protoEnum),
+		inputs:   make(map[string]bool),
+		scalars:  make(map[string]bool),
+	}
+
+	var files []*protoFile
+	for _, name := range names {
+		b, err := afero.ReadFile(fs, name)
+		if err != nil {
+			return "", err
+		}
+
+		var set descriptor.FileDescriptorSet
+		if err = proto.Unmarshal(b, &set); err != nil {
+			return "", fmt.Errorf("gqlc: %s: expected a FileDescriptorSet: %w", name, err)
+		}
+
+		for _, fd := range set.File {
+			f := &protoFile{desc: fd, comments: make(map[string]string)}
+			for _, loc := range fd.GetSourceCodeInfo().GetLocation() {
+				if com := strings.TrimSpace(loc.GetLeadingComments()); com != "" {
+					f.comments[pathKey(loc.Path)] = com
+				}
+			}
+
+			c.addFile(f)
+			files = append(files, f)
+		}
+	}
+
+	// Requests become arguments, so they only need a type if they're
+	// also used as the type of a field.
+	//
+	requests := make(map[string]bool)
+	for _, f := range files {
+		for _, svc := range f.desc.Service {
+			for _, m := range svc.Method {
+				requests[m.GetInputType()] = true
+			}
+		}
+	}
+	for _, m := range c.messages {
+		for _, f := range m.desc.Field {
+			delete(requests, f.GetTypeName())
+		}
+	}
+
+	for _, decl := range c.order {
+		switch d := decl.(type) {
+		case *protoMessage:
+			if requests[d.fullName] {
+				continue
+			}
+			c.message(d)
+		case *protoEnum:
+			c.enum(d)
+		}
+	}
+
+	for _, f := range files {
+		for i, svc := range f.desc.Service {
+			c.service(f, svc, []int32{fileServiceTag, int32(i)})
+		}
+	}
+	return c.schema(), nil
+}
+
+// addFile adds the messages and enums of a file, including nested ones,
+// which are named after the messages they're nested in e.g. User_Address.
+//
+func (c *protoConverter) addFile(f *protoFile) {
+	pkg := ""
+	if f.desc.GetPackage() != "" {
+		pkg = "." + f.desc.GetPackage()
+	}
+
+	for i, e := range f.desc.EnumType {
+		c.addEnum(f, pkg, "", e, []int32{fileEnumTag, int32(i)})
+	}
+	for i, m := range f.desc.MessageType {
+		c.addMessage(f, pkg, "", m, []int32{fileMessageTag, int32(i)})
+	}
+}
+
+func (c *protoConverter) addMessage(f *protoFile, scope, prefix string, m *descriptor.DescriptorProto, path []int32) {
+	pm := &protoMessage{
+		name:     prefix + m.GetName(),
+		fullName: scope + "." + m.GetName(),
+		desc:     m,
+		path:     path,
+		file:     f,
+	}
+	c.messages[pm.fullName] = pm
+	if !m.GetOptions().GetMapEntry() {
+		c.order = append(c.order, pm)
+	}
+
+	for i, e := range m.EnumType {
+		c.addEnum(f, pm.fullName, pm.name+"_", e, append(path[:len(path):len(path)], messageEnum, int32(i)))
+	}
+	for i, n := range m.NestedType {
+		c.addMessage(f, pm.fullName, pm.name+"_", n, append(path[:len(path):len(path)], messageNested, int32(i)))
+	}
+}
+
+func (c *protoConverter) addEnum(f *protoFile, scope, prefix string, e *descriptor.EnumDescriptorProto, path []int32) {
+	pe := &protoEnum{
+		name:     prefix + e.GetName(),
+		fullName: scope + "." + e.GetName(),
+		desc:     e,
+		path:     path,
+		file:     f,
+	}
+	c.enums[pe.fullName] = pe
+	c.order = append(c.order, pe)
+}
+
+// protoOptions returns the non-default options of a descriptor, in the
+// text format, as the options argument of a directive.
+//
+func protoOptions(opts proto.Message) string {
+	if opts == nil || proto.Size(opts) == 0 {
+		return ""
+	}
+	return ", options: " + quote(strings.TrimSpace(proto.CompactTextString(opts)))
+}
+
+func (c *protoConverter) message(m *protoMessage) {
+	c.writeObject(&c.decls, "type", m.name, m)
+}
+
+// input declares the input type of a message, which is used as an argument.
+func (c *protoConverter) input(m *protoMessage) string {
+	name := m.name + "Input"
+	if c.inputs[name] {
+		return name
+	}
+	c.inputs[name] = true
+
+	var b strings.Builder
+	c.writeObject(&b, "input", name, m)
+	c.decls.WriteString(b.String())
+	return name
+}
+
+func (c *protoConverter) writeObject(b *strings.Builder, kind, name string, m *protoMessage) {
+	input := kind == "input"
+
+	writeDescr(b, "", m.file.comment(m.path))
+	b.WriteString(kind + " " + name + " @proto(name: " + quote(strings.TrimPrefix(m.fullName, ".")) + protoOptions(m.desc.GetOptions()) + ") {\n")
+	if len(m.desc.Field) == 0 {
+		// Types must have fields, even if their message doesn't
+		b.WriteString("\t_: Boolean\n")
+	}
+	for i, f := range m.desc.Field {
+		path := append(m.path[:len(m.path):len(m.path)], messageField, int32(i))
+
+		writeDescr(b, "\t", m.file.comment(path))
+		b.WriteString("\t" + jsonName(f) + ": " + c.fieldType(f, m.file.proto3(), input))
+		if opts := protoOptions(f.GetOptions()); opts != "" {
+			b.WriteString(" @proto(name: " + quote(f.GetName()) + opts + ")")
+		}
+		if f.GetOptions().GetDeprecated() && !input {
+			b.WriteString(" @deprecated")
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteString("}\n\n")
+}
+
+// fieldType returns the GraphQL type of a field. Singular proto3 fields
+// always have a value, so they're non-null unless they're messages or
+// part of a oneof, and repeated fields are non-null lists. Fields left
+// out of a request have their default value, so inputs are nullable.
+//
+func (c *protoConverter) fieldType(f *descriptor.FieldDescriptorProto, proto3, input bool) string {
+	if m, ok := c.messages[f.GetTypeName()]; ok && m.desc.GetOptions().GetMapEntry() {
+		c.scalars["JSON"] = true
+		return "JSON"
+	}
+
+	typ, nullable := c.scalarType(f, input)
+	switch f.GetLabel() {
+	case descriptor.FieldDescriptorProto_LABEL_REPEATED:
+		typ = "[" + typ + "!]"
+		if input {
+			return typ
+		}
+		return typ + "!"
+	case descriptor.FieldDescriptorProto_LABEL_REQUIRED:
+		return typ + "!"
+	}
+
+	if input || nullable || !proto3 || f.OneofIndex != nil {
+		return typ
+	}
+	return typ + "!"
+}
+
+// jsonName returns the name of a field in the proto3 JSON mapping.
+func jsonName(f *descriptor.FieldDescriptorProto) string {
+	if f.GetJsonName() != "" {
+		return f.GetJsonName()
+	}
+
+	parts := strings.Split(f.GetName(), "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// wellKnownTypes maps the well-known types to GraphQL types.
+var wellKnownTypes = map[string]string{
+	".google.protobuf.Timestamp":   "DateTime",
+	".google.protobuf.Duration":    "String",
+	".google.protobuf.Struct":      "JSON",
+	".google.protobuf.Value":       "JSON",
+	".google.protobuf.ListValue":   "JSON",
+	".google.protobuf.Any":         "JSON",
+	".google.protobuf.FieldMask":   "String",
+	".google.protobuf.StringValue": "String",
+	".google.protobuf.BytesValue":  "String",
+	".google.protobuf.BoolValue":   "Boolean",
+	".google.protobuf.DoubleValue": "Float",
+	".google.protobuf.FloatValue":  "Float",
+	".google.protobuf.Int32Value":  "Int",
+	".google.protobuf.UInt32Value": "Int",
+	".google.protobuf.Int64Value":  "String",
+	".google.protobuf.UInt64Value": "String",
+	".google.protobuf.Empty":       "Boolean",
+}
+
+// scalarType returns the GraphQL type of a field, without its label, and
+// whether it's nullable. 64-bit integers don't fit in an Int, so they're
+// strings, like in the proto3 JSON mapping.
+//
+func (c *protoConverter) scalarType(f *descriptor.FieldDescriptorProto, input bool) (string, bool) {
+	switch f.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE, descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		return "Float", false
+	case descriptor.FieldDescriptorProto_TYPE_INT32, descriptor.FieldDescriptorProto_TYPE_SINT32, descriptor.FieldDescriptorProto_TYPE_SFIXED32,
+		descriptor.FieldDescriptorProto_TYPE_UINT32, descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		return "Int", false
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		return "Boolean", false
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		if e, ok := c.enums[f.GetTypeName()]; ok {
+			return e.name, false
+		}
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE, descriptor.FieldDescriptorProto_TYPE_GROUP:
+		if typ, ok := wellKnownTypes[f.GetTypeName()]; ok {
+			if typ == "JSON" || typ == "DateTime" {
+				c.scalars[typ] = true
+			}
+			return typ, true
+		}
+
+		if m, ok := c.messages[f.GetTypeName()]; ok {
+			if input {
+				return c.input(m), true
+			}
+			return m.name, true
+		}
+	}
+	return "String", false
+}
+
+func (c *protoConverter) enum(e *protoEnum) {
+	writeDescr(&c.decls, "", e.file.comment(e.path))
+	c.decls.WriteString("enum " + e.name + " @proto(name: " + quote(strings.TrimPrefix(e.fullName, ".")) + protoOptions(e.desc.GetOptions()) + ") {\n")
+	for i, v := range e.desc.Value {
+		path := append(e.path[:len(e.path):len(e.path)], enumValueTag, int32(i))
+
+		writeDescr(&c.decls, "\t", e.file.comment(path))
+		c.decls.WriteString("\t" + v.GetName())
+		if opts := protoOptions(v.GetOptions()); opts != "" {
+			c.decls.WriteString(" @proto(name: " + quote(v.GetName()) + opts + ")")
+		}
+		if v.GetOptions().GetDeprecated() {
+			c.decls.WriteString(" @deprecated")
+		}
+		c.decls.WriteByte('\n')
+	}
+	c.decls.WriteString("}\n\n")
+}
+
+// service adds a field for each method of a service. Methods without side
+// effects are queries, server streaming methods are subscriptions and the
+// rest are mutations. Client streaming methods can't be called from GraphQL.
+//
+func (c *protoConverter) service(f *protoFile, svc *descriptor.ServiceDescriptorProto, path []int32) {
+	svcName := svc.GetName()
+	if f.desc.GetPackage() != "" {
+		svcName = f.desc.GetPackage() + "." + svcName
+	}
+
+	for i, m := range svc.Method {
+		if m.GetClientStreaming() {
+			continue
+		}
+
+		b := &c.mutation
+		switch {
+		case m.GetServerStreaming():
+			b = &c.subscription
+		case isQueryMethod(m):
+			b = &c.query
+		}
+
+		writeDescr(b, "\t", f.comment(append(path[:len(path):len(path)], serviceMethod, int32(i))))
+		b.WriteString("\t" + lowerCamel(m.GetName()))
+
+		// Fields of the request become arguments
+		if req, ok := c.messages[m.GetInputType()]; ok && len(req.desc.Field) > 0 {
+			args := make([]string, 0, len(req.desc.Field))
+			for _, rf := range req.desc.Field {
+				args = append(args, jsonName(rf)+": "+c.fieldType(rf, req.file.proto3(), true))
+			}
+			b.WriteString("(" + strings.Join(args, ", ") + ")")
+		}
+
+		b.WriteString(": ")
+		if typ, ok := wellKnownTypes[m.GetOutputType()]; ok {
+			if typ == "JSON" || typ == "DateTime" {
+				c.scalars[typ] = true
+			}
+			b.WriteString(typ)
+		} else if resp, ok := c.messages[m.GetOutputType()]; ok {
+			b.WriteString(resp.name)
+		} else {
+			c.scalars["JSON"] = true
+			b.WriteString("JSON")
+		}
+
+		b.WriteString(" @grpc(service: " + quote(svcName) + ", method: " + quote(m.GetName()) + protoOptions(m.GetOptions()) + ")")
+		if m.GetOptions().GetDeprecated() {
+			b.WriteString(" @deprecated")
+		}
+		b.WriteByte('\n')
+	}
+}
+
+// isQueryMethod reports whether a method has no side effects, by its
+// idempotency level or, if it doesn't have one, its name.
+//
+func isQueryMethod(m *descriptor.MethodDescriptorProto) bool {
+	switch m.GetOptions().GetIdempotencyLevel() {
+	case descriptor.MethodOptions_NO_SIDE_EFFECTS:
+		return true
+	case descriptor.MethodOptions_IDEMPOTENT:
+		return false
+	}
+
+	for _, prefix := range queryPrefixes {
+		if strings.HasPrefix(m.GetName(), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// schema returns the converted types as SDL.
+func (c *protoConverter) schema() string {
+	var b strings.Builder
+	b.WriteString(protoDirectives)
+
+	names := make([]string, 0, len(c.scalars))
+	for s := range c.scalars {
+		names = append(names, s)
+	}
+	sort.Strings(names)
+	for _, s := range names {
+		b.WriteString("scalar " + s + "\n\n")
+	}
+
+	b.WriteString(c.decls.String())
+	for _, root := range []struct {
+		name   string
+		fields *strings.Builder
+	}{
+		{name: "Query", fields: &c.query},
+		{name: "Mutation", fields: &c.mutation},
+		{name: "Subscription", fields: &c.subscription},
+	} {
+		if root.fields.Len() == 0 {
+			continue
+		}
+
+		b.WriteString("type " + root.name + " {\n")
+		b.WriteString(root.fields.String())
+		b.WriteString("}\n\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/spf13/afero"
+)
+
+func testDescriptorSet() *descriptor.FileDescriptorSet {
+	field := func(name string, num int32, label descriptor.FieldDescriptorProto_Label, typ descriptor.FieldDescriptorProto_Type, typeName string) *descriptor.FieldDescriptorProto {
+		f := &descriptor.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(num),
+			Label:  label.Enum(),
+			Type:   typ.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	optional, repeated := descriptor.FieldDescriptorProto_LABEL_OPTIONAL, descriptor.FieldDescriptorProto_LABEL_REPEATED
+
+	deprecated := field("nick_name", 5, optional, descriptor.FieldDescriptorProto_TYPE_STRING, "")
+	deprecated.Options = &descriptor.FieldOptions{Deprecated: proto.Bool(true)}
+
+	return &descriptor.FileDescriptorSet{File: []*descriptor.FileDescriptorProto{
+		{
+			Name:       proto.String("users.proto"),
+			Package:    proto.String("example.users"),
+			Syntax:     proto.String("proto3"),
+			Dependency: []string{"google/protobuf/timestamp.proto"},
+			MessageType: []*descriptor.DescriptorProto{
+				{
+					Name: proto.String("User"),
+					Field: []*descriptor.FieldDescriptorProto{
+						field("id", 1, optional, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+						field("age", 2, optional, descriptor.FieldDescriptorProto_TYPE_INT32, ""),
+						field("created_at", 3, optional, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+						field("role", 4, optional, descriptor.FieldDescriptorProto_TYPE_ENUM, ".example.users.User.Role"),
+						deprecated,
+						field("friends", 6, repeated, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".example.users.User"),
+						field("balance", 7, optional, descriptor.FieldDescriptorProto_TYPE_INT64, ""),
+						field("labels", 8, repeated, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".example.users.User.LabelsEntry"),
+					},
+					NestedType: []*descriptor.DescriptorProto{
+						{
+							Name: proto.String("LabelsEntry"),
+							Field: []*descriptor.FieldDescriptorProto{
+								field("key", 1, optional, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+								field("value", 2, optional, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+							},
+							Options: &descriptor.MessageOptions{MapEntry: proto.Bool(true)},
+						},
+					},
+					EnumType: []*descriptor.EnumDescriptorProto{
+						{
+							Name: proto.String("Role"),
+							Value: []*descriptor.EnumValueDescriptorProto{
+								{Name: proto.String("ROLE_UNSPECIFIED"), Number: proto.Int32(0)},
+								{Name: proto.String("ADMIN"), Number: proto.Int32(1)},
+							},
+						},
+					},
+				},
+				{
+					Name: proto.String("GetUserRequest"),
+					Field: []*descriptor.FieldDescriptorProto{
+						field("user_id", 1, optional, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					},
+				},
+				{
+					Name: proto.String("UpdateUserRequest"),
+					Field: []*descriptor.FieldDescriptorProto{
+						field("user", 1, optional, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".example.users.User"),
+					},
+				},
+			},
+			Service: []*descriptor.ServiceDescriptorProto{
+				{
+					Name: proto.String("Users"),
+					Method: []*descriptor.MethodDescriptorProto{
+						{
+							Name:       proto.String("GetUser"),
+							InputType:  proto.String(".example.users.GetUserRequest"),
+							OutputType: proto.String(".example.users.User"),
+						},
+						{
+							Name:       proto.String("UpdateUser"),
+							InputType:  proto.String(".example.users.UpdateUserRequest"),
+							OutputType: proto.String(".example.users.User"),
+							Options:    &descriptor.MethodOptions{Deprecated: proto.Bool(true)},
+						},
+						{
+							Name:            proto.String("WatchUser"),
+							InputType:       proto.String(".example.users.GetUserRequest"),
+							OutputType:      proto.String(".example.users.User"),
+							ServerStreaming: proto.Bool(true),
+						},
+						{
+							Name:            proto.String("Upload"),
+							InputType:       proto.String(".example.users.User"),
+							OutputType:      proto.String(".google.protobuf.Empty"),
+							ClientStreaming: proto.Bool(true),
+						},
+					},
+				},
+			},
+			SourceCodeInfo: &descriptor.SourceCodeInfo{
+				Location: []*descriptor.SourceCodeInfo_Location{
+					{Path: []int32{4, 0}, LeadingComments: proto.String(" User is a member.\n")},
+					{Path: []int32{4, 0, 2, 1}, LeadingComments: proto.String(" age in years.\n")},
+					{Path: []int32{6, 0, 2, 0}, LeadingComments: proto.String(" GetUser gets a user.\n")},
+				},
+			},
+		},
+	}}
+}
+
+func TestConvert_Proto(t *testing.T) {
+	b, err := proto.Marshal(testDescriptorSet())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/users.pb", b, 0644)
+
+	var out bytes.Buffer
+	c := NewCLI(WithFS(fs))
+	cmd := c.newConvertCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"/users.pb"})
+
+	err = cmd.Execute()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := `scalar DateTime
+
+scalar JSON
+
+type Mutation {
+  updateUser(
+    user: UserInput
+  ): User @grpc(service: "example.users.Users", method: "UpdateUser", options: "deprecated:true") @deprecated
+}
+
+type Query {
+  "GetUser gets a user."
+  getUser(
+    userId: String
+  ): User @grpc(service: "example.users.Users", method: "GetUser")
+}
+
+type Subscription {
+  watchUser(
+    userId: String
+  ): User @grpc(service: "example.users.Users", method: "WatchUser")
+}
+
+"User is a member."
+type User @proto(name: "example.users.User") {
+  id: String!
+  "age in years."
+  age: Int!
+  createdAt: DateTime
+  role: User_Role!
+  nickName: String! @proto(name: "nick_name", options: "deprecated:true") @deprecated
+  friends: [User!]!
+  balance: String!
+  labels: JSON
+}
+
+enum User_Role @proto(name: "example.users.User.Role") {
+  ROLE_UNSPECIFIED
+  ADMIN
+}
+
+"User is a member."
+input UserInput @proto(name: "example.users.User") {
+  id: String
+  "age in years."
+  age: Int
+  createdAt: DateTime
+  role: User_Role
+  nickName: String @proto(name: "nick_name", options: "deprecated:true")
+  friends: [UserInput!]
+  balance: String
+  labels: JSON
+}
+
+"grpc is the gRPC method a root field calls."
+directive @grpc(
+  service: String!
+  method: String!
+  options: String
+) on FIELD_DEFINITION
+
+"""
+proto is the protobuf definition a type, or field, was converted from.
+options are its non-default options, in the protobuf text format.
+"""
+directive @proto(
+  name: String
+  options: String
+) on OBJECT | INPUT_OBJECT | ENUM | ENUM_VALUE | FIELD_DEFINITION | INPUT_FIELD_DEFINITION
+`
+	if out.String() != ex {
+		t.Errorf("expected:\n%s\nbut got:\n%s", ex, out.String())
+	}
+}
+
+func TestConvert_InvalidProto(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/users.pb", []byte("not a descriptor"), 0644)
+
+	c := NewCLI(WithFS(fs))
+	cmd := c.newConvertCmd()
+	cmd.SetArgs([]string{"/users.pb"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error")
+	}
+}
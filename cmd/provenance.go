@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/plugin"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// The types of a provenance record, which is an in-toto statement whose
+// predicate is SLSA provenance.
+//
+const (
+	statementType       = "https://in-toto.io/Statement/v1"
+	provenancePredicate = "https://slsa.dev/provenance/v1"
+	provenanceBuildType = "https://github.com/gqlc/gqlc/provenance/v1"
+	provenanceBuilderID = "https://github.com/gqlc/gqlc"
+)
+
+// What a dependency of the generated code is.
+const (
+	dependencySchema     = "schema"
+	dependencyOperations = "operations"
+	dependencyConfig     = "config"
+	dependencyPlugin     = "plugin"
+)
+
+// provenance records what the generated code was generated from, so it
+// can be audited, e.g. as part of a software supply chain.
+//
+type provenance struct {
+	// file is where the record is written
+	file string
+
+	// config is the config file codemods were loaded from, if any
+	config string
+}
+
+func initProvenance(fs afero.Fs, p **provenance) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		file, err := cmd.Flags().GetString("provenance")
+		if err != nil || file == "" {
+			return err
+		}
+
+		config, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return err
+		}
+		if !cmd.Flags().Changed("config") {
+			exists, err := afero.Exists(fs, config)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				config = ""
+			}
+		}
+
+		*p = &provenance{file: file, config: config}
+		return nil
+	}
+}
+
+// outputLog records the files written by a generator, which documents are
+// generated into concurrently.
+//
+type outputLog struct {
+	mu    sync.Mutex
+	files []string
+}
+
+func (l *outputLog) add(fname string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.files = append(l.files, fname)
+}
+
+type provenanceStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []resourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     provenanceRecord     `json:"predicate"`
+}
+
+type provenanceRecord struct {
+	BuildDefinition buildDefinition `json:"buildDefinition"`
+	RunDetails      runDetails      `json:"runDetails"`
+}
+
+type buildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   externalParameters   `json:"externalParameters"`
+	ResolvedDependencies []resourceDescriptor `json:"resolvedDependencies"`
+}
+
+type externalParameters struct {
+	Inputs     []string              `json:"inputs"`
+	Generators []provenanceGenerator `json:"generators"`
+}
+
+type provenanceGenerator struct {
+	Name    string                 `json:"name"`
+	OutDir  string                 `json:"outDir"`
+	Options map[string]interface{} `json:"options"`
+}
+
+type runDetails struct {
+	Builder builder `json:"builder"`
+}
+
+type builder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version"`
+}
+
+type resourceDescriptor struct {
+	Name        string            `json:"name,omitempty"`
+	URI         string            `json:"uri,omitempty"`
+	Digest      map[string]string `json:"digest"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// writeProvenance writes the provenance of the generated files: which
+// generator wrote each, and the digests of the schema documents, operation
+// documents, config file and plugins they were generated from, along with
+// the options each generator was given.
+//
+func (c *gqlcCmd) writeProvenance(fs, outFs afero.Fs, args []string, ops []gen.OperationDocument, outputs []*outputLog) error {
+	stmt := provenanceStatement{
+		Type:          statementType,
+		Subject:       []resourceDescriptor{},
+		PredicateType: provenancePredicate,
+		Predicate: provenanceRecord{
+			BuildDefinition: buildDefinition{
+				BuildType: provenanceBuildType,
+				ExternalParameters: externalParameters{
+					Inputs:     args,
+					Generators: make([]provenanceGenerator, len(c.cfg.geners)),
+				},
+				ResolvedDependencies: []resourceDescriptor{},
+			},
+			RunDetails: runDetails{
+				Builder: builder{
+					ID:      provenanceBuilderID,
+					Version: map[string]string{"gqlc": version, "go": runtime.Version()},
+				},
+			},
+		},
+	}
+	def := &stmt.Predicate.BuildDefinition
+
+	for i, g := range c.cfg.geners {
+		opts := make(map[string]interface{}, len(g.opts))
+		for k, v := range g.opts {
+			opts[k] = flagValue(v)
+		}
+		def.ExternalParameters.Generators[i] = provenanceGenerator{Name: g.name, OutDir: relPath(g.outDir), Options: opts}
+
+		files := outputs[i].files
+		sort.Strings(files)
+		for j, fname := range files {
+			if j > 0 && files[j-1] == fname {
+				continue
+			}
+
+			digest, err := hashOf(outFs, fname)
+			if err != nil {
+				return err
+			}
+			stmt.Subject = append(stmt.Subject, resourceDescriptor{
+				Name:        relPath(fname),
+				Digest:      map[string]string{"sha256": digest},
+				Annotations: map[string]string{"generator": g.name},
+			})
+		}
+
+		p, ok := g.Generator.(*plugin.Generator)
+		if !ok {
+			continue
+		}
+		path, err := exec.LookPath(p.Prefix + p.Name)
+		if err != nil {
+			return err
+		}
+		digest, err := hashOf(afero.NewOsFs(), path)
+		if err != nil {
+			return err
+		}
+		def.ResolvedDependencies = append(def.ResolvedDependencies, dependency(p.Prefix+p.Name, path, digest, dependencyPlugin))
+	}
+
+	paths := make([]string, 0, len(c.digests))
+	for path := range c.digests {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		def.ResolvedDependencies = append(def.ResolvedDependencies, dependency("", path, c.digests[path], dependencySchema))
+	}
+
+	for _, op := range ops {
+		sum := sha256.Sum256([]byte(op.Source))
+		def.ResolvedDependencies = append(def.ResolvedDependencies, dependency("", op.Name, hex.EncodeToString(sum[:]), dependencyOperations))
+	}
+
+	if config := c.cfg.provenance.config; config != "" {
+		digest, err := hashOf(fs, config)
+		if err != nil {
+			return err
+		}
+		def.ResolvedDependencies = append(def.ResolvedDependencies, dependency("", config, digest, dependencyConfig))
+	}
+
+	b, err := json.MarshalIndent(stmt, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	file := c.cfg.provenance.file
+	if dir := filepath.Dir(file); dir != "." {
+		err = fs.MkdirAll(dir, 0755)
+		if err != nil {
+			return err
+		}
+	}
+	return afero.WriteFile(fs, file, b, 0644)
+}
+
+// relPath returns a path relative to the working directory, if it's in it,
+// so records don't depend on where the code was generated.
+//
+func relPath(path string) string {
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(path)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func dependency(name, uri, digest, kind string) resourceDescriptor {
+	return resourceDescriptor{
+		Name:        name,
+		URI:         filepath.ToSlash(uri),
+		Digest:      map[string]string{"sha256": digest},
+		Annotations: map[string]string{"kind": kind},
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/spf13/afero"
+)
+
+func TestRun_Provenance(t *testing.T) {
+	schema := "type Query { me: User }\ntype User { name: String }"
+	ops := "query Me { me { name } }"
+
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/in/a.gql", []byte(schema), 0644)
+	afero.WriteFile(fs, "/in/ops.graphql", []byte(ops), 0644)
+	afero.WriteFile(fs, "/in/gqlc.yaml", []byte("codemods: []\n"), 0644)
+
+	g := newMockGenerator(t)
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, doc *ast.Document, _ interface{}) error {
+		f, err := gen.Context(ctx).Open("a.txt")
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = f.Write([]byte("generated"))
+		return err
+	})
+
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners:     []generator{{Generator: g, name: "txt", outDir: "/out", opts: map[string]interface{}{"style": `"short"`}}},
+			ipaths:     []string{"/in"},
+			ops:        []string{"ops.graphql"},
+			jobs:       1,
+			provenance: &provenance{file: "/out/provenance.json", config: "/in/gqlc.yaml"},
+		},
+	}
+
+	err := cmd.run(fs, "a.gql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := afero.ReadFile(fs, "/out/provenance.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stmt provenanceStatement
+	err = json.Unmarshal(b, &stmt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := func(s string) map[string]string {
+		sum := sha256.Sum256([]byte(s))
+		return map[string]string{"sha256": hex.EncodeToString(sum[:])}
+	}
+
+	if stmt.Type != statementType || stmt.PredicateType != provenancePredicate {
+		t.Errorf("expected an in-toto statement of SLSA provenance, but got: %s", b)
+	}
+
+	ex := []resourceDescriptor{{Name: "/out/a.txt", Digest: digest("generated"), Annotations: map[string]string{"generator": "txt"}}}
+	exJSON, _ := json.Marshal(ex)
+	subJSON, _ := json.Marshal(stmt.Subject)
+	if string(exJSON) != string(subJSON) {
+		t.Errorf("expected subject: %s, but got: %s", exJSON, subJSON)
+	}
+
+	def := stmt.Predicate.BuildDefinition
+	gens, _ := json.Marshal(def.ExternalParameters.Generators)
+	if string(gens) != `[{"name":"txt","outDir":"/out","options":{"style":"short"}}]` {
+		t.Errorf("unexpected generators: %s", gens)
+	}
+
+	ex = []resourceDescriptor{
+		dependency("", "/in/a.gql", digest(schema)["sha256"], dependencySchema),
+		dependency("", "ops.graphql", digest(ops)["sha256"], dependencyOperations),
+		dependency("", "/in/gqlc.yaml", digest("codemods: []\n")["sha256"], dependencyConfig),
+	}
+	exJSON, _ = json.Marshal(ex)
+	depJSON, _ := json.Marshal(def.ResolvedDependencies)
+	if string(exJSON) != string(depJSON) {
+		t.Errorf("expected dependencies: %s, but got: %s", exJSON, depJSON)
+	}
+}
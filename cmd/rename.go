@@ -0,0 +1,598 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gqlc/gqlc/internal/oplex"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func (c *CommandLine) newRenameCmd() *baseCmd {
+	r := new(renamer)
+
+	cmd := &cobra.Command{
+		Use:   "rename old -> new files...",
+		Short: "Rename a type or field across schema and operation documents",
+		Long: `rename renames a type or field, given by its schema coordinate, and
+rewrites every reference to it in the given schema files along with any
+operation documents supplied with --ops. Only the renamed identifiers are
+touched, so formatting and comments are preserved.
+
+Renaming an interface field also renames it on every implementation. With
+--alias, the old field is kept alongside the new one and marked as
+deprecated, which allows clients to migrate gradually.`,
+		Example: `gqlc rename User.email -> User.primaryEmail --ops queries.graphql schema.gql`,
+		Args: func(cmd *cobra.Command, args []string) (err error) {
+			arrow, _ := cmd.Flags().GetBool("arrow")
+
+			r.old, r.new, args, err = parseRenameArgs(args, arrow)
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				return fmt.Errorf("gqlc: no schema files given")
+			}
+			r.files = args
+
+			return validateLocalFiles(r.files)
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) (err error) {
+			r.ops, err = cmd.Flags().GetStringSlice("ops")
+			if err != nil {
+				return
+			}
+			r.alias, err = cmd.Flags().GetBool("alias")
+			if err != nil {
+				return
+			}
+			r.dryRun, err = cmd.Flags().GetBool("dry_run")
+			if err != nil {
+				return
+			}
+
+			return validateLocalFiles(r.ops)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return r.run(c.fs, cmd)
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringSlice("ops", nil, "Operation documents to rewrite. May be specified multiple times.")
+	cmd.Flags().Bool("alias", false, "Keep a renamed field as a deprecated alias of the new field.")
+	cmd.Flags().Bool("dry_run", false, "Print the rewritten documents instead of writing them.")
+
+	// A standalone -> would otherwise be rejected as an unknown shorthand flag
+	cmd.Flags().BoolP("arrow", ">", false, "")
+	cmd.Flags().MarkHidden("arrow")
+
+	return &baseCmd{Command: cmd}
+}
+
+// parseRenameArgs splits the rename arguments into the old and new
+// coordinates and the remaining files. The arrow is either joined
+// with the coordinates or, when given as a separate argument, has
+// already been consumed by flag parsing.
+//
+func parseRenameArgs(args []string, arrow bool) (old, new string, files []string, err error) {
+	switch {
+	case len(args) > 0 && strings.Contains(args[0], "->"):
+		parts := strings.SplitN(args[0], "->", 2)
+		old, new, files = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), args[1:]
+	case arrow && len(args) > 1:
+		old, new, files = args[0], args[1], args[2:]
+	}
+
+	if old == "" || new == "" {
+		err = fmt.Errorf("gqlc: expected rename of the form: old -> new")
+	}
+	return
+}
+
+func validateLocalFiles(filenames []string) error {
+	for _, filename := range filenames {
+		if strings.HasPrefix(filename, "http") || strings.HasPrefix(filename, "ws") {
+			return fmt.Errorf("gqlc: remote files can not be rewritten: %s", filename)
+		}
+
+		ext := filepath.Ext(filename)
+		if ext != ".gql" && ext != ".graphql" {
+			return fmt.Errorf("gqlc: invalid file extension: %s", filename)
+		}
+	}
+	return nil
+}
+
+// textEdit replaces the source text between two offsets.
+type textEdit struct {
+	start, end int
+	text       string
+}
+
+// applyEdits applies non-overlapping edits to the given source.
+func applyEdits(src []byte, edits []textEdit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var b bytes.Buffer
+	b.Grow(len(src))
+
+	last := 0
+	for _, e := range edits {
+		b.Write(src[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+	b.Write(src[last:])
+	return b.Bytes()
+}
+
+// renamer renames a type or field across a set of documents.
+type renamer struct {
+	old, new string
+	files    []string
+	ops      []string
+	alias    bool
+	dryRun   bool
+
+	// parent is set when renaming a field
+	parent string
+
+	// parents contains the types whose field is being renamed
+	parents map[string]bool
+
+	dset  *token.DocSet
+	srcs  map[string][]byte
+	edits map[string][]textEdit
+}
+
+func (r *renamer) run(fs afero.Fs, cmd *cobra.Command) error {
+	r.dset = token.NewDocSet()
+	r.srcs = make(map[string][]byte, len(r.files)+len(r.ops))
+	r.edits = make(map[string][]textEdit, len(r.files)+len(r.ops))
+
+	docs := make([]*ast.Document, 0, len(r.files))
+	for _, filename := range r.files {
+		src, err := afero.ReadFile(fs, filename)
+		if err != nil {
+			return err
+		}
+		r.srcs[filename] = src
+
+		doc, err := parser.ParseDoc(r.dset, filename, bytes.NewReader(src), parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+	}
+
+	syms := collectSymbols(docs)
+	err := r.resolve(syms, docs)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		r.renameSchema(doc)
+	}
+
+	err = r.renameOperations(fs, syms, docs)
+	if err != nil {
+		return err
+	}
+
+	return r.write(fs, cmd, append(r.files, r.ops...))
+}
+
+// resolve validates the old and new coordinates against the schema.
+func (r *renamer) resolve(syms []*symbol, docs []*ast.Document) error {
+	oldType, oldField := splitCoord(r.old)
+	newType, newField := splitCoord(r.new)
+	if !isName(oldType) || (oldField != "" && !isName(oldField)) {
+		return fmt.Errorf("gqlc: invalid schema coordinate: %s", r.old)
+	}
+
+	// Allow the new name of a field to be given without its type
+	if oldField != "" && newField == "" {
+		newType, newField = oldType, newType
+	}
+	if oldField != "" && newType != oldType {
+		return fmt.Errorf("gqlc: fields can not be moved between types: %s -> %s", r.old, r.new)
+	}
+	if (oldField == "") != (newField == "") || !isName(newType) || (newField != "" && !isName(newField)) {
+		return fmt.Errorf("gqlc: invalid schema coordinate: %s", r.new)
+	}
+
+	var typ *symbol
+	for _, sym := range syms {
+		if sym.kind == typeSym && sym.name == oldType {
+			typ = sym
+		}
+		if oldField == "" && sym.kind == typeSym && sym.name == newType {
+			return fmt.Errorf("gqlc: type already exists: %s", newType)
+		}
+	}
+	if typ == nil {
+		return fmt.Errorf("gqlc: unknown type: %s", oldType)
+	}
+
+	if oldField == "" {
+		if r.alias {
+			return fmt.Errorf("gqlc: only fields can be aliased")
+		}
+
+		r.old, r.new = oldType, newType
+		return nil
+	}
+
+	switch typ.tok {
+	case token.Token_TYPE, token.Token_INTERFACE:
+	case token.Token_INPUT:
+		if r.alias {
+			return fmt.Errorf("gqlc: input fields can not be aliased")
+		}
+	default:
+		return fmt.Errorf("gqlc: %s has no fields to rename", oldType)
+	}
+
+	r.parent, r.old, r.new = oldType, oldField, newField
+	r.parents = map[string]bool{oldType: true}
+	if typ.tok == token.Token_INTERFACE {
+		for _, impl := range implementations(docs, oldType) {
+			r.parents[impl] = true
+		}
+	}
+
+	var found bool
+	for _, sym := range syms {
+		if (sym.kind != fieldSym && sym.kind != inputFieldSym) || !r.parents[sym.parent] {
+			continue
+		}
+
+		switch sym.name {
+		case oldField:
+			found = found || sym.parent == oldType
+		case newField:
+			return fmt.Errorf("gqlc: field already exists: %s.%s", sym.parent, newField)
+		}
+	}
+	if !found {
+		return fmt.Errorf("gqlc: unknown field: %s.%s", oldType, oldField)
+	}
+	return nil
+}
+
+func splitCoord(coord string) (typ, field string) {
+	i := strings.IndexByte(coord, '.')
+	if i < 0 {
+		return coord, ""
+	}
+	return coord[:i], coord[i+1:]
+}
+
+func isName(s string) bool {
+	if s == "" || oplex.IsDigit(s[0]) {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '_' && !oplex.IsLetter(s[i]) && !oplex.IsDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// implementations returns the names of all types which implement the given interface.
+func implementations(docs []*ast.Document, iface string) (impls []string) {
+	for _, doc := range docs {
+		for _, d := range doc.Types {
+			ts := declSpec(d)
+			if ts == nil {
+				continue
+			}
+
+			obj, ok := ts.Type.(*ast.TypeSpec_Object)
+			if !ok {
+				continue
+			}
+
+			for _, id := range obj.Object.Interfaces {
+				if id.Name == iface {
+					impls = append(impls, ts.Name.Name)
+				}
+			}
+		}
+	}
+	return
+}
+
+// declSpec returns the type spec of a type declaration or extension.
+func declSpec(d *ast.TypeDecl) *ast.TypeSpec {
+	switch v := d.Spec.(type) {
+	case *ast.TypeDecl_TypeSpec:
+		return v.TypeSpec
+	case *ast.TypeDecl_TypeExtSpec:
+		return v.TypeExtSpec.Type
+	}
+	return nil
+}
+
+func (r *renamer) edit(pos int64, text string) {
+	p := r.dset.Position(token.Pos(pos))
+	r.edits[p.Filename] = append(r.edits[p.Filename], textEdit{start: p.Offset, end: p.Offset + len(r.old), text: text})
+}
+
+func (r *renamer) renameIdent(id *ast.Ident) {
+	if id != nil && id.Name == r.old {
+		r.edit(id.NamePos, r.new)
+	}
+}
+
+func (r *renamer) renameSchema(doc *ast.Document) {
+	for _, d := range doc.Types {
+		ts := declSpec(d)
+		if ts == nil {
+			continue
+		}
+
+		if r.parent != "" {
+			if ts.Name != nil && r.parents[ts.Name.Name] {
+				r.renameFields(ts)
+			}
+			continue
+		}
+
+		r.renameIdent(ts.Name)
+		switch v := ts.Type.(type) {
+		case *ast.TypeSpec_Schema:
+			if v.Schema.RootOps != nil {
+				r.renameFieldTypes(v.Schema.RootOps.List)
+			}
+		case *ast.TypeSpec_Object:
+			for _, id := range v.Object.Interfaces {
+				r.renameIdent(id)
+			}
+			if v.Object.Fields != nil {
+				r.renameFieldTypes(v.Object.Fields.List)
+			}
+		case *ast.TypeSpec_Interface:
+			if v.Interface.Fields != nil {
+				r.renameFieldTypes(v.Interface.Fields.List)
+			}
+		case *ast.TypeSpec_Union:
+			for _, id := range v.Union.Members {
+				r.renameIdent(id)
+			}
+		case *ast.TypeSpec_Input:
+			if v.Input.Fields != nil {
+				r.renameArgTypes(v.Input.Fields.List)
+			}
+		case *ast.TypeSpec_Directive:
+			if v.Directive.Args != nil {
+				r.renameArgTypes(v.Directive.Args.List)
+			}
+		}
+	}
+}
+
+func (r *renamer) renameFieldTypes(fields []*ast.Field) {
+	for _, f := range fields {
+		r.renameIdent(namedIdent(fieldType(f)))
+		if f.Args != nil {
+			r.renameArgTypes(f.Args.List)
+		}
+	}
+}
+
+func (r *renamer) renameArgTypes(args []*ast.InputValue) {
+	for _, a := range args {
+		r.renameIdent(namedIdent(inputValueType(a)))
+	}
+}
+
+func (r *renamer) renameFields(ts *ast.TypeSpec) {
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Object:
+		r.renameFieldList(v.Object.Fields)
+	case *ast.TypeSpec_Interface:
+		r.renameFieldList(v.Interface.Fields)
+	case *ast.TypeSpec_Input:
+		if v.Input.Fields == nil {
+			break
+		}
+
+		for _, f := range v.Input.Fields.List {
+			r.renameIdent(f.Name)
+		}
+	}
+}
+
+func (r *renamer) renameFieldList(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+
+	for i, f := range fields.List {
+		if f.Name.Name != r.old {
+			continue
+		}
+		r.renameIdent(f.Name)
+
+		if r.alias {
+			r.insertAlias(fields, i)
+		}
+	}
+}
+
+// insertAlias copies the source of a field, with its old name, after
+// the field and marks the copy as deprecated.
+//
+func (r *renamer) insertAlias(fields *ast.FieldList, i int) {
+	f := fields.List[i]
+	start := r.dset.Position(token.Pos(f.Name.NamePos))
+	src := r.srcs[start.Filename]
+
+	end := r.dset.Position(token.Pos(fields.Closing)).Offset
+	if i+1 < len(fields.List) {
+		next := fields.List[i+1]
+		end = r.dset.Position(token.Pos(next.Name.NamePos)).Offset
+		if next.Doc != nil && len(next.Doc.List) > 0 {
+			end = r.dset.Position(token.Pos(next.Doc.List[0].Char)).Offset
+		}
+	}
+	end = start.Offset + len(trimField(string(src[start.Offset:end])))
+	alias := string(src[start.Offset:end])
+
+	var deprecated bool
+	for _, d := range f.Directives {
+		deprecated = deprecated || d.Name == "deprecated"
+	}
+	if !deprecated {
+		alias += fmt.Sprintf(` @deprecated(reason: "Use %s instead.")`, "`"+r.new+"`")
+	}
+
+	lineStart := bytes.LastIndexAny(src[:start.Offset], "\r\n") + 1
+	indent := string(src[lineStart:start.Offset])
+	if strings.TrimSpace(indent) == "" {
+		alias = "\n" + indent + alias
+
+		// Keep any trailing comment on the line with the renamed field
+		eol := bytes.IndexAny(src[end:], "\r\n")
+		if eol < 0 {
+			eol = len(src) - end
+		}
+		if rest := strings.TrimLeft(string(src[end:end+eol]), " \t,"); rest == "" || rest[0] == '#' {
+			end += eol
+		}
+	} else {
+		alias = " " + alias
+	}
+
+	r.edits[start.Filename] = append(r.edits[start.Filename], textEdit{start: end, end: end, text: alias})
+}
+
+// trimField trims any trailing comments, commas and whitespace
+// from the source of a field definition.
+//
+func trimField(src string) string {
+	for i := 0; i < len(src); i++ {
+		switch src[i] {
+		case '"':
+			end, ok := oplex.ScanString(src, i)
+			if !ok {
+				return strings.TrimRight(src, " \t\r\n,")
+			}
+			i = end - 1
+		case '#':
+			src = src[:i]
+		}
+	}
+	return strings.TrimRight(src, " \t\r\n,")
+}
+
+func (r *renamer) renameOperations(fs afero.Fs, syms []*symbol, docs []*ast.Document) error {
+	w := newOpWalker(syms, rootTypes(docs))
+
+	var file string
+	if r.parent == "" {
+		w.typeRef = func(tok oplex.Token) {
+			if tok.Val == r.old {
+				r.edits[file] = append(r.edits[file], textEdit{start: tok.Off, end: tok.Off + len(tok.Val), text: r.new})
+			}
+		}
+	} else {
+		w.field = func(parent string, tok oplex.Token) {
+			if tok.Val == r.old && r.parents[parent] {
+				r.edits[file] = append(r.edits[file], textEdit{start: tok.Off, end: tok.Off + len(tok.Val), text: r.new})
+			}
+		}
+	}
+
+	for _, file = range r.ops {
+		src, err := afero.ReadFile(fs, file)
+		if err != nil {
+			return err
+		}
+		r.srcs[file] = src
+
+		toks, err := oplex.Lex(string(src))
+		if err == nil {
+			err = w.walk(toks)
+		}
+		if oerr, ok := err.(oplex.Error); ok {
+			line, col := lineCol(src, oerr.Off)
+			return fmt.Errorf("gqlc: %s:%d:%d: %s", file, line, col, oerr.Msg)
+		}
+	}
+	return nil
+}
+
+// rootTypes returns the root operation types of a schema.
+func rootTypes(docs []*ast.Document) map[string]string {
+	roots := map[string]string{
+		"query":        "Query",
+		"mutation":     "Mutation",
+		"subscription": "Subscription",
+	}
+
+	for _, doc := range docs {
+		for _, d := range doc.Types {
+			ts := declSpec(d)
+			if ts == nil {
+				continue
+			}
+
+			s, ok := ts.Type.(*ast.TypeSpec_Schema)
+			if !ok || s.Schema.RootOps == nil {
+				continue
+			}
+
+			for _, f := range s.Schema.RootOps.List {
+				roots[f.Name.Name] = namedType(fieldType(f))
+			}
+		}
+	}
+	return roots
+}
+
+func lineCol(src []byte, off int) (line, col int) {
+	line = bytes.Count(src[:off], []byte("\n")) + 1
+	col = off - bytes.LastIndexByte(src[:off], '\n')
+	return
+}
+
+func (r *renamer) write(fs afero.Fs, cmd *cobra.Command, files []string) error {
+	for _, file := range files {
+		edits, ok := r.edits[file]
+		if !ok {
+			continue
+		}
+		src := applyEdits(r.srcs[file], edits)
+
+		if r.dryRun {
+			fmt.Fprintf(cmd.OutOrStdout(), "# %s\n%s", file, src)
+			continue
+		}
+
+		mode := os.FileMode(0644)
+		if info, err := fs.Stat(file); err == nil {
+			mode = info.Mode()
+		}
+
+		err := afero.WriteFile(fs, file, src, mode)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,398 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+var renameSchema = `schema {
+	query: RootQuery
+}
+
+# Something with an id
+interface Node {
+	id: ID!
+	email: String
+}
+
+"A user"
+type User implements Node {
+	id: ID!
+	email(primary: Boolean = true): String # the address
+	friends: [User!]!
+}
+
+extend type User {
+	backup: User
+}
+
+union Result = User | RootQuery
+
+type RootQuery {
+	user(id: ID!): User
+	search(text: String = "#user"): [Result]
+}
+
+input UserFilter {
+	email: String
+	like: User
+}
+`
+
+var renameOps = `query GetUser($id: ID!) {
+	user(id: $id) {
+		email
+		primary: email(primary: true)
+		friends { email }
+		...UserEmail
+	}
+	search(text: "email") {
+		... on User { id email }
+	}
+}
+
+fragment UserEmail on User @include(if: true) {
+	backup { email }
+}
+`
+
+func TestRename(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Args   []string
+		Schema string
+		Ops    string
+		Err    bool
+	}{
+		{
+			Name: "Type",
+			Args: []string{"User", "->", "Account"},
+			Schema: `schema {
+	query: RootQuery
+}
+
+# Something with an id
+interface Node {
+	id: ID!
+	email: String
+}
+
+"A user"
+type Account implements Node {
+	id: ID!
+	email(primary: Boolean = true): String # the address
+	friends: [Account!]!
+}
+
+extend type Account {
+	backup: Account
+}
+
+union Result = Account | RootQuery
+
+type RootQuery {
+	user(id: ID!): Account
+	search(text: String = "#user"): [Result]
+}
+
+input UserFilter {
+	email: String
+	like: Account
+}
+`,
+			Ops: `query GetUser($id: ID!) {
+	user(id: $id) {
+		email
+		primary: email(primary: true)
+		friends { email }
+		...UserEmail
+	}
+	search(text: "email") {
+		... on Account { id email }
+	}
+}
+
+fragment UserEmail on Account @include(if: true) {
+	backup { email }
+}
+`,
+		},
+		{
+			Name: "RootType",
+			Args: []string{"RootQuery->Query"},
+			Schema: `schema {
+	query: Query
+}
+
+# Something with an id
+interface Node {
+	id: ID!
+	email: String
+}
+
+"A user"
+type User implements Node {
+	id: ID!
+	email(primary: Boolean = true): String # the address
+	friends: [User!]!
+}
+
+extend type User {
+	backup: User
+}
+
+union Result = User | Query
+
+type Query {
+	user(id: ID!): User
+	search(text: String = "#user"): [Result]
+}
+
+input UserFilter {
+	email: String
+	like: User
+}
+`,
+			Ops: renameOps,
+		},
+		{
+			Name: "InterfaceField",
+			Args: []string{"Node.email", "->", "Node.primaryEmail"},
+			Schema: `schema {
+	query: RootQuery
+}
+
+# Something with an id
+interface Node {
+	id: ID!
+	primaryEmail: String
+}
+
+"A user"
+type User implements Node {
+	id: ID!
+	primaryEmail(primary: Boolean = true): String # the address
+	friends: [User!]!
+}
+
+extend type User {
+	backup: User
+}
+
+union Result = User | RootQuery
+
+type RootQuery {
+	user(id: ID!): User
+	search(text: String = "#user"): [Result]
+}
+
+input UserFilter {
+	email: String
+	like: User
+}
+`,
+			Ops: `query GetUser($id: ID!) {
+	user(id: $id) {
+		primaryEmail
+		primary: primaryEmail(primary: true)
+		friends { primaryEmail }
+		...UserEmail
+	}
+	search(text: "email") {
+		... on User { id primaryEmail }
+	}
+}
+
+fragment UserEmail on User @include(if: true) {
+	backup { primaryEmail }
+}
+`,
+		},
+		{
+			Name: "Alias",
+			Args: []string{"User.email", "->", "primaryEmail", "--alias"},
+			Schema: `schema {
+	query: RootQuery
+}
+
+# Something with an id
+interface Node {
+	id: ID!
+	email: String
+}
+
+"A user"
+type User implements Node {
+	id: ID!
+	primaryEmail(primary: Boolean = true): String # the address
+	email(primary: Boolean = true): String @deprecated(reason: "Use ` + "`primaryEmail`" + ` instead.")
+	friends: [User!]!
+}
+
+extend type User {
+	backup: User
+}
+
+union Result = User | RootQuery
+
+type RootQuery {
+	user(id: ID!): User
+	search(text: String = "#user"): [Result]
+}
+
+input UserFilter {
+	email: String
+	like: User
+}
+`,
+			Ops: `query GetUser($id: ID!) {
+	user(id: $id) {
+		primaryEmail
+		primary: primaryEmail(primary: true)
+		friends { primaryEmail }
+		...UserEmail
+	}
+	search(text: "email") {
+		... on User { id primaryEmail }
+	}
+}
+
+fragment UserEmail on User @include(if: true) {
+	backup { primaryEmail }
+}
+`,
+		},
+		{
+			Name: "InputField",
+			Args: []string{"UserFilter.email", "->", "UserFilter.address"},
+			Schema: `schema {
+	query: RootQuery
+}
+
+# Something with an id
+interface Node {
+	id: ID!
+	email: String
+}
+
+"A user"
+type User implements Node {
+	id: ID!
+	email(primary: Boolean = true): String # the address
+	friends: [User!]!
+}
+
+extend type User {
+	backup: User
+}
+
+union Result = User | RootQuery
+
+type RootQuery {
+	user(id: ID!): User
+	search(text: String = "#user"): [Result]
+}
+
+input UserFilter {
+	address: String
+	like: User
+}
+`,
+			Ops: renameOps,
+		},
+		{
+			Name: "TypeExists",
+			Args: []string{"User", "->", "Result"},
+			Err:  true,
+		},
+		{
+			Name: "FieldExists",
+			Args: []string{"User.email", "->", "User.friends"},
+			Err:  true,
+		},
+		{
+			Name: "UnknownField",
+			Args: []string{"User.name", "->", "User.fullName"},
+			Err:  true,
+		},
+		{
+			Name: "MoveField",
+			Args: []string{"User.email", "->", "Node.email"},
+			Err:  true,
+		},
+		{
+			Name: "AliasType",
+			Args: []string{"User", "->", "Account", "--alias"},
+			Err:  true,
+		},
+		{
+			Name: "NoArrow",
+			Args: []string{"User", "Account"},
+			Err:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			fs := afero.NewMemMapFs()
+			afero.WriteFile(fs, "schema.gql", []byte(renameSchema), 0644)
+			afero.WriteFile(fs, "ops.graphql", []byte(renameOps), 0644)
+
+			c := NewCLI(WithFS(fs))
+			cmd := c.newRenameCmd()
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetArgs(append(testCase.Args, "--ops", "ops.graphql", "schema.gql"))
+
+			err := cmd.Execute()
+			if testCase.Err {
+				if err == nil {
+					subT.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			schema, _ := afero.ReadFile(fs, "schema.gql")
+			if string(schema) != testCase.Schema {
+				subT.Errorf("expected schema:\n%s\nbut got:\n%s", testCase.Schema, schema)
+			}
+
+			ops, _ := afero.ReadFile(fs, "ops.graphql")
+			if string(ops) != testCase.Ops {
+				subT.Errorf("expected operations:\n%s\nbut got:\n%s", testCase.Ops, ops)
+			}
+		})
+	}
+}
+
+func TestRename_DryRun(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "schema.gql", []byte("type User { email: String }\n"), 0644)
+
+	var b bytes.Buffer
+	c := NewCLI(WithFS(fs))
+	cmd := c.newRenameCmd()
+	cmd.SetOut(&b)
+	cmd.SetArgs([]string{"User.email", "->", "User.primaryEmail", "--dry_run", "schema.gql"})
+
+	err := cmd.Execute()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := "# schema.gql\ntype User { primaryEmail: String }\n"
+	if b.String() != ex {
+		t.Errorf("expected:\n%s\nbut got:\n%s", ex, b.String())
+	}
+
+	src, _ := afero.ReadFile(fs, "schema.gql")
+	if string(src) != "type User { email: String }\n" {
+		t.Error("expected schema to be left untouched")
+	}
+}
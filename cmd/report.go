@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gqlc/compiler"
+	"github.com/gqlc/gqlc/diag"
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"github.com/spf13/cobra"
+)
+
+// Report formats for the problems found while compiling.
+const (
+	reportText   = string(diag.Text)
+	reportJSON   = string(diag.JSON)
+	reportSARIF  = string(diag.SARIF)
+	reportGithub = string(diag.GitHub)
+)
+
+// Diagnostic codes, by the stage of compiling that found the problem.
+const (
+	codeParse       = "parse"
+	codeImportCycle = "import-cycle"
+	codeImport      = "import"
+	codeType        = "type"
+	codeGenerate    = "generate"
+)
+
+// parseErrRe matches the position prefix of a parser error
+// e.g. parser: schema.gql:3: unexpected ...
+//
+var parseErrRe = regexp.MustCompile(`^parser: (.+):(\d+): `)
+
+// reportedError is an error which has already been reported in a format
+// for tools, so it shouldn't be logged again.
+//
+type reportedError struct{ error }
+
+func (e reportedError) Unwrap() error { return e.error }
+
+// Reported reports whether err has already been reported by --report e.g.
+// as a GitHub workflow command, so logging it would only repeat it.
+//
+func Reported(err error) bool {
+	var r reportedError
+	return errors.As(err, &r)
+}
+
+// reporter prints diagnostics in a given format.
+type reporter struct {
+	format string
+	w      io.Writer
+
+	// mu guards reporting, since documents are generated concurrently
+	mu sync.Mutex
+
+	// pending holds diagnostics until flush, for formats which
+	// must be written as a single document.
+	pending []diag.Diagnostic
+}
+
+func initReporter(r *reporter) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("report")
+		if err != nil {
+			return err
+		}
+
+		if _, err = diag.ParseFormat(format); err != nil {
+			return fmt.Errorf("gqlc: unknown report format: %s", format)
+		}
+
+		r.format = format
+		r.w = cmd.OutOrStdout()
+		return nil
+	}
+}
+
+// report prints the given diagnostics. Plain text is logged, whereas
+// GitHub workflow commands must be written to stdout for the runner
+// to pick them up as annotations. JSON and SARIF are held until flush.
+//
+func (r *reporter) report(diags ...diag.Diagnostic) {
+	if !r.structured() {
+		logDiagnostics(diags)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.format != reportGithub {
+		r.pending = append(r.pending, diags...)
+		return
+	}
+
+	if err := diag.Render(r.writer(), diag.GitHub, diags...); err != nil {
+		log.Println(err)
+	}
+}
+
+// logDiagnostics logs diagnostics. Errors are logged by their message, and
+// anything else e.g. warnings, with its position and severity.
+//
+func logDiagnostics(diags []diag.Diagnostic) {
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			log.Println(d.Msg)
+			continue
+		}
+
+		var b strings.Builder
+		if err := diag.Render(&b, diag.Text, d); err != nil {
+			log.Println(err)
+			continue
+		}
+		log.Print(b.String())
+	}
+}
+
+// structured reports whether diagnostics are reported for tools, rather
+// than logged.
+//
+func (r *reporter) structured() bool {
+	return r != nil && r.format != "" && r.format != reportText
+}
+
+// flush writes the diagnostics held by report. A document is written
+// even if nothing went wrong, so tools can tell a clean run from a
+// failed one.
+//
+func (r *reporter) flush() {
+	if r == nil || r.format != reportJSON && r.format != reportSARIF {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := diag.Render(r.writer(), diag.Format(r.format), r.pending...); err != nil {
+		log.Println(err)
+	}
+	r.pending = nil
+}
+
+func (r *reporter) writer() io.Writer {
+	if r.w == nil {
+		return os.Stdout
+	}
+	return r.w
+}
+
+// diagnose traces errors back to the input files that caused them. Type
+// errors which name a type are given the line the type is declared on.
+//
+func (c *gqlcCmd) diagnose(dset *token.DocSet, docs map[string]*ast.Document, errs ...error) []diag.Diagnostic {
+	diags := make([]diag.Diagnostic, len(errs))
+	for i, err := range errs {
+		d := diag.Diagnostic{Severity: diag.Error, Msg: err.Error()}
+
+		switch v := err.(type) {
+		case *diag.Diagnostic:
+			d = *v
+		case *compiler.TypeError:
+			d.Code = codeType
+			d.Pos.File, d.Pos.Line = c.locate(dset, docs, v.Doc, v.Msg)
+		case *compiler.ImportError:
+			d.Code = codeImport
+			d.Pos.File, d.Pos.Line = c.locate(dset, docs, v.Doc, "")
+		case gen.GeneratorError:
+			d.Code = codeGenerate
+			d.Pos.File, _ = c.locate(dset, docs, findDoc(docs, v.DocName), "")
+		default:
+			// Type checkers, like the spec validator, return plain errors
+			// which name the type they're about
+			d.Pos.File, d.Pos.Line = c.locate(dset, docs, nil, err.Error())
+			if d.Pos.Line > 0 {
+				d.Code = codeType
+			}
+		}
+
+		diags[i] = d
+	}
+	return diags
+}
+
+// locate finds the file of a document and the line that the type named
+// by the first segment of msg is declared on e.g. Query:field: .... If doc
+// is nil, every document is searched for the type.
+//
+func (c *gqlcCmd) locate(dset *token.DocSet, docs map[string]*ast.Document, doc *ast.Document, msg string) (file string, line int) {
+	name := strings.TrimSpace(strings.SplitN(msg, ":", 2)[0])
+
+	for base, d := range docs {
+		if doc != nil && d != doc {
+			continue
+		}
+
+		decl := findDecl(d, name)
+		if doc == nil && decl == nil {
+			continue
+		}
+
+		file = c.files[base]
+		if file == "" {
+			file = base
+		}
+		if decl != nil && decl.TokPos > 0 {
+			line = dset.Position(token.Pos(decl.TokPos)).Line
+		}
+		return
+	}
+	return
+}
+
+func findDoc(docs map[string]*ast.Document, name string) *ast.Document {
+	for _, d := range docs {
+		if d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+func findDecl(doc *ast.Document, name string) *ast.TypeDecl {
+	if name == "" {
+		return nil
+	}
+
+	for _, d := range doc.Types {
+		ts := declSpec(d)
+		if ts == nil {
+			continue
+		}
+
+		if ts.Name == nil && name == "schema" || ts.Name != nil && ts.Name.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// parseError attributes a parser error to the file it was read from.
+func parseError(file string, err error) error {
+	d := &diag.Diagnostic{
+		Severity: diag.Error,
+		Code:     codeParse,
+		Pos:      diag.Pos{File: file},
+		Msg:      err.Error(),
+	}
+
+	if m := parseErrRe.FindStringSubmatch(d.Msg); m != nil {
+		d.Pos.Line, _ = strconv.Atoi(m[2])
+	}
+	return d
+}
@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gqlc/gqlc/diag"
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func TestInitReporter(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Args   []string
+		Format string
+		Err    bool
+	}{
+		{
+			Name:   "Default",
+			Format: reportText,
+		},
+		{
+			Name:   "Github",
+			Args:   []string{"--report", "github"},
+			Format: reportGithub,
+		},
+		{
+			Name:   "SARIF",
+			Args:   []string{"--report", "sarif"},
+			Format: reportSARIF,
+		},
+		{
+			Name: "Unknown",
+			Args: []string{"--report", "junit"},
+			Err:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().String("report", reportText, "")
+			if err := cmd.ParseFlags(testCase.Args); err != nil {
+				subT.Error(err)
+				return
+			}
+
+			var r reporter
+			err := initReporter(&r)(cmd, nil)
+			if testCase.Err {
+				if err == nil {
+					subT.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if r.format != testCase.Format {
+				subT.Errorf("expected format: %s, but got: %s", testCase.Format, r.format)
+			}
+		})
+	}
+}
+
+func TestReporter_Github(t *testing.T) {
+	var b bytes.Buffer
+	r := &reporter{format: reportGithub, w: &b}
+
+	r.report(
+		diag.Diagnostic{Msg: "100% broken\nreally", Pos: diag.Pos{File: "a,b:c.gql", Line: 3}},
+		diag.Diagnostic{Msg: "no position"},
+	)
+
+	ex := "::error file=a%2Cb%3Ac.gql,line=3::100%25 broken%0Areally\n::error::no position\n"
+	if b.String() != ex {
+		t.Errorf("expected:\n%q\nbut got:\n%q", ex, b.String())
+	}
+}
+
+func TestRun_ReportGithub(t *testing.T) {
+	typeErrors := `schema {
+  query: Query
+}
+
+type Query {
+  a: String
+}
+
+enum Direction {
+  NORTH
+  NORTH
+}
+
+input Point {
+  x: Query
+}
+`
+
+	testCases := []struct {
+		Name      string
+		Schema    string
+		KeepGoing bool
+		Ex        []string
+	}{
+		{
+			Name:   "ParseError",
+			Schema: "type Query {\n  a: String\n\n  b String\n}\n",
+			Ex:     []string{"::error file=/schemas/test.gql,line=4::parser: test.gql:4: "},
+		},
+		{
+			Name:   "TypeErrors",
+			Schema: typeErrors,
+			Ex: []string{
+				"::error file=/schemas/test.gql,line=9::Direction:NORTH: enum value must be unique",
+				"::error file=/schemas/test.gql,line=14::Point:x: ",
+			},
+		},
+		{
+			Name:      "Skipped",
+			Schema:    typeErrors,
+			KeepGoing: true,
+			Ex: []string{
+				"::error file=/schemas/test.gql,line=9::Direction:NORTH: enum value must be unique",
+				"::warning file=/schemas/test.gql::skipping test due to type errors",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			fs := afero.NewMemMapFs()
+			afero.WriteFile(fs, "/schemas/test.gql", []byte(testCase.Schema), 0644)
+
+			var b bytes.Buffer
+			cmd := &gqlcCmd{
+				cfg: &gqlcConfig{
+					report:    reporter{format: reportGithub, w: &b},
+					keepGoing: testCase.KeepGoing,
+				},
+			}
+
+			err := cmd.run(fs, "/schemas/test.gql")
+			if err == nil {
+				subT.Error("expected an error")
+				return
+			}
+			if !Reported(err) {
+				subT.Errorf("expected the error to be marked as reported, so it isn't logged again: %s", err)
+			}
+
+			out := b.String()
+			for _, ex := range testCase.Ex {
+				if !strings.Contains(out, ex) {
+					subT.Errorf("expected output to contain: %q\nbut got:\n%s", ex, out)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_ReportSARIF(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/schemas/test.gql", []byte(`enum Direction {
+  NORTH
+  NORTH
+}
+`), 0644)
+
+	var b bytes.Buffer
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			report: reporter{format: reportSARIF, w: &b},
+		},
+	}
+
+	if err := cmd.run(fs, "/schemas/test.gql"); err == nil {
+		t.Error("expected an error")
+		return
+	}
+
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				RuleID    string
+				Level     string
+				Locations []struct {
+					PhysicalLocation struct {
+						Region struct{ StartLine int }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(b.Bytes(), &log); err != nil {
+		t.Errorf("expected a single SARIF log, but got: %s\n%s", err, b.String())
+		return
+	}
+
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+		t.Errorf("expected one result, but got:\n%s", b.String())
+		return
+	}
+	res := log.Runs[0].Results[0]
+	if res.RuleID != "type" || res.Level != "error" || len(res.Locations) != 1 || res.Locations[0].PhysicalLocation.Region.StartLine != 1 {
+		t.Errorf("expected a type error on line 1, but got:\n%s", b.String())
+	}
+}
+
+func TestRun_ReportWarnings(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/schemas/test.gql", []byte(`type Query {
+  a: String
+}
+`), 0644)
+
+	g := newMockGenerator(t)
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, doc *ast.Document, _ interface{}) error {
+		gen.Warnf(gen.Context(ctx), doc, token.Pos(doc.Types[0].TokPos), "%s can't be generated", "Query")
+		return nil
+	})
+
+	var b bytes.Buffer
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners: []generator{{Generator: g, outDir: "/out"}},
+			jobs:   1,
+			report: reporter{format: reportJSON, w: &b},
+		},
+	}
+
+	if err := cmd.run(fs, "/schemas/test.gql"); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var diags []diag.Diagnostic
+	if err := json.Unmarshal(b.Bytes(), &diags); err != nil {
+		t.Errorf("expected a JSON array, but got: %s\n%s", err, b.String())
+		return
+	}
+
+	ex := diag.Diagnostic{
+		Severity: diag.Warning,
+		Code:     codeGenerate,
+		Pos:      diag.Pos{File: "/schemas/test.gql", Line: 1, Column: 1},
+		Msg:      "Query can't be generated",
+	}
+	if len(diags) != 1 || !reflect.DeepEqual(diags[0], ex) {
+		t.Errorf("expected: %v, but got: %v", ex, diags)
+	}
+}
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/gqlc/types"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"github.com/spf13/afero"
+)
+
+// resolvedFileSuffix is appended to the name of a document for the file its
+// resolved options are written to.
+//
+const resolvedFileSuffix = ".options.resolved.json"
+
+// Where a resolved option came from, in order of precedence.
+const (
+	sourceDefault  = "default"
+	sourceDocument = "document"
+	sourceFlag     = "flag"
+)
+
+// optionDirectives maps generators to the document directive holding their
+// options, when it isn't named after the generator's flag.
+//
+var optionDirectives = map[string]string{
+	"cs": "csharp",
+	"kt": "kotlin",
+}
+
+// optionDirective returns the name of the document directive holding the
+// options of a generator.
+//
+func optionDirective(name string) string {
+	if d, ok := optionDirectives[name]; ok {
+		return d
+	}
+	return name
+}
+
+// resolvedOptions are the options every generator resolves for a document.
+type resolvedOptions struct {
+	Document   string              `json:"document"`
+	Generators []resolvedGenerator `json:"generators"`
+}
+
+type resolvedGenerator struct {
+	Name      string                    `json:"name"`
+	Directive string                    `json:"directive,omitempty"`
+	Options   map[string]resolvedOption `json:"options"`
+}
+
+type resolvedOption struct {
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+}
+
+// writeResolvedOptions writes the options each generator resolves for each
+// document, to <dir>/<document>.options.resolved.json. The options are
+// resolved the way generators do: the defaults of their document directive
+// are overridden by the document, which is overridden by the flags.
+//
+func (c *gqlcCmd) writeResolvedOptions(fs afero.Fs, dir string, docs []*ast.Document) error {
+	for _, doc := range docs {
+		ro := resolvedOptions{Document: doc.Name, Generators: []resolvedGenerator{}}
+		for _, g := range c.cfg.geners {
+			filter, err := getTypeFilter(g.opts)
+			if err != nil {
+				return err
+			}
+			if filter != nil && !containsDoc(filter.apply(docs), doc.Name) {
+				continue
+			}
+
+			ro.Generators = append(ro.Generators, resolveOptions(g, doc))
+		}
+
+		b, err := json.MarshalIndent(ro, "", "  ")
+		if err != nil {
+			return err
+		}
+		b = append(b, '\n')
+
+		name := filepath.Join(dir, filepath.FromSlash(doc.Name)+resolvedFileSuffix)
+		err = fs.MkdirAll(filepath.Dir(name), 0755)
+		if err != nil {
+			return err
+		}
+
+		err = afero.WriteFile(fs, name, b, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsDoc(docs []*ast.Document, name string) bool {
+	for _, doc := range docs {
+		if doc.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveOptions resolves the options a generator is given for a document.
+func resolveOptions(g generator, doc *ast.Document) resolvedGenerator {
+	rg := resolvedGenerator{Name: g.name, Options: make(map[string]resolvedOption)}
+
+	name := optionDirective(g.name)
+
+	// Defaults are declared by the input type of the directive's options
+	if input := optionsType(name); input != nil {
+		rg.Directive = name
+		for _, f := range input.Fields.List {
+			if f.Default == nil {
+				continue
+			}
+
+			var val interface{}
+			switch v := f.Default.(type) {
+			case *ast.InputValue_BasicLit:
+				val = litValue(v.BasicLit)
+			case *ast.InputValue_CompositeLit:
+				val = litValue(v.CompositeLit)
+			}
+			rg.Options[f.Name.Name] = resolvedOption{Value: val, Source: sourceDefault}
+		}
+	}
+
+	for _, d := range doc.Directives {
+		if d.Name != name || d.Args == nil {
+			continue
+		}
+		rg.Directive = name
+
+		for _, arg := range d.Args.Args {
+			lit, ok := arg.Value.(*ast.Arg_CompositeLit)
+			if !ok {
+				continue
+			}
+			obj, ok := lit.CompositeLit.Value.(*ast.CompositeLit_ObjLit)
+			if !ok {
+				continue
+			}
+
+			for _, f := range obj.ObjLit.Fields {
+				rg.Options[f.Key.Name] = resolvedOption{Value: litValue(f.Val), Source: sourceDocument}
+			}
+		}
+	}
+
+	for k, v := range g.opts {
+		rg.Options[k] = resolvedOption{Value: flagValue(v), Source: sourceFlag}
+	}
+	return rg
+}
+
+// optionsType returns the input type of the options argument of a
+// registered directive, or nil if there's none.
+//
+func optionsType(directive string) *ast.InputType {
+	decl := types.Lookup(directive)
+	if decl == nil || decl.Tok != token.Token_DIRECTIVE {
+		return nil
+	}
+
+	dt, ok := decl.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Directive)
+	if !ok || dt.Directive.Args == nil {
+		return nil
+	}
+
+	for _, arg := range dt.Directive.Args.List {
+		ident, ok := arg.Type.(*ast.InputValue_Ident)
+		if arg.Name.Name != "options" || !ok {
+			continue
+		}
+
+		input := types.Lookup(ident.Ident.Name)
+		if input == nil {
+			return nil
+		}
+		it, ok := input.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Input)
+		if !ok || it.Input.Fields == nil {
+			return nil
+		}
+		return it.Input
+	}
+	return nil
+}
+
+// litValue converts a GraphQL literal to the JSON value it represents.
+// Enum values are strings.
+//
+func litValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		switch v.Kind {
+		case token.Token_STRING:
+			return unquote(v.Value)
+		case token.Token_INT:
+			if i, err := strconv.ParseInt(v.Value, 10, 64); err == nil {
+				return i
+			}
+		case token.Token_FLOAT:
+			if f, err := strconv.ParseFloat(v.Value, 64); err == nil {
+				return f
+			}
+		case token.Token_BOOL:
+			return v.Value == "true"
+		}
+		return v.Value
+	case *ast.CompositeLit:
+		switch w := v.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			return litValue(w.BasicLit)
+		case *ast.CompositeLit_ListLit:
+			return litValue(w.ListLit)
+		case *ast.CompositeLit_ObjLit:
+			return litValue(w.ObjLit)
+		}
+	case *ast.ListLit:
+		vals := []interface{}{}
+		switch w := v.List.(type) {
+		case *ast.ListLit_BasicList:
+			for _, b := range w.BasicList.Values {
+				vals = append(vals, litValue(b))
+			}
+		case *ast.ListLit_CompositeList:
+			for _, c := range w.CompositeList.Values {
+				vals = append(vals, litValue(c))
+			}
+		}
+		return vals
+	case *ast.ObjLit:
+		obj := make(map[string]interface{}, len(v.Fields))
+		for _, f := range v.Fields {
+			obj[f.Key.Name] = litValue(f.Val)
+		}
+		return obj
+	}
+	return nil
+}
+
+// flagValue returns the value of a generator flag option, without the
+// quotes strings are given in.
+//
+func flagValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case string:
+		return unquote(v)
+	case []string:
+		vals := make([]string, len(v))
+		for i, s := range v {
+			vals[i] = unquote(s)
+		}
+		return vals
+	}
+	return val
+}
+
+func unquote(s string) string {
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+	return strings.Trim(s, `"`)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	_ "github.com/gqlc/gqlc/csharp"
+	_ "github.com/gqlc/gqlc/doc"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+	"github.com/spf13/afero"
+)
+
+func TestResolveOptions(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api", strings.NewReader(`@doc(options: {title: "API", tocKinds: ["object", "scalar"], tocCollapse: 2})
+
+type Query {
+	a: String
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Precedence", func(subT *testing.T) {
+		rg := resolveOptions(generator{name: "doc", opts: map[string]interface{}{
+			"title": `"Flag"`,
+			"html":  true,
+			"css":   []string{`"a.css"`, `"b.css"`},
+		}}, doc)
+
+		if rg.Directive != "doc" {
+			subT.Errorf("expected the doc directive, but got: %q", rg.Directive)
+		}
+
+		ex := map[string]resolvedOption{
+			"title":       {Value: "Flag", Source: sourceFlag},
+			"html":        {Value: true, Source: sourceFlag},
+			"css":         {Value: []string{"a.css", "b.css"}, Source: sourceFlag},
+			"tocKinds":    {Value: []interface{}{"object", "scalar"}, Source: sourceDocument},
+			"tocCollapse": {Value: int64(2), Source: sourceDocument},
+			"pages":       {Value: "kind", Source: sourceDefault},
+			"tables":      {Value: false, Source: sourceDefault},
+		}
+		for name, opt := range ex {
+			if !reflect.DeepEqual(rg.Options[name], opt) {
+				subT.Errorf("expected %s to be resolved as %#v, but got: %#v", name, opt, rg.Options[name])
+			}
+		}
+	})
+
+	t.Run("Alias", func(subT *testing.T) {
+		rg := resolveOptions(generator{name: "cs"}, doc)
+		if rg.Directive != "csharp" || len(rg.Options) == 0 {
+			subT.Errorf("expected the defaults of the csharp directive, but got: %#v", rg)
+		}
+	})
+
+	t.Run("Plugin", func(subT *testing.T) {
+		rg := resolveOptions(generator{name: "plugin", opts: map[string]interface{}{"a": int64(1)}}, doc)
+
+		ex := map[string]resolvedOption{"a": {Value: int64(1), Source: sourceFlag}}
+		if rg.Directive != "" || !reflect.DeepEqual(rg.Options, ex) {
+			subT.Errorf("expected only the flags of a plugin, but got: %#v", rg)
+		}
+	})
+}
+
+func TestRun_ResolvedOptions(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/docs/a.gql", []byte(`@doc(options: {title: "A"})
+
+type A { b: String }`), 0644)
+
+	// The options are written before generating, so a failing generator
+	// can be debugged.
+	//
+	g := newMockGenerator(t)
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, *ast.Document, interface{}) error {
+		return errors.New("failed")
+	})
+
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners:          []generator{{Generator: g, name: "doc", opts: map[string]interface{}{"html": true}}},
+			ipaths:          []string{"/docs"},
+			jobs:            1,
+			resolvedOptions: "/out",
+		},
+	}
+
+	err := cmd.run(fs, "a.gql")
+	if err == nil {
+		t.Fatal("expected the generator to fail")
+	}
+
+	b, err := afero.ReadFile(fs, "/out/a"+resolvedFileSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ro resolvedOptions
+	err = json.Unmarshal(b, &ro)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ro.Document != "a" || len(ro.Generators) != 1 {
+		t.Fatalf("expected the options of the doc generator for a, but got: %s", b)
+	}
+	opts := ro.Generators[0].Options
+	if opts["title"].Value != "A" || opts["title"].Source != sourceDocument || opts["html"].Value != true || opts["html"].Source != sourceFlag {
+		t.Errorf("unexpected options: %s", b)
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/gqlc/gqlc/plugin"
+	"github.com/spf13/cobra"
+)
+
+// initPluginRetries configures how plugin generators are retried, when
+// they fail. Only plugins are retried, since built in generators don't
+// fail transiently.
+//
+func initPluginRetries(geners *[]generator) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		retries, err := cmd.Flags().GetInt("plugin_retries")
+		if err != nil {
+			return err
+		}
+		if retries < 0 {
+			return fmt.Errorf("plugin_retries must not be negative, but got: %d", retries)
+		}
+
+		backoff, err := cmd.Flags().GetDuration("plugin_backoff")
+		if err != nil {
+			return err
+		}
+
+		for _, g := range *geners {
+			p, ok := g.Generator.(*plugin.Generator)
+			if !ok {
+				continue
+			}
+
+			p.Retries = retries
+			p.Backoff = backoff
+		}
+		return nil
+	}
+}
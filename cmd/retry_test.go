@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/gqlc/gqlc/plugin"
+	"github.com/spf13/afero"
+)
+
+func TestPluginRetries(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/in/api.gql", []byte(`type Query { a: String }`), 0644)
+
+	testCases := []struct {
+		Name    string
+		Args    []string
+		Retries int
+		Backoff time.Duration
+		Err     bool
+	}{
+		{Name: "Default", Backoff: time.Second},
+		{Name: "Flags", Args: []string{"--plugin_retries", "3", "--plugin_backoff", "10ms"}, Retries: 3, Backoff: 10 * time.Millisecond},
+		{Name: "Negative", Args: []string{"--plugin_retries", "-1"}, Err: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			p := &plugin.Generator{Name: "test", Prefix: "gqlc-gen-"}
+
+			c := NewCLI(WithFS(fs))
+			c.RegisterGenerator(p, "test_out", "test_opt", "Run a plugin.")
+			cmd := c.newExplainCmd()
+			cmd.SetOut(ioutil.Discard)
+			cmd.SetArgs(append([]string{"-I", "/in", "--test_out", "/out", "api.gql"}, testCase.Args...))
+
+			err := cmd.Execute()
+			if testCase.Err {
+				if err == nil {
+					subT.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if p.Retries != testCase.Retries || p.Backoff != testCase.Backoff {
+				subT.Errorf("expected %d retries after %s, but got: %d after %s", testCase.Retries, testCase.Backoff, p.Retries, p.Backoff)
+			}
+		})
+	}
+}
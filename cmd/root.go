@@ -2,18 +2,27 @@ package cmd
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"text/scanner"
+	"time"
 
 	"github.com/gqlc/compiler"
 	"github.com/gqlc/compiler/spec"
+	"github.com/gqlc/gqlc/codemod"
+	"github.com/gqlc/gqlc/diag"
+	"github.com/gqlc/gqlc/federation"
 	"github.com/gqlc/gqlc/gen"
 	"github.com/gqlc/graphql/ast"
 	"github.com/gqlc/graphql/parser"
@@ -25,18 +34,62 @@ import (
 )
 
 type gqlcConfig struct {
-	ipaths []string
-	geners []generator
+	ipaths   []string
+	ops      []string
+	geners   []generator
+	codemods codemod.Pipeline
+	report   reporter
 
 	logger  *zap.Logger
 	client  *fetchClient
 	headers http.Header
+
+	allowImportCycles bool
+	keepGoing         bool
+
+	// jobs is the number of documents generated at once by each generator
+	jobs int
+
+	limits outputLimits
+
+	// maxDepth is how deeply types and values may nest, which 0 leaves
+	// unlimited.
+	//
+	maxDepth int
+
+	// compat compares generated code with the committed code, instead of
+	// overwriting it, when set.
+	//
+	compat *compatCheck
+
+	// hashes keeps files which were modified since they were generated
+	// from being overwritten without asking.
+	//
+	hashes *outputHashes
+
+	// resolvedOptions is the directory the options each generator resolves
+	// for each document are written to, if it's set.
+	//
+	resolvedOptions string
+
+	// provenance records what the generated files were generated from,
+	// if it's set.
+	//
+	provenance *provenance
 }
 
 type gqlcCmd struct {
 	*cobra.Command
 
 	cfg *gqlcConfig
+
+	// files maps document names to the path they were read from
+	files map[string]string
+
+	// digests maps the paths documents were read from to their sha256
+	// digest.
+	//
+	digests map[string]string
 }
 
 func (c *CommandLine) newGqlcCmd(cfgs []genConfig, fs afero.Fs, pluginPrefix string) *gqlcCmd {
@@ -64,7 +117,15 @@ func (c *CommandLine) newGqlcCmd(cfgs []genConfig, fs afero.Fs, pluginPrefix str
 
 		An additional flag, *_opt, can be used to pass options to a generator. The
 		argument given to this type of flag is the same format as the *_opt
-		key=value pairs above.`,
+		key=value pairs above.
+
+		The generator option, post, is reserved for attaching post-processing
+		commands to a generator, e.g. --js_opt post="prettier --write". Each
+		command is run with the files the generator wrote appended to its arguments.
+
+		The generator options, include and exclude, are reserved for scoping the
+		types a generator sees by name, kind or directive, e.g.
+		--go_opt exclude=@internal or --doc_opt include="Query,Mutation".`,
 		Example: "gqlc -I . --doc_out ./docs --go_out ./goservice --js_out ./jsservice api.gql",
 		Args: func(cmd *cobra.Command, args []string) error {
 			err := cobra.MinimumNArgs(1)(cmd, args)
@@ -90,10 +151,44 @@ func (c *CommandLine) newGqlcCmd(cfgs []genConfig, fs afero.Fs, pluginPrefix str
 			},
 			func(cmd *cobra.Command, args []string) (err error) {
 				cc.cfg.ipaths, err = cmd.Flags().GetStringSlice("import_path")
+				if err != nil {
+					return
+				}
+
+				cc.cfg.ops, err = cmd.Flags().GetStringSlice("ops")
+				if err != nil {
+					return
+				}
+				err = validateOps(cc.cfg.ops)
+				if err != nil {
+					return
+				}
+
+				cc.cfg.allowImportCycles, err = cmd.Flags().GetBool("allow-import-cycles")
+				if err != nil {
+					return
+				}
+
+				cc.cfg.keepGoing, err = cmd.Flags().GetBool("keep-going")
+				if err != nil {
+					return
+				}
+
+				cc.cfg.jobs, err = cmd.Flags().GetInt("jobs")
+				if err == nil && cc.cfg.jobs < 1 {
+					err = fmt.Errorf("jobs must be at least 1, but got: %d", cc.cfg.jobs)
+				}
 				return
 			},
+			initPluginRetries(&cc.cfg.geners),
+			initCodemods(fs, c.codemods, &cc.cfg.codemods),
+			initConfigPost(fs, &cc.cfg.geners),
+			initReporter(&cc.cfg.report),
 			cc.validatePluginTypes(c.fs),
 			initGenDirs(fs, &outDirs),
+			initCompat(fs, &cc.cfg.compat),
+			initOutputHashes(fs, &cc.cfg.hashes),
+			initProvenance(fs, &cc.cfg.provenance),
 		),
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			defer resetGlobalLogger()
@@ -112,8 +207,50 @@ imports.  May be specified multiple times;
 directories will be searched in order.  If not
 given, the current working directory is used.`)
 	cc.Flags().BoolP("verbose", "v", false, "Output logging")
+	cc.Flags().StringSlice("ops", nil, `Provide executable documents, i.e. operations and
+fragments, or directories of them, to generators
+alongside the schema. May be specified multiple
+times.`)
 	cc.Flags().StringSliceP("types", "t", nil, "Provide .gql files containing types you wish to register with the compiler.")
 	cc.Flags().VarP(&headerFlag{value: &cc.cfg.headers}, "headers", "H", "Provide HTTP headers to fetching. Format: a=1,b=2")
+	cc.Flags().String("config", defaultConfigFile, `Provide a config file listing codemods to apply
+before generating, and post-processors for each
+generator.`)
+	cc.Flags().String("report", reportText, `Format to report errors in. One of text, json,
+sarif or github, which prints GitHub Actions
+workflow commands.`)
+	cc.Flags().Bool("allow-import-cycles", false, `Break import cycles, by ignoring the import which
+closes each cycle, instead of failing.`)
+	cc.Flags().Bool("keep-going", false, `Generate the documents which type check, even if
+others fail to.`)
+	cc.Flags().IntP("jobs", "j", runtime.NumCPU(), "Number of documents each generator generates at once.")
+	cc.Flags().Int64Var(&cc.cfg.limits.maxFiles, "max-files", 0, "Maximum number of files generators may write. 0 means no limit.")
+	cc.Flags().Var(sizeFlag{value: &cc.cfg.limits.maxBytes}, "max-output-bytes", `Maximum number of bytes generators may write in
+total e.g. 100MB. 0 means no limit.`)
+	cc.Flags().Var(sizeFlag{value: &cc.cfg.limits.maxFileBytes}, "max-file-bytes", `Maximum number of bytes generators may write to a
+single file e.g. 10MiB. 0 means no limit.`)
+	cc.Flags().IntVar(&cc.cfg.maxDepth, "max-depth", gen.DefaultMaxDepth, `Maximum depth types, e.g. [[Int!]!], and values
+may nest. 0 means no limit.`)
+	cc.Flags().Bool("compat", false, `Compare the generated code with the code already
+in the output directories, e.g. as generated by a
+previous version of gqlc, and summarize the
+declarations which were renamed, changed, removed
+or added, instead of writing it.`)
+	cc.Flags().Int("plugin_retries", 0, `Number of times to retry a plugin which exits
+with an error, e.g. because it depends on the
+network.`)
+	cc.Flags().Duration("plugin_backoff", time.Second, `How long to wait before retrying a plugin, which
+doubles after each retry.`)
+	cc.Flags().Bool("force", false, `Overwrite files which were modified since they
+were generated, instead of asking.`)
+	cc.Flags().StringVar(&cc.cfg.resolvedOptions, "resolved-options", "", `Write the options each generator resolves for each
+document, to <dir>/<document>.options.resolved.json.`)
+	cc.Flags().String("provenance", "", `Write a provenance record of the generated files
+to this file, listing the digests of the inputs,
+plugins and outputs, and the options used, as an
+in-toto statement of SLSA provenance.`)
+	cc.Flags().Bool("skip-modified", false, `Keep files which were modified since they were
+generated, instead of asking.`)
 
 	fp := &fparser{
 		Scanner: new(scanner.Scanner),
@@ -122,6 +259,7 @@ given, the current working directory is used.`)
 	for _, cfg := range cfgs {
 		f := genFlag{
 			g:       cfg.g,
+			name:    cfg.name,
 			opts:    make(map[string]interface{}),
 			geners:  &cc.cfg.geners,
 			outDirs: &outDirs,
@@ -142,29 +280,166 @@ given, the current working directory is used.`)
 type genCtx struct {
 	fs  afero.Fs
 	dir string
+
+	// sources maps document names to the file they were parsed from
+	sources map[string]string
+
+	dset *token.DocSet
+
+	// files tracks every file opened so they can be post-processed
+	files []string
+
+	// ops are the executable documents given alongside the schema
+	ops []gen.OperationDocument
+
+	// docs are the documents being generated together
+	docs []*ast.Document
+
+	limits   *outputLimits
+	maxDepth int
+	compat   *compatCheck
+	hashes   *outputHashes
+
+	// outputs records the files written, for their provenance
+	outputs *outputLog
+
+	// report reports the diagnostics of generators
+	report *reporter
 }
 
+// Report implements the gen.DiagnosticContext interface.
+func (ctx *genCtx) Report(doc *ast.Document, d diag.Diagnostic) {
+	if !d.Pos.IsValid() {
+		d.Pos.File = ctx.sources[doc.Name]
+		if d.Pos.File == "" {
+			d.Pos.File = doc.Name
+		}
+	}
+	ctx.report.report(d)
+}
+
+// MaxDepth implements the gen.DepthContext interface.
+func (ctx *genCtx) MaxDepth() int { return ctx.maxDepth }
+
+// Dir implements the gen.PathContext interface.
+func (ctx *genCtx) Dir() string { return ctx.dir }
+
+// Source implements the gen.PathContext interface.
+func (ctx *genCtx) Source(doc *ast.Document) string {
+	src, ok := ctx.sources[doc.Name]
+	if !ok {
+		return ""
+	}
+
+	abs, err := filepath.Abs(src)
+	if err != nil {
+		return ""
+	}
+	return abs
+}
+
+// Operations implements the gen.OperationContext interface.
+func (ctx *genCtx) Operations() []gen.OperationDocument { return ctx.ops }
+
+// Documents implements the gen.DocumentContext interface.
+func (ctx *genCtx) Documents() []*ast.Document { return ctx.docs }
+
+// Position implements the gen.PositionContext interface.
+func (ctx *genCtx) Position(pos token.Pos) token.Position { return ctx.dset.Position(pos) }
+
+// ReadFile implements the gen.PathContext interface.
+func (ctx *genCtx) ReadFile(name string) ([]byte, error) { return afero.ReadFile(ctx.fs, name) }
+
 func (ctx *genCtx) Open(name string) (io.WriteCloser, error) {
-	f, err := ctx.fs.OpenFile(filepath.Join(ctx.dir, name), os.O_WRONLY|os.O_CREATE, 0755)
+	fname := filepath.Join(ctx.dir, name)
+	if ctx.hashes != nil {
+		ok, err := ctx.hashes.check(ctx.dir, fname)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return discard{}, nil
+		}
+	}
+
+	if err := ctx.limits.open(fname); err != nil {
+		return nil, err
+	}
+
+	if err := ctx.fs.MkdirAll(filepath.Dir(fname), 0755); err != nil {
+		return nil, err
+	}
+
+	f, err := ctx.fs.OpenFile(fname, os.O_WRONLY|os.O_CREATE, 0755)
 	if err != nil {
 		return nil, err
 	}
+	ctx.files = append(ctx.files, fname)
+	ctx.outputs.add(fname)
+	if ctx.compat != nil {
+		ctx.compat.add(fname)
+	}
 
-	return f, f.Truncate(0)
+	return ctx.limits.writer(fname, f), f.Truncate(0)
+}
+
+// Scaffold implements the gen.ScaffoldContext interface.
+func (ctx *genCtx) Scaffold(name string) (io.WriteCloser, error) {
+	fname := filepath.Join(ctx.dir, name)
+	exists, err := afero.Exists(ctx.fs, fname)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, gen.ErrScaffolded
+	}
+
+	if ctx.hashes != nil {
+		ctx.hashes.scaffold(fname)
+	}
+	return ctx.Open(name)
 }
 
 type generator struct {
 	gen.Generator
 
+	name   string
 	opts   map[string]interface{}
 	outDir string
 }
 
+// typeCheckers are run against every document before it's generated.
+var typeCheckers = []compiler.TypeChecker{
+	spec.Validator,
+	compiler.ImportValidator,
+	federation.Validator,
+}
+
 func (c *gqlcCmd) run(fs afero.Fs, args ...string) (err error) {
+	dset := token.NewDocSet()
+	docMap := make(map[string]*ast.Document, len(args))
+
+	// Type errors are reported as they're found, everything else on return
+	var reported bool
+	defer func() {
+		if err != nil && c.cfg.report.structured() {
+			if !reported {
+				c.cfg.report.report(c.diagnose(dset, docMap, err)...)
+			}
+			err = reportedError{err}
+		}
+		c.cfg.report.flush()
+	}()
+
 	// Parse files
 	zap.S().Info("parsing input files")
-	docMap := make(map[string]*ast.Document, len(args))
-	err = c.parseInputFiles(fs, token.NewDocSet(), docMap, args...)
+	err = c.parseInputFiles(fs, dset, docMap, args...)
+	if err != nil {
+		return
+	}
+
+	zap.S().Info("checking for import cycles")
+	err = c.checkImportCycles(dset, docMap)
 	if err != nil {
 		return
 	}
@@ -189,14 +464,22 @@ func (c *gqlcCmd) run(fs afero.Fs, args ...string) (err error) {
 	zap.S().Info("implementing interfaces")
 	docsIR = implInterfaces(docsIR)
 
+	// Define the implicit schema of documents which only extend it
+	zap.S().Info("defining implicit schemas")
+	docsIR = implicitSchema(docsIR)
+
 	// Perform type checking
 	zap.S().Info("type checking")
-	errs := compiler.CheckTypes(docsIR, spec.Validator, compiler.ImportValidator)
-	if len(errs) > 0 {
-		for _, err = range errs {
-			log.Println(err)
+	var checkErr error
+	if c.cfg.keepGoing {
+		checkErr = c.checkEach(dset, docMap, docsIR)
+	} else {
+		errs := compiler.CheckTypes(docsIR, typeCheckers...)
+		if len(errs) > 0 {
+			c.cfg.report.report(c.diagnose(dset, docMap, errs...)...)
+			reported = true
+			return errs[len(errs)-1]
 		}
-		return
 	}
 
 	// Merge type extensions with the original type definitions
@@ -205,29 +488,226 @@ func (c *gqlcCmd) run(fs afero.Fs, args ...string) (err error) {
 		docsIR[d] = compiler.MergeExtensions(types)
 	}
 
+	// Apply any codemods
+	if len(c.cfg.codemods) > 0 {
+		zap.S().Info("applying codemods")
+		err = c.cfg.codemods.Apply(docsIR)
+		if err != nil {
+			return
+		}
+	}
+
 	// Convert types from IR to []*ast.TypeDecl
 	docs = compiler.FromIR(docsIR)
 	for _, doc := range docs {
 		doc.Types = sortTypeDecls(doc.Types)
 	}
 
+	zap.S().Info("reading operations")
+	ops, err := c.readOperations(fs, docs)
+	if err != nil {
+		return
+	}
+
+	// Document names have had their extension stripped by now
+	sources := make(map[string]string, len(c.files))
+	for name, path := range c.files {
+		sources[strings.TrimSuffix(name, filepath.Ext(name))] = path
+	}
+
+	if c.cfg.resolvedOptions != "" {
+		zap.S().Info("writing resolved options")
+		err = c.writeResolvedOptions(fs, c.cfg.resolvedOptions, docs)
+		if err != nil {
+			return
+		}
+	}
+
 	// Run code generators
 	zap.S().Info("generating documents")
+	c.cfg.limits.reset()
+	outFs, hashes := fs, c.cfg.hashes
+	if c.cfg.compat != nil {
+		outFs, hashes = c.cfg.compat.fs(), nil
+	}
+	if hashes != nil {
+		// Files written before a generator failed are recorded too, or
+		// they'd look modified to the next run.
+		defer func() {
+			zap.S().Info("recording generated file hashes")
+			if serr := hashes.save(); serr != nil && err == nil {
+				err = serr
+			}
+		}()
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	for _, g := range c.cfg.geners {
-		ctx = gen.WithContext(ctx, &genCtx{dir: g.outDir, fs: fs})
+	outputs := make([]*outputLog, len(c.cfg.geners))
+	for i, g := range c.cfg.geners {
+		pps, perr := getPostProcessors(g.opts)
+		if perr != nil {
+			return perr
+		}
+		if c.cfg.compat != nil && len(pps) > 0 {
+			// They'd be run on the committed files
+			zap.S().Infow("skipping post-processors, since the generated code isn't written", "generator", g.name)
+			pps = nil
+		}
 
-		for _, doc := range docs {
-			err = g.Generate(ctx, doc, g.opts)
-			if err != nil {
-				return
-			}
+		filter, ferr := getTypeFilter(g.opts)
+		if ferr != nil {
+			return ferr
+		}
+
+		gDocs := docs
+		if filter != nil {
+			gDocs = filter.apply(docs)
+		}
+
+		outputs[i] = new(outputLog)
+		gCtx := &genCtx{dir: g.outDir, fs: outFs, sources: sources, dset: dset, ops: ops, docs: gDocs, limits: &c.cfg.limits, maxDepth: c.cfg.maxDepth, compat: c.cfg.compat, hashes: hashes, outputs: outputs[i], report: &c.cfg.report}
+		err = c.generate(ctx, g, gCtx, gDocs, pps)
+		if err != nil {
+			return
 		}
 	}
+
+	if c.cfg.provenance != nil {
+		zap.S().Info("writing provenance")
+		if err = c.writeProvenance(fs, outFs, args, ops, outputs); err != nil {
+			return
+		}
+	}
+
+	if c.cfg.compat != nil {
+		zap.S().Info("comparing generated code")
+		if err = c.cfg.compat.report(); err != nil {
+			return
+		}
+	}
+
+	if checkErr != nil {
+		// Its type errors have already been reported
+		reported = true
+		return checkErr
+	}
 	return
 }
 
+// generate generates docs with g, up to c.cfg.jobs at a time. Each document
+// is given its own copy of gCtx, so only the files it opened are post-processed
+// after it. Once a document fails no more are started, and the error of the
+// first failing document is returned.
+//
+func (c *gqlcCmd) generate(ctx context.Context, g generator, gCtx *genCtx, docs []*ast.Document, pps []postProcessor) error {
+	jobs := c.cfg.jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	var (
+		wg     sync.WaitGroup
+		failed int32
+		sem    = make(chan struct{}, jobs)
+		errs   = make([]error, len(docs))
+	)
+	for i, doc := range docs {
+		sem <- struct{}{}
+		if atomic.LoadInt32(&failed) != 0 {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, doc *ast.Document) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			dCtx := *gCtx
+			errs[i] = generateDoc(gen.WithContext(ctx, &dCtx), g, &dCtx, doc, pps)
+			if errs[i] != nil {
+				atomic.StoreInt32(&failed, 1)
+			}
+		}(i, doc)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateDoc generates a single document and runs the post-processors over
+// the files written for it.
+//
+func generateDoc(ctx context.Context, g generator, gCtx *genCtx, doc *ast.Document, pps []postProcessor) (err error) {
+	// Documents are generated in their own goroutines, so a panic can't be
+	// recovered by Run.
+	defer func() {
+		if r := recover(); r != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: g.name,
+				Msg:     panicError(r).Error(),
+			}
+		}
+	}()
+
+	err = g.Generate(ctx, doc, g.opts)
+	if err != nil {
+		return err
+	}
+
+	for _, pp := range pps {
+		err = pp.run(ctx, gCtx.files)
+		if err != nil {
+			return gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: g.name,
+				Msg:     err.Error(),
+			}
+		}
+	}
+	return nil
+}
+
+// checkEach type checks each document on its own and removes those which
+// fail from the IR, so the rest can still be generated. Imports have
+// already been reduced, so a document contains every type it depends on.
+//
+func (c *gqlcCmd) checkEach(dset *token.DocSet, docMap map[string]*ast.Document, docsIR compiler.IR) error {
+	docs := make([]*ast.Document, 0, len(docsIR))
+	for doc := range docsIR {
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Name < docs[j].Name })
+
+	var failed []string
+	for _, doc := range docs {
+		errs := compiler.CheckTypes(compiler.IR{doc: docsIR[doc]}, typeCheckers...)
+		if len(errs) == 0 {
+			continue
+		}
+		c.cfg.report.report(c.diagnose(dset, docMap, errs...)...)
+
+		var pos diag.Pos
+		pos.File, _ = c.locate(dset, docMap, findDoc(docMap, doc.Name), "")
+		c.cfg.report.report(*diag.Warningf(pos, codeType, "skipping %s due to type errors", doc.Name))
+
+		delete(docsIR, doc)
+		failed = append(failed, doc.Name)
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("gqlc: skipped generating %s due to type errors", strings.Join(failed, ", "))
+}
+
 // resolveImportPaths makes sure import paths and doc names are consistent.
 func resolveImportPaths(docs []*ast.Document) {
 	for _, d := range docs {
@@ -239,29 +719,11 @@ func resolveImportPaths(docs []*ast.Document) {
 				continue
 			}
 
-			for _, arg := range direc.Args.Args {
-
-				compLit := arg.Value.(*ast.Arg_CompositeLit).CompositeLit
-				listLit := compLit.Value.(*ast.CompositeLit_ListLit).ListLit.List
-
-				var paths []*ast.BasicLit
-				switch v := listLit.(type) {
-				case *ast.ListLit_BasicList:
-					paths = append(paths, v.BasicList.Values...)
-				case *ast.ListLit_CompositeList:
-					cpaths := v.CompositeList.Values
-					paths = make([]*ast.BasicLit, len(cpaths))
-					for i, c := range cpaths {
-						paths[i] = c.Value.(*ast.CompositeLit_BasicLit).BasicLit
-					}
-				}
-
-				for _, p := range paths {
-					iPath := strings.Trim(p.Value, "\"")
-					iName := filepath.Base(iPath)
+			for _, p := range importLits(direc) {
+				iPath := strings.Trim(p.Value, "\"")
+				iName := filepath.Base(iPath)
 
-					p.Value = fmt.Sprintf(`"%s"`, iName[:len(iName)-len(filepath.Ext(iName))])
-				}
+				p.Value = fmt.Sprintf(`"%s"`, iName[:len(iName)-len(filepath.Ext(iName))])
 			}
 		}
 	}
@@ -280,14 +742,37 @@ func (c *gqlcCmd) parseInputFiles(fs afero.Fs, dset *token.DocSet, docs map[stri
 		if err != nil {
 			return err
 		}
-		defer f.Close()
 
-		doc, err := parser.ParseDoc(dset, name, f, parser.ParseComments)
+		path := filename
+		if fname, _ := normFilePath(fs, c.cfg.ipaths, filename); fname != "" {
+			path = fname
+		}
+
+		// Close each file once it's parsed, rather than deferring it, so
+		// files aren't all held open until the last one is parsed. It's
+		// hashed as it's read, for the provenance of the generated files.
+		// Files aren't memory-mapped, since ParseDoc reads its whole source
+		// into memory, then copies it again for the lexer, either way.
+		//
+		h := sha256.New()
+		doc, err := parser.ParseDoc(dset, name, io.TeeReader(f, h), parser.ParseComments)
+		f.Close()
 		if err != nil {
-			return err
+			return parseError(path, err)
+		}
+
+		// Everything from here on walks types and values recursively
+		if err = gen.CheckDepth(doc, c.cfg.maxDepth); err != nil {
+			return fmt.Errorf("gqlc: %s: %s, see --max-depth", path, err)
 		}
 
+		if c.files == nil {
+			c.files = make(map[string]string)
+			c.digests = make(map[string]string)
+		}
 		docs[name] = doc
+		c.files[name] = path
+		c.digests[path] = hex.EncodeToString(h.Sum(nil))
 	}
 
 	for _, doc := range docs {
@@ -312,26 +797,8 @@ func getImports(doc *ast.Document) (names []string) {
 			continue
 		}
 
-		for _, arg := range direc.Args.Args {
-
-			compLit := arg.Value.(*ast.Arg_CompositeLit).CompositeLit
-			listLit := compLit.Value.(*ast.CompositeLit_ListLit).ListLit.List
-
-			var paths []*ast.BasicLit
-			switch v := listLit.(type) {
-			case *ast.ListLit_BasicList:
-				paths = append(paths, v.BasicList.Values...)
-			case *ast.ListLit_CompositeList:
-				cpaths := v.CompositeList.Values
-				paths = make([]*ast.BasicLit, len(cpaths))
-				for i, c := range cpaths {
-					paths[i] = c.Value.(*ast.CompositeLit_BasicLit).BasicLit
-				}
-			}
-
-			for _, p := range paths {
-				names = append(names, strings.Trim(p.Value, "\""))
-			}
+		for _, p := range importLits(direc) {
+			names = append(names, strings.Trim(p.Value, "\""))
 		}
 	}
 
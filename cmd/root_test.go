@@ -1,13 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gqlc/gqlc/gen"
@@ -265,6 +269,280 @@ type Bytes implements Iterator {
 	}
 }
 
+func TestRun_SchemaExtension(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Src  string
+	}{
+		{
+			Name: "Implicit",
+			Src: `extend schema @link(url: "https://specs.apollo.dev/federation/v2.0")
+
+directive @link(url: String) on SCHEMA
+
+type Query {
+	a: String
+}
+
+type Mutation {
+	b: String
+}`,
+		},
+		{
+			Name: "Explicit",
+			Src: `schema {
+	query: Query
+	mutation: Mutation
+}
+
+extend schema @link(url: "https://specs.apollo.dev/federation/v2.0")
+
+directive @link(url: String) on SCHEMA
+
+type Query {
+	a: String
+}
+
+type Mutation {
+	b: String
+}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			fs := afero.NewMemMapFs()
+			afero.WriteFile(fs, "/in/api.gql", []byte(testCase.Src), 0644)
+
+			var doc *ast.Document
+			g := newMockGenerator(subT)
+			g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, d *ast.Document, _ interface{}) error {
+				doc = d
+				return nil
+			})
+
+			cmd := &gqlcCmd{
+				cfg: &gqlcConfig{
+					geners: []generator{{Generator: g}},
+				},
+			}
+
+			err := cmd.run(fs, "/in/api.gql")
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if doc.Schema == nil {
+				subT.Fatal("expected the document to have a schema")
+			}
+			for _, d := range doc.Types {
+				if _, ok := d.Spec.(*ast.TypeDecl_TypeExtSpec); ok {
+					subT.Errorf("expected the schema extension to be merged")
+				}
+			}
+
+			ts := doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+			if len(ts.Directives) != 1 || ts.Directives[0].Name != "link" {
+				subT.Errorf("expected the schema to be @link, but got: %v", ts.Directives)
+			}
+
+			var ops []string
+			for _, f := range ts.Type.(*ast.TypeSpec_Schema).Schema.RootOps.List {
+				ops = append(ops, f.Name.Name+": "+f.Type.(*ast.Field_Ident).Ident.Name)
+			}
+			if strings.Join(ops, ", ") != "query: Query, mutation: Mutation" {
+				subT.Errorf("expected the query and mutation root operations, but got: %v", ops)
+			}
+		})
+	}
+}
+
+func TestRun_KeepGoing(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/keep/good.gql", []byte(`type Good {
+	name: String
+}`), 0644)
+	afero.WriteFile(fs, "/keep/bad.gql", []byte(`enum Bad {
+	NORTH
+	NORTH
+}`), 0644)
+	afero.WriteFile(fs, "/keep/dependent.gql", []byte(`@import(paths: ["bad.gql"])
+
+type Dependent {
+	bad: Bad
+}`), 0644)
+
+	args := []string{"good.gql", "bad.gql", "dependent.gql"}
+
+	t.Run("Off", func(subT *testing.T) {
+		g := newMockGenerator(subT)
+
+		cmd := &gqlcCmd{
+			cfg: &gqlcConfig{
+				geners: []generator{{Generator: g}},
+				ipaths: []string{"/keep"},
+			},
+		}
+
+		err := cmd.run(fs, args...)
+		if err == nil {
+			subT.Error("expected type error")
+		}
+	})
+
+	t.Run("On", func(subT *testing.T) {
+		g := newMockGenerator(subT)
+		g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, doc *ast.Document, _ interface{}) error {
+			if doc.Name != "good" {
+				subT.Errorf("expected only good to be generated, but got: %s", doc.Name)
+			}
+			return nil
+		})
+
+		cmd := &gqlcCmd{
+			cfg: &gqlcConfig{
+				geners:    []generator{{Generator: g}},
+				ipaths:    []string{"/keep"},
+				keepGoing: true,
+			},
+		}
+
+		err := cmd.run(fs, args...)
+		if err == nil {
+			subT.Fatal("expected skipped documents to be an error")
+		}
+
+		if !strings.Contains(err.Error(), "dependent") || strings.Contains(err.Error(), "good") {
+			subT.Errorf("expected only dependent to be skipped, but got: %s", err)
+		}
+	})
+}
+
+func TestRun_Jobs(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/jobs/a.gql", []byte(`type A {
+	name: String
+}`), 0644)
+	afero.WriteFile(fs, "/jobs/b.gql", []byte(`type B {
+	name: String
+}`), 0644)
+
+	args := []string{"a.gql", "b.gql"}
+
+	t.Run("Concurrent", func(subT *testing.T) {
+		// Each call waits for the other, which only returns if they run at once
+		var wg sync.WaitGroup
+		wg.Add(2)
+
+		g := newMockGenerator(subT)
+		g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_, _, _ interface{}) error {
+			wg.Done()
+
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+
+			select {
+			case <-done:
+				return nil
+			case <-time.After(5 * time.Second):
+				return fmt.Errorf("documents weren't generated concurrently")
+			}
+		}).Times(2)
+
+		cmd := &gqlcCmd{
+			cfg: &gqlcConfig{
+				geners: []generator{{Generator: g}},
+				ipaths: []string{"/jobs"},
+				jobs:   2,
+			},
+		}
+
+		err := cmd.run(fs, args...)
+		if err != nil {
+			subT.Error(err)
+		}
+	})
+
+	t.Run("Error", func(subT *testing.T) {
+		g := newMockGenerator(subT)
+		g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, doc *ast.Document, _ interface{}) error {
+			return fmt.Errorf("failed: %s", doc.Name)
+		}).MinTimes(1).MaxTimes(2)
+
+		cmd := &gqlcCmd{
+			cfg: &gqlcConfig{
+				geners: []generator{{Generator: g}},
+				ipaths: []string{"/jobs"},
+				jobs:   2,
+			},
+		}
+
+		err := cmd.run(fs, args...)
+		if err == nil || !strings.HasPrefix(err.Error(), "failed: ") {
+			subT.Errorf("expected generator error, but got: %v", err)
+		}
+	})
+
+	t.Run("Panic", func(subT *testing.T) {
+		g := newMockGenerator(subT)
+		g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, doc *ast.Document, _ interface{}) error {
+			panic("unexpected option")
+		}).MinTimes(1).MaxTimes(2)
+
+		cmd := &gqlcCmd{
+			cfg: &gqlcConfig{
+				geners: []generator{{Generator: g, name: "mock"}},
+				ipaths: []string{"/jobs"},
+				jobs:   2,
+			},
+		}
+
+		err := cmd.run(fs, args...)
+		if err == nil || !strings.Contains(err.Error(), "gqlc: recovered from unexpected panic: \"unexpected option\"") {
+			subT.Errorf("expected the panic to be recovered, but got: %v", err)
+		}
+	})
+}
+
+func TestRun_Documents(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/docs/a.gql", []byte("type A { b: String }"), 0644)
+	afero.WriteFile(fs, "/docs/b.gql", []byte("type B { a: String }"), 0644)
+
+	var names []string
+	g := newMockGenerator(t)
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).DoAndReturn(func(ctx context.Context, doc *ast.Document, _ interface{}) error {
+		var docs []string
+		for _, d := range gen.Documents(gen.Context(ctx)) {
+			docs = append(docs, d.Name)
+		}
+		sort.Strings(docs)
+		names = append(names, doc.Name+": "+strings.Join(docs, ","))
+		return nil
+	})
+
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners: []generator{{Generator: g}},
+			ipaths: []string{"/docs"},
+			jobs:   1,
+		},
+	}
+
+	err := cmd.run(fs, "a.gql", "b.gql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(names)
+	if ex := "a: a,b|b: a,b"; strings.Join(names, "|") != ex {
+		t.Errorf("expected every document to be carried while generating each, but got: %v", names)
+	}
+}
+
 var testIntroResp = []byte(`{
 	"data": {
 		"__schema": {
@@ -0,0 +1,66 @@
+// schema.go defines the implicit schema of documents which only extend it
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/gqlc/compiler"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+// implicitSchema defines the schema of each document which extends it,
+// e.g. extend schema @link(url: "..."), without defining it. Such a schema
+// is implicit, i.e. its root operations are the types named Query,
+// Mutation and Subscription, so it's defined with them for the extension
+// to be merged into.
+//
+func implicitSchema(ir compiler.IR) compiler.IR {
+	for doc, types := range ir {
+		decls := types["schema"]
+		if len(decls) == 0 {
+			continue
+		}
+
+		defined := false
+		for _, decl := range decls {
+			_, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+			defined = defined || ok
+		}
+		if defined {
+			continue
+		}
+
+		var rootOps []*ast.Field
+		for _, op := range []string{"Query", "Mutation", "Subscription"} {
+			tdecls, ok := types[op]
+			if !ok || tdecls[0].Tok != token.Token_TYPE {
+				continue
+			}
+
+			rootOps = append(rootOps, &ast.Field{
+				Name: &ast.Ident{Name: strings.ToLower(op)},
+				Type: &ast.Field_Ident{Ident: &ast.Ident{Name: op}},
+			})
+		}
+
+		// Without a Query type there's no implicit schema to extend, which
+		// type checking reports.
+		if len(rootOps) == 0 || rootOps[0].Name.Name != "query" {
+			continue
+		}
+
+		schema := &ast.TypeDecl{
+			Tok: token.Token_SCHEMA,
+			Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+				Type: &ast.TypeSpec_Schema{Schema: &ast.SchemaType{
+					RootOps: &ast.FieldList{List: rootOps},
+				}},
+			}},
+		}
+		types["schema"] = append([]*ast.TypeDecl{schema}, decls...)
+		doc.Schema = schema
+	}
+	return ir
+}
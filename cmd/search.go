@@ -0,0 +1,383 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/scanner"
+
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func (c *CommandLine) newSearchCmd() *baseCmd {
+	gc := &gqlcCmd{
+		cfg: &gqlcConfig{
+			client: defaultClient,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "search query files...",
+		Short: "Search a schema for members matching a structural query",
+		Long: `search finds schema members, i.e. types, fields, arguments, input fields,
+enum values and directives, which match a query and prints their schema
+coordinates and positions.
+
+A query is made of predicates combined with &&, || and !, and grouped
+with parentheses. Names and types may contain * wildcards. A type with
+list or non-null modifiers is matched exactly, otherwise only the named
+type is compared.
+
+Predicates:
+	types(name: "...", kind: "object")   type definitions
+	fields(name: "...", type: "...")     object and interface fields
+	args(name: "...", type: "...")       field and directive arguments
+	inputFields(name: "...", type: "...") input object fields
+	enumValues(name: "...")              enum values
+	directives(name: "...")              directive definitions
+	in("Type")                           members of the given type
+	hasDirective("name")                 members annotated with @name
+	description("text")                  members whose description contains text`,
+		Example: `gqlc search 'fields(type: "DateTime") && !hasDirective("deprecated")' schema.gql`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			err := cobra.MinimumNArgs(2)(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			return validateFilenames(cmd, args[1:])
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) (err error) {
+			gc.cfg.ipaths, err = cmd.Flags().GetStringSlice("import_path")
+			return
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return gc.search(c.fs, cmd, args[0], args[1:]...)
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringSliceP("import_path", "I", []string{"."}, `Specify the directory in which to search for
+imports.  May be specified multiple times;
+directories will be searched in order.  If not
+given, the current working directory is used.`)
+
+	return &baseCmd{Command: cmd}
+}
+
+func (c *gqlcCmd) search(fs afero.Fs, cmd *cobra.Command, query string, filenames ...string) error {
+	pred, err := parseQuery(query)
+	if err != nil {
+		return err
+	}
+
+	dset := token.NewDocSet()
+	docMap := make(map[string]*ast.Document, len(filenames))
+	err = c.parseInputFiles(fs, dset, docMap, filenames...)
+	if err != nil {
+		return err
+	}
+
+	docs := make([]*ast.Document, 0, len(docMap))
+	for _, doc := range docMap {
+		docs = append(docs, doc)
+	}
+
+	var matches []*symbol
+	for _, sym := range collectSymbols(docs) {
+		if pred(sym) {
+			matches = append(matches, sym)
+		}
+	}
+
+	positions := make(map[*symbol]token.Position, len(matches))
+	for _, sym := range matches {
+		positions[sym] = dset.Position(sym.pos)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := positions[matches[i]], positions[matches[j]]
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+
+	out := cmd.OutOrStdout()
+	for _, sym := range matches {
+		fmt.Fprintf(out, "%s: %s\n", positions[sym], sym.coord)
+	}
+	return nil
+}
+
+// predicate reports whether a symbol matches a search query.
+type predicate func(*symbol) bool
+
+// queryParser is a recursive descent parser for search queries.
+//
+// 	expr    = and { "||" and }
+// 	and     = unary { "&&" unary }
+// 	unary   = "!" unary | primary
+// 	primary = "(" expr ")" | ident "(" [ arg { "," arg } ] ")"
+// 	arg     = [ ident ":" ] string
+//
+type queryParser struct {
+	scanner.Scanner
+	tok rune
+}
+
+// parseQuery compiles a search query into a predicate.
+func parseQuery(query string) (pred predicate, err error) {
+	p := new(queryParser)
+	p.Init(strings.NewReader(query))
+	p.Mode = scanner.ScanIdents | scanner.ScanStrings | scanner.ScanRawStrings
+	p.Error = func(_ *scanner.Scanner, msg string) { p.errorf("%s", msg) }
+
+	defer func() {
+		if r := recover(); r != nil {
+			perr, ok := r.(queryError)
+			if !ok {
+				panic(r)
+			}
+			err = perr
+		}
+	}()
+
+	p.next()
+	pred = p.parseExpr()
+	if p.tok != scanner.EOF {
+		p.errorf("unexpected %s", p.TokenText())
+	}
+	return
+}
+
+type queryError string
+
+func (e queryError) Error() string { return string(e) }
+
+func (p *queryParser) errorf(format string, args ...interface{}) {
+	panic(queryError(fmt.Sprintf("gqlc: invalid search query at column %d: %s", p.Position.Column, fmt.Sprintf(format, args...))))
+}
+
+func (p *queryParser) next() { p.tok = p.Scan() }
+
+func (p *queryParser) expect(tok rune) {
+	if p.tok != tok {
+		p.errorf("expected %q but found %q", tok, p.TokenText())
+	}
+	p.next()
+}
+
+func (p *queryParser) parseExpr() predicate {
+	left := p.parseAnd()
+	for p.tok == '|' {
+		p.next()
+		p.expect('|')
+
+		l, r := left, p.parseAnd()
+		left = func(s *symbol) bool { return l(s) || r(s) }
+	}
+	return left
+}
+
+func (p *queryParser) parseAnd() predicate {
+	left := p.parseUnary()
+	for p.tok == '&' {
+		p.next()
+		p.expect('&')
+
+		l, r := left, p.parseUnary()
+		left = func(s *symbol) bool { return l(s) && r(s) }
+	}
+	return left
+}
+
+func (p *queryParser) parseUnary() predicate {
+	if p.tok == '!' {
+		p.next()
+
+		pred := p.parseUnary()
+		return func(s *symbol) bool { return !pred(s) }
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() predicate {
+	if p.tok == '(' {
+		p.next()
+		pred := p.parseExpr()
+		p.expect(')')
+		return pred
+	}
+
+	if p.tok != scanner.Ident {
+		p.errorf("expected predicate but found %q", p.TokenText())
+	}
+	name := p.TokenText()
+	p.next()
+
+	args := make(map[string]string)
+	var pos []string
+
+	p.expect('(')
+	for p.tok != ')' {
+		if len(args)+len(pos) > 0 {
+			p.expect(',')
+		}
+
+		key := ""
+		if p.tok == scanner.Ident {
+			key = p.TokenText()
+			p.next()
+			p.expect(':')
+		}
+
+		if p.tok != scanner.String && p.tok != scanner.RawString {
+			p.errorf("expected string but found %q", p.TokenText())
+		}
+		val, err := strconv.Unquote(p.TokenText())
+		if err != nil {
+			p.errorf("%s", err)
+		}
+		p.next()
+
+		if key == "" {
+			pos = append(pos, val)
+			continue
+		}
+		args[key] = val
+	}
+	p.next()
+
+	return p.newPredicate(name, args, pos)
+}
+
+func (p *queryParser) newPredicate(name string, args map[string]string, pos []string) predicate {
+	// Positional arguments are only allowed for single argument predicates
+	single := func() string {
+		if len(pos) > 1 || len(args) > 0 || len(pos) == 0 {
+			p.errorf("%s expects a single string argument", name)
+		}
+		return pos[0]
+	}
+
+	members := func(kind symbolKind, allowed ...string) predicate {
+		if len(pos) > 0 {
+			p.errorf("%s only accepts named arguments", name)
+		}
+		for k := range args {
+			if !contains(allowed, k) {
+				p.errorf("unknown argument to %s: %s", name, k)
+			}
+		}
+
+		return func(s *symbol) bool {
+			if s.kind != kind {
+				return false
+			}
+			if n, ok := args["name"]; ok && !matchGlob(n, s.name) {
+				return false
+			}
+			if t, ok := args["type"]; ok && !matchType(t, s.typ) {
+				return false
+			}
+			if k, ok := args["kind"]; ok && !strings.EqualFold(k, tokenKind(s.tok)) {
+				return false
+			}
+			return true
+		}
+	}
+
+	switch name {
+	case "types":
+		return members(typeSym, "name", "kind")
+	case "fields":
+		return members(fieldSym, "name", "type")
+	case "args":
+		return members(argSym, "name", "type")
+	case "inputFields":
+		return members(inputFieldSym, "name", "type")
+	case "enumValues":
+		return members(enumValueSym, "name")
+	case "directives":
+		return members(directiveSym, "name")
+	case "in":
+		parent := single()
+		return func(s *symbol) bool { return s.parent != "" && matchGlob(parent, s.parent) }
+	case "hasDirective":
+		dir := strings.TrimPrefix(single(), "@")
+		return func(s *symbol) bool {
+			for _, d := range s.dirs {
+				if matchGlob(dir, d.Name) {
+					return true
+				}
+			}
+			return false
+		}
+	case "description":
+		text := single()
+		return func(s *symbol) bool { return strings.Contains(s.doc.Text(), text) }
+	}
+
+	p.errorf("unknown predicate: %s", name)
+	return nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenKind returns the lowercase name of a type declaration token e.g. object.
+func tokenKind(tok token.Token) string {
+	if tok == token.Token_TYPE {
+		return "object"
+	}
+	return strings.ToLower(tok.String())
+}
+
+// matchType matches a type pattern against a GraphQL type. Patterns
+// with modifiers are matched against the full type, otherwise only
+// the named type is considered.
+//
+func matchType(pattern string, typ interface{}) bool {
+	if typ == nil {
+		return false
+	}
+
+	if strings.ContainsAny(pattern, "[]!") {
+		return matchGlob(pattern, typeString(typ))
+	}
+	return matchGlob(pattern, namedType(typ))
+}
+
+// matchGlob reports whether s matches the pattern, where * matches any sequence of characters.
+func matchGlob(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+var searchSchema = []byte(`directive @deprecated(reason: String = "No longer supported") on FIELD_DEFINITION | ENUM_VALUE
+
+scalar DateTime
+
+"An event on a calendar"
+type Event {
+	id: ID!
+	start: DateTime!
+	end: DateTime @deprecated(reason: "use duration")
+	history(since: DateTime): [DateTime!]
+}
+
+enum Status {
+	OPEN
+	CLOSED @deprecated
+}
+
+input EventFilter {
+	after: DateTime
+	status: Status = OPEN
+}
+`)
+
+func TestParseQuery(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		Query string
+		Err   bool
+	}{
+		{Name: "Single", Query: `fields(type: "DateTime")`},
+		{Name: "Positional", Query: `hasDirective("deprecated")`},
+		{Name: "RawString", Query: "description(`event`)"},
+		{Name: "Combined", Query: `(fields() || args()) && !hasDirective("deprecated")`},
+		{Name: "UnknownPredicate", Query: `methods()`, Err: true},
+		{Name: "UnknownArg", Query: `enumValues(type: "Int")`, Err: true},
+		{Name: "MissingArg", Query: `in()`, Err: true},
+		{Name: "Unterminated", Query: `fields(name: "a"`, Err: true},
+		{Name: "SingleAmpersand", Query: `fields() & args()`, Err: true},
+		{Name: "Trailing", Query: `fields() args()`, Err: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			_, err := parseQuery(testCase.Query)
+			if testCase.Err && err == nil {
+				subT.Error("expected an error")
+			}
+			if !testCase.Err && err != nil {
+				subT.Error(err)
+			}
+		})
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	testCases := []struct {
+		Pattern string
+		S       string
+		Match   bool
+	}{
+		{"Date", "Date", true},
+		{"Date", "DateTime", false},
+		{"Date*", "DateTime", true},
+		{"*Time", "DateTime", true},
+		{"D*e*e", "DateTime", true},
+		{"*x*", "DateTime", false},
+		{"*", "", true},
+	}
+
+	for _, testCase := range testCases {
+		if m := matchGlob(testCase.Pattern, testCase.S); m != testCase.Match {
+			t.Errorf("matchGlob(%q, %q): expected %v, but got %v", testCase.Pattern, testCase.S, testCase.Match, m)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	err := afero.WriteFile(fs, "/schema.gql", searchSchema, 0644)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	testCases := []struct {
+		Name  string
+		Query string
+		Out   string
+	}{
+		{
+			Name:  "FieldsByType",
+			Query: `fields(type: "DateTime") && !hasDirective("deprecated")`,
+			Out: `schema.gql:8:2: Event.start
+schema.gql:10:2: Event.history
+`,
+		},
+		{
+			Name:  "ExactType",
+			Query: `fields(type: "DateTime!") || args(type: "DateTime")`,
+			Out: `schema.gql:8:2: Event.start
+schema.gql:10:10: Event.history(since:)
+`,
+		},
+		{
+			Name:  "Deprecated",
+			Query: `hasDirective("@deprecated")`,
+			Out: `schema.gql:9:2: Event.end
+schema.gql:15:2: Status.CLOSED
+`,
+		},
+		{
+			Name:  "TypesByKind",
+			Query: `types(kind: "input") || types(kind: "scalar") || directives()`,
+			Out: `schema.gql:1:12: @deprecated
+schema.gql:3:8: DateTime
+schema.gql:18:7: EventFilter
+`,
+		},
+		{
+			Name:  "In",
+			Query: `in("Event*") && (inputFields(name: "s*") || fields(name: "*d"))`,
+			Out: `schema.gql:7:2: Event.id
+schema.gql:9:2: Event.end
+schema.gql:20:2: EventFilter.status
+`,
+		},
+		{
+			Name:  "Description",
+			Query: `description("calendar")`,
+			Out: `schema.gql:6:6: Event
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			var b bytes.Buffer
+
+			c := NewCLI(WithFS(fs))
+			cmd := c.newSearchCmd()
+			cmd.SetOut(&b)
+			cmd.SetArgs([]string{testCase.Query, "/schema.gql"})
+
+			err := cmd.Execute()
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if b.String() != testCase.Out {
+				subT.Errorf("expected:\n%s\nbut got:\n%s", testCase.Out, b.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+// symbolKind classifies a schema member.
+type symbolKind int
+
+const (
+	typeSym symbolKind = iota
+	fieldSym
+	argSym
+	inputFieldSym
+	enumValueSym
+	directiveSym
+)
+
+// symbol is a single named member of a schema e.g. a type, field or argument.
+type symbol struct {
+	kind symbolKind
+
+	// coord is the schema coordinate of the member e.g. Query.search(text:)
+	coord string
+	name  string
+
+	// parent is the name of the enclosing type or directive
+	parent string
+
+	// tok is the declaration token of the enclosing type
+	tok token.Token
+
+	// typ is the GraphQL type of a field, argument or input field
+	typ interface{}
+
+	// def is the default value of an argument or input field
+	def interface{}
+
+	doc  *ast.DocGroup
+	dirs []*ast.DirectiveLit
+	pos  token.Pos
+}
+
+// collectSymbols builds a symbol table for the given documents.
+// Members of type extensions are included alongside their type.
+//
+func collectSymbols(docs []*ast.Document) (syms []*symbol) {
+	for _, doc := range docs {
+		for _, d := range doc.Types {
+			var ts *ast.TypeSpec
+			switch v := d.Spec.(type) {
+			case *ast.TypeDecl_TypeSpec:
+				ts = v.TypeSpec
+				if _, ok := ts.Type.(*ast.TypeSpec_Schema); ok {
+					continue
+				}
+
+				coord := ts.Name.Name
+				if d.Tok == token.Token_DIRECTIVE {
+					coord = "@" + coord
+				}
+
+				kind := typeSym
+				if d.Tok == token.Token_DIRECTIVE {
+					kind = directiveSym
+				}
+
+				syms = append(syms, &symbol{
+					kind:  kind,
+					coord: coord,
+					name:  ts.Name.Name,
+					tok:   d.Tok,
+					doc:   d.Doc,
+					dirs:  ts.Directives,
+					pos:   token.Pos(ts.Name.NamePos),
+				})
+			case *ast.TypeDecl_TypeExtSpec:
+				ts = v.TypeExtSpec.Type
+				if ts == nil || ts.Name == nil {
+					continue
+				}
+			}
+
+			syms = append(syms, memberSymbols(d.Tok, ts)...)
+		}
+	}
+	return
+}
+
+func memberSymbols(tok token.Token, ts *ast.TypeSpec) (syms []*symbol) {
+	name := ts.Name.Name
+
+	fields := func(list *ast.FieldList) {
+		if list == nil {
+			return
+		}
+
+		for _, f := range list.List {
+			coord := name + "." + f.Name.Name
+			syms = append(syms, &symbol{
+				kind:   fieldSym,
+				coord:  coord,
+				name:   f.Name.Name,
+				parent: name,
+				tok:    tok,
+				typ:    fieldType(f),
+				doc:    f.Doc,
+				dirs:   f.Directives,
+				pos:    token.Pos(f.Name.NamePos),
+			})
+
+			if f.Args != nil {
+				syms = append(syms, argSymbols(tok, name, coord, f.Args.List)...)
+			}
+		}
+	}
+
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Object:
+		fields(v.Object.Fields)
+	case *ast.TypeSpec_Interface:
+		fields(v.Interface.Fields)
+	case *ast.TypeSpec_Enum:
+		if v.Enum.Values == nil {
+			break
+		}
+
+		for _, val := range v.Enum.Values.List {
+			syms = append(syms, &symbol{
+				kind:   enumValueSym,
+				coord:  name + "." + val.Name.Name,
+				name:   val.Name.Name,
+				parent: name,
+				tok:    tok,
+				doc:    val.Doc,
+				dirs:   val.Directives,
+				pos:    token.Pos(val.Name.NamePos),
+			})
+		}
+	case *ast.TypeSpec_Input:
+		if v.Input.Fields == nil {
+			break
+		}
+
+		for _, f := range v.Input.Fields.List {
+			syms = append(syms, &symbol{
+				kind:   inputFieldSym,
+				coord:  name + "." + f.Name.Name,
+				name:   f.Name.Name,
+				parent: name,
+				tok:    tok,
+				typ:    inputValueType(f),
+				def:    f.Default,
+				doc:    f.Doc,
+				dirs:   f.Directives,
+				pos:    token.Pos(f.Name.NamePos),
+			})
+		}
+	case *ast.TypeSpec_Directive:
+		if v.Directive.Args != nil {
+			syms = append(syms, argSymbols(tok, name, "@"+name, v.Directive.Args.List)...)
+		}
+	}
+	return
+}
+
+func argSymbols(tok token.Token, parent, coord string, args []*ast.InputValue) (syms []*symbol) {
+	for _, a := range args {
+		syms = append(syms, &symbol{
+			kind:   argSym,
+			coord:  coord + "(" + a.Name.Name + ":)",
+			name:   a.Name.Name,
+			parent: parent,
+			tok:    tok,
+			typ:    inputValueType(a),
+			def:    a.Default,
+			doc:    a.Doc,
+			dirs:   a.Directives,
+			pos:    token.Pos(a.Name.NamePos),
+		})
+	}
+	return
+}
+
+func fieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func inputValueType(a *ast.InputValue) interface{} {
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+// typeString returns the GraphQL notation of a type e.g. [String!]
+func typeString(typ interface{}) string {
+	var b strings.Builder
+	writeTypeString(&b, typ)
+	return b.String()
+}
+
+func writeTypeString(b *strings.Builder, typ interface{}) {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		b.WriteString(v.Name)
+	case *ast.List:
+		b.WriteByte('[')
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			writeTypeString(b, w.Ident)
+		case *ast.List_List:
+			writeTypeString(b, w.List)
+		case *ast.List_NonNull:
+			writeTypeString(b, w.NonNull)
+		}
+		b.WriteByte(']')
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			writeTypeString(b, w.Ident)
+		case *ast.NonNull_List:
+			writeTypeString(b, w.List)
+		}
+		b.WriteByte('!')
+	}
+}
+
+// namedType returns the name of the underlying named type.
+func namedType(typ interface{}) string {
+	id := namedIdent(typ)
+	if id == nil {
+		return ""
+	}
+	return id.Name
+}
+
+// namedIdent returns the identifier of the underlying named type.
+func namedIdent(typ interface{}) *ast.Ident {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		return v
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			return w.Ident
+		case *ast.List_List:
+			return namedIdent(w.List)
+		case *ast.List_NonNull:
+			return namedIdent(w.NonNull)
+		}
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return w.Ident
+		case *ast.NonNull_List:
+			return namedIdent(w.List)
+		}
+	}
+	return nil
+}
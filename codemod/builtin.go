@@ -0,0 +1,456 @@
+package codemod
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gqlc/compiler"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+func init() {
+	Register("addDirective", func(args map[string]interface{}) (Codemod, error) {
+		if err := checkArgs(args, "directive", "types", "fields"); err != nil {
+			return nil, err
+		}
+
+		directive, err := stringArg(args, "directive", true)
+		if err != nil {
+			return nil, err
+		}
+		types, err := stringArg(args, "types", false)
+		if err != nil {
+			return nil, err
+		}
+		fields, err := stringArg(args, "fields", false)
+		if err != nil {
+			return nil, err
+		}
+
+		return AddDirective(directive, types, fields)
+	})
+
+	Register("prefixTypes", func(args map[string]interface{}) (Codemod, error) {
+		if err := checkArgs(args, "prefix", "types"); err != nil {
+			return nil, err
+		}
+
+		prefix, err := stringArg(args, "prefix", true)
+		if err != nil {
+			return nil, err
+		}
+		types, err := stringArg(args, "types", false)
+		if err != nil {
+			return nil, err
+		}
+
+		return PrefixTypes(prefix, types)
+	})
+
+	Register("stripFields", func(args map[string]interface{}) (Codemod, error) {
+		if err := checkArgs(args, "directive", "fields"); err != nil {
+			return nil, err
+		}
+
+		directive, err := stringArg(args, "directive", false)
+		if err != nil {
+			return nil, err
+		}
+		fields, err := stringArg(args, "fields", false)
+		if err != nil {
+			return nil, err
+		}
+
+		return StripFields(directive, fields)
+	})
+}
+
+// match reports whether name matches the pattern, as defined by path.Match.
+// An empty pattern matches nothing.
+//
+func match(pattern, name string) bool {
+	if pattern == "" {
+		return false
+	}
+
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+func validPattern(pattern string) error {
+	if pattern == "" {
+		return nil
+	}
+
+	_, err := path.Match(pattern, "")
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %s", pattern)
+	}
+	return nil
+}
+
+// AddDirective returns a codemod which applies a directive, given in SDL
+// form e.g. @auth(requires: ADMIN), to every type whose name matches the
+// types pattern and every field whose coordinate e.g. User.email matches
+// the fields pattern. Members which already have the directive are left
+// as is.
+//
+func AddDirective(directive, types, fields string) (Codemod, error) {
+	if types == "" && fields == "" {
+		return nil, fmt.Errorf("one of types or fields must be provided")
+	}
+	if err := validPattern(types); err != nil {
+		return nil, err
+	}
+	if err := validPattern(fields); err != nil {
+		return nil, err
+	}
+
+	dir, err := parseDirective(directive)
+	if err != nil {
+		return nil, err
+	}
+
+	add := func(dirs []*ast.DirectiveLit) []*ast.DirectiveLit {
+		for _, d := range dirs {
+			if d.Name == dir.Name {
+				return dirs
+			}
+		}
+		return append(dirs, proto.Clone(dir).(*ast.DirectiveLit))
+	}
+
+	return Func(func(ir compiler.IR) error {
+		walkSpecs(ir, func(ts *ast.TypeSpec) {
+			name := specName(ts)
+			if match(types, name) {
+				ts.Directives = add(ts.Directives)
+			}
+			if fields == "" {
+				return
+			}
+
+			walkMembers(ts, func(f *ast.Field) {
+				if match(fields, name+"."+f.Name.Name) {
+					f.Directives = add(f.Directives)
+				}
+			}, func(f *ast.InputValue) {
+				if match(fields, name+"."+f.Name.Name) {
+					f.Directives = add(f.Directives)
+				}
+			})
+		})
+		return nil
+	}), nil
+}
+
+// parseDirective parses a single directive application e.g. @auth(requires: ADMIN).
+func parseDirective(s string) (*ast.DirectiveLit, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "@") {
+		s = "@" + s
+	}
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "codemod", strings.NewReader("scalar Codemod "+s), 0)
+	if err != nil || len(doc.Types) != 1 {
+		return nil, fmt.Errorf("invalid directive: %s", s)
+	}
+
+	dirs := doc.Types[0].Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Directives
+	if len(dirs) != 1 {
+		return nil, fmt.Errorf("expected a single directive but got: %s", s)
+	}
+	return dirs[0], nil
+}
+
+// PrefixTypes returns a codemod which prefixes the name of every type
+// matching the types pattern, which defaults to all types, along with
+// every reference to them.
+//
+func PrefixTypes(prefix, types string) (Codemod, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("prefix must not be empty")
+	}
+	if types == "" {
+		types = "*"
+	}
+	if err := validPattern(types); err != nil {
+		return nil, err
+	}
+
+	return Func(func(ir compiler.IR) error {
+		renames := make(map[string]string)
+		walkSpecs(ir, func(ts *ast.TypeSpec) {
+			if _, ok := ts.Type.(*ast.TypeSpec_Directive); ok {
+				return
+			}
+
+			if name := specName(ts); name != "" && match(types, name) {
+				renames[name] = prefix + name
+			}
+		})
+
+		for _, decls := range ir {
+			for old, renamed := range renames {
+				if _, exists := decls[renamed]; exists {
+					return fmt.Errorf("type already exists: %s", renamed)
+				}
+
+				ds, ok := decls[old]
+				if !ok {
+					continue
+				}
+
+				delete(decls, old)
+				decls[renamed] = ds
+			}
+		}
+
+		rename := func(id *ast.Ident) {
+			if id == nil {
+				return
+			}
+			if renamed, ok := renames[id.Name]; ok {
+				id.Name = renamed
+			}
+		}
+
+		walkSpecs(ir, func(ts *ast.TypeSpec) {
+			if _, ok := ts.Type.(*ast.TypeSpec_Directive); !ok {
+				rename(ts.Name)
+			}
+
+			switch v := ts.Type.(type) {
+			case *ast.TypeSpec_Schema:
+				if v.Schema.RootOps != nil {
+					for _, f := range v.Schema.RootOps.List {
+						rename(namedIdent(f.Type))
+					}
+				}
+			case *ast.TypeSpec_Object:
+				for _, id := range v.Object.Interfaces {
+					rename(id)
+				}
+			case *ast.TypeSpec_Union:
+				for _, id := range v.Union.Members {
+					rename(id)
+				}
+			case *ast.TypeSpec_Directive:
+				if v.Directive.Args != nil {
+					for _, a := range v.Directive.Args.List {
+						rename(namedIdent(a.Type))
+					}
+				}
+			case *ast.TypeSpec_Enum:
+				return
+			}
+
+			walkMembers(ts, func(f *ast.Field) {
+				rename(namedIdent(f.Type))
+				if f.Args != nil {
+					for _, a := range f.Args.List {
+						rename(namedIdent(a.Type))
+					}
+				}
+			}, func(f *ast.InputValue) {
+				rename(namedIdent(f.Type))
+			})
+		})
+		return nil
+	}), nil
+}
+
+// StripFields returns a codemod which removes every field, input field,
+// argument and enum value that either has the given directive applied or
+// whose coordinate matches the fields pattern. Arguments are matched by
+// coordinates of the form Type.field(arg:).
+//
+func StripFields(directive, fields string) (Codemod, error) {
+	directive = strings.TrimPrefix(directive, "@")
+	if directive == "" && fields == "" {
+		return nil, fmt.Errorf("one of directive or fields must be provided")
+	}
+	if err := validPattern(fields); err != nil {
+		return nil, err
+	}
+
+	strip := func(coord string, dirs []*ast.DirectiveLit) bool {
+		if match(fields, coord) {
+			return true
+		}
+
+		for _, d := range dirs {
+			if d.Name == directive {
+				return true
+			}
+		}
+		return false
+	}
+
+	stripArgs := func(coord string, args *ast.InputValueList) {
+		if args == nil {
+			return
+		}
+
+		n := 0
+		for _, a := range args.List {
+			if !strip(coord+"("+a.Name.Name+":)", a.Directives) {
+				args.List[n] = a
+				n++
+			}
+		}
+		args.List = args.List[:n]
+	}
+
+	stripFields := func(parent string, fields *ast.FieldList) {
+		if fields == nil {
+			return
+		}
+
+		n := 0
+		for _, f := range fields.List {
+			coord := parent + "." + f.Name.Name
+			if strip(coord, f.Directives) {
+				continue
+			}
+
+			stripArgs(coord, f.Args)
+			fields.List[n] = f
+			n++
+		}
+		fields.List = fields.List[:n]
+	}
+
+	return Func(func(ir compiler.IR) error {
+		walkSpecs(ir, func(ts *ast.TypeSpec) {
+			name := specName(ts)
+
+			switch v := ts.Type.(type) {
+			case *ast.TypeSpec_Object:
+				stripFields(name, v.Object.Fields)
+			case *ast.TypeSpec_Interface:
+				stripFields(name, v.Interface.Fields)
+			case *ast.TypeSpec_Enum:
+				stripFields(name, v.Enum.Values)
+			case *ast.TypeSpec_Input:
+				if v.Input.Fields == nil {
+					break
+				}
+
+				n := 0
+				for _, f := range v.Input.Fields.List {
+					if !strip(name+"."+f.Name.Name, f.Directives) {
+						v.Input.Fields.List[n] = f
+						n++
+					}
+				}
+				v.Input.Fields.List = v.Input.Fields.List[:n]
+			}
+		})
+		return nil
+	}), nil
+}
+
+// specName returns the name of a type spec, which is empty for the schema.
+func specName(ts *ast.TypeSpec) string {
+	if ts.Name == nil {
+		return ""
+	}
+	return ts.Name.Name
+}
+
+// walkSpecs calls fn for every type spec, including extensions, in the IR.
+func walkSpecs(ir compiler.IR, fn func(*ast.TypeSpec)) {
+	for _, decls := range ir {
+		for _, ds := range decls {
+			for _, d := range ds {
+				var ts *ast.TypeSpec
+				switch v := d.Spec.(type) {
+				case *ast.TypeDecl_TypeSpec:
+					ts = v.TypeSpec
+				case *ast.TypeDecl_TypeExtSpec:
+					ts = v.TypeExtSpec.Type
+				}
+
+				if ts == nil {
+					continue
+				}
+				if _, ok := ts.Type.(*ast.TypeSpec_Schema); !ok && ts.Name == nil {
+					continue
+				}
+
+				fn(ts)
+			}
+		}
+	}
+}
+
+// walkMembers calls fieldFn for every object and interface field and
+// inputFn for every input field of the given type.
+//
+func walkMembers(ts *ast.TypeSpec, fieldFn func(*ast.Field), inputFn func(*ast.InputValue)) {
+	var fields *ast.FieldList
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Object:
+		fields = v.Object.Fields
+	case *ast.TypeSpec_Interface:
+		fields = v.Interface.Fields
+	case *ast.TypeSpec_Input:
+		if v.Input.Fields == nil {
+			return
+		}
+
+		for _, f := range v.Input.Fields.List {
+			inputFn(f)
+		}
+		return
+	}
+
+	if fields == nil {
+		return
+	}
+	for _, f := range fields.List {
+		fieldFn(f)
+	}
+}
+
+// namedIdent returns the identifier of the named type underlying
+// a field or input value type.
+//
+func namedIdent(typ interface{}) *ast.Ident {
+	switch v := typ.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return namedIdent(v.List)
+	case *ast.Field_NonNull:
+		return namedIdent(v.NonNull)
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return namedIdent(v.List)
+	case *ast.InputValue_NonNull:
+		return namedIdent(v.NonNull)
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			return w.Ident
+		case *ast.List_List:
+			return namedIdent(w.List)
+		case *ast.List_NonNull:
+			return namedIdent(w.NonNull)
+		}
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return w.Ident
+		case *ast.NonNull_List:
+			return namedIdent(w.List)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Package codemod contains transformations which are applied to
+// the IR of a set of documents before any generators are run.
+//
+package codemod
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gqlc/compiler"
+)
+
+// Codemod transforms the IR of a set of documents in place.
+type Codemod interface {
+	// Apply applies the transformation to the given IR.
+	Apply(ir compiler.IR) error
+}
+
+// Func is an adapter to allow the use of ordinary functions as a Codemod.
+type Func func(compiler.IR) error
+
+// Apply calls f(ir).
+func (f Func) Apply(ir compiler.IR) error { return f(ir) }
+
+// Pipeline applies a sequence of codemods in order.
+type Pipeline []Codemod
+
+// Apply applies each codemod in order and stops at the first error.
+func (p Pipeline) Apply(ir compiler.IR) error {
+	for _, c := range p {
+		if err := c.Apply(ir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Factory creates a Codemod from its arguments.
+type Factory func(args map[string]interface{}) (Codemod, error)
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]Factory)
+)
+
+// Register makes a codemod available by name e.g. for use in a gqlc.yaml
+// file. It panics if a codemod with the same name is already registered.
+//
+func Register(name string, f Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic("codemod: Register called twice for codemod: " + name)
+	}
+	registry[name] = f
+}
+
+// Names returns the sorted names of all registered codemods.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New returns the named codemod configured with the given arguments.
+func New(name string, args map[string]interface{}) (Codemod, error) {
+	mu.RLock()
+	f, ok := registry[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("codemod: unknown codemod: %s", name)
+	}
+
+	c, err := f(args)
+	if err != nil {
+		return nil, fmt.Errorf("codemod: %s: %w", name, err)
+	}
+
+	return named{name: name, Codemod: c}, nil
+}
+
+// named attributes any errors from a codemod to its name.
+type named struct {
+	Codemod
+
+	name string
+}
+
+func (n named) Apply(ir compiler.IR) error {
+	if err := n.Codemod.Apply(ir); err != nil {
+		return fmt.Errorf("codemod: %s: %w", n.name, err)
+	}
+	return nil
+}
+
+// stringArg returns the named string argument.
+func stringArg(args map[string]interface{}, name string, required bool) (string, error) {
+	v, ok := args[name]
+	if !ok || v == nil {
+		if required {
+			return "", fmt.Errorf("missing required argument: %s", name)
+		}
+		return "", nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("expected argument %s to be a string but got: %T", name, v)
+	}
+	return s, nil
+}
+
+// checkArgs reports an error for any unknown arguments.
+func checkArgs(args map[string]interface{}, known ...string) error {
+	for name := range args {
+		var ok bool
+		for _, k := range known {
+			ok = ok || k == name
+		}
+		if !ok {
+			return fmt.Errorf("unknown argument: %s", name)
+		}
+	}
+	return nil
+}
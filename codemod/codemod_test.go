@@ -0,0 +1,322 @@
+package codemod
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/compiler"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+const testSchema = `schema {
+	query: Query
+}
+
+directive @internal on FIELD_DEFINITION | INPUT_FIELD_DEFINITION | ENUM_VALUE | ARGUMENT_DEFINITION
+
+type Query {
+	user(id: ID!, debug: Boolean @internal): User
+	search(in: SearchInput): [Result!]!
+}
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	email: String
+	password: String @internal
+}
+
+union Result = User
+
+input SearchInput {
+	text: String
+	trace: Boolean @internal
+}
+
+enum Role {
+	ADMIN
+	DEBUG @internal
+}
+`
+
+func parseIR(t *testing.T, src string) compiler.IR {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(src), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return compiler.ToIR([]*ast.Document{doc})
+}
+
+// spec returns the first type spec with the given name.
+func spec(ir compiler.IR, name string) (ts *ast.TypeSpec) {
+	walkSpecs(ir, func(s *ast.TypeSpec) {
+		if ts == nil && specName(s) == name {
+			ts = s
+		}
+	})
+	return
+}
+
+func fieldNames(ts *ast.TypeSpec) (names []string) {
+	walkMembers(ts, func(f *ast.Field) {
+		names = append(names, f.Name.Name)
+	}, func(f *ast.InputValue) {
+		names = append(names, f.Name.Name)
+	})
+	if e, ok := ts.Type.(*ast.TypeSpec_Enum); ok {
+		for _, v := range e.Enum.Values.List {
+			names = append(names, v.Name.Name)
+		}
+	}
+	return
+}
+
+func directiveNames(dirs []*ast.DirectiveLit) (names []string) {
+	for _, d := range dirs {
+		names = append(names, d.Name)
+	}
+	return
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Mod  string
+		Args map[string]interface{}
+		Err  string
+	}{
+		{
+			Name: "Unknown",
+			Mod:  "nope",
+			Err:  "codemod: unknown codemod: nope",
+		},
+		{
+			Name: "MissingArg",
+			Mod:  "prefixTypes",
+			Err:  "codemod: prefixTypes: missing required argument: prefix",
+		},
+		{
+			Name: "UnknownArg",
+			Mod:  "prefixTypes",
+			Args: map[string]interface{}{"prefix": "V1", "suffix": "X"},
+			Err:  "codemod: prefixTypes: unknown argument: suffix",
+		},
+		{
+			Name: "WrongType",
+			Mod:  "stripFields",
+			Args: map[string]interface{}{"directive": 1},
+			Err:  "codemod: stripFields: expected argument directive to be a string but got: int",
+		},
+		{
+			Name: "BadPattern",
+			Mod:  "addDirective",
+			Args: map[string]interface{}{"directive": "@auth", "fields": "User.["},
+			Err:  "codemod: addDirective: invalid pattern: User.[",
+		},
+		{
+			Name: "BadDirective",
+			Mod:  "addDirective",
+			Args: map[string]interface{}{"directive": "@auth(", "types": "*"},
+			Err:  "codemod: addDirective: invalid directive: @auth(",
+		},
+		{
+			Name: "Valid",
+			Mod:  "addDirective",
+			Args: map[string]interface{}{"directive": "auth(requires: ADMIN)", "types": "*"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			_, err := New(testCase.Mod, testCase.Args)
+			if testCase.Err == "" {
+				if err != nil {
+					subT.Error(err)
+				}
+				return
+			}
+
+			if err == nil || err.Error() != testCase.Err {
+				subT.Errorf("expected error: %s, but got: %v", testCase.Err, err)
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("expected sorted names but got: %v", names)
+	}
+
+	for _, name := range []string{"addDirective", "prefixTypes", "stripFields"} {
+		i := sort.SearchStrings(names, name)
+		if i == len(names) || names[i] != name {
+			t.Errorf("expected codemod to be registered: %s", name)
+		}
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	var calls []int
+	fail := errors.New("fail")
+
+	p := Pipeline{
+		Func(func(compiler.IR) error { calls = append(calls, 1); return nil }),
+		Func(func(compiler.IR) error { calls = append(calls, 2); return fail }),
+		Func(func(compiler.IR) error { calls = append(calls, 3); return nil }),
+	}
+
+	err := p.Apply(nil)
+	if err != fail {
+		t.Errorf("expected error: %v, but got: %v", fail, err)
+	}
+	if !reflect.DeepEqual(calls, []int{1, 2}) {
+		t.Errorf("expected pipeline to stop at first error but got calls: %v", calls)
+	}
+}
+
+func TestAddDirective(t *testing.T) {
+	ir := parseIR(t, testSchema)
+
+	c, err := New("addDirective", map[string]interface{}{
+		"directive": "@auth(requires: ADMIN)",
+		"types":     "User",
+		"fields":    "*.email",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Applying twice must not duplicate the directive
+	for i := 0; i < 2; i++ {
+		if err = c.Apply(ir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	user := spec(ir, "User")
+	if dirs := directiveNames(user.Directives); !reflect.DeepEqual(dirs, []string{"auth"}) {
+		t.Errorf("expected User directives: [auth], but got: %v", dirs)
+	}
+
+	for _, f := range user.Type.(*ast.TypeSpec_Object).Object.Fields.List {
+		var ex []string
+		switch f.Name.Name {
+		case "email":
+			ex = []string{"auth"}
+		case "password":
+			ex = []string{"internal"}
+		}
+
+		if dirs := directiveNames(f.Directives); !reflect.DeepEqual(dirs, ex) {
+			t.Errorf("expected User.%s directives: %v, but got: %v", f.Name.Name, ex, dirs)
+		}
+	}
+
+	if dirs := spec(ir, "Query").Directives; len(dirs) != 0 {
+		t.Errorf("expected Query to be untouched but got: %v", directiveNames(dirs))
+	}
+}
+
+func TestPrefixTypes(t *testing.T) {
+	ir := parseIR(t, testSchema)
+
+	c, err := PrefixTypes("V1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Apply(ir); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for _, types := range ir {
+		for name := range types {
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+
+	ex := []string{"V1Node", "V1Query", "V1Result", "V1Role", "V1SearchInput", "V1User", "internal", "schema"}
+	if !reflect.DeepEqual(keys, ex) {
+		t.Errorf("expected types: %v, but got: %v", ex, keys)
+	}
+
+	schema := spec(ir, "").Type.(*ast.TypeSpec_Schema).Schema
+	if name := namedIdent(schema.RootOps.List[0].Type).Name; name != "V1Query" {
+		t.Errorf("expected query root to be renamed but got: %s", name)
+	}
+
+	user := spec(ir, "V1User").Type.(*ast.TypeSpec_Object).Object
+	if name := user.Interfaces[0].Name; name != "V1Node" {
+		t.Errorf("expected interface to be renamed but got: %s", name)
+	}
+	if name := namedIdent(user.Fields.List[0].Type).Name; name != "ID" {
+		t.Errorf("expected builtin scalar to be untouched but got: %s", name)
+	}
+
+	query := spec(ir, "V1Query").Type.(*ast.TypeSpec_Object).Object
+	if name := namedIdent(query.Fields.List[1].Args.List[0].Type).Name; name != "V1SearchInput" {
+		t.Errorf("expected argument type to be renamed but got: %s", name)
+	}
+	if name := namedIdent(query.Fields.List[1].Type).Name; name != "V1Result" {
+		t.Errorf("expected list type to be renamed but got: %s", name)
+	}
+
+	union := spec(ir, "V1Result").Type.(*ast.TypeSpec_Union).Union
+	if name := union.Members[0].Name; name != "V1User" {
+		t.Errorf("expected union member to be renamed but got: %s", name)
+	}
+}
+
+func TestPrefixTypes_Conflict(t *testing.T) {
+	ir := parseIR(t, "type User { id: ID }\ntype AUser { id: ID }")
+
+	c, err := PrefixTypes("A", "User")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.Apply(ir)
+	if err == nil || err.Error() != "type already exists: AUser" {
+		t.Errorf("expected conflict error but got: %v", err)
+	}
+}
+
+func TestStripFields(t *testing.T) {
+	ir := parseIR(t, testSchema)
+
+	c, err := StripFields("@internal", "Query.search")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Apply(ir); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string][]string{
+		"Query":       {"user"},
+		"User":        {"id", "email"},
+		"SearchInput": {"text"},
+		"Role":        {"ADMIN"},
+	}
+	for name, ex := range testCases {
+		if names := fieldNames(spec(ir, name)); !reflect.DeepEqual(names, ex) {
+			t.Errorf("expected %s fields: %v, but got: %v", name, ex, names)
+		}
+	}
+
+	user := spec(ir, "Query").Type.(*ast.TypeSpec_Object).Object.Fields.List[0]
+	if n := len(user.Args.List); n != 1 || user.Args.List[0].Name.Name != "id" {
+		t.Errorf("expected internal argument to be stripped but got %d args", n)
+	}
+}
@@ -0,0 +1,104 @@
+#nullable enable
+
+using System.Collections.Generic;
+using HotChocolate;
+using HotChocolate.Types;
+
+namespace Example.GraphQL
+{
+    [GraphQLDescription("Echo represents an echo message.")]
+    public partial class Echo : ISearchResult
+    {
+        [GraphQLDescription("msg contains the provided message.")]
+        public string Msg { get; set; } = default!;
+    }
+
+    [GraphQLDescription("Query represents valid queries.")]
+    public partial class Query
+    {
+        [GraphQLDescription("version returns the current API version.")]
+        [GraphQLType("Version")]
+        public partial object? Version();
+
+        [GraphQLDescription("echo echos a message.")]
+        public partial Echo? Echo(string text);
+
+        [GraphQLDescription("search performs a search over some data set.")]
+        public partial Result? Search(string? text, List<string?>? terms);
+    }
+
+    [GraphQLDescription("Result represents a search result.")]
+    public partial class Result : IConnection, ISearchResult
+    {
+        [GraphQLDescription("total yields the total number of search results.")]
+        public int? Total { get; set; }
+
+        [GraphQLDescription("edges contains the search results.")]
+        public List<INode?>? Edges { get; set; }
+
+        [GraphQLDescription("hasNextPage tells if there are more search results.")]
+        public bool? HasNextPage { get; set; }
+
+        [GraphQLDescription("count is the old name of total.")]
+        [GraphQLDeprecated("Use total.")]
+        public int? Count { get; set; }
+    }
+
+    [GraphQLDescription("Connection represents a set of edges, which are meant to be paginated.")]
+    [InterfaceType("Connection")]
+    public interface IConnection
+    {
+        [GraphQLDescription("total returns the total number of edges.")]
+        int? Total { get; }
+
+        [GraphQLDescription("edges contains the current page of edges.")]
+        List<INode?>? Edges { get; }
+
+        [GraphQLDescription("hasNextPage tells if there exists more edges.")]
+        bool? HasNextPage { get; }
+    }
+
+    [GraphQLDescription("Node represents a node.")]
+    [InterfaceType("Node")]
+    public interface INode
+    {
+        [GraphQLDescription("id uniquely identifies the node.")]
+        string Id { get; }
+    }
+
+    [GraphQLDescription("SearchResult is a test union type")]
+    [UnionType("SearchResult")]
+    public interface ISearchResult
+    {
+    }
+
+    [GraphQLDescription("Direction represents a cardinal direction.")]
+    public enum Direction
+    {
+        [GraphQLDescription("EnumValue description")]
+        North,
+        East,
+        South,
+        [GraphQLDeprecated("No longer supported")]
+        SouthWest,
+        [GraphQLDescription("EnumValue Description and Directives.")]
+        West
+    }
+
+    [GraphQLDescription("Point represents a 2-D geo point.")]
+    [GraphQLName("Point")]
+    public partial class Point
+    {
+        public double X { get; set; }
+
+        public double Y { get; set; }
+
+        public string? Label { get; set; } = "origin";
+
+        public bool? Visible { get; set; } = true;
+
+        public Direction? Heading { get; set; } = Direction.North;
+
+        public List<double?>? Weights { get; set; } = new List<double?> { 1.0, 2.5 };
+    }
+}
@@ -0,0 +1,898 @@
+// Package csharp contains a C# generator for GraphQL Documents.
+// The generated code is annotated for HotChocolate's annotation-based
+// schema definition.
+//
+package csharp
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/gen/printer"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+// Options contains the options for the C# generator.
+type Options struct {
+	// Namespace is the namespace of the generated declarations
+	Namespace string
+
+	// Copy descriptions to [GraphQLDescription] attributes (default: true)
+	Descriptions bool
+}
+
+// Generator generates C# code for a GraphQL schema.
+type Generator struct {
+	printer.Printer
+
+	log *zap.Logger
+
+	// kinds maps the types declared in a document to their kind
+	kinds map[string]token.Token
+
+	// unions maps a type to the unions it's a member of
+	unions map[string][]string
+}
+
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Printer.Reset()
+	g.Indent = "    "
+	g.kinds = make(map[string]token.Token)
+	g.unions = make(map[string][]string)
+}
+
+// Generate generates C# code for the given document.
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "csharp",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("csharp").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	gOpts, oerr := getOptions(doc, opts)
+	if oerr != nil {
+		return oerr
+	}
+
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+		g.kinds[name] = d.Tok
+
+		if u, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Union); ok {
+			for _, mem := range u.Union.Members {
+				g.unions[mem.Name] = append(g.unions[mem.Name], name)
+			}
+		}
+	}
+
+	g.P("#nullable enable")
+	g.P()
+	g.P("using System.Collections.Generic;")
+	g.P("using HotChocolate;")
+	g.P("using HotChocolate.Types;")
+
+	if gOpts.Namespace != "" {
+		g.P()
+		g.P("namespace ", gOpts.Namespace)
+		g.P("{")
+		g.In()
+	}
+
+	// Generate types
+	g.log.Info("generating types")
+	roots := rootTypes(doc)
+	first := true
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		switch ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar, *ast.TypeSpec_Directive, *ast.TypeSpec_Schema:
+			// Custom scalars are referenced through [GraphQLType] attributes instead
+			continue
+		}
+
+		if gOpts.Namespace == "" || !first {
+			g.P()
+		}
+		first = false
+
+		name := ts.TypeSpec.Name.Name
+		descr := d.Doc
+		if !gOpts.Descriptions {
+			descr = nil
+		}
+
+		switch ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			g.generateObject(name, descr, gOpts.Descriptions, roots[name], ts.TypeSpec)
+		case *ast.TypeSpec_Interface:
+			g.generateInterface(name, descr, gOpts.Descriptions, ts.TypeSpec)
+		case *ast.TypeSpec_Union:
+			g.generateUnion(name, descr)
+		case *ast.TypeSpec_Enum:
+			g.generateEnum(name, descr, gOpts.Descriptions, ts.TypeSpec)
+		case *ast.TypeSpec_Input:
+			g.generateInput(name, descr, gOpts.Descriptions, ts.TypeSpec)
+		}
+	}
+
+	if gOpts.Namespace != "" {
+		g.Out()
+		g.P("}")
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	// Open file to write to
+	csFile, err := gCtx.Open(filepath.Join(filepath.Dir(doc.Name), fileName(doc)+".cs"))
+	if err != nil {
+		return
+	}
+	defer csFile.Close()
+
+	// Write generated output
+	_, err = g.WriteTo(csFile)
+	return
+}
+
+// fileName returns the name, without extension, of the generated file
+// which follows the C# convention of PascalCase file names.
+//
+func fileName(doc *ast.Document) string {
+	base := filepath.Base(doc.Name)
+	name := pascalCase(base[:len(base)-len(filepath.Ext(base))])
+	if name == "" {
+		return "Schema"
+	}
+	return name
+}
+
+// rootTypes returns the names of the root operation types.
+func rootTypes(doc *ast.Document) map[string]bool {
+	roots := make(map[string]bool, 3)
+	if doc.Schema == nil {
+		roots["Query"] = true
+		roots["Mutation"] = true
+		roots["Subscription"] = true
+		return roots
+	}
+
+	schema := doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Schema).Schema
+	for _, f := range schema.RootOps.List {
+		roots[f.Type.(*ast.Field_Ident).Ident.Name] = true
+	}
+	return roots
+}
+
+// generateObject generates a partial class for an object type. Fields which
+// take arguments, and every field of a root type, are generated as partial
+// methods which must be implemented in another part of the class.
+//
+func (g *Generator) generateObject(name string, doc *ast.DocGroup, descr, root bool, ts *ast.TypeSpec) {
+	obj := ts.Type.(*ast.TypeSpec_Object).Object
+
+	var supers []string
+	for _, inter := range obj.Interfaces {
+		supers = append(supers, interfaceName(inter.Name))
+	}
+	for _, u := range g.unions[name] {
+		supers = append(supers, interfaceName(u))
+	}
+
+	g.printDescription(doc)
+	g.printDecl("public partial class ", name, supers)
+	g.P("{")
+	g.In()
+
+	if obj.Fields != nil {
+		for i, f := range obj.Fields.List {
+			if i > 0 {
+				g.P()
+			}
+
+			resolver := root || (f.Args != nil && len(f.Args.List) > 0)
+			g.generateField(name, f, descr, resolver, "public ")
+		}
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateInterface(name string, doc *ast.DocGroup, descr bool, ts *ast.TypeSpec) {
+	inter := ts.Type.(*ast.TypeSpec_Interface).Interface
+
+	g.printDescription(doc)
+	g.P("[InterfaceType(", quote(name), ")]")
+	g.P("public interface ", interfaceName(name))
+	g.P("{")
+	g.In()
+
+	if inter.Fields != nil {
+		for i, f := range inter.Fields.List {
+			if i > 0 {
+				g.P()
+			}
+
+			resolver := f.Args != nil && len(f.Args.List) > 0
+			g.generateField(interfaceName(name), f, descr, resolver, "")
+		}
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+// generateField generates either a property or, for resolvers, a method.
+func (g *Generator) generateField(class string, f *ast.Field, descr, resolver bool, mod string) {
+	typ := fieldType(f)
+	member := memberName(class, f.Name.Name)
+
+	if descr {
+		g.printDescription(f.Doc)
+	}
+	g.printAttrs(f.Name.Name, member, resolver, f.Directives)
+	g.printTypeAttr(typ)
+
+	if !resolver {
+		accessors := " { get; set; }"
+		if mod == "" {
+			accessors = " { get; }"
+		}
+
+		g.P(mod, g.csType(typ), " ", member, accessors, g.initializer(typ, mod))
+		return
+	}
+
+	if mod != "" {
+		mod += "partial "
+	}
+
+	var params []string
+	if f.Args != nil {
+		for _, a := range f.Args.List {
+			params = append(params, g.param(a))
+		}
+	}
+	g.P(mod, g.csType(typ), " ", member, "(", strings.Join(params, ", "), ");")
+}
+
+// param returns the method parameter for a field argument.
+func (g *Generator) param(a *ast.InputValue) string {
+	var b strings.Builder
+
+	typ := inputValueType(a)
+	if g.kinds[namedType(typ)] == token.Token_SCALAR {
+		b.WriteString("[GraphQLType(")
+		b.WriteString(quote(sdlType(typ)))
+		b.WriteString(")] ")
+	}
+	b.WriteString(g.csType(typ))
+	b.WriteByte(' ')
+	b.WriteString(identifier(a.Name.Name))
+	return b.String()
+}
+
+func (g *Generator) generateUnion(name string, doc *ast.DocGroup) {
+	g.printDescription(doc)
+	g.P("[UnionType(", quote(name), ")]")
+	g.P("public interface ", interfaceName(name))
+	g.P("{")
+	g.P("}")
+}
+
+func (g *Generator) generateEnum(name string, doc *ast.DocGroup, descr bool, ts *ast.TypeSpec) {
+	enum := ts.Type.(*ast.TypeSpec_Enum).Enum
+
+	g.printDescription(doc)
+	g.P("public enum ", name)
+	g.P("{")
+	g.In()
+
+	var values []*ast.Field
+	if enum.Values != nil {
+		values = enum.Values.List
+	}
+	for i, v := range values {
+		if descr {
+			g.printDescription(v.Doc)
+		}
+
+		member := enumValueName(v.Name.Name)
+		if reason, ok := deprecation(v.Directives); ok {
+			g.P("[GraphQLDeprecated(", quote(reason), ")]")
+		}
+		if strings.ToUpper(snakeCase(member)) != v.Name.Name {
+			g.P("[GraphQLName(", quote(v.Name.Name), ")]")
+		}
+
+		sep := ","
+		if i == len(values)-1 {
+			sep = ""
+		}
+		g.P(member, sep)
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateInput(name string, doc *ast.DocGroup, descr bool, ts *ast.TypeSpec) {
+	input := ts.Type.(*ast.TypeSpec_Input).Input
+
+	g.printDescription(doc)
+	if !strings.HasSuffix(name, "Input") {
+		// HotChocolate appends Input to the names of inferred input types
+		g.P("[GraphQLName(", quote(name), ")]")
+	}
+	g.P("public partial class ", name)
+	g.P("{")
+	g.In()
+
+	if input.Fields != nil {
+		for i, f := range input.Fields.List {
+			if i > 0 {
+				g.P()
+			}
+
+			typ := inputValueType(f)
+			member := memberName(name, f.Name.Name)
+
+			if descr {
+				g.printDescription(f.Doc)
+			}
+			g.printAttrs(f.Name.Name, member, false, f.Directives)
+			g.printTypeAttr(typ)
+
+			init := g.initializer(typ, "public ")
+			if def := defaultValue(f.Default); def != nil {
+				init = " = " + g.csVal(typ, def) + ";"
+			}
+			g.P("public ", g.csType(typ), " ", member, " { get; set; }", init)
+		}
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+// printDecl prints a type declaration along with any base types.
+func (g *Generator) printDecl(decl, name string, supers []string) {
+	if len(supers) == 0 {
+		g.P(decl, name)
+		return
+	}
+	g.P(decl, name, " : ", strings.Join(supers, ", "))
+}
+
+// printAttrs prints the name and deprecation attributes of a member. The name
+// is only given when HotChocolate's naming conventions wouldn't produce the
+// original GraphQL name.
+//
+func (g *Generator) printAttrs(gqlName, member string, method bool, dirs []*ast.DirectiveLit) {
+	if graphqlName(member, method) != gqlName {
+		g.P("[GraphQLName(", quote(gqlName), ")]")
+	}
+
+	if reason, ok := deprecation(dirs); ok {
+		g.P("[GraphQLDeprecated(", quote(reason), ")]")
+	}
+}
+
+// printTypeAttr prints a [GraphQLType] attribute for members of a custom
+// scalar type, since they have no C# type to infer the scalar from.
+//
+func (g *Generator) printTypeAttr(typ interface{}) {
+	if g.kinds[namedType(typ)] == token.Token_SCALAR {
+		g.P("[GraphQLType(", quote(sdlType(typ)), ")]")
+	}
+}
+
+func (g *Generator) printDescription(doc *ast.DocGroup) {
+	if doc == nil {
+		return
+	}
+
+	text := strings.TrimSpace(doc.Text())
+	if text == "" {
+		return
+	}
+	g.P("[GraphQLDescription(", quote(text), ")]")
+}
+
+// initializer returns the property initializer for non-null reference
+// types which would otherwise be flagged by nullable reference checks.
+//
+func (g *Generator) initializer(typ interface{}, mod string) string {
+	if mod == "" {
+		return ""
+	}
+
+	if _, ok := typ.(*ast.NonNull); !ok || g.valueType(typ) {
+		return ""
+	}
+	return " = default!;"
+}
+
+// deprecation returns the reason given to a @deprecated directive.
+func deprecation(dirs []*ast.DirectiveLit) (string, bool) {
+	for _, d := range dirs {
+		if d.Name != "deprecated" {
+			continue
+		}
+
+		reason := "No longer supported"
+		if d.Args == nil {
+			return reason, true
+		}
+
+		for _, arg := range d.Args.Args {
+			if arg.Name.Name != "reason" {
+				continue
+			}
+
+			if b, ok := arg.Value.(*ast.Arg_BasicLit); ok {
+				reason = unquote(b.BasicLit.Value)
+			}
+		}
+		return reason, true
+	}
+	return "", false
+}
+
+func fieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func inputValueType(a *ast.InputValue) interface{} {
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func defaultValue(def interface{}) interface{} {
+	switch v := def.(type) {
+	case *ast.InputValue_BasicLit:
+		return v.BasicLit
+	case *ast.InputValue_CompositeLit:
+		if _, ok := v.CompositeLit.Value.(*ast.CompositeLit_ObjLit); ok {
+			// Input objects have no literal syntax in C#
+			return nil
+		}
+		return v.CompositeLit
+	}
+	return nil
+}
+
+var csTypes = map[string]string{
+	"Int":     "int",
+	"Float":   "double",
+	"String":  "string",
+	"Boolean": "bool",
+	"ID":      "string",
+}
+
+// csType returns the C# type of a GraphQL type. Nullable GraphQL types
+// map to nullable C# types and non-null types drop the ?.
+//
+func (g *Generator) csType(typ interface{}) string {
+	if nn, ok := typ.(*ast.NonNull); ok {
+		switch w := nn.Type.(type) {
+		case *ast.NonNull_Ident:
+			return g.baseType(w.Ident)
+		case *ast.NonNull_List:
+			return g.baseType(w.List)
+		}
+	}
+	return g.baseType(typ) + "?"
+}
+
+// baseType returns the C# type of a GraphQL type without any nullability.
+func (g *Generator) baseType(typ interface{}) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		if t, ok := csTypes[v.Name]; ok {
+			return t
+		}
+
+		switch g.kinds[v.Name] {
+		case token.Token_SCALAR:
+			return "object"
+		case token.Token_INTERFACE, token.Token_UNION:
+			return interfaceName(v.Name)
+		}
+		return v.Name
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			typ = w.Ident
+		case *ast.List_List:
+			typ = w.List
+		case *ast.List_NonNull:
+			typ = w.NonNull
+		}
+		return "List<" + g.csType(typ) + ">"
+	}
+	return "object"
+}
+
+// valueType reports whether a GraphQL type maps to a C# value type.
+func (g *Generator) valueType(typ interface{}) bool {
+	if nn, ok := typ.(*ast.NonNull); ok {
+		id, ok := nn.Type.(*ast.NonNull_Ident)
+		if !ok {
+			return false
+		}
+		typ = id.Ident
+	}
+
+	id, ok := typ.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	switch id.Name {
+	case "Int", "Float", "Boolean":
+		return true
+	}
+	return g.kinds[id.Name] == token.Token_ENUM
+}
+
+// namedType returns the name of the underlying named type.
+func namedType(typ interface{}) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		return v.Name
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			return w.Ident.Name
+		case *ast.List_List:
+			return namedType(w.List)
+		case *ast.List_NonNull:
+			return namedType(w.NonNull)
+		}
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return w.Ident.Name
+		case *ast.NonNull_List:
+			return namedType(w.List)
+		}
+	}
+	return ""
+}
+
+// sdlType returns the GraphQL type syntax of a type e.g. [Int!]!
+func sdlType(typ interface{}) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		return v.Name
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			return "[" + w.Ident.Name + "]"
+		case *ast.List_List:
+			return "[" + sdlType(w.List) + "]"
+		case *ast.List_NonNull:
+			return "[" + sdlType(w.NonNull) + "]"
+		}
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return w.Ident.Name + "!"
+		case *ast.NonNull_List:
+			return sdlType(w.List) + "!"
+		}
+	}
+	return ""
+}
+
+// csVal returns the C# literal of a default value of the given type.
+func (g *Generator) csVal(typ, val interface{}) string {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		switch v.Kind {
+		case token.Token_STRING:
+			return quote(unquote(v.Value))
+		case token.Token_IDENT:
+			return namedType(typ) + "." + enumValueName(v.Value)
+		case token.Token_INT:
+			if namedType(typ) == "Float" {
+				return v.Value + ".0"
+			}
+		}
+		return v.Value
+	case *ast.ListLit:
+		return g.csList(typ, v)
+	case *ast.CompositeLit:
+		switch w := v.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			return g.csVal(typ, w.BasicLit)
+		case *ast.CompositeLit_ListLit:
+			return g.csVal(typ, w.ListLit)
+		}
+	}
+	return "default"
+}
+
+func (g *Generator) csList(typ interface{}, v *ast.ListLit) string {
+	var vals []interface{}
+	switch w := v.List.(type) {
+	case *ast.ListLit_BasicList:
+		for _, bval := range w.BasicList.Values {
+			vals = append(vals, bval)
+		}
+	case *ast.ListLit_CompositeList:
+		for _, cval := range w.CompositeList.Values {
+			vals = append(vals, cval)
+		}
+	}
+
+	elems := make([]string, len(vals))
+	for i, iv := range vals {
+		elems[i] = g.csVal(elemType(typ), iv)
+	}
+
+	list := "new " + g.baseType(listType(typ))
+	if len(elems) == 0 {
+		return list + "()"
+	}
+	return list + " { " + strings.Join(elems, ", ") + " }"
+}
+
+// listType returns the list of a possibly non-null list type.
+func listType(typ interface{}) interface{} {
+	if nn, ok := typ.(*ast.NonNull); ok {
+		if l, ok := nn.Type.(*ast.NonNull_List); ok {
+			return l.List
+		}
+	}
+	return typ
+}
+
+// elemType returns the element type of a list type.
+func elemType(typ interface{}) interface{} {
+	l, ok := listType(typ).(*ast.List)
+	if !ok {
+		return typ
+	}
+
+	switch w := l.Type.(type) {
+	case *ast.List_Ident:
+		return w.Ident
+	case *ast.List_List:
+		return w.List
+	case *ast.List_NonNull:
+		return w.NonNull
+	}
+	return typ
+}
+
+func unquote(s string) string {
+	u, err := strconv.Unquote(s)
+	if err != nil {
+		return strings.Trim(s, `"`)
+	}
+	return u
+}
+
+// quote returns s as a double quoted C# string literal.
+func quote(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+var keywords = map[string]bool{
+	"abstract": true, "as": true, "base": true, "bool": true, "break": true,
+	"byte": true, "case": true, "catch": true, "char": true, "checked": true,
+	"class": true, "const": true, "continue": true, "decimal": true, "default": true,
+	"delegate": true, "do": true, "double": true, "else": true, "enum": true,
+	"event": true, "explicit": true, "extern": true, "false": true, "finally": true,
+	"fixed": true, "float": true, "for": true, "foreach": true, "goto": true,
+	"if": true, "implicit": true, "in": true, "int": true, "interface": true,
+	"internal": true, "is": true, "lock": true, "long": true, "namespace": true,
+	"new": true, "null": true, "object": true, "operator": true, "out": true,
+	"override": true, "params": true, "private": true, "protected": true, "public": true,
+	"readonly": true, "ref": true, "return": true, "sbyte": true, "sealed": true,
+	"short": true, "sizeof": true, "stackalloc": true, "static": true, "string": true,
+	"struct": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "uint": true, "ulong": true, "unchecked": true,
+	"unsafe": true, "ushort": true, "using": true, "virtual": true, "void": true,
+	"volatile": true, "while": true,
+}
+
+// identifier returns a valid C# identifier for a GraphQL name.
+func identifier(name string) string {
+	if keywords[name] {
+		return "@" + name
+	}
+	return name
+}
+
+// interfaceName returns the C# interface name of a GraphQL interface or union.
+func interfaceName(name string) string {
+	return "I" + name
+}
+
+// memberName returns the PascalCase member name of a field. Members can't
+// share the name of their enclosing type so those are suffixed.
+//
+func memberName(class, name string) string {
+	member := pascalCase(name)
+	if member == "" {
+		member = "Value"
+	}
+	if member == class {
+		member += "Value"
+	}
+	return member
+}
+
+// graphqlName returns the field name HotChocolate infers from a member,
+// which drops the Get prefix and Async suffix of methods.
+//
+func graphqlName(member string, method bool) string {
+	if method {
+		if len(member) > 3 && strings.HasPrefix(member, "Get") && unicode.IsUpper(rune(member[3])) {
+			member = member[3:]
+		}
+		if len(member) > 5 && strings.HasSuffix(member, "Async") {
+			member = member[:len(member)-5]
+		}
+	}
+
+	if member == "" {
+		return member
+	}
+	return strings.ToLower(member[:1]) + member[1:]
+}
+
+// enumValueName converts an enum value e.g. NORTH_EAST to PascalCase e.g.
+// NorthEast, which HotChocolate converts back to upper snake case.
+//
+func enumValueName(value string) string {
+	return pascalCase(strings.ToLower(value))
+}
+
+// snakeCase converts a PascalCase name e.g. NorthEast to snake case e.g. North_East.
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// pascalCase converts a name e.g. star-wars to a PascalCase name e.g. StarWars.
+func pascalCase(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Descriptions: true,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "csharp" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		csOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range csOpts.Fields {
+			switch arg.Key.Name {
+			case "namespace":
+				gOpts.Namespace = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			case "descriptions":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Descriptions = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts == nil {
+		return
+	}
+	if n, ok := opts["namespace"]; ok {
+		gOpts.Namespace, _ = n.(string)
+	}
+	if d, ok := opts["descriptions"]; ok {
+		gOpts.Descriptions, _ = d.(bool)
+	}
+
+	return
+}
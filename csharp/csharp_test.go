@@ -0,0 +1,563 @@
+package csharp
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "Test.cs", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected csharp output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected csharp output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestNames(t *testing.T) {
+	testCases := []struct {
+		Doc  string
+		File string
+	}{
+		{Doc: "test.gql", File: "Test"},
+		{Doc: "star-wars.graphql", File: "StarWars"},
+		{Doc: "dir/api_v2.gql", File: "ApiV2"},
+		{Doc: "_.gql", File: "Schema"},
+	}
+
+	for _, testCase := range testCases {
+		if name := fileName(&ast.Document{Name: testCase.Doc}); name != testCase.File {
+			t.Errorf("expected file name %s for %s, but got: %s", testCase.File, testCase.Doc, name)
+		}
+	}
+
+	if id := identifier("params"); id != "@params" {
+		t.Errorf("expected keyword to be escaped, but got: %s", id)
+	}
+
+	if name := memberName("Echo", "echo"); name != "EchoValue" {
+		t.Errorf("expected member to not clash with its class, but got: %s", name)
+	}
+
+	if name := enumValueName("NORTH_EAST"); name != "NorthEast" {
+		t.Errorf("expected enum value to be PascalCase, but got: %s", name)
+	}
+
+	nameCases := []struct {
+		Member string
+		Method bool
+		Name   string
+	}{
+		{Member: "HasNextPage", Name: "hasNextPage"},
+		{Member: "GetUser", Name: "getUser"},
+		{Member: "GetUser", Method: true, Name: "user"},
+		{Member: "SearchAsync", Method: true, Name: "search"},
+		{Member: "Getaway", Method: true, Name: "getaway"},
+	}
+	for _, c := range nameCases {
+		if name := graphqlName(c.Member, c.Method); name != c.Name {
+			t.Errorf("expected HotChocolate name %s for %s, but got: %s", c.Name, c.Member, name)
+		}
+	}
+}
+
+func TestCSharpType(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+	g.kinds["Time"] = token.Token_SCALAR
+	g.kinds["Node"] = token.Token_INTERFACE
+
+	testCases := []struct {
+		Type   interface{}
+		CSharp string
+	}{
+		{Type: &ast.Ident{Name: "Int"}, CSharp: "int?"},
+		{Type: &ast.Ident{Name: "ID"}, CSharp: "string?"},
+		{Type: &ast.Ident{Name: "Time"}, CSharp: "object?"},
+		{Type: &ast.Ident{Name: "Node"}, CSharp: "INode?"},
+		{Type: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "User"}}}, CSharp: "User"},
+		{
+			Type:   &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Boolean"}}},
+			CSharp: "List<bool?>?",
+		},
+		{
+			Type:   &ast.NonNull{Type: &ast.NonNull_List{List: &ast.List{Type: &ast.List_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Float"}}}}}}},
+			CSharp: "List<double>",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if typ := g.csType(testCase.Type); typ != testCase.CSharp {
+			t.Errorf("expected: %s, but got: %s", testCase.CSharp, typ)
+		}
+	}
+}
+
+func TestObject(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+	g.kinds["Node"] = token.Token_INTERFACE
+	g.kinds["Time"] = token.Token_SCALAR
+	g.unions["Test"] = []string{"Result"}
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Object{
+		Object: &ast.ObjectType{
+			Interfaces: []*ast.Ident{{Name: "Node"}},
+			Fields: &ast.FieldList{
+				List: []*ast.Field{
+					{
+						Name: &ast.Ident{Name: "id"},
+						Type: &ast.Field_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}},
+					},
+					{
+						Name: &ast.Ident{Name: "createdAt"},
+						Type: &ast.Field_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Time"}}}},
+					},
+					{
+						Name: &ast.Ident{Name: "test"},
+						Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "Int"}},
+						Directives: []*ast.DirectiveLit{
+							{Name: "deprecated"},
+						},
+					},
+					{
+						Name: &ast.Ident{Name: "friends"},
+						Type: &ast.Field_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Test"}}}},
+						Args: &ast.InputValueList{List: []*ast.InputValue{
+							{
+								Name: &ast.Ident{Name: "first"},
+								Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "Int"}},
+							},
+							{
+								Name: &ast.Ident{Name: "params"},
+								Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "Time"}},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}}
+
+	g.generateObject("Test", nil, false, false, ts)
+
+	ex := []byte(`public partial class Test : INode, IResult
+{
+    public string Id { get; set; } = default!;
+
+    [GraphQLType("Time!")]
+    public object CreatedAt { get; set; } = default!;
+
+    [GraphQLName("test")]
+    [GraphQLDeprecated("No longer supported")]
+    public int? TestValue { get; set; }
+
+    public partial List<Test?>? Friends(int? first, [GraphQLType("Time")] object? @params);
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestRootObject(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Object{
+		Object: &ast.ObjectType{
+			Fields: &ast.FieldList{
+				List: []*ast.Field{
+					{
+						Name: &ast.Ident{Name: "hello"},
+						Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "String"}},
+					},
+				},
+			},
+		},
+	}}
+
+	g.generateObject("Query", nil, false, true, ts)
+
+	ex := []byte(`public partial class Query
+{
+    public partial string? Hello();
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestInterface(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Interface{
+		Interface: &ast.InterfaceType{
+			Fields: &ast.FieldList{
+				List: []*ast.Field{
+					{
+						Name: &ast.Ident{Name: "id"},
+						Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "ID"}},
+					},
+					{
+						Name: &ast.Ident{Name: "getNode"},
+						Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "ID"}},
+						Args: &ast.InputValueList{List: []*ast.InputValue{
+							{
+								Name: &ast.Ident{Name: "id"},
+								Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}}
+
+	g.generateInterface("Node", &ast.DocGroup{List: []*ast.DocGroup_Doc{{Text: `"A "node"."`, Char: '"'}}}, false, ts)
+
+	ex := []byte(`[GraphQLDescription("A \"node\".")]
+[InterfaceType("Node")]
+public interface INode
+{
+    string? Id { get; }
+
+    [GraphQLName("getNode")]
+    string? GetNode(string id);
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestUnion(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+
+	g.generateUnion("SearchResult", nil)
+
+	ex := []byte(`[UnionType("SearchResult")]
+public interface ISearchResult
+{
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestEnum(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Enum{
+		Enum: &ast.EnumType{
+			Values: &ast.FieldList{
+				List: []*ast.Field{
+					{Name: &ast.Ident{Name: "NORTH_EAST"}},
+					{Name: &ast.Ident{Name: "b"}},
+					{
+						Name: &ast.Ident{Name: "C"},
+						Directives: []*ast.DirectiveLit{
+							{
+								Name: "deprecated",
+								Args: &ast.CallExpr{Args: []*ast.Arg{{
+									Name:  &ast.Ident{Name: "reason"},
+									Value: &ast.Arg_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: `"Use B."`}},
+								}}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	g.generateEnum("Test", nil, false, ts)
+
+	ex := []byte(`public enum Test
+{
+    NorthEast,
+    [GraphQLName("b")]
+    B,
+    [GraphQLDeprecated("Use B.")]
+    C
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestInput(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+	g.kinds["Direction"] = token.Token_ENUM
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Input{
+		Input: &ast.InputType{
+			Fields: &ast.InputValueList{
+				List: []*ast.InputValue{
+					{
+						Name: &ast.Ident{Name: "id"},
+						Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}},
+					},
+					{
+						Name: &ast.Ident{Name: "heading"},
+						Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Direction"}}}},
+					},
+					{
+						Name: &ast.Ident{Name: "pattern"},
+						Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "String"}},
+						Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+							Kind:  token.Token_STRING,
+							Value: `"a\\b"`,
+						}},
+					},
+					{
+						Name: &ast.Ident{Name: "ratio"},
+						Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Float"}}}},
+						Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+							Kind:  token.Token_INT,
+							Value: "1",
+						}},
+					},
+					{
+						Name: &ast.Ident{Name: "dirs"},
+						Type: &ast.InputValue_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Direction"}}}},
+						Default: &ast.InputValue_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ListLit{
+							ListLit: &ast.ListLit{
+								List: &ast.ListLit_BasicList{BasicList: &ast.ListLit_Basic{Values: []*ast.BasicLit{
+									{Kind: token.Token_IDENT, Value: "NORTH"},
+									{Kind: token.Token_IDENT, Value: "SOUTH_WEST"},
+								}}},
+							},
+						}}},
+					},
+				},
+			},
+		},
+	}}
+
+	g.generateInput("Test", nil, false, ts)
+
+	ex := []byte(`[GraphQLName("Test")]
+public partial class Test
+{
+    public string Id { get; set; } = default!;
+
+    public Direction Heading { get; set; }
+
+    public string? Pattern { get; set; } = "a\\b";
+
+    public double Ratio { get; set; } = 1.0;
+
+    public List<Direction?>? Dirs { get; set; } = new List<Direction?> { Direction.North, Direction.SouthWest };
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestOptions(t *testing.T) {
+	doc := &ast.Document{
+		Directives: []*ast.DirectiveLit{
+			{
+				Name: "csharp",
+				Args: &ast.CallExpr{Args: []*ast.Arg{{
+					Name: &ast.Ident{Name: "options"},
+					Value: &ast.Arg_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+						Fields: []*ast.ObjLit_Pair{
+							{
+								Key: &ast.Ident{Name: "namespace"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: `"Example"`}}},
+							},
+							{
+								Key: &ast.Ident{Name: "descriptions"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_BOOL, Value: "false"}}},
+							},
+						},
+					}}}},
+				}}},
+			},
+		},
+	}
+
+	gOpts, err := getOptions(doc, map[string]interface{}{"namespace": "Other.Example"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.Namespace != "Other.Example" || gOpts.Descriptions {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := new(Generator)
+	g.Reset()
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `schema {
+	query: Query
+}
+
+"Query represents the queries this example provides."
+type Query {
+	hello: String
+}
+
+"A user of the service."
+type User {
+	name: String!
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, map[string]interface{}{"namespace": "Example"})
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Println(b.String())
+	// Output:
+	// #nullable enable
+	//
+	// using System.Collections.Generic;
+	// using HotChocolate;
+	// using HotChocolate.Types;
+	//
+	// namespace Example
+	// {
+	//     [GraphQLDescription("Query represents the queries this example provides.")]
+	//     public partial class Query
+	//     {
+	//         public partial string? Hello();
+	//     }
+	//
+	//     [GraphQLDescription("A user of the service.")]
+	//     public partial class User
+	//     {
+	//         public string Name { get; set; } = default!;
+	//     }
+	// }
+}
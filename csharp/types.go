@@ -0,0 +1,63 @@
+// types.go contains the GraphQL types this generator supports
+
+package csharp
+
+import (
+	"github.com/gqlc/gqlc/types"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+var csharpTypeDecls = []*ast.TypeDecl{
+	{
+		Tok: token.Token_DIRECTIVE,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "csharp"},
+			Type: &ast.TypeSpec_Directive{Directive: &ast.DirectiveType{
+				Locs: []*ast.DirectiveLocation{{Loc: ast.DirectiveLocation_DOCUMENT}},
+				Args: &ast.InputValueList{
+					List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "options"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "CSharpOptions"},
+							},
+						},
+					},
+				},
+			}},
+		}},
+	},
+	{
+		Tok: token.Token_INPUT,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "CSharpOptions"},
+			Type: &ast.TypeSpec_Input{Input: &ast.InputType{
+				Fields: &ast.InputValueList{
+					List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "namespace"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+						},
+						{
+							Name: &ast.Ident{Name: "descriptions"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "true",
+							}},
+						},
+					},
+				},
+			}},
+		}},
+	},
+}
+
+func init() {
+	types.Register(csharpTypeDecls...)
+}
@@ -0,0 +1,118 @@
+// Package diag contains diagnostics, the problems found while compiling a
+// GraphQL Document, and renders them for people and for tools e.g. editors
+// and CI annotations.
+//
+package diag
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Severity is how serious a Diagnostic is.
+type Severity int
+
+// Severities, from most to least serious.
+const (
+	Error Severity = iota
+	Warning
+	Info
+)
+
+var severities = [...]string{
+	Error:   "error",
+	Warning: "warning",
+	Info:    "info",
+}
+
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severities) {
+		return "Severity(" + strconv.Itoa(int(s)) + ")"
+	}
+	return severities[s]
+}
+
+// MarshalText encodes a Severity as its name.
+func (s Severity) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// UnmarshalText decodes a Severity from its name.
+func (s *Severity) UnmarshalText(b []byte) error {
+	for i, name := range severities {
+		if name == string(b) {
+			*s = Severity(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("diag: unknown severity: %s", b)
+}
+
+// Pos is where a Diagnostic occurred. Line and Column start at 1 and are
+// 0 if unknown.
+//
+type Pos struct {
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// IsValid reports whether the file of the position is known.
+func (p Pos) IsValid() bool { return p.File != "" }
+
+// String formats a position as file:line:column, leaving out what's unknown.
+func (p Pos) String() string {
+	s := p.File
+	if p.Line > 0 {
+		s += ":" + strconv.Itoa(p.Line)
+		if p.Column > 0 {
+			s += ":" + strconv.Itoa(p.Column)
+		}
+	}
+	return s
+}
+
+// Related is another position which helps explain a Diagnostic e.g. the
+// previous declaration of a duplicate type.
+//
+type Related struct {
+	Pos Pos    `json:"pos"`
+	Msg string `json:"message"`
+}
+
+// Diagnostic is a single problem. Code identifies the kind of problem e.g.
+// parse, type, so tools can group and filter them.
+//
+// A *Diagnostic is an error, so it can be returned and still be reported
+// with its position once it reaches the caller.
+//
+type Diagnostic struct {
+	Severity Severity  `json:"severity"`
+	Code     string    `json:"code,omitempty"`
+	Pos      Pos       `json:"pos"`
+	Msg      string    `json:"message"`
+	Related  []Related `json:"related,omitempty"`
+}
+
+// Error returns the message of the Diagnostic. Its position isn't included,
+// since most messages, like those of the parser, already carry one.
+//
+func (d *Diagnostic) Error() string { return d.Msg }
+
+// Errorf returns an error Diagnostic.
+func Errorf(pos Pos, code, format string, args ...interface{}) *Diagnostic {
+	return &Diagnostic{Severity: Error, Code: code, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Warningf returns a warning Diagnostic.
+func Warningf(pos Pos, code, format string, args ...interface{}) *Diagnostic {
+	return &Diagnostic{Severity: Warning, Code: code, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// From converts an error into a Diagnostic. Errors which aren't already
+// diagnostics become errors of the given code, without a position.
+//
+func From(err error, code string) Diagnostic {
+	if d, ok := err.(*Diagnostic); ok {
+		return *d
+	}
+	return Diagnostic{Severity: Error, Code: code, Msg: err.Error()}
+}
@@ -0,0 +1,215 @@
+package diag
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var testDiags = []Diagnostic{
+	{
+		Severity: Error,
+		Code:     "type",
+		Pos:      Pos{File: "schema.gql", Line: 9, Column: 3},
+		Msg:      "Direction:NORTH: enum value must be unique",
+		Related: []Related{
+			{Pos: Pos{File: "schema.gql", Line: 8, Column: 3}, Msg: "NORTH is first declared here"},
+		},
+	},
+	{
+		Severity: Warning,
+		Code:     "generate",
+		Pos:      Pos{File: "schema.gql"},
+		Msg:      "positions\nare unknown",
+	},
+	{Severity: Info, Msg: "nothing to generate"},
+}
+
+func TestSeverity(t *testing.T) {
+	for _, s := range []Severity{Error, Warning, Info} {
+		b, err := s.MarshalText()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		var u Severity
+		if err = u.UnmarshalText(b); err != nil || u != s {
+			t.Errorf("expected %s to round trip, but got: %s, %v", s, u, err)
+		}
+	}
+
+	if s := Severity(7).String(); s != "Severity(7)" {
+		t.Errorf("expected unknown severity to be numbered, but got: %s", s)
+	}
+
+	var s Severity
+	if err := s.UnmarshalText([]byte("fatal")); err == nil {
+		t.Error("expected an error for an unknown severity")
+	}
+}
+
+func TestPos_String(t *testing.T) {
+	testCases := []struct {
+		Pos Pos
+		Ex  string
+	}{
+		{Pos: Pos{File: "a.gql", Line: 1, Column: 2}, Ex: "a.gql:1:2"},
+		{Pos: Pos{File: "a.gql", Line: 1}, Ex: "a.gql:1"},
+		{Pos: Pos{File: "a.gql", Column: 2}, Ex: "a.gql"},
+		{Pos: Pos{}, Ex: ""},
+	}
+
+	for _, testCase := range testCases {
+		if s := testCase.Pos.String(); s != testCase.Ex {
+			t.Errorf("expected: %q, but got: %q", testCase.Ex, s)
+		}
+	}
+}
+
+func TestFrom(t *testing.T) {
+	d := Errorf(Pos{File: "a.gql", Line: 3}, "parse", "unexpected %s", "}")
+	if from := From(d, "other"); from.Code != "parse" || from.Pos.Line != 3 || from.Msg != "unexpected }" {
+		t.Errorf("expected diagnostic to be kept as is, but got: %v", from)
+	}
+
+	from := From(errors.New("broken"), "generate")
+	if from.Severity != Error || from.Code != "generate" || from.Msg != "broken" || from.Pos.IsValid() {
+		t.Errorf("expected an error without a position, but got: %v", from)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range Formats {
+		if p, err := ParseFormat(string(f)); err != nil || p != f {
+			t.Errorf("expected %s to parse, but got: %s, %v", f, p, err)
+		}
+	}
+
+	if _, err := ParseFormat("junit"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestRender_Text(t *testing.T) {
+	var b bytes.Buffer
+	if err := Render(&b, Text, testDiags...); err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := `schema.gql:9:3: error: Direction:NORTH: enum value must be unique [type]
+	schema.gql:8:3: NORTH is first declared here
+schema.gql: warning: positions
+are unknown [generate]
+info: nothing to generate
+`
+	if b.String() != ex {
+		t.Errorf("expected:\n%s\nbut got:\n%s", ex, b.String())
+	}
+}
+
+func TestRender_JSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := Render(&b, JSON, testDiags...); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var diags []Diagnostic
+	if err := json.Unmarshal(b.Bytes(), &diags); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(diags) != len(testDiags) || diags[1].Severity != Warning || diags[0].Related[0].Pos.Line != 8 {
+		t.Errorf("expected diagnostics to round trip, but got:\n%s", b.String())
+	}
+
+	b.Reset()
+	if err := Render(&b, JSON); err != nil || strings.TrimSpace(b.String()) != "[]" {
+		t.Errorf("expected an empty array, but got: %s, %v", b.String(), err)
+	}
+}
+
+func TestRender_GitHub(t *testing.T) {
+	var b bytes.Buffer
+	if err := Render(&b, GitHub, testDiags...); err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := `::error file=schema.gql,line=9,col=3::Direction:NORTH: enum value must be unique
+::warning file=schema.gql::positions%0Aare unknown
+::notice::nothing to generate
+`
+	if b.String() != ex {
+		t.Errorf("expected:\n%s\nbut got:\n%s", ex, b.String())
+	}
+}
+
+func TestRender_SARIF(t *testing.T) {
+	var b bytes.Buffer
+	if err := Render(&b, SARIF, testDiags...); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(b.Bytes(), &log); err != nil {
+		t.Error(err)
+		return
+	}
+	if log.Version != sarifVersion || len(log.Runs) != 1 {
+		t.Errorf("expected a single run, but got:\n%s", b.String())
+		return
+	}
+
+	run := log.Runs[0]
+	if rules := run.Tool.Driver.Rules; len(rules) != 2 || rules[0].ID != "generate" || rules[1].ID != "type" {
+		t.Errorf("expected a rule for each code, but got: %v", rules)
+	}
+
+	levels := make([]string, len(run.Results))
+	for i, res := range run.Results {
+		levels[i] = res.Level
+	}
+	if s := strings.Join(levels, ","); s != "error,warning,note" {
+		t.Errorf("expected levels: error,warning,note, but got: %s", s)
+	}
+
+	res := run.Results[0]
+	if len(res.Locations) != 1 || res.Locations[0].PhysicalLocation.Region.StartColumn != 3 {
+		t.Errorf("expected a location with a column, but got: %v", res.Locations)
+	}
+	if len(res.RelatedLocations) != 1 || res.RelatedLocations[0].Message.Text != "NORTH is first declared here" {
+		t.Errorf("expected a related location, but got: %v", res.RelatedLocations)
+	}
+	if run.Results[1].Locations[0].PhysicalLocation.Region != nil {
+		t.Error("expected a location without a line to have no region")
+	}
+	if len(run.Results[2].Locations) != 0 {
+		t.Error("expected a diagnostic without a position to have no locations")
+	}
+}
+
+func TestRender_Unknown(t *testing.T) {
+	if err := Render(new(bytes.Buffer), Format("junit")); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func ExampleRender() {
+	d := Errorf(Pos{File: "schema.gql", Line: 9}, "type", "Direction:NORTH: enum value must be unique")
+
+	var b bytes.Buffer
+	Render(&b, GitHub, *d)
+	Render(&b, Text, *d)
+	fmt.Print(b.String())
+
+	// Output:
+	// ::error file=schema.gql,line=9::Direction:NORTH: enum value must be unique
+	// schema.gql:9: error: Direction:NORTH: enum value must be unique [type]
+}
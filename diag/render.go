@@ -0,0 +1,178 @@
+package diag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Format is a way of rendering diagnostics.
+type Format string
+
+// Supported formats.
+const (
+	// Text is one line per diagnostic, like the Go compiler.
+	Text Format = "text"
+
+	// JSON is an array of diagnostics.
+	JSON Format = "json"
+
+	// SARIF is a Static Analysis Results Interchange Format v2.1.0 log,
+	// which code scanning tools can import.
+	SARIF Format = "sarif"
+
+	// GitHub is GitHub Actions workflow commands, which the runner turns
+	// into annotations on a pull request.
+	GitHub Format = "github"
+)
+
+// Formats lists every supported format.
+var Formats = []Format{Text, JSON, SARIF, GitHub}
+
+// ParseFormat returns the Format with the given name.
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("diag: unknown format: %s", name)
+}
+
+// Render writes diagnostics to w in the given format. JSON and SARIF are
+// single documents, so every diagnostic should be rendered at once.
+//
+func Render(w io.Writer, format Format, diags ...Diagnostic) error {
+	switch format {
+	case Text:
+		return renderText(w, diags)
+	case JSON:
+		return renderJSON(w, diags)
+	case SARIF:
+		return renderSARIF(w, diags)
+	case GitHub:
+		return renderGitHub(w, diags)
+	}
+	return fmt.Errorf("diag: unknown format: %s", format)
+}
+
+// renderText writes file:line:column: severity: message [code], followed
+// by each related position on an indented line.
+//
+func renderText(w io.Writer, diags []Diagnostic) error {
+	for _, d := range diags {
+		var b strings.Builder
+		if d.Pos.IsValid() {
+			b.WriteString(d.Pos.String())
+			b.WriteString(": ")
+		}
+		b.WriteString(d.Severity.String())
+		b.WriteString(": ")
+		b.WriteString(d.Msg)
+		if d.Code != "" {
+			b.WriteString(" [")
+			b.WriteString(d.Code)
+			b.WriteByte(']')
+		}
+		b.WriteByte('\n')
+
+		for _, r := range d.Related {
+			b.WriteByte('\t')
+			if r.Pos.IsValid() {
+				b.WriteString(r.Pos.String())
+				b.WriteString(": ")
+			}
+			b.WriteString(r.Msg)
+			b.WriteByte('\n')
+		}
+
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderJSON(w io.Writer, diags []Diagnostic) error {
+	if diags == nil {
+		diags = []Diagnostic{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(diags)
+}
+
+// renderGitHub writes an ::error, ::warning or ::notice command for each
+// diagnostic. Annotations can't point at more than one place, so related
+// positions are left out; messages are expected to stand on their own.
+//
+func renderGitHub(w io.Writer, diags []Diagnostic) error {
+	for _, d := range diags {
+		cmd := "::error"
+		switch d.Severity {
+		case Warning:
+			cmd = "::warning"
+		case Info:
+			cmd = "::notice"
+		}
+
+		var props []string
+		if d.Pos.File != "" {
+			props = append(props, "file="+escapeProperty(relPath(d.Pos.File)))
+		}
+		if d.Pos.Line > 0 {
+			props = append(props, "line="+strconv.Itoa(d.Pos.Line))
+		}
+		if d.Pos.Column > 0 {
+			props = append(props, "col="+strconv.Itoa(d.Pos.Column))
+		}
+		if len(props) > 0 {
+			cmd += " " + strings.Join(props, ",")
+		}
+
+		if _, err := fmt.Fprintf(w, "%s::%s\n", cmd, escapeData(d.Msg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeData escapes the message of a workflow command.
+func escapeData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeProperty escapes the value of a workflow command property.
+func escapeProperty(s string) string {
+	s = escapeData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
+
+// relPath makes an absolute path relative to the working directory,
+// since annotations are resolved against the root of the checkout.
+//
+func relPath(name string) string {
+	if !filepath.IsAbs(name) {
+		return filepath.ToSlash(name)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return filepath.ToSlash(name)
+	}
+
+	rel, err := filepath.Rel(wd, name)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(name)
+	}
+	return filepath.ToSlash(rel)
+}
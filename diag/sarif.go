@@ -0,0 +1,142 @@
+package diag
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// The subset of SARIF which diagnostics map on to.
+type (
+	sarifLog struct {
+		Version string     `json:"version"`
+		Schema  string     `json:"$schema"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules,omitempty"`
+	}
+
+	sarifRule struct {
+		ID string `json:"id"`
+	}
+
+	sarifResult struct {
+		RuleID           string          `json:"ruleId,omitempty"`
+		Level            string          `json:"level"`
+		Message          sarifMessage    `json:"message"`
+		Locations        []sarifLocation `json:"locations,omitempty"`
+		RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifLocation struct {
+		ID               *int                  `json:"id,omitempty"`
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+		Message          *sarifMessage         `json:"message,omitempty"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           *sarifRegion          `json:"region,omitempty"`
+	}
+
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn,omitempty"`
+	}
+)
+
+var sarifLevels = [...]string{
+	Error:   "error",
+	Warning: "warning",
+	Info:    "note",
+}
+
+func sarifLoc(p Pos) sarifPhysicalLocation {
+	loc := sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: relPath(p.File)},
+	}
+	if p.Line > 0 {
+		loc.Region = &sarifRegion{StartLine: p.Line, StartColumn: p.Column}
+	}
+	return loc
+}
+
+// renderSARIF writes a log with a single run of gqlc. Each code is a rule,
+// and diagnostics without a position have no locations.
+//
+func renderSARIF(w io.Writer, diags []Diagnostic) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gqlc",
+			InformationURI: "https://github.com/gqlc/gqlc",
+		}},
+		Results: make([]sarifResult, 0, len(diags)),
+	}
+
+	codes := make(map[string]bool)
+	for _, d := range diags {
+		res := sarifResult{
+			RuleID:  d.Code,
+			Level:   "error",
+			Message: sarifMessage{Text: d.Msg},
+		}
+		if d.Severity >= 0 && int(d.Severity) < len(sarifLevels) {
+			res.Level = sarifLevels[d.Severity]
+		}
+		if d.Pos.IsValid() {
+			res.Locations = []sarifLocation{{PhysicalLocation: sarifLoc(d.Pos)}}
+		}
+		for i, r := range d.Related {
+			id := i
+			res.RelatedLocations = append(res.RelatedLocations, sarifLocation{
+				ID:               &id,
+				PhysicalLocation: sarifLoc(r.Pos),
+				Message:          &sarifMessage{Text: r.Msg},
+			})
+		}
+		run.Results = append(run.Results, res)
+
+		if d.Code != "" && !codes[d.Code] {
+			codes[d.Code] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: d.Code})
+		}
+	}
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool {
+		return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	})
+}
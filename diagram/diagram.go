@@ -0,0 +1,515 @@
+// Package diagram contains a generator for class diagrams of GraphQL Documents.
+// Diagrams are written as Mermaid, which renders in Markdown on most code
+// hosts, or as Graphviz dot.
+//
+package diagram
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/gen/printer"
+	"github.com/gqlc/graphql/ast"
+	"go.uber.org/zap"
+)
+
+// Formats a diagram can be written as.
+const (
+	Mermaid = "MERMAID"
+	Dot     = "DOT"
+)
+
+// Options contains the options for the diagram generator.
+type Options struct {
+	// Format is either MERMAID or DOT (default: MERMAID)
+	Format string
+
+	// List the fields of each type (default: true)
+	Fields bool
+}
+
+// Generator generates a class diagram for a GraphQL schema.
+type Generator struct {
+	printer.Printer
+
+	log *zap.Logger
+}
+
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Printer.Reset()
+	g.Indent = "  "
+}
+
+// Generate generates a class diagram of the given document. The diagram is
+// named after the document and its format e.g. test.gql generates test.mmd,
+// or test.dot.
+//
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "diagram",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("diagram").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	gOpts, err := getOptions(doc, opts)
+	if err != nil {
+		return
+	}
+
+	g.log.Info("generating diagram")
+	d := newDiagram(doc, gOpts.Fields)
+	switch gOpts.Format {
+	case Mermaid:
+		g.printMermaid(d)
+	case Dot:
+		g.printDot(docName(doc), d)
+	default:
+		return fmt.Errorf("unknown diagram format: %s", gOpts.Format)
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	f, err := gCtx.Open(fileName(doc, gOpts.Format))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	_, err = g.WriteTo(f)
+	return
+}
+
+func docName(doc *ast.Document) string {
+	base := filepath.Base(doc.Name)
+	name := base[:len(base)-len(filepath.Ext(base))]
+	if name == "" {
+		name = "schema"
+	}
+	return name
+}
+
+// fileName returns the name of the diagram generated for a document.
+func fileName(doc *ast.Document, format string) string {
+	if format == Dot {
+		return docName(doc) + ".dot"
+	}
+	return docName(doc) + ".mmd"
+}
+
+// Stereotypes of the types in a diagram. Objects don't have one.
+const (
+	interfaceType = "interface"
+	unionType     = "union"
+	enumType      = "enumeration"
+	inputType     = "input"
+)
+
+// edge kinds
+const (
+	fieldEdge = iota
+	implementsEdge
+	memberEdge
+)
+
+type node struct {
+	name    string
+	kind    string
+	members []string
+}
+
+type edge struct {
+	kind     int
+	from, to string
+	label    string
+	list     bool
+}
+
+// diagram is the format independent model of a class diagram.
+type diagram struct {
+	nodes []*node
+	edges []*edge
+}
+
+// newDiagram builds the diagram of a document. Every object, interface,
+// union, enum and input is a node. Edges join fields to the object,
+// interface or union types they return, objects to the interfaces they
+// implement and unions to their members. Input fields are joined to the
+// inputs they contain.
+//
+func newDiagram(doc *ast.Document, fields bool) *diagram {
+	kinds := make(map[string]string, len(doc.Types))
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		switch ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			kinds[ts.TypeSpec.Name.Name] = ""
+		case *ast.TypeSpec_Interface:
+			kinds[ts.TypeSpec.Name.Name] = interfaceType
+		case *ast.TypeSpec_Union:
+			kinds[ts.TypeSpec.Name.Name] = unionType
+		case *ast.TypeSpec_Input:
+			kinds[ts.TypeSpec.Name.Name] = inputType
+		}
+	}
+
+	d := new(diagram)
+	for _, decl := range doc.Types {
+		ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			n := &node{name: name}
+			d.fields(n, kinds, v.Object.Fields, fields)
+			for _, inter := range v.Object.Interfaces {
+				d.edges = append(d.edges, &edge{kind: implementsEdge, from: name, to: inter.Name})
+			}
+			d.nodes = append(d.nodes, n)
+		case *ast.TypeSpec_Interface:
+			n := &node{name: name, kind: interfaceType}
+			d.fields(n, kinds, v.Interface.Fields, fields)
+			d.nodes = append(d.nodes, n)
+		case *ast.TypeSpec_Union:
+			d.nodes = append(d.nodes, &node{name: name, kind: unionType})
+			for _, mem := range v.Union.Members {
+				d.edges = append(d.edges, &edge{kind: memberEdge, from: mem.Name, to: name})
+			}
+		case *ast.TypeSpec_Enum:
+			n := &node{name: name, kind: enumType}
+			if fields && v.Enum.Values != nil {
+				for _, val := range v.Enum.Values.List {
+					n.members = append(n.members, val.Name.Name)
+				}
+			}
+			d.nodes = append(d.nodes, n)
+		case *ast.TypeSpec_Input:
+			n := &node{name: name, kind: inputType}
+			if v.Input.Fields != nil {
+				for _, f := range v.Input.Fields.List {
+					typ := inputValueType(f)
+					if fields {
+						n.members = append(n.members, f.Name.Name+": "+typeString(typ))
+					}
+
+					to, list := baseType(typ)
+					if kinds[to] == inputType {
+						d.edges = append(d.edges, &edge{kind: fieldEdge, from: name, to: to, label: f.Name.Name, list: list})
+					}
+				}
+			}
+			d.nodes = append(d.nodes, n)
+		}
+	}
+	return d
+}
+
+// fields adds the fields of an object or interface to its node, and
+// joins it to the types they return.
+//
+func (d *diagram) fields(n *node, kinds map[string]string, fields *ast.FieldList, members bool) {
+	if fields == nil {
+		return
+	}
+
+	for _, f := range fields.List {
+		typ := fieldType(f)
+		if members {
+			n.members = append(n.members, fieldString(f, typ))
+		}
+
+		to, list := baseType(typ)
+		if kind, ok := kinds[to]; ok && kind != inputType {
+			d.edges = append(d.edges, &edge{kind: fieldEdge, from: n.name, to: to, label: f.Name.Name, list: list})
+		}
+	}
+}
+
+func fieldString(f *ast.Field, typ interface{}) string {
+	var b strings.Builder
+	b.WriteString(f.Name.Name)
+
+	if f.Args != nil && len(f.Args.List) > 0 {
+		b.WriteByte('(')
+		for i, a := range f.Args.List {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(a.Name.Name)
+			b.WriteString(": ")
+			b.WriteString(typeString(inputValueType(a)))
+		}
+		b.WriteByte(')')
+	}
+
+	b.WriteString(": ")
+	b.WriteString(typeString(typ))
+	return b.String()
+}
+
+func (g *Generator) printMermaid(d *diagram) {
+	g.P("classDiagram")
+	g.In()
+
+	for _, n := range d.nodes {
+		if n.kind == "" && len(n.members) == 0 {
+			g.P("class ", n.name)
+			continue
+		}
+
+		g.P("class ", n.name, " {")
+		g.In()
+		if n.kind != "" {
+			g.P("<<", n.kind, ">>")
+		}
+		for _, m := range n.members {
+			g.P(mermaidMember(m))
+		}
+		g.Out()
+		g.P("}")
+	}
+
+	for _, e := range d.edges {
+		switch e.kind {
+		case fieldEdge:
+			card := ""
+			if e.list {
+				card = `"*" `
+			}
+			g.P(e.from, " --> ", card, e.to, " : ", e.label)
+		case implementsEdge:
+			g.P(e.to, " <|.. ", e.from)
+		case memberEdge:
+			g.P(e.to, " <|-- ", e.from)
+		}
+	}
+
+	g.Out()
+}
+
+// mermaidMember converts a field to Mermaid's member syntax, where the
+// type of a method follows its arguments, rather than a colon.
+//
+func mermaidMember(m string) string {
+	if i := strings.LastIndex(m, "): "); i > -1 {
+		return m[:i+1] + " " + m[i+3:]
+	}
+	return m
+}
+
+func (g *Generator) printDot(name string, d *diagram) {
+	g.P("digraph ", strconv.Quote(name), " {")
+	g.In()
+	g.P("node [shape=record];")
+	g.WriteByte('\n')
+
+	for _, n := range d.nodes {
+		var label strings.Builder
+		label.WriteByte('{')
+		if n.kind != "" {
+			label.WriteString("«")
+			label.WriteString(n.kind)
+			label.WriteString("»\\n")
+		}
+		label.WriteString(n.name)
+
+		if len(n.members) > 0 {
+			label.WriteByte('|')
+			for _, m := range n.members {
+				label.WriteString(dotEscape(m))
+				label.WriteString("\\l")
+			}
+		}
+		label.WriteByte('}')
+
+		g.P(n.name, " [label=\"", label.String(), "\"];")
+	}
+
+	if len(d.edges) > 0 {
+		g.WriteByte('\n')
+	}
+	for _, e := range d.edges {
+		switch e.kind {
+		case fieldEdge:
+			attrs := "label=" + strconv.Quote(e.label)
+			if e.list {
+				attrs += `, headlabel="*"`
+			}
+			g.P(e.from, " -> ", e.to, " [", attrs, "];")
+		case implementsEdge:
+			g.P(e.from, " -> ", e.to, " [arrowhead=empty, style=dashed];")
+		case memberEdge:
+			g.P(e.from, " -> ", e.to, " [arrowhead=empty];")
+		}
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+var dotEscaper = strings.NewReplacer(
+	`"`, `\"`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`|`, `\|`,
+	`<`, `\<`,
+	`>`, `\>`,
+)
+
+// dotEscape escapes the characters which are special in record labels.
+func dotEscape(s string) string { return dotEscaper.Replace(s) }
+
+// baseType returns the named type a field type wraps, and whether
+// it's wrapped in a list.
+//
+func baseType(typ interface{}) (name string, list bool) {
+	for {
+		switch v := typ.(type) {
+		case *ast.Ident:
+			return v.Name, list
+		case *ast.List:
+			list = true
+			switch w := v.Type.(type) {
+			case *ast.List_Ident:
+				typ = w.Ident
+			case *ast.List_List:
+				typ = w.List
+			case *ast.List_NonNull:
+				typ = w.NonNull
+			}
+		case *ast.NonNull:
+			switch w := v.Type.(type) {
+			case *ast.NonNull_Ident:
+				typ = w.Ident
+			case *ast.NonNull_List:
+				typ = w.List
+			}
+		default:
+			return "", list
+		}
+	}
+}
+
+func typeString(typ interface{}) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		return v.Name
+	case *ast.List:
+		var elem interface{}
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			elem = w.Ident
+		case *ast.List_List:
+			elem = w.List
+		case *ast.List_NonNull:
+			elem = w.NonNull
+		}
+		return "[" + typeString(elem) + "]"
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return typeString(w.Ident) + "!"
+		case *ast.NonNull_List:
+			return typeString(w.List) + "!"
+		}
+	}
+	return ""
+}
+
+func fieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func inputValueType(a *ast.InputValue) interface{} {
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Format: Mermaid,
+		Fields: true,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "diagram" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		dOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range dOpts.Fields {
+			switch arg.Key.Name {
+			case "format":
+				gOpts.Format = arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+			case "fields":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Fields = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts != nil {
+		if f, ok := opts["format"].(string); ok {
+			gOpts.Format = f
+		}
+		if f, ok := opts["fields"]; ok {
+			gOpts.Fields, _ = f.(bool)
+		}
+	}
+
+	gOpts.Format = strings.ToUpper(strings.Trim(gOpts.Format, `"`))
+	return
+}
@@ -0,0 +1,384 @@
+package diagram
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "test.mmd", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected diagram output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected diagram output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+const relSrc = `type Query {
+	node(id: ID!): Node
+	search: [SearchResult!]!
+	version: String
+}
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+}
+
+union SearchResult = User | Post
+
+type Post {
+	author: User
+}
+
+enum Role {
+	ADMIN
+	USER
+}
+
+input Filter {
+	role: Role
+	and: [Filter!]
+}`
+
+func TestMermaid(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(relSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("Fields", func(subT *testing.T) {
+		g := new(Generator)
+		g.Reset()
+		g.printMermaid(newDiagram(doc, true))
+
+		ex := `classDiagram
+  class Query {
+    node(id: ID!) Node
+    search: [SearchResult!]!
+    version: String
+  }
+  class Node {
+    <<interface>>
+    id: ID!
+  }
+  class User {
+    id: ID!
+  }
+  class SearchResult {
+    <<union>>
+  }
+  class Post {
+    author: User
+  }
+  class Role {
+    <<enumeration>>
+    ADMIN
+    USER
+  }
+  class Filter {
+    <<input>>
+    role: Role
+    and: [Filter!]
+  }
+  Query --> Node : node
+  Query --> "*" SearchResult : search
+  Node <|.. User
+  SearchResult <|-- User
+  SearchResult <|-- Post
+  Post --> User : author
+  Filter --> "*" Filter : and
+`
+
+		gen.CompareBytes(subT, []byte(ex), g.Bytes())
+	})
+
+	t.Run("NoFields", func(subT *testing.T) {
+		g := new(Generator)
+		g.Reset()
+		g.printMermaid(newDiagram(doc, false))
+
+		ex := `classDiagram
+  class Query
+  class Node {
+    <<interface>>
+  }
+  class User
+  class SearchResult {
+    <<union>>
+  }
+  class Post
+  class Role {
+    <<enumeration>>
+  }
+  class Filter {
+    <<input>>
+  }
+  Query --> Node : node
+  Query --> "*" SearchResult : search
+  Node <|.. User
+  SearchResult <|-- User
+  SearchResult <|-- Post
+  Post --> User : author
+  Filter --> "*" Filter : and
+`
+
+		gen.CompareBytes(subT, []byte(ex), g.Bytes())
+	})
+}
+
+func TestDot(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(relSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	g.Reset()
+	g.printDot("test", newDiagram(doc, true))
+
+	ex := `digraph "test" {
+  node [shape=record];
+
+  Query [label="{Query|node(id: ID!): Node\l` + `search: [SearchResult!]!\l` + `version: String\l}"];
+  Node [label="{«interface»\nNode|id: ID!\l}"];
+  User [label="{User|id: ID!\l}"];
+  SearchResult [label="{«union»\nSearchResult}"];
+  Post [label="{Post|author: User\l}"];
+  Role [label="{«enumeration»\nRole|ADMIN\l` + `USER\l}"];
+  Filter [label="{«input»\nFilter|role: Role\l` + `and: [Filter!]\l}"];
+
+  Query -> Node [label="node"];
+  Query -> SearchResult [label="search", headlabel="*"];
+  User -> Node [arrowhead=empty, style=dashed];
+  User -> SearchResult [arrowhead=empty];
+  Post -> SearchResult [arrowhead=empty];
+  Post -> User [label="author"];
+  Filter -> Filter [label="and", headlabel="*"];
+}
+`
+
+	gen.CompareBytes(t, []byte(ex), g.Bytes())
+}
+
+func TestDotEscape(t *testing.T) {
+	s := dotEscape(`f(a: {b: "c"}): A|B <c>`)
+	ex := `f(a: \{b: \"c\"\}): A\|B \<c\>`
+	if s != ex {
+		t.Errorf("expected: %s, but got: %s", ex, s)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	doc := &ast.Document{
+		Directives: []*ast.DirectiveLit{
+			{
+				Name: "diagram",
+				Args: &ast.CallExpr{Args: []*ast.Arg{{
+					Name: &ast.Ident{Name: "options"},
+					Value: &ast.Arg_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+						Fields: []*ast.ObjLit_Pair{
+							{
+								Key: &ast.Ident{Name: "format"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_IDENT, Value: Dot}}},
+							},
+							{
+								Key: &ast.Ident{Name: "fields"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_BOOL, Value: "false"}}},
+							},
+						},
+					}}}},
+				}}},
+			},
+		},
+	}
+
+	gOpts, err := getOptions(doc, map[string]interface{}{"fields": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.Format != Dot || !gOpts.Fields {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+
+	gOpts, err = getOptions(doc, map[string]interface{}{"format": "mermaid"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.Format != Mermaid {
+		t.Errorf("expected format %s, but got: %s", Mermaid, gOpts.Format)
+	}
+
+	if name := fileName(&ast.Document{Name: "dir/api.gql"}, Dot); name != "api.dot" {
+		t.Errorf("expected file name api.dot, but got: %s", name)
+	}
+
+	err = new(Generator).Generate(gen.WithContext(context.Background(), gen.TestCtx{Writer: ioutil.Discard}), doc, map[string]interface{}{"format": "svg"})
+	if err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := new(Generator)
+	g.Reset()
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `type Query {
+	me: User
+}
+
+type User {
+	name: String!
+	friends: [User!]!
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, nil)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Print(b.String())
+	// Output:
+	// classDiagram
+	//   class Query {
+	//     me: User
+	//   }
+	//   class User {
+	//     name: String!
+	//     friends: [User!]!
+	//   }
+	//   Query --> User : me
+	//   User --> "*" User : friends
+}
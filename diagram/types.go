@@ -0,0 +1,85 @@
+// types.go contains the GraphQL types this generator supports
+
+package diagram
+
+import (
+	"github.com/gqlc/gqlc/types"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+var diagramTypes = []*ast.TypeDecl{
+	{
+		Tok: token.Token_DIRECTIVE,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "diagram"},
+			Type: &ast.TypeSpec_Directive{Directive: &ast.DirectiveType{
+				Locs: []*ast.DirectiveLocation{{Loc: ast.DirectiveLocation_DOCUMENT}},
+				Args: &ast.InputValueList{
+					List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "options"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "DiagramOptions"},
+							},
+						},
+					},
+				},
+			}},
+		}},
+	},
+	{
+		Tok: token.Token_INPUT,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "DiagramOptions"},
+			Type: &ast.TypeSpec_Input{Input: &ast.InputType{
+				Fields: &ast.InputValueList{
+					List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "format"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "DiagramFormat"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_IDENT,
+								Value: Mermaid,
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "fields"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "true",
+							}},
+						},
+					},
+				},
+			}},
+		}},
+	},
+	{
+		Tok: token.Token_ENUM,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "DiagramFormat"},
+			Type: &ast.TypeSpec_Enum{Enum: &ast.EnumType{
+				Values: &ast.FieldList{
+					List: []*ast.Field{
+						{
+							Name: &ast.Ident{Name: Mermaid},
+						},
+						{
+							Name: &ast.Ident{Name: Dot},
+						},
+					},
+				},
+			}},
+		}},
+	},
+}
+
+func init() {
+	types.Register(diagramTypes...)
+}
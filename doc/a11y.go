@@ -0,0 +1,394 @@
+// a11y.go audits the generated HTML against accessibility rules, so
+// documentation which can't be navigated by everyone fails to generate
+
+package doc
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// The rules HTML can be audited against. The first four only apply to
+// standalone pages, since fragments are embedded in another page.
+//
+const (
+	// ruleLang requires the language of a page to be declared.
+	ruleLang = "lang"
+
+	// ruleTitle requires a page to have a title.
+	ruleTitle = "title"
+
+	// ruleLandmarks requires a page to have a single main landmark, and
+	// its navigation to be labelled.
+	//
+	ruleLandmarks = "landmarks"
+
+	// ruleSkipLink requires a page with navigation before its main content
+	// to start with a link which skips it.
+	//
+	ruleSkipLink = "skip-link"
+
+	// ruleHeadingOrder requires heading levels to only increase by one.
+	ruleHeadingOrder = "heading-order"
+
+	// ruleImageAlt requires images to have alternative text.
+	ruleImageAlt = "image-alt"
+
+	// ruleLinkName requires links to have text, or a label.
+	ruleLinkName = "link-name"
+
+	// ruleLinkTarget requires links within a page to go to an element of it.
+	ruleLinkTarget = "link-target"
+)
+
+// a11yRules are every rule, in the order violations are reported.
+var a11yRules = []string{
+	ruleLang,
+	ruleTitle,
+	ruleLandmarks,
+	ruleSkipLink,
+	ruleHeadingOrder,
+	ruleImageAlt,
+	ruleLinkName,
+	ruleLinkTarget,
+}
+
+// a11yAll enables every rule.
+const a11yAll = "all"
+
+// checkA11y checks the names of the rules to audit.
+func checkA11y(rules []string) error {
+	for _, name := range rules {
+		if name == a11yAll {
+			continue
+		}
+
+		known := false
+		for _, r := range a11yRules {
+			known = known || r == name
+		}
+		if !known {
+			return fmt.Errorf("unknown accessibility rule: %s, expected %s or one of: %s", name, a11yAll, strings.Join(a11yRules, ", "))
+		}
+	}
+	return nil
+}
+
+// element is an HTML start or end tag.
+type element struct {
+	name  string
+	attrs map[string]string
+	end   bool
+}
+
+func (e *element) has(attr string) bool {
+	_, ok := e.attrs[attr]
+	return ok
+}
+
+// auditHTML audits HTML, which is a standalone page if page is set,
+// against the given rules and returns an error listing every violation.
+//
+func auditHTML(b []byte, rules []string, page bool) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	enabled := make(map[string]bool, len(a11yRules))
+	for _, name := range rules {
+		if name != a11yAll {
+			enabled[name] = true
+			continue
+		}
+		for _, r := range a11yRules {
+			enabled[r] = true
+		}
+	}
+
+	a := &audit{ids: make(map[string]bool)}
+	a.scan(string(b))
+
+	if page {
+		a.checkPage()
+	}
+	a.checkLinkTargets()
+
+	var msgs []string
+	for _, r := range a11yRules {
+		if !enabled[r] {
+			continue
+		}
+
+		for _, msg := range a.violations[r] {
+			msgs = append(msgs, r+": "+msg)
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("accessibility audit failed:\n\t%s", strings.Join(msgs, "\n\t"))
+}
+
+// audit collects the violations of every rule while scanning HTML.
+type audit struct {
+	violations map[string][]string
+
+	// Document structure
+	lang   string
+	title  *strings.Builder
+	mains  int
+	navs   int
+	skip   bool
+	firstA *element
+	ids    map[string]bool
+
+	// Headings
+	level int
+
+	// Links, whose text is collected while they're open
+	link     *element
+	linkText strings.Builder
+	hrefs    []string
+}
+
+func (a *audit) report(rule, format string, args ...interface{}) {
+	if a.violations == nil {
+		a.violations = make(map[string][]string)
+	}
+	a.violations[rule] = append(a.violations[rule], fmt.Sprintf(format, args...))
+}
+
+// scan walks the tags of HTML, and the text between them.
+func (a *audit) scan(src string) {
+	for i := 0; i < len(src); {
+		lt := strings.IndexByte(src[i:], '<')
+		if lt < 0 {
+			a.text(src[i:])
+			return
+		}
+		a.text(src[i : i+lt])
+		i += lt
+
+		switch {
+		case strings.HasPrefix(src[i:], "<!--"):
+			end := strings.Index(src[i:], "-->")
+			if end < 0 {
+				return
+			}
+			i += end + 3
+			continue
+		case strings.HasPrefix(src[i:], "<!"):
+			end := strings.IndexByte(src[i:], '>')
+			if end < 0 {
+				return
+			}
+			i += end + 1
+			continue
+		}
+
+		e, n := parseTag(src[i:])
+		if e == nil {
+			a.text("<")
+			i++
+			continue
+		}
+		i += n
+		a.element(e)
+
+		// The contents of styles and scripts aren't HTML
+		if !e.end && (e.name == "style" || e.name == "script") {
+			end := strings.Index(src[i:], "</"+e.name)
+			if end < 0 {
+				return
+			}
+			i += end
+		}
+	}
+}
+
+func (a *audit) text(s string) {
+	if a.title != nil {
+		a.title.WriteString(s)
+	}
+	if a.link != nil {
+		a.linkText.WriteString(s)
+	}
+}
+
+func (a *audit) element(e *element) {
+	if id, ok := e.attrs["id"]; ok && !e.end {
+		a.ids[id] = true
+	}
+
+	switch e.name {
+	case "html":
+		if !e.end {
+			a.lang = strings.TrimSpace(e.attrs["lang"])
+		}
+	case "title":
+		if !e.end {
+			a.title = new(strings.Builder)
+		} else if a.title != nil && strings.TrimSpace(a.title.String()) == "" {
+			a.report(ruleTitle, "the <title> is empty")
+		}
+	case "main":
+		if !e.end {
+			a.mains++
+		}
+	case "nav":
+		if e.end {
+			break
+		}
+		a.navs++
+		a.skip = a.skip || a.mains == 0
+		if strings.TrimSpace(e.attrs["aria-label"]) == "" && strings.TrimSpace(e.attrs["aria-labelledby"]) == "" {
+			a.report(ruleLandmarks, "<nav> %d has no aria-label, or aria-labelledby", a.navs)
+		}
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		if e.end {
+			break
+		}
+		level := int(e.name[1] - '0')
+		if a.level > 0 && level > a.level+1 {
+			a.report(ruleHeadingOrder, "<%s> follows <h%d>", e.name, a.level)
+		}
+		a.level = level
+	case "img":
+		if strings.TrimSpace(e.attrs["alt"]) == "" {
+			a.report(ruleImageAlt, "<img src=%q> has no alt text", e.attrs["src"])
+		}
+		if a.link != nil {
+			a.linkText.WriteString(e.attrs["alt"])
+		}
+	case "a":
+		if !e.end {
+			if a.firstA == nil {
+				a.firstA = e
+			}
+			if e.has("href") {
+				a.link = e
+				a.linkText.Reset()
+			}
+			break
+		}
+		if a.link == nil {
+			break
+		}
+
+		href := a.link.attrs["href"]
+		if strings.TrimSpace(a.linkText.String()) == "" && strings.TrimSpace(a.link.attrs["aria-label"]) == "" {
+			a.report(ruleLinkName, "<a href=%q> has no text", href)
+		}
+		if strings.HasPrefix(href, "#") && len(href) > 1 {
+			a.hrefs = append(a.hrefs, href)
+		}
+		a.link = nil
+	}
+}
+
+// checkPage checks the rules which only apply to standalone pages.
+func (a *audit) checkPage() {
+	if a.lang == "" {
+		a.report(ruleLang, "<html> has no lang")
+	}
+	if a.title == nil {
+		a.report(ruleTitle, "there's no <title>")
+	}
+	if a.mains != 1 {
+		a.report(ruleLandmarks, "expected a single <main>, but found %d", a.mains)
+	}
+
+	// Only navigation before the main content needs skipping
+	if !a.skip {
+		return
+	}
+
+	if a.firstA == nil || !strings.HasPrefix(a.firstA.attrs["href"], "#") || !a.ids[a.firstA.attrs["href"][1:]] {
+		a.report(ruleSkipLink, "the first link doesn't skip to the content")
+	}
+}
+
+func (a *audit) checkLinkTargets() {
+	for _, href := range a.hrefs {
+		if !a.ids[href[1:]] {
+			a.report(ruleLinkTarget, "<a href=%q> goes to no element", href)
+		}
+	}
+}
+
+// parseTag parses the tag at the start of src and returns it, along with
+// its length, or nil if src doesn't start with a tag.
+//
+func parseTag(src string) (*element, int) {
+	i := 1
+	e := &element{attrs: make(map[string]string)}
+	if i < len(src) && src[i] == '/' {
+		e.end = true
+		i++
+	}
+
+	start := i
+	for i < len(src) && isTagNameChar(src[i]) {
+		i++
+	}
+	if i == start {
+		return nil, 0
+	}
+	e.name = strings.ToLower(src[start:i])
+
+	for i < len(src) {
+		for i < len(src) && isSpace(src[i]) {
+			i++
+		}
+		if i >= len(src) {
+			return nil, 0
+		}
+
+		switch src[i] {
+		case '>':
+			return e, i + 1
+		case '/':
+			i++
+			continue
+		}
+
+		start = i
+		for i < len(src) && !isSpace(src[i]) && src[i] != '=' && src[i] != '>' && src[i] != '/' {
+			i++
+		}
+		name := strings.ToLower(src[start:i])
+		if i >= len(src) || src[i] != '=' {
+			e.attrs[name] = ""
+			continue
+		}
+		i++
+
+		var val string
+		switch {
+		case i < len(src) && (src[i] == '"' || src[i] == '\''):
+			end := strings.IndexByte(src[i+1:], src[i])
+			if end < 0 {
+				return nil, 0
+			}
+			val = src[i+1 : i+1+end]
+			i += end + 2
+		default:
+			start = i
+			for i < len(src) && !isSpace(src[i]) && src[i] != '>' {
+				i++
+			}
+			val = src[start:i]
+		}
+		e.attrs[name] = html.UnescapeString(val)
+	}
+	return nil, 0
+}
+
+func isTagNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,488 @@
+// asciidoc.go renders the documentation model as AsciiDoc
+
+package doc
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// RenderAsciiDoc renders a documentation model as AsciiDoc, e.g. for Antora,
+// beginning with its title. Every section and type is anchored by its name,
+// so other pages can cross reference them e.g. xref:schema.adoc#User[].
+//
+func RenderAsciiDoc(w io.Writer, m *Model) error {
+	return renderAsciiDoc(w, m, false, false)
+}
+
+func renderAsciiDoc(w io.Writer, m *Model, tables, crossLinks bool) error {
+	a := &asciiDoc{tables: tables, links: newLinker(m, crossLinks)}
+	a.links.xref = true
+	a.writeModel(m)
+
+	_, err := a.WriteTo(w)
+	return err
+}
+
+// asciiDoc writes the AsciiDoc of a model. Descriptions are written as they
+// are, since their Markdown is mostly valid AsciiDoc too.
+//
+type asciiDoc struct {
+	bytes.Buffer
+
+	tables bool
+	links  *linker
+
+	// self is the type being written, and depth how many lists deep the
+	// fields being written are.
+	//
+	self  string
+	depth int
+}
+
+func (a *asciiDoc) writeModel(m *Model) {
+	a.WriteString("= ")
+	a.WriteString(m.Title)
+	a.WriteString("\n:toc:\n:toclevels: 2\n\n")
+	a.WriteString("_This was generated by gqlc._\n")
+
+	if dirs := m.schemaDirectives(); len(dirs) > 0 {
+		a.WriteByte('\n')
+		for i, d := range dirs {
+			if i > 0 {
+				a.WriteByte(' ')
+			}
+			a.WriteString("`+")
+			a.WriteString(d)
+			a.WriteString("+`")
+		}
+		a.WriteByte('\n')
+	}
+
+	for _, s := range m.Sections {
+		name := sectionNames[s.Kind]
+		if s.Kind != schema {
+			name += "s"
+		}
+		a.writeHeader("==", name)
+
+		for _, typ := range s.Types {
+			a.writeType(s.Kind, typ)
+		}
+	}
+
+	for i, op := range m.Operations {
+		if i == 0 {
+			a.writeHeader("==", "Operations")
+		}
+		a.writeOperation(op)
+	}
+
+	if len(m.Deprecations) > 0 {
+		a.writeHeader("==", "Deprecations")
+		a.WriteByte('\n')
+		for _, d := range m.Deprecations {
+			a.WriteString("* <<")
+			a.WriteString(d.Type)
+			a.WriteByte(',')
+			a.WriteString(d.Type)
+			a.WriteByte('.')
+			a.WriteString(d.Name)
+			a.WriteString(">>")
+			if d.Reason != "" {
+				a.WriteString(": ")
+				a.WriteString(d.Reason)
+			}
+			a.WriteByte('\n')
+		}
+	}
+
+	if len(m.Experimental) > 0 {
+		a.WriteString("\n[#")
+		a.WriteString(experimentalAnchor)
+		a.WriteString("]\n== ")
+		a.WriteString(experimentalTitle)
+		a.WriteString("\n\n")
+		for _, e := range m.Experimental {
+			a.WriteString("* <<")
+			a.WriteString(e.Type)
+			a.WriteByte(',')
+			a.WriteString(e.String())
+			a.WriteString(">> ")
+			a.writeBadge(e.Stability)
+			if e.Reason != "" {
+				a.WriteString(": ")
+				a.WriteString(e.Reason)
+			}
+			a.WriteByte('\n')
+		}
+	}
+}
+
+// writeHeader writes an anchored section header.
+func (a *asciiDoc) writeHeader(level, name string) {
+	a.WriteString("\n[#")
+	a.WriteString(name)
+	a.WriteString("]\n")
+	a.WriteString(level)
+	a.WriteByte(' ')
+	a.WriteString(name)
+	a.WriteByte('\n')
+}
+
+func (a *asciiDoc) writeType(kind string, typ *Type) {
+	a.self = typ.Name
+	if kind != schema {
+		a.writeHeader("===", typ.Name)
+	}
+
+	if typ.Stability != "" {
+		a.WriteByte('\n')
+		a.writeBadge(typ.Stability)
+		a.WriteByte('\n')
+		if reason, _ := directiveReason(typ.Directives, typ.Stability); reason != "" {
+			a.WriteString("\n*")
+			a.WriteString(stabilityLabel(typ.Stability))
+			a.WriteString("*: ")
+			a.WriteString(reason)
+			a.WriteByte('\n')
+		}
+	}
+
+	if directives := withoutDirective(typ.Directives, typ.Stability); len(directives) > 0 {
+		a.WriteString("\n*Directives*: ")
+		a.writeDirectives(directives)
+		a.WriteByte('\n')
+	}
+
+	if typ.Description != "" {
+		a.WriteByte('\n')
+		a.WriteString(a.links.link(typ.Description, typ.Name))
+		a.WriteByte('\n')
+	}
+
+	if len(typ.Interfaces) > 0 {
+		a.WriteString("\n*Interfaces*: ")
+		a.writeRefs(typ.Interfaces)
+		a.WriteByte('\n')
+	}
+
+	if len(typ.Members) > 0 {
+		a.WriteString("\n*Members*: ")
+		a.writeRefs(typ.Members)
+		a.WriteByte('\n')
+	}
+
+	if len(typ.Fields) > 0 {
+		label, ok := fieldsLabels[kind]
+		if !ok {
+			label = "Fields"
+		}
+		a.WriteString("\n*")
+		a.WriteString(label)
+		a.WriteString("*:\n\n")
+
+		switch {
+		case a.tables && kind == enum:
+			a.writeValueTable(typ.Fields)
+		case a.tables && kind == input:
+			a.writeInputTable(typ.Fields)
+		default:
+			a.writeFields(typ.Fields)
+		}
+	}
+
+	if len(typ.Usages) > 0 {
+		a.WriteString("\n*Used by*:\n\n")
+		for _, u := range typ.Usages {
+			a.WriteString("* <<")
+			a.WriteString(u.Type)
+			a.WriteByte(',')
+			a.WriteString(u.String())
+			a.WriteString(">>\n")
+		}
+	}
+}
+
+func (a *asciiDoc) writeOperation(op *Operation) {
+	a.writeHeader("===", op.Name)
+	a.WriteString("\n_")
+	a.WriteString(opKindNames[op.Kind])
+	a.WriteString("_\n")
+
+	if op.Description != "" {
+		a.WriteByte('\n')
+		a.WriteString(a.links.link(op.Description, ""))
+		a.WriteByte('\n')
+	}
+
+	if len(op.Variables) > 0 {
+		a.WriteString("\n*Variables*:\n\n")
+
+		vars := make([]*Field, len(op.Variables))
+		for i, v := range op.Variables {
+			vc := *v
+			vc.Name = "$" + v.Name
+			vars[i] = &vc
+		}
+		a.writeFields(vars)
+	}
+
+	if len(op.Selections) > 0 {
+		a.WriteString("\n*Selections*:\n\n")
+		a.writeSelections(op.Selections)
+	}
+}
+
+// writeSelections writes a nested list of selected fields.
+func (a *asciiDoc) writeSelections(sels []*Selection) {
+	a.depth++
+	for _, s := range sels {
+		a.writeBullet()
+
+		switch {
+		case s.Name == "" && s.On == "":
+			a.WriteString("...")
+		case s.Name == "":
+			a.WriteString("... on <<")
+			a.WriteString(s.On)
+			a.WriteString(">>")
+		default:
+			a.WriteByte('`')
+			if s.Alias != "" {
+				a.WriteString(s.Alias)
+				a.WriteString(": ")
+			}
+			a.WriteString(s.Name)
+			a.WriteByte('`')
+
+			if s.Type != "" {
+				a.WriteString(" *(")
+				a.writeTypeRef(s.Type)
+				a.WriteString(")*")
+			}
+		}
+		a.WriteByte('\n')
+
+		a.writeSelections(s.Selections)
+	}
+	a.depth--
+}
+
+// writeFields writes a list of fields, whose descriptions and the like are
+// attached to their item with list continuations.
+//
+func (a *asciiDoc) writeFields(fields []*Field) {
+	a.depth++
+	for _, f := range fields {
+		reason, deprecated := deprecation(f.Directives)
+		directives := withoutDirective(withoutDeprecated(f.Directives), f.Stability)
+
+		a.writeBullet()
+		a.WriteByte('`')
+		a.WriteString(f.Name)
+		a.WriteByte('`')
+		if f.Type != "" {
+			a.WriteString(" *(")
+			a.writeTypeRef(f.Type)
+			a.WriteString(")*")
+		}
+		if deprecated {
+			a.WriteString(" [.deprecated]#*DEPRECATED*#")
+		}
+		if f.Stability != "" {
+			a.WriteByte(' ')
+			a.writeBadge(f.Stability)
+		}
+		a.WriteByte('\n')
+
+		if reason != "" {
+			a.WriteString("+\n*Deprecated*: ")
+			a.WriteString(reason)
+			a.WriteByte('\n')
+		}
+
+		if reason, _ := directiveReason(f.Directives, f.Stability); reason != "" {
+			a.WriteString("+\n*")
+			a.WriteString(stabilityLabel(f.Stability))
+			a.WriteString("*: ")
+			a.WriteString(reason)
+			a.WriteByte('\n')
+		}
+
+		if len(directives) > 0 {
+			a.WriteString("+\n*Directives*: ")
+			a.writeDirectives(directives)
+			a.WriteByte('\n')
+		}
+
+		if f.Description != "" {
+			// Each paragraph needs its own continuation to stay in the item
+			a.WriteString("+\n")
+			a.WriteString(strings.ReplaceAll(a.links.link(f.Description, a.self), "\n\n", "\n+\n"))
+			a.WriteByte('\n')
+		}
+
+		if f.Default != "" {
+			a.WriteString("+\n*Default Value*: `+")
+			a.WriteString(f.Default)
+			a.WriteString("+`\n")
+		}
+
+		if f.Example != "" {
+			a.WriteString("+\n*Example*:\n+\n[source,graphql]\n----\n")
+			a.WriteString(f.Example)
+			a.WriteString("----\n")
+		}
+
+		if len(f.Args) > 0 {
+			a.WriteString("+\n*Args*:\n\n")
+			a.writeFields(f.Args)
+			a.WriteByte('\n')
+		}
+	}
+	a.depth--
+}
+
+// writeValueTable writes a table of enum values.
+func (a *asciiDoc) writeValueTable(values []*Field) {
+	a.WriteString("[cols=\"1,3,1\", options=\"header\"]\n|===\n")
+	a.WriteString("|Value |Description |Deprecated\n")
+
+	for _, v := range values {
+		reason, deprecated := deprecation(v.Directives)
+		dirs := withoutDirective(withoutDeprecated(v.Directives), v.Stability)
+
+		a.WriteString("\n|`")
+		a.WriteString(v.Name)
+		a.WriteByte('`')
+		if v.Stability != "" {
+			a.WriteByte(' ')
+			a.writeBadge(v.Stability)
+		}
+		a.WriteString("\n|")
+		a.writeCell(a.links.link(v.Description, a.self), dirs)
+		a.WriteString("\n|")
+		if deprecated {
+			if reason == "" {
+				reason = "Yes"
+			}
+			a.writeCell(reason, nil)
+		}
+		a.WriteByte('\n')
+	}
+	a.WriteString("|===\n")
+}
+
+// writeInputTable writes a table of input fields.
+func (a *asciiDoc) writeInputTable(fields []*Field) {
+	a.WriteString("[cols=\"1,1,1,3\", options=\"header\"]\n|===\n")
+	a.WriteString("|Field |Type |Default |Description\n")
+
+	for _, f := range fields {
+		a.WriteString("\n|`")
+		a.WriteString(f.Name)
+		a.WriteByte('`')
+		if f.Stability != "" {
+			a.WriteByte(' ')
+			a.writeBadge(f.Stability)
+		}
+		a.WriteString("\n|")
+		a.writeTypeRef(f.Type)
+		a.WriteString("\n|")
+		if f.Default != "" {
+			a.WriteString("`+")
+			a.WriteString(strings.ReplaceAll(f.Default, "|", "\\|"))
+			a.WriteString("+`")
+		}
+		a.WriteString("\n|")
+		a.writeCell(a.links.link(f.Description, a.self), withoutDirective(f.Directives, f.Stability))
+		a.WriteByte('\n')
+	}
+	a.WriteString("|===\n")
+}
+
+// writeCell writes text, followed by any directives, as a single table cell.
+func (a *asciiDoc) writeCell(text string, directives []string) {
+	a.WriteString(strings.ReplaceAll(text, "|", "\\|"))
+	if len(directives) == 0 {
+		return
+	}
+
+	if text != "" {
+		a.WriteString(" +\n")
+	}
+	a.WriteString("*Directives*: ")
+	for i, d := range directives {
+		if i > 0 {
+			a.WriteString(", ")
+		}
+		a.WriteString("`+")
+		a.WriteString(strings.ReplaceAll(d, "|", "\\|"))
+		a.WriteString("+`")
+	}
+}
+
+// writeDirectives writes directives as literals, so their arguments aren't
+// mistaken for attribute references or formatting.
+//
+func (a *asciiDoc) writeDirectives(directives []string) {
+	for i, d := range directives {
+		if i > 0 {
+			a.WriteString(", ")
+		}
+		a.WriteString("`+")
+		a.WriteString(d)
+		a.WriteString("+`")
+	}
+}
+
+// writeRefs writes a comma separated list of cross references to types.
+func (a *asciiDoc) writeRefs(names []string) {
+	for i, name := range names {
+		if i > 0 {
+			a.WriteString(", ")
+		}
+		a.WriteString("<<")
+		a.WriteString(name)
+		a.WriteString(">>")
+	}
+}
+
+// writeTypeRef writes a type, cross referencing its named type unless it's
+// a built-in scalar.
+//
+func (a *asciiDoc) writeTypeRef(typ string) {
+	i := len(typ) - len(strings.TrimLeft(typ, "["))
+	name := strings.TrimRight(typ[i:], "]!")
+
+	switch name {
+	case "Int", "Float", "String", "Boolean", "ID":
+		a.WriteString(typ)
+		return
+	}
+
+	// [[ would begin an anchor
+	a.WriteString(strings.ReplaceAll(typ[:i], "[[", "\\[["))
+	a.WriteString("<<")
+	a.WriteString(name)
+	a.WriteString(">>")
+	a.WriteString(typ[i+len(name):])
+}
+
+// writeBadge writes the badge of a stability level, styled by its role
+// e.g. [.beta]#*BETA*#
+//
+func (a *asciiDoc) writeBadge(level string) {
+	a.WriteString("[.")
+	a.WriteString(level)
+	a.WriteString("]#*")
+	a.WriteString(badge(level))
+	a.WriteString("*#")
+}
+
+func (a *asciiDoc) writeBullet() {
+	a.WriteString(strings.Repeat("*", a.depth))
+	a.WriteByte(' ')
+}
@@ -5,15 +5,15 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-
 	"io"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/gqlc/gqlc/gen"
-	"github.com/gqlc/gqlc/types"
+	"github.com/gqlc/gqlc/gen/printer"
 	"github.com/gqlc/graphql/ast"
-	"github.com/yuin/goldmark"
 	"go.uber.org/zap"
 )
 
@@ -22,7 +22,120 @@ type Options struct {
 	Title string
 	HTML  bool
 
-	toc *[]string
+	// Theme and CSS style the HTML, which is then written as a standalone
+	// page, instead of a fragment. Theme is one of the built-in themes,
+	// light, dark or github, and CSS is a file of styles applied after
+	// the theme, relative to the directory of the schema document.
+	//
+	Theme string
+	CSS   string
+
+	// A11y are the accessibility rules the HTML is audited against, e.g.
+	// image-alt, or all of them with "all". Generation fails if any of
+	// them are violated.
+	//
+	A11y []string
+
+	// RawHTML is what's done with the HTML written in descriptions, which
+	// are otherwise rendered as Markdown: omit it, escape it, sanitize it,
+	// i.e. only keep the tags which format text, or allow it as it is.
+	//
+	RawHTML string
+
+	// AsciiDoc also writes the documentation as AsciiDoc, to a .adoc file.
+	AsciiDoc bool
+
+	// JSON also writes the documentation model as JSON, to a .json file,
+	// or index.json with MultiPage, for tools to consume.
+	//
+	JSON bool
+
+	// Tables renders enum values and input fields as tables,
+	// instead of lists.
+	//
+	Tables bool
+
+	// Operations are executable documents, whose named operations are
+	// documented after the types. Relative paths are resolved against
+	// the directory of the schema document.
+	//
+	Operations []string
+
+	// CrossLinks links the names of types mentioned in descriptions to
+	// their documentation. Names written as [[Name]] are always linked.
+	//
+	CrossLinks bool
+
+	// Templates is a directory of templates which override how the table
+	// of contents, sections, types and fields are rendered. It's relative
+	// to the directory of the schema document.
+	//
+	Templates string
+
+	// Sections are the sections documented, in order, e.g. objects or
+	// enums. Every section is documented, in the order the types are
+	// declared, if it's empty. The operations and deprecations always
+	// follow the types, but can be left out.
+	//
+	Sections []string
+
+	// TocKinds are the kinds of sections listed in the table of contents,
+	// e.g. object or operations, which is every section if it's empty.
+	// TocCollapse lists sections with more types than it without their
+	// types, unless it's 0.
+	//
+	TocKinds    []string
+	TocCollapse int
+
+	// AlphaIndex also writes an alphabetical index of the types, which
+	// the table of contents links to.
+	//
+	AlphaIndex bool
+
+	// Examples documents every root field with an example operation
+	// calling it, which selects the fields of what it returns.
+	//
+	Examples bool
+
+	// Deprecations summarizes everything deprecated in a section at the
+	// end of the documentation.
+	//
+	Deprecations bool
+
+	// Experimental are the directives marking types, fields, arguments,
+	// input fields and enum values as experimental e.g. beta, which are
+	// badged as such and summarized in a section at the end of the
+	// documentation. It's experimental and beta, unless it's set.
+	//
+	Experimental []string
+
+	// DirectiveUsage lists where each directive declared in the schema
+	// is applied, in the directive's documentation.
+	//
+	DirectiveUsage bool
+
+	// MultiPage writes the documentation as a page per kind of type, or
+	// per type if Pages is "type", along with an index page holding the
+	// table of contents.
+	//
+	MultiPage bool
+	Pages     string
+
+	// Wiki writes the documentation as GitHub wiki pages, a page per type
+	// linked by [[Name]], along with a Home page holding the table of
+	// contents and a _Sidebar listing every page.
+	//
+	Wiki bool
+
+	// Frontmatter writes YAML frontmatter at the top of each Markdown file,
+	// for static site generators e.g. Hugo, Docusaurus or Jekyll. It holds
+	// the title, Slug and Weight, which pages are nested under and weighted
+	// after, and FrontmatterKeys, which are given as key=value.
+	//
+	Frontmatter     bool
+	Slug            string
+	Weight          int
+	FrontmatterKeys []string
 }
 
 const (
@@ -34,73 +147,69 @@ const (
 	enum      = "enum"
 	input     = "input"
 	directive = "directive"
+
+	// operations, deprecations and experimental aren't kinds of types,
+	// but are paged like them
+	//
+	operations   = "operations"
+	deprecations = "deprecations"
+	experimental = "experimental"
 )
 
-type declType uint16
+// Generator generates CommonMark documentation for GraphQL Documents.
+type Generator struct {
+	printer.Printer
 
-const (
-	schemaType declType = 1 << iota
-	scalarType
-	objectType
-	interType
-	unionType
-	enumType
-	inputType
-	directiveType
-	extendType
-)
+	tables bool
 
-func (o *Options) addContent(name string, typ, mask declType) declType {
-	if mask&typ != 0 {
-		switch typ {
-		case schemaType:
-		case scalarType:
-			*o.toc = append(*o.toc, scalar)
-		case objectType:
-			*o.toc = append(*o.toc, object)
-		case interType:
-			*o.toc = append(*o.toc, inter)
-		case unionType:
-			*o.toc = append(*o.toc, union)
-		case enumType:
-			*o.toc = append(*o.toc, enum)
-		case inputType:
-			*o.toc = append(*o.toc, input)
-		case directiveType:
-			*o.toc = append(*o.toc, directive)
-		}
+	// rawHTML is the policy for the HTML written in descriptions.
+	rawHTML string
 
-		mask &= ^typ
-	}
+	// crossLinks is set to link every type mentioned in descriptions, and
+	// self is the type being generated, which isn't linked to.
+	//
+	crossLinks bool
+	links      *linker
+	self       string
 
-	*o.toc = append(*o.toc, name)
+	// docs maps the types of the other documents being generated to
+	// their documentation, so references to them aren't dead links.
+	//
+	docs map[string]string
 
-	return mask
-}
+	// tmpls override how the documentation is rendered, if they're set,
+	// and err is the first error executing them.
+	//
+	tmpls *templates
+	err   error
 
-// Generator generates CommonMark documentation for GraphQL Documents.
-type Generator struct {
-	sync.Mutex
-	bytes.Buffer
+	// htmlPage wraps the HTML, when it's styled, and a11y are the rules
+	// it's audited against.
+	//
+	htmlPage *htmlPage
+	a11y     []string
+
+	// toc controls what the table of contents lists.
+	toc *tocOptions
 
-	indent []byte
+	// front is written at the top of Markdown files, if it's set.
+	front *frontmatter
 
 	mdOnce sync.Once
 	log    *zap.Logger
 }
 
-// Reset overrides the bytes.Buffer Reset method to assist in cleaning up some Generator state.
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
 func (g *Generator) Reset() {
-	g.Buffer.Reset()
-	if g.indent == nil {
-		g.indent = make([]byte, 0, 2)
-	}
-	g.indent = g.indent[0:0]
+	g.Printer.Reset()
 }
 
 // Generate generates CommonMark documentation for the given document.
-func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
-	g.Lock()
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
 	defer func() {
 		if err != nil {
 			err = gen.GeneratorError{
@@ -110,12 +219,14 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 			}
 		}
 	}()
-	defer g.Unlock()
 	g.Reset()
 
 	// Register logger
-	if g.log == nil {
-		g.log = zap.L().Named("doc").With(zap.String("doc", doc.Name))
+	g.log = zap.L().Named("doc").With(zap.String("doc", doc.Name))
+
+	// Check the document is well formed
+	if verr := gen.ValidateShapeContext(gen.Context(ctx), doc); verr != nil {
+		return verr
 	}
 
 	// Get generator options
@@ -125,266 +236,570 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 		return oerr
 	}
 
+	err = checkSections(gOpts.Sections)
+	if err != nil {
+		return
+	}
+	err = checkA11y(gOpts.A11y)
+	if err != nil {
+		return
+	}
+	err = checkRawHTML(gOpts.RawHTML)
+	if err != nil {
+		return
+	}
+
 	// Generate types
 	g.log.Info("generating types")
-	g.generateTypes(doc.Types, gOpts)
+	g.tables = gOpts.Tables
+	g.rawHTML = gOpts.RawHTML
+	g.crossLinks = gOpts.CrossLinks
+	g.a11y = gOpts.A11y
+	m := BuildModel(doc, gOpts)
 
 	// Extract generator context
 	gCtx := gen.Context(ctx)
 
-	// Open .md file
+	// Read templates
+	if gOpts.Templates != "" {
+		g.log.Info("reading templates")
+		g.tmpls, err = readTemplates(gCtx, doc, gOpts.Templates)
+		if err != nil {
+			return
+		}
+	}
+
+	// Read styles
+	if gOpts.HTML {
+		g.htmlPage, err = newHTMLPage(gCtx, doc, gOpts)
+		if err != nil {
+			return
+		}
+	}
+
+	// Parse operations
+	if (len(gOpts.Operations) > 0 || len(gen.Operations(gCtx)) > 0) && showsSection(gOpts, operations) {
+		g.log.Info("parsing operations")
+		m.Operations, err = readOperations(gCtx, doc, gOpts.Operations)
+		if err != nil {
+			return
+		}
+	}
 	base := doc.Name[:len(doc.Name)-len(filepath.Ext(doc.Name))]
-	docFile, err := gCtx.Open(base + ".md")
+
+	// Configure table of contents
+	var index string
+	switch {
+	case gOpts.AlphaIndex && (gOpts.MultiPage || gOpts.Wiki):
+		index = alphabetical
+	case gOpts.AlphaIndex:
+		index = filepath.Base(base) + "-" + alphabetical
+	}
+	g.toc, err = newTocOptions(gOpts, index)
 	if err != nil {
 		return
 	}
-	defer docFile.Close()
 
-	// Write Title and Table of Contents
-	_, err = writeToC(docFile, gOpts)
+	g.front, err = newFrontmatter(gOpts)
 	if err != nil {
 		return
 	}
 
-	// Write markdown
-	b := g.Bytes()
-	_, err = docFile.Write(b)
+	g.docs = otherDocs(gCtx, doc, gOpts)
+
+	switch {
+	case gOpts.Wiki:
+		g.log.Info("writing wiki")
+		err = g.writeWiki(gCtx, base, m, gOpts)
+	case gOpts.MultiPage:
+		g.log.Info("writing pages")
+		err = g.writePages(gCtx, base, m, gOpts)
+	default:
+		err = g.writeFile(gCtx, base, m, gOpts)
+	}
 	if err != nil {
 		return
 	}
 
-	if !gOpts.HTML {
+	if gOpts.JSON {
+		g.log.Info("writing json")
+		name := base + ".json"
+		if gOpts.MultiPage {
+			name = base + "/index.json"
+		}
+
+		err = g.writeJSONFile(gCtx, name, m)
+		if err != nil {
+			return
+		}
+	}
+
+	if !gOpts.AsciiDoc {
 		return
 	}
 
-	// Open HTML file
-	htmlFile, err := gCtx.Open(base + ".html")
+	g.log.Info("writing asciidoc")
+	adocFile, err := gCtx.Open(base + ".adoc")
 	if err != nil {
 		return
 	}
-	defer htmlFile.Close()
+	defer adocFile.Close()
 
-	err = goldmark.Convert(b, htmlFile)
-	return
+	return renderAsciiDoc(adocFile, m, gOpts.Tables, gOpts.CrossLinks)
 }
 
-func noopGen(*ast.TypeSpec) {}
+// writeFile writes the documentation to a single .md file, and .html file
+// if it's enabled.
+//
+func (g *Generator) writeFile(gCtx gen.GeneratorContext, base string, m *Model, gOpts *Options) error {
+	g.generateModel(m)
+	if g.err != nil {
+		return g.err
+	}
 
-func (g *Generator) generateTypes(types []*ast.TypeDecl, opts *Options) {
-	var fieldsBuf bytes.Buffer
-	var typ declType
-	var ts *ast.TypeSpec
-	mask := schemaType | scalarType | objectType | interType | unionType | enumType | inputType | directiveType
-	tLen := len(types) - 1
-	for i, decl := range types {
-		d, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
-		if !ok {
-			panic("only expected type spec and not type ext specs.")
+	// Open .md file
+	docFile, err := gCtx.Open(base + ".md")
+	if err != nil {
+		return err
+	}
+	defer docFile.Close()
+
+	// Write markdown
+	var md bytes.Buffer
+	err = g.writeMarkdown(&md, m)
+	if err != nil {
+		return err
+	}
+	err = g.front.write(docFile, m.Title)
+	if err != nil {
+		return err
+	}
+	_, err = docFile.Write(md.Bytes())
+	if err != nil {
+		return err
+	}
+
+	if gOpts.AlphaIndex {
+		err = g.writeIndex(gCtx, base, m, gOpts)
+		if err != nil {
+			return err
 		}
-		ts = d.TypeSpec
+	}
+	if !gOpts.HTML {
+		return nil
+	}
+
+	// Open HTML file
+	htmlFile, err := gCtx.Open(base + ".html")
+	if err != nil {
+		return err
+	}
+	defer htmlFile.Close()
 
-		// Add to Table of Contents
-		name := schema
-		if ts.Name != nil {
-			name = ts.Name.Name
+	// Links to other documents go to their .html files
+	g.links.ext = ".html"
+	if len(g.docs) > 0 {
+		g.Reset()
+		g.generateSections(m)
+		if g.err != nil {
+			return g.err
 		}
+	}
 
-		// Generate type
-		gen := noopGen
-		switch v := ts.Type.(type) {
-		case *ast.TypeSpec_Schema:
-			if mask&schemaType != 0 {
-				g.writeSectionHeader("Schema")
-			}
-			if v.Schema.RootOps == nil {
-				break
-			}
+	// A standalone page has the title and table of contents, while a
+	// fragment is left to be embedded under those of another page.
+	//
+	if g.htmlPage == nil {
+		return g.writeHTML(htmlFile, base+".html", g.Bytes())
+	}
 
-			gen = func(_ *ast.TypeSpec) {
-				g.WriteByte('\n')
-				g.P("*Root Operations*:")
-				g.generateFields(v.Schema.RootOps.List, &fieldsBuf)
-			}
+	// The table of contents links to other .html files
+	md.Reset()
+	err = g.writeMarkdown(&md, m)
+	if err != nil {
+		return err
+	}
+	return g.writeHTML(htmlFile, base+".html", md.Bytes())
+}
 
-			typ = schemaType
-		case *ast.TypeSpec_Scalar:
-			if mask&scalarType != 0 {
-				g.writeSectionHeader("Scalar")
-				g.WriteByte('\n')
-			}
+// readOperations reads and parses the given executable documents, along
+// with those carried by the generator context.
+//
+func readOperations(gCtx gen.GeneratorContext, doc *ast.Document, files []string) ([]*Operation, error) {
+	var srcs []OperationSource
+	for _, op := range gen.Operations(gCtx) {
+		srcs = append(srcs, OperationSource{Name: op.Name, Src: op.Source})
+	}
+	if len(files) == 0 {
+		return ParseOperations(doc, srcs...)
+	}
 
-			typ = scalarType
-		case *ast.TypeSpec_Object:
-			if mask&objectType != 0 {
-				g.writeSectionHeader("Object")
-				g.WriteByte('\n')
-			}
+	pCtx, ok := gCtx.(gen.PathContext)
+	if !ok {
+		return nil, fmt.Errorf("operations can't be read by this generator context")
+	}
 
-			gen = g.generateObject
+	dir := filepath.Dir(pCtx.Source(doc))
+	for _, name := range files {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
 
-			typ = objectType
-		case *ast.TypeSpec_Interface:
-			if mask&interType != 0 {
-				g.writeSectionHeader("Interface")
-				g.WriteByte('\n')
-			}
-			if v.Interface.Fields == nil {
-				break
-			}
+		b, err := pCtx.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		srcs = append(srcs, OperationSource{Name: name, Src: string(b)})
+	}
+	return ParseOperations(doc, srcs...)
+}
 
-			gen = func(_ *ast.TypeSpec) {
-				g.WriteByte('\n')
-				g.P("*Fields*:")
-				g.generateFields(v.Interface.Fields.List, &fieldsBuf)
-			}
+// RenderMarkdown renders a documentation model as CommonMark, beginning with
+// its title and a table of contents.
+//
+func RenderMarkdown(w io.Writer, m *Model) error {
+	g := new(Generator)
+	g.Reset()
+	g.generateModel(m)
+	return g.writeMarkdown(w, m)
+}
 
-			typ = interType
-		case *ast.TypeSpec_Union:
-			if mask&unionType != 0 {
-				g.writeSectionHeader("Union")
-				g.WriteByte('\n')
-			}
-			if len(v.Union.Members) == 0 {
-				break
-			}
+// RenderHTML renders the types of a documentation model as HTML. The title
+// and table of contents are left out, so the HTML can be embedded in
+// another page.
+//
+func RenderHTML(w io.Writer, m *Model) error {
+	g := new(Generator)
+	g.Reset()
+	g.generateModel(m)
+	return convertMarkdown(w, g.Bytes(), false, rawOmit)
+}
 
-			gen = func(_ *ast.TypeSpec) {
-				g.WriteByte('\n')
-				g.WriteString("*Members*: ")
-				mLen := len(v.Union.Members) - 1
-				for i, m := range v.Union.Members {
-					g.WriteByte('*')
-					g.WriteByte('*')
-					g.WriteByte('[')
-					g.WriteString(m.Name)
-					g.WriteByte(']')
-					g.WriteByte('(')
-					g.WriteByte('#')
-					g.WriteString(m.Name)
-					g.WriteByte(')')
-					g.WriteByte('*')
-					g.WriteByte('*')
-					if i != mLen {
-						g.WriteByte(',')
-						g.WriteByte(' ')
-					}
-				}
-				g.WriteByte('\n')
-			}
+// writeMarkdown writes the Title, Table of Contents and the generated types.
+func (g *Generator) writeMarkdown(w io.Writer, m *Model) (err error) {
+	if g.tmpls != nil {
+		toc := g.fork()
+		g.tmpls.execute(toc, "toc", m)
+		if toc.err != nil {
+			return toc.err
+		}
+		_, err = toc.WriteTo(w)
+	} else {
+		_, err = writeToC(w, m, g.links, g.toc)
+	}
+	if err != nil {
+		return err
+	}
 
-			typ = unionType
-		case *ast.TypeSpec_Enum:
-			if mask&enumType != 0 {
-				g.writeSectionHeader("Enum")
-				g.WriteByte('\n')
-			}
-			if v.Enum.Values == nil {
-				break
-			}
+	_, err = w.Write(g.Bytes())
+	return err
+}
 
-			gen = func(_ *ast.TypeSpec) {
-				g.WriteByte('\n')
-				g.P("*Values*:")
-				g.generateFields(v.Enum.Values.List, &fieldsBuf)
-			}
+var sectionNames = map[string]string{
+	schema:    "Schema",
+	scalar:    "Scalar",
+	object:    "Object",
+	inter:     "Interface",
+	union:     "Union",
+	enum:      "Enum",
+	input:     "Input",
+	directive: "Directive",
+}
 
-			typ = enumType
-		case *ast.TypeSpec_Input:
-			if mask&inputType != 0 {
-				g.writeSectionHeader("Input")
-				g.WriteByte('\n')
-			}
-			if v.Input.Fields == nil {
-				break
-			}
+// fieldsLabels are the names given to the fields of each kind of type.
+var fieldsLabels = map[string]string{
+	schema:    "Root Operations",
+	enum:      "Values",
+	directive: "Args",
+}
 
-			gen = func(_ *ast.TypeSpec) {
-				g.WriteByte('\n')
-				g.P("*Fields*:")
-				g.generateArgs(v.Input.Fields.List, &fieldsBuf)
-			}
+func (g *Generator) generateModel(m *Model) {
+	g.links = newLinker(m, g.crossLinks)
+	g.links.docs = g.docs
+	g.generateSections(m)
+}
 
-			typ = inputType
-		case *ast.TypeSpec_Directive:
-			if mask&directiveType != 0 {
-				g.writeSectionHeader("Directive")
-				g.WriteByte('\n')
-			}
-			if v.Directive.Args == nil {
-				break
-			}
+// generateSections generates the sections of a model, with the links
+// of the generator.
+//
+func (g *Generator) generateSections(m *Model) {
+	first := true
+	for _, s := range m.Sections {
+		if len(s.Types) == 0 {
+			continue
+		}
+		if !first {
+			g.WriteByte('\n')
+		}
+		first = false
 
-			gen = func(_ *ast.TypeSpec) {
-				g.WriteByte('\n')
-				g.P("*Args*:")
-				g.generateArgs(v.Directive.Args.List, &fieldsBuf)
-			}
+		g.execute("section", s, func() { g.generateSection(s) })
+	}
 
-			typ = directiveType
-		default:
-			panic("unknown type spec type")
+	if len(m.Operations) > 0 {
+		if !first {
+			g.WriteByte('\n')
 		}
+		first = false
 
-		mask = opts.addContent(name, typ, mask)
-		if typ != schemaType {
-			g.writeTypeHeader(name)
+		g.generateOperations(m.Operations)
+	}
+
+	if len(m.Deprecations) > 0 {
+		if !first {
+			g.WriteByte('\n')
 		}
+		first = false
 
-		dirs := filterDirectives(ts.Directives)
-		if len(dirs) > 0 {
-			g.Write(g.indent)
-			g.WriteString("*Directives*: ")
-			g.writeDirectives(dirs)
+		g.generateDeprecations(m.Deprecations)
+	}
+
+	if len(m.Experimental) > 0 {
+		if !first {
 			g.WriteByte('\n')
 		}
+		g.generateExperimental(m.Experimental)
+	}
+}
 
-		decl.Doc.TextTo(&g.Buffer)
+// generateOperations generates the Operations section.
+func (g *Generator) generateOperations(ops []*Operation) {
+	g.WriteString("## Operations\n\n")
+	for i, op := range ops {
+		if i > 0 {
+			g.WriteByte('\n')
+		}
+		g.generateOperation(op)
+	}
+}
 
-		gen(ts)
+// generateSection generates the header of a section, followed by its types.
+func (g *Generator) generateSection(s *Section) {
+	g.writeSectionHeader(sectionNames[s.Kind])
+	if s.Kind != schema {
+		g.WriteByte('\n')
+	}
 
-		if i != tLen {
+	for i, typ := range s.Types {
+		if i > 0 {
 			g.WriteByte('\n')
 		}
+		g.execute("type", typeData{Kind: s.Kind, Type: typ}, func() { g.generateType(s.Kind, typ) })
 	}
 }
 
-func filterDirectives(dirs []*ast.DirectiveLit) (fdirs []*ast.DirectiveLit) {
-	if len(dirs) == 0 {
+// execute executes the named template with data, or calls def to generate
+// the default output if there aren't any templates.
+//
+func (g *Generator) execute(name string, data interface{}, def func()) {
+	if g.tmpls == nil {
+		def()
 		return
 	}
-	fdirs = make([]*ast.DirectiveLit, 0, len(dirs))
+	g.tmpls.execute(g, name, data)
+}
 
-	for _, d := range dirs {
-		if types.IsGqlcDirective(d.Name) {
-			continue
+// fork returns an empty Generator which generates just like g, from where
+// g is at e.g. its indentation.
+//
+func (g *Generator) fork() *Generator {
+	f := &Generator{
+		tables:     g.tables,
+		rawHTML:    g.rawHTML,
+		crossLinks: g.crossLinks,
+		links:      g.links,
+		docs:       g.docs,
+		self:       g.self,
+		tmpls:      g.tmpls,
+		htmlPage:   g.htmlPage,
+		a11y:       g.a11y,
+		toc:        g.toc,
+		front:      g.front,
+		log:        g.log,
+	}
+	f.SetIndentation(g.Indentation())
+	return f
+}
+
+// generateDeprecations generates the Deprecations section, which links
+// everything deprecated to the type it belongs to.
+//
+func (g *Generator) generateDeprecations(deps []*Deprecation) {
+	g.WriteString("## Deprecations\n\n")
+	for _, d := range deps {
+		g.WriteString("- ")
+		g.WriteString(g.links.ref(d.Type+"."+d.Name, d.Type))
+		if d.Reason != "" {
+			g.WriteString(": ")
+			g.WriteString(d.Reason)
 		}
+		g.WriteByte('\n')
+	}
+}
+
+var opKindNames = map[string]string{
+	"query":        "Query",
+	"mutation":     "Mutation",
+	"subscription": "Subscription",
+}
 
-		fdirs = append(fdirs, d)
+func (g *Generator) generateOperation(op *Operation) {
+	g.writeTypeHeader(op.Name)
+	g.P("*", opKindNames[op.Kind], "*")
+
+	if op.Description != "" {
+		g.WriteByte('\n')
+		g.WriteString(g.links.link(op.Description, ""))
+		g.WriteByte('\n')
 	}
 
-	return
+	if len(op.Variables) > 0 {
+		g.WriteByte('\n')
+		g.P("*Variables*:")
+
+		vars := make([]*Field, len(op.Variables))
+		for i, v := range op.Variables {
+			vc := *v
+			vc.Name = "$" + v.Name
+			vars[i] = &vc
+		}
+		g.generateFields(vars)
+	}
+
+	if len(op.Selections) > 0 {
+		g.WriteByte('\n')
+		g.P("*Selections*:")
+		g.generateSelections(op.Selections)
+	}
 }
 
-var (
-	schemaName, schemaLink       = []byte("Schema"), []byte("](#Schema")
-	scalarName, scalarLink       = []byte("Scalar"), []byte("s](#Scalar")
-	objectName, objectLink       = []byte("Object"), []byte("s](#Object")
-	interName, interLink         = []byte("Interface"), []byte("s](#Interface")
-	unionName, unionLink         = []byte("Union"), []byte("s](#Union")
-	enumName, enumLink           = []byte("Enum"), []byte("s](#Enum")
-	inputName, inputLink         = []byte("Input"), []byte("s](#Input")
-	directiveName, directiveLink = []byte("Directive"), []byte("s](#Directive")
-)
+// generateSelections generates a nested list of selected fields, linking
+// each to the documentation of its type.
+//
+func (g *Generator) generateSelections(sels []*Selection) {
+	for _, s := range sels {
+		g.WriteIndent()
+		g.WriteString("- ")
+
+		switch {
+		case s.Name == "" && s.On == "":
+			g.WriteString("...")
+		case s.Name == "":
+			g.WriteString("... on ")
+			g.WriteString(g.links.ref(s.On, s.On))
+		default:
+			if s.Alias != "" {
+				g.WriteString(s.Alias)
+				g.WriteString(": ")
+			}
+			g.WriteString(s.Name)
+
+			if s.Type != "" {
+				g.WriteString(" **(")
+				g.printType(s.Type)
+				g.WriteString(")**")
+			}
+		}
+		g.WriteByte('\n')
+
+		g.In()
+		g.generateSelections(s.Selections)
+		g.Out()
+	}
+}
+
+func (g *Generator) generateType(kind string, typ *Type) {
+	g.self = typ.Name
+	if kind != schema {
+		g.writeTypeHeader(typ.Name)
+	}
+
+	directives := withoutDirective(typ.Directives, typ.Stability)
+	if typ.Stability != "" {
+		g.writeStability(typ)
+		if len(directives) > 0 || typ.Description != "" {
+			g.WriteByte('\n')
+		}
+	}
 
-// writeToC writes the Title and Table of Contents to the given io.Writer.
-func writeToC(w io.Writer, opts *Options) (int64, error) {
+	if len(directives) > 0 {
+		g.WriteIndent()
+		g.WriteString("*Directives*: ")
+		g.writeDirectives(directives)
+		g.WriteByte('\n')
+	}
+
+	if typ.Description != "" {
+		g.WriteString(g.links.link(typ.Description, typ.Name))
+		g.WriteByte('\n')
+	}
+
+	if len(typ.Interfaces) > 0 {
+		g.WriteByte('\n')
+		g.WriteIndent()
+		g.WriteString("*Interfaces*: ")
+		g.WriteString(strings.Join(typ.Interfaces, ", "))
+		g.WriteByte('\n')
+	}
+
+	if len(typ.Members) > 0 {
+		g.WriteByte('\n')
+		g.WriteString("*Members*: ")
+		for i, m := range typ.Members {
+			if i > 0 {
+				g.WriteString(", ")
+			}
+			g.WriteString("**")
+			g.WriteString(g.links.ref(m, m))
+			g.WriteString("**")
+		}
+		g.WriteByte('\n')
+	}
+
+	if len(typ.Fields) > 0 {
+		label, ok := fieldsLabels[kind]
+		if !ok {
+			label = "Fields"
+		}
+
+		g.WriteByte('\n')
+		g.P("*", label, "*:")
+
+		switch {
+		case g.tables && kind == enum:
+			g.WriteByte('\n')
+			g.generateValueTable(typ.Fields)
+		case g.tables && kind == input:
+			g.WriteByte('\n')
+			g.generateInputTable(typ.Fields)
+		default:
+			g.generateFields(typ.Fields)
+		}
+	}
+
+	if len(typ.Usages) > 0 {
+		g.WriteByte('\n')
+		g.P("*Used by*:")
+		for _, u := range typ.Usages {
+			g.WriteString("- ")
+			g.WriteString(g.links.ref(u.String(), u.Type))
+			g.WriteByte('\n')
+		}
+	}
+}
+
+// writeToC writes the Title and Table of Contents to the given io.Writer,
+// linking to the documentation of each section and type with l. What's
+// listed is controlled by toc, which lists everything if it's nil.
+//
+func writeToC(w io.Writer, m *Model, l *linker, toc *tocOptions) (int64, error) {
 	var b bytes.Buffer
 	b.Grow(bytes.MinRead)
 
 	// Title
 	b.WriteByte('#')
 	b.WriteByte(' ')
-	b.WriteString(opts.Title)
+	b.WriteString(m.Title)
 	b.WriteByte('\n')
 
 	// Generated line
@@ -392,35 +807,85 @@ func writeToC(w io.Writer, opts *Options) (int64, error) {
 	b.WriteByte('\n')
 	b.WriteByte('\n')
 
+	// Badges of the directives applied to the schema
+	if dirs := m.schemaDirectives(); len(dirs) > 0 {
+		for i, d := range dirs {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteByte('`')
+			b.WriteString(d)
+			b.WriteByte('`')
+		}
+		b.WriteByte('\n')
+		b.WriteByte('\n')
+	}
+
 	// Table of Contents
 	b.WriteString("## Table of Contents")
 	b.WriteByte('\n')
 
-	for _, s := range *opts.toc {
-		switch s {
-		case schema:
-			writeContentLink(&b, schemaName, schemaLink, false)
-		case scalar:
-			writeContentLink(&b, scalarName, scalarLink, true)
-		case object:
-			writeContentLink(&b, objectName, objectLink, true)
-		case inter:
-			writeContentLink(&b, interName, interLink, true)
-		case union:
-			writeContentLink(&b, unionName, unionLink, true)
-		case enum:
-			writeContentLink(&b, enumName, enumLink, true)
-		case input:
-			writeContentLink(&b, inputName, inputLink, true)
-		case directive:
-			writeContentLink(&b, directiveName, directiveLink, true)
-		default:
-			b.WriteByte('\t')
-			b.Write([]byte("* ["))
-			b.WriteString(s)
-			b.Write([]byte("](#"))
-			b.WriteString(s)
-			b.WriteByte(')')
+	for _, s := range m.Sections {
+		if !toc.lists(s.Kind) {
+			continue
+		}
+
+		name := sectionNames[s.Kind]
+		if s.Kind != schema {
+			name += "s"
+		}
+		b.WriteString("- ")
+		writeContentLink(&b, name, l.sectionHref(s.Kind, name))
+		b.WriteByte('\n')
+
+		if s.Kind == schema || toc.collapses(len(s.Types)) {
+			continue
+		}
+
+		for _, typ := range s.Types {
+			b.WriteString("\t* ")
+			b.WriteString(l.ref(typ.Name, typ.Name))
+			b.WriteByte('\n')
+		}
+	}
+
+	if len(m.Operations) > 0 && toc.lists(operations) {
+		b.WriteString("- ")
+		writeContentLink(&b, "Operations", l.sectionHref(operations, "Operations"))
+		b.WriteByte('\n')
+		for _, op := range m.Operations {
+			if toc.collapses(len(m.Operations)) {
+				break
+			}
+
+			b.WriteString("\t* ")
+			writeContentLink(&b, op.Name, l.opHref(op.Name))
+			b.WriteByte('\n')
+		}
+	}
+
+	if len(m.Deprecations) > 0 && toc.lists(deprecations) {
+		b.WriteString("- ")
+		writeContentLink(&b, "Deprecations", l.sectionHref(deprecations, "Deprecations"))
+		b.WriteByte('\n')
+	}
+
+	if len(m.Experimental) > 0 && toc.lists(experimental) {
+		b.WriteString("- ")
+		writeContentLink(&b, experimentalTitle, l.sectionHref(experimental, experimentalAnchor))
+		b.WriteByte('\n')
+	}
+
+	if toc != nil && toc.index != "" {
+		ext := ".md"
+		if l != nil && l.ext != "" {
+			ext = l.ext
+		}
+		b.WriteString("- ")
+		if l != nil && l.wiki {
+			b.WriteString(wikiLink(alphabeticalTitle, toc.index))
+		} else {
+			writeContentLink(&b, alphabeticalTitle, toc.index+ext)
 		}
 		b.WriteByte('\n')
 	}
@@ -429,13 +894,17 @@ func writeToC(w io.Writer, opts *Options) (int64, error) {
 	return b.WriteTo(w)
 }
 
-func writeContentLink(b *bytes.Buffer, name, link []byte, addS bool) {
-	b.Write([]byte("- ["))
-	b.Write(name)
-	b.Write(link)
-	if addS {
-		b.WriteByte('s')
+// writeContentLink writes a link, or just its name if there's nowhere to link to.
+func writeContentLink(b *bytes.Buffer, name, href string) {
+	if href == "" {
+		b.WriteString(name)
+		return
 	}
+
+	b.WriteByte('[')
+	b.WriteString(name)
+	b.WriteString("](")
+	b.WriteString(href)
 	b.WriteByte(')')
 }
 
@@ -463,370 +932,270 @@ func (g *Generator) writeTypeHeader(name string) {
 	g.WriteByte('\n')
 }
 
-func (g *Generator) writeDirectives(directives []*ast.DirectiveLit) {
-	dLen := len(directives) - 1
-	for i, d := range directives {
-		g.WriteByte('@')
-		g.WriteString(d.Name)
-
-		if d.Args != nil {
-			g.WriteByte('(')
-
-			aLen := len(d.Args.Args) - 1
-			for j, a := range d.Args.Args {
-				g.WriteString(a.Name.Name)
-				g.WriteByte(':')
-				g.WriteByte(' ')
-
-				var aVal interface{}
-				switch v := a.Value.(type) {
-				case *ast.Arg_BasicLit:
-					aVal = v.BasicLit
-				case *ast.Arg_CompositeLit:
-					aVal = v.CompositeLit
-				}
-				g.printVal(aVal)
-
-				if j != aLen {
-					g.WriteByte(',')
-					g.WriteByte(' ')
-				}
-			}
-
-			g.WriteByte(')')
-		}
-
-		if i != dLen {
-			g.WriteByte(',')
-			g.WriteByte(' ')
-		}
-	}
+func (g *Generator) writeDirectives(directives []string) {
+	g.WriteString(strings.Join(directives, ", "))
 	g.WriteByte('\n')
 }
 
-func (g *Generator) generateObject(ts *ast.TypeSpec) {
-	obj := ts.Type.(*ast.TypeSpec_Object).Object
+// generateFields only generates a list of fields. It assumes any "Fields" section/list header
+// has been generated.
+//
+func (g *Generator) generateFields(fields []*Field) {
+	for _, f := range fields {
+		g.execute("field", f, func() { g.generateField(f) })
+	}
+}
 
-	if len(obj.Interfaces) > 0 {
-		g.WriteByte('\n')
-		g.Write(g.indent)
-		g.WriteString("*Interfaces*: ")
+// generateField generates a single list item of a field, along with its args.
+// Deprecated and experimental fields are marked as such, along with the
+// reason, instead of listing @deprecated, or @beta, with their other
+// directives.
+//
+func (g *Generator) generateField(f *Field) {
+	reason, deprecated := deprecation(f.Directives)
+	directives := withoutDirective(withoutDeprecated(f.Directives), f.Stability)
 
-		iLen := len(obj.Interfaces) - 1
-		for i, inter := range obj.Interfaces {
-			g.WriteString(inter.Name)
+	// Write name
+	g.WriteIndent()
+	g.WriteByte('-')
+	g.WriteByte(' ')
+	g.WriteString(f.Name)
 
-			if i != iLen {
-				g.WriteByte(',')
-				g.WriteByte(' ')
-			}
-		}
-		g.WriteByte('\n')
+	// Write type
+	if f.Type != "" {
+		g.WriteByte(' ')
+		g.WriteByte('*')
+		g.WriteByte('*')
+		g.WriteByte('(')
+		g.printType(f.Type)
+		g.WriteByte(')')
+		g.WriteByte('*')
+		g.WriteByte('*')
 	}
-
-	if obj.Fields == nil {
-		return
+	if deprecated {
+		g.WriteString(" **DEPRECATED**")
 	}
-	if len(obj.Fields.List) == 0 {
-		return
+	if f.Stability != "" {
+		g.WriteString(" **")
+		g.WriteString(badge(f.Stability))
+		g.WriteString("**")
 	}
-
-	var b bytes.Buffer
 	g.WriteByte('\n')
-	g.P("*Fields*:")
-	g.generateFields(obj.Fields.List, &b)
-}
 
-// generateFields only generates a list of fields. It assumes any "Fields" section/list header
-// has been generated.
-//
-func (g *Generator) generateFields(fields []*ast.Field, b *bytes.Buffer) {
-	for _, f := range fields {
-		b.Reset()
+	g.In()
 
-		// Write name
-		g.Write(g.indent)
-		g.WriteByte('-')
-		g.WriteByte(' ')
-		g.WriteString(f.Name.Name)
-
-		// Write type
-		if f.Type != nil {
-			g.WriteByte(' ')
-			g.WriteByte('*')
-			g.WriteByte('*')
-			g.WriteByte('(')
-			var typ interface{}
-			switch v := f.Type.(type) {
-			case *ast.Field_Ident:
-				typ = v.Ident
-			case *ast.Field_List:
-				typ = v.List
-			case *ast.Field_NonNull:
-				typ = v.NonNull
-			}
-			g.printType(typ)
-			g.WriteByte(')')
-			g.WriteByte('*')
-			g.WriteByte('*')
-		}
+	if reason != "" {
 		g.WriteByte('\n')
+		g.WriteIndent()
+		g.WriteString("*Deprecated*: ")
+		g.WriteString(reason)
+		g.WriteByte('\n')
+	}
 
-		g.In()
-
-		dirs := filterDirectives(f.Directives)
-		if len(dirs) > 0 {
-			g.WriteByte('\n')
-			g.Write(g.indent)
-			g.WriteString("*Directives*: ")
-			g.writeDirectives(dirs)
-		}
-
-		// Write descr
-		f.Doc.TextTo(b)
-		if b.Len() > 0 {
-			g.WriteByte('\n')
-			g.Write(g.indent)
-			b.WriteTo(g)
-		}
-
-		// Write args
-		if f.Args != nil {
-			g.WriteByte('\n')
-			g.P("*Args*:")
-			g.generateArgs(f.Args.List, b)
-		}
-
-		g.Out()
+	if reason, _ := directiveReason(f.Directives, f.Stability); reason != "" {
+		g.WriteByte('\n')
+		g.WriteIndent()
+		g.WriteString("*")
+		g.WriteString(stabilityLabel(f.Stability))
+		g.WriteString("*: ")
+		g.WriteString(reason)
+		g.WriteByte('\n')
 	}
-}
 
-func (g *Generator) generateArgs(args []*ast.InputValue, b *bytes.Buffer) {
-	for _, f := range args {
-		b.Reset()
+	if len(directives) > 0 {
+		g.WriteByte('\n')
+		g.WriteIndent()
+		g.WriteString("*Directives*: ")
+		g.writeDirectives(directives)
+	}
 
-		// Write name
-		g.Write(g.indent)
-		g.WriteByte('-')
-		g.WriteByte(' ')
-		g.WriteString(f.Name.Name)
-
-		// Write type
-		if f.Type != nil {
-			g.WriteByte(' ')
-			g.WriteByte('*')
-			g.WriteByte('*')
-			g.WriteByte('(')
-			var typ interface{}
-			switch v := f.Type.(type) {
-			case *ast.InputValue_Ident:
-				typ = v.Ident
-			case *ast.InputValue_List:
-				typ = v.List
-			case *ast.InputValue_NonNull:
-				typ = v.NonNull
-			}
-			g.printType(typ)
-			g.WriteByte(')')
-			g.WriteByte('*')
-			g.WriteByte('*')
-		}
+	// Write descr
+	if f.Description != "" {
 		g.WriteByte('\n')
+		g.writeIndented(g.links.link(f.Description, g.self))
+	}
 
-		g.In()
+	// Write default value
+	if f.Default != "" {
+		g.WriteByte('\n')
+		g.WriteIndent()
+		g.WriteString("*Default Value*: `")
+		g.WriteString(f.Default)
+		g.WriteByte('`')
+		g.WriteByte('\n')
+	}
 
-		dirs := filterDirectives(f.Directives)
-		if len(dirs) > 0 {
-			g.WriteByte('\n')
-			g.Write(g.indent)
-			g.WriteString("*Directives*: ")
-			g.writeDirectives(dirs)
+	// Write example
+	if f.Example != "" {
+		g.WriteByte('\n')
+		g.P("*Example*:")
+		g.WriteByte('\n')
+		g.P("```graphql")
+		for _, line := range strings.SplitAfter(f.Example, "\n") {
+			if line != "" {
+				g.WriteIndent()
+				g.WriteString(line)
+			}
 		}
+		g.P("```")
+	}
 
-		// Write descr
-		f.Doc.TextTo(b)
-		if b.Len() > 0 {
-			g.WriteByte('\n')
-			g.Write(g.indent)
-			b.WriteTo(g)
-		}
+	// Write args
+	if len(f.Args) > 0 {
+		g.WriteByte('\n')
+		g.P("*Args*:")
+		g.generateFields(f.Args)
+	}
 
-		// Write default value
-		if f.Default != nil {
-			g.WriteByte('\n')
-			g.Write(g.indent)
-			g.WriteString("*Default Value*")
-			g.WriteByte(':')
-			g.WriteByte(' ')
+	g.Out()
+}
 
-			g.WriteByte('`')
-			var dv interface{}
-			switch v := f.Default.(type) {
-			case *ast.InputValue_BasicLit:
-				dv = v.BasicLit
-			case *ast.InputValue_CompositeLit:
-				dv = v.CompositeLit
+// generateValueTable generates a table of enum values. Directives, other
+// than @deprecated which has its own column, are listed after the description.
+//
+func (g *Generator) generateValueTable(values []*Field) {
+	g.WriteString("| Value | Description | Deprecated |\n")
+	g.WriteString("|-------|-------------|------------|\n")
+
+	for _, v := range values {
+		reason, deprecated := deprecation(v.Directives)
+		dirs := withoutDirective(withoutDeprecated(v.Directives), v.Stability)
+
+		g.WriteString("| ")
+		g.WriteString(v.Name)
+		g.writeCellBadge(v.Stability)
+		g.WriteString(" | ")
+		g.writeCell(g.links.link(v.Description, g.self), dirs)
+		g.WriteString(" | ")
+		if deprecated {
+			if reason == "" {
+				reason = "Yes"
 			}
-			g.printVal(dv)
-			g.WriteByte('`')
-			g.WriteByte('\n')
+			g.writeCell(reason, nil)
 		}
-
-		g.Out()
+		g.WriteString(" |\n")
 	}
 }
 
-func (g *Generator) printType(typ interface{}) {
-	switch v := typ.(type) {
-	case *ast.Ident:
-		switch v.Name {
-		case "Int", "Float", "String", "Boolean", "ID":
-			g.WriteString(v.Name)
-		default:
-			g.WriteByte('[')
-			g.WriteString(v.Name)
-			g.WriteByte(']')
+// generateInputTable generates a table of input fields.
+func (g *Generator) generateInputTable(fields []*Field) {
+	g.WriteString("| Field | Type | Default | Description |\n")
+	g.WriteString("|-------|------|---------|-------------|\n")
 
-			g.WriteByte('(')
-			g.WriteByte('#')
-			g.WriteString(v.Name)
-			g.WriteByte(')')
-		}
-	case *ast.List:
-		g.WriteByte('[')
-
-		var nt interface{}
-		switch w := v.Type.(type) {
-		case *ast.List_Ident:
-			nt = w.Ident
-		case *ast.List_List:
-			nt = w.List
-		case *ast.List_NonNull:
-			nt = w.NonNull
-		}
-		g.printType(nt)
-
-		g.WriteByte(']')
-	case *ast.NonNull:
-		var nt interface{}
-		switch w := v.Type.(type) {
-		case *ast.NonNull_Ident:
-			nt = w.Ident
-		case *ast.NonNull_List:
-			nt = w.List
+	for _, f := range fields {
+		g.WriteString("| ")
+		g.WriteString(f.Name)
+		g.writeCellBadge(f.Stability)
+		g.WriteString(" | ")
+		g.printType(f.Type)
+		g.WriteString(" | ")
+		if f.Default != "" {
+			g.WriteByte('`')
+			g.WriteString(escapeCell(f.Default))
+			g.WriteByte('`')
 		}
-		g.printType(nt)
-
-		g.WriteByte('!')
+		g.WriteString(" | ")
+		g.writeCell(g.links.link(f.Description, g.self), withoutDirective(f.Directives, f.Stability))
+		g.WriteString(" |\n")
 	}
 }
 
-// printVal prints a value
-func (g *Generator) printVal(val interface{}) {
-	switch v := val.(type) {
-	case *ast.BasicLit:
-		g.WriteString(v.Value)
-	case *ast.ListLit:
-		g.printList(v)
-	case *ast.ObjLit:
-		g.printObject(v)
-	case *ast.CompositeLit:
-		switch w := v.Value.(type) {
-		case *ast.CompositeLit_BasicLit:
-			g.printVal(w.BasicLit)
-		case *ast.CompositeLit_ListLit:
-			g.printVal(w.ListLit)
-		case *ast.CompositeLit_ObjLit:
-			g.printVal(w.ObjLit)
+// writeIndented writes every line of text at the current indentation, so a
+// description's lists and code blocks stay within the field's list item.
+//
+func (g *Generator) writeIndented(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		if line != "" {
+			g.WriteIndent()
+			g.WriteString(line)
 		}
+		g.WriteByte('\n')
 	}
 }
 
-func (g *Generator) printList(v *ast.ListLit) {
-	g.WriteByte('[')
-
-	var vals []interface{}
-	switch w := v.List.(type) {
-	case *ast.ListLit_BasicList:
-		for _, bval := range w.BasicList.Values {
-			vals = append(vals, bval)
-		}
-	case *ast.ListLit_CompositeList:
-		for _, cval := range w.CompositeList.Values {
-			vals = append(vals, cval)
-		}
+// writeCell writes text, followed by any directives, as a single table cell.
+func (g *Generator) writeCell(text string, directives []string) {
+	g.WriteString(escapeCell(text))
+	if len(directives) == 0 {
+		return
 	}
 
-	vLen := len(vals) - 1
-	for i, iv := range vals {
-		g.printVal(iv)
-		if i != vLen {
-			g.WriteByte(',')
-			g.WriteByte(' ')
-		}
+	if text != "" {
+		g.WriteString("<br>")
 	}
-
-	g.WriteByte(']')
+	g.WriteString("*Directives*: ")
+	g.WriteString(escapeCell(strings.Join(directives, ", ")))
 }
 
-func (g *Generator) printObject(v *ast.ObjLit) {
-	g.WriteByte('{')
-	g.WriteByte(' ')
-
-	pLen := len(v.Fields) - 1
-	for i, p := range v.Fields {
-		g.WriteString(p.Key.Name)
-		g.WriteString(": ")
+// escapeCell keeps text from breaking out of a table cell.
+func escapeCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
 
-		g.printVal(p.Val)
+// deprecation returns the reason given to @deprecated, if it's in directives.
+func deprecation(directives []string) (reason string, ok bool) {
+	for _, d := range directives {
+		if !isDeprecated(d) {
+			continue
+		}
 
-		if i != pLen {
-			g.WriteByte(',')
+		args := strings.TrimSuffix(strings.TrimPrefix(d, "@deprecated"), ")")
+		if i := strings.Index(args, "reason:"); i >= 0 {
+			reason = strings.Trim(strings.TrimSpace(args[i+len("reason:"):]), `"`)
 		}
-		g.WriteByte(' ')
+		return reason, true
 	}
-
-	g.WriteByte('}')
+	return "", false
 }
 
-// P prints the arguments to the generated output.
-func (g *Generator) P(str ...interface{}) {
-	g.Write(g.indent)
-	for _, s := range str {
-		switch v := s.(type) {
-		case string:
-			g.WriteString(v)
-		case bool:
-			fmt.Fprint(g, v)
-		case int:
-			fmt.Fprint(g, v)
-		case float64:
-			fmt.Fprint(g, v)
+// withoutDeprecated returns directives, without @deprecated.
+func withoutDeprecated(directives []string) (dirs []string) {
+	for _, d := range directives {
+		if !isDeprecated(d) {
+			dirs = append(dirs, d)
 		}
 	}
-	g.WriteByte('\n')
+	return
 }
 
-// In increases the indent.
-func (g *Generator) In() {
-	g.indent = append(g.indent, '\t')
+func isDeprecated(directive string) bool {
+	return directive == "@deprecated" || strings.HasPrefix(directive, "@deprecated(")
 }
 
-// Out decreases the indent.
-func (g *Generator) Out() {
-	if len(g.indent) > 0 {
-		g.indent = g.indent[:len(g.indent)-1]
+// printType prints a type, linking to the documentation of its named type
+// unless it's a built-in scalar.
+//
+func (g *Generator) printType(typ string) {
+	i := len(typ) - len(strings.TrimLeft(typ, "["))
+	name := strings.TrimRight(typ[i:], "]!")
+
+	switch name {
+	case "Int", "Float", "String", "Boolean", "ID":
+		g.WriteString(typ)
+		return
 	}
+
+	prefix, suffix := typ[:i], typ[i+len(name):]
+	if g.links != nil && g.links.wiki {
+		// The brackets of lists would run into the wiki link's
+		prefix = strings.Repeat("&#91;", i)
+		suffix = strings.Replace(suffix, "]", "&#93;", -1)
+	}
+
+	g.WriteString(prefix)
+	g.WriteString(g.links.ref(name, name))
+	g.WriteString(suffix)
 }
 
 // getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
 // Precedence: CLI over Doc over Default
 //
 func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
-	toc := make([]string, 0, len(doc.Types)+9)
 	gOpts = &Options{
-		Title: `Documentation`,
-		toc:   &toc,
+		Title:   `Documentation`,
+		Pages:   pagesKind,
+		RawHTML: rawOmit,
 	}
 
 	// Extract document directive options
@@ -849,6 +1218,95 @@ func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options,
 				if v == "true" {
 					gOpts.HTML = true
 				}
+			case "asciidoc":
+				v := arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+				if v == "true" {
+					gOpts.AsciiDoc = true
+				}
+			case "json":
+				v := arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+				if v == "true" {
+					gOpts.JSON = true
+				}
+			case "tables":
+				v := arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+				if v == "true" {
+					gOpts.Tables = true
+				}
+			case "operations":
+				gOpts.Operations = stringList(arg.Val)
+			case "crossLinks":
+				v := arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+				if v == "true" {
+					gOpts.CrossLinks = true
+				}
+			case "templates":
+				gOpts.Templates = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			case "theme":
+				gOpts.Theme = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			case "css":
+				gOpts.CSS = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			case "a11y":
+				gOpts.A11y = stringList(arg.Val)
+			case "rawHTML":
+				gOpts.RawHTML = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			case "sections":
+				gOpts.Sections = stringList(arg.Val)
+			case "tocKinds":
+				gOpts.TocKinds = stringList(arg.Val)
+			case "tocCollapse":
+				gOpts.TocCollapse, err = strconv.Atoi(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return
+				}
+			case "alphaIndex":
+				v := arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+				if v == "true" {
+					gOpts.AlphaIndex = true
+				}
+			case "examples":
+				v := arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+				if v == "true" {
+					gOpts.Examples = true
+				}
+			case "deprecations":
+				v := arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+				if v == "true" {
+					gOpts.Deprecations = true
+				}
+			case "experimental":
+				gOpts.Experimental = stringList(arg.Val)
+			case "directiveUsage":
+				v := arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+				if v == "true" {
+					gOpts.DirectiveUsage = true
+				}
+			case "multiPage":
+				v := arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+				if v == "true" {
+					gOpts.MultiPage = true
+				}
+			case "pages":
+				gOpts.Pages = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			case "wiki":
+				v := arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+				if v == "true" {
+					gOpts.Wiki = true
+				}
+			case "frontmatter":
+				v := arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+				if v == "true" {
+					gOpts.Frontmatter = true
+				}
+			case "slug":
+				gOpts.Slug = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			case "weight":
+				gOpts.Weight, err = strconv.Atoi(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return
+				}
+			case "frontmatterKeys":
+				gOpts.FrontmatterKeys = stringList(arg.Val)
 			}
 		}
 	}
@@ -860,7 +1318,7 @@ func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options,
 
 	// Unmarshal cli options
 	if opts == nil {
-		return
+		return gOpts, checkPages(gOpts.Pages)
 	}
 	if t, ok := opts["title"]; ok {
 		gOpts.Title, _ = t.(string)
@@ -868,5 +1326,156 @@ func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options,
 	if h, ok := opts["html"]; ok {
 		gOpts.HTML, _ = h.(bool)
 	}
+	if a, ok := opts["asciidoc"]; ok {
+		gOpts.AsciiDoc, _ = a.(bool)
+	}
+	if j, ok := opts["json"]; ok {
+		gOpts.JSON, _ = j.(bool)
+	}
+	if t, ok := opts["tables"]; ok {
+		gOpts.Tables, _ = t.(bool)
+	}
+	if c, ok := opts["crossLinks"]; ok {
+		gOpts.CrossLinks, _ = c.(bool)
+	}
+	if t, ok := opts["templates"]; ok {
+		v, _ := t.(string)
+		gOpts.Templates = strings.Trim(v, `"`)
+	}
+	if t, ok := opts["theme"]; ok {
+		v, _ := t.(string)
+		gOpts.Theme = strings.Trim(v, `"`)
+	}
+	if c, ok := opts["css"]; ok {
+		v, _ := c.(string)
+		gOpts.CSS = strings.Trim(v, `"`)
+	}
+	if a, ok := opts["a11y"]; ok {
+		switch v := a.(type) {
+		case string:
+			gOpts.A11y = strings.Split(strings.Trim(v, `"`), ",")
+		case []string:
+			gOpts.A11y = nil
+			for _, rule := range v {
+				gOpts.A11y = append(gOpts.A11y, strings.Split(strings.Trim(rule, `"`), ",")...)
+			}
+		}
+	}
+	if r, ok := opts["rawHTML"]; ok {
+		v, _ := r.(string)
+		gOpts.RawHTML = strings.Trim(v, `"`)
+	}
+	if s, ok := opts["sections"]; ok {
+		switch v := s.(type) {
+		case string:
+			// A single value may list them all e.g. "objects,enums"
+			gOpts.Sections = strings.Split(strings.Trim(v, `"`), ",")
+		case []string:
+			gOpts.Sections = nil
+			for _, name := range v {
+				gOpts.Sections = append(gOpts.Sections, strings.Split(strings.Trim(name, `"`), ",")...)
+			}
+		}
+	}
+	if t, ok := opts["tocKinds"]; ok {
+		switch v := t.(type) {
+		case string:
+			gOpts.TocKinds = []string{strings.Trim(v, `"`)}
+		case []string:
+			gOpts.TocKinds = nil
+			for _, kind := range v {
+				gOpts.TocKinds = append(gOpts.TocKinds, strings.Trim(kind, `"`))
+			}
+		}
+	}
+	if t, ok := opts["tocCollapse"]; ok {
+		n, _ := t.(int64)
+		gOpts.TocCollapse = int(n)
+	}
+	if a, ok := opts["alphaIndex"]; ok {
+		gOpts.AlphaIndex, _ = a.(bool)
+	}
+	if e, ok := opts["examples"]; ok {
+		gOpts.Examples, _ = e.(bool)
+	}
+	if d, ok := opts["deprecations"]; ok {
+		gOpts.Deprecations, _ = d.(bool)
+	}
+	if e, ok := opts["experimental"]; ok {
+		switch v := e.(type) {
+		case string:
+			gOpts.Experimental = []string{strings.Trim(v, `"`)}
+		case []string:
+			gOpts.Experimental = []string{}
+			for _, name := range v {
+				gOpts.Experimental = append(gOpts.Experimental, strings.Trim(name, `"`))
+			}
+		}
+	}
+	if d, ok := opts["directiveUsage"]; ok {
+		gOpts.DirectiveUsage, _ = d.(bool)
+	}
+	if m, ok := opts["multiPage"]; ok {
+		gOpts.MultiPage, _ = m.(bool)
+	}
+	if p, ok := opts["pages"]; ok {
+		v, _ := p.(string)
+		gOpts.Pages = strings.Trim(v, `"`)
+	}
+	if w, ok := opts["wiki"]; ok {
+		gOpts.Wiki, _ = w.(bool)
+	}
+	if f, ok := opts["frontmatter"]; ok {
+		gOpts.Frontmatter, _ = f.(bool)
+	}
+	if s, ok := opts["slug"]; ok {
+		v, _ := s.(string)
+		gOpts.Slug = strings.Trim(v, `"`)
+	}
+	if w, ok := opts["weight"]; ok {
+		n, _ := w.(int64)
+		gOpts.Weight = int(n)
+	}
+	if k, ok := opts["frontmatterKeys"]; ok {
+		switch v := k.(type) {
+		case string:
+			gOpts.FrontmatterKeys = []string{strings.Trim(v, `"`)}
+		case []string:
+			gOpts.FrontmatterKeys = nil
+			for _, kv := range v {
+				gOpts.FrontmatterKeys = append(gOpts.FrontmatterKeys, strings.Trim(kv, `"`))
+			}
+		}
+	}
+	if o, ok := opts["operations"]; ok {
+		switch v := o.(type) {
+		case string:
+			gOpts.Operations = []string{strings.Trim(v, `"`)}
+		case []string:
+			for _, name := range v {
+				gOpts.Operations = append(gOpts.Operations, strings.Trim(name, `"`))
+			}
+		}
+	}
+	return gOpts, checkPages(gOpts.Pages)
+}
+
+// stringList returns the unquoted strings of a String or [String] value.
+func stringList(val *ast.CompositeLit) (strs []string) {
+	switch v := val.Value.(type) {
+	case *ast.CompositeLit_BasicLit:
+		strs = append(strs, strings.Trim(v.BasicLit.Value, `"`))
+	case *ast.CompositeLit_ListLit:
+		switch list := v.ListLit.List.(type) {
+		case *ast.ListLit_BasicList:
+			for _, lit := range list.BasicList.Values {
+				strs = append(strs, strings.Trim(lit.Value, `"`))
+			}
+		case *ast.ListLit_CompositeList:
+			for _, lit := range list.CompositeList.Values {
+				strs = append(strs, stringList(lit)...)
+			}
+		}
+	}
 	return
 }
@@ -3,12 +3,16 @@ package doc
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -119,101 +123,74 @@ func TestUpdate(t *testing.T) {
 	})
 }
 
-func TestAddContent(t *testing.T) {
-	mask := schemaType | scalarType | objectType | interType | unionType | enumType | inputType | directiveType | extendType
+func TestBuildModel(t *testing.T) {
+	decl := func(name string, typ interface{}) *ast.TypeDecl {
+		ts := &ast.TypeSpec{Name: &ast.Ident{Name: name}}
+		switch v := typ.(type) {
+		case *ast.TypeSpec_Scalar:
+			ts.Type = v
+		case *ast.TypeSpec_Object:
+			ts.Type = v
+		}
+		return &ast.TypeDecl{Spec: &ast.TypeDecl_TypeSpec{TypeSpec: ts}}
+	}
+	scalarSpec := &ast.TypeSpec_Scalar{Scalar: &ast.ScalarType{}}
+	objectSpec := &ast.TypeSpec_Object{Object: &ast.ObjectType{}}
 
 	testCases := []struct {
-		Name string
-		C    []struct {
-			name string
-			typ  declType
-		}
-		Total int
+		Name     string
+		Types    []*ast.TypeDecl
+		Sections []string
+		Total    int
 	}{
 		{
-			Name: "SingleType",
-			C: []struct {
-				name string
-				typ  declType
-			}{
-				{
-					name: "Test",
-					typ:  scalarType,
-				},
-			},
-			Total: 2,
+			Name:     "SingleType",
+			Types:    []*ast.TypeDecl{decl("Test", scalarSpec)},
+			Sections: []string{scalar},
+			Total:    1,
 		},
 		{
 			Name: "MultiSameType",
-			C: []struct {
-				name string
-				typ  declType
-			}{
-				{
-					name: "A",
-					typ:  scalarType,
-				},
-				{
-					name: "B",
-					typ:  scalarType,
-				},
-				{
-					name: "C",
-					typ:  scalarType,
-				},
+			Types: []*ast.TypeDecl{
+				decl("A", scalarSpec),
+				decl("B", scalarSpec),
+				decl("C", scalarSpec),
 			},
-			Total: 4,
+			Sections: []string{scalar},
+			Total:    3,
 		},
 		{
 			Name: "ManyTypes",
-			C: []struct {
-				name string
-				typ  declType
-			}{
-				{
-					name: "A",
-					typ:  scalarType,
-				},
-				{
-					name: "B",
-					typ:  scalarType,
-				},
-				{
-					name: "C",
-					typ:  scalarType,
-				},
-				{
-					name: "A",
-					typ:  objectType,
-				},
-				{
-					name: "B",
-					typ:  objectType,
-				},
-				{
-					name: "C",
-					typ:  objectType,
-				},
+			Types: []*ast.TypeDecl{
+				decl("A", scalarSpec),
+				decl("B", scalarSpec),
+				decl("C", scalarSpec),
+				decl("A", objectSpec),
+				decl("B", objectSpec),
+				decl("C", objectSpec),
+				{Spec: &ast.TypeDecl_TypeExtSpec{TypeExtSpec: &ast.TypeExtensionSpec{}}},
 			},
-			Total: 8,
+			Sections: []string{scalar, object},
+			Total:    6,
 		},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.Name, func(subT *testing.T) {
-			toc := make([]string, 0, len(testCase.C))
-			opts := &Options{
-				toc: &toc,
+			m := BuildModel(&ast.Document{Types: testCase.Types}, &Options{Title: "Test"})
+			if m.Title != "Test" {
+				subT.Errorf("expected title Test, but got: %s", m.Title)
 			}
 
-			tmask := mask
-			for _, c := range testCase.C {
-				tmask = opts.addContent(c.name, c.typ, tmask)
+			var sections []string
+			total := 0
+			for _, s := range m.Sections {
+				sections = append(sections, s.Kind)
+				total += len(s.Types)
 			}
 
-			if len(*opts.toc) != testCase.Total {
-				fmt.Println(*opts.toc)
-				subT.Fail()
+			if strings.Join(sections, ",") != strings.Join(testCase.Sections, ",") || total != testCase.Total {
+				subT.Errorf("expected sections %v with %d types, but got: %v with %d", testCase.Sections, testCase.Total, sections, total)
 			}
 		})
 	}
@@ -221,13 +198,15 @@ func TestAddContent(t *testing.T) {
 
 func TestToC(t *testing.T) {
 	testCases := []struct {
-		Name string
-		ToC  []string
-		Ex   []byte
+		Name     string
+		Sections []*Section
+		Ex       []byte
 	}{
 		{
 			Name: "SingleSection",
-			ToC:  []string{scalar, "Int", "Float", "String"},
+			Sections: []*Section{
+				{Kind: scalar, Types: []*Type{{Name: "Int"}, {Name: "Float"}, {Name: "String"}}},
+			},
 			Ex: []byte(`# Test
 *This was generated by gqlc.*
 
@@ -241,18 +220,10 @@ func TestToC(t *testing.T) {
 		},
 		{
 			Name: "MultipleSections",
-			ToC: []string{
-				scalar,
-				"Int",
-				"Float",
-				"String",
-				object,
-				"Person",
-				"Hero",
-				"Jedi",
-				inter,
-				"Node",
-				"Connection",
+			Sections: []*Section{
+				{Kind: scalar, Types: []*Type{{Name: "Int"}, {Name: "Float"}, {Name: "String"}}},
+				{Kind: object, Types: []*Type{{Name: "Person"}, {Name: "Hero"}, {Name: "Jedi"}}},
+				{Kind: inter, Types: []*Type{{Name: "Node"}, {Name: "Connection"}}},
 			},
 			Ex: []byte(`# Test
 *This was generated by gqlc.*
@@ -276,13 +247,13 @@ func TestToC(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.Name, func(subT *testing.T) {
-			opts := &Options{
-				Title: "Test",
-				toc:   &testCase.ToC,
+			m := &Model{
+				Title:    "Test",
+				Sections: testCase.Sections,
 			}
 
 			var b bytes.Buffer
-			writeToC(&b, opts)
+			writeToC(&b, m, nil, nil)
 			gen.CompareBytes(subT, testCase.Ex, b.Bytes())
 		})
 	}
@@ -420,14 +391,11 @@ func TestFields(t *testing.T) {
 		},
 	}
 
-	var testBuf bytes.Buffer
 	for _, testCase := range testCases {
 		t.Run(testCase.Name, func(subT *testing.T) {
-			g.Lock()
-			defer g.Unlock()
 			g.Reset()
 
-			g.generateFields(testCase.Fields, &testBuf)
+			g.generateFields(buildFields(testCase.Fields))
 
 			gen.CompareBytes(subT, testCase.Ex, g.Bytes())
 		})
@@ -482,20 +450,147 @@ func TestArgs(t *testing.T) {
 		},
 	}
 
-	var testBuf bytes.Buffer
 	for _, testCase := range testCases {
 		t.Run(testCase.Name, func(subT *testing.T) {
-			g.Lock()
-			defer g.Unlock()
 			g.Reset()
 
-			g.generateArgs(testCase.Args, &testBuf)
+			g.generateFields(buildArgs(testCase.Args))
 
 			gen.CompareBytes(subT, testCase.Ex, g.Bytes())
 		})
 	}
 }
 
+func TestTables(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "tables", strings.NewReader(`@doc(options: {tables: true})
+
+enum Direction {
+	"Up | north."
+	NORTH @a
+	SOUTH @deprecated(reason: "Use NORTH.")
+	EAST @deprecated
+}
+
+input Point {
+	"Horizontal
+position."
+	x: Float! = 0.0
+	y: Float
+	"Tags of the point."
+	tags: [Tag] @a
+}`), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	opts, err := getOptions(doc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !opts.Tables {
+		t.Error("expected tables option to be read from the document")
+		return
+	}
+
+	g := new(Generator)
+	g.Reset()
+	g.tables = opts.Tables
+	g.generateModel(BuildModel(doc, opts))
+
+	ex := "## Enums\n\n" +
+		"### Direction\n\n" +
+		"*Values*:\n\n" +
+		"| Value | Description | Deprecated |\n" +
+		"|-------|-------------|------------|\n" +
+		"| NORTH | Up \\| north.<br>*Directives*: @a |  |\n" +
+		"| SOUTH |  | Use NORTH. |\n" +
+		"| EAST |  | Yes |\n" +
+		"\n" +
+		"## Inputs\n\n" +
+		"### Point\n\n" +
+		"*Fields*:\n\n" +
+		"| Field | Type | Default | Description |\n" +
+		"|-------|------|---------|-------------|\n" +
+		"| x | Float! | `0.0` | Horizontal<br>position. |\n" +
+		"| y | Float |  |  |\n" +
+		"| tags | [[Tag](#Tag)] |  | Tags of the point.<br>*Directives*: @a |\n"
+
+	gen.CompareBytes(t, []byte(ex), g.Bytes())
+}
+
+func TestCrossLinks(t *testing.T) {
+	m := &Model{Sections: []*Section{
+		{Kind: object, Types: []*Type{{Name: "User"}, {Name: "Query"}}},
+		{Kind: directive, Types: []*Type{{Name: "key"}}},
+	}}
+
+	testCases := []struct {
+		Name string
+		Auto bool
+		In   string
+		Ex   string
+	}{
+		{Name: "Explicit", In: "See [[User]] or [[Unknown]].", Ex: "See [User](#User) or [[Unknown]]."},
+		{Name: "NotAuto", In: "A User.", Ex: "A User."},
+		{Name: "Auto", Auto: true, In: "A User, or Users.", Ex: "A [User](#User), or Users."},
+		{Name: "Self", Auto: true, In: "Query a User.", Ex: "Query a [User](#User)."},
+		{Name: "Directives", Auto: true, In: "The key.", Ex: "The key."},
+		{Name: "Code", Auto: true, In: "Use `User` or ``a `User` b``.", Ex: "Use `User` or ``a `User` b``."},
+		{Name: "Fenced", Auto: true, In: "A User:\n```\nUser\n```\nUser", Ex: "A [User](#User):\n```\nUser\n```\n[User](#User)"},
+		{Name: "Links", Auto: true, In: "[a User](https://example.com/User) <https://x.io/User> https://x.io/User", Ex: "[a User](https://example.com/User) <https://x.io/User> https://x.io/User"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			out := newLinker(m, testCase.Auto).link(testCase.In, "Query")
+			if out != testCase.Ex {
+				subT.Errorf("expected: %q\nbut got: %q", testCase.Ex, out)
+			}
+		})
+	}
+
+	t.Run("Generate", func(subT *testing.T) {
+		doc, err := parser.ParseDoc(token.NewDocSet(), "links", strings.NewReader(`@doc(options: {crossLinks: true})
+
+"A Query for a User."
+type Query {
+	"The current User."
+	me: User
+}
+
+type User {
+	name: String
+}`), 0)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		opts, err := getOptions(doc, nil)
+		if err != nil {
+			subT.Fatal(err)
+		}
+		if !opts.CrossLinks {
+			subT.Fatal("expected crossLinks option to be read from the document")
+		}
+
+		g := new(Generator)
+		g.Reset()
+		g.crossLinks = opts.CrossLinks
+		g.generateModel(BuildModel(doc, opts))
+
+		for _, s := range []string{
+			"### Query\nA Query for a [User](#User).\n",
+			"\tThe current [User](#User).\n",
+		} {
+			if !strings.Contains(g.String(), s) {
+				subT.Errorf("expected %q in:\n%s", s, g.String())
+			}
+		}
+	})
+}
+
 type noopCloser struct {
 	io.Writer
 }
@@ -503,12 +598,15 @@ type noopCloser struct {
 func (*noopCloser) Close() error { return nil }
 
 type testCtx struct {
-	md, html io.Writer
+	md, html, adoc io.Writer
 }
 
 func (ctx *testCtx) Open(name string) (io.WriteCloser, error) {
-	if filepath.Ext(name) == ".md" {
+	switch filepath.Ext(name) {
+	case ".md":
 		return &noopCloser{ctx.md}, nil
+	case ".adoc":
+		return &noopCloser{ctx.adoc}, nil
 	}
 	return &noopCloser{ctx.html}, nil
 }
@@ -553,55 +651,1802 @@ func TestGenerator_Generate(t *testing.T) {
 
 		gen.CompareBytes(subT, ex, b.Bytes())
 	})
+
+	t.Run("WithTables", func(subT *testing.T) {
+		var md, html bytes.Buffer
+		g := new(Generator)
+		ctx := gen.WithContext(context.Background(), &testCtx{md: &md, html: &html})
+		err := g.Generate(ctx, testDoc, map[string]interface{}{"html": true, "tables": true})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if !strings.Contains(md.String(), "| Value | Description | Deprecated |") {
+			subT.Errorf("expected enum values to be a table, but got:\n%s", md.String())
+		}
+		if !strings.Contains(html.String(), "<table>") {
+			subT.Errorf("expected HTML to contain a table, but got:\n%s", html.String())
+		}
+	})
+
+	t.Run("WithAsciiDoc", func(subT *testing.T) {
+		var adoc bytes.Buffer
+		g := new(Generator)
+		ctx := gen.WithContext(context.Background(), &testCtx{md: new(bytes.Buffer), adoc: &adoc})
+		err := g.Generate(ctx, testDoc, map[string]interface{}{"asciidoc": true, "tables": true})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		for _, s := range []string{
+			"= Test Documentation\n:toc:\n",
+			"\n[#Objects]\n== Objects\n",
+			"|Value |Description |Deprecated\n",
+		} {
+			if !strings.Contains(adoc.String(), s) {
+				subT.Errorf("expected %q in:\n%s", s, adoc.String())
+			}
+		}
+	})
 }
 
-func BenchmarkGenerator_Generate(b *testing.B) {
-	var buf bytes.Buffer
-	g := new(Generator)
-	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+func TestTemplates(t *testing.T) {
+	gqlSrc := `"A user."
+type User {
+	"The user's name."
+	name: String!
+	friends(first: Int = 10): [User!]
+}`
 
-	for i := 0; i < b.N; i++ {
-		buf.Reset()
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
 
-		err := g.Generate(ctx, testDoc, nil)
+	generate := func(files map[string]string) (string, error) {
+		pCtx := gen.TestPathCtx{
+			Sources: map[string]string{"api": "/src/api.gql"},
+			Files:   make(map[string][]byte),
+		}
+		for name, src := range files {
+			pCtx.Files["/src/tmpl/"+name] = []byte(src)
+		}
+
+		var b bytes.Buffer
+		pCtx.TestCtx = gen.TestCtx{Writer: &b}
+		err := new(Generator).Generate(gen.WithContext(context.Background(), pCtx), doc, map[string]interface{}{"templates": "tmpl"})
+		return b.String(), err
+	}
+
+	t.Run("Defaults", func(subT *testing.T) {
+		var ex bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), gen.TestCtx{Writer: &ex}), doc, nil)
 		if err != nil {
-			b.Error(err)
+			subT.Error(err)
+			return
+		}
+
+		out, err := generate(map[string]string{"type.tmpl": "{{type .}}"})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+		gen.CompareBytes(subT, ex.Bytes(), []byte(out))
+	})
+
+	t.Run("Overrides", func(subT *testing.T) {
+		out, err := generate(map[string]string{
+			"toc.tmpl":   "# {{.Title}}\n\n",
+			"type.tmpl":  "### {{.Name}} ({{.Kind}})\n\n{{link .Description}}\n\n{{range .Fields}}{{template \"field\" .}}{{end}}",
+			"field.tmpl": "- `{{.Name}}`: {{typeRef .Type}}{{with .Description}} - {{.}}{{end}}\n",
+		})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ex := "# Documentation\n" +
+			"\n" +
+			"## Objects\n" +
+			"\n" +
+			"### User (object)\n" +
+			"\n" +
+			"A user.\n" +
+			"\n" +
+			"- `name`: String! - The user's name.\n" +
+			"- `friends`: [[User](#User)!]\n"
+		if out != ex {
+			subT.Errorf("expected:\n%s\nbut got:\n%s", ex, out)
+		}
+	})
+
+	t.Run("NestedFields", func(subT *testing.T) {
+		out, err := generate(map[string]string{"field.tmpl": "{{if .Args}}{{field .}}{{else}}- {{.Name}}\n{{end}}"})
+		if err != nil {
+			subT.Error(err)
 			return
 		}
+
+		ex := "*Fields*:\n" +
+			"- name\n" +
+			"- friends **([[User](#User)!])**\n" +
+			"\n" +
+			"\t*Args*:\n" +
+			"- first\n"
+		if !strings.HasSuffix(out, ex) {
+			subT.Errorf("expected args to be rendered by the field template, but got:\n%s", out)
+		}
+	})
+
+	t.Run("NoTemplates", func(subT *testing.T) {
+		_, err := generate(nil)
+		if err == nil || !strings.Contains(err.Error(), "no templates found in /src/tmpl") {
+			subT.Errorf("expected error for missing templates, but got: %v", err)
+		}
+	})
+
+	t.Run("ExecError", func(subT *testing.T) {
+		_, err := generate(map[string]string{"field.tmpl": "{{.Missing}}"})
+		if err == nil {
+			subT.Error("expected error executing template")
+		}
+	})
+}
+
+func TestOperations(t *testing.T) {
+	schemaSrc := `type Query {
+	user(id: ID!): User
+	node(id: ID!): Node
+}
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	name: String
+	friends: [User!]!
+}`
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api", strings.NewReader(schemaSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	opsSrc := `# GetUser fetches a user
+# and their friends.
+query GetUser($id: ID!, $first: Int = 10) {
+	user(id: $id) {
+		...UserFields
+		buddies: friends { name }
+	}
+	node(id: $id) {
+		__typename
+		... on User { name }
 	}
 }
 
-func ExampleGenerator_Generate() {
-	g := new(Generator)
+query { user(id: "1") { id } }
 
-	gqlSrc := `schema {
+fragment UserFields on User {
+	id
+	name
+}`
+
+	newCtx := func(b io.Writer) context.Context {
+		return gen.WithContext(context.Background(), gen.TestPathCtx{
+			TestCtx: gen.TestCtx{Writer: b},
+			DirPath: "/out",
+			Sources: map[string]string{"api": "/src/api.gql"},
+			Files:   map[string][]byte{"/src/ops/user.graphql": []byte(opsSrc)},
+		})
+	}
+
+	t.Run("Generate", func(subT *testing.T) {
+		var b bytes.Buffer
+		g := new(Generator)
+		err := g.Generate(newCtx(&b), doc, map[string]interface{}{"operations": `"ops/user.graphql"`})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		toc := "- [Operations](#Operations)\n" +
+			"\t* [GetUser](#GetUser)\n"
+		if !strings.Contains(b.String(), toc) {
+			subT.Errorf("expected table of contents to link operations, but got:\n%s", b.String())
+		}
+
+		ex := "## Operations\n\n" +
+			"### GetUser\n" +
+			"*Query*\n" +
+			"\n" +
+			"GetUser fetches a user\n" +
+			"and their friends.\n" +
+			"\n" +
+			"*Variables*:\n" +
+			"- $id **(ID!)**\n" +
+			"- $first **(Int)**\n" +
+			"\n" +
+			"\t*Default Value*: `10`\n" +
+			"\n" +
+			"*Selections*:\n" +
+			"- user **([User](#User))**\n" +
+			"\t- id **(ID!)**\n" +
+			"\t- name **(String)**\n" +
+			"\t- buddies: friends **([[User](#User)!]!)**\n" +
+			"\t\t- name **(String)**\n" +
+			"- node **([Node](#Node))**\n" +
+			"\t- __typename **(String!)**\n" +
+			"\t- ... on [User](#User)\n" +
+			"\t\t- name **(String)**\n"
+
+		out := b.String()
+		i := strings.Index(out, "## Operations")
+		if i < 0 {
+			subT.Errorf("expected an operations section, but got:\n%s", out)
+			return
+		}
+		gen.CompareBytes(subT, []byte(ex), []byte(out[i:]))
+	})
+
+	t.Run("Context", func(subT *testing.T) {
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestOperationCtx{
+			TestCtx: gen.TestCtx{Writer: &b},
+			Docs:    []gen.OperationDocument{{Name: "ops/user.graphql", Source: opsSrc}},
+		})
+
+		err := new(Generator).Generate(ctx, doc, nil)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if !strings.Contains(b.String(), "## Operations\n\n### GetUser\n") {
+			subT.Errorf("expected the operations of the context to be documented, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("MissingFile", func(subT *testing.T) {
+		g := new(Generator)
+		err := g.Generate(newCtx(new(bytes.Buffer)), doc, map[string]interface{}{"operations": `"missing.graphql"`})
+		if err == nil {
+			subT.Error("expected error for missing operations file")
+		}
+	})
+
+	t.Run("Errors", func(subT *testing.T) {
+		testCases := []struct {
+			Name string
+			Src  string
+			Err  string
+		}{
+			{
+				Name: "Syntax",
+				Src:  "query A {\n\tuser(id: 1) {\n}",
+				Err:  "ops.graphql:3:2: unterminated selection set",
+			},
+			{
+				Name: "UnknownFragment",
+				Src:  "query A { user(id: 1) { ...B } }",
+				Err:  "operation A: unknown fragment: B",
+			},
+			{
+				Name: "CyclicFragment",
+				Src:  "query A { user(id: 1) { ...B } }\nfragment B on User { friends { ...B } }",
+				Err:  "operation A: fragment B spreads itself",
+			},
+		}
+
+		for _, testCase := range testCases {
+			subT.Run(testCase.Name, func(triT *testing.T) {
+				_, err := ParseOperations(doc, OperationSource{Name: "ops.graphql", Src: testCase.Src})
+				if err == nil || err.Error() != testCase.Err {
+					triT.Errorf("expected error %q, but got: %v", testCase.Err, err)
+				}
+			})
+		}
+	})
+}
+
+// pagesCtx keeps every file opened in it, by name.
+type pagesCtx map[string]*bytes.Buffer
+
+func (ctx pagesCtx) Open(name string) (io.WriteCloser, error) {
+	b := new(bytes.Buffer)
+	ctx[name] = b
+	return &noopCloser{b}, nil
+}
+
+func TestMultiPage(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api.gql", strings.NewReader(`@doc(options: {multiPage: true})
+
+schema {
 	query: Query
 }
 
-"Query represents the queries this example provides."
 type Query {
-	hello: String
-}`
+	user: User
+	role: Role
+}
 
-	doc, err := parser.ParseDoc(token.NewDocSet(), "example", strings.NewReader(gqlSrc), 0)
+"A User, with a Role."
+type User {
+	role: Role
+}
+
+enum Role {
+	ADMIN
+	USER
+}`), 0)
 	if err != nil {
-		return // Handle error
+		t.Fatal(err)
 	}
 
-	var b bytes.Buffer
-	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
-	err = g.Generate(ctx, doc, map[string]interface{}{"title": "Example Documentation"})
+	t.Run("Kind", func(subT *testing.T) {
+		ctx := make(pagesCtx)
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, map[string]interface{}{"crossLinks": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		for name, ex := range map[string][]string{
+			"api/index.md": {
+				"- [Schema](schema.md#Schema)\n",
+				"- [Objects](objects.md#Objects)\n\t* [Query](objects.md#Query)\n\t* [User](objects.md#User)\n",
+				"- [Enums](enums.md#Enums)\n\t* [Role](enums.md#Role)\n",
+			},
+			"api/schema.md":  {"## Schema\n", "- query **([Query](objects.md#Query))**\n"},
+			"api/objects.md": {"- user **([User](#User))**\n", "A User, with a [Role](enums.md#Role).\n"},
+			"api/enums.md":   {"## Enums\n\n### Role\n"},
+		} {
+			b, ok := ctx[name]
+			if !ok {
+				subT.Errorf("expected %s to be written", name)
+				continue
+			}
+
+			for _, s := range ex {
+				if !strings.Contains(b.String(), s) {
+					subT.Errorf("expected %q in %s:\n%s", s, name, b.String())
+				}
+			}
+		}
+		if len(ctx) != 4 {
+			subT.Errorf("expected 4 pages, but got: %d", len(ctx))
+		}
+	})
+
+	t.Run("Type", func(subT *testing.T) {
+		ctx := make(pagesCtx)
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, map[string]interface{}{"pages": "type", "html": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		index := ctx["api/index.md"].String()
+		if !strings.Contains(index, "- Objects\n\t* [Query](Query.md#Query)\n\t* [User](User.md#User)\n") {
+			subT.Errorf("expected objects to link to their own pages, but got:\n%s", index)
+		}
+
+		user := ctx["api/User.md"].String()
+		if !strings.HasPrefix(user, "### User\n") || !strings.Contains(user, "- role **([Role](Role.md#Role))**\n") {
+			subT.Errorf("unexpected User page:\n%s", user)
+		}
+
+		html := ctx["api/User.html"].String()
+		if !strings.Contains(html, `<a href="Role.html#Role">Role</a>`) {
+			subT.Errorf("expected HTML pages to link to each other, but got:\n%s", html)
+		}
+	})
+
+	t.Run("UnknownPages", func(subT *testing.T) {
+		err := new(Generator).Generate(gen.WithContext(context.Background(), make(pagesCtx)), doc, map[string]interface{}{"pages": "section"})
+		if err == nil || !strings.Contains(err.Error(), "unknown pages option: section") {
+			subT.Errorf("expected unknown pages error, but got: %v", err)
+		}
+	})
+}
+
+func TestWiki(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api.gql", strings.NewReader(`@doc(options: {wiki: true, alphaIndex: true})
+
+schema {
+	query: Query
+}
+
+type Query {
+	users: [User!]!
+}
+
+"A User, with a [[Role]]."
+type User {
+	role: Role
+}
+
+enum Role {
+	ADMIN
+	USER
+}`), 0)
 	if err != nil {
-		return // Handle err
+		t.Fatal(err)
 	}
 
-	fmt.Println(b.String())
+	ctx := make(pagesCtx)
+	err = new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, map[string]interface{}{"html": true, "frontmatter": true})
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	// Output:
-	// # Example Documentation
-	// *This was generated by gqlc.*
-	//
-	// ## Table of Contents
+	for name, ex := range map[string][]string{
+		"api/Home.md": {
+			"# Documentation\n",
+			"- [Schema](schema#Schema)\n",
+			"- Objects\n\t* [[Query]]\n\t* [[User]]\n",
+			"- [[Alphabetical Index|alphabetical]]\n",
+		},
+		"api/_Sidebar.md": {
+			"**[[Documentation|Home]]**\n",
+			"### [[Schema|schema]]\n",
+			"### Objects\n\n- [[Query]]\n- [[User]]\n",
+			"### Enums\n\n- [[Role]]\n",
+		},
+		"api/Query.md":        {"- users **(&#91;[[User]]!&#93;!)**\n"},
+		"api/User.md":         {"### User\nA User, with a [[Role]].\n", "- role **([[Role]])**\n"},
+		"api/schema.md":       {"- query **([[Query]])**\n"},
+		"api/Role.md":         {"### Role\n"},
+		"api/alphabetical.md": {"- [[User]] *object*\n"},
+	} {
+		b, ok := ctx[name]
+		if !ok {
+			t.Errorf("expected %s to be written", name)
+			continue
+		}
+
+		for _, s := range ex {
+			if !strings.Contains(b.String(), s) {
+				t.Errorf("expected %q in %s:\n%s", s, name, b.String())
+			}
+		}
+	}
+
+	// Wikis are Markdown only
+	if len(ctx) != 7 || strings.HasPrefix(ctx["api/Home.md"].String(), "---") {
+		t.Errorf("expected only the 7 Markdown pages, without frontmatter, but got: %d", len(ctx))
+	}
+}
+
+func TestHTMLThemes(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api", strings.NewReader(`@doc(options: {title: "A & B"})
+
+"A user."
+type User {
+	id: ID!
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newCtx := func(b io.Writer) context.Context {
+		return gen.WithContext(context.Background(), gen.TestPathCtx{
+			TestCtx: gen.TestCtx{Writer: b},
+			DirPath: "/out",
+			Sources: map[string]string{"api": "/src/api.gql"},
+			Files:   map[string][]byte{"/src/style.css": []byte("body { margin: 0; }")},
+		})
+	}
+
+	t.Run("Theme", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: new(bytes.Buffer), html: &b}), doc, map[string]interface{}{"html": true, "theme": "dark"})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		out := b.String()
+		for _, s := range []string{
+			"<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n",
+			"<title>A &amp; B</title>\n<style>\n",
+			darkCSS,
+			"</style>\n</head>\n<body>\n<a class=\"gqlc-skip\" href=\"#content\">Skip to content</a>\n<div class=\"gqlc-doc\">\n<header>\n<h1 id=\"A-B\">A &amp; B</h1>\n",
+			"</header>\n<nav aria-labelledby=\"Table-of-Contents\">\n<h2 id=\"Table-of-Contents\">Table of Contents</h2>\n<ul>\n",
+			"</ul>\n</nav>\n<main id=\"content\" tabindex=\"-1\">\n<h2 id=\"Objects\">Objects</h2>\n",
+			"</main>\n</div>\n</body>\n</html>\n",
+		} {
+			if !strings.Contains(out, s) {
+				subT.Errorf("expected %q in:\n%s", s, out)
+			}
+		}
+	})
+
+	t.Run("CSS", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := new(Generator).Generate(newCtx(&b), doc, map[string]interface{}{"html": true, "theme": "github", "css": "style.css"})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		ex := githubCSS + "body { margin: 0; }\n</style>\n"
+		if !strings.Contains(b.String(), ex) {
+			subT.Errorf("expected the css to be applied after the theme, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("Fragment", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: new(bytes.Buffer), html: &b}), doc, map[string]interface{}{"html": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if !strings.HasPrefix(b.String(), "<h2 id=\"Objects\">Objects</h2>\n") {
+			subT.Errorf("expected an HTML fragment without a theme, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("Highlight", func(subT *testing.T) {
+		md := "A user, e.g.\n\n```graphql\n# A user\ntype User @key(fields: \"id\") { id: ID! }\nquery { user(id: $id, n: -1.5) { id } }\n```\n"
+
+		var b bytes.Buffer
+		err := writeHTML(&b, []byte(md), false, rawOmit, nil)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		ex := `<pre><code class="language-graphql"><span class="gql-comment"># A user</span>
+<span class="gql-keyword">type</span> User <span class="gql-directive">@key</span>(fields: <span class="gql-string">&#34;id&#34;</span>) { id: ID! }
+<span class="gql-keyword">query</span> { user(id: <span class="gql-variable">$id</span>, n: <span class="gql-number">-1.5</span>) { id } }
+</code></pre>`
+		if !strings.Contains(b.String(), ex) {
+			subT.Errorf("expected %q in:\n%s", ex, b.String())
+		}
+	})
+
+	t.Run("UnknownTheme", func(subT *testing.T) {
+		err := new(Generator).Generate(newCtx(new(bytes.Buffer)), doc, map[string]interface{}{"html": true, "theme": "solarized"})
+		if err == nil || !strings.Contains(err.Error(), "unknown theme: solarized, expected one of: dark, github, light") {
+			subT.Errorf("expected unknown theme error, but got: %v", err)
+		}
+	})
+}
+
+func TestDeprecations(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api", strings.NewReader(`@doc(options: {deprecations: true})
+
+type User {
+	name: String @deprecated(reason: "Use fullName.") @key
+	fullName: String
+	friends(first: Int @deprecated(reason: "Use last."), last: Int): [User]
+}
+
+enum Role {
+	ADMIN
+	ROOT @deprecated
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Markdown", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: &b}), doc, nil)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		for _, s := range []string{
+			"- [Deprecations](#Deprecations)\n\n",
+			"- name **(String)** **DEPRECATED**\n\n\t*Deprecated*: Use fullName.\n\n\t*Directives*: @key\n",
+			"\t- first **(Int)** **DEPRECATED**\n\n\t\t*Deprecated*: Use last.\n",
+			"- ROOT **DEPRECATED**\n",
+			"## Deprecations\n\n" +
+				"- [User.name](#User): Use fullName.\n" +
+				"- [User.friends(first)](#User): Use last.\n" +
+				"- [Role.ROOT](#Role)\n",
+		} {
+			if !strings.Contains(b.String(), s) {
+				subT.Errorf("expected %q in:\n%s", s, b.String())
+			}
+		}
+		if strings.Contains(b.String(), "@deprecated") {
+			subT.Errorf("expected @deprecated to be replaced by badges, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("NoSummary", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: &b}), doc, map[string]interface{}{"deprecations": false})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if strings.Contains(b.String(), "Deprecations") || !strings.Contains(b.String(), "**DEPRECATED**") {
+			subT.Errorf("expected badges without a summary, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("AsciiDoc", func(subT *testing.T) {
+		var adoc bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: new(bytes.Buffer), adoc: &adoc}), doc, map[string]interface{}{"asciidoc": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		for _, s := range []string{
+			"* `name` *(String)* [.deprecated]#*DEPRECATED*#\n+\n*Deprecated*: Use fullName.\n",
+			"\n[#Deprecations]\n== Deprecations\n\n* <<User,User.name>>: Use fullName.\n",
+		} {
+			if !strings.Contains(adoc.String(), s) {
+				subT.Errorf("expected %q in:\n%s", s, adoc.String())
+			}
+		}
+	})
+
+	t.Run("MultiPage", func(subT *testing.T) {
+		ctx := make(pagesCtx)
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, map[string]interface{}{"multiPage": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if s := ctx["api/index.md"].String(); !strings.Contains(s, "- [Deprecations](deprecations.md#Deprecations)\n") {
+			subT.Errorf("expected the index to link to the deprecations, but got:\n%s", s)
+		}
+		if s := ctx["api/deprecations.md"].String(); !strings.Contains(s, "- [User.name](objects.md#User): Use fullName.\n") {
+			subT.Errorf("expected deprecations to link to their types, but got:\n%s", s)
+		}
+	})
+}
+
+func TestExperimental(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api", strings.NewReader(`type User {
+	name: String
+	avatar(size: Int @preview): String @beta(reason: "The size may change.") @key
+}
+
+type Team @experimental {
+	name: String
+}
+
+enum Role {
+	ADMIN
+	OWNER @experimental
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Markdown", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: &b}), doc, nil)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		for _, s := range []string{
+			"- [Experimental API](#Experimental-API)\n\n",
+			"- avatar **(String)** **BETA**\n\n\t*Beta*: The size may change.\n\n\t*Directives*: @key\n",
+			"### Team\n**EXPERIMENTAL**\n\n*Fields*:\n",
+			"- OWNER **EXPERIMENTAL**\n",
+			"\t- size **(Int)**\n",
+			"## Experimental API\n\n" +
+				"- [User.avatar](#User) **BETA**: The size may change.\n" +
+				"- [Team](#Team) **EXPERIMENTAL**\n" +
+				"- [Role.OWNER](#Role) **EXPERIMENTAL**\n",
+		} {
+			if !strings.Contains(b.String(), s) {
+				subT.Errorf("expected %q in:\n%s", s, b.String())
+			}
+		}
+		if strings.Contains(b.String(), "@beta") || strings.Contains(b.String(), "@experimental") {
+			subT.Errorf("expected the directives to be replaced by badges, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("Names", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: &b}), doc, map[string]interface{}{"experimental": []string{"preview"}})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if s := "\t- size **(Int)** **PREVIEW**\n"; !strings.Contains(b.String(), s) {
+			subT.Errorf("expected %q in:\n%s", s, b.String())
+		}
+		if s := "## Experimental API\n\n- [User.avatar(size)](#User) **PREVIEW**\n"; !strings.Contains(b.String(), s) {
+			subT.Errorf("expected %q in:\n%s", s, b.String())
+		}
+		if strings.Contains(b.String(), "**BETA**") || !strings.Contains(b.String(), "@beta") {
+			subT.Errorf("expected only @preview to be badged, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("Hidden", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: &b}), doc, map[string]interface{}{"sections": "objects,enums"})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if strings.Contains(b.String(), "Experimental API") || !strings.Contains(b.String(), "**BETA**") {
+			subT.Errorf("expected badges without a summary, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("AsciiDoc", func(subT *testing.T) {
+		var adoc bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: new(bytes.Buffer), adoc: &adoc}), doc, map[string]interface{}{"asciidoc": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		for _, s := range []string{
+			"* `avatar` *(String)* [.beta]#*BETA*#\n+\n*Beta*: The size may change.\n",
+			"\n[#Experimental-API]\n== Experimental API\n\n* <<User,User.avatar>> [.beta]#*BETA*#: The size may change.\n",
+		} {
+			if !strings.Contains(adoc.String(), s) {
+				subT.Errorf("expected %q in:\n%s", s, adoc.String())
+			}
+		}
+	})
+
+	t.Run("MultiPage", func(subT *testing.T) {
+		ctx := make(pagesCtx)
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, map[string]interface{}{"multiPage": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if s := ctx["api/index.md"].String(); !strings.Contains(s, "- [Experimental API](experimental.md#Experimental-API)\n") {
+			subT.Errorf("expected the index to link to the experimental API, but got:\n%s", s)
+		}
+		if s := ctx["api/experimental.md"].String(); !strings.Contains(s, "- [Team](objects.md#Team) **EXPERIMENTAL**\n") {
+			subT.Errorf("expected the experimental API to link to its types, but got:\n%s", s)
+		}
+	})
+}
+
+func TestToCOptions(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api.gql", strings.NewReader(`@doc(options: {tocKinds: ["object", "enum"], tocCollapse: 2, alphaIndex: true})
+
+type User { role: Role }
+type Query { user: User }
+type account { id: ID }
+enum Role {
+	ADMIN
+}
+
+directive @auth on FIELD_DEFINITION`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("SinglePage", func(subT *testing.T) {
+		ctx := make(pagesCtx)
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, map[string]interface{}{"html": true, "theme": "light"})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		ex := "## Table of Contents\n" +
+			"- [Objects](#Objects)\n" +
+			"- [Enums](#Enums)\n" +
+			"\t* [Role](#Role)\n" +
+			"- [Alphabetical Index](api-alphabetical.md)\n\n"
+		if s := ctx["api.md"].String(); !strings.Contains(s, ex) {
+			subT.Errorf("expected %q in:\n%s", ex, s)
+		}
+		if s := ctx["api.html"].String(); !strings.Contains(s, `<a href="api-alphabetical.html">Alphabetical Index</a>`) {
+			subT.Errorf("expected the HTML to link to the HTML index, but got:\n%s", s)
+		}
+
+		ex = "# Alphabetical Index\n" +
+			"\n## A\n\n" +
+			"- [account](api.md#account) *object*\n" +
+			"- [@auth](api.md#auth) *directive*\n" +
+			"\n## Q\n\n" +
+			"- [Query](api.md#Query) *object*\n" +
+			"\n## R\n\n" +
+			"- [Role](api.md#Role) *enum*\n" +
+			"\n## U\n\n" +
+			"- [User](api.md#User) *object*\n"
+		if s := ctx["api-alphabetical.md"].String(); s != ex {
+			subT.Errorf("expected:\n%s\nbut got:\n%s", ex, s)
+		}
+		if s := ctx["api-alphabetical.html"].String(); !strings.Contains(s, `<a href="api.html#User">User</a>`) {
+			subT.Errorf("expected the HTML index to link to the HTML documentation, but got:\n%s", s)
+		}
+	})
+
+	t.Run("MultiPage", func(subT *testing.T) {
+		ctx := make(pagesCtx)
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, map[string]interface{}{"multiPage": true, "tocCollapse": int64(0)})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		ex := "- [Objects](objects.md#Objects)\n" +
+			"\t* [User](objects.md#User)\n" +
+			"\t* [Query](objects.md#Query)\n" +
+			"\t* [account](objects.md#account)\n" +
+			"- [Enums](enums.md#Enums)\n" +
+			"\t* [Role](enums.md#Role)\n" +
+			"- [Alphabetical Index](alphabetical.md)\n"
+		if s := ctx["api/index.md"].String(); !strings.Contains(s, ex) {
+			subT.Errorf("expected %q in:\n%s", ex, s)
+		}
+		if s := ctx["api/alphabetical.md"].String(); !strings.Contains(s, "- [@auth](directives.md#auth) *directive*\n") {
+			subT.Errorf("expected the index to link across pages, but got:\n%s", s)
+		}
+	})
+
+	t.Run("SectionNames", func(subT *testing.T) {
+		ctx := make(pagesCtx)
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, map[string]interface{}{"tocKinds": []string{"Objects", "enums"}})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		ex := "## Table of Contents\n" +
+			"- [Objects](#Objects)\n" +
+			"- [Enums](#Enums)\n" +
+			"\t* [Role](#Role)\n"
+		if s := ctx["api.md"].String(); !strings.Contains(s, ex) {
+			subT.Errorf("expected %q in:\n%s", ex, s)
+		}
+	})
+
+	t.Run("UnknownKind", func(subT *testing.T) {
+		err := new(Generator).Generate(gen.WithContext(context.Background(), make(pagesCtx)), doc, map[string]interface{}{"tocKinds": "widgets"})
+		if err == nil || !strings.Contains(err.Error(), "unknown tocKinds kind: widgets") {
+			subT.Errorf("expected unknown kind error, but got: %v", err)
+		}
+	})
+}
+
+func TestExamples(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api", strings.NewReader(`type Query {
+	user(id: ID!): User
+	search(term: String!): [Result]
+}
+
+type User {
+	id: ID!
+	name: String
+	friends: [User]
+	posts(first: Int!): [Post]
+}
+
+type Post {
+	title: String
+}
+
+union Result = User | Post`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Markdown", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: &b}), doc, map[string]interface{}{"examples": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		for _, s := range []string{
+			"- user **([User](#User))**\n\n\t*Example*:\n\n\t```graphql\n" +
+				"\tquery User($id: ID!) {\n" +
+				"\t  user(id: $id) {\n" +
+				"\t    id\n" +
+				"\t    name\n" +
+				"\t    friends {\n" +
+				"\t      id\n" +
+				"\t      name\n" +
+				"\t      friends {\n" +
+				"\t        id\n" +
+				"\t        name\n" +
+				"\t      }\n" +
+				"\t    }\n" +
+				"\t  }\n" +
+				"\t}\n" +
+				"\t```\n",
+			"\tquery Search($term: String!) {\n" +
+				"\t  search(term: $term) {\n" +
+				"\t    __typename\n" +
+				"\t    ... on User {\n",
+			"\t    ... on Post {\n\t      title\n\t    }\n",
+		} {
+			if !strings.Contains(b.String(), s) {
+				subT.Errorf("expected %q in:\n%s", s, b.String())
+			}
+		}
+		if strings.Contains(b.String(), "posts {") || strings.Count(b.String(), "*Example*") != 2 {
+			subT.Errorf("expected examples of only the root fields, without posts, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("Disabled", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: &b}), doc, nil)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if strings.Contains(b.String(), "*Example*") {
+			subT.Errorf("expected no examples, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("AsciiDoc", func(subT *testing.T) {
+		var adoc bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: new(bytes.Buffer), adoc: &adoc}), doc, map[string]interface{}{"examples": true, "asciidoc": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		s := "+\n*Example*:\n+\n[source,graphql]\n----\nquery User($id: ID!) {\n  user(id: $id) {\n"
+		if !strings.Contains(adoc.String(), s) {
+			subT.Errorf("expected %q in:\n%s", s, adoc.String())
+		}
+	})
+}
+
+func TestDirectiveUsage(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api", strings.NewReader(`@doc(options: {directiveUsage: true})
+
+directive @auth(role: String) on OBJECT | FIELD_DEFINITION | ARGUMENT_DEFINITION
+
+directive @unused on FIELD_DEFINITION
+
+type User @auth(role: "admin") {
+	name: String @auth
+	friends(first: Int @auth): [User] @deprecated
+}
+
+type Post {
+	title: String @auth(role: "editor")
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Markdown", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: &b}), doc, nil)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		s := "*Used by*:\n" +
+			"- [User](#User)\n" +
+			"- [User.name](#User)\n" +
+			"- [User.friends(first)](#User)\n" +
+			"- [Post.title](#Post)\n"
+		if !strings.Contains(b.String(), s) {
+			subT.Errorf("expected %q in:\n%s", s, b.String())
+		}
+		if strings.Count(b.String(), "*Used by*") != 1 {
+			subT.Errorf("expected only @auth to be used, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("Disabled", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: &b}), doc, map[string]interface{}{"directiveUsage": false})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if strings.Contains(b.String(), "*Used by*") {
+			subT.Errorf("expected no usages, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("AsciiDoc", func(subT *testing.T) {
+		var adoc bytes.Buffer
+		err := new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: new(bytes.Buffer), adoc: &adoc}), doc, map[string]interface{}{"asciidoc": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		s := "*Used by*:\n\n* <<User,User>>\n* <<User,User.name>>\n* <<User,User.friends(first)>>\n* <<Post,Post.title>>\n"
+		if !strings.Contains(adoc.String(), s) {
+			subT.Errorf("expected %q in:\n%s", s, adoc.String())
+		}
+	})
+}
+
+func TestSchemaDirectives(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api", strings.NewReader(`schema @link(url: "https://specs.apollo.dev/federation/v2.0") @public {
+	query: Query
+}
+
+type Query {
+	a: String
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var md, adoc bytes.Buffer
+	err = new(Generator).Generate(gen.WithContext(context.Background(), &testCtx{md: &md, adoc: &adoc}), doc, map[string]interface{}{"asciidoc": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := "*This was generated by gqlc.*\n\n`@link(url: \"https://specs.apollo.dev/federation/v2.0\")` `@public`\n\n## Table of Contents\n"
+	if !strings.Contains(md.String(), s) {
+		t.Errorf("expected %q in:\n%s", s, md.String())
+	}
+
+	s = "_This was generated by gqlc._\n\n`+@link(url: \"https://specs.apollo.dev/federation/v2.0\")+` `+@public+`\n"
+	if !strings.Contains(adoc.String(), s) {
+		t.Errorf("expected %q in:\n%s", s, adoc.String())
+	}
+}
+
+func TestJSON(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api.gql", strings.NewReader(`type Query {
+	"The user with the given id."
+	user(id: ID!): User
+}
+
+"A User."
+type User {
+	name: String @deprecated(reason: "Use fullName.")
+	fullName: String
+}
+
+enum Role {
+	ADMIN
+	USER
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		Title string `json:"title"`
+		ToC   []struct {
+			Name  string `json:"name"`
+			Href  string `json:"href"`
+			Types []struct {
+				Name string `json:"name"`
+				Href string `json:"href"`
+			} `json:"types"`
+		} `json:"toc"`
+		Sections []struct {
+			Kind  string `json:"kind"`
+			Types []struct {
+				Name        string `json:"name"`
+				Description string `json:"description"`
+				Fields      []struct {
+					Name        string `json:"name"`
+					Type        string `json:"type"`
+					Description string `json:"description"`
+					Args        []struct {
+						Name string `json:"name"`
+						Type string `json:"type"`
+					} `json:"args"`
+				} `json:"fields"`
+			} `json:"types"`
+		} `json:"sections"`
+		Deprecations []*Deprecation `json:"deprecations"`
+	}
+
+	t.Run("File", func(subT *testing.T) {
+		ctx := pagesCtx{}
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, map[string]interface{}{"json": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		b, ok := ctx["api.json"]
+		if !ok {
+			subT.Fatalf("expected api.json to be written, but got: %v", ctx)
+		}
+
+		var r result
+		err = json.Unmarshal(b.Bytes(), &r)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if r.Title != "Documentation" || len(r.ToC) != 2 || r.ToC[0].Name != "Objects" || r.ToC[0].Types[1].Href != "#User" {
+			subT.Errorf("unexpected table of contents: %s", b.String())
+		}
+		if len(r.Sections) != 2 || r.Sections[0].Kind != "object" || r.Sections[1].Kind != "enum" {
+			subT.Fatalf("unexpected sections: %s", b.String())
+		}
+
+		user := r.Sections[0].Types[0].Fields[0]
+		if user.Name != "user" || user.Type != "User" || user.Description != "The user with the given id." || len(user.Args) != 1 || user.Args[0].Type != "ID!" {
+			subT.Errorf("unexpected user field: %+v", user)
+		}
+		if r.Sections[0].Types[1].Description != "A User." {
+			subT.Errorf("expected the User's description, but got: %+v", r.Sections[0].Types[1])
+		}
+
+		// Deprecations are always listed
+		if len(r.Deprecations) != 1 || *r.Deprecations[0] != (Deprecation{Type: "User", Name: "name", Reason: "Use fullName."}) {
+			subT.Errorf("unexpected deprecations: %s", b.String())
+		}
+	})
+
+	t.Run("MultiPage", func(subT *testing.T) {
+		ctx := pagesCtx{}
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, map[string]interface{}{"json": true, "multiPage": true, "html": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		b, ok := ctx["api/index.json"]
+		if !ok {
+			subT.Fatalf("expected api/index.json to be written, but got: %v", ctx)
+		}
+
+		var r result
+		err = json.Unmarshal(b.Bytes(), &r)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if r.ToC[0].Href != "objects.md#Objects" || r.ToC[0].Types[1].Href != "objects.md#User" {
+			subT.Errorf("expected links to the Markdown pages, but got: %s", b.String())
+		}
+	})
+
+	t.Run("Disabled", func(subT *testing.T) {
+		ctx := pagesCtx{}
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, nil)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if _, ok := ctx["api.json"]; ok {
+			subT.Error("expected no json to be written")
+		}
+	})
+}
+
+func TestFrontmatter(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api.gql", strings.NewReader(`@doc(options: {title: "API", frontmatter: true, weight: 10})
+
+type Query {
+	user: User
+}
+
+type User {
+	name: String
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("File", func(subT *testing.T) {
+		ctx := pagesCtx{}
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, map[string]interface{}{
+			"slug":            `"/api"`,
+			"frontmatterKeys": []string{`"layout=docs"`, `"sidebar=true"`, `"order=1.5"`, `"description=Types: all"`},
+			"html":            true,
+		})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		ex := `---
+title: "API"
+slug: "/api"
+weight: 10
+layout: "docs"
+sidebar: true
+order: 1.5
+description: "Types: all"
+---
+
+# API
+`
+		if md := ctx["api.md"].String(); !strings.HasPrefix(md, ex) {
+			subT.Errorf("expected frontmatter:\n%s\nbut got:\n%s", ex, md)
+		}
+		if html := ctx["api.html"].String(); strings.Contains(html, "layout") {
+			subT.Errorf("expected the HTML to not have frontmatter, but got:\n%s", html)
+		}
+	})
+
+	t.Run("MultiPage", func(subT *testing.T) {
+		ctx := pagesCtx{}
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, map[string]interface{}{
+			"multiPage":       true,
+			"pages":           `"type"`,
+			"slug":            `"/api/"`,
+			"frontmatterKeys": `"title=Reference"`,
+		})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		testCases := map[string]string{
+			"api/index.md": "---\nslug: \"/api/\"\nweight: 10\ntitle: \"Reference\"\n---\n\n",
+			"api/Query.md": "---\nslug: \"/api/Query\"\nweight: 11\ntitle: \"Reference\"\n---\n\n",
+			"api/User.md":  "---\nslug: \"/api/User\"\nweight: 12\ntitle: \"Reference\"\n---\n\n",
+		}
+		for name, ex := range testCases {
+			if md := ctx[name].String(); !strings.HasPrefix(md, ex) {
+				subT.Errorf("expected %s to begin with:\n%s\nbut got:\n%s", name, ex, md)
+			}
+		}
+	})
+
+	t.Run("PageTitles", func(subT *testing.T) {
+		ctx := pagesCtx{}
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, map[string]interface{}{"multiPage": true, "alphaIndex": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		testCases := map[string]string{
+			"api/objects.md":      "---\ntitle: \"Objects\"\nweight: 11\n---\n\n## Objects",
+			"api/alphabetical.md": "---\ntitle: \"Alphabetical Index\"\nweight: 12\n---\n\n# Alphabetical Index",
+		}
+		for name, ex := range testCases {
+			if md := ctx[name].String(); !strings.HasPrefix(md, ex) {
+				subT.Errorf("expected %s to begin with:\n%s\nbut got:\n%s", name, ex, md)
+			}
+		}
+	})
+
+	t.Run("InvalidKey", func(subT *testing.T) {
+		err := new(Generator).Generate(gen.WithContext(context.Background(), pagesCtx{}), doc, map[string]interface{}{"frontmatterKeys": `"layout"`})
+		if err == nil || !strings.Contains(err.Error(), "frontmatterKeys must be formatted as key=value, but got: layout") {
+			subT.Errorf("expected an error for the key without a value, but got: %v", err)
+		}
+	})
+}
+
+func TestSections(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api.gql", strings.NewReader(`directive @auth on FIELD_DEFINITION
+
+type Query {
+	role: Role
+	name: String @deprecated
+}
+
+enum Role {
+	ADMIN
+}
+
+scalar Time`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name     string
+		Opts     map[string]interface{}
+		Sections []string
+		Err      string
+	}{
+		{
+			Name:     "Default",
+			Sections: []string{"Directives", "Objects", "Enums", "Scalars"},
+		},
+		{
+			Name:     "Ordered",
+			Opts:     map[string]interface{}{"sections": `"enums,objects"`},
+			Sections: []string{"Enums", "Objects"},
+		},
+		{
+			Name:     "Kinds",
+			Opts:     map[string]interface{}{"sections": []string{"scalar", `"Objects"`, "deprecations"}, "deprecations": true},
+			Sections: []string{"Scalars", "Objects", "Deprecations"},
+		},
+		{
+			Name:     "HiddenDeprecations",
+			Opts:     map[string]interface{}{"sections": "objects", "deprecations": true},
+			Sections: []string{"Objects"},
+		},
+		{
+			Name: "Unknown",
+			Opts: map[string]interface{}{"sections": "objects,widgets"},
+			Err:  "unknown section: widgets",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			ctx := pagesCtx{}
+			err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, testCase.Opts)
+			if testCase.Err != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.Err) {
+					subT.Errorf("expected error: %s, but got: %v", testCase.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			var sections []string
+			for _, line := range strings.Split(ctx["api.md"].String(), "\n") {
+				if strings.HasPrefix(line, "## ") && line != "## Table of Contents" {
+					sections = append(sections, line[3:])
+				}
+			}
+			if strings.Join(sections, ",") != strings.Join(testCase.Sections, ",") {
+				subT.Errorf("expected sections: %v, but got: %v", testCase.Sections, sections)
+			}
+		})
+	}
+}
+
+// docsCtx is a pagesCtx which carries the documents being generated.
+type docsCtx struct {
+	pagesCtx
+
+	docs []*ast.Document
+}
+
+func (ctx docsCtx) Documents() []*ast.Document { return ctx.docs }
+
+func TestOtherDocs(t *testing.T) {
+	users, err := parser.ParseDoc(token.NewDocSet(), "users", strings.NewReader(`@doc(options: {crossLinks: true})
+
+type Query {
+	me: User
+}
+
+"A User, with a Role."
+type User {
+	role: Role
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	types, err := parser.ParseDoc(token.NewDocSet(), "shared/types", strings.NewReader(`type Audit {
+	by: User
+}
+
+enum Role {
+	ADMIN
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name string
+		Opts map[string]interface{}
+		Ex   map[string][]string
+	}{
+		{
+			Name: "File",
+			Opts: map[string]interface{}{"html": true},
+			Ex: map[string][]string{
+				"users.md":        {"- me **([User](#User))**\n", "A User, with a [Role](shared/types.md#Role).\n", "- role **([Role](shared/types.md#Role))**\n"},
+				"users.html":      {`<a href="shared/types.html#Role">Role</a>`},
+				"shared/types.md": {"- by **([User](../users.md#User))**\n"},
+			},
+		},
+		{
+			Name: "Pages",
+			Opts: map[string]interface{}{"multiPage": true},
+			Ex: map[string][]string{
+				"users/objects.md":        {"- role **([Role](../shared/types/enums.md#Role))**\n"},
+				"shared/types/objects.md": {"- by **([User](../../users/objects.md#User))**\n"},
+			},
+		},
+		{
+			Name: "TypePages",
+			Opts: map[string]interface{}{"pages": "type"},
+			Ex: map[string][]string{
+				"users.md": {"- role **([Role](shared/types.md#Role))**\n"},
+			},
+		},
+		{
+			Name: "TypePagesMulti",
+			Opts: map[string]interface{}{"multiPage": true, "pages": "type"},
+			Ex: map[string][]string{
+				"users/User.md": {"- role **([Role](../shared/types/Role.md#Role))**\n"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			ctx := docsCtx{pagesCtx: make(pagesCtx), docs: []*ast.Document{users, types}}
+			for _, doc := range ctx.docs {
+				err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, testCase.Opts)
+				if err != nil {
+					subT.Fatal(err)
+				}
+			}
+
+			for name, ex := range testCase.Ex {
+				b, ok := ctx.pagesCtx[name]
+				if !ok {
+					subT.Errorf("expected %s to be written", name)
+					continue
+				}
+
+				for _, s := range ex {
+					if !strings.Contains(b.String(), s) {
+						subT.Errorf("expected %q in %s:\n%s", s, name, b.String())
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("Alone", func(subT *testing.T) {
+		ctx := docsCtx{pagesCtx: make(pagesCtx), docs: []*ast.Document{users}}
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), users, nil)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if md := ctx.pagesCtx["users.md"].String(); !strings.Contains(md, "- role **([Role](#Role))**\n") {
+			subT.Errorf("expected unknown types to be left as anchors, but got:\n%s", md)
+		}
+	})
+}
+
+func TestA11y(t *testing.T) {
+	t.Run("Generate", func(subT *testing.T) {
+		for name, opts := range map[string]map[string]interface{}{
+			"Fragment":  {"html": true, "a11y": "all"},
+			"Page":      {"html": true, "theme": "light", "a11y": "all", "deprecations": true},
+			"MultiPage": {"html": true, "theme": "github", "a11y": []string{"all"}, "multiPage": true, "alphaIndex": true},
+		} {
+			err := new(Generator).Generate(gen.WithContext(context.Background(), make(pagesCtx)), testDoc, opts)
+			if err != nil {
+				subT.Errorf("%s: expected the HTML to pass the audit, but got: %s", name, err)
+			}
+		}
+	})
+
+	testCases := []struct {
+		Name  string
+		Rules []string
+		Page  bool
+		HTML  string
+		Err   string
+	}{
+		{
+			Name:  "ImageAlt",
+			Rules: []string{ruleImageAlt},
+			HTML:  `<p><img src="a.png" alt=""> <img src='b.png' alt="B"></p>`,
+			Err:   `image-alt: <img src="a.png"> has no alt text`,
+		},
+		{
+			Name:  "LinkName",
+			Rules: []string{ruleLinkName},
+			HTML:  `<a href="a.html"></a><a href="b.html" aria-label="B"></a><a href="c.html"><img src="c.png" alt="C"></a>`,
+			Err:   `link-name: <a href="a.html"> has no text`,
+		},
+		{
+			Name:  "LinkTarget",
+			Rules: []string{ruleLinkTarget},
+			HTML:  `<h3 id="User">User</h3><a href="#User">User</a> <a href="#Role">Role</a> <a href="other.html#Role">Role</a>`,
+			Err:   `link-target: <a href="#Role"> goes to no element`,
+		},
+		{
+			Name:  "HeadingOrder",
+			Rules: []string{ruleHeadingOrder},
+			HTML:  "<h3>A</h3><h4>B</h4><h2>C</h2><h4>D</h4>",
+			Err:   "heading-order: <h4> follows <h2>",
+		},
+		{
+			Name:  "Page",
+			Rules: []string{"all"},
+			Page:  true,
+			HTML:  "<!DOCTYPE html>\n<html>\n<head><style>a > b { }</style></head>\n<body><nav><a href=\"x.html\">X</a></nav><h1 id=\"x\">X</h1></body></html>",
+			Err:   "lang: <html> has no lang\n\ttitle: there's no <title>\n\tlandmarks: <nav> 1 has no aria-label, or aria-labelledby\n\tlandmarks: expected a single <main>, but found 0\n\tskip-link: the first link doesn't skip to the content",
+		},
+		{
+			Name:  "Disabled",
+			Rules: []string{ruleLinkName},
+			HTML:  `<img src="a.png">`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			err := auditHTML([]byte(testCase.HTML), testCase.Rules, testCase.Page)
+			if testCase.Err == "" {
+				if err != nil {
+					subT.Errorf("unexpected error: %s", err)
+				}
+				return
+			}
+
+			ex := "accessibility audit failed:\n\t" + testCase.Err
+			if err == nil || err.Error() != ex {
+				subT.Errorf("expected error:\n%s\nbut got:\n%v", ex, err)
+			}
+		})
+	}
+
+	t.Run("Fails", func(subT *testing.T) {
+		doc, err := parser.ParseDoc(token.NewDocSet(), "api", strings.NewReader(`"A user, see ![](avatar.png)."
+type User {
+	id: ID!
+}`), 0)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		err = new(Generator).Generate(gen.WithContext(context.Background(), make(pagesCtx)), doc, map[string]interface{}{"html": true, "a11y": "image-alt,link-name"})
+		if err == nil || !strings.Contains(err.Error(), "api.html: accessibility audit failed:\n\timage-alt: <img src=\"avatar.png\"> has no alt text") {
+			subT.Errorf("expected the audit to fail, but got: %v", err)
+		}
+	})
+
+	t.Run("UnknownRule", func(subT *testing.T) {
+		err := new(Generator).Generate(gen.WithContext(context.Background(), make(pagesCtx)), testDoc, map[string]interface{}{"html": true, "a11y": "contrast"})
+		if err == nil || !strings.Contains(err.Error(), "unknown accessibility rule: contrast") {
+			subT.Errorf("expected unknown rule error, but got: %v", err)
+		}
+	})
+
+	t.Run("Contrast", func(subT *testing.T) {
+		colors := regexp.MustCompile(`(?s)(body|\.gqlc-doc a|\.gqlc-doc pre|\.gql-[a-z]+) \{([^}]*)\}`)
+		prop := func(decls, name string) string {
+			m := regexp.MustCompile(`(?:^|[\s;])` + name + `: (#[0-9a-f]{6})`).FindStringSubmatch(decls)
+			if m == nil {
+				return ""
+			}
+			return m[1]
+		}
+
+		for name, css := range themes {
+			var bg, code string
+			for _, m := range colors.FindAllStringSubmatch(css, -1) {
+				sel, decls := m[1], m[2]
+				if c := prop(decls, "background"); c != "" {
+					if sel == "body" {
+						bg = c
+					} else {
+						code = c
+					}
+				}
+
+				fg := prop(decls, "color")
+				if fg == "" {
+					continue
+				}
+				on := bg
+				if strings.HasPrefix(sel, ".gql-") {
+					on = code
+				}
+				if r := contrastRatio(fg, on); r < 4.5 {
+					subT.Errorf("%s: %s %s on %s has a contrast ratio of %.2f", name, sel, fg, on, r)
+				}
+			}
+		}
+	})
+}
+
+// contrastRatio returns the WCAG contrast ratio of two #rrggbb colors.
+func contrastRatio(a, b string) float64 {
+	luminance := func(hex string) float64 {
+		var rgb [3]float64
+		for i := range rgb {
+			v, _ := strconv.ParseUint(hex[1+2*i:3+2*i], 16, 8)
+			c := float64(v) / 255
+			if c <= 0.03928 {
+				rgb[i] = c / 12.92
+			} else {
+				rgb[i] = math.Pow((c+0.055)/1.055, 2.4)
+			}
+		}
+		return 0.2126*rgb[0] + 0.7152*rgb[1] + 0.0722*rgb[2]
+	}
+
+	la, lb := luminance(a), luminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+func TestRawHTML(t *testing.T) {
+	md := "A <b>bold</b> <a href=\"javascript:x()\" onclick=\"y()\">link</a> <script>x()</script> a<br>b\n\n<div class=\"x\">\n<i>block</i>\n</div>\n"
+
+	testCases := []struct {
+		Policy string
+		HTML   string
+	}{
+		{
+			Policy: rawOmit,
+			HTML:   "<p>A <!-- raw HTML omitted -->bold<!-- raw HTML omitted --> <!-- raw HTML omitted -->link<!-- raw HTML omitted --> <!-- raw HTML omitted -->x()<!-- raw HTML omitted --> a<br>b</p>\n<!-- raw HTML omitted -->\n",
+		},
+		{
+			Policy: rawEscape,
+			HTML:   "<p>A &lt;b&gt;bold&lt;/b&gt; &lt;a href=&#34;javascript:x()&#34; onclick=&#34;y()&#34;&gt;link&lt;/a&gt; &lt;script&gt;x()&lt;/script&gt; a<br>b</p>\n&lt;div class=&#34;x&#34;&gt;\n&lt;i&gt;block&lt;/i&gt;\n&lt;/div&gt;\n",
+		},
+		{
+			Policy: rawSanitize,
+			HTML:   "<p>A <b>bold</b> <a>link</a> x() a<br>b</p>\n\n<i>block</i>\n\n",
+		},
+		{
+			Policy: rawAllow,
+			HTML:   "<p>A <b>bold</b> <a href=\"javascript:x()\" onclick=\"y()\">link</a> <script>x()</script> a<br>b</p>\n<div class=\"x\">\n<i>block</i>\n</div>\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Policy, func(subT *testing.T) {
+			var b bytes.Buffer
+			err := convertMarkdown(&b, []byte(md), false, testCase.Policy)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			gen.CompareBytes(subT, []byte(testCase.HTML), b.Bytes())
+		})
+	}
+
+	t.Run("Sanitize", func(subT *testing.T) {
+		for src, ex := range map[string]string{
+			`<a href="https://example.com" title="T" target="_blank">`: `<a href="https://example.com" title="T">`,
+			`<a href=" JavaScript:x()">`:                               `<a>`,
+			`<a href="../user.html#User">`:                             `<a href="../user.html#User">`,
+			`<img src="data:image/png;base64,AA" alt="A" onerror=x()>`: `<img alt="A">`,
+			`<style>p { display: none }</style>text`:                   "text",
+			`<!-- comment -->1 < 2`:                                    "1 &lt; 2",
+		} {
+			if s := sanitizeHTML(src); s != ex {
+				subT.Errorf("expected %q to be sanitized as %q, but got: %q", src, ex, s)
+			}
+		}
+	})
+
+	t.Run("Generate", func(subT *testing.T) {
+		doc, err := parser.ParseDoc(token.NewDocSet(), "raw.gql", strings.NewReader(`@doc(options: {html: true, rawHTML: "sanitize"})
+
+type User {
+	"The <b>roles</b> of the <i onclick='x()'>user</i>."
+	roles: [String]
+}`), 0)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ctx := make(pagesCtx)
+		err = new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, nil)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ex := "<p>The <b>roles</b> of the <i>user</i>.</p>\n"
+		if s := ctx["raw.html"].String(); !strings.Contains(s, ex) {
+			subT.Errorf("expected the description to be sanitized, but got:\n%s", s)
+		}
+	})
+
+	t.Run("Indented", func(subT *testing.T) {
+		m := &Model{Sections: []*Section{
+			{Kind: object, Types: []*Type{{Name: "User", Fields: []*Field{
+				{Name: "roles", Type: "[String]", Description: "The roles:\n\n- admin\n- user\n\n```graphql\n{ roles }\n```"},
+			}}}},
+		}}
+
+		var b bytes.Buffer
+		err := RenderHTML(&b, m)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ex := "<li>\n<p>roles <strong>([String])</strong></p>\n<p>The roles:</p>\n<ul>\n<li>admin</li>\n<li>user</li>\n</ul>\n<pre>"
+		if s := b.String(); !strings.Contains(s, ex) {
+			subT.Errorf("expected the description to be rendered within the field's list item, but got:\n%s", s)
+		}
+	})
+
+	t.Run("UnknownPolicy", func(subT *testing.T) {
+		err := new(Generator).Generate(gen.WithContext(context.Background(), make(pagesCtx)), testDoc, map[string]interface{}{"html": true, "rawHTML": "strip"})
+		if err == nil || !strings.Contains(err.Error(), "unknown raw HTML policy: strip") {
+			subT.Errorf("expected an unknown policy error, but got: %v", err)
+		}
+	})
+}
+
+func TestRender(t *testing.T) {
+	m := BuildModel(testDoc, &Options{Title: "Test Documentation"})
+
+	t.Run("Markdown", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := RenderMarkdown(&b, m)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ex, err := ioutil.ReadFile(*exMdDocName)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		gen.CompareBytes(subT, ex, b.Bytes())
+	})
+
+	t.Run("HTML", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := RenderHTML(&b, m)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ex, err := ioutil.ReadFile(*exHTMLDocName)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		gen.CompareBytes(subT, ex, b.Bytes())
+	})
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	var buf bytes.Buffer
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `schema {
+	query: Query
+}
+
+"Query represents the queries this example provides."
+type Query {
+	hello: String
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		return // Handle error
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, map[string]interface{}{"title": "Example Documentation"})
+	if err != nil {
+		return // Handle err
+	}
+
+	fmt.Println(b.String())
+
+	// Output:
+	// # Example Documentation
+	// *This was generated by gqlc.*
+	//
+	// ## Table of Contents
 	// - [Schema](#Schema)
 	// - [Objects](#Objects)
 	// 	* [Query](#Query)
@@ -619,3 +2464,92 @@ type Query {
 	// *Fields*:
 	// - hello **(String)**
 }
+
+func ExampleRenderMarkdown() {
+	gqlSrc := `"A user of the service."
+type User {
+	name: String!
+	friends(first: Int = 10): [User!]
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		return // Handle error
+	}
+
+	m := BuildModel(doc, &Options{Title: "Users"})
+
+	// The model can be modified before it's rendered
+	m.Sections[0].Types[0].Description += " Names are unique."
+
+	err = RenderMarkdown(os.Stdout, m)
+	if err != nil {
+		return // Handle error
+	}
+
+	// Output:
+	// # Users
+	// *This was generated by gqlc.*
+	//
+	// ## Table of Contents
+	// - [Objects](#Objects)
+	// 	* [User](#User)
+	//
+	// ## Objects
+	//
+	// ### User
+	// A user of the service. Names are unique.
+	//
+	// *Fields*:
+	// - name **(String!)**
+	// - friends **([[User](#User)!])**
+	//
+	// 	*Args*:
+	// 	- first **(Int)**
+	//
+	// 		*Default Value*: `10`
+}
+
+func ExampleRenderAsciiDoc() {
+	gqlSrc := `"A user of the service."
+type User {
+	name: String!
+	friends(first: Int = 10): [User!]
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		return // Handle error
+	}
+
+	err = RenderAsciiDoc(os.Stdout, BuildModel(doc, &Options{Title: "Users"}))
+	if err != nil {
+		return // Handle error
+	}
+
+	// Output:
+	// = Users
+	// :toc:
+	// :toclevels: 2
+	//
+	// _This was generated by gqlc._
+	//
+	// [#Objects]
+	// == Objects
+	//
+	// [#User]
+	// === User
+	//
+	// A user of the service.
+	//
+	// *Fields*:
+	//
+	// * `name` *(String!)*
+	// * `friends` *([<<User>>!])*
+	// +
+	// *Args*:
+	//
+	// ** `first` *(Int)*
+	// +
+	// *Default Value*: `+10+`
+}
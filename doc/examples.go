@@ -0,0 +1,153 @@
+// examples.go derives example operations for the root fields of a schema
+
+package doc
+
+import (
+	"strings"
+)
+
+// exampleDepth is how many levels of objects an example selects into,
+// which also keeps examples of cyclic types finite.
+//
+const exampleDepth = 3
+
+// rootOps are the kinds of operations, in the order they're documented.
+var rootOps = []string{"query", "mutation", "subscription"}
+
+// examples derives example operations from the types of a model.
+type examples struct {
+	types map[string]*Type
+	kinds map[string]string
+}
+
+// buildExamples sets the Example of every root field, i.e. the fields of
+// the root type of each kind of operation, to an operation calling it.
+//
+func buildExamples(m *Model, roots map[string]string) {
+	e := &examples{types: make(map[string]*Type), kinds: make(map[string]string)}
+	for _, s := range m.Sections {
+		for _, typ := range s.Types {
+			e.types[typ.Name] = typ
+			e.kinds[typ.Name] = s.Kind
+		}
+	}
+
+	for _, op := range rootOps {
+		if e.kinds[roots[op]] != object {
+			continue
+		}
+
+		for _, f := range e.types[roots[op]].Fields {
+			f.Example = e.operation(op, f)
+		}
+	}
+}
+
+// operation returns an operation which calls a root field, with a variable
+// for each of its arguments.
+//
+func (e *examples) operation(op string, f *Field) string {
+	var b strings.Builder
+	b.WriteString(op)
+	b.WriteByte(' ')
+	b.WriteString(strings.ToUpper(f.Name[:1]))
+	b.WriteString(f.Name[1:])
+
+	if len(f.Args) > 0 {
+		b.WriteByte('(')
+		for i, a := range f.Args {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteByte('$')
+			b.WriteString(a.Name)
+			b.WriteString(": ")
+			b.WriteString(a.Type)
+		}
+		b.WriteByte(')')
+	}
+	b.WriteString(" {\n  ")
+
+	b.WriteString(f.Name)
+	if len(f.Args) > 0 {
+		b.WriteByte('(')
+		for i, a := range f.Args {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(a.Name)
+			b.WriteString(": $")
+			b.WriteString(a.Name)
+		}
+		b.WriteByte(')')
+	}
+	e.selectionSet(&b, namedType(f.Type), 1, "  ")
+	b.WriteString("\n}\n")
+	return b.String()
+}
+
+// selectionSet writes the selection set of a type, if it has one, selecting
+// every field which doesn't require arguments.
+//
+func (e *examples) selectionSet(b *strings.Builder, name string, depth int, indent string) {
+	kind := e.kinds[name]
+	if kind != object && kind != inter && kind != union {
+		return
+	}
+
+	b.WriteString(" {")
+	in := indent + "  "
+
+	selected := false
+	for _, f := range e.types[name].Fields {
+		if requiresArgs(f) {
+			continue
+		}
+
+		fieldType := namedType(f.Type)
+		if e.hasSelections(fieldType) && depth >= exampleDepth {
+			continue
+		}
+
+		b.WriteByte('\n')
+		b.WriteString(in)
+		b.WriteString(f.Name)
+		e.selectionSet(b, fieldType, depth+1, in)
+		selected = true
+	}
+
+	if kind == union || !selected {
+		b.WriteByte('\n')
+		b.WriteString(in)
+		b.WriteString("__typename")
+	}
+
+	if kind == union && depth < exampleDepth {
+		for _, mem := range e.types[name].Members {
+			b.WriteByte('\n')
+			b.WriteString(in)
+			b.WriteString("... on ")
+			b.WriteString(mem)
+			e.selectionSet(b, mem, depth+1, in)
+		}
+	}
+
+	b.WriteByte('\n')
+	b.WriteString(indent)
+	b.WriteByte('}')
+}
+
+func (e *examples) hasSelections(name string) bool {
+	kind := e.kinds[name]
+	return kind == object || kind == inter || kind == union
+}
+
+// requiresArgs reports whether a field has arguments which must be given.
+func requiresArgs(f *Field) bool {
+	for _, a := range f.Args {
+		if strings.HasSuffix(a.Type, "!") && a.Default == "" {
+			return true
+		}
+	}
+	return false
+}
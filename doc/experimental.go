@@ -0,0 +1,176 @@
+// experimental.go marks the parts of a schema which aren't stable yet
+
+package doc
+
+import (
+	"strings"
+)
+
+// experimentalTitle is the title of the section summarizing everything
+// experimental, and experimentalAnchor where it's linked to.
+//
+const (
+	experimentalTitle  = "Experimental API"
+	experimentalAnchor = "Experimental-API"
+)
+
+// defaultStabilities are the directives marking experimental APIs, unless
+// the Experimental option names others.
+//
+var defaultStabilities = []string{"experimental", "beta"}
+
+// stabilities returns the names of the directives marking experimental APIs.
+func stabilities(opts *Options) []string {
+	if opts == nil || opts.Experimental == nil {
+		return defaultStabilities
+	}
+	return opts.Experimental
+}
+
+// buildExperimental sets the Stability of every type, field, argument,
+// input field and enum value marked by one of the directives named, and
+// collects them in the order they're documented.
+//
+func buildExperimental(m *Model, names []string) (exps []*Experimental) {
+	if len(names) == 0 {
+		return nil
+	}
+
+	mark := func(typ, name string, directives []string) string {
+		level := stability(directives, names)
+		if level != "" {
+			reason, _ := directiveReason(directives, level)
+			exps = append(exps, &Experimental{Type: typ, Name: name, Stability: level, Reason: reason})
+		}
+		return level
+	}
+
+	for _, s := range m.Sections {
+		if s.Kind == schema {
+			continue
+		}
+
+		for _, typ := range s.Types {
+			typ.Stability = mark(typ.Name, "", typ.Directives)
+			for _, f := range typ.Fields {
+				f.Stability = mark(typ.Name, f.Name, f.Directives)
+				for _, a := range f.Args {
+					a.Stability = mark(typ.Name, f.Name+"("+a.Name+")", a.Directives)
+				}
+			}
+		}
+	}
+	return
+}
+
+// stability returns the name of the first of directives which is one of
+// names, or empty if none of them are.
+//
+func stability(directives, names []string) string {
+	for _, d := range directives {
+		name := directiveName(d)
+		for _, n := range names {
+			if name == n {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// directiveReason returns the reason given to the named directive, if it's
+// in directives e.g. @beta(reason: "The API may change.")
+//
+func directiveReason(directives []string, name string) (reason string, ok bool) {
+	for _, d := range directives {
+		if directiveName(d) != name {
+			continue
+		}
+
+		args := strings.TrimSuffix(strings.TrimPrefix(d, "@"+name), ")")
+		if i := strings.Index(args, "reason:"); i >= 0 {
+			reason = strings.Trim(strings.TrimSpace(args[i+len("reason:"):]), `"`)
+		}
+		return reason, true
+	}
+	return "", false
+}
+
+// withoutDirective returns directives, without the named directive.
+func withoutDirective(directives []string, name string) (dirs []string) {
+	if name == "" {
+		return directives
+	}
+
+	for _, d := range directives {
+		if directiveName(d) != name {
+			dirs = append(dirs, d)
+		}
+	}
+	return
+}
+
+// badge returns the badge of a stability level e.g. BETA.
+func badge(level string) string {
+	return strings.ToUpper(level)
+}
+
+// stabilityLabel returns the label of the reason given to a stability
+// level e.g. Beta.
+//
+func stabilityLabel(level string) string {
+	if level == "" {
+		return ""
+	}
+	return strings.ToUpper(level[:1]) + level[1:]
+}
+
+// generateExperimental generates the Experimental API section, which links
+// everything experimental to the type it belongs to.
+//
+func (g *Generator) generateExperimental(exps []*Experimental) {
+	g.WriteString("## ")
+	g.WriteString(experimentalTitle)
+	g.WriteString("\n\n")
+	for _, e := range exps {
+		g.WriteString("- ")
+		g.WriteString(g.links.ref(e.String(), e.Type))
+		g.WriteString(" **")
+		g.WriteString(badge(e.Stability))
+		g.WriteString("**")
+		if e.Reason != "" {
+			g.WriteString(": ")
+			g.WriteString(e.Reason)
+		}
+		g.WriteByte('\n')
+	}
+}
+
+// writeStability writes the badge of a type, along with the reason it's
+// experimental.
+//
+func (g *Generator) writeStability(typ *Type) {
+	g.WriteString("**")
+	g.WriteString(badge(typ.Stability))
+	g.WriteString("**\n")
+	if reason, _ := directiveReason(typ.Directives, typ.Stability); reason != "" {
+		g.WriteString("\n*")
+		g.WriteString(stabilityLabel(typ.Stability))
+		g.WriteString("*: ")
+		g.WriteString(reason)
+		g.WriteByte('\n')
+	}
+}
+
+// writeCellBadge writes the badge of a stability level after the name in
+// the first cell of a table row.
+//
+func (g *Generator) writeCellBadge(level string) {
+	if level == "" {
+		return
+	}
+
+	g.WriteString(" **")
+	g.WriteString(badge(level))
+	g.WriteString("**")
+}
@@ -0,0 +1,132 @@
+// frontmatter.go writes YAML frontmatter at the top of Markdown files, so
+// they can be dropped into static site generators
+
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// frontmatter is written at the top of every Markdown file.
+type frontmatter struct {
+	slug   string
+	weight int
+
+	// keys are custom keys, in the order they were given.
+	keys [][2]string
+}
+
+// newFrontmatter returns the frontmatter configured by the options, or nil
+// if it's disabled.
+//
+func newFrontmatter(gOpts *Options) (*frontmatter, error) {
+	if !gOpts.Frontmatter {
+		return nil, nil
+	}
+
+	f := &frontmatter{slug: gOpts.Slug, weight: gOpts.Weight}
+	for _, kv := range gOpts.FrontmatterKeys {
+		i := strings.IndexByte(kv, '=')
+		if i < 1 {
+			return nil, fmt.Errorf("frontmatterKeys must be formatted as key=value, but got: %s", kv)
+		}
+		f.keys = append(f.keys, [2]string{strings.TrimSpace(kv[:i]), strings.TrimSpace(kv[i+1:])})
+	}
+	return f, nil
+}
+
+// page returns the frontmatter of the nth page under the index, whose slug
+// is nested under the slug of the index and which is weighted after it.
+//
+func (f *frontmatter) page(name string, n int) *frontmatter {
+	if f == nil {
+		return nil
+	}
+
+	p := *f
+	if p.slug != "" {
+		p.slug = strings.TrimSuffix(p.slug, "/") + "/" + name
+	}
+	p.weight += n
+	return &p
+}
+
+// write writes the frontmatter of a file titled title. Custom keys
+// override the title, slug and weight.
+//
+func (f *frontmatter) write(w io.Writer, title string) error {
+	if f == nil {
+		return nil
+	}
+
+	custom := make(map[string]bool, len(f.keys))
+	for _, kv := range f.keys {
+		custom[kv[0]] = true
+	}
+
+	var b bytes.Buffer
+	b.WriteString("---\n")
+	if !custom["title"] {
+		b.WriteString("title: ")
+		b.WriteString(strconv.Quote(title))
+		b.WriteByte('\n')
+	}
+	if f.slug != "" && !custom["slug"] {
+		b.WriteString("slug: ")
+		b.WriteString(strconv.Quote(f.slug))
+		b.WriteByte('\n')
+	}
+	if f.weight != 0 && !custom["weight"] {
+		b.WriteString("weight: ")
+		b.WriteString(strconv.Itoa(f.weight))
+		b.WriteByte('\n')
+	}
+	for _, kv := range f.keys {
+		b.WriteString(kv[0])
+		b.WriteString(": ")
+		b.WriteString(yamlScalar(kv[1]))
+		b.WriteByte('\n')
+	}
+	b.WriteString("---\n\n")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// yamlScalar returns a value as a YAML scalar. Booleans and numbers, along
+// with values which are already quoted, are written as is and everything
+// else is quoted, so it's always read as a string.
+//
+func yamlScalar(v string) string {
+	switch {
+	case v == "true", v == "false":
+		return v
+	case len(v) > 1 && (v[0] == '"' && v[len(v)-1] == '"' || v[0] == '\'' && v[len(v)-1] == '\''):
+		return v
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return v
+	}
+	return strconv.Quote(v)
+}
+
+// pageTitle returns the title of a page.
+func pageTitle(p *page) string {
+	switch {
+	case p.ops != nil:
+		return "Operations"
+	case p.deps != nil:
+		return "Deprecations"
+	case p.exps != nil:
+		return experimentalTitle
+	case p.typ != nil:
+		return p.typ.Name
+	case p.section.Kind == schema:
+		return sectionNames[schema]
+	}
+	return sectionNames[p.section.Kind] + "s"
+}
@@ -0,0 +1,389 @@
+// html.go converts the Markdown documentation to HTML, optionally as a
+// standalone, styled page
+
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/yuin/goldmark"
+	mdast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// newMarkdown returns the Markdown converter HTML is rendered with, which
+// renders raw HTML by the given policy.
+//
+func newMarkdown(tables bool, rawHTML string) goldmark.Markdown {
+	opts := []goldmark.Option{
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithRendererOptions(renderer.WithNodeRenderers(
+			util.Prioritized(codeRenderer{}, 100),
+			util.Prioritized(rawRenderer{policy: rawHTML}, 100),
+		)),
+	}
+	if tables {
+		opts = append(opts, goldmark.WithExtensions(extension.Table))
+	}
+	return goldmark.New(opts...)
+}
+
+// convertMarkdown converts Markdown to HTML, whose headings are given the
+// ids the Markdown links to them with.
+//
+func convertMarkdown(w io.Writer, md []byte, tables bool, rawHTML string) error {
+	ctx := parser.NewContext(parser.WithIDs(&headingIDs{used: make(map[string]bool)}))
+	return newMarkdown(tables, rawHTML).Convert(md, w, parser.WithContext(ctx))
+}
+
+// headingIDs gives headings their text as their id, e.g. ### User is
+// linked to as #User, with its spaces replaced by dashes.
+//
+type headingIDs struct {
+	used map[string]bool
+}
+
+// Generate implements the parser.IDs interface.
+func (ids *headingIDs) Generate(value []byte, kind mdast.NodeKind) []byte {
+	var b bytes.Buffer
+	for _, c := range bytes.TrimSpace(value) {
+		switch {
+		case isNameStart(c), c >= '0' && c <= '9', c == '-':
+			b.WriteByte(c)
+		case c == ' ' && !bytes.HasSuffix(b.Bytes(), []byte("-")):
+			b.WriteByte('-')
+		}
+	}
+	if b.Len() == 0 {
+		b.WriteString("heading")
+	}
+
+	id := b.String()
+	for n := 1; ids.used[id]; n++ {
+		id = fmt.Sprintf("%s-%d", b.String(), n)
+	}
+	ids.used[id] = true
+	return []byte(id)
+}
+
+// Put implements the parser.IDs interface.
+func (ids *headingIDs) Put(value []byte) { ids.used[string(value)] = true }
+
+// htmlPage is a standalone HTML page, which the HTML converted from the
+// Markdown is wrapped in, along with its styles.
+//
+type htmlPage struct {
+	title string
+	css   string
+}
+
+// newHTMLPage returns the page HTML is wrapped in, styled by a built-in
+// theme followed by a CSS file, or nil if neither is set, in which case
+// the HTML is left as a fragment to be embedded in another page.
+//
+func newHTMLPage(gCtx gen.GeneratorContext, doc *ast.Document, gOpts *Options) (*htmlPage, error) {
+	if gOpts.Theme == "" && gOpts.CSS == "" {
+		return nil, nil
+	}
+
+	p := &htmlPage{title: gOpts.Title, css: a11yCSS}
+	if gOpts.Theme != "" {
+		css, ok := themes[gOpts.Theme]
+		if !ok {
+			return nil, fmt.Errorf("unknown theme: %s, expected one of: %s", gOpts.Theme, strings.Join(themeNames(), ", "))
+		}
+		p.css += css
+	}
+	if gOpts.CSS == "" {
+		return p, nil
+	}
+
+	pCtx, ok := gCtx.(gen.PathContext)
+	if !ok {
+		return nil, fmt.Errorf("css can't be read by this generator context")
+	}
+
+	name := gOpts.CSS
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(filepath.Dir(pCtx.Source(doc)), name)
+	}
+	b, err := pCtx.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	p.css += string(b)
+	return p, nil
+}
+
+// tocID is the id of the table of contents heading, which labels the
+// navigation of a page.
+//
+const tocID = "Table-of-Contents"
+
+// writeHTML converts Markdown to HTML, wrapped in p if it's set. A page is
+// split into landmarks: the title is its header, the table of contents is
+// its navigation, which a link at the start of the page skips, and the rest
+// is its main content.
+//
+func writeHTML(w io.Writer, md []byte, tables bool, rawHTML string, p *htmlPage) error {
+	if p == nil {
+		return convertMarkdown(w, md, tables, rawHTML)
+	}
+
+	var body bytes.Buffer
+	err := convertMarkdown(&body, md, tables, rawHTML)
+	if err != nil {
+		return err
+	}
+	header, nav, main := splitLandmarks(body.Bytes())
+
+	var b bytes.Buffer
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n")
+	b.WriteString("<meta charset=\"utf-8\">\n")
+	b.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	b.WriteString("<title>")
+	b.WriteString(html.EscapeString(p.title))
+	b.WriteString("</title>\n")
+	if p.css != "" {
+		b.WriteString("<style>\n")
+		b.WriteString(p.css)
+		if !strings.HasSuffix(p.css, "\n") {
+			b.WriteByte('\n')
+		}
+		b.WriteString("</style>\n")
+	}
+	b.WriteString("</head>\n<body>\n")
+
+	// A page which is only a table of contents, e.g. the index of pages,
+	// has nothing to skip to.
+	//
+	if len(bytes.TrimSpace(main)) == 0 {
+		b.WriteString("<div class=\"gqlc-doc\">\n<main id=\"content\">\n")
+		writeLandmarks(&b, header, nav)
+		b.WriteString("</main>\n</div>\n</body>\n</html>\n")
+		_, err = b.WriteTo(w)
+		return err
+	}
+
+	if nav != nil {
+		b.WriteString("<a class=\"gqlc-skip\" href=\"#content\">Skip to content</a>\n")
+	}
+	b.WriteString("<div class=\"gqlc-doc\">\n")
+	writeLandmarks(&b, header, nav)
+	b.WriteString("<main id=\"content\" tabindex=\"-1\">\n")
+	b.Write(main)
+	b.WriteString("</main>\n</div>\n</body>\n</html>\n")
+	_, err = b.WriteTo(w)
+	return err
+}
+
+// writeHTML writes Markdown as HTML to the file, name, after auditing it.
+func (g *Generator) writeHTML(w io.Writer, name string, md []byte) error {
+	var b bytes.Buffer
+	err := writeHTML(&b, md, g.tables, g.rawHTML, g.htmlPage)
+	if err != nil {
+		return err
+	}
+
+	err = auditHTML(b.Bytes(), g.a11y, g.htmlPage != nil)
+	if err != nil {
+		return fmt.Errorf("%s: %s", name, err)
+	}
+
+	_, err = b.WriteTo(w)
+	return err
+}
+
+func writeLandmarks(b *bytes.Buffer, header, nav []byte) {
+	if len(bytes.TrimSpace(header)) > 0 {
+		b.WriteString("<header>\n")
+		b.Write(header)
+		b.WriteString("</header>\n")
+	}
+	if nav != nil {
+		b.WriteString("<nav aria-labelledby=\"" + tocID + "\">\n")
+		b.Write(nav)
+		b.WriteString("</nav>\n")
+	}
+}
+
+// splitLandmarks splits the HTML of a page at its table of contents, into
+// what's before it, the table of contents itself and what's after it. The
+// table of contents is nil if there isn't one, in which case everything is
+// the main content.
+//
+func splitLandmarks(body []byte) (header, nav, main []byte) {
+	start := bytes.Index(body, []byte("<h2 id=\""+tocID+"\">"))
+	if start < 0 {
+		return nil, nil, body
+	}
+
+	// The table of contents ends with its outermost list
+	end := start + bytes.Index(body[start:], []byte("</h2>\n")) + len("</h2>\n")
+	if bytes.HasPrefix(body[end:], []byte("<ul>")) {
+		depth := 0
+		for i := end; i < len(body); i++ {
+			switch {
+			case bytes.HasPrefix(body[i:], []byte("<ul>")):
+				depth++
+			case bytes.HasPrefix(body[i:], []byte("</ul>\n")):
+				depth--
+				if depth == 0 {
+					end = i + len("</ul>\n")
+					i = len(body)
+				}
+			}
+		}
+	}
+	return body[:start], body[start:end], body[end:]
+}
+
+// codeRenderer renders fenced code blocks, highlighting those written in
+// GraphQL, i.e. SDL snippets in descriptions.
+//
+type codeRenderer struct{}
+
+func (codeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(mdast.KindFencedCodeBlock, renderCodeBlock)
+}
+
+func renderCodeBlock(w util.BufWriter, source []byte, node mdast.Node, entering bool) (mdast.WalkStatus, error) {
+	if !entering {
+		w.WriteString("</code></pre>\n")
+		return mdast.WalkContinue, nil
+	}
+
+	n := node.(*mdast.FencedCodeBlock)
+	lang := string(n.Language(source))
+
+	var code bytes.Buffer
+	for i := 0; i < n.Lines().Len(); i++ {
+		line := n.Lines().At(i)
+		code.Write(line.Value(source))
+	}
+
+	w.WriteString("<pre><code")
+	if lang != "" {
+		w.WriteString(" class=\"language-")
+		w.WriteString(html.EscapeString(lang))
+		w.WriteByte('"')
+	}
+	w.WriteByte('>')
+
+	switch lang {
+	case "graphql", "gql":
+		highlight(w, code.String())
+	default:
+		w.WriteString(html.EscapeString(code.String()))
+	}
+	return mdast.WalkContinue, nil
+}
+
+var gqlKeywords = map[string]bool{
+	"schema": true, "scalar": true, "type": true, "interface": true,
+	"union": true, "enum": true, "input": true, "directive": true,
+	"extend": true, "implements": true, "repeatable": true, "on": true,
+	"query": true, "mutation": true, "subscription": true, "fragment": true,
+	"true": true, "false": true, "null": true,
+}
+
+// highlight writes GraphQL as HTML, with its keywords, directives,
+// variables, strings, numbers and comments in spans classed by kind,
+// e.g. gql-keyword, for themes to style.
+//
+func highlight(w util.BufWriter, src string) {
+	for i := 0; i < len(src); {
+		c := src[i]
+		end := i + 1
+		class := ""
+
+		switch {
+		case c == '#':
+			end = strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += i
+			}
+			class = "gql-comment"
+		case strings.HasPrefix(src[i:], `"""`):
+			end = strings.Index(src[i+3:], `"""`)
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += i + 6
+			}
+			class = "gql-string"
+		case c == '"':
+			for end < len(src) && src[end] != '"' && src[end] != '\n' {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end < len(src) && src[end] == '"' {
+				end++
+			}
+			class = "gql-string"
+		case (c == '@' || c == '$') && end < len(src) && isNameStart(src[end]):
+			end = nameEnd(src, end)
+			class = "gql-directive"
+			if c == '$' {
+				class = "gql-variable"
+			}
+		case isNameStart(c):
+			end = nameEnd(src, end)
+			if gqlKeywords[src[i:end]] {
+				class = "gql-keyword"
+			}
+		case c == '-' || c >= '0' && c <= '9':
+			for end < len(src) && (src[end] >= '0' && src[end] <= '9' || strings.IndexByte(".eE+-", src[end]) >= 0) {
+				end++
+			}
+			if src[i:end] != "-" {
+				class = "gql-number"
+			}
+		}
+		if end > len(src) {
+			end = len(src)
+		}
+
+		if class != "" {
+			w.WriteString("<span class=\"")
+			w.WriteString(class)
+			w.WriteString("\">")
+		}
+		w.WriteString(html.EscapeString(src[i:end]))
+		if class != "" {
+			w.WriteString("</span>")
+		}
+		i = end
+	}
+}
+
+func nameEnd(src string, i int) int {
+	for i < len(src) && (isNameStart(src[i]) || src[i] >= '0' && src[i] <= '9') {
+		i++
+	}
+	return i
+}
+
+func themeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
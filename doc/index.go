@@ -0,0 +1,155 @@
+// index.go contains the options of the table of contents, and the
+// alphabetical index, which keep large schemas navigable
+
+package doc
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+)
+
+// alphabetical names the alphabetical index page, which is titled
+// alphabeticalTitle.
+//
+const (
+	alphabetical      = "alphabetical"
+	alphabeticalTitle = "Alphabetical Index"
+)
+
+// tocOptions control what the table of contents lists.
+type tocOptions struct {
+	// kinds are the sections listed, or all of them if it's empty.
+	kinds map[string]bool
+
+	// collapse lists only the section, without its types, of sections
+	// with more types than it, unless it's 0.
+	//
+	collapse int
+
+	// index is the page of the alphabetical index, if there is one.
+	index string
+}
+
+// newTocOptions returns the table of contents options, or nil if they're
+// all defaults.
+//
+func newTocOptions(gOpts *Options, index string) (*tocOptions, error) {
+	if len(gOpts.TocKinds) == 0 && gOpts.TocCollapse == 0 && index == "" {
+		return nil, nil
+	}
+	if gOpts.TocCollapse < 0 {
+		return nil, fmt.Errorf("tocCollapse must not be negative, but got: %d", gOpts.TocCollapse)
+	}
+
+	toc := &tocOptions{collapse: gOpts.TocCollapse, index: index}
+	for _, name := range gOpts.TocKinds {
+		// Kinds are named like sections are, e.g. object or objects
+		kind, ok := sectionKinds[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown tocKinds kind: %s, expected any of: %s", name, sectionList)
+		}
+
+		if toc.kinds == nil {
+			toc.kinds = make(map[string]bool)
+		}
+		toc.kinds[kind] = true
+	}
+	return toc, nil
+}
+
+// lists reports whether sections of a kind are listed.
+func (t *tocOptions) lists(kind string) bool {
+	return t == nil || t.kinds == nil || t.kinds[kind]
+}
+
+// collapses reports whether the types of a section are left out.
+func (t *tocOptions) collapses(n int) bool {
+	return t != nil && t.collapse > 0 && n > t.collapse
+}
+
+// generateIndex generates an alphabetical index of every type and
+// directive, grouped by their first letter and linked to their
+// documentation.
+//
+func (g *Generator) generateIndex(m *Model) {
+	type entry struct{ name, kind string }
+
+	var entries []entry
+	for _, s := range m.Sections {
+		if s.Kind == schema {
+			continue
+		}
+
+		for _, typ := range s.Types {
+			entries = append(entries, entry{name: typ.Name, kind: s.Kind})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := strings.ToLower(entries[i].name), strings.ToLower(entries[j].name)
+		if a != b {
+			return a < b
+		}
+		return entries[i].name < entries[j].name
+	})
+
+	g.WriteString("# " + alphabeticalTitle + "\n")
+
+	var letter string
+	for _, e := range entries {
+		if l := strings.ToUpper(e.name[:1]); l != letter {
+			letter = l
+			g.WriteString("\n## ")
+			g.WriteString(l)
+			g.WriteString("\n\n")
+		}
+
+		name := e.name
+		if e.kind == directive {
+			name = "@" + name
+		}
+		g.WriteString("- ")
+		g.WriteString(g.links.ref(name, e.name))
+		g.WriteString(" *")
+		g.WriteString(e.kind)
+		g.WriteString("*\n")
+	}
+}
+
+// writeIndex writes the alphabetical index of a single file of
+// documentation, next to it, as .md and .html if it's enabled.
+//
+func (g *Generator) writeIndex(gCtx gen.GeneratorContext, base string, m *Model, gOpts *Options) error {
+	// Every type is documented on the same page
+	page := filepath.Base(base)
+	l := newLinker(m, false)
+	l.pages = make(map[string]string)
+	for _, s := range m.Sections {
+		for _, typ := range s.Types {
+			l.pages[typ.Name] = page
+		}
+	}
+	l.page = alphabetical
+
+	ig := g.fork()
+	ig.links = l
+
+	exts := []string{".md"}
+	if gOpts.HTML {
+		exts = append(exts, ".html")
+	}
+	for _, ext := range exts {
+		l.ext = ext
+
+		ig.Reset()
+		ig.generateIndex(m)
+		err := ig.writePage(gCtx, base+"-"+alphabetical+ext, g.front.page(alphabetical, 1), alphabeticalTitle, ig.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
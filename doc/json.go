@@ -0,0 +1,120 @@
+// json.go writes the documentation model as JSON, for tools to consume
+
+package doc
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/gqlc/gqlc/gen"
+)
+
+// jsonModel is a documentation model as it's written to JSON, along with
+// its table of contents and everything deprecated.
+//
+type jsonModel struct {
+	*Model
+
+	ToC          []*tocEntry    `json:"toc"`
+	Deprecations []*Deprecation `json:"deprecations"`
+}
+
+// tocEntry is a link in the table of contents, to a section along with its
+// types, or to a type.
+//
+type tocEntry struct {
+	Name  string      `json:"name"`
+	Href  string      `json:"href,omitempty"`
+	Types []*tocEntry `json:"types,omitempty"`
+}
+
+// RenderJSON renders a documentation model as JSON, along with its table of
+// contents and everything deprecated, so it can be consumed by tools e.g. to
+// index it for search.
+//
+func RenderJSON(w io.Writer, m *Model) error {
+	return writeJSON(w, m, nil, nil)
+}
+
+// writeJSON writes a model as JSON, linking to the documentation of each
+// type by l.
+//
+func writeJSON(w io.Writer, m *Model, l *linker, toc *tocOptions) error {
+	deps := m.Deprecations
+	if deps == nil {
+		deps = buildDeprecations(m)
+	}
+	if deps == nil {
+		deps = []*Deprecation{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonModel{Model: m, ToC: buildToC(m, l, toc), Deprecations: deps})
+}
+
+// buildToC builds the table of contents, like writeToC writes it.
+func buildToC(m *Model, l *linker, toc *tocOptions) []*tocEntry {
+	entries := []*tocEntry{}
+	for _, s := range m.Sections {
+		if !toc.lists(s.Kind) {
+			continue
+		}
+
+		name := sectionNames[s.Kind]
+		if s.Kind != schema {
+			name += "s"
+		}
+		e := &tocEntry{Name: name, Href: l.sectionHref(s.Kind, name)}
+		entries = append(entries, e)
+
+		if s.Kind == schema || toc.collapses(len(s.Types)) {
+			continue
+		}
+
+		for _, typ := range s.Types {
+			e.Types = append(e.Types, &tocEntry{Name: typ.Name, Href: l.href(typ.Name)})
+		}
+	}
+
+	if len(m.Operations) > 0 && toc.lists(operations) {
+		e := &tocEntry{Name: "Operations", Href: l.sectionHref(operations, "Operations")}
+		entries = append(entries, e)
+		for _, op := range m.Operations {
+			if toc.collapses(len(m.Operations)) {
+				break
+			}
+			e.Types = append(e.Types, &tocEntry{Name: op.Name, Href: l.opHref(op.Name)})
+		}
+	}
+
+	if len(m.Deprecations) > 0 && toc.lists(deprecations) {
+		entries = append(entries, &tocEntry{Name: "Deprecations", Href: l.sectionHref(deprecations, "Deprecations")})
+	}
+	if len(m.Experimental) > 0 && toc.lists(experimental) {
+		entries = append(entries, &tocEntry{Name: experimentalTitle, Href: l.sectionHref(experimental, experimentalAnchor)})
+	}
+	return entries
+}
+
+// writeJSONFile writes the model to a .json file, which links to the
+// Markdown documentation next to it.
+//
+func (g *Generator) writeJSONFile(gCtx gen.GeneratorContext, name string, m *Model) error {
+	f, err := gCtx.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Every link is from outside of the pages
+	l := g.links
+	if l != nil {
+		lc := *l
+		lc.ext = ".md"
+		lc.page = ""
+		l = &lc
+	}
+	return writeJSON(f, m, l, g.toc)
+}
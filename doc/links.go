@@ -0,0 +1,332 @@
+// links.go links the type names mentioned in descriptions to their documentation
+
+package doc
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+)
+
+// linker links type names in description text to the sections documenting
+// them. Names written as [[Name]] are always linked, and with auto set,
+// so is every other mention of a documented type.
+//
+type linker struct {
+	names map[string]bool
+	auto  bool
+
+	// xref writes AsciiDoc cross references, instead of Markdown links
+	xref bool
+
+	// wiki writes GitHub wiki links, which name the page of a type
+	wiki bool
+
+	// pages maps types, and sections, to the pages documenting them, when
+	// the documentation is split into pages, which are linked to with ext.
+	// Links to the page being written, page, are left as anchors.
+	//
+	pages    map[string]string
+	sections map[string]string
+	ext      string
+	page     string
+
+	// docs maps the types of other documents, generated alongside this
+	// one, to the files documenting them, relative to this one's.
+	//
+	docs map[string]string
+}
+
+// newLinker returns a linker for the types documented by a model. The
+// schema and directives aren't linked, since directive names are likely
+// to be ordinary words.
+//
+func newLinker(m *Model, auto bool) *linker {
+	l := &linker{names: make(map[string]bool), auto: auto}
+	for _, s := range m.Sections {
+		if s.Kind == schema || s.Kind == directive {
+			continue
+		}
+
+		for _, typ := range s.Types {
+			l.names[typ.Name] = true
+		}
+	}
+	return l
+}
+
+// link returns text with the type names in it linked, except for self, so
+// a type's documentation doesn't link to itself. Code, links and URLs are
+// left as they are.
+//
+func (l *linker) link(text, self string) string {
+	if l == nil || len(l.names)+len(l.docs) == 0 || (!l.auto && !strings.Contains(text, "[[")) {
+		return text
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+
+	fenced := false
+	for i := 0; i < len(text); {
+		// Fenced code blocks are copied a line at a time
+		if i == 0 || text[i-1] == '\n' {
+			end := strings.IndexByte(text[i:], '\n') + i + 1
+			if end == i {
+				end = len(text)
+			}
+
+			line := text[i:end]
+			if strings.HasPrefix(strings.TrimSpace(line), "```") {
+				fenced = !fenced
+			}
+			if fenced || strings.HasPrefix(strings.TrimSpace(line), "```") {
+				b.WriteString(line)
+				i = end
+				continue
+			}
+		}
+
+		c := text[i]
+		switch {
+		case c == '`':
+			// Copy the code span up to the closing run of backticks
+			n := len(text[i:]) - len(strings.TrimLeft(text[i:], "`"))
+			end := strings.Index(text[i+n:], text[i:i+n])
+			if end < 0 {
+				b.WriteString(text[i : i+n])
+				i += n
+				continue
+			}
+			end += i + 2*n
+			b.WriteString(text[i:end])
+			i = end
+		case strings.HasPrefix(text[i:], "[["):
+			end := strings.Index(text[i:], "]]")
+			if end > 2 && l.linkable(text[i+2:i+end]) {
+				l.writeLink(&b, text[i+2:i+end])
+				i += end + 2
+				continue
+			}
+			b.WriteString("[[")
+			i += 2
+		case c == '[':
+			// Copy an existing link, so its text isn't linked again
+			end := strings.Index(text[i:], "](")
+			if end < 0 {
+				b.WriteByte(c)
+				i++
+				continue
+			}
+			close := strings.IndexByte(text[i+end:], ')')
+			if close < 0 {
+				b.WriteByte(c)
+				i++
+				continue
+			}
+			end += i + close + 1
+			b.WriteString(text[i:end])
+			i = end
+		case c == '<' || strings.HasPrefix(text[i:], "http://") || strings.HasPrefix(text[i:], "https://"):
+			// Copy autolinks, HTML and URLs up to where they end
+			stop := " \t\n"
+			if c == '<' {
+				stop = ">\n"
+			}
+			end := strings.IndexAny(text[i+1:], stop)
+			if end < 0 {
+				end = len(text)
+			} else {
+				end += i + 1
+			}
+			b.WriteString(text[i:end])
+			i = end
+		case isNameStart(c):
+			end := i + 1
+			for end < len(text) && (isNameStart(text[end]) || text[end] >= '0' && text[end] <= '9') {
+				end++
+			}
+
+			name := text[i:end]
+			if l.auto && name != self && l.linkable(name) {
+				l.writeLink(&b, name)
+			} else {
+				b.WriteString(name)
+			}
+			i = end
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func (l *linker) writeLink(b *strings.Builder, name string) {
+	if l.xref {
+		b.WriteString("<<")
+		b.WriteString(name)
+		b.WriteString(">>")
+		return
+	}
+	b.WriteString(l.ref(name, name))
+}
+
+// ref returns a link to the documentation of a type, labelled text. Wiki
+// links are only written to types with pages of their own, which are found
+// by name wherever they are in the wiki.
+//
+func (l *linker) ref(text, name string) string {
+	if page, ok := l.wikiPage(name); ok && page == name {
+		return wikiLink(text, name)
+	}
+	return "[" + text + "](" + l.href(name) + ")"
+}
+
+// linkable reports whether a type is documented, by this document or
+// another.
+//
+func (l *linker) linkable(name string) bool {
+	if l.names[name] {
+		return true
+	}
+	_, ok := l.docs[name]
+	return ok
+}
+
+// href returns where the documentation of a type is.
+func (l *linker) href(name string) string {
+	if l == nil {
+		return "#" + name
+	}
+	if doc, ok := l.docs[name]; ok && !l.names[name] {
+		ext := l.ext
+		if ext == "" {
+			ext = ".md"
+		}
+		return doc + ext + "#" + name
+	}
+	return l.pageHref(l.pages[name], name)
+}
+
+// opHref returns where the documentation of an operation is.
+func (l *linker) opHref(name string) string {
+	if l == nil {
+		return "#" + name
+	}
+	return l.pageHref(l.sections[operations], name)
+}
+
+// sectionHref returns where a section is, which is nowhere when every
+// type of the section has its own page.
+//
+func (l *linker) sectionHref(kind, name string) string {
+	if l == nil || l.sections == nil {
+		return "#" + name
+	}
+
+	page, ok := l.sections[kind]
+	if !ok {
+		return ""
+	}
+	return l.pageHref(page, name)
+}
+
+func (l *linker) pageHref(page, anchor string) string {
+	if page == "" || page == l.page {
+		return "#" + anchor
+	}
+	return page + l.ext + "#" + anchor
+}
+
+// otherDocs maps the types of the other documents being generated along
+// with doc to the files documenting them, without their extension and
+// relative to the files documenting doc. The documents are assumed to be
+// documented with the same options as doc, which is the case unless their
+// document directives differ.
+//
+func otherDocs(gCtx gen.GeneratorContext, doc *ast.Document, gOpts *Options) map[string]string {
+	docs := gen.Documents(gCtx)
+	if len(docs) < 2 {
+		return nil
+	}
+
+	// Pages are written to a directory named after the document
+	base := strings.TrimSuffix(doc.Name, filepath.Ext(doc.Name))
+	dir := path.Dir(filepath.ToSlash(base))
+	if gOpts.MultiPage {
+		dir = filepath.ToSlash(base)
+	}
+
+	local := make(map[string]bool, len(doc.Types))
+	for _, decl := range doc.Types {
+		if ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec); ok && ts.TypeSpec.Name != nil {
+			local[ts.TypeSpec.Name.Name] = true
+		}
+	}
+
+	// Types defined more than once are linked to the first document, by name
+	docs = append([]*ast.Document(nil), docs...)
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Name < docs[j].Name })
+
+	refs := make(map[string]string)
+	for _, other := range docs {
+		if other.Name == doc.Name {
+			continue
+		}
+
+		oBase := filepath.ToSlash(strings.TrimSuffix(other.Name, filepath.Ext(other.Name)))
+		m := BuildModel(other, nil)
+		pages := make(map[string]string)
+		if gOpts.MultiPage {
+			for _, p := range splitPages(m, gOpts.Pages == pagesType) {
+				if p.typ != nil {
+					pages[p.typ.Name] = p.name
+					continue
+				}
+				if p.section == nil {
+					continue
+				}
+				for _, typ := range p.section.Types {
+					pages[typ.Name] = p.name
+				}
+			}
+		}
+
+		for _, s := range m.Sections {
+			if s.Kind == schema || s.Kind == directive {
+				continue
+			}
+
+			for _, typ := range s.Types {
+				if _, ok := refs[typ.Name]; ok || local[typ.Name] {
+					continue
+				}
+
+				file := oBase
+				if gOpts.MultiPage {
+					file = path.Join(oBase, pages[typ.Name])
+				}
+				refs[typ.Name] = relPath(dir, file)
+			}
+		}
+	}
+	return refs
+}
+
+// relPath returns the slash separated path of target relative to dir.
+func relPath(dir, target string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(dir), filepath.FromSlash(target))
+	if err != nil {
+		return target
+	}
+	return filepath.ToSlash(rel)
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,621 @@
+// model.go contains the document model which is rendered as documentation
+
+package doc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gqlc/gqlc/types"
+	"github.com/gqlc/graphql/ast"
+)
+
+// Model is the documentation of a GraphQL Document. It's independent of
+// any output format, so it can be rendered by RenderMarkdown and RenderHTML
+// or by other tools embedding schema documentation.
+//
+type Model struct {
+	Title    string     `json:"title"`
+	Sections []*Section `json:"sections,omitempty"`
+
+	// Operations are documented after the types, see ParseOperations.
+	Operations []*Operation `json:"operations,omitempty"`
+
+	// Deprecations summarize everything deprecated, at the end of the
+	// documentation, if Options.Deprecations is set.
+	//
+	Deprecations []*Deprecation `json:"deprecations,omitempty"`
+
+	// Experimental summarizes everything marked experimental, at the end
+	// of the documentation, see Options.Experimental.
+	//
+	Experimental []*Experimental `json:"experimental,omitempty"`
+}
+
+// Section groups the documented types of a single kind.
+type Section struct {
+	// Kind is one of: schema, scalar, object, interface, union, enum,
+	// input or directive.
+	//
+	Kind  string  `json:"kind"`
+	Types []*Type `json:"types,omitempty"`
+}
+
+// Type documents a single type declaration. The schema is documented as a
+// type named schema whose fields are its root operations.
+//
+type Type struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Directives  []string `json:"directives,omitempty"`
+
+	// Stability is the directive marking the type experimental e.g.
+	// beta, if it is.
+	//
+	Stability string `json:"stability,omitempty"`
+
+	// Interfaces are the interfaces implemented by an object
+	Interfaces []string `json:"interfaces,omitempty"`
+
+	// Members are the possible types of a union
+	Members []string `json:"members,omitempty"`
+
+	// Fields are the fields of an object, interface or input, the values
+	// of an enum, the arguments of a directive or the root operations of
+	// the schema.
+	//
+	Fields []*Field `json:"fields,omitempty"`
+
+	// Usages are where a directive is applied, if Options.DirectiveUsage
+	// is set.
+	//
+	Usages []*Usage `json:"usages,omitempty"`
+}
+
+// Field documents a field, argument, input field, enum value or root operation.
+type Field struct {
+	Name string `json:"name"`
+
+	// Type is the GraphQL type of the field e.g. [String!]!, which is
+	// empty for enum values.
+	//
+	Type string `json:"type,omitempty"`
+
+	Description string   `json:"description,omitempty"`
+	Directives  []string `json:"directives,omitempty"`
+
+	// Stability is the directive marking the field experimental, if it is.
+	Stability string `json:"stability,omitempty"`
+
+	// Default is the default value of an argument or input field
+	Default string `json:"default,omitempty"`
+
+	Args []*Field `json:"args,omitempty"`
+
+	// Example is an operation calling a root field, if Options.Examples
+	// is set.
+	//
+	Example string `json:"example,omitempty"`
+}
+
+// Deprecation is a field, argument, input field or enum value which is
+// deprecated, i.e. scheduled for removal.
+//
+type Deprecation struct {
+	// Type is the type it belongs to, or the directive it's an argument of.
+	Type string `json:"type,omitempty"`
+
+	// Name is its name, along with its field if it's an argument e.g.
+	// user(id).
+	//
+	Name string `json:"name"`
+
+	Reason string `json:"reason,omitempty"`
+}
+
+// Experimental is a type, field, argument, input field or enum value which
+// is marked experimental, i.e. it may change or be removed without notice.
+//
+type Experimental struct {
+	// Type is the type it is or belongs to, or the directive it's an
+	// argument of.
+	//
+	Type string `json:"type,omitempty"`
+
+	// Name is its name, along with its field if it's an argument e.g.
+	// user(id), or empty if it's the type itself.
+	//
+	Name string `json:"name"`
+
+	// Stability is the directive marking it e.g. beta
+	Stability string `json:"stability"`
+
+	Reason string `json:"reason,omitempty"`
+}
+
+// String returns the qualified name of what's experimental e.g. User.name
+func (e *Experimental) String() string {
+	if e.Name == "" {
+		return e.Type
+	}
+	return e.Type + "." + e.Name
+}
+
+// Usage is a type, field, argument, input field or enum value which a
+// directive is applied to.
+//
+type Usage struct {
+	// Type is the type it is or belongs to, or the directive it's an
+	// argument of.
+	//
+	Type string `json:"type,omitempty"`
+
+	// Name is its name, along with its field if it's an argument e.g.
+	// user(id), or empty if it's the type itself.
+	//
+	Name string `json:"name"`
+}
+
+// String returns the qualified name of a usage e.g. User.name
+func (u *Usage) String() string {
+	if u.Name == "" {
+		return u.Type
+	}
+	return u.Type + "." + u.Name
+}
+
+// BuildModel builds the documentation model of a GraphQL Document. Types
+// are grouped into sections in the order their kind first appears, so the
+// declarations should already be sorted, as the gqlc command does, unless
+// the Sections option orders them. Type
+// extensions are expected to have been merged into their types and are
+// skipped, along with any gqlc directives.
+//
+func BuildModel(doc *ast.Document, opts *Options) *Model {
+	m := &Model{Title: "Documentation"}
+	if opts != nil {
+		m.Title = opts.Title
+	}
+
+	sections := make(map[string]*Section)
+	for _, decl := range doc.Types {
+		d, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+		ts := d.TypeSpec
+
+		typ := &Type{
+			Name:        schema,
+			Description: description(decl.Doc),
+			Directives:  directiveStrings(ts.Directives),
+		}
+		if ts.Name != nil {
+			typ.Name = ts.Name.Name
+		}
+
+		var kind string
+		switch v := ts.Type.(type) {
+		case *ast.TypeSpec_Schema:
+			kind = schema
+			if v.Schema.RootOps != nil {
+				typ.Fields = buildFields(v.Schema.RootOps.List)
+			}
+		case *ast.TypeSpec_Scalar:
+			kind = scalar
+		case *ast.TypeSpec_Object:
+			kind = object
+			for _, inter := range v.Object.Interfaces {
+				typ.Interfaces = append(typ.Interfaces, inter.Name)
+			}
+			if v.Object.Fields != nil {
+				typ.Fields = buildFields(v.Object.Fields.List)
+			}
+		case *ast.TypeSpec_Interface:
+			kind = inter
+			if v.Interface.Fields != nil {
+				typ.Fields = buildFields(v.Interface.Fields.List)
+			}
+		case *ast.TypeSpec_Union:
+			kind = union
+			for _, mem := range v.Union.Members {
+				typ.Members = append(typ.Members, mem.Name)
+			}
+		case *ast.TypeSpec_Enum:
+			kind = enum
+			if v.Enum.Values != nil {
+				typ.Fields = buildFields(v.Enum.Values.List)
+			}
+		case *ast.TypeSpec_Input:
+			kind = input
+			if v.Input.Fields != nil {
+				typ.Fields = buildArgs(v.Input.Fields.List)
+			}
+		case *ast.TypeSpec_Directive:
+			kind = directive
+			if v.Directive.Args != nil {
+				typ.Fields = buildArgs(v.Directive.Args.List)
+			}
+		default:
+			continue
+		}
+
+		s, ok := sections[kind]
+		if !ok {
+			s = &Section{Kind: kind}
+			sections[kind] = s
+			m.Sections = append(m.Sections, s)
+		}
+		s.Types = append(s.Types, typ)
+	}
+
+	if opts != nil && len(opts.Sections) > 0 {
+		m.Sections = orderSections(m.Sections, opts.Sections)
+	}
+
+	if opts != nil && opts.Examples {
+		buildExamples(m, rootTypes(doc))
+	}
+	if opts != nil && opts.Deprecations && showsSection(opts, deprecations) {
+		m.Deprecations = buildDeprecations(m)
+	}
+	if exps := buildExperimental(m, stabilities(opts)); showsSection(opts, experimental) {
+		m.Experimental = exps
+	}
+	if opts != nil && opts.DirectiveUsage {
+		buildUsages(m)
+	}
+	return m
+}
+
+// schemaDirectives returns the directives applied to the schema, including
+// those of schema extensions, which are merged into it.
+//
+func (m *Model) schemaDirectives() []string {
+	for _, s := range m.Sections {
+		if s.Kind == schema && len(s.Types) > 0 {
+			return s.Types[0].Directives
+		}
+	}
+	return nil
+}
+
+// buildUsages sets the Usages of every directive declared in the
+// document, in the order they're documented.
+//
+func buildUsages(m *Model) {
+	dirs := make(map[string]*Type)
+	for _, s := range m.Sections {
+		if s.Kind != directive {
+			continue
+		}
+
+		for _, typ := range s.Types {
+			dirs[typ.Name] = typ
+		}
+	}
+	if len(dirs) == 0 {
+		return
+	}
+
+	add := func(typ, name string, directives []string) {
+		for _, d := range directives {
+			dir, ok := dirs[directiveName(d)]
+			if !ok {
+				continue
+			}
+
+			// Repeatable directives are only listed once
+			if n := len(dir.Usages); n > 0 && dir.Usages[n-1].Type == typ && dir.Usages[n-1].Name == name {
+				continue
+			}
+			dir.Usages = append(dir.Usages, &Usage{Type: typ, Name: name})
+		}
+	}
+
+	for _, s := range m.Sections {
+		for _, typ := range s.Types {
+			add(typ.Name, "", typ.Directives)
+			for _, f := range typ.Fields {
+				add(typ.Name, f.Name, f.Directives)
+				for _, a := range f.Args {
+					add(typ.Name, f.Name+"("+a.Name+")", a.Directives)
+				}
+			}
+		}
+	}
+}
+
+// directiveName returns the name of an applied directive e.g. key for
+// @key(fields: "id")
+//
+func directiveName(directive string) string {
+	name := strings.TrimPrefix(directive, "@")
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// buildDeprecations collects everything deprecated, in the order it's documented.
+func buildDeprecations(m *Model) (deps []*Deprecation) {
+	add := func(typ, name string, directives []string) {
+		if reason, ok := deprecation(directives); ok {
+			deps = append(deps, &Deprecation{Type: typ, Name: name, Reason: reason})
+		}
+	}
+
+	for _, s := range m.Sections {
+		if s.Kind == schema {
+			continue
+		}
+
+		for _, typ := range s.Types {
+			for _, f := range typ.Fields {
+				add(typ.Name, f.Name, f.Directives)
+				for _, a := range f.Args {
+					add(typ.Name, f.Name+"("+a.Name+")", a.Directives)
+				}
+			}
+		}
+	}
+	return
+}
+
+func buildFields(fields []*ast.Field) []*Field {
+	docFields := make([]*Field, len(fields))
+	for i, f := range fields {
+		var typ interface{}
+		switch v := f.Type.(type) {
+		case *ast.Field_Ident:
+			typ = v.Ident
+		case *ast.Field_List:
+			typ = v.List
+		case *ast.Field_NonNull:
+			typ = v.NonNull
+		}
+
+		docFields[i] = &Field{
+			Name:        f.Name.Name,
+			Type:        typeString(typ),
+			Description: description(f.Doc),
+			Directives:  directiveStrings(f.Directives),
+		}
+		if f.Args != nil {
+			docFields[i].Args = buildArgs(f.Args.List)
+		}
+	}
+	return docFields
+}
+
+func buildArgs(args []*ast.InputValue) []*Field {
+	docArgs := make([]*Field, len(args))
+	for i, a := range args {
+		var typ interface{}
+		switch v := a.Type.(type) {
+		case *ast.InputValue_Ident:
+			typ = v.Ident
+		case *ast.InputValue_List:
+			typ = v.List
+		case *ast.InputValue_NonNull:
+			typ = v.NonNull
+		}
+
+		var def interface{}
+		switch v := a.Default.(type) {
+		case *ast.InputValue_BasicLit:
+			def = v.BasicLit
+		case *ast.InputValue_CompositeLit:
+			def = v.CompositeLit
+		}
+
+		docArgs[i] = &Field{
+			Name:        a.Name.Name,
+			Type:        typeString(typ),
+			Description: description(a.Doc),
+			Directives:  directiveStrings(a.Directives),
+		}
+		if def != nil {
+			docArgs[i].Default = valueString(def)
+		}
+	}
+	return docArgs
+}
+
+func description(doc *ast.DocGroup) string {
+	return strings.TrimSuffix(doc.Text(), "\n")
+}
+
+// directiveStrings returns the applied directives, except for those of gqlc,
+// in GraphQL syntax e.g. @deprecated(reason: "Use other.")
+//
+func directiveStrings(dirs []*ast.DirectiveLit) (ds []string) {
+	for _, d := range dirs {
+		if types.IsGqlcDirective(d.Name) {
+			continue
+		}
+
+		var b strings.Builder
+		b.WriteByte('@')
+		b.WriteString(d.Name)
+
+		if d.Args != nil {
+			b.WriteByte('(')
+			for j, a := range d.Args.Args {
+				if j > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(a.Name.Name)
+				b.WriteString(": ")
+
+				switch v := a.Value.(type) {
+				case *ast.Arg_BasicLit:
+					writeVal(&b, v.BasicLit)
+				case *ast.Arg_CompositeLit:
+					writeVal(&b, v.CompositeLit)
+				}
+			}
+			b.WriteByte(')')
+		}
+
+		ds = append(ds, b.String())
+	}
+	return
+}
+
+// typeString returns the GraphQL syntax of a type e.g. [String!]!
+func typeString(typ interface{}) string {
+	var b strings.Builder
+	writeType(&b, typ)
+	return b.String()
+}
+
+func writeType(b *strings.Builder, typ interface{}) {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		b.WriteString(v.Name)
+	case *ast.List:
+		b.WriteByte('[')
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			writeType(b, w.Ident)
+		case *ast.List_List:
+			writeType(b, w.List)
+		case *ast.List_NonNull:
+			writeType(b, w.NonNull)
+		}
+		b.WriteByte(']')
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			writeType(b, w.Ident)
+		case *ast.NonNull_List:
+			writeType(b, w.List)
+		}
+		b.WriteByte('!')
+	}
+}
+
+// valueString returns the GraphQL syntax of a value.
+func valueString(val interface{}) string {
+	var b strings.Builder
+	writeVal(&b, val)
+	return b.String()
+}
+
+func writeVal(b *strings.Builder, val interface{}) {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		b.WriteString(v.Value)
+	case *ast.ListLit:
+		b.WriteByte('[')
+
+		var vals []interface{}
+		switch w := v.List.(type) {
+		case *ast.ListLit_BasicList:
+			for _, bval := range w.BasicList.Values {
+				vals = append(vals, bval)
+			}
+		case *ast.ListLit_CompositeList:
+			for _, cval := range w.CompositeList.Values {
+				vals = append(vals, cval)
+			}
+		}
+
+		for i, iv := range vals {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeVal(b, iv)
+		}
+
+		b.WriteByte(']')
+	case *ast.ObjLit:
+		b.WriteString("{ ")
+		for i, p := range v.Fields {
+			b.WriteString(p.Key.Name)
+			b.WriteString(": ")
+			writeVal(b, p.Val)
+
+			if i != len(v.Fields)-1 {
+				b.WriteByte(',')
+			}
+			b.WriteByte(' ')
+		}
+		b.WriteByte('}')
+	case *ast.CompositeLit:
+		switch w := v.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			writeVal(b, w.BasicLit)
+		case *ast.CompositeLit_ListLit:
+			writeVal(b, w.ListLit)
+		case *ast.CompositeLit_ObjLit:
+			writeVal(b, w.ObjLit)
+		}
+	}
+}
+
+// sectionKinds maps the names sections can be given by to their kind, e.g.
+// objects, or object, to object.
+//
+var sectionKinds = map[string]string{
+	operations:   operations,
+	deprecations: deprecations,
+	experimental: experimental,
+}
+
+func init() {
+	for kind, name := range sectionNames {
+		sectionKinds[kind] = kind
+		sectionKinds[strings.ToLower(name)+"s"] = kind
+	}
+}
+
+// sectionList lists the names of the sections, for errors.
+const sectionList = "schema, scalars, objects, interfaces, unions, enums, inputs, directives, operations, deprecations or experimental"
+
+// checkSections checks that the Sections option only names sections.
+func checkSections(names []string) error {
+	for _, name := range names {
+		if _, ok := sectionKinds[strings.ToLower(name)]; !ok {
+			return fmt.Errorf("unknown section: %s, expected any of: %s", name, sectionList)
+		}
+	}
+	return nil
+}
+
+// showsSection reports whether sections of a kind are documented, which
+// they all are unless the Sections option is set.
+//
+func showsSection(opts *Options, kind string) bool {
+	if opts == nil || len(opts.Sections) == 0 {
+		return true
+	}
+
+	for _, name := range opts.Sections {
+		if sectionKinds[strings.ToLower(name)] == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// orderSections returns the sections named, in the order they're named.
+func orderSections(sections []*Section, names []string) []*Section {
+	byKind := make(map[string]*Section, len(sections))
+	for _, s := range sections {
+		byKind[s.Kind] = s
+	}
+
+	ordered := make([]*Section, 0, len(sections))
+	for _, name := range names {
+		s, ok := byKind[sectionKinds[strings.ToLower(name)]]
+		if !ok {
+			continue
+		}
+
+		ordered = append(ordered, s)
+		delete(byKind, s.Kind)
+	}
+	return ordered
+}
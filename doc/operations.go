@@ -0,0 +1,505 @@
+// operations.go contains a parser for executable documents, whose named
+// operations are documented alongside the schema.
+
+package doc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gqlc/gqlc/internal/oplex"
+	"github.com/gqlc/graphql/ast"
+)
+
+// Operation documents a named query, mutation or subscription.
+type Operation struct {
+	Name string `json:"name"`
+
+	// Kind is one of: query, mutation or subscription.
+	Kind string `json:"kind"`
+
+	// Description is taken from the comment lines directly above the operation.
+	Description string `json:"description,omitempty"`
+
+	// Variables are named without their leading $.
+	Variables []*Field `json:"variables,omitempty"`
+
+	Selections []*Selection `json:"selections,omitempty"`
+}
+
+// Selection documents a selected field or an inline fragment. Fragment
+// spreads are replaced by the selections of their fragment.
+//
+type Selection struct {
+	Name  string `json:"name"`
+	Alias string `json:"alias,omitempty"`
+
+	// Type is the schema type of the field, which is empty if it isn't known.
+	Type string `json:"type,omitempty"`
+
+	// On is the type condition of an inline fragment, in which case Name is empty.
+	On string `json:"on,omitempty"`
+
+	Selections []*Selection `json:"selections,omitempty"`
+}
+
+// OperationSource is an executable document to be documented.
+type OperationSource struct {
+	Name string
+	Src  string
+}
+
+// ParseOperations parses the named operations of executable documents
+// and resolves the types of their selections against the schema doc.
+// Fragments may be spread across any of the given sources. Anonymous
+// operations aren't documented.
+//
+func ParseOperations(doc *ast.Document, srcs ...OperationSource) ([]*Operation, error) {
+	frags := make(map[string]*opFragment)
+
+	var ops []*Operation
+	for _, src := range srcs {
+		toks, err := oplex.Lex(src.Src)
+		if err != nil {
+			return nil, opErrorf(src, err)
+		}
+
+		p := &opParser{src: src.Src, toks: toks}
+		fops, ffrags, err := p.parse()
+		if err != nil {
+			return nil, opErrorf(src, err)
+		}
+		ops = append(ops, fops...)
+
+		for _, f := range ffrags {
+			if _, ok := frags[f.name]; ok {
+				return nil, fmt.Errorf("%s: fragment %s is defined more than once", src.Name, f.name)
+			}
+			frags[f.name] = f
+		}
+	}
+
+	r := &opResolver{
+		fields: schemaFields(doc),
+		roots:  rootTypes(doc),
+		frags:  frags,
+		seen:   make(map[string]bool),
+	}
+	for _, op := range ops {
+		sels, err := r.resolve(r.roots[op.Kind], op.Selections)
+		if err != nil {
+			return nil, fmt.Errorf("operation %s: %s", op.Name, err)
+		}
+		op.Selections = sels
+	}
+	return ops, nil
+}
+
+func opErrorf(src OperationSource, err error) error {
+	oerr, ok := err.(oplex.Error)
+	if !ok {
+		return err
+	}
+
+	line := 1 + strings.Count(src.Src[:oerr.Off], "\n")
+	col := oerr.Off - strings.LastIndexByte(src.Src[:oerr.Off], '\n')
+	return fmt.Errorf("%s:%d:%d: %s", src.Name, line, col, oerr.Msg)
+}
+
+// schemaFields maps the objects and interfaces of doc to their field types.
+func schemaFields(doc *ast.Document) map[string]map[string]string {
+	fields := make(map[string]map[string]string)
+	for _, decl := range doc.Types {
+		d, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || d.TypeSpec.Name == nil {
+			continue
+		}
+
+		var list *ast.FieldList
+		switch v := d.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			list = v.Object.Fields
+		case *ast.TypeSpec_Interface:
+			list = v.Interface.Fields
+		}
+
+		types := make(map[string]string)
+		if list != nil {
+			for _, f := range buildFields(list.List) {
+				types[f.Name] = f.Type
+			}
+		}
+		fields[d.TypeSpec.Name.Name] = types
+	}
+	return fields
+}
+
+// rootTypes returns the root type of each operation kind, defaulting to
+// Query, Mutation and Subscription when doc has no schema declaration.
+//
+func rootTypes(doc *ast.Document) map[string]string {
+	for _, decl := range doc.Types {
+		d, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+
+		s, ok := d.TypeSpec.Type.(*ast.TypeSpec_Schema)
+		if !ok || s.Schema.RootOps == nil {
+			continue
+		}
+
+		roots := make(map[string]string)
+		for _, f := range buildFields(s.Schema.RootOps.List) {
+			roots[f.Name] = namedType(f.Type)
+		}
+		return roots
+	}
+
+	return map[string]string{
+		"query":        "Query",
+		"mutation":     "Mutation",
+		"subscription": "Subscription",
+	}
+}
+
+// namedType strips the list and non-null wrappers of a type.
+func namedType(typ string) string {
+	return strings.Trim(typ, "[]!")
+}
+
+type opFragment struct {
+	name string
+	on   string
+	sels []*Selection
+}
+
+// opResolver resolves selection types and expands fragment spreads.
+type opResolver struct {
+	fields map[string]map[string]string
+	roots  map[string]string
+	frags  map[string]*opFragment
+
+	// seen are the fragments being expanded, to catch cycles
+	seen map[string]bool
+}
+
+func (r *opResolver) resolve(parent string, sels []*Selection) ([]*Selection, error) {
+	var out []*Selection
+	for _, sel := range sels {
+		switch {
+		case strings.HasPrefix(sel.Name, "..."):
+			name := sel.Name[3:]
+			frag, ok := r.frags[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown fragment: %s", name)
+			}
+			if r.seen[name] {
+				return nil, fmt.Errorf("fragment %s spreads itself", name)
+			}
+
+			r.seen[name] = true
+			fsels, err := r.resolve(frag.on, frag.sels)
+			delete(r.seen, name)
+			if err != nil {
+				return nil, err
+			}
+
+			// Only spreads of another type need their type condition
+			if frag.on == parent {
+				out = append(out, fsels...)
+				continue
+			}
+			out = append(out, &Selection{On: frag.on, Selections: fsels})
+		case sel.On != "" || sel.Name == "":
+			on := sel.On
+			if on == "" {
+				on = parent
+			}
+
+			isels, err := r.resolve(on, sel.Selections)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, &Selection{On: sel.On, Selections: isels})
+		default:
+			s := &Selection{Name: sel.Name, Alias: sel.Alias, Type: r.fields[parent][sel.Name]}
+			if sel.Name == "__typename" {
+				s.Type = "String!"
+			}
+
+			if len(sel.Selections) > 0 {
+				ssels, err := r.resolve(namedType(s.Type), sel.Selections)
+				if err != nil {
+					return nil, err
+				}
+				s.Selections = ssels
+			}
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// opParser parses the operations and fragments of an executable document.
+// Selections are left unresolved, with fragment spreads named ...Fragment.
+//
+type opParser struct {
+	src  string
+	toks []oplex.Token
+	i    int
+
+	ops   []*Operation
+	frags []*opFragment
+}
+
+func (p *opParser) parse() (ops []*Operation, frags []*opFragment, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			oerr, ok := r.(oplex.Error)
+			if !ok {
+				panic(r)
+			}
+			err = oerr
+		}
+	}()
+
+	for p.peek().Kind != oplex.EOF {
+		p.definition()
+	}
+	return p.ops, p.frags, nil
+}
+
+func (p *opParser) errorf(format string, args ...interface{}) {
+	panic(oplex.Error{Off: p.peek().Off, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (p *opParser) peek() oplex.Token { return p.toks[p.i] }
+
+func (p *opParser) next() oplex.Token {
+	tok := p.toks[p.i]
+	if tok.Kind != oplex.EOF {
+		p.i++
+	}
+	return tok
+}
+
+func (p *opParser) is(val string) bool {
+	tok := p.peek()
+	return tok.Kind == oplex.Punct && tok.Val == val
+}
+
+func (p *opParser) expect(val string) {
+	if !p.is(val) {
+		p.errorf("expected %q but found %q", val, p.peek().Val)
+	}
+	p.next()
+}
+
+func (p *opParser) name() string {
+	if p.peek().Kind != oplex.Name {
+		p.errorf("expected name but found %q", p.peek().Val)
+	}
+	return p.next().Val
+}
+
+func (p *opParser) definition() {
+	if p.is("{") {
+		p.selectionSet()
+		return
+	}
+
+	tok := p.peek()
+	switch kw := p.name(); kw {
+	case "query", "mutation", "subscription":
+		op := &Operation{Kind: kw, Description: leadingComment(p.src, tok.Off)}
+		if p.peek().Kind == oplex.Name {
+			op.Name = p.name()
+		}
+		if p.is("(") {
+			op.Variables = p.variableDefinitions()
+		}
+		p.directives()
+		op.Selections = p.selectionSet()
+
+		if op.Name != "" {
+			p.ops = append(p.ops, op)
+		}
+	case "fragment":
+		f := &opFragment{name: p.name()}
+		if p.name() != "on" {
+			p.i--
+			p.errorf("expected type condition for fragment %s", f.name)
+		}
+		f.on = p.name()
+		p.directives()
+		f.sels = p.selectionSet()
+		p.frags = append(p.frags, f)
+	default:
+		p.i--
+		p.errorf("unexpected %q, only operations and fragments are allowed", kw)
+	}
+}
+
+func (p *opParser) variableDefinitions() (vars []*Field) {
+	p.expect("(")
+	for !p.is(")") {
+		p.expect("$")
+		v := &Field{Name: p.name()}
+		p.expect(":")
+		v.Type = p.typ()
+		if p.is("=") {
+			p.next()
+			v.Default = p.value()
+		}
+		v.Directives = p.directives()
+		vars = append(vars, v)
+	}
+	p.next()
+	return
+}
+
+func (p *opParser) typ() string {
+	if p.is("[") {
+		p.next()
+		typ := "[" + p.typ()
+		p.expect("]")
+		return p.nonNull(typ + "]")
+	}
+	return p.nonNull(p.name())
+}
+
+func (p *opParser) nonNull(typ string) string {
+	if p.is("!") {
+		p.next()
+		return typ + "!"
+	}
+	return typ
+}
+
+func (p *opParser) directives() (ds []string) {
+	for p.is("@") {
+		start := p.next().Off
+		p.name()
+		if p.is("(") {
+			p.arguments()
+		}
+		ds = append(ds, p.text(start))
+	}
+	return
+}
+
+func (p *opParser) arguments() {
+	p.expect("(")
+	for !p.is(")") {
+		p.name()
+		p.expect(":")
+		p.value()
+	}
+	p.next()
+}
+
+// value skips over a value and returns its source text.
+func (p *opParser) value() string {
+	start := p.peek().Off
+
+	tok := p.next()
+	switch {
+	case tok.Kind == oplex.Value, tok.Kind == oplex.Name:
+	case tok.Val == "$":
+		p.name()
+	case tok.Val == "[":
+		for !p.is("]") {
+			p.value()
+		}
+		p.next()
+	case tok.Val == "{":
+		for !p.is("}") {
+			p.name()
+			p.expect(":")
+			p.value()
+		}
+		p.next()
+	default:
+		p.i--
+		p.errorf("expected value but found %q", tok.Val)
+	}
+	return p.text(start)
+}
+
+// text returns the source from start to the end of the last token read.
+func (p *opParser) text(start int) string {
+	last := p.toks[p.i-1]
+	return p.src[start : last.Off+len(last.Val)]
+}
+
+func (p *opParser) selectionSet() (sels []*Selection) {
+	p.expect("{")
+	for !p.is("}") {
+		if p.peek().Kind == oplex.EOF {
+			p.errorf("unterminated selection set")
+		}
+		sels = append(sels, p.selection())
+	}
+	p.next()
+	return
+}
+
+func (p *opParser) selection() *Selection {
+	if p.is("...") {
+		p.next()
+
+		switch {
+		case p.peek().Kind == oplex.Name && p.peek().Val == "on":
+			p.next()
+			sel := &Selection{On: p.name()}
+			p.directives()
+			sel.Selections = p.selectionSet()
+			return sel
+		case p.peek().Kind == oplex.Name:
+			sel := &Selection{Name: "..." + p.name()}
+			p.directives()
+			return sel
+		default:
+			p.directives()
+			return &Selection{Selections: p.selectionSet()}
+		}
+	}
+
+	sel := &Selection{Name: p.name()}
+	if p.is(":") {
+		p.next()
+		sel.Alias, sel.Name = sel.Name, p.name()
+	}
+
+	if p.is("(") {
+		p.arguments()
+	}
+	p.directives()
+	if p.is("{") {
+		sel.Selections = p.selectionSet()
+	}
+	return sel
+}
+
+// leadingComment returns the text of the comment lines directly above off.
+func leadingComment(src string, off int) string {
+	var lines []string
+
+	end := strings.LastIndexByte(src[:off], '\n')
+	for end >= 0 {
+		start := strings.LastIndexByte(src[:end], '\n') + 1
+
+		line := strings.TrimSpace(src[start:end])
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		lines = append(lines, strings.TrimSpace(line[1:]))
+		end = start - 1
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
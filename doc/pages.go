@@ -0,0 +1,195 @@
+// pages.go splits the documentation into pages, linked together by an index
+
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+)
+
+// The ways documentation can be split into pages.
+const (
+	pagesKind = "kind"
+	pagesType = "type"
+)
+
+func checkPages(pages string) error {
+	switch pages {
+	case pagesKind, pagesType:
+		return nil
+	}
+	return fmt.Errorf("unknown pages option: %s, expected kind or type", pages)
+}
+
+// page is a single page of documentation, which is either a whole
+// section, a single type of a section, the operations, deprecations or
+// everything experimental.
+//
+type page struct {
+	name string
+
+	section *Section
+	typ     *Type
+	ops     []*Operation
+	deps    []*Deprecation
+	exps    []*Experimental
+}
+
+// splitPages splits a model into pages, one for each kind of type or, with
+// perType set, for each type. The schema, directives, operations,
+// deprecations and everything experimental always get their own pages.
+//
+func splitPages(m *Model, perType bool) (pages []*page) {
+	for _, s := range m.Sections {
+		if len(s.Types) == 0 {
+			continue
+		}
+
+		name := strings.ToLower(sectionNames[s.Kind])
+		if s.Kind != schema {
+			name += "s"
+		}
+		if !perType || s.Kind == schema || s.Kind == directive {
+			pages = append(pages, &page{name: name, section: s})
+			continue
+		}
+
+		for _, typ := range s.Types {
+			pages = append(pages, &page{name: typ.Name, section: s, typ: typ})
+		}
+	}
+
+	if len(m.Operations) > 0 {
+		pages = append(pages, &page{name: operations, ops: m.Operations})
+	}
+	if len(m.Deprecations) > 0 {
+		pages = append(pages, &page{name: deprecations, deps: m.Deprecations})
+	}
+	if len(m.Experimental) > 0 {
+		pages = append(pages, &page{name: experimental, exps: m.Experimental})
+	}
+	return
+}
+
+// newPageLinker returns a linker which links across pages.
+func newPageLinker(m *Model, auto bool, pages []*page) *linker {
+	l := newLinker(m, auto)
+	l.pages = make(map[string]string)
+	l.sections = make(map[string]string)
+	for _, p := range pages {
+		switch {
+		case p.ops != nil:
+			l.sections[operations] = p.name
+		case p.deps != nil:
+			l.sections[deprecations] = p.name
+		case p.exps != nil:
+			l.sections[experimental] = p.name
+		case p.typ != nil:
+			l.pages[p.typ.Name] = p.name
+		default:
+			l.sections[p.section.Kind] = p.name
+			for _, typ := range p.section.Types {
+				l.pages[typ.Name] = p.name
+			}
+		}
+	}
+	return l
+}
+
+// writePages writes the documentation as an index page, holding the table
+// of contents, and a page for each kind of type or type, as .md files and
+// .html files if it's enabled.
+//
+func (g *Generator) writePages(gCtx gen.GeneratorContext, dir string, m *Model, gOpts *Options) error {
+	pages := splitPages(m, gOpts.Pages == pagesType)
+	g.links = newPageLinker(m, g.crossLinks, pages)
+	g.links.docs = g.docs
+
+	exts := []string{".md"}
+	if gOpts.HTML {
+		exts = append(exts, ".html")
+	}
+
+	for _, ext := range exts {
+		g.links.ext = ext
+
+		// The index is only the table of contents
+		var index bytes.Buffer
+		g.Reset()
+		g.links.page = "index"
+		err := g.writeMarkdown(&index, m)
+		if err != nil {
+			return err
+		}
+		err = g.writePage(gCtx, dir+"/index"+ext, g.front, m.Title, index.Bytes())
+		if err != nil {
+			return err
+		}
+
+		for i, p := range pages {
+			g.Reset()
+			g.links.page = p.name
+			g.generatePage(p)
+			if g.err != nil {
+				return g.err
+			}
+
+			err = g.writePage(gCtx, dir+"/"+p.name+ext, g.front.page(p.name, i+1), pageTitle(p), g.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+
+		if !gOpts.AlphaIndex {
+			continue
+		}
+		g.Reset()
+		g.links.page = alphabetical
+		g.generateIndex(m)
+		err = g.writePage(gCtx, dir+"/"+alphabetical+ext, g.front.page(alphabetical, len(pages)+1), alphabeticalTitle, g.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generatePage generates the documentation on a page.
+func (g *Generator) generatePage(p *page) {
+	switch {
+	case p.ops != nil:
+		g.generateOperations(p.ops)
+	case p.deps != nil:
+		g.generateDeprecations(p.deps)
+	case p.exps != nil:
+		g.generateExperimental(p.exps)
+	case p.typ != nil:
+		g.execute("type", typeData{Kind: p.section.Kind, Type: p.typ}, func() { g.generateType(p.section.Kind, p.typ) })
+	default:
+		g.execute("section", p.section, func() { g.generateSection(p.section) })
+	}
+}
+
+// writePage writes a page of Markdown to a file, after its frontmatter, or
+// converted to HTML if the file is a .html file.
+//
+func (g *Generator) writePage(gCtx gen.GeneratorContext, name string, front *frontmatter, title string, md []byte) error {
+	f, err := gCtx.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if !strings.HasSuffix(name, ".html") {
+		err = front.write(f, title)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(md)
+		return err
+	}
+	return g.writeHTML(f, name, md)
+}
@@ -0,0 +1,298 @@
+// sanitize.go renders the raw HTML found in descriptions, according to the
+// policy chosen for it, when the documentation is converted to HTML
+
+package doc
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	mdast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// The policies for raw HTML in descriptions.
+const (
+	// rawOmit leaves raw HTML out of the page, with a comment in its place.
+	rawOmit = "omit"
+
+	// rawEscape writes raw HTML as text, so it's shown as it was written.
+	rawEscape = "escape"
+
+	// rawSanitize keeps the tags, and attributes, of raw HTML which only
+	// format text, and leaves out the rest, e.g. scripts and handlers.
+	//
+	rawSanitize = "sanitize"
+
+	// rawAllow writes raw HTML as it is, for descriptions which are trusted.
+	rawAllow = "allow"
+)
+
+// rawPolicies are every policy for raw HTML.
+var rawPolicies = []string{rawOmit, rawEscape, rawSanitize, rawAllow}
+
+// checkRawHTML checks the policy for raw HTML.
+func checkRawHTML(policy string) error {
+	for _, p := range rawPolicies {
+		if p == policy {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown raw HTML policy: %s, expected one of: %s", policy, strings.Join(rawPolicies, ", "))
+}
+
+// safeAttrs are the tags kept by sanitizing, along with their attributes
+// which are kept. Every tag keeps its title.
+//
+var safeAttrs = map[string][]string{
+	"a":          {"href"},
+	"abbr":       nil,
+	"b":          nil,
+	"blockquote": nil,
+	"br":         nil,
+	"code":       nil,
+	"dd":         nil,
+	"del":        nil,
+	"details":    {"open"},
+	"dl":         nil,
+	"dt":         nil,
+	"em":         nil,
+	"hr":         nil,
+	"i":          nil,
+	"img":        {"src", "alt", "width", "height"},
+	"ins":        nil,
+	"kbd":        nil,
+	"li":         nil,
+	"mark":       nil,
+	"ol":         {"start"},
+	"p":          nil,
+	"pre":        nil,
+	"q":          nil,
+	"s":          nil,
+	"small":      nil,
+	"span":       nil,
+	"strong":     nil,
+	"sub":        nil,
+	"summary":    nil,
+	"sup":        nil,
+	"table":      nil,
+	"tbody":      nil,
+	"td":         {"colspan", "rowspan", "align"},
+	"tfoot":      nil,
+	"th":         {"colspan", "rowspan", "align"},
+	"thead":      nil,
+	"tr":         nil,
+	"u":          nil,
+	"ul":         nil,
+}
+
+// unsafeContent are the tags whose content is left out, along with them,
+// by sanitizing, since it isn't text.
+//
+var unsafeContent = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"iframe":   true,
+	"object":   true,
+	"template": true,
+	"textarea": true,
+}
+
+// rawRenderer renders raw HTML, both inline and in blocks, by a policy.
+// The line breaks the generator writes into table cells are always kept.
+//
+type rawRenderer struct {
+	policy string
+}
+
+func (r rawRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(mdast.KindRawHTML, r.renderRawHTML)
+	reg.Register(mdast.KindHTMLBlock, r.renderHTMLBlock)
+}
+
+func (r rawRenderer) renderRawHTML(w util.BufWriter, source []byte, node mdast.Node, entering bool) (mdast.WalkStatus, error) {
+	if !entering {
+		return mdast.WalkSkipChildren, nil
+	}
+
+	n := node.(*mdast.RawHTML)
+	var raw strings.Builder
+	for i := 0; i < n.Segments.Len(); i++ {
+		seg := n.Segments.At(i)
+		raw.Write(seg.Value(source))
+	}
+	w.WriteString(r.render(raw.String(), false))
+	return mdast.WalkSkipChildren, nil
+}
+
+func (r rawRenderer) renderHTMLBlock(w util.BufWriter, source []byte, node mdast.Node, entering bool) (mdast.WalkStatus, error) {
+	if !entering {
+		return mdast.WalkContinue, nil
+	}
+
+	n := node.(*mdast.HTMLBlock)
+	var raw strings.Builder
+	for i := 0; i < n.Lines().Len(); i++ {
+		line := n.Lines().At(i)
+		raw.Write(line.Value(source))
+	}
+	if n.HasClosure() {
+		raw.Write(n.ClosureLine.Value(source))
+	}
+
+	out := r.render(raw.String(), true)
+	w.WriteString(out)
+	if !strings.HasSuffix(out, "\n") {
+		w.WriteByte('\n')
+	}
+	return mdast.WalkContinue, nil
+}
+
+func (r rawRenderer) render(raw string, block bool) string {
+	if isLineBreak(raw) {
+		return raw
+	}
+
+	switch r.policy {
+	case rawEscape:
+		return html.EscapeString(raw)
+	case rawSanitize:
+		return sanitizeHTML(raw)
+	case rawAllow:
+		return raw
+	}
+
+	if block {
+		return "<!-- raw HTML omitted -->\n"
+	}
+	return "<!-- raw HTML omitted -->"
+}
+
+func isLineBreak(raw string) bool {
+	switch strings.ToLower(raw) {
+	case "<br>", "<br/>", "<br />":
+		return true
+	}
+	return false
+}
+
+// sanitizeHTML returns HTML with only its safe tags and attributes, and
+// its text, which is escaped if it could be mistaken for a tag.
+//
+func sanitizeHTML(src string) string {
+	var b strings.Builder
+	for i := 0; i < len(src); {
+		lt := strings.IndexByte(src[i:], '<')
+		if lt < 0 {
+			b.WriteString(src[i:])
+			break
+		}
+		b.WriteString(src[i : i+lt])
+		i += lt
+
+		// Comments, doctypes and the like are left out
+		if strings.HasPrefix(src[i:], "<!") || strings.HasPrefix(src[i:], "<?") {
+			end := strings.IndexByte(src[i:], '>')
+			if strings.HasPrefix(src[i:], "<!--") {
+				end = strings.Index(src[i:], "-->")
+				if end >= 0 {
+					end += 2
+				}
+			}
+			if end < 0 {
+				break
+			}
+			i += end + 1
+			continue
+		}
+
+		e, n := parseTag(src[i:])
+		if e == nil {
+			b.WriteString("&lt;")
+			i++
+			continue
+		}
+		i += n
+
+		if unsafeContent[e.name] && !e.end {
+			end := strings.Index(strings.ToLower(src[i:]), "</"+e.name)
+			if end < 0 {
+				break
+			}
+			i += end
+			continue
+		}
+		writeSafeTag(&b, e)
+	}
+	return b.String()
+}
+
+// writeSafeTag writes a tag, if it's safe, with its safe attributes.
+func writeSafeTag(b *strings.Builder, e *element) {
+	allowed, ok := safeAttrs[e.name]
+	if !ok {
+		return
+	}
+
+	b.WriteByte('<')
+	if e.end {
+		b.WriteByte('/')
+		b.WriteString(e.name)
+		b.WriteByte('>')
+		return
+	}
+	b.WriteString(e.name)
+
+	names := make([]string, 0, len(e.attrs))
+	for name := range e.attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if name != "title" && !contains(allowed, name) {
+			continue
+		}
+
+		val := e.attrs[name]
+		if (name == "href" || name == "src") && !isSafeURL(val) {
+			continue
+		}
+
+		b.WriteByte(' ')
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(html.EscapeString(val))
+		b.WriteByte('"')
+	}
+	b.WriteByte('>')
+}
+
+// isSafeURL reports whether a URL is relative, or uses a scheme which
+// can't run code when followed, unlike javascript: URLs.
+//
+func isSafeURL(u string) bool {
+	u = strings.TrimSpace(u)
+	colon := strings.IndexByte(u, ':')
+	if colon < 0 || strings.ContainsAny(u[:colon], "/?#") {
+		return true
+	}
+
+	switch strings.ToLower(u[:colon]) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+// templates.go contains the templates which override how the documentation
+// is rendered
+
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+)
+
+// templateNames are the templates a templates directory may override, each
+// read from a file of its name with a .tmpl extension.
+//
+var templateNames = []string{"toc", "section", "type", "field"}
+
+// defaultTemplates render the documentation just as it is without templates,
+// by calling back into the generator. The generator executes the templates
+// of whatever it renders in turn, so overriding e.g. the field template
+// changes every field, even those of types rendered by default.
+//
+const defaultTemplates = `{{define "toc"}}{{toc .}}{{end}}` +
+	`{{define "section"}}{{section .}}{{end}}` +
+	`{{define "type"}}{{type .}}{{end}}` +
+	`{{define "field"}}{{field .}}{{end}}`
+
+// typeData is what the type template is executed with.
+type typeData struct {
+	// Kind is the kind of the section the type is in e.g. object
+	Kind string
+
+	*Type
+}
+
+// templates are the templates a Generator renders with.
+type templates struct {
+	*template.Template
+
+	// gens are the generators executing templates, the last of which the
+	// template functions render with.
+	//
+	gens []*Generator
+}
+
+// readTemplates reads the templates found in dir, which is relative to the
+// directory of the schema document, over the default templates.
+//
+func readTemplates(gCtx gen.GeneratorContext, doc *ast.Document, dir string) (*templates, error) {
+	pCtx, ok := gCtx.(gen.PathContext)
+	if !ok {
+		return nil, fmt.Errorf("templates can't be read by this generator context")
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(filepath.Dir(pCtx.Source(doc)), dir)
+	}
+
+	ts := new(templates)
+	ts.Template = template.Must(template.New("doc").Funcs(ts.funcs()).Parse(defaultTemplates))
+
+	var found bool
+	for _, name := range templateNames {
+		b, err := pCtx.ReadFile(filepath.Join(dir, name+".tmpl"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = ts.New(name).Parse(string(b))
+		if err != nil {
+			return nil, err
+		}
+		found = true
+	}
+	if !found {
+		return nil, fmt.Errorf("no templates found in %s, expected any of: toc.tmpl, section.tmpl, type.tmpl or field.tmpl", dir)
+	}
+	return ts, nil
+}
+
+// funcs are the functions templates can call. Besides the default
+// rendering of each template, there's link, which links the types named
+// in a description, and typeRef, which links a type to its documentation.
+//
+func (ts *templates) funcs() template.FuncMap {
+	return template.FuncMap{
+		"toc": func(m *Model) (string, error) {
+			var b bytes.Buffer
+			g := ts.gens[len(ts.gens)-1]
+			_, err := writeToC(&b, m, g.links, g.toc)
+			return b.String(), err
+		},
+		"section": func(s *Section) (string, error) {
+			return ts.render(func(g *Generator) { g.generateSection(s) })
+		},
+		"type": func(t typeData) (string, error) {
+			return ts.render(func(g *Generator) { g.generateType(t.Kind, t.Type) })
+		},
+		"field": func(f *Field) (string, error) {
+			return ts.render(func(g *Generator) { g.generateField(f) })
+		},
+		"link": func(text string) string {
+			g := ts.gens[len(ts.gens)-1]
+			return g.links.link(text, g.self)
+		},
+		"typeRef": func(typ string) (string, error) {
+			return ts.render(func(g *Generator) { g.printType(typ) })
+		},
+	}
+}
+
+// execute executes the named template into g.
+func (ts *templates) execute(g *Generator, name string, data interface{}) {
+	if g.err != nil {
+		return
+	}
+
+	ts.gens = append(ts.gens, g)
+	err := ts.ExecuteTemplate(&g.Buffer, name, data)
+	ts.gens = ts.gens[:len(ts.gens)-1]
+	if err != nil {
+		g.err = err
+	}
+}
+
+// render renders with a copy of the generator executing the current
+// template, so it's rendered at the same indentation, and returns what
+// was rendered.
+//
+func (ts *templates) render(f func(*Generator)) (string, error) {
+	g := ts.gens[len(ts.gens)-1].fork()
+	f(g)
+	return g.String(), g.err
+}
@@ -0,0 +1,116 @@
+package doc
+
+// themes are the built-in styles of standalone HTML pages. Their colors
+// contrast with their backgrounds by at least 4.5:1, as WCAG AA requires.
+//
+var themes = map[string]string{
+	"light":  baseCSS + lightCSS,
+	"dark":   baseCSS + darkCSS,
+	"github": githubCSS,
+}
+
+// a11yCSS is applied to every page, before its theme. It hides the skip
+// link until it's focused, and outlines whatever has focus.
+//
+const a11yCSS = `.gqlc-skip {
+	position: absolute;
+	left: -10000px;
+}
+.gqlc-skip:focus {
+	left: 1rem;
+	top: 1rem;
+	padding: 0.5rem 1rem;
+	background: #ffffff;
+	color: #1f2328;
+	z-index: 1;
+}
+.gqlc-doc a:focus, .gqlc-skip:focus {
+	outline: 2px solid currentColor;
+	outline-offset: 2px;
+}
+`
+
+const baseCSS = `.gqlc-doc {
+	max-width: 52rem;
+	margin: 0 auto;
+	padding: 2rem 1rem;
+	font-family: system-ui, -apple-system, "Segoe UI", Roboto, sans-serif;
+	line-height: 1.6;
+}
+.gqlc-doc pre {
+	padding: 1rem;
+	overflow: auto;
+	border-radius: 4px;
+}
+.gqlc-doc code {
+	font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace;
+	font-size: 0.9em;
+}
+.gqlc-doc table {
+	border-collapse: collapse;
+}
+.gqlc-doc th, .gqlc-doc td {
+	padding: 0.4rem 0.8rem;
+	border: 1px solid;
+}
+`
+
+const lightCSS = `body { background: #ffffff; color: #1f2328; }
+.gqlc-doc a { color: #0b57d0; }
+.gqlc-doc pre { background: #f4f5f7; }
+.gqlc-doc th, .gqlc-doc td { border-color: #d0d7de; }
+.gql-keyword { color: #a626a4; }
+.gql-directive { color: #946400; }
+.gql-variable { color: #bc443a; }
+.gql-string { color: #3b7b3a; }
+.gql-number { color: #0b6e99; }
+.gql-comment { color: #6e6f73; font-style: italic; }
+`
+
+const darkCSS = `body { background: #1e1f22; color: #dcdfe4; }
+.gqlc-doc a { color: #61afef; }
+.gqlc-doc pre { background: #282c34; }
+.gqlc-doc th, .gqlc-doc td { border-color: #3e4451; }
+.gql-keyword { color: #c678dd; }
+.gql-directive { color: #e5c07b; }
+.gql-variable { color: #e2747c; }
+.gql-string { color: #98c379; }
+.gql-number { color: #d19a66; }
+.gql-comment { color: #9397a0; font-style: italic; }
+`
+
+const githubCSS = `body { background: #ffffff; color: #1f2328; }
+.gqlc-doc {
+	max-width: 980px;
+	margin: 0 auto;
+	padding: 45px;
+	font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", "Noto Sans", Helvetica, Arial, sans-serif;
+	font-size: 16px;
+	line-height: 1.5;
+}
+.gqlc-doc h1, .gqlc-doc h2 {
+	padding-bottom: 0.3em;
+	border-bottom: 1px solid #d1d9e0;
+}
+.gqlc-doc a { color: #0969da; text-decoration: none; }
+.gqlc-doc a:hover { text-decoration: underline; }
+.gqlc-doc pre {
+	padding: 16px;
+	overflow: auto;
+	background: #f6f8fa;
+	border-radius: 6px;
+}
+.gqlc-doc code {
+	font-family: ui-monospace, SFMono-Regular, "SF Mono", Menlo, Consolas, monospace;
+	font-size: 85%;
+}
+.gqlc-doc table { border-collapse: collapse; }
+.gqlc-doc th, .gqlc-doc td { padding: 6px 13px; border: 1px solid #d1d9e0; }
+.gqlc-doc tr:nth-child(2n) { background: #f6f8fa; }
+.gql-keyword { color: #cf222e; }
+.gql-directive { color: #8250df; }
+.gql-variable { color: #953800; }
+.gql-string { color: #0a3069; }
+.gql-number { color: #0550ae; }
+.gql-comment { color: #59636e; }
+`
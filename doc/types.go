@@ -57,6 +57,228 @@ var docTypes = []*ast.TypeDecl{
 								Value: "false",
 							}},
 						},
+						{
+							Name: &ast.Ident{Name: "asciidoc"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "json"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "tables"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "operations"},
+							Type: &ast.InputValue_List{List: &ast.List{
+								Type: &ast.List_Ident{
+									Ident: &ast.Ident{Name: "String"},
+								},
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "crossLinks"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "templates"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+						},
+						{
+							Name: &ast.Ident{Name: "multiPage"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "pages"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_STRING,
+								Value: "\"kind\"",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "wiki"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "theme"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+						},
+						{
+							Name: &ast.Ident{Name: "css"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+						},
+						{
+							Name: &ast.Ident{Name: "a11y"},
+							Type: &ast.InputValue_List{List: &ast.List{
+								Type: &ast.List_Ident{
+									Ident: &ast.Ident{Name: "String"},
+								},
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "rawHTML"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_STRING,
+								Value: "\"omit\"",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "deprecations"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "experimental"},
+							Type: &ast.InputValue_List{List: &ast.List{
+								Type: &ast.List_Ident{
+									Ident: &ast.Ident{Name: "String"},
+								},
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "directiveUsage"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "sections"},
+							Type: &ast.InputValue_List{List: &ast.List{
+								Type: &ast.List_Ident{
+									Ident: &ast.Ident{Name: "String"},
+								},
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "tocKinds"},
+							Type: &ast.InputValue_List{List: &ast.List{
+								Type: &ast.List_Ident{
+									Ident: &ast.Ident{Name: "String"},
+								},
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "tocCollapse"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Int"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_INT,
+								Value: "0",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "alphaIndex"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "examples"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "frontmatter"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "slug"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+						},
+						{
+							Name: &ast.Ident{Name: "weight"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Int"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_INT,
+								Value: "0",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "frontmatterKeys"},
+							Type: &ast.InputValue_List{List: &ast.List{
+								Type: &ast.List_Ident{
+									Ident: &ast.Ident{Name: "String"},
+								},
+							}},
+						},
 					},
 				},
 			}},
@@ -0,0 +1,142 @@
+// wiki.go writes the documentation as the pages of a GitHub wiki
+
+package doc
+
+import (
+	"bytes"
+
+	"github.com/gqlc/gqlc/gen"
+)
+
+// The pages GitHub gives a special meaning in a wiki.
+const (
+	wikiHome    = "Home"
+	wikiSidebar = "_Sidebar"
+)
+
+// wikiLink returns a GitHub wiki link to a page, labelled text.
+func wikiLink(text, page string) string {
+	if text == page {
+		return "[[" + page + "]]"
+	}
+	return "[[" + text + "|" + page + "]]"
+}
+
+// wikiPage returns the page documenting name, if the links are wiki links.
+// Types of other documents are assumed to have pages of their own.
+//
+func (l *linker) wikiPage(name string) (string, bool) {
+	if l == nil || !l.wiki {
+		return "", false
+	}
+	if page, ok := l.pages[name]; ok {
+		return page, true
+	}
+	return name, true
+}
+
+// writeWiki writes the documentation as a GitHub wiki: a page per type,
+// along with pages for the schema, directives, operations and
+// deprecations, a Home page holding the table of contents and a _Sidebar
+// listing every page. The pages are named after what they document, which
+// GitHub turns into their titles, and they're written flat into dir, so
+// wiki links between them resolve. Wikis are Markdown only, so neither
+// frontmatter nor HTML is written.
+//
+func (g *Generator) writeWiki(gCtx gen.GeneratorContext, dir string, m *Model, gOpts *Options) error {
+	pages := splitPages(m, true)
+	g.links = newPageLinker(m, g.crossLinks, pages)
+	g.links.docs = g.docs
+	g.links.wiki = true
+
+	var home bytes.Buffer
+	g.Reset()
+	g.links.page = wikiHome
+	err := g.writeMarkdown(&home, m)
+	if err != nil {
+		return err
+	}
+	err = writeWikiPage(gCtx, dir, wikiHome, home.Bytes())
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pages {
+		g.Reset()
+		g.links.page = p.name
+		g.generatePage(p)
+		if g.err != nil {
+			return g.err
+		}
+
+		err = writeWikiPage(gCtx, dir, p.name, g.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	if gOpts.AlphaIndex {
+		g.Reset()
+		g.links.page = alphabetical
+		g.generateIndex(m)
+		err = writeWikiPage(gCtx, dir, alphabetical, g.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	g.Reset()
+	g.links.page = wikiSidebar
+	g.generateSidebar(m, pages, gOpts.AlphaIndex)
+	return writeWikiPage(gCtx, dir, wikiSidebar, g.Bytes())
+}
+
+// generateSidebar generates the sidebar of a wiki, which links to Home and
+// then every page, with the pages of types listed under their kind.
+//
+func (g *Generator) generateSidebar(m *Model, pages []*page, index bool) {
+	g.WriteString("**")
+	g.WriteString(wikiLink(m.Title, wikiHome))
+	g.WriteString("**\n")
+
+	var section *Section
+	for _, p := range pages {
+		if p.typ == nil {
+			section = nil
+			g.WriteString("\n### ")
+			g.WriteString(wikiLink(pageTitle(p), p.name))
+			g.WriteByte('\n')
+			continue
+		}
+
+		if p.section != section {
+			section = p.section
+			g.WriteString("\n### ")
+			g.WriteString(sectionNames[section.Kind])
+			g.WriteString("s\n\n")
+		}
+		g.WriteString("- ")
+		g.WriteString(wikiLink(p.typ.Name, p.name))
+		g.WriteByte('\n')
+	}
+
+	if index {
+		g.WriteString("\n### ")
+		g.WriteString(wikiLink(alphabeticalTitle, alphabetical))
+		g.WriteByte('\n')
+	}
+}
+
+// writeWikiPage writes a page of a wiki to dir, which GitHub names after
+// its file.
+//
+func writeWikiPage(gCtx gen.GeneratorContext, dir, name string, md []byte) error {
+	f, err := gCtx.Open(dir + "/" + name + ".md")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(md)
+	return err
+}
@@ -0,0 +1,605 @@
+// Package federation provides support for Apollo Federation v2 subgraphs.
+// It registers the federation directives, so they can be applied in any
+// schema, validates them during type checking, and lets generators wire
+// up the _entities and _service fields every subgraph must serve.
+//
+package federation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gqlc/compiler"
+	"github.com/gqlc/gqlc/sdl"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+// Names of the federation directives.
+const (
+	Key       = "key"
+	External  = "external"
+	Requires  = "requires"
+	Provides  = "provides"
+	Shareable = "shareable"
+)
+
+// Link is the schema extension which tells the gateway a subgraph uses
+// Federation v2, rather than v1.
+//
+const Link = `extend schema @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key", "@external", "@requires", "@provides", "@shareable"])`
+
+// Validator checks the field sets given to @key, @requires and @provides
+// select fields which exist, and fields required by @requires are @external.
+//
+var Validator = compiler.TypeCheckerFn(validate)
+
+// Selection is a field of a field set, along with the fields selected from it.
+type Selection struct {
+	Name   string
+	Fields []Selection
+}
+
+// ParseFieldSet parses a field set, as given to @key, @requires and
+// @provides, e.g. "id organization { id }".
+//
+func ParseFieldSet(s string) ([]Selection, error) {
+	sels, rest, err := parseSelections(s, 0)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(rest) != "" {
+		return nil, fmt.Errorf("unexpected %q in field set", rest[0])
+	}
+	if len(sels) == 0 {
+		return nil, fmt.Errorf("field set must select one or more fields")
+	}
+	return sels, nil
+}
+
+func parseSelections(s string, depth int) (sels []Selection, rest string, err error) {
+	for {
+		s = strings.TrimLeft(s, " \t\r\n,")
+		if s == "" {
+			if depth > 0 {
+				return nil, "", fmt.Errorf("field set is missing a closing '}'")
+			}
+			return sels, "", nil
+		}
+
+		switch c := s[0]; {
+		case c == '}':
+			if depth == 0 {
+				return nil, s, nil
+			}
+			return sels, s[1:], nil
+		case c == '{':
+			if len(sels) == 0 {
+				return nil, "", fmt.Errorf("field set selects from nothing")
+			}
+			if len(sels[len(sels)-1].Fields) > 0 {
+				return nil, "", fmt.Errorf("field set selects from %s more than once", sels[len(sels)-1].Name)
+			}
+
+			sub, r, serr := parseSelections(s[1:], depth+1)
+			if serr != nil {
+				return nil, "", serr
+			}
+			if len(sub) == 0 {
+				return nil, "", fmt.Errorf("field set must select one or more fields of %s", sels[len(sels)-1].Name)
+			}
+			sels[len(sels)-1].Fields = sub
+			s = r
+		case isNameStart(c):
+			i := 1
+			for i < len(s) && (isNameStart(s[i]) || s[i] >= '0' && s[i] <= '9') {
+				i++
+			}
+			sels = append(sels, Selection{Name: s[:i]})
+			s = s[i:]
+		default:
+			return nil, "", fmt.Errorf("unexpected %q in field set", c)
+		}
+	}
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// fieldSet returns the field set given to a directive.
+func fieldSet(d *ast.DirectiveLit) (string, bool) {
+	if d.Args == nil {
+		return "", false
+	}
+
+	for _, a := range d.Args.Args {
+		if a.Name.Name != "fields" {
+			continue
+		}
+
+		lit, ok := a.Value.(*ast.Arg_BasicLit)
+		if !ok {
+			return "", false
+		}
+		return strings.Trim(lit.BasicLit.Value, `"`), true
+	}
+	return "", false
+}
+
+func hasDirective(dirs []*ast.DirectiveLit, name string) bool {
+	for _, d := range dirs {
+		if d.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// composite tracks the fields of an object or interface type, across its
+// definition and extensions.
+//
+type composite struct {
+	order    []*ast.Field
+	fields   map[string]*ast.Field
+	external map[string]bool
+}
+
+func lookupComposite(ir compiler.IR, types map[string][]*ast.TypeDecl, name string) (*composite, bool) {
+	decls, ok := types[name]
+	if !ok {
+		_, decls = compiler.Lookup(name, ir)
+	}
+
+	var c *composite
+	for _, decl := range decls {
+		var ts *ast.TypeSpec
+		switch v := decl.Spec.(type) {
+		case *ast.TypeDecl_TypeSpec:
+			ts = v.TypeSpec
+		case *ast.TypeDecl_TypeExtSpec:
+			ts = v.TypeExtSpec.Type
+		}
+
+		var fields *ast.FieldList
+		switch v := ts.Type.(type) {
+		case *ast.TypeSpec_Object:
+			fields = v.Object.Fields
+		case *ast.TypeSpec_Interface:
+			fields = v.Interface.Fields
+		default:
+			continue
+		}
+
+		if c == nil {
+			c = &composite{fields: make(map[string]*ast.Field), external: make(map[string]bool)}
+		}
+		if fields == nil {
+			continue
+		}
+
+		external := hasDirective(ts.Directives, External)
+		for _, f := range fields.List {
+			c.order = append(c.order, f)
+			c.fields[f.Name.Name] = f
+			c.external[f.Name.Name] = external || hasDirective(f.Directives, External)
+		}
+	}
+	return c, c != nil
+}
+
+func validate(ir compiler.IR) (errs []error) {
+	for _, types := range ir {
+		for name, decls := range types {
+			c, ok := lookupComposite(ir, types, name)
+			if !ok {
+				continue
+			}
+
+			for _, decl := range decls {
+				var ts *ast.TypeSpec
+				switch v := decl.Spec.(type) {
+				case *ast.TypeDecl_TypeSpec:
+					ts = v.TypeSpec
+				case *ast.TypeDecl_TypeExtSpec:
+					ts = v.TypeExtSpec.Type
+				}
+
+				for _, d := range ts.Directives {
+					if d.Name != Key {
+						continue
+					}
+
+					err := checkFieldSet(ir, types, d, name, c, true)
+					if err != nil {
+						errs = append(errs, fmt.Errorf("%s: @%s: %w", name, Key, err))
+					}
+				}
+			}
+
+			for _, f := range c.order {
+				for _, d := range f.Directives {
+					var err error
+					switch d.Name {
+					case Requires:
+						err = checkRequires(ir, types, d, name, c)
+					case Provides:
+						ret := baseType(f)
+						fc, ok := lookupComposite(ir, types, ret)
+						if !ok {
+							err = fmt.Errorf("%s isn't an object or interface type", ret)
+							break
+						}
+						err = checkFieldSet(ir, types, d, ret, fc, false)
+					default:
+						continue
+					}
+
+					if err != nil {
+						errs = append(errs, fmt.Errorf("%s:%s: @%s: %w", name, f.Name.Name, d.Name, err))
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+func checkRequires(ir compiler.IR, types map[string][]*ast.TypeDecl, d *ast.DirectiveLit, name string, c *composite) error {
+	err := checkFieldSet(ir, types, d, name, c, false)
+	if err != nil {
+		return err
+	}
+
+	s, _ := fieldSet(d)
+	sels, _ := ParseFieldSet(s)
+	for _, sel := range sels {
+		if !c.external[sel.Name] {
+			return fmt.Errorf("required field must be @%s: %s", External, sel.Name)
+		}
+	}
+	return nil
+}
+
+func checkFieldSet(ir compiler.IR, types map[string][]*ast.TypeDecl, d *ast.DirectiveLit, name string, c *composite, key bool) error {
+	s, ok := fieldSet(d)
+	if !ok {
+		return nil
+	}
+
+	sels, err := ParseFieldSet(s)
+	if err != nil {
+		return err
+	}
+	return checkSelections(ir, types, sels, name, c, key)
+}
+
+func checkSelections(ir compiler.IR, types map[string][]*ast.TypeDecl, sels []Selection, name string, c *composite, key bool) error {
+	for _, sel := range sels {
+		f, ok := c.fields[sel.Name]
+		if !ok {
+			return fmt.Errorf("unknown field of %s: %s", name, sel.Name)
+		}
+		if key && f.Args != nil && len(f.Args.List) > 0 {
+			return fmt.Errorf("key field can't have arguments: %s", sel.Name)
+		}
+
+		typ := baseType(f)
+		fc, isComposite := lookupComposite(ir, types, typ)
+		switch {
+		case isComposite && len(sel.Fields) == 0:
+			return fmt.Errorf("field of type %s must select its fields: %s", typ, sel.Name)
+		case !isComposite && len(sel.Fields) > 0:
+			return fmt.Errorf("field of type %s can't select fields: %s", typ, sel.Name)
+		case isComposite:
+			if err := checkSelections(ir, types, sel.Fields, typ, fc, key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func baseType(f *ast.Field) string {
+	var typ interface{}
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident.Name
+	case *ast.Field_List:
+		typ = v.List
+	case *ast.Field_NonNull:
+		typ = v.NonNull
+	}
+
+	for {
+		switch v := typ.(type) {
+		case *ast.List:
+			switch w := v.Type.(type) {
+			case *ast.List_Ident:
+				return w.Ident.Name
+			case *ast.List_List:
+				typ = w.List
+			case *ast.List_NonNull:
+				typ = w.NonNull
+			}
+		case *ast.NonNull:
+			switch w := v.Type.(type) {
+			case *ast.NonNull_Ident:
+				return w.Ident.Name
+			case *ast.NonNull_List:
+				typ = w.List
+			}
+		default:
+			return ""
+		}
+	}
+}
+
+// Entity is an object, or interface, which other subgraphs can reference
+// by its key.
+//
+type Entity struct {
+	Name string
+
+	// Key is the field set of the @key directive.
+	Key string
+
+	// Resolvable is false if the subgraph can only reference the entity.
+	Resolvable bool
+
+	// Interface is true for entity interfaces.
+	Interface bool
+}
+
+// Entities returns the entities of a document, in the order they're declared.
+func Entities(doc *ast.Document) (entities []Entity) {
+	for _, decl := range doc.Types {
+		ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+
+		var inter bool
+		switch ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+		case *ast.TypeSpec_Interface:
+			inter = true
+		default:
+			continue
+		}
+
+		for _, d := range ts.TypeSpec.Directives {
+			if d.Name != Key {
+				continue
+			}
+
+			e := Entity{Name: ts.TypeSpec.Name.Name, Resolvable: true, Interface: inter}
+			e.Key, _ = fieldSet(d)
+			for _, a := range d.Args.Args {
+				if lit, ok := a.Value.(*ast.Arg_BasicLit); ok && a.Name.Name == "resolvable" {
+					e.Resolvable = lit.BasicLit.Value != "false"
+				}
+			}
+			entities = append(entities, e)
+		}
+	}
+	return
+}
+
+// Resolvers are given to the fields added by Subgraph with the gqlc
+// @resolver directive, so generators can implement them in their own
+// language. Fields with an empty resolver are left as is.
+//
+type Resolvers struct {
+	// Entities resolves Query._entities
+	Entities string
+
+	// Service resolves Query._service
+	Service string
+
+	// SDL resolves _Service.sdl
+	SDL string
+}
+
+// Subgraph returns a copy of the document, with the types and fields every
+// subgraph serves:
+//
+//	scalar _Any
+//	union _Entity = ...
+//	type _Service { sdl: String }
+//
+//	type Query {
+//		_entities(representations: [_Any!]!): [_Entity]!
+//		_service: _Service!
+//	}
+//
+// _Any and _entities are only added if the document has entities, which
+// are objects. The document itself isn't modified.
+//
+func Subgraph(doc *ast.Document, r Resolvers) *ast.Document {
+	var members []*ast.Ident
+	for _, e := range Entities(doc) {
+		if !e.Interface {
+			members = append(members, &ast.Ident{Name: e.Name})
+		}
+	}
+
+	var queryFields []*ast.Field
+	if len(members) > 0 {
+		queryFields = append(queryFields, &ast.Field{
+			Name: &ast.Ident{Name: "_entities"},
+			Args: &ast.InputValueList{List: []*ast.InputValue{{
+				Name: &ast.Ident{Name: "representations"},
+				Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_List{List: &ast.List{
+					Type: &ast.List_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "_Any"}}}},
+				}}}},
+			}}},
+			Type: &ast.Field_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_List{List: &ast.List{
+				Type: &ast.List_Ident{Ident: &ast.Ident{Name: "_Entity"}},
+			}}}},
+			Directives: resolver(r.Entities),
+		})
+	}
+	queryFields = append(queryFields, &ast.Field{
+		Name:       &ast.Ident{Name: "_service"},
+		Type:       &ast.Field_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "_Service"}}}},
+		Directives: resolver(r.Service),
+	})
+
+	sub := &ast.Document{
+		Name:       doc.Name,
+		Doc:        doc.Doc,
+		Directives: doc.Directives,
+		Schema:     doc.Schema,
+		Types:      make([]*ast.TypeDecl, 0, len(doc.Types)+4),
+	}
+
+	query := queryName(doc)
+	var hasQuery bool
+	for _, decl := range doc.Types {
+		ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil || ts.TypeSpec.Name.Name != query {
+			sub.Types = append(sub.Types, decl)
+			continue
+		}
+
+		obj, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Object)
+		if !ok {
+			sub.Types = append(sub.Types, decl)
+			continue
+		}
+		hasQuery = true
+
+		var fields []*ast.Field
+		if obj.Object.Fields != nil {
+			fields = append(fields, obj.Object.Fields.List...)
+		}
+		fields = append(fields, queryFields...)
+
+		sub.Types = append(sub.Types, &ast.TypeDecl{
+			Doc:    decl.Doc,
+			TokPos: decl.TokPos,
+			Tok:    decl.Tok,
+			Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+				Name:       ts.TypeSpec.Name,
+				Directives: ts.TypeSpec.Directives,
+				Type: &ast.TypeSpec_Object{Object: &ast.ObjectType{
+					Interfaces: obj.Object.Interfaces,
+					Fields:     &ast.FieldList{List: fields},
+				}},
+			}},
+		})
+	}
+
+	if !hasQuery {
+		sub.Types = append(sub.Types, &ast.TypeDecl{
+			Tok: token.Token_TYPE,
+			Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+				Name: &ast.Ident{Name: query},
+				Type: &ast.TypeSpec_Object{Object: &ast.ObjectType{
+					Fields: &ast.FieldList{List: queryFields},
+				}},
+			}},
+		})
+	}
+
+	if len(members) > 0 {
+		sub.Types = append(sub.Types,
+			&ast.TypeDecl{
+				Tok: token.Token_SCALAR,
+				Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+					Name: &ast.Ident{Name: "_Any"},
+					Type: &ast.TypeSpec_Scalar{Scalar: &ast.ScalarType{}},
+				}},
+			},
+			&ast.TypeDecl{
+				Tok: token.Token_UNION,
+				Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+					Name: &ast.Ident{Name: "_Entity"},
+					Type: &ast.TypeSpec_Union{Union: &ast.UnionType{Members: members}},
+				}},
+			},
+		)
+	}
+
+	sub.Types = append(sub.Types, &ast.TypeDecl{
+		Tok: token.Token_TYPE,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "_Service"},
+			Type: &ast.TypeSpec_Object{Object: &ast.ObjectType{
+				Fields: &ast.FieldList{List: []*ast.Field{{
+					Name:       &ast.Ident{Name: "sdl"},
+					Type:       &ast.Field_Ident{Ident: &ast.Ident{Name: "String"}},
+					Directives: resolver(r.SDL),
+				}}},
+			}},
+		}},
+	})
+	return sub
+}
+
+func resolver(name string) []*ast.DirectiveLit {
+	if name == "" {
+		return nil
+	}
+
+	return []*ast.DirectiveLit{{
+		Name: "resolver",
+		Args: &ast.CallExpr{Args: []*ast.Arg{{
+			Name: &ast.Ident{Name: "name"},
+			Value: &ast.Arg_BasicLit{BasicLit: &ast.BasicLit{
+				Kind:  token.Token_STRING,
+				Value: `"` + name + `"`,
+			}},
+		}}},
+	}}
+}
+
+// queryName returns the name of the query root operation type.
+func queryName(doc *ast.Document) string {
+	schema := doc.Schema
+	for _, decl := range doc.Types {
+		if ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec); ok {
+			if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Schema); ok {
+				schema = decl
+			}
+		}
+	}
+	if schema == nil {
+		return "Query"
+	}
+
+	ts, ok := schema.Spec.(*ast.TypeDecl_TypeSpec)
+	if !ok {
+		return "Query"
+	}
+	s, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Schema)
+	if !ok || s.Schema.RootOps == nil {
+		return "Query"
+	}
+
+	for _, op := range s.Schema.RootOps.List {
+		if op.Name.Name != "query" {
+			continue
+		}
+
+		if id, ok := op.Type.(*ast.Field_Ident); ok {
+			return id.Ident.Name
+		}
+	}
+	return "Query"
+}
+
+// SDL returns the schema of the subgraph, as it's served by _service, i.e.
+// the document, without gqlc's options, extended with the federation
+// @link.
+//
+func SDL(doc *ast.Document) string {
+	d := &ast.Document{
+		Name:   doc.Name,
+		Schema: doc.Schema,
+		Types:  doc.Types,
+	}
+
+	return Link + "\n\n" + string(sdl.Format(d, &sdl.Options{Descriptions: true}))
+}
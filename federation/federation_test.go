@@ -0,0 +1,327 @@
+package federation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/compiler"
+	"github.com/gqlc/compiler/spec"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+func parse(t *testing.T, src string) *ast.Document {
+	t.Helper()
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(src), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestParseFieldSet(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Set  string
+		Sels []Selection
+		Err  string
+	}{
+		{Name: "Single", Set: "id", Sels: []Selection{{Name: "id"}}},
+		{Name: "Compound", Set: "id, sku", Sels: []Selection{{Name: "id"}, {Name: "sku"}}},
+		{
+			Name: "Nested",
+			Set:  "id org { id region { code } }",
+			Sels: []Selection{
+				{Name: "id"},
+				{Name: "org", Fields: []Selection{{Name: "id"}, {Name: "region", Fields: []Selection{{Name: "code"}}}}},
+			},
+		},
+		{Name: "Empty", Set: " ", Err: "field set must select one or more fields"},
+		{Name: "Unclosed", Set: "org { id", Err: "field set is missing a closing '}'"},
+		{Name: "Unopened", Set: "id }", Err: "unexpected '}' in field set"},
+		{Name: "NoField", Set: "{ id }", Err: "field set selects from nothing"},
+		{Name: "EmptySelection", Set: "org { }", Err: "field set must select one or more fields of org"},
+		{Name: "Fragment", Set: "... on User { id }", Err: "unexpected '.' in field set"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			sels, err := ParseFieldSet(testCase.Set)
+			if testCase.Err != "" {
+				if err == nil || err.Error() != testCase.Err {
+					subT.Errorf("expected error: %s, but got: %v", testCase.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if !reflect.DeepEqual(sels, testCase.Sels) {
+				subT.Errorf("expected: %v, but got: %v", testCase.Sels, sels)
+			}
+		})
+	}
+}
+
+func TestValidator(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Src  string
+		Err  string
+	}{
+		{
+			Name: "Valid",
+			Src: `type User @key(fields: "id org { id }") @shareable {
+	id: ID!
+	org: Org
+	name: String @external
+	greeting: String @requires(fields: "name")
+	manager: User @provides(fields: "name")
+}
+
+type Org {
+	id: ID!
+}`,
+		},
+		{
+			Name: "Extension",
+			Src: `type User {
+	id: ID!
+}
+
+extend type User @key(fields: "id")`,
+		},
+		{
+			Name: "UnknownKeyField",
+			Src: `type User @key(fields: "uuid") {
+	id: ID!
+}`,
+			Err: "User: @key: unknown field of User: uuid",
+		},
+		{
+			Name: "KeyFieldArgs",
+			Src: `type User @key(fields: "id") {
+	id(format: String): ID!
+}`,
+			Err: "User: @key: key field can't have arguments: id",
+		},
+		{
+			Name: "MissingSelection",
+			Src: `type User @key(fields: "org") {
+	org: Org
+}
+
+type Org {
+	id: ID!
+}`,
+			Err: "User: @key: field of type Org must select its fields: org",
+		},
+		{
+			Name: "LeafSelection",
+			Src: `type User @key(fields: "id { a }") {
+	id: ID!
+}`,
+			Err: "User: @key: field of type ID can't select fields: id",
+		},
+		{
+			Name: "InvalidFieldSet",
+			Src: `type User @key(fields: "id {") {
+	id: ID!
+}`,
+			Err: "User: @key: field set is missing a closing '}'",
+		},
+		{
+			Name: "RequiresNotExternal",
+			Src: `type User @key(fields: "id") {
+	id: ID!
+	name: String
+	greeting: String @requires(fields: "name")
+}`,
+			Err: "User:greeting: @requires: required field must be @external: name",
+		},
+		{
+			Name: "RequiresExternalType",
+			Src: `type User @key(fields: "id") {
+	id: ID!
+	greeting: String @requires(fields: "name")
+}
+
+extend type User @external {
+	name: String
+}`,
+		},
+		{
+			Name: "ProvidesLeaf",
+			Src: `type User {
+	name: String @provides(fields: "length")
+}`,
+			Err: "User:name: @provides: String isn't an object or interface type",
+		},
+		{
+			Name: "ProvidesUnknownField",
+			Src: `type User {
+	manager: User @provides(fields: "age")
+}`,
+			Err: "User:manager: @provides: unknown field of User: age",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			doc := parse(subT, testCase.Src)
+
+			errs := compiler.CheckTypes(compiler.ToIR([]*ast.Document{doc}), spec.Validator, Validator)
+			if testCase.Err == "" {
+				for _, err := range errs {
+					subT.Error(err)
+				}
+				return
+			}
+
+			if len(errs) != 1 || errs[0].Error() != testCase.Err {
+				subT.Errorf("expected error: %s, but got: %v", testCase.Err, errs)
+			}
+		})
+	}
+}
+
+func TestEntities(t *testing.T) {
+	doc := parse(t, `type User @key(fields: "id") {
+	id: ID!
+}
+
+interface Node @key(fields: "id") {
+	id: ID!
+}
+
+type Review @key(fields: "id", resolvable: false) {
+	id: ID!
+}
+
+type Query {
+	me: User
+}`)
+
+	ex := []Entity{
+		{Name: "User", Key: "id", Resolvable: true},
+		{Name: "Node", Key: "id", Resolvable: true, Interface: true},
+		{Name: "Review", Key: "id"},
+	}
+	if entities := Entities(doc); !reflect.DeepEqual(entities, ex) {
+		t.Errorf("expected: %v, but got: %v", ex, entities)
+	}
+}
+
+func fieldNames(doc *ast.Document, name string) (names []string) {
+	for _, decl := range doc.Types {
+		ts := decl.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+		if ts.Name == nil || ts.Name.Name != name {
+			continue
+		}
+
+		for _, f := range ts.Type.(*ast.TypeSpec_Object).Object.Fields.List {
+			names = append(names, f.Name.Name)
+		}
+	}
+	return
+}
+
+func typeNames(doc *ast.Document) (names []string) {
+	for _, decl := range doc.Types {
+		if ts := decl.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec; ts.Name != nil {
+			names = append(names, ts.Name.Name)
+		}
+	}
+	return
+}
+
+func TestSubgraph(t *testing.T) {
+	t.Run("Entities", func(subT *testing.T) {
+		doc := parse(subT, `type Query {
+	me: User
+}
+
+type User @key(fields: "id") {
+	id: ID!
+}`)
+
+		sub := Subgraph(doc, Resolvers{SDL: "sdl"})
+
+		if names, ex := typeNames(sub), []string{"Query", "User", "_Any", "_Entity", "_Service"}; !reflect.DeepEqual(names, ex) {
+			subT.Errorf("expected types: %v, but got: %v", ex, names)
+		}
+		if names, ex := fieldNames(sub, "Query"), []string{"me", "_entities", "_service"}; !reflect.DeepEqual(names, ex) {
+			subT.Errorf("expected Query fields: %v, but got: %v", ex, names)
+		}
+		if names, ex := fieldNames(doc, "Query"), []string{"me"}; !reflect.DeepEqual(names, ex) {
+			subT.Errorf("expected document to be left as is, but got Query fields: %v", names)
+		}
+
+		union := sub.Types[3].Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Union).Union
+		if len(union.Members) != 1 || union.Members[0].Name != "User" {
+			subT.Errorf("expected _Entity to be User, but got: %v", union.Members)
+		}
+
+		sdl := sub.Types[4].Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Object).Object.Fields.List[0]
+		if len(sdl.Directives) != 1 || sdl.Directives[0].Name != "resolver" {
+			subT.Errorf("expected _Service.sdl to have a resolver, but got: %v", sdl.Directives)
+		}
+	})
+
+	t.Run("NoEntities", func(subT *testing.T) {
+		doc := parse(subT, `schema {
+	query: RootQuery
+}
+
+type RootQuery {
+	version: String
+}`)
+
+		sub := Subgraph(doc, Resolvers{})
+
+		if names, ex := typeNames(sub), []string{"RootQuery", "_Service"}; !reflect.DeepEqual(names, ex) {
+			subT.Errorf("expected types: %v, but got: %v", ex, names)
+		}
+		if names, ex := fieldNames(sub, "RootQuery"), []string{"version", "_service"}; !reflect.DeepEqual(names, ex) {
+			subT.Errorf("expected RootQuery fields: %v, but got: %v", ex, names)
+		}
+	})
+
+	t.Run("NoQuery", func(subT *testing.T) {
+		doc := parse(subT, `type User @key(fields: "id") {
+	id: ID!
+}`)
+
+		sub := Subgraph(doc, Resolvers{})
+		if names, ex := fieldNames(sub, "Query"), []string{"_entities", "_service"}; !reflect.DeepEqual(names, ex) {
+			subT.Errorf("expected Query fields: %v, but got: %v", ex, names)
+		}
+	})
+}
+
+func TestSDL(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(`@go(options: {package: "api"})
+
+type User @key(fields: "id") {
+	id: ID!
+}`), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := Link + `
+
+type User @key(fields: "id") {
+  id: ID!
+}
+`
+	if s := SDL(doc); s != ex {
+		t.Errorf("expected:\n%s\nbut got:\n%s", ex, s)
+	}
+}
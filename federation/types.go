@@ -0,0 +1,117 @@
+// types.go contains the Federation v2 directives, which subgraphs use
+
+package federation
+
+import (
+	"github.com/gqlc/compiler"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+func fieldSetArg() *ast.InputValue {
+	return &ast.InputValue{
+		Name: &ast.Ident{Name: "fields"},
+		Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{
+			Type: &ast.NonNull_Ident{
+				Ident: &ast.Ident{Name: "FieldSet"},
+			},
+		}},
+	}
+}
+
+var fedTypes = []*ast.TypeDecl{
+	{
+		Tok: token.Token_SCALAR,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "FieldSet"},
+			Type: &ast.TypeSpec_Scalar{Scalar: &ast.ScalarType{}},
+		}},
+	},
+	{
+		Tok: token.Token_DIRECTIVE,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: Key},
+			Type: &ast.TypeSpec_Directive{Directive: &ast.DirectiveType{
+				Locs: []*ast.DirectiveLocation{
+					{Loc: ast.DirectiveLocation_OBJECT},
+					{Loc: ast.DirectiveLocation_INTERFACE},
+				},
+				Args: &ast.InputValueList{
+					List: []*ast.InputValue{
+						fieldSetArg(),
+						{
+							Name: &ast.Ident{Name: "resolvable"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "true",
+							}},
+						},
+					},
+				},
+			}},
+		}},
+	},
+	{
+		Tok: token.Token_DIRECTIVE,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: External},
+			Type: &ast.TypeSpec_Directive{Directive: &ast.DirectiveType{
+				Locs: []*ast.DirectiveLocation{
+					{Loc: ast.DirectiveLocation_OBJECT},
+					{Loc: ast.DirectiveLocation_FIELD_DEFINITION},
+				},
+			}},
+		}},
+	},
+	{
+		Tok: token.Token_DIRECTIVE,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: Requires},
+			Type: &ast.TypeSpec_Directive{Directive: &ast.DirectiveType{
+				Locs: []*ast.DirectiveLocation{
+					{Loc: ast.DirectiveLocation_FIELD_DEFINITION},
+				},
+				Args: &ast.InputValueList{
+					List: []*ast.InputValue{fieldSetArg()},
+				},
+			}},
+		}},
+	},
+	{
+		Tok: token.Token_DIRECTIVE,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: Provides},
+			Type: &ast.TypeSpec_Directive{Directive: &ast.DirectiveType{
+				Locs: []*ast.DirectiveLocation{
+					{Loc: ast.DirectiveLocation_FIELD_DEFINITION},
+				},
+				Args: &ast.InputValueList{
+					List: []*ast.InputValue{fieldSetArg()},
+				},
+			}},
+		}},
+	},
+	{
+		Tok: token.Token_DIRECTIVE,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: Shareable},
+			Type: &ast.TypeSpec_Directive{Directive: &ast.DirectiveType{
+				Locs: []*ast.DirectiveLocation{
+					{Loc: ast.DirectiveLocation_OBJECT},
+					{Loc: ast.DirectiveLocation_FIELD_DEFINITION},
+				},
+			}},
+		}},
+	},
+}
+
+// The directives are a part of the schema, rather than options for gqlc,
+// so they're registered with the compiler directly, instead of through
+// the types package.
+//
+func init() {
+	compiler.RegisterTypes(fedTypes...)
+}
@@ -0,0 +1,479 @@
+// Package fragments contains a generator of fragment libraries, i.e. modules
+// of constants holding the fragments clients share, for Go, TypeScript and
+// Javascript. The fragments are read from the executable documents given
+// alongside the schema, and checked against it, so every client selects the
+// same canonical fields, and a fragment which is renamed or removed fails to
+// compile instead of at runtime.
+//
+package fragments
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"go.uber.org/zap"
+)
+
+// The languages fragment modules can be generated for.
+const (
+	Go = "go"
+	TS = "ts"
+	JS = "js"
+)
+
+// Options contains the options for the fragments generator.
+type Options struct {
+	// Langs are the languages modules are generated for, any of: go, ts
+	// or js (default: ts)
+	//
+	Langs []string
+
+	// Package is the package of the generated Go file (default: fragments)
+	Package string
+}
+
+// Generator generates fragment libraries for a GraphQL schema.
+type Generator struct {
+	bytes.Buffer
+
+	log *zap.Logger
+}
+
+var header = "// Code generated by gqlc, DO NOT EDIT.\n\n"
+
+// Generate generates the modules of the fragments on the types of the given
+// document, named after it e.g. test.gql generates test.fragments.ts. Each
+// fragment is a constant holding its definition, followed by the
+// definitions of the fragments it spreads, so it can be appended to any
+// operation which spreads it.
+//
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "fragments",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("fragments").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	gOpts, err := getOptions(doc, opts)
+	if err != nil {
+		return
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	srcs := gen.Operations(gCtx)
+	if len(srcs) == 0 {
+		return fmt.Errorf("no fragments were given, they must be given with --ops")
+	}
+
+	g.log.Info("reading fragments")
+	frags, err := readFragments(srcs)
+	if err != nil {
+		return
+	}
+
+	docs := gen.Documents(gCtx)
+	if len(docs) == 0 {
+		docs = []*ast.Document{doc}
+	}
+	err = checkFragments(frags, docs)
+	if err != nil {
+		return
+	}
+
+	// Only the fragments on the types of this document are generated, so
+	// every fragment is generated once when there are many documents.
+	//
+	local := make(map[string]bool, len(doc.Types))
+	for _, name := range typeNames(doc) {
+		local[name] = true
+	}
+
+	var names []string
+	for name, f := range frags {
+		if local[f.on] {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		g.log.Info("no fragments are on the types of this document")
+		return
+	}
+	sort.Strings(names)
+
+	base := filepath.Base(doc.Name)
+	base = base[:len(base)-len(filepath.Ext(base))]
+
+	for _, lang := range gOpts.Langs {
+		g.Reset()
+
+		var name string
+		switch lang {
+		case Go:
+			name = base + "_fragments.go"
+			g.generateGo(gOpts.Package, names, frags)
+		case TS:
+			name = base + ".fragments.ts"
+			g.generateJS(names, frags, true)
+		case JS:
+			name = base + ".fragments.js"
+			g.generateJS(names, frags, false)
+		}
+
+		g.log.Info("writing fragments", zap.String("lang", lang))
+		err = g.writeFile(gCtx, name)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (g *Generator) writeFile(gCtx gen.GeneratorContext, name string) error {
+	f, err := gCtx.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = g.WriteTo(f)
+	return err
+}
+
+// readFragments reads the fragments of executable documents, by name.
+func readFragments(srcs []gen.OperationDocument) (map[string]*fragment, error) {
+	frags := make(map[string]*fragment)
+	for _, src := range srcs {
+		fs, err := parseFragments(src.Name, src.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range fs {
+			if other, ok := frags[f.name]; ok {
+				return nil, fmt.Errorf("fragment %s is defined by both %s and %s", f.name, other.src, f.src)
+			}
+			frags[f.name] = f
+		}
+	}
+	return frags, nil
+}
+
+// checkFragments checks that every fragment is on an object, interface or
+// union of the schema, and only spreads fragments which are defined, without
+// spreading itself.
+//
+func checkFragments(frags map[string]*fragment, docs []*ast.Document) error {
+	kinds := make(map[string]string)
+	for _, doc := range docs {
+		for _, d := range doc.Types {
+			ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+			if !ok || ts.TypeSpec.Name == nil {
+				continue
+			}
+
+			switch ts.TypeSpec.Type.(type) {
+			case *ast.TypeSpec_Object, *ast.TypeSpec_Interface, *ast.TypeSpec_Union:
+				kinds[ts.TypeSpec.Name.Name] = "composite"
+			case *ast.TypeSpec_Scalar:
+				kinds[ts.TypeSpec.Name.Name] = "scalar"
+			case *ast.TypeSpec_Enum:
+				kinds[ts.TypeSpec.Name.Name] = "enum"
+			case *ast.TypeSpec_Input:
+				kinds[ts.TypeSpec.Name.Name] = "input"
+			}
+		}
+	}
+
+	names := make([]string, 0, len(frags))
+	for name := range frags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		f := frags[name]
+		switch kind := kinds[f.on]; kind {
+		case "composite":
+		case "":
+			return fmt.Errorf("%s: fragment %s is on unknown type %s", f.src, f.name, f.on)
+		default:
+			return fmt.Errorf("%s: fragment %s is on %s, which is an %s, not an object, interface or union", f.src, f.name, f.on, kind)
+		}
+
+		_, err := dependencies(frags, f)
+		if err != nil {
+			return fmt.Errorf("%s: %s", f.src, err)
+		}
+	}
+	return nil
+}
+
+// dependencies returns the fragments spread by f, directly or by the
+// fragments it spreads, sorted by name.
+//
+func dependencies(frags map[string]*fragment, f *fragment) ([]*fragment, error) {
+	seen := map[string]bool{f.name: true}
+	var deps []*fragment
+
+	var visit func(f *fragment) error
+	visit = func(f *fragment) error {
+		for _, name := range f.spreads {
+			if name == f.name {
+				return fmt.Errorf("fragment %s spreads itself", name)
+			}
+			if seen[name] {
+				continue
+			}
+
+			dep, ok := frags[name]
+			if !ok {
+				return fmt.Errorf("fragment %s spreads unknown fragment %s", f.name, name)
+			}
+			seen[name] = true
+			deps = append(deps, dep)
+
+			err := visit(dep)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err := visit(f)
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range deps {
+		if contains(dep.spreads, f.name) {
+			return nil, fmt.Errorf("fragment %s spreads itself, through %s", f.name, dep.name)
+		}
+	}
+
+	sort.Slice(deps, func(i, j int) bool { return deps[i].name < deps[j].name })
+	return deps, nil
+}
+
+// definition returns the definition of a fragment followed by those of its
+// dependencies.
+//
+func definition(frags map[string]*fragment, f *fragment) string {
+	deps, _ := dependencies(frags, f)
+
+	texts := []string{f.text}
+	for _, dep := range deps {
+		texts = append(texts, dep.text)
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+func (g *Generator) generateGo(pkg string, names []string, frags map[string]*fragment) {
+	g.WriteString(header)
+	g.WriteString("package ")
+	g.WriteString(pkg)
+	g.WriteByte('\n')
+
+	for _, name := range names {
+		f := frags[name]
+		g.WriteByte('\n')
+
+		ident := exportName(name)
+		fmt.Fprintf(g, "// %s is the %s fragment on %s.\n", ident, name, f.on)
+		if f.descr != "" {
+			g.WriteString("//\n")
+			for _, line := range strings.Split(f.descr, "\n") {
+				g.WriteString(strings.TrimRight("// "+line, " "))
+				g.WriteByte('\n')
+			}
+		}
+
+		g.WriteString("const ")
+		g.WriteString(ident)
+		g.WriteString(" = ")
+
+		def := definition(frags, f)
+		if strings.Contains(def, "`") {
+			g.WriteString(strconv.Quote(def))
+		} else {
+			g.WriteByte('`')
+			g.WriteString(def)
+			g.WriteByte('`')
+		}
+		g.WriteByte('\n')
+	}
+}
+
+// generateJS generates an ES module of the fragments, which is typed as
+// TypeScript if ts is set. Along with a constant for each fragment, the
+// module exports them all as fragments, keyed by name.
+//
+func (g *Generator) generateJS(names []string, frags map[string]*fragment, ts bool) {
+	g.WriteString(header)
+
+	for i, name := range names {
+		f := frags[name]
+		if i > 0 {
+			g.WriteByte('\n')
+		}
+
+		if f.descr != "" {
+			g.WriteString("/**\n")
+			for _, line := range strings.Split(f.descr, "\n") {
+				g.WriteString(strings.TrimRight(" * "+line, " "))
+				g.WriteByte('\n')
+			}
+			g.WriteString(" */\n")
+		}
+
+		g.WriteString("export const ")
+		g.WriteString(name)
+		g.WriteString(" = ")
+
+		def := definition(frags, f)
+		if strings.ContainsAny(def, "`\\") || strings.Contains(def, "${") {
+			g.WriteString(strconv.Quote(def))
+		} else {
+			g.WriteByte('`')
+			g.WriteString(def)
+			g.WriteByte('`')
+		}
+		g.WriteString(";\n")
+	}
+
+	g.WriteString("\nexport const fragments = {\n")
+	for _, name := range names {
+		g.WriteString("  ")
+		g.WriteString(name)
+		g.WriteString(",\n")
+	}
+	g.WriteByte('}')
+	if ts {
+		g.WriteString(" as const;\n\nexport type FragmentName = keyof typeof fragments;\n")
+		return
+	}
+	g.WriteString(";\n")
+}
+
+// typeNames returns the names of the types declared by a document.
+func typeNames(doc *ast.Document) (names []string) {
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if ok && ts.TypeSpec.Name != nil {
+			names = append(names, ts.TypeSpec.Name.Name)
+		}
+	}
+	return
+}
+
+// exportName returns the exported Go name of a fragment.
+func exportName(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Langs:   []string{TS},
+		Package: "fragments",
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "fragments" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		docOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range docOpts.Fields {
+			switch arg.Key.Name {
+			case "langs":
+				gOpts.Langs = stringList(arg.Val)
+			case "package":
+				gOpts.Package = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if l, ok := opts["langs"]; ok {
+		switch v := l.(type) {
+		case string:
+			gOpts.Langs = []string{strings.Trim(v, `"`)}
+		case []string:
+			gOpts.Langs = nil
+			for _, lang := range v {
+				gOpts.Langs = append(gOpts.Langs, strings.Trim(lang, `"`))
+			}
+		}
+	}
+	if p, ok := opts["package"]; ok {
+		v, _ := p.(string)
+		gOpts.Package = strings.Trim(v, `"`)
+	}
+
+	for _, lang := range gOpts.Langs {
+		switch lang {
+		case Go, TS, JS:
+		default:
+			return gOpts, fmt.Errorf("unknown lang: %s, expected any of: go, ts or js", lang)
+		}
+	}
+	return
+}
+
+// stringList returns the unquoted strings of a String or [String] value.
+func stringList(val *ast.CompositeLit) (strs []string) {
+	switch v := val.Value.(type) {
+	case *ast.CompositeLit_BasicLit:
+		strs = append(strs, strings.Trim(v.BasicLit.Value, `"`))
+	case *ast.CompositeLit_ListLit:
+		switch list := v.ListLit.List.(type) {
+		case *ast.ListLit_BasicList:
+			for _, lit := range list.BasicList.Values {
+				strs = append(strs, strings.Trim(lit.Value, `"`))
+			}
+		case *ast.ListLit_CompositeList:
+			for _, lit := range list.CompositeList.Values {
+				strs = append(strs, stringList(lit)...)
+			}
+		}
+	}
+	return
+}
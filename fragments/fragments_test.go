@@ -0,0 +1,285 @@
+package fragments
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+const schemaSrc = `type Query {
+	me: User
+}
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	name: String!
+	friends: [User!]
+}
+
+enum Role {
+	ADMIN
+}`
+
+const fragmentsSrc = `query Me {
+	me { ...UserFields }
+}
+
+# The fields every client shows
+# for a user.
+fragment UserFields on User {
+	name
+	...NodeFields
+}
+
+fragment NodeFields on Node { id }
+
+fragment friends on User {
+	friends { ...UserFields }
+}`
+
+// testCtx carries both the executable and schema documents.
+type testCtx struct {
+	gen.TestOperationCtx
+
+	docs  []*ast.Document
+	files map[string]*bytes.Buffer
+}
+
+func (ctx *testCtx) Documents() []*ast.Document { return ctx.docs }
+
+func (ctx *testCtx) Open(name string) (io.WriteCloser, error) {
+	b := new(bytes.Buffer)
+	ctx.files[filepath.ToSlash(name)] = b
+	return gen.TestCtx{Writer: b}, nil
+}
+
+func parse(t *testing.T, name, src string) *ast.Document {
+	t.Helper()
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), name, strings.NewReader(src), parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func generate(t *testing.T, doc *ast.Document, srcs map[string]string, opts map[string]interface{}) (map[string]*bytes.Buffer, error) {
+	t.Helper()
+
+	ctx := &testCtx{docs: []*ast.Document{doc}, files: make(map[string]*bytes.Buffer)}
+	for name, src := range srcs {
+		ctx.Docs = append(ctx.Docs, gen.OperationDocument{Name: name, Source: src})
+	}
+
+	err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, opts)
+	return ctx.files, err
+}
+
+func TestGenerate(t *testing.T) {
+	doc := parse(t, "test.gql", schemaSrc)
+
+	files, err := generate(t, doc, map[string]string{"ops.graphql": fragmentsSrc}, map[string]interface{}{"langs": []string{"go", "ts", "js"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userFields := "fragment UserFields on User {\n\tname\n\t...NodeFields\n}\n\nfragment NodeFields on Node { id }"
+	friends := "fragment friends on User {\n\tfriends { ...UserFields }\n}\n\nfragment NodeFields on Node { id }\n\n" + strings.SplitN(userFields, "\n\n", 2)[0]
+
+	testCases := map[string]string{
+		"test_fragments.go": "// Code generated by gqlc, DO NOT EDIT.\n\npackage fragments\n\n" +
+			"// NodeFields is the NodeFields fragment on Node.\nconst NodeFields = `fragment NodeFields on Node { id }`\n\n" +
+			"// UserFields is the UserFields fragment on User.\n//\n// The fields every client shows\n// for a user.\nconst UserFields = `" + userFields + "`\n\n" +
+			"// Friends is the friends fragment on User.\nconst Friends = `" + friends + "`\n",
+		"test.fragments.ts": "// Code generated by gqlc, DO NOT EDIT.\n\n" +
+			"export const NodeFields = `fragment NodeFields on Node { id }`;\n\n" +
+			"/**\n * The fields every client shows\n * for a user.\n */\nexport const UserFields = `" + userFields + "`;\n\n" +
+			"export const friends = `" + friends + "`;\n\n" +
+			"export const fragments = {\n  NodeFields,\n  UserFields,\n  friends,\n} as const;\n\n" +
+			"export type FragmentName = keyof typeof fragments;\n",
+		"test.fragments.js": "// Code generated by gqlc, DO NOT EDIT.\n\n" +
+			"export const NodeFields = `fragment NodeFields on Node { id }`;\n\n" +
+			"/**\n * The fields every client shows\n * for a user.\n */\nexport const UserFields = `" + userFields + "`;\n\n" +
+			"export const friends = `" + friends + "`;\n\n" +
+			"export const fragments = {\n  NodeFields,\n  UserFields,\n  friends,\n};\n",
+	}
+	if len(files) != len(testCases) {
+		t.Errorf("expected %d files, but got: %d", len(testCases), len(files))
+	}
+	for name, ex := range testCases {
+		b, ok := files[name]
+		if !ok {
+			t.Errorf("expected file: %s", name)
+			continue
+		}
+		if b.String() != ex {
+			t.Errorf("%s: expected:\n%s\nbut got:\n%s", name, ex, b)
+		}
+	}
+}
+
+func TestGenerate_Options(t *testing.T) {
+	src := "fragment UserName on User { name }"
+
+	t.Run("Default", func(subT *testing.T) {
+		files, err := generate(subT, parse(subT, "test.gql", schemaSrc), map[string]string{"ops.graphql": src}, nil)
+		if err != nil {
+			subT.Fatal(err)
+		}
+		if _, ok := files["test.fragments.ts"]; !ok || len(files) != 1 {
+			subT.Errorf("expected only test.fragments.ts, but got: %v", files)
+		}
+	})
+
+	t.Run("Directive", func(subT *testing.T) {
+		doc := parse(subT, "test.gql", `@fragments(options: {langs: ["go"], package: "queries"})`+"\n\n"+schemaSrc)
+
+		files, err := generate(subT, doc, map[string]string{"ops.graphql": src}, nil)
+		if err != nil {
+			subT.Fatal(err)
+		}
+		b, ok := files["test_fragments.go"]
+		if !ok || len(files) != 1 {
+			subT.Fatalf("expected only test_fragments.go, but got: %v", files)
+		}
+		if !strings.Contains(b.String(), "\npackage queries\n") {
+			subT.Errorf("expected package queries, but got:\n%s", b)
+		}
+	})
+
+	t.Run("Quoted", func(subT *testing.T) {
+		src := "fragment UserName on User {\n\tname @format(as: \"`${x}`\")\n}"
+
+		files, err := generate(subT, parse(subT, "test.gql", schemaSrc), map[string]string{"ops.graphql": src}, map[string]interface{}{"langs": []string{"go", "ts"}})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		ex := `= "fragment UserName on User {\n\tname @format(as: \"` + "`${x}`" + `\")\n}"`
+		for _, name := range []string{"test_fragments.go", "test.fragments.ts"} {
+			if !strings.Contains(files[name].String(), ex) {
+				subT.Errorf("%s: expected quoted fragment: %s, but got:\n%s", name, ex, files[name])
+			}
+		}
+	})
+
+	t.Run("OtherDocument", func(subT *testing.T) {
+		files, err := generate(subT, parse(subT, "other.gql", "type Post { title: String }"), map[string]string{"ops.graphql": "fragment PostTitle on Post { title }"}, nil)
+		if err != nil {
+			subT.Fatal(err)
+		}
+		if _, ok := files["other.fragments.ts"]; !ok {
+			subT.Fatalf("expected other.fragments.ts, but got: %v", files)
+		}
+
+		files, err = generate(subT, parse(subT, "test.gql", schemaSrc), map[string]string{"ops.graphql": "query Me { me { name } }"}, nil)
+		if err != nil {
+			subT.Fatal(err)
+		}
+		if len(files) != 0 {
+			subT.Errorf("expected no files without fragments, but got: %v", files)
+		}
+	})
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Srcs map[string]string
+		Opts map[string]interface{}
+		Err  string
+	}{
+		{
+			Name: "NoOperations",
+			Err:  "no fragments were given, they must be given with --ops",
+		},
+		{
+			Name: "UnknownLang",
+			Srcs: map[string]string{"ops.graphql": fragmentsSrc},
+			Opts: map[string]interface{}{"langs": "rust"},
+			Err:  "unknown lang: rust, expected any of: go, ts or js",
+		},
+		{
+			Name: "Duplicate",
+			Srcs: map[string]string{
+				"a.graphql": "fragment UserName on User { name }",
+				"b.graphql": "fragment UserName on User { id }",
+			},
+			Err: "fragment UserName is defined by both",
+		},
+		{
+			Name: "UnknownType",
+			Srcs: map[string]string{"ops.graphql": "fragment PostTitle on Post { title }"},
+			Err:  "ops.graphql: fragment PostTitle is on unknown type Post",
+		},
+		{
+			Name: "NotComposite",
+			Srcs: map[string]string{"ops.graphql": "fragment RoleName on Role { name }"},
+			Err:  "ops.graphql: fragment RoleName is on Role, which is an enum, not an object, interface or union",
+		},
+		{
+			Name: "UnknownSpread",
+			Srcs: map[string]string{"ops.graphql": "fragment UserName on User { ...Names }"},
+			Err:  "ops.graphql: fragment UserName spreads unknown fragment Names",
+		},
+		{
+			Name: "Cycle",
+			Srcs: map[string]string{"ops.graphql": "fragment A on User { ...B }\nfragment B on User { friends { ...A } }"},
+			Err:  "ops.graphql: fragment A spreads itself, through B",
+		},
+		{
+			Name: "Syntax",
+			Srcs: map[string]string{"ops.graphql": "fragment UserName User { name }"},
+			Err:  "ops.graphql:1:19: expected type condition for fragment UserName",
+		},
+		{
+			Name: "Unterminated",
+			Srcs: map[string]string{"ops.graphql": `fragment UserName on User { name(s: "x) }`},
+			Err:  "ops.graphql:1:37: unterminated string",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			_, err := generate(subT, parse(subT, "test.gql", schemaSrc), testCase.Srcs, testCase.Opts)
+			if err == nil || !strings.Contains(err.Error(), testCase.Err) {
+				subT.Errorf("expected error containing: %s, but got: %v", testCase.Err, err)
+			}
+		})
+	}
+}
+
+func TestParseFragments(t *testing.T) {
+	src := "\uFEFF# Not a description.\n\nquery Me($id: ID = \"}\") { me { ... on User { name } } }\n\n" +
+		"# Selects names.\nfragment UserName on User {\n\tname(format: \"\"\"{\"\"\")\n\t...NodeFields\n\t... on Node { id }\n}"
+
+	frags, err := parseFragments("ops.graphql", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ex := []*fragment{{
+		name:    "UserName",
+		on:      "User",
+		text:    "fragment UserName on User {\n\tname(format: \"\"\"{\"\"\")\n\t...NodeFields\n\t... on Node { id }\n}",
+		descr:   "Selects names.",
+		spreads: []string{"NodeFields"},
+		src:     "ops.graphql",
+	}}
+	if !reflect.DeepEqual(ex, frags) {
+		t.Errorf("expected fragments: %+v, but got: %+v", ex[0], frags)
+	}
+}
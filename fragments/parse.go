@@ -0,0 +1,167 @@
+package fragments
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gqlc/gqlc/internal/oplex"
+)
+
+// fragment is a fragment definition, as it was written.
+type fragment struct {
+	name string
+	on   string
+
+	// text is the definition itself, and descr the comment lines directly
+	// above it.
+	//
+	text  string
+	descr string
+
+	// spreads are the names of the fragments spread by this one
+	spreads []string
+
+	// src is the name of the executable document it's defined in
+	src string
+}
+
+// scanner finds the fragment definitions of an executable document. The
+// document is assumed to be valid, since it's validated before generators
+// are given it, so only enough of its tokens are scanned to find where
+// definitions start and end.
+//
+type scanner struct {
+	toks []oplex.Token
+	i    int
+}
+
+// parseFragments returns the fragments defined by an executable document,
+// in the order they're defined. Operations are skipped.
+//
+func parseFragments(name, src string) (frags []*fragment, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			oerr, ok := r.(oplex.Error)
+			if !ok {
+				panic(r)
+			}
+			err = posError(name, src, oerr)
+		}
+	}()
+
+	toks, err := oplex.Lex(src)
+	if err != nil {
+		return nil, posError(name, src, err.(oplex.Error))
+	}
+
+	s := &scanner{toks: toks}
+	for s.peek().Kind != oplex.EOF {
+		start := s.peek().Off
+		if s.peek().Val == "{" {
+			s.definition()
+			continue
+		}
+
+		switch kw := s.name(); kw {
+		case "query", "mutation", "subscription":
+			s.definition()
+		case "fragment":
+			f := &fragment{name: s.name(), descr: leadingComment(src, start), src: name}
+			if s.peek().Val != "on" {
+				s.errorf("expected type condition for fragment %s", f.name)
+			}
+			s.next()
+			f.on = s.name()
+
+			var end int
+			f.spreads, end = s.definition()
+			f.text = src[start:end]
+			frags = append(frags, f)
+		default:
+			s.i--
+			s.errorf("unexpected %q, only operations and fragments are allowed", kw)
+		}
+	}
+	return
+}
+
+// posError positions an error by the line and column of its offset.
+func posError(name, src string, err oplex.Error) error {
+	line := 1 + strings.Count(src[:err.Off], "\n")
+	col := err.Off - strings.LastIndexByte(src[:err.Off], '\n')
+	return fmt.Errorf("%s:%d:%d: %s", name, line, col, err.Msg)
+}
+
+func (s *scanner) errorf(format string, args ...interface{}) {
+	panic(oplex.Error{Off: s.peek().Off, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (s *scanner) peek() oplex.Token { return s.toks[s.i] }
+
+func (s *scanner) next() oplex.Token {
+	tok := s.toks[s.i]
+	if tok.Kind != oplex.EOF {
+		s.i++
+	}
+	return tok
+}
+
+// name scans a name.
+func (s *scanner) name() string {
+	if s.peek().Kind != oplex.Name {
+		s.errorf("expected name")
+	}
+	return s.next().Val
+}
+
+// definition scans the rest of a definition, up to the end of its
+// selection set, and returns the names of the fragments it spreads, and
+// the offset just past its end.
+//
+func (s *scanner) definition() (spreads []string, end int) {
+	depth := 0
+	for s.peek().Kind != oplex.EOF {
+		tok := s.next()
+		if tok.Kind != oplex.Punct {
+			continue
+		}
+
+		switch tok.Val {
+		case "{", "(", "[":
+			depth++
+		case "}", ")", "]":
+			depth--
+			if depth == 0 && tok.Val == "}" {
+				return spreads, tok.Off + 1
+			}
+		case "...":
+			if next := s.peek(); next.Kind == oplex.Name && next.Val != "on" {
+				spreads = append(spreads, s.next().Val)
+			}
+		}
+	}
+	s.errorf("unexpected end of document")
+	return
+}
+
+// leadingComment returns the comment lines directly above off.
+func leadingComment(src string, off int) string {
+	var lines []string
+
+	end := strings.LastIndexByte(src[:off], '\n')
+	for end >= 0 {
+		start := strings.LastIndexByte(src[:end], '\n') + 1
+
+		line := strings.TrimSpace(src[start:end])
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		lines = append(lines, strings.TrimSpace(line[1:]))
+		end = start - 1
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
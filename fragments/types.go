@@ -0,0 +1,72 @@
+// types.go contains the GraphQL types this generator supports
+
+package fragments
+
+import (
+	"github.com/gqlc/gqlc/types"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+var fragmentsTypes = []*ast.TypeDecl{
+	{
+		Tok: token.Token_DIRECTIVE,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "fragments"},
+			Type: &ast.TypeSpec_Directive{Directive: &ast.DirectiveType{
+				Locs: []*ast.DirectiveLocation{{Loc: ast.DirectiveLocation_DOCUMENT}},
+				Args: &ast.InputValueList{
+					List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "options"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "FragmentsOptions"},
+							},
+						},
+					},
+				},
+			}},
+		}},
+	},
+	{
+		Tok: token.Token_INPUT,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "FragmentsOptions"},
+			Type: &ast.TypeSpec_Input{Input: &ast.InputType{
+				Fields: &ast.InputValueList{
+					List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "langs"},
+							Type: &ast.InputValue_List{List: &ast.List{
+								Type: &ast.List_Ident{Ident: &ast.Ident{Name: "String"}},
+							}},
+							Default: &ast.InputValue_CompositeLit{CompositeLit: &ast.CompositeLit{
+								Value: &ast.CompositeLit_ListLit{ListLit: &ast.ListLit{
+									List: &ast.ListLit_BasicList{BasicList: &ast.ListLit_Basic{
+										Values: []*ast.BasicLit{
+											{Kind: token.Token_STRING, Value: `"ts"`},
+										},
+									}},
+								}},
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "package"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_STRING,
+								Value: `"fragments"`,
+							}},
+						},
+					},
+				},
+			}},
+		}},
+	},
+}
+
+func init() {
+	types.Register(fragmentsTypes...)
+}
@@ -0,0 +1,49 @@
+// Package fuzz provides a go-fuzz entry point, which runs the generators
+// over arbitrary GraphQL documents.
+//
+// To fuzz, build the package with go-fuzz and point it at the seed corpus:
+//
+//	go-fuzz-build github.com/gqlc/gqlc/fuzz
+//	go-fuzz -bin fuzz-fuzz.zip -workdir fuzz
+//
+package fuzz
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+
+	"github.com/gqlc/gqlc/doc"
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/golang"
+	"github.com/gqlc/gqlc/js"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+// generators are run over every document which parses and is well shaped.
+var generators = []gen.Generator{
+	new(js.Generator),
+	new(golang.Generator),
+	new(doc.Generator),
+}
+
+// Fuzz parses data as a GraphQL document and runs every generator over it.
+// Generators may reject a document with an error, but must never panic.
+// It returns 1 when the document was generated, so go-fuzz prioritizes it,
+// and 0 otherwise.
+//
+func Fuzz(data []byte) int {
+	d, err := parser.ParseDoc(token.NewDocSet(), "fuzz", bytes.NewReader(data), parser.ParseComments)
+	if err != nil {
+		return 0
+	}
+
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: ioutil.Discard})
+	for _, g := range generators {
+		if err = g.Generate(ctx, d, nil); err != nil {
+			return 0
+		}
+	}
+	return 1
+}
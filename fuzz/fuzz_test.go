@@ -0,0 +1,31 @@
+package fuzz
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// TestCorpus runs Fuzz over the seed corpus, so any crasher which is added
+// to it stays fixed.
+//
+func TestCorpus(t *testing.T) {
+	names, err := filepath.Glob(filepath.Join("corpus", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) == 0 {
+		t.Fatal("empty corpus")
+	}
+
+	for _, name := range names {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run(filepath.Base(name), func(subT *testing.T) {
+			Fuzz(b)
+		})
+	}
+}
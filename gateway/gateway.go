@@ -0,0 +1,324 @@
+// Package gateway contains a generator of gateway route manifests for
+// GraphQL Documents. A manifest routes every root field to the URL of the
+// service backing it, as given by @service directives, so a gateway can be
+// configured from the same schema the services implement.
+//
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/ir"
+	"github.com/gqlc/graphql/ast"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+)
+
+// Formats a manifest can be written as.
+const (
+	YAML = "YAML"
+	JSON = "JSON"
+)
+
+// serviceDirective is the directive routing the fields of the schema, a
+// root type or a single root field to a service.
+//
+const serviceDirective = "service"
+
+// Options contains the options for the gateway generator.
+type Options struct {
+	// Format is either YAML or JSON (default: YAML)
+	Format string
+}
+
+// Manifest routes the root fields of a schema to the services resolving
+// them.
+//
+type Manifest struct {
+	Services []*Service `json:"services" yaml:"services"`
+}
+
+// Service is a service along with the root fields routed to it.
+type Service struct {
+	// Name is the name given to @service, or the host of its URL.
+	Name string `json:"name" yaml:"name"`
+	URL  string `json:"url" yaml:"url"`
+
+	Routes Routes `json:"routes" yaml:"routes"`
+}
+
+// Routes are the names of the root fields of each kind of operation.
+type Routes struct {
+	Query        []string `json:"query,omitempty" yaml:"query,omitempty"`
+	Mutation     []string `json:"mutation,omitempty" yaml:"mutation,omitempty"`
+	Subscription []string `json:"subscription,omitempty" yaml:"subscription,omitempty"`
+}
+
+// Generator generates a gateway route manifest for a GraphQL schema.
+type Generator struct {
+	bytes.Buffer
+
+	log *zap.Logger
+}
+
+// Generate generates the route manifest of the given document. It's named
+// after the document and its format e.g. test.gql generates
+// test.gateway.yaml, or test.gateway.json.
+//
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "gateway",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("gateway").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	gOpts, err := getOptions(doc, opts)
+	if err != nil {
+		return
+	}
+	switch gOpts.Format {
+	case YAML, JSON:
+	default:
+		return fmt.Errorf("unknown gateway format: %s", gOpts.Format)
+	}
+
+	s, err := ir.Load(doc)
+	if err != nil {
+		return
+	}
+
+	def, err := schemaService(doc)
+	if err != nil {
+		return
+	}
+
+	g.log.Info("routing root fields")
+	m, err := BuildManifest(s, def)
+	if err != nil {
+		return
+	}
+
+	g.log.Info("generating manifest")
+	switch gOpts.Format {
+	case YAML:
+		var b []byte
+		b, err = yaml.Marshal(m)
+		if err != nil {
+			return
+		}
+		g.WriteString("# Code generated by gqlc, DO NOT EDIT.\n\n")
+		g.Write(b)
+	case JSON:
+		enc := json.NewEncoder(g)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(m)
+		if err != nil {
+			return
+		}
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	f, err := gCtx.Open(fileName(doc, gOpts.Format))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	_, err = g.WriteTo(f)
+	return
+}
+
+// fileName returns the name of the manifest generated for a document.
+func fileName(doc *ast.Document, format string) string {
+	base := filepath.Base(doc.Name)
+	name := base[:len(base)-len(filepath.Ext(base))]
+	if name == "" {
+		name = "schema"
+	}
+	return name + ".gateway." + strings.ToLower(format)
+}
+
+// BuildManifest routes every root field of a schema to a service. A field
+// is routed by its own @service, or else that of its root type, or else
+// def, which is the service of the whole schema, if it has one. Services
+// are listed in the order they're first routed to, and every root field
+// must be routed to one.
+//
+func BuildManifest(s *ir.Schema, def *Service) (*Manifest, error) {
+	m := &Manifest{Services: []*Service{}}
+	byName := make(map[string]*Service)
+
+	roots := []struct {
+		typ    *ir.Named
+		routes func(*Service) *[]string
+	}{
+		{s.Query(), func(svc *Service) *[]string { return &svc.Routes.Query }},
+		{s.Mutation(), func(svc *Service) *[]string { return &svc.Routes.Mutation }},
+		{s.Subscription(), func(svc *Service) *[]string { return &svc.Routes.Subscription }},
+	}
+	for _, root := range roots {
+		if root.typ == nil {
+			continue
+		}
+
+		typDef := def
+		if d := findDirective(root.typ.Directives()); d != nil {
+			svc, err := service(d.Arg)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", root.typ.Name(), err)
+			}
+			typDef = svc
+		}
+
+		for _, f := range root.typ.Fields() {
+			svc := typDef
+			if d := findDirective(f.Directives()); d != nil {
+				var err error
+				svc, err = service(d.Arg)
+				if err != nil {
+					return nil, fmt.Errorf("%s.%s: %s", root.typ.Name(), f.Name(), err)
+				}
+			}
+			if svc == nil {
+				return nil, fmt.Errorf("%s.%s isn't routed to a service, see @%s", root.typ.Name(), f.Name(), serviceDirective)
+			}
+
+			routed, ok := byName[svc.Name]
+			switch {
+			case !ok:
+				routed = &Service{Name: svc.Name, URL: svc.URL}
+				byName[svc.Name] = routed
+				m.Services = append(m.Services, routed)
+			case routed.URL != svc.URL:
+				return nil, fmt.Errorf("%s.%s: service %s is at both %s and %s", root.typ.Name(), f.Name(), svc.Name, routed.URL, svc.URL)
+			}
+
+			routes := root.routes(routed)
+			*routes = append(*routes, f.Name())
+		}
+	}
+	return m, nil
+}
+
+func findDirective(dirs []*ir.Directive) *ir.Directive {
+	for _, d := range dirs {
+		if d.Name() == serviceDirective {
+			return d
+		}
+	}
+	return nil
+}
+
+// service returns the service given by the arguments of a @service
+// directive, which arg looks up in GraphQL syntax.
+//
+func service(arg func(string) (string, bool)) (*Service, error) {
+	v, _ := arg("url")
+	rawURL := unquote(v)
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid service url: %q, expected an absolute url", rawURL)
+	}
+
+	svc := &Service{Name: u.Hostname(), URL: rawURL}
+	if name, ok := arg("name"); ok {
+		svc.Name = unquote(name)
+	}
+	return svc, nil
+}
+
+// schemaService returns the service given by the @service directive of a
+// document's schema, if it has one.
+//
+func schemaService(doc *ast.Document) (*Service, error) {
+	if doc.Schema == nil {
+		return nil, nil
+	}
+
+	for _, d := range doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Directives {
+		if d.Name != serviceDirective || d.Args == nil {
+			continue
+		}
+
+		svc, err := service(func(name string) (string, bool) {
+			for _, a := range d.Args.Args {
+				if lit, ok := a.Value.(*ast.Arg_BasicLit); ok && a.Name.Name == name {
+					return lit.BasicLit.Value, true
+				}
+			}
+			return "", false
+		})
+		if err != nil {
+			return nil, fmt.Errorf("schema: %s", err)
+		}
+		return svc, nil
+	}
+	return nil, nil
+}
+
+// unquote returns the value of a GraphQL string.
+func unquote(s string) string {
+	if v, err := strconv.Unquote(s); err == nil {
+		return v
+	}
+	return strings.Trim(s, `"`)
+}
+
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Format: YAML,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "gateway" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		gwOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range gwOpts.Fields {
+			switch arg.Key.Name {
+			case "format":
+				gOpts.Format = arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts != nil {
+		if f, ok := opts["format"].(string); ok {
+			gOpts.Format = f
+		}
+	}
+
+	gOpts.Format = strings.ToUpper(strings.Trim(gOpts.Format, `"`))
+	return
+}
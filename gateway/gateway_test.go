@@ -0,0 +1,233 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/ir"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "test.gateway.yaml", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, parser.ParseComments)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected gateway output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected gateway output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := new(Generator).Generate(ctx, testDoc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func TestBuildManifest(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Src  string
+		Ex   *Manifest
+		Err  string
+	}{
+		{
+			Name: "FieldOverridesType",
+			Src: `type Query @service(url: "http://a:4000/graphql") {
+	a: Int
+	b: Int @service(url: "http://b/graphql")
+	c: Int
+}`,
+			Ex: &Manifest{Services: []*Service{
+				{Name: "a", URL: "http://a:4000/graphql", Routes: Routes{Query: []string{"a", "c"}}},
+				{Name: "b", URL: "http://b/graphql", Routes: Routes{Query: []string{"b"}}},
+			}},
+		},
+		{
+			Name: "Named",
+			Src: `type Query {
+	a: Int @service(url: "http://a/graphql", name: "accounts")
+}
+
+type Mutation {
+	b: Int @service(url: "http://a/graphql", name: "accounts")
+}`,
+			Ex: &Manifest{Services: []*Service{
+				{Name: "accounts", URL: "http://a/graphql", Routes: Routes{Query: []string{"a"}, Mutation: []string{"b"}}},
+			}},
+		},
+		{
+			Name: "Unrouted",
+			Src: `type Query {
+	a: Int @service(url: "http://a/graphql")
+	b: Int
+}`,
+			Err: "Query.b isn't routed to a service, see @service",
+		},
+		{
+			Name: "InvalidURL",
+			Src: `type Query {
+	a: Int @service(url: "/graphql")
+}`,
+			Err: `Query.a: invalid service url: "/graphql", expected an absolute url`,
+		},
+		{
+			Name: "ConflictingURLs",
+			Src: `type Query {
+	a: Int @service(url: "http://a/graphql", name: "svc")
+	b: Int @service(url: "http://b/graphql", name: "svc")
+}`,
+			Err: "Query.b: service svc is at both http://a/graphql and http://b/graphql",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(testCase.Src), 0)
+			if err != nil {
+				subT.Fatal(err)
+			}
+			s, err := ir.Load(doc)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			m, err := BuildManifest(s, nil)
+			if testCase.Err != "" {
+				if err == nil || err.Error() != testCase.Err {
+					subT.Errorf("expected error: %s, but got: %v", testCase.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if len(m.Services) != len(testCase.Ex.Services) {
+				subT.Fatalf("expected %d services, but got: %d", len(testCase.Ex.Services), len(m.Services))
+			}
+			for i, svc := range m.Services {
+				ex := testCase.Ex.Services[i]
+				if svc.Name != ex.Name || svc.URL != ex.URL || strings.Join(svc.Routes.Query, ",") != strings.Join(ex.Routes.Query, ",") || strings.Join(svc.Routes.Mutation, ",") != strings.Join(ex.Routes.Mutation, ",") {
+					subT.Errorf("expected service: %+v, but got: %+v", ex, svc)
+				}
+			}
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := new(Generator).Generate(ctx, testDoc, map[string]interface{}{"format": "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ex := `"name": "users.example.com",
+      "url": "https://users.example.com/graphql",
+      "routes": {
+        "query": [
+          "me",
+          "user"
+        ],
+        "mutation": [
+          "rename"
+        ]
+      }
+    },`
+	if !strings.Contains(b.String(), ex) {
+		t.Errorf("expected output to contain:\n%s\nbut got:\n%s", ex, b.String())
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: new(bytes.Buffer)})
+	err := new(Generator).Generate(ctx, testDoc, map[string]interface{}{"format": "toml"})
+	if err == nil || !strings.Contains(err.Error(), "unknown gateway format: TOML") {
+		t.Errorf("expected unknown format error, but got: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+// types.go contains the GraphQL types this generator supports
+
+package gateway
+
+import (
+	"github.com/gqlc/gqlc/types"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+var gatewayTypes = []*ast.TypeDecl{
+	{
+		Tok: token.Token_DIRECTIVE,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "gateway"},
+			Type: &ast.TypeSpec_Directive{Directive: &ast.DirectiveType{
+				Locs: []*ast.DirectiveLocation{{Loc: ast.DirectiveLocation_DOCUMENT}},
+				Args: &ast.InputValueList{
+					List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "options"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "GatewayOptions"},
+							},
+						},
+					},
+				},
+			}},
+		}},
+	},
+	{
+		Tok: token.Token_INPUT,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "GatewayOptions"},
+			Type: &ast.TypeSpec_Input{Input: &ast.InputType{
+				Fields: &ast.InputValueList{
+					List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "format"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "GatewayFormat"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_IDENT,
+								Value: YAML,
+							}},
+						},
+					},
+				},
+			}},
+		}},
+	},
+	{
+		Tok: token.Token_ENUM,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "GatewayFormat"},
+			Type: &ast.TypeSpec_Enum{Enum: &ast.EnumType{
+				Values: &ast.FieldList{
+					List: []*ast.Field{
+						{
+							Name: &ast.Ident{Name: YAML},
+						},
+						{
+							Name: &ast.Ident{Name: JSON},
+						},
+					},
+				},
+			}},
+		}},
+	},
+	{
+		Tok: token.Token_DIRECTIVE,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: serviceDirective},
+			Type: &ast.TypeSpec_Directive{Directive: &ast.DirectiveType{
+				Locs: []*ast.DirectiveLocation{
+					{Loc: ast.DirectiveLocation_SCHEMA},
+					{Loc: ast.DirectiveLocation_OBJECT},
+					{Loc: ast.DirectiveLocation_FIELD_DEFINITION},
+				},
+				Args: &ast.InputValueList{
+					List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "url"},
+							Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{
+								Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "String"}},
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "name"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+						},
+					},
+				},
+			}},
+		}},
+	},
+}
+
+func init() {
+	types.Register(gatewayTypes...)
+}
@@ -0,0 +1,216 @@
+package gen
+
+import "github.com/gqlc/graphql/ast"
+
+// DefaultMaxDepth is how deeply type references, e.g. [[[Int!]!]!], and
+// values, e.g. default values of nested lists and objects, may nest by
+// default. Generators print them recursively, so a pathological document
+// could otherwise exhaust the stack.
+//
+const DefaultMaxDepth = 100
+
+// CheckDepth checks that the type references and values of a document nest
+// at most maxDepth levels deep. Unlike ValidateShape, it doesn't recurse,
+// so it's safe to run on any document before handing it to something which
+// does. A maxDepth of 0 means no limit. The returned error is ShapeErrors.
+//
+func CheckDepth(doc *ast.Document, maxDepth int) error {
+	if doc == nil || maxDepth <= 0 {
+		return nil
+	}
+
+	c := &depthChecker{shapeValidator{maxDepth: maxDepth}}
+	c.directives(doc.Directives)
+	for _, d := range doc.Types {
+		switch s := d.GetSpec().(type) {
+		case *ast.TypeDecl_TypeSpec:
+			c.typeSpec(s.TypeSpec)
+		case *ast.TypeDecl_TypeExtSpec:
+			if s.TypeExtSpec == nil {
+				continue
+			}
+			c.push("extend")
+			c.typeSpec(s.TypeExtSpec.Type)
+			c.pop()
+		}
+	}
+
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return c.errs
+}
+
+type depthChecker struct {
+	shapeValidator
+}
+
+func (c *depthChecker) typeSpec(ts *ast.TypeSpec) {
+	if ts == nil {
+		return
+	}
+	if _, ok := ts.Type.(*ast.TypeSpec_Schema); ok || ts.Name == nil {
+		c.push("schema")
+	} else {
+		c.push("type %s", ts.Name.Name)
+	}
+	defer c.pop()
+
+	c.directives(ts.Directives)
+	switch t := ts.Type.(type) {
+	case *ast.TypeSpec_Object:
+		c.fields(t.Object.GetFields())
+	case *ast.TypeSpec_Interface:
+		c.fields(t.Interface.GetFields())
+	case *ast.TypeSpec_Enum:
+		for _, v := range t.Enum.GetValues().GetList() {
+			c.push("value %s", v.GetName().GetName())
+			c.directives(v.GetDirectives())
+			c.pop()
+		}
+	case *ast.TypeSpec_Input:
+		c.inputValues(t.Input.GetFields(), "field")
+	case *ast.TypeSpec_Directive:
+		c.inputValues(t.Directive.GetArgs(), "arg")
+	}
+}
+
+func (c *depthChecker) fields(fields *ast.FieldList) {
+	for _, f := range fields.GetList() {
+		c.push("field %s", f.GetName().GetName())
+
+		var typ interface{}
+		switch t := f.GetType().(type) {
+		case *ast.Field_List:
+			typ = t.List
+		case *ast.Field_NonNull:
+			typ = t.NonNull
+		}
+		c.check("type", typeDepth(typ))
+
+		c.inputValues(f.GetArgs(), "arg")
+		c.directives(f.GetDirectives())
+		c.pop()
+	}
+}
+
+func (c *depthChecker) inputValues(vals *ast.InputValueList, kind string) {
+	for _, val := range vals.GetList() {
+		c.push("%s %s", kind, val.GetName().GetName())
+
+		var typ interface{}
+		switch t := val.GetType().(type) {
+		case *ast.InputValue_List:
+			typ = t.List
+		case *ast.InputValue_NonNull:
+			typ = t.NonNull
+		}
+		c.check("type", typeDepth(typ))
+
+		if d, ok := val.GetDefault().(*ast.InputValue_CompositeLit); ok {
+			c.check("value", valueDepth(d.CompositeLit))
+		}
+
+		c.directives(val.GetDirectives())
+		c.pop()
+	}
+}
+
+func (c *depthChecker) directives(dirs []*ast.DirectiveLit) {
+	for _, d := range dirs {
+		if d.GetArgs() == nil {
+			continue
+		}
+
+		c.push("@%s", d.Name)
+		for _, a := range d.Args.Args {
+			if lit, ok := a.GetValue().(*ast.Arg_CompositeLit); ok {
+				c.push("arg %s", a.GetName().GetName())
+				c.check("value", valueDepth(lit.CompositeLit))
+				c.pop()
+			}
+		}
+		c.pop()
+	}
+}
+
+func (c *depthChecker) check(kind string, depth int) {
+	if depth > c.maxDepth {
+		c.errorf("%s is nested %d levels deep, which exceeds the maximum depth of %d", kind, depth, c.maxDepth)
+	}
+}
+
+// typeDepth returns how many lists and non-nulls wrap a type reference.
+func typeDepth(typ interface{}) (depth int) {
+	for {
+		switch t := typ.(type) {
+		case *ast.List:
+			if t == nil {
+				return
+			}
+			depth++
+
+			switch e := t.Type.(type) {
+			case *ast.List_List:
+				typ = e.List
+			case *ast.List_NonNull:
+				typ = e.NonNull
+			default:
+				return
+			}
+		case *ast.NonNull:
+			if t == nil {
+				return
+			}
+			depth++
+
+			l, ok := t.Type.(*ast.NonNull_List)
+			if !ok {
+				return
+			}
+			typ = l.List
+		default:
+			return
+		}
+	}
+}
+
+// valueDepth returns how deeply the lists and objects of a value nest.
+func valueDepth(lit *ast.CompositeLit) (depth int) {
+	type level struct {
+		lit   *ast.CompositeLit
+		depth int
+	}
+
+	stack := []level{{lit, 0}}
+	for len(stack) > 0 {
+		l := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		var elems []*ast.CompositeLit
+		switch v := l.lit.GetValue().(type) {
+		case *ast.CompositeLit_ListLit:
+			if v.ListLit == nil {
+				continue
+			}
+			elems = v.ListLit.GetCompositeList().GetValues()
+		case *ast.CompositeLit_ObjLit:
+			if v.ObjLit == nil {
+				continue
+			}
+			for _, p := range v.ObjLit.Fields {
+				elems = append(elems, p.GetVal())
+			}
+		default:
+			continue
+		}
+
+		if l.depth+1 > depth {
+			depth = l.depth + 1
+		}
+		for _, e := range elems {
+			stack = append(stack, level{e, l.depth + 1})
+		}
+	}
+	return
+}
@@ -0,0 +1,158 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gqlc/graphql/ast"
+)
+
+// deepType returns a type reference of n lists, alternately non-null.
+func deepType(n int) *ast.Field {
+	var typ interface{} = &ast.Ident{Name: "Int"}
+	for i := 0; i < n; i++ {
+		switch t := typ.(type) {
+		case *ast.Ident:
+			typ = &ast.List{Type: &ast.List_Ident{Ident: t}}
+		case *ast.List:
+			if i%2 == 0 {
+				typ = &ast.List{Type: &ast.List_List{List: t}}
+				continue
+			}
+			typ = &ast.NonNull{Type: &ast.NonNull_List{List: t}}
+		case *ast.NonNull:
+			typ = &ast.List{Type: &ast.List_NonNull{NonNull: t}}
+		}
+	}
+
+	f := &ast.Field{Name: &ast.Ident{Name: "a"}}
+	switch t := typ.(type) {
+	case *ast.Ident:
+		f.Type = &ast.Field_Ident{Ident: t}
+	case *ast.List:
+		f.Type = &ast.Field_List{List: t}
+	case *ast.NonNull:
+		f.Type = &ast.Field_NonNull{NonNull: t}
+	}
+	return f
+}
+
+// deepValue returns a value of n lists, or objects, nested in each other.
+func deepValue(n int) *ast.CompositeLit {
+	lit := &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Value: "1"}}}
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			lit = &ast.CompositeLit{Value: &ast.CompositeLit_ListLit{ListLit: &ast.ListLit{
+				List: &ast.ListLit_CompositeList{CompositeList: &ast.ListLit_Composite{Values: []*ast.CompositeLit{lit}}},
+			}}}
+			continue
+		}
+		lit = &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+			Fields: []*ast.ObjLit_Pair{{Key: &ast.Ident{Name: "a"}, Val: lit}},
+		}}}
+	}
+	return lit
+}
+
+// deepDoc returns a document whose field type, and argument default, nest
+// n levels deep.
+//
+func deepDoc(n int) *ast.Document {
+	f := deepType(n)
+	f.Args = &ast.InputValueList{List: []*ast.InputValue{{
+		Name:    &ast.Ident{Name: "b"},
+		Type:    &ast.InputValue_Ident{Ident: &ast.Ident{Name: "JSON"}},
+		Default: &ast.InputValue_CompositeLit{CompositeLit: deepValue(n)},
+	}}}
+	return &ast.Document{Name: "deep", Types: []*ast.TypeDecl{object("Query", f)}}
+}
+
+func TestCheckDepth(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		Depth int
+		Max   int
+		Errs  []string
+	}{
+		{Name: "Shallow", Depth: 3, Max: DefaultMaxDepth},
+		{Name: "AtMax", Depth: 10, Max: 10},
+		{
+			Name:  "TooDeep",
+			Depth: 11,
+			Max:   10,
+			Errs: []string{
+				"type Query > field a: type is nested 11 levels deep, which exceeds the maximum depth of 10",
+				"type Query > field a > arg b: value is nested 11 levels deep, which exceeds the maximum depth of 10",
+			},
+		},
+		{Name: "NoLimit", Depth: 1000, Max: 0},
+		{
+			Name:  "Pathological",
+			Depth: 100000,
+			Max:   DefaultMaxDepth,
+			Errs: []string{
+				"type Query > field a: type is nested 100000 levels deep, which exceeds the maximum depth of 100",
+				"type Query > field a > arg b: value is nested 100000 levels deep, which exceeds the maximum depth of 100",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			err := CheckDepth(deepDoc(testCase.Depth), testCase.Max)
+			if len(testCase.Errs) == 0 {
+				if err != nil {
+					subT.Fatal(err)
+				}
+				return
+			}
+
+			if err == nil {
+				subT.Fatal("expected an error")
+			}
+			errs := err.(ShapeErrors)
+			if len(errs) != len(testCase.Errs) {
+				subT.Fatalf("expected %d errors, but got: %s", len(testCase.Errs), err)
+			}
+			for i, e := range errs {
+				if e.Error() != testCase.Errs[i] {
+					subT.Errorf("expected %q, but got: %q", testCase.Errs[i], e)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateShapeDepth(t *testing.T) {
+	// The validator stops descending at the limit, so it's safe to give
+	// a pathological document.
+	//
+	err := ValidateShape(deepDoc(100000))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	errs := err.(ShapeErrors)
+	expected := [][2]string{
+		{"type Query > field a", "type is nested deeper than the maximum depth of 100"},
+		{"type Query > field a > arg b", "value is nested deeper than the maximum depth of 100"},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, but got: %s", len(expected), err)
+	}
+	for i, e := range errs {
+		if !strings.HasPrefix(e.Path, expected[i][0]) || e.Msg != expected[i][1] {
+			t.Errorf("expected %s: %s, but got: %s", expected[i][0], expected[i][1], e)
+		}
+	}
+
+	if err = ValidateShapeDepth(deepDoc(50), 50); err != nil {
+		t.Error(err)
+	}
+	if err = ValidateShapeDepth(deepDoc(51), 50); err == nil {
+		t.Error("expected a document deeper than the limit to be invalid")
+	}
+	if err = ValidateShapeDepth(deepDoc(1000), 0); err != nil {
+		t.Error(err)
+	}
+}
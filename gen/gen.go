@@ -5,10 +5,15 @@ package gen
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"path/filepath"
 
+	"github.com/gqlc/gqlc/diag"
 	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
 )
 
 // Generator provides a simple API for creating a code generator for
@@ -16,6 +21,9 @@ import (
 //
 type Generator interface {
 	// Generate handles converting a GraphQL Document to scaffolded source code.
+	// It may be called for several documents at once, so any state needed while
+	// generating should be kept per call.
+	//
 	Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error
 }
 
@@ -27,6 +35,179 @@ type GeneratorContext interface {
 	Open(filename string) (io.WriteCloser, error)
 }
 
+// PathContext is a GeneratorContext which knows where it writes to, and
+// where documents were read from, so generators can refer to both by path.
+//
+type PathContext interface {
+	GeneratorContext
+
+	// Dir returns the absolute path of the directory files are opened in.
+	Dir() string
+
+	// Source returns the absolute path of the file a document was parsed
+	// from, or "" if it isn't known.
+	Source(doc *ast.Document) string
+
+	// ReadFile reads a file from the file system files are written to.
+	ReadFile(name string) ([]byte, error)
+}
+
+// PositionContext is a GeneratorContext which can resolve the token
+// positions of the documents being generated, e.g. TypeDecl.TokPos.
+//
+type PositionContext interface {
+	GeneratorContext
+
+	// Position returns the position in its source file of a token.
+	Position(pos token.Pos) token.Position
+}
+
+// ScaffoldContext is a GeneratorContext which can scaffold files, e.g.
+// resolver implementations, which are only written if they don't exist
+// yet, so they're safe to edit, unlike the rest of the generated files.
+//
+type ScaffoldContext interface {
+	GeneratorContext
+
+	// Scaffold opens a file to scaffold, or returns ErrScaffolded if it
+	// already exists.
+	//
+	Scaffold(filename string) (io.WriteCloser, error)
+}
+
+// ErrScaffolded is returned when scaffolding a file which already exists.
+var ErrScaffolded = errors.New("gen: file has already been scaffolded")
+
+// Scaffold opens a file which is only written if it doesn't exist yet, and
+// returns ErrScaffolded if it does. Contexts which can't scaffold files
+// are checked for the file with PathContext.ReadFile, if they can be.
+//
+func Scaffold(gCtx GeneratorContext, filename string) (io.WriteCloser, error) {
+	switch ctx := gCtx.(type) {
+	case ScaffoldContext:
+		return ctx.Scaffold(filename)
+	case PathContext:
+		if _, err := ctx.ReadFile(filepath.Join(ctx.Dir(), filename)); err == nil {
+			return nil, ErrScaffolded
+		}
+	}
+	return gCtx.Open(filename)
+}
+
+// OperationDocument is an executable document, i.e. the operations and
+// fragments of clients, as it was written. Generators are only given the
+// type system definitions of a schema as an *ast.Document, which can't
+// hold executable definitions.
+//
+type OperationDocument struct {
+	// Name is the path the document was read from.
+	Name string
+
+	// Source is the document itself.
+	Source string
+}
+
+// OperationContext is a GeneratorContext which carries the executable
+// documents given alongside the schema, e.g. for generating typed clients
+// of its operations.
+//
+type OperationContext interface {
+	GeneratorContext
+
+	// Operations returns the executable documents, which have been
+	// validated against the schema being generated.
+	//
+	Operations() []OperationDocument
+}
+
+// Operations returns the executable documents carried by a context, or nil
+// if it doesn't carry any.
+//
+func Operations(gCtx GeneratorContext) []OperationDocument {
+	ctx, ok := gCtx.(OperationContext)
+	if !ok {
+		return nil
+	}
+	return ctx.Operations()
+}
+
+// DocumentContext is a GeneratorContext which carries every document being
+// generated together, so generators can refer to the types of the other
+// documents, e.g. to link to their documentation.
+//
+type DocumentContext interface {
+	GeneratorContext
+
+	// Documents returns the documents being generated, including the
+	// one currently being generated.
+	//
+	Documents() []*ast.Document
+}
+
+// Documents returns the documents being generated together, as carried by
+// a context, or nil if it doesn't carry them.
+//
+func Documents(gCtx GeneratorContext) []*ast.Document {
+	ctx, ok := gCtx.(DocumentContext)
+	if !ok {
+		return nil
+	}
+	return ctx.Documents()
+}
+
+// DepthContext is a GeneratorContext which limits how deeply the types
+// and values of documents may nest, e.g. as configured by a user.
+//
+type DepthContext interface {
+	GeneratorContext
+
+	// MaxDepth returns the maximum depth, or 0 for no limit.
+	MaxDepth() int
+}
+
+// MaxDepth returns the maximum depth of types and values carried by a
+// context, or DefaultMaxDepth if it doesn't carry one.
+//
+func MaxDepth(gCtx GeneratorContext) int {
+	ctx, ok := gCtx.(DepthContext)
+	if !ok {
+		return DefaultMaxDepth
+	}
+	return ctx.MaxDepth()
+}
+
+// DiagnosticContext is a GeneratorContext which reports diagnostics e.g.
+// warnings about what a generator couldn't do, alongside those found while
+// compiling, so they reach every report format.
+//
+type DiagnosticContext interface {
+	GeneratorContext
+
+	// Report reports a diagnostic about a document. If its position has no
+	// file, it's given the file the document was parsed from.
+	//
+	Report(doc *ast.Document, d diag.Diagnostic)
+}
+
+// Warnf reports a warning about a document, at pos if it's known, through
+// a context if it reports diagnostics, and logs it otherwise.
+//
+func Warnf(gCtx GeneratorContext, doc *ast.Document, pos token.Pos, format string, args ...interface{}) {
+	d := diag.Warningf(diag.Pos{}, "generate", format, args...)
+
+	if pCtx, ok := gCtx.(PositionContext); ok && pos.IsValid() {
+		p := pCtx.Position(pos)
+		d.Pos.Line, d.Pos.Column = p.Line, p.Column
+	}
+
+	dCtx, ok := gCtx.(DiagnosticContext)
+	if !ok {
+		zap.L().Warn(d.Msg, zap.String("doc", doc.Name))
+		return
+	}
+	dCtx.Report(doc, *d)
+}
+
 type genCtx string
 
 var genCtxKey = genCtx("genCtx")
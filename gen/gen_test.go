@@ -0,0 +1,59 @@
+package gen
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/diag"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+// posDiagCtx is a TestDiagnosticCtx, which also implements PositionContext.
+type posDiagCtx struct {
+	TestDiagnosticCtx
+
+	dset *token.DocSet
+}
+
+func (ctx posDiagCtx) Position(pos token.Pos) token.Position { return ctx.dset.Position(pos) }
+
+func TestWarnf(t *testing.T) {
+	dset := token.NewDocSet()
+	doc, err := parser.ParseDoc(dset, "test", strings.NewReader("type Query {\n  a: String\n}\n\ntype User {\n  id: ID\n}\n"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := token.Pos(doc.Types[1].TokPos)
+
+	t.Run("Positioned", func(subT *testing.T) {
+		var diags []diag.Diagnostic
+		ctx := posDiagCtx{TestDiagnosticCtx: TestDiagnosticCtx{TestCtx: TestCtx{Writer: ioutil.Discard}, Diags: &diags}, dset: dset}
+
+		Warnf(ctx, doc, user, "%s is skipped", "User")
+
+		ex := []diag.Diagnostic{{Severity: diag.Warning, Code: "generate", Pos: diag.Pos{File: "test", Line: 5, Column: 1}, Msg: "User is skipped"}}
+		if !reflect.DeepEqual(diags, ex) {
+			subT.Errorf("expected: %v, but got: %v", ex, diags)
+		}
+	})
+
+	t.Run("NoPositions", func(subT *testing.T) {
+		var diags []diag.Diagnostic
+		ctx := TestDiagnosticCtx{TestCtx: TestCtx{Writer: ioutil.Discard}, Diags: &diags}
+
+		Warnf(ctx, doc, user, "%s is skipped", "User")
+
+		ex := []diag.Diagnostic{{Severity: diag.Warning, Code: "generate", Pos: diag.Pos{File: "test"}, Msg: "User is skipped"}}
+		if !reflect.DeepEqual(diags, ex) {
+			subT.Errorf("expected: %v, but got: %v", ex, diags)
+		}
+	})
+
+	t.Run("NotReported", func(subT *testing.T) {
+		// It's logged instead, so this only checks that it doesn't panic.
+		Warnf(TestCtx{Writer: ioutil.Discard}, doc, user, "%s is skipped", "User")
+	})
+}
@@ -0,0 +1,205 @@
+package gen
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ImportPath returns the Go import path of a directory, which is found from
+// the go.mod file of the module enclosing it. An empty path is returned if
+// the directory isn't part of a module.
+//
+func ImportPath(ctx PathContext, dir string) (string, error) {
+	var rel []string
+	for {
+		b, err := ctx.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			mod := modulePath(b)
+			if mod == "" {
+				return "", nil
+			}
+
+			for i, j := 0, len(rel)-1; i < j; i, j = i+1, j-1 {
+				rel[i], rel[j] = rel[j], rel[i]
+			}
+			return path.Join(append([]string{mod}, rel...)...), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		rel = append(rel, filepath.Base(dir))
+		dir = parent
+	}
+}
+
+// modulePath returns the module path declared by a go.mod file.
+func modulePath(mod []byte) string {
+	s := bufio.NewScanner(bytes.NewReader(mod))
+	for s.Scan() {
+		line := s.Text()
+		if i := strings.Index(line, "//"); i > -1 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "module" {
+			continue
+		}
+
+		if p, err := strconv.Unquote(fields[1]); err == nil {
+			return p
+		}
+		return fields[1]
+	}
+	return ""
+}
+
+// ModuleDir returns the directory of a Go import path, within the modules
+// of the go.work workspace enclosing dir, or the module enclosing dir if
+// it isn't in a workspace. The import path is provided by the module whose
+// path is its longest prefix, and an error is returned if there isn't one,
+// or if a module of the workspace doesn't exist.
+//
+func ModuleDir(ctx PathContext, dir, importPath string) (string, error) {
+	mods, err := workspaceModules(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+
+	var mod module
+	for _, m := range mods {
+		if len(m.path) <= len(mod.path) {
+			continue
+		}
+		if importPath == m.path || strings.HasPrefix(importPath, m.path+"/") {
+			mod = m
+		}
+	}
+	if mod.path == "" {
+		return "", fmt.Errorf("no module provides %s", importPath)
+	}
+
+	rel := strings.TrimPrefix(strings.TrimPrefix(importPath, mod.path), "/")
+	return filepath.Join(mod.dir, filepath.FromSlash(rel)), nil
+}
+
+// module is a Go module, found in dir.
+type module struct {
+	path string
+	dir  string
+}
+
+// workspaceModules returns the modules used by the go.work file enclosing
+// dir, or the module enclosing dir if there isn't one.
+//
+func workspaceModules(ctx PathContext, dir string) ([]module, error) {
+	for d := dir; ; {
+		work := filepath.Join(d, "go.work")
+		b, err := ctx.ReadFile(work)
+		if err == nil {
+			return readWorkspace(ctx, work, b)
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(d)
+		if parent == d {
+			break
+		}
+		d = parent
+	}
+
+	// Without a workspace, there's just the enclosing module
+	for d := dir; ; {
+		b, err := ctx.ReadFile(filepath.Join(d, "go.mod"))
+		if err == nil {
+			return []module{{path: modulePath(b), dir: d}}, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(d)
+		if parent == d {
+			return nil, fmt.Errorf("%s isn't in a Go module or workspace", dir)
+		}
+		d = parent
+	}
+}
+
+// readWorkspace reads the modules used by a go.work file, each of which
+// must have a go.mod file declaring its path.
+//
+func readWorkspace(ctx PathContext, work string, b []byte) ([]module, error) {
+	var mods []module
+	for _, dir := range workspaceUses(b) {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(work), filepath.FromSlash(dir))
+		}
+
+		mod, err := ctx.ReadFile(filepath.Join(dir, "go.mod"))
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s: module %s doesn't exist", work, dir)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p := modulePath(mod)
+		if p == "" {
+			return nil, fmt.Errorf("%s: module %s doesn't declare its path", work, dir)
+		}
+		mods = append(mods, module{path: p, dir: dir})
+	}
+	return mods, nil
+}
+
+// workspaceUses returns the directories of the use directives of a go.work
+// file, which are either on a single line or in a block.
+//
+func workspaceUses(work []byte) (dirs []string) {
+	s := bufio.NewScanner(bytes.NewReader(work))
+	inBlock := false
+	for s.Scan() {
+		line := s.Text()
+		if i := strings.Index(line, "//"); i > -1 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inBlock && fields[0] == ")":
+			inBlock = false
+			continue
+		case inBlock:
+		case fields[0] == "use" && len(fields) == 2 && fields[1] == "(":
+			inBlock = true
+			continue
+		case fields[0] == "use" && len(fields) == 2:
+			fields = fields[1:]
+		default:
+			continue
+		}
+
+		dir := fields[0]
+		if p, err := strconv.Unquote(dir); err == nil {
+			dir = p
+		}
+		dirs = append(dirs, dir)
+	}
+	return
+}
@@ -0,0 +1,172 @@
+// Package printer contains the buffer generators print their output to,
+// which keeps track of indentation and wraps long descriptions.
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Printer is a bytes.Buffer which prints indented lines. A zero Printer
+// indents by tabs and doesn't wrap.
+//
+type Printer struct {
+	bytes.Buffer
+
+	// Indent is what each level of indentation consists of, which is a
+	// tab if it's empty.
+	//
+	Indent string
+
+	// Width is the width lines printed by PrintWrapped are wrapped at,
+	// indentation included, or 0 to never wrap them.
+	//
+	Width int
+
+	// TabWidth is how many columns a tab counts as when wrapping, which
+	// is 8 if it's 0.
+	//
+	TabWidth int
+
+	indent []byte
+}
+
+// Reset resets the buffer and indentation.
+func (p *Printer) Reset() {
+	p.Buffer.Reset()
+	p.indent = p.indent[:0]
+}
+
+// P prints the arguments to the output as an indented line. Blank lines,
+// i.e. P(), aren't indented.
+//
+func (p *Printer) P(str ...interface{}) {
+	if len(str) > 0 {
+		p.Write(p.indent)
+	}
+	for _, s := range str {
+		switch v := s.(type) {
+		case []byte:
+			p.Write(v)
+		case byte:
+			p.WriteByte(v)
+		case rune:
+			p.WriteRune(v)
+		case string:
+			p.WriteString(v)
+		default:
+			fmt.Fprint(p, v)
+		}
+	}
+	p.WriteByte('\n')
+}
+
+// In increases the indentation.
+func (p *Printer) In() {
+	if p.Indent == "" {
+		p.indent = append(p.indent, '\t')
+		return
+	}
+	p.indent = append(p.indent, p.Indent...)
+}
+
+// Out decreases the indentation.
+func (p *Printer) Out() {
+	n := len(p.Indent)
+	if n == 0 {
+		n = 1
+	}
+	if len(p.indent) < n {
+		n = len(p.indent)
+	}
+	p.indent = p.indent[:len(p.indent)-n]
+}
+
+// WriteIndent writes the current indentation, for lines which aren't
+// printed all at once with P.
+//
+func (p *Printer) WriteIndent() {
+	p.Write(p.indent)
+}
+
+// Indentation returns a copy of the current indentation.
+func (p *Printer) Indentation() []byte {
+	return append([]byte(nil), p.indent...)
+}
+
+// SetIndentation sets the current indentation e.g. to continue printing
+// from where another Printer is at.
+//
+func (p *Printer) SetIndentation(indent []byte) {
+	p.indent = append(p.indent[:0], indent...)
+}
+
+// PrintWrapped prints text as indented lines which start with prefix,
+// wrapping it at Width. Line breaks in text are kept.
+//
+func (p *Printer) PrintWrapped(prefix, text string) {
+	width := 0
+	if p.Width > 0 {
+		width = p.Width - p.columns(string(p.indent)+prefix)
+		if width < 1 {
+			width = 1
+		}
+	}
+
+	for _, line := range Wrap(text, width) {
+		p.P(strings.TrimRight(prefix+line, " \t"))
+	}
+}
+
+func (p *Printer) columns(s string) int {
+	tab := p.TabWidth
+	if tab == 0 {
+		tab = 8
+	}
+
+	n := 0
+	for _, r := range s {
+		if r == '\t' {
+			n += tab - n%tab
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+// Wrap splits text into lines no wider than width, breaking them between
+// words. Line breaks in text are kept and words wider than width are left
+// whole. A width of 0 or less only splits text at its line breaks.
+//
+func Wrap(text string, width int) []string {
+	lines := strings.Split(text, "\n")
+	if width <= 0 {
+		return lines
+	}
+
+	var wrapped []string
+	for _, line := range lines {
+		words := strings.Fields(line)
+		if len(words) == 0 {
+			wrapped = append(wrapped, "")
+			continue
+		}
+
+		cur, n := words[0], utf8.RuneCountInString(words[0])
+		for _, w := range words[1:] {
+			wn := utf8.RuneCountInString(w)
+			if n+1+wn > width {
+				wrapped = append(wrapped, cur)
+				cur, n = w, wn
+				continue
+			}
+			cur += " " + w
+			n += 1 + wn
+		}
+		wrapped = append(wrapped, cur)
+	}
+	return wrapped
+}
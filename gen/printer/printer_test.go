@@ -0,0 +1,128 @@
+package printer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrinter(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Indent string
+		Ex     string
+	}{
+		{
+			Name: "Tabs",
+			Ex:   "a {\n\tb 1 true\n\t\tc\n\td\n}\n",
+		},
+		{
+			Name:   "Spaces",
+			Indent: "  ",
+			Ex:     "a {\n  b 1 true\n    c\n  d\n}\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			p := &Printer{Indent: testCase.Indent}
+			p.P("a {")
+			p.In()
+			p.P("b ", 1, " ", true)
+			p.In()
+			p.P([]byte("c"))
+			p.Out()
+			p.WriteIndent()
+			p.WriteString("d\n")
+			p.Out()
+			p.Out()
+			p.P('}')
+
+			if p.String() != testCase.Ex {
+				subT.Errorf("expected %q, but got: %q", testCase.Ex, p.String())
+			}
+		})
+	}
+}
+
+func TestPrinter_Reset(t *testing.T) {
+	p := new(Printer)
+	p.In()
+	p.P("a")
+	p.Reset()
+	p.P("b")
+
+	if p.String() != "b\n" {
+		t.Errorf("expected the indentation to be reset, but got: %q", p.String())
+	}
+}
+
+func TestPrinter_BlankLine(t *testing.T) {
+	p := new(Printer)
+	p.In()
+	p.P("a")
+	p.P()
+	p.P("b")
+
+	if p.String() != "\ta\n\n\tb\n" {
+		t.Errorf("expected blank lines to not be indented, but got: %q", p.String())
+	}
+}
+
+func TestPrinter_PrintWrapped(t *testing.T) {
+	p := &Printer{Width: 21}
+	p.In()
+	p.PrintWrapped("// ", "one two three four five\n\nsix")
+
+	ex := "\t// one two\n\t// three four\n\t// five\n\t//\n\t// six\n"
+	if p.String() != ex {
+		t.Errorf("expected %q, but got: %q", ex, p.String())
+	}
+}
+
+func TestWrap(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		Text  string
+		Width int
+		Ex    []string
+	}{
+		{
+			Name: "NoWidth",
+			Text: "a b c\nd",
+			Ex:   []string{"a b c", "d"},
+		},
+		{
+			Name:  "Words",
+			Text:  "the quick brown fox",
+			Width: 10,
+			Ex:    []string{"the quick", "brown fox"},
+		},
+		{
+			Name:  "LongWord",
+			Text:  "a extraordinarily b",
+			Width: 5,
+			Ex:    []string{"a", "extraordinarily", "b"},
+		},
+		{
+			Name:  "LineBreaks",
+			Text:  "a b\n\nc",
+			Width: 1,
+			Ex:    []string{"a", "b", "", "c"},
+		},
+		{
+			Name:  "Runes",
+			Text:  "héllo wörld",
+			Width: 11,
+			Ex:    []string{"héllo wörld"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			lines := Wrap(testCase.Text, testCase.Width)
+			if !reflect.DeepEqual(lines, testCase.Ex) {
+				subT.Errorf("expected %q, but got: %q", testCase.Ex, lines)
+			}
+		})
+	}
+}
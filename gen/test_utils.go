@@ -3,7 +3,12 @@ package gen
 import (
 	"bytes"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/gqlc/gqlc/diag"
+	"github.com/gqlc/graphql/ast"
 )
 
 // TestCtx is a noop closer, which wraps an io.Writer
@@ -19,6 +24,76 @@ func (ctx TestCtx) Open(filename string) (io.WriteCloser, error) { return ctx, n
 // Close always returns nil.
 func (ctx TestCtx) Close() error { return nil }
 
+// TestPathCtx is a TestCtx, which also implements PathContext.
+// Files are only read from the Files map.
+//
+type TestPathCtx struct {
+	TestCtx
+
+	// DirPath is returned by Dir.
+	DirPath string
+
+	// Sources maps document names to their source files.
+	Sources map[string]string
+
+	// Files maps file names to their contents.
+	Files map[string][]byte
+}
+
+// Dir returns ctx.DirPath.
+func (ctx TestPathCtx) Dir() string { return ctx.DirPath }
+
+// Source returns the source file of the document from ctx.Sources.
+func (ctx TestPathCtx) Source(doc *ast.Document) string { return ctx.Sources[doc.Name] }
+
+// ReadFile returns the contents of the named file from ctx.Files.
+func (ctx TestPathCtx) ReadFile(name string) ([]byte, error) {
+	b, ok := ctx.Files[filepath.ToSlash(name)]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return b, nil
+}
+
+// TestDiagnosticCtx is a TestCtx, which also implements DiagnosticContext.
+// Diagnostics are appended to Diags.
+//
+type TestDiagnosticCtx struct {
+	TestCtx
+
+	Diags *[]diag.Diagnostic
+}
+
+// Report appends the diagnostic to ctx.Diags.
+func (ctx TestDiagnosticCtx) Report(doc *ast.Document, d diag.Diagnostic) {
+	if !d.Pos.IsValid() {
+		d.Pos.File = doc.Name
+	}
+	*ctx.Diags = append(*ctx.Diags, d)
+}
+
+// TestOperationCtx is a TestCtx, which also implements OperationContext.
+type TestOperationCtx struct {
+	TestCtx
+
+	// Docs is returned by Operations.
+	Docs []OperationDocument
+}
+
+// Operations returns ctx.Docs.
+func (ctx TestOperationCtx) Operations() []OperationDocument { return ctx.Docs }
+
+// TestDocumentCtx is a TestCtx, which also implements DocumentContext.
+type TestDocumentCtx struct {
+	TestCtx
+
+	// Docs is returned by Documents.
+	Docs []*ast.Document
+}
+
+// Documents returns ctx.Docs.
+func (ctx TestDocumentCtx) Documents() []*ast.Document { return ctx.Docs }
+
 // CompareBytes is a testing utility for comparing generator outputs.
 func CompareBytes(t *testing.T, ex, out []byte) {
 	t.Helper()
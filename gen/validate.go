@@ -0,0 +1,762 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gqlc/gqlc/types"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+// ShapeError describes an AST node which isn't shaped the way generators
+// expect it to be e.g. a field without a name.
+//
+type ShapeError struct {
+	// Path locates the node e.g. type Query > field user > arg id
+	Path string
+	Msg  string
+
+	// Pos is the token position of the node, if it's known, and Position
+	// is where that is in its source file, once it's been resolved.
+	//
+	Pos      token.Pos
+	Position token.Position
+}
+
+func (e *ShapeError) Error() string {
+	msg := e.Msg
+	if e.Path != "" {
+		msg = e.Path + ": " + msg
+	}
+	if e.Position.IsValid() {
+		msg = e.Position.String() + ": " + msg
+	}
+	return msg
+}
+
+// ShapeErrors are all the problems found in a document.
+type ShapeErrors []*ShapeError
+
+func (errs ShapeErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ValidateShape checks that a document is shaped like those produced by
+// the parser, which every generator relies on: nodes which must be named
+// are named, every oneof is set to one of its cases, and generator options
+// are object literals, whose values are of the types they're declared as.
+// Documents built by hand or decoded from a plugin
+// request should be validated, so a malformed one is reported instead of
+// panicking a generator. Type references and values may nest at most
+// DefaultMaxDepth levels deep. The returned error is ShapeErrors.
+//
+func ValidateShape(doc *ast.Document) error {
+	return ValidateShapeDepth(doc, DefaultMaxDepth)
+}
+
+// ValidateShapeContext is ValidateShapeDepth, with the maximum depth of a
+// generator context. Errors are positioned in their source files, if it's
+// a PositionContext.
+//
+func ValidateShapeContext(gCtx GeneratorContext, doc *ast.Document) error {
+	err := ValidateShapeDepth(doc, MaxDepth(gCtx))
+	pCtx, ok := gCtx.(PositionContext)
+	if err == nil || !ok {
+		return err
+	}
+
+	for _, e := range err.(ShapeErrors) {
+		if e.Pos > 0 {
+			e.Position = pCtx.Position(e.Pos)
+		}
+	}
+	return err
+}
+
+// ValidateShapeDepth is ValidateShape, but with type references and values
+// limited to maxDepth levels instead of DefaultMaxDepth. A maxDepth of 0
+// means no limit.
+//
+func ValidateShapeDepth(doc *ast.Document, maxDepth int) error {
+	v := &shapeValidator{maxDepth: maxDepth}
+	if doc == nil {
+		v.errorf("missing document")
+		return v.errs
+	}
+
+	for _, d := range doc.Directives {
+		v.directive(d, true)
+	}
+
+	// The parser also lists the schema with the types
+	schema := doc.Schema != nil
+	for _, d := range doc.Types {
+		if d == doc.Schema {
+			schema = false
+		}
+	}
+	if schema {
+		v.decl(doc.Schema)
+	}
+
+	for i, d := range doc.Types {
+		if d == nil {
+			v.errorf("missing type declaration %d", i)
+			continue
+		}
+		v.decl(d)
+	}
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+type shapeValidator struct {
+	path []string
+	errs ShapeErrors
+
+	// depth is how deeply nested the current type, or value, is
+	depth    int
+	maxDepth int
+}
+
+func (v *shapeValidator) push(format string, args ...interface{}) {
+	v.path = append(v.path, fmt.Sprintf(format, args...))
+}
+
+func (v *shapeValidator) pop() { v.path = v.path[:len(v.path)-1] }
+
+// enter descends into a list, non-null or object, unless doing so would
+// exceed the maximum depth, in which case it's reported instead.
+//
+func (v *shapeValidator) enter(kind string) bool {
+	if v.maxDepth > 0 && v.depth >= v.maxDepth {
+		v.errorf("%s is nested deeper than the maximum depth of %d", kind, v.maxDepth)
+		return false
+	}
+	v.depth++
+	return true
+}
+
+func (v *shapeValidator) leave() { v.depth-- }
+
+func (v *shapeValidator) errorf(format string, args ...interface{}) {
+	v.errorAt(0, format, args...)
+}
+
+// errorAt reports an error at the token position of the current node.
+func (v *shapeValidator) errorAt(pos int64, format string, args ...interface{}) {
+	v.errs = append(v.errs, &ShapeError{
+		Path: strings.Join(v.path, " > "),
+		Msg:  fmt.Sprintf(format, args...),
+		Pos:  token.Pos(pos),
+	})
+}
+
+func (v *shapeValidator) decl(d *ast.TypeDecl) {
+	switch s := d.Spec.(type) {
+	case *ast.TypeDecl_TypeSpec:
+		if s.TypeSpec == nil {
+			v.errorf("missing type spec")
+			return
+		}
+		v.typeSpec(s.TypeSpec, false)
+	case *ast.TypeDecl_TypeExtSpec:
+		if s.TypeExtSpec == nil || s.TypeExtSpec.Type == nil {
+			v.errorf("missing type extension spec")
+			return
+		}
+
+		v.push("extend")
+		v.typeSpec(s.TypeExtSpec.Type, true)
+		v.pop()
+	default:
+		v.errorf("type declaration has no spec")
+	}
+}
+
+// typeSpec checks a type definition. Unlike an extension, a definition
+// must declare its fields or values.
+//
+func (v *shapeValidator) typeSpec(ts *ast.TypeSpec, ext bool) {
+	_, isSchema := ts.Type.(*ast.TypeSpec_Schema)
+	switch {
+	case isSchema:
+		v.push("schema")
+	case !v.name(ts.Name):
+		v.errorf("type has no name")
+		return
+	default:
+		v.push("type %s", ts.Name.Name)
+	}
+	defer v.pop()
+
+	for _, d := range ts.Directives {
+		v.directive(d, false)
+	}
+
+	switch t := ts.Type.(type) {
+	case *ast.TypeSpec_Schema:
+		// An extension can apply directives to the schema alone
+		if ext && t.Schema != nil && t.Schema.RootOps == nil {
+			return
+		}
+		if t.Schema == nil || t.Schema.RootOps == nil {
+			v.errorf("schema has no root operations")
+			return
+		}
+
+		for _, f := range t.Schema.RootOps.List {
+			v.field(f, "root operation")
+			if f == nil || !v.name(f.Name) {
+				continue
+			}
+
+			if id, ok := f.Type.(*ast.Field_Ident); !ok || !v.name(id.Ident) {
+				v.push("root operation %s", f.Name.Name)
+				v.errorf("root operation type must be a named type")
+				v.pop()
+			}
+		}
+	case *ast.TypeSpec_Scalar:
+		if t.Scalar == nil {
+			v.errorf("missing scalar")
+		}
+	case *ast.TypeSpec_Object:
+		if t.Object == nil {
+			v.errorf("missing object")
+			return
+		}
+		v.names(t.Object.Interfaces, "interface")
+		if t.Object.Fields == nil && !ext {
+			v.errorf("object has no fields")
+		}
+		v.fields(t.Object.Fields)
+	case *ast.TypeSpec_Interface:
+		if t.Interface == nil {
+			v.errorf("missing interface")
+			return
+		}
+		if t.Interface.Fields == nil && !ext {
+			v.errorf("interface has no fields")
+		}
+		v.fields(t.Interface.Fields)
+	case *ast.TypeSpec_Union:
+		if t.Union == nil {
+			v.errorf("missing union")
+			return
+		}
+		v.names(t.Union.Members, "member")
+	case *ast.TypeSpec_Enum:
+		if t.Enum == nil {
+			v.errorf("missing enum")
+			return
+		}
+		if t.Enum.Values == nil {
+			if !ext {
+				v.errorf("enum has no values")
+			}
+			return
+		}
+
+		for _, val := range t.Enum.Values.List {
+			if val == nil || !v.name(val.Name) {
+				v.errorf("enum value has no name")
+				continue
+			}
+
+			v.push("value %s", val.Name.Name)
+			for _, d := range val.Directives {
+				v.directive(d, false)
+			}
+			v.pop()
+		}
+	case *ast.TypeSpec_Input:
+		if t.Input == nil {
+			v.errorf("missing input")
+			return
+		}
+		if t.Input.Fields == nil && !ext {
+			v.errorf("input has no fields")
+		}
+		v.inputValues(t.Input.Fields, "field")
+	case *ast.TypeSpec_Directive:
+		if t.Directive == nil {
+			v.errorf("missing directive")
+			return
+		}
+		v.inputValues(t.Directive.Args, "arg")
+
+		for _, loc := range t.Directive.Locs {
+			if loc == nil {
+				v.errorf("missing directive location")
+			}
+		}
+	default:
+		v.errorf("type has no kind")
+	}
+}
+
+// name reports whether id is a non-empty identifier.
+func (v *shapeValidator) name(id *ast.Ident) bool { return id != nil && id.Name != "" }
+
+func (v *shapeValidator) names(ids []*ast.Ident, kind string) {
+	for _, id := range ids {
+		if !v.name(id) {
+			v.errorf("%s has no name", kind)
+		}
+	}
+}
+
+func (v *shapeValidator) fields(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+
+	for _, f := range fields.List {
+		v.field(f, "field")
+	}
+}
+
+func (v *shapeValidator) field(f *ast.Field, kind string) {
+	if f == nil || !v.name(f.Name) {
+		v.errorf("%s has no name", kind)
+		return
+	}
+
+	v.push("%s %s", kind, f.Name.Name)
+	defer v.pop()
+
+	switch t := f.Type.(type) {
+	case *ast.Field_Ident:
+		v.typ(t.Ident)
+	case *ast.Field_List:
+		v.typ(t.List)
+	case *ast.Field_NonNull:
+		v.typ(t.NonNull)
+	default:
+		v.errorf("missing type")
+	}
+
+	v.inputValues(f.Args, "arg")
+	for _, d := range f.Directives {
+		v.directive(d, false)
+	}
+}
+
+func (v *shapeValidator) inputValues(vals *ast.InputValueList, kind string) {
+	if vals == nil {
+		return
+	}
+
+	for _, val := range vals.List {
+		if val == nil || !v.name(val.Name) {
+			v.errorf("%s has no name", kind)
+			continue
+		}
+		v.push("%s %s", kind, val.Name.Name)
+
+		switch t := val.Type.(type) {
+		case *ast.InputValue_Ident:
+			v.typ(t.Ident)
+		case *ast.InputValue_List:
+			v.typ(t.List)
+		case *ast.InputValue_NonNull:
+			v.typ(t.NonNull)
+		default:
+			v.errorf("missing type")
+		}
+
+		switch d := val.Default.(type) {
+		case nil:
+		case *ast.InputValue_BasicLit:
+			v.basicLit(d.BasicLit)
+		case *ast.InputValue_CompositeLit:
+			v.compositeLit(d.CompositeLit)
+		}
+
+		for _, d := range val.Directives {
+			v.directive(d, false)
+		}
+		v.pop()
+	}
+}
+
+// typ checks a type reference, which must end in a named type.
+func (v *shapeValidator) typ(typ interface{}) {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		if !v.name(t) {
+			v.errorf("type has no name")
+		}
+	case *ast.List:
+		if t == nil {
+			v.errorf("missing list type")
+			return
+		}
+		if !v.enter("type") {
+			return
+		}
+		defer v.leave()
+
+		switch e := t.Type.(type) {
+		case *ast.List_Ident:
+			v.typ(e.Ident)
+		case *ast.List_List:
+			v.typ(e.List)
+		case *ast.List_NonNull:
+			v.typ(e.NonNull)
+		default:
+			v.errorf("list has no element type")
+		}
+	case *ast.NonNull:
+		if t == nil {
+			v.errorf("missing non-null type")
+			return
+		}
+		if !v.enter("type") {
+			return
+		}
+		defer v.leave()
+
+		switch e := t.Type.(type) {
+		case *ast.NonNull_Ident:
+			v.typ(e.Ident)
+		case *ast.NonNull_List:
+			v.typ(e.List)
+		default:
+			v.errorf("non-null has no type")
+		}
+	}
+}
+
+// directive checks an applied directive. Generators read their options
+// from a document directive's options argument, so it must be an object.
+// The args of directives registered by gqlc, e.g. options, must be of the
+// types they're declared as, since generators assert the types of the
+// values they read.
+//
+func (v *shapeValidator) directive(d *ast.DirectiveLit, onDoc bool) {
+	if d == nil || d.Name == "" {
+		v.errorf("directive has no name")
+		return
+	}
+	if d.Args == nil {
+		return
+	}
+
+	v.push("@%s", d.Name)
+	defer v.pop()
+
+	declared := directiveArgs(d.Name)
+	if _, ok := declared["options"]; ok && onDoc && len(d.Args.Args) == 0 {
+		v.errorAt(d.AtPos, "missing arg options")
+	}
+
+	for _, a := range d.Args.Args {
+		if a == nil || !v.name(a.Name) {
+			v.errorf("arg has no name")
+			continue
+		}
+		v.push("arg %s", a.Name.Name)
+
+		var lit *ast.CompositeLit
+		switch val := a.Value.(type) {
+		case *ast.Arg_BasicLit:
+			v.basicLit(val.BasicLit)
+			if onDoc && a.Name.Name == "options" {
+				v.errorAt(val.BasicLit.GetValuePos(), "options must be an object")
+				break
+			}
+			lit = &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: val.BasicLit}}
+		case *ast.Arg_CompositeLit:
+			v.compositeLit(val.CompositeLit)
+
+			if _, ok := val.CompositeLit.GetValue().(*ast.CompositeLit_ObjLit); onDoc && a.Name.Name == "options" && !ok {
+				v.errorAt(val.CompositeLit.GetOpening(), "options must be an object")
+				break
+			}
+			lit = val.CompositeLit
+		default:
+			v.errorf("missing value")
+		}
+
+		if declared != nil {
+			if typ, ok := declared[a.Name.Name]; !ok {
+				v.errorAt(d.AtPos, "unknown arg")
+			} else if lit != nil {
+				v.value(typ, lit)
+			}
+		}
+		v.pop()
+	}
+}
+
+// directiveArgs returns the types of the args of a directive registered by
+// gqlc, or nil if it isn't one.
+//
+func directiveArgs(name string) map[string]interface{} {
+	decl := types.Lookup(name)
+	if decl == nil {
+		return nil
+	}
+	ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+	if !ok {
+		return nil
+	}
+	dt, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Directive)
+	if !ok {
+		return nil
+	}
+
+	args := make(map[string]interface{})
+	if dt.Directive.Args != nil {
+		for _, a := range dt.Directive.Args.List {
+			args[a.Name.Name] = inputValueType(a)
+		}
+	}
+	return args
+}
+
+// value checks a value is of the type it's declared as: scalars are
+// literals of their kind, a single value may be given for a list, and
+// input objects are objects of their declared fields. Null isn't allowed,
+// since generators have no use for it. The shape of the value has already
+// been checked, so malformed parts of it are skipped.
+//
+func (v *shapeValidator) value(typ interface{}, lit *ast.CompositeLit) {
+	if lit == nil {
+		return
+	}
+
+	switch t := typ.(type) {
+	case *ast.NonNull:
+		switch e := t.GetType().(type) {
+		case *ast.NonNull_Ident:
+			v.value(e.Ident, lit)
+		case *ast.NonNull_List:
+			v.value(e.List, lit)
+		}
+	case *ast.List:
+		var elem interface{}
+		switch e := t.GetType().(type) {
+		case *ast.List_Ident:
+			elem = e.Ident
+		case *ast.List_List:
+			elem = e.List
+		case *ast.List_NonNull:
+			elem = e.NonNull
+		}
+
+		l, ok := lit.Value.(*ast.CompositeLit_ListLit)
+		if !ok {
+			v.value(elem, lit)
+			return
+		}
+
+		switch vals := l.ListLit.GetList().(type) {
+		case *ast.ListLit_BasicList:
+			for _, e := range vals.BasicList.GetValues() {
+				if e != nil {
+					v.value(elem, &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: e}})
+				}
+			}
+		case *ast.ListLit_CompositeList:
+			for _, e := range vals.CompositeList.GetValues() {
+				v.value(elem, e)
+			}
+		}
+	case *ast.Ident:
+		if t != nil {
+			v.namedValue(t.Name, lit)
+		}
+	}
+}
+
+// literalKinds are the kinds of literal each built-in scalar may be given.
+var literalKinds = map[string][]token.Token{
+	"Boolean": {token.Token_BOOL},
+	"Int":     {token.Token_INT},
+	"Float":   {token.Token_INT, token.Token_FLOAT},
+	"String":  {token.Token_STRING},
+	"ID":      {token.Token_STRING, token.Token_INT},
+}
+
+func (v *shapeValidator) namedValue(name string, lit *ast.CompositeLit) {
+	var kinds []token.Token
+	var input *ast.InputType
+	if k, ok := literalKinds[name]; ok {
+		kinds = k
+	} else if decl := types.Lookup(name); decl != nil {
+		ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			return
+		}
+
+		switch t := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Enum:
+			kinds = []token.Token{token.Token_IDENT, token.Token_STRING}
+		case *ast.TypeSpec_Input:
+			input = t.Input
+		default:
+			return
+		}
+	} else {
+		// Custom scalars may be given any value
+		return
+	}
+
+	switch val := lit.Value.(type) {
+	case *ast.CompositeLit_BasicLit:
+		if val.BasicLit == nil {
+			return
+		}
+		for _, k := range kinds {
+			if val.BasicLit.Kind == k {
+				return
+			}
+		}
+		v.errorAt(val.BasicLit.ValuePos, "expected %s, but got %s", name, describeLit(lit))
+	case *ast.CompositeLit_ObjLit:
+		if input == nil {
+			v.errorAt(lit.Opening, "expected %s, but got %s", name, describeLit(lit))
+			return
+		}
+
+		fields := make(map[string]interface{})
+		if input.Fields != nil {
+			for _, f := range input.Fields.List {
+				fields[f.Name.Name] = inputValueType(f)
+			}
+		}
+		for _, p := range val.ObjLit.GetFields() {
+			if p == nil || !v.name(p.Key) {
+				continue
+			}
+
+			v.push("field %s", p.Key.Name)
+			if typ, ok := fields[p.Key.Name]; ok {
+				v.value(typ, p.Val)
+			} else {
+				v.errorAt(int64(p.Key.NamePos), "unknown field of %s", name)
+			}
+			v.pop()
+		}
+	case *ast.CompositeLit_ListLit:
+		v.errorAt(lit.Opening, "expected %s, but got %s", name, describeLit(lit))
+	}
+}
+
+// describeLit describes the kind of a literal, for errors.
+func describeLit(lit *ast.CompositeLit) string {
+	switch val := lit.Value.(type) {
+	case *ast.CompositeLit_ListLit:
+		return "a list"
+	case *ast.CompositeLit_ObjLit:
+		return "an object"
+	case *ast.CompositeLit_BasicLit:
+		switch val.BasicLit.Kind {
+		case token.Token_STRING:
+			return "a string"
+		case token.Token_INT:
+			return "an int"
+		case token.Token_FLOAT:
+			return "a float"
+		case token.Token_BOOL:
+			return "a boolean"
+		case token.Token_NULL:
+			return "null"
+		case token.Token_IDENT:
+			return "an enum value"
+		}
+	}
+	return "a value"
+}
+
+func inputValueType(val *ast.InputValue) interface{} {
+	switch t := val.Type.(type) {
+	case *ast.InputValue_Ident:
+		return t.Ident
+	case *ast.InputValue_List:
+		return t.List
+	case *ast.InputValue_NonNull:
+		return t.NonNull
+	}
+	return nil
+}
+
+func (v *shapeValidator) basicLit(lit *ast.BasicLit) {
+	if lit == nil {
+		v.errorf("missing value")
+	}
+}
+
+func (v *shapeValidator) compositeLit(lit *ast.CompositeLit) {
+	if lit == nil {
+		v.errorf("missing value")
+		return
+	}
+
+	switch val := lit.Value.(type) {
+	case *ast.CompositeLit_BasicLit:
+		v.basicLit(val.BasicLit)
+	case *ast.CompositeLit_ListLit:
+		if val.ListLit == nil {
+			v.errorf("missing list value")
+			return
+		}
+		if !v.enter("value") {
+			return
+		}
+		defer v.leave()
+
+		switch l := val.ListLit.List.(type) {
+		case nil:
+		case *ast.ListLit_BasicList:
+			if l.BasicList == nil {
+				v.errorf("missing list values")
+				return
+			}
+			for _, e := range l.BasicList.Values {
+				v.basicLit(e)
+			}
+		case *ast.ListLit_CompositeList:
+			if l.CompositeList == nil {
+				v.errorf("missing list values")
+				return
+			}
+			for _, e := range l.CompositeList.Values {
+				v.compositeLit(e)
+			}
+		}
+	case *ast.CompositeLit_ObjLit:
+		if val.ObjLit == nil {
+			v.errorf("missing object value")
+			return
+		}
+		if !v.enter("value") {
+			return
+		}
+		defer v.leave()
+
+		for _, p := range val.ObjLit.Fields {
+			if p == nil || !v.name(p.Key) {
+				v.errorf("object field has no name")
+				continue
+			}
+
+			v.push("field %s", p.Key.Name)
+			v.compositeLit(p.Val)
+			v.pop()
+		}
+	default:
+		v.errorf("missing value")
+	}
+}
@@ -0,0 +1,309 @@
+package gen
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/types"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+func object(name string, fields ...*ast.Field) *ast.TypeDecl {
+	obj := new(ast.ObjectType)
+	if len(fields) > 0 {
+		obj.Fields = &ast.FieldList{List: fields}
+	}
+
+	return &ast.TypeDecl{
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: name},
+			Type: &ast.TypeSpec_Object{Object: obj},
+		}},
+	}
+}
+
+func TestValidateShape(t *testing.T) {
+	str := &ast.Field_Ident{Ident: &ast.Ident{Name: "String"}}
+
+	testCases := []struct {
+		Name string
+		Doc  *ast.Document
+		Errs []string
+	}{
+		{
+			Name: "Valid",
+			Doc: &ast.Document{
+				Name: "test",
+				Types: []*ast.TypeDecl{
+					object("Query", &ast.Field{Name: &ast.Ident{Name: "a"}, Type: str}),
+				},
+			},
+		},
+		{
+			Name: "NilDocument",
+			Errs: []string{"missing document"},
+		},
+		{
+			Name: "NoSpec",
+			Doc:  &ast.Document{Types: []*ast.TypeDecl{{}}},
+			Errs: []string{"type declaration has no spec"},
+		},
+		{
+			Name: "NoTypeName",
+			Doc: &ast.Document{Types: []*ast.TypeDecl{
+				{Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{Type: &ast.TypeSpec_Scalar{Scalar: &ast.ScalarType{}}}}},
+			}},
+			Errs: []string{"type has no name"},
+		},
+		{
+			Name: "NoKind",
+			Doc: &ast.Document{Types: []*ast.TypeDecl{
+				{Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{Name: &ast.Ident{Name: "A"}}}},
+			}},
+			Errs: []string{"type A: type has no kind"},
+		},
+		{
+			Name: "Fields",
+			Doc: &ast.Document{Types: []*ast.TypeDecl{
+				object("Query",
+					nil,
+					&ast.Field{Name: &ast.Ident{Name: "a"}},
+					&ast.Field{Name: &ast.Ident{Name: "b"}, Type: &ast.Field_List{List: &ast.List{}}},
+					&ast.Field{
+						Name: &ast.Ident{Name: "c"},
+						Type: str,
+						Args: &ast.InputValueList{List: []*ast.InputValue{
+							{Name: &ast.Ident{Name: "id"}, Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{}}},
+						}},
+					},
+				),
+			}},
+			Errs: []string{
+				"type Query: field has no name",
+				"type Query > field a: missing type",
+				"type Query > field b: list has no element type",
+				"type Query > field c > arg id: non-null has no type",
+			},
+		},
+		{
+			Name: "Schema",
+			Doc: &ast.Document{Schema: &ast.TypeDecl{
+				Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+					Type: &ast.TypeSpec_Schema{Schema: &ast.SchemaType{
+						RootOps: &ast.FieldList{List: []*ast.Field{
+							{Name: &ast.Ident{Name: "query"}, Type: &ast.Field_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Query"}}}}},
+						}},
+					}},
+				}},
+			}},
+			Errs: []string{"schema > root operation query: root operation type must be a named type"},
+		},
+		{
+			Name: "Extension",
+			Doc: &ast.Document{Types: []*ast.TypeDecl{
+				object("A"),
+				{Spec: &ast.TypeDecl_TypeExtSpec{TypeExtSpec: &ast.TypeExtensionSpec{
+					Type: &ast.TypeSpec{
+						Name: &ast.Ident{Name: "E"},
+						Type: &ast.TypeSpec_Enum{Enum: &ast.EnumType{}},
+					},
+				}}},
+				{Spec: &ast.TypeDecl_TypeExtSpec{TypeExtSpec: &ast.TypeExtensionSpec{
+					Type: &ast.TypeSpec{
+						Name: &ast.Ident{Name: "U"},
+						Type: &ast.TypeSpec_Union{Union: &ast.UnionType{Members: []*ast.Ident{nil}}},
+					},
+				}}},
+			}},
+			Errs: []string{
+				"type A: object has no fields",
+				"extend > type U: member has no name",
+			},
+		},
+		{
+			Name: "SchemaExtension",
+			Doc: &ast.Document{Types: []*ast.TypeDecl{
+				{Spec: &ast.TypeDecl_TypeExtSpec{TypeExtSpec: &ast.TypeExtensionSpec{
+					Type: &ast.TypeSpec{
+						Type:       &ast.TypeSpec_Schema{Schema: &ast.SchemaType{}},
+						Directives: []*ast.DirectiveLit{{Name: "link"}, {}},
+					},
+				}}},
+			}},
+			Errs: []string{"extend > schema: directive has no name"},
+		},
+		{
+			Name: "Options",
+			Doc: &ast.Document{Directives: []*ast.DirectiveLit{
+				{
+					Name: "js",
+					Args: &ast.CallExpr{Args: []*ast.Arg{
+						{
+							Name:  &ast.Ident{Name: "options"},
+							Value: &ast.Arg_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: `"a"`}},
+						},
+					}},
+				},
+				{
+					Name: "doc",
+					Args: &ast.CallExpr{Args: []*ast.Arg{
+						{
+							Name: &ast.Ident{Name: "options"},
+							Value: &ast.Arg_CompositeLit{CompositeLit: &ast.CompositeLit{
+								Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+									Fields: []*ast.ObjLit_Pair{{Key: &ast.Ident{Name: "title"}}},
+								}},
+							}},
+						},
+					}},
+				},
+			}},
+			Errs: []string{
+				"@js > arg options: options must be an object",
+				"@doc > arg options > field title: missing value",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			err := ValidateShape(testCase.Doc)
+			if len(testCase.Errs) == 0 {
+				if err != nil {
+					subT.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+
+			errs, ok := err.(ShapeErrors)
+			if !ok {
+				subT.Fatalf("expected ShapeErrors but got: %#v", err)
+			}
+			if len(errs) != len(testCase.Errs) {
+				subT.Fatalf("expected %d errors but got %d: %s", len(testCase.Errs), len(errs), err)
+			}
+
+			for i, e := range errs {
+				if e.Error() != testCase.Errs[i] {
+					subT.Errorf("expected: %s\ngot:      %s", testCase.Errs[i], e)
+				}
+			}
+		})
+	}
+}
+
+type posCtx struct {
+	TestCtx
+
+	dset *token.DocSet
+}
+
+func (ctx posCtx) Position(pos token.Pos) token.Position { return ctx.dset.Position(pos) }
+
+func TestValidateShape_Options(t *testing.T) {
+	types.Register(
+		&ast.TypeDecl{
+			Tok: token.Token_DIRECTIVE,
+			Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+				Name: &ast.Ident{Name: "shapeTest"},
+				Type: &ast.TypeSpec_Directive{Directive: &ast.DirectiveType{
+					Locs: []*ast.DirectiveLocation{{Loc: ast.DirectiveLocation_DOCUMENT}},
+					Args: &ast.InputValueList{List: []*ast.InputValue{
+						{Name: &ast.Ident{Name: "options"}, Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "ShapeTestOptions"}}},
+					}},
+				}},
+			}},
+		},
+		&ast.TypeDecl{
+			Tok: token.Token_INPUT,
+			Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+				Name: &ast.Ident{Name: "ShapeTestOptions"},
+				Type: &ast.TypeSpec_Input{Input: &ast.InputType{
+					Fields: &ast.InputValueList{List: []*ast.InputValue{
+						{Name: &ast.Ident{Name: "flag"}, Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "Boolean"}}},
+						{Name: &ast.Ident{Name: "width"}, Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "Int"}}},
+						{Name: &ast.Ident{Name: "names"}, Type: &ast.InputValue_List{List: &ast.List{
+							Type: &ast.List_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "String"}}}},
+						}}},
+						{Name: &ast.Ident{Name: "nested"}, Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "ShapeTestOptions"}}},
+					}},
+				}},
+			}},
+		},
+	)
+
+	testCases := []struct {
+		Name string
+		Src  string
+		Errs []string
+	}{
+		{
+			Name: "Valid",
+			Src:  `@shapeTest(options: {flag: true, width: 80, names: "a", nested: {names: ["b", "c"]}})`,
+		},
+		{
+			Name: "Scalars",
+			Src:  `@shapeTest(options: {flag: [true], width: "80"})`,
+			Errs: []string{
+				"test.gql:1:28: @shapeTest > arg options > field flag: expected Boolean, but got a list",
+				"test.gql:1:43: @shapeTest > arg options > field width: expected Int, but got a string",
+			},
+		},
+		{
+			Name: "Lists",
+			Src:  `@shapeTest(options: {names: [1, {a: "b"}]})`,
+			Errs: []string{
+				"test.gql:1:30: @shapeTest > arg options > field names: expected String, but got an int",
+				"test.gql:1:33: @shapeTest > arg options > field names: expected String, but got an object",
+			},
+		},
+		{
+			Name: "Nested",
+			Src:  `@shapeTest(options: {nested: {flag: null, other: 1}})`,
+			Errs: []string{
+				"test.gql:1:37: @shapeTest > arg options > field nested > field flag: expected Boolean, but got null",
+				"test.gql:1:43: @shapeTest > arg options > field nested > field other: unknown field of ShapeTestOptions",
+			},
+		},
+		{
+			Name: "UnknownArg",
+			Src:  `@shapeTest(options: {}, other: 1)`,
+			Errs: []string{"test.gql:1:1: @shapeTest > arg other: unknown arg"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			dset := token.NewDocSet()
+			doc, err := parser.ParseDoc(dset, "test.gql", strings.NewReader(testCase.Src+"\n\ntype Query {\n\ta: Int\n}\n"), 0)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			err = ValidateShapeContext(posCtx{TestCtx: TestCtx{Writer: ioutil.Discard}, dset: dset}, doc)
+			if len(testCase.Errs) == 0 {
+				if err != nil {
+					subT.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+
+			errs, ok := err.(ShapeErrors)
+			if !ok {
+				subT.Fatalf("expected ShapeErrors but got: %#v", err)
+			}
+			if len(errs) != len(testCase.Errs) {
+				subT.Fatalf("expected %d errors but got %d: %s", len(testCase.Errs), len(errs), err)
+			}
+
+			for i, e := range errs {
+				if e.Error() != testCase.Errs[i] {
+					subT.Errorf("expected: %s\ngot:      %s", testCase.Errs[i], e)
+				}
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ module github.com/gqlc/gqlc
 require (
 	github.com/golang/mock v1.4.0
 	github.com/golang/protobuf v1.3.5
-	github.com/gorilla/websocket v1.4.2 // indirect
 	github.com/gqlc/compiler v0.6.0
 	github.com/gqlc/graphql v0.4.1
 	github.com/spf13/afero v1.2.2
@@ -12,6 +11,18 @@ require (
 	github.com/yuin/goldmark v1.1.30
 	github.com/zaba505/gws v0.5.0
 	go.uber.org/zap v1.15.0
+	golang.org/x/term v0.10.0
+	gopkg.in/yaml.v2 v2.4.0
+)
+
+require (
+	github.com/gorilla/websocket v1.4.2 // indirect
+	github.com/klauspost/compress v1.10.3 // indirect
+	go.uber.org/atomic v1.6.0 // indirect
+	go.uber.org/multierr v1.5.0 // indirect
+	golang.org/x/sys v0.10.0 // indirect
+	golang.org/x/text v0.3.2 // indirect
+	nhooyr.io/websocket v1.8.6 // indirect
 )
 
-go 1.13
+go 1.17
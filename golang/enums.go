@@ -0,0 +1,141 @@
+package golang
+
+import (
+	"strings"
+
+	"github.com/gqlc/graphql/ast"
+)
+
+// generateEnumTypes generates a named string type for every enum, with a
+// typed constant for each of its values, so structs and resolvers can't be
+// given values which aren't in the enum. Values are checked when they're
+// marshaled to, or unmarshaled from, JSON.
+//
+func (g *Generator) generateEnumTypes(doc *ast.Document, descr bool) {
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		enum, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Enum)
+		if !ok {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+
+		var vals []*ast.Field
+		if enum.Enum.Values != nil {
+			vals = enum.Enum.Values.List
+		}
+
+		g.P()
+		if d.Doc != nil && descr {
+			g.printComment(d.Doc)
+		}
+		g.P("type ", name, " string")
+
+		consts := make([]string, len(vals))
+		if len(vals) > 0 {
+			g.P()
+			g.P("const (")
+			g.In()
+			for i, v := range vals {
+				consts[i] = enumConst(name, v.Name.Name)
+				if v.Doc != nil && descr {
+					g.printComment(v.Doc)
+				}
+				g.P(consts[i], " ", name, " = \"", v.Name.Name, "\"")
+			}
+			g.Out()
+			g.P(")")
+		}
+
+		g.P()
+		g.P("// IsValid reports whether e is a value of ", name, ".")
+		g.P("func (e ", name, ") IsValid() bool {")
+		g.In()
+		if len(consts) > 0 {
+			g.P("switch e {")
+			g.P("case ", strings.Join(consts, ", "), ":")
+			g.In()
+			g.P("return true")
+			g.Out()
+			g.P("}")
+		}
+		g.P("return false")
+		g.Out()
+		g.P("}")
+
+		g.P()
+		g.P("// String implements the fmt.Stringer interface.")
+		g.P("func (e ", name, ") String() string { return string(e) }")
+
+		g.P()
+		g.P("// MarshalJSON implements the json.Marshaler interface, rejecting invalid values.")
+		g.P("func (e ", name, ") MarshalJSON() ([]byte, error) {")
+		g.In()
+		g.P("if !e.IsValid() {")
+		g.In()
+		g.P("return nil, fmt.Errorf(\"%q is not a valid ", name, "\", string(e))")
+		g.Out()
+		g.P("}")
+		g.P("return json.Marshal(string(e))")
+		g.Out()
+		g.P("}")
+
+		g.P()
+		g.P("// UnmarshalJSON implements the json.Unmarshaler interface, rejecting invalid values.")
+		g.P("func (e *", name, ") UnmarshalJSON(b []byte) error {")
+		g.In()
+		g.P("var s string")
+		g.P("if err := json.Unmarshal(b, &s); err != nil {")
+		g.In()
+		g.P("return err")
+		g.Out()
+		g.P("}")
+		g.P("if !", name, "(s).IsValid() {")
+		g.In()
+		g.P("return fmt.Errorf(\"%q is not a valid ", name, "\", s)")
+		g.Out()
+		g.P("}")
+		g.P("*e = ", name, "(s)")
+		g.P("return nil")
+		g.Out()
+		g.P("}")
+	}
+}
+
+// hasEnums reports whether a document declares any enums, and so whether
+// their types need the fmt package.
+//
+func hasEnums(doc *ast.Document) bool {
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Enum); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// enumConst returns the name of the constant of an enum value, which is
+// prefixed by its enum e.g. READ_ONLY of Role is RoleReadOnly.
+//
+func enumConst(enum, val string) string {
+	var b strings.Builder
+	b.WriteString(enum)
+	for _, word := range strings.Split(val, "_") {
+		if word == "" {
+			continue
+		}
+		if strings.ToUpper(word) == word {
+			word = word[:1] + strings.ToLower(word[1:])
+		}
+		b.WriteString(strings.ToUpper(word[:1]))
+		b.WriteString(word[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,119 @@
+package golang
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	"go/format"
+	goparser "go/parser"
+	"go/scanner"
+	gotoken "go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// formatSource formats generated code like gofmt. Code which can't be
+// formatted doesn't parse, so the error points out where, in the lines of
+// the file around it.
+//
+func formatSource(name string, src []byte) ([]byte, error) {
+	out, err := format.Source(src)
+	if err == nil {
+		return out, nil
+	}
+
+	list, ok := err.(scanner.ErrorList)
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("couldn't format %s: %s", name, err)
+	}
+	pos := list[0].Pos
+	return nil, fmt.Errorf("generated invalid Go, %s:%d:%d: %s\n%s", filepath.ToSlash(name), pos.Line, pos.Column, list[0].Msg, snippet(src, pos.Line))
+}
+
+// snippet returns the lines of src around the given one, numbered, with the
+// line itself marked.
+//
+func snippet(src []byte, line int) string {
+	lines := strings.Split(string(src), "\n")
+
+	var b strings.Builder
+	for i := line - 3; i < line+2; i++ {
+		if i < 0 || i >= len(lines) {
+			continue
+		}
+
+		marker := "  "
+		if i == line-1 {
+			marker = "> "
+		}
+		fmt.Fprintf(&b, "%s%4d | %s\n", marker, i+1, lines[i])
+	}
+	return b.String()
+}
+
+// fixImports removes the imports of formatted code which it doesn't refer
+// to. Imports are referred to by their name, which is the last element of
+// their path, unless it's named. The lines of unused imports are removed,
+// and the code is formatted again, which tidies up what's left.
+//
+func fixImports(src []byte) ([]byte, error) {
+	fset := gotoken.NewFileSet()
+	f, err := goparser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	goast.Inspect(f, func(n goast.Node) bool {
+		if sel, ok := n.(*goast.SelectorExpr); ok {
+			if id, ok := sel.X.(*goast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+
+	unused := make(map[int]bool)
+	for _, d := range f.Decls {
+		gd, ok := d.(*goast.GenDecl)
+		if !ok || gd.Tok != gotoken.IMPORT {
+			continue
+		}
+
+		var n int
+		for _, spec := range gd.Specs {
+			imp := spec.(*goast.ImportSpec)
+			p, _ := strconv.Unquote(imp.Path.Value)
+
+			name := packageName(p)
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			if name == "_" || name == "." || used[name] {
+				continue
+			}
+
+			unused[fset.Position(imp.Pos()).Line] = true
+			n++
+		}
+
+		// Imports are removed altogether, when none of them are used
+		if n == len(gd.Specs) {
+			for l := fset.Position(gd.Pos()).Line; l <= fset.Position(gd.End()).Line; l++ {
+				unused[l] = true
+			}
+		}
+	}
+	if len(unused) == 0 {
+		return src, nil
+	}
+
+	var b bytes.Buffer
+	for i, line := range bytes.SplitAfter(src, []byte{'\n'}) {
+		if !unused[i+1] {
+			b.Write(line)
+		}
+	}
+	return format.Source(b.Bytes())
+}
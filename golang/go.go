@@ -6,12 +6,16 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
-	"sync"
+	"unicode"
 
+	"github.com/gqlc/gqlc/federation"
 	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/gen/printer"
 	"github.com/gqlc/graphql/ast"
 	"github.com/gqlc/graphql/token"
 	"go.uber.org/zap"
@@ -19,36 +23,118 @@ import (
 
 // Options contains the options for the Go generator.
 type Options struct {
-	// Package is the go package this will belong to. (default: main)
+	// Package is the go package this will belong to. If it isn't set, it's
+	// named after the import path of the output directory, when that's in a
+	// Go module, or main otherwise.
 	Package string
 
+	// ImportPath is the import path the code is generated into. If it's
+	// set, the code is written to its directory in whichever module of the
+	// go.work workspace enclosing the output directory provides it, so the
+	// documents of a schema can target different modules. Otherwise, it's
+	// the import path of the output directory, if that's in a Go module,
+	// found from the enclosing go.mod file.
+	//
+	ImportPath string
+
+	// Emit a go:generate directive, which reruns gqlc with the same options.
+	Generate bool
+
 	// Copy descriptions to Go
 	Descriptions bool
+
+	// CommentWidth wraps the comments descriptions are copied to at this
+	// width, or not at all if it's 0.
+	//
+	CommentWidth int
+
+	// Generate a struct for every input type, whose nullable fields are
+	// Optional values, which tell an absent field apart from a null one.
+	Optionals bool
+
+	// Serve the document as an Apollo Federation subgraph
+	Federation bool
+
+	// Generate a struct for every object type, of its fields which take no
+	// arguments, and a <Type>Resolver interface for every root operation
+	// type, and any other type with fields which take arguments. Input
+	// structs are generated too, since resolvers are passed them.
+	//
+	Resolvers bool
+
+	// DefaultResolvers generates a Default<Type>Resolver for every resolver
+	// interface, which resolves every field to its zero value, so resolvers
+	// can embed it and be implemented a field at a time.
+	//
+	DefaultResolvers bool
+
+	// Mocks generates a Mock<Type>Resolver for every resolver interface,
+	// for testing code which uses them: funcs, which resolves each field
+	// with a func, or gomock, like mockgen generates.
+	//
+	Mocks string
+
+	// JSONNaming names the json tags of struct fields: camel or snake case.
+	// If it isn't set, they're named as the fields are declared.
+	//
+	JSONNaming string
+
+	// OmitEmpty adds omitempty to the json tags of nullable object fields.
+	OmitEmpty bool
+
+	// Tags are extra struct tag keys, e.g. db, which are named like json
+	// tags. Fields can also be given tags with the goTag directive.
+	//
+	Tags []string
+
+	// Scalars binds scalars to existing Go types, which are written
+	// Scalar=importpath.Type e.g. DateTime=time.Time, instead of generating
+	// types for them. Their packages are imported as they're needed.
+	//
+	Scalars []string
+
+	// Split moves the code generated by Optionals and Resolvers out into
+	// sub-packages of the output package: models, of the structs, scalar
+	// types, and interfaces, enums, of the enum types, and resolvers, of
+	// the resolver interfaces. Enums stay with the models, unless they're
+	// split out too. Types are qualified by the packages they're in, which
+	// are imported, so the output directory must be in a Go module.
+	//
+	Split []string
+
+	// FixImports removes the imports generated code doesn't refer to, like
+	// goimports does.
+	//
+	FixImports bool
 }
 
 // Generator generates Go code for a GraphQL schema.
 type Generator struct {
-	sync.Mutex
-	bytes.Buffer
+	printer.Printer
+
+	log *zap.Logger
+
+	// scalars are the scalars bound to Go types
+	scalars map[string]*scalarBinding
 
-	indent []byte
-	log    *zap.Logger
+	// layout places code in sub-packages, if it's split into them
+	layout *layout
 }
 
-// Reset overrides the bytes.Buffer Reset method to assist in cleaning up some Generator state.
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
 func (g *Generator) Reset() {
-	g.Buffer.Reset()
-	if g.indent == nil {
-		g.indent = make([]byte, 0, 5)
-	}
-	g.indent = g.indent[0:0]
+	g.Printer.Reset()
+	g.layout = nil
 }
 
 var typeSuffix = []byte("Type")
 
 // Generate generates Go code for the given document.
-func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
-	g.Lock()
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
 	defer func() {
 		if err != nil {
 			err = gen.GeneratorError{
@@ -58,11 +144,13 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 			}
 		}
 	}()
-	defer g.Unlock()
 	g.Reset()
 
-	if g.log == nil {
-		g.log = zap.L().Named("golang").With(zap.String("doc", doc.Name))
+	g.log = zap.L().Named("golang").With(zap.String("doc", doc.Name))
+
+	// Check the document is well formed
+	if verr := gen.ValidateShapeContext(gen.Context(ctx), doc); verr != nil {
+		return verr
 	}
 
 	// Get generator options
@@ -71,10 +159,118 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 	if oerr != nil {
 		return oerr
 	}
+	if gOpts.CommentWidth < 0 {
+		return fmt.Errorf("commentWidth must not be negative, but got: %d", gOpts.CommentWidth)
+	}
+	g.Width = gOpts.CommentWidth
+
+	switch gOpts.JSONNaming {
+	case "", namingCamel, namingSnake:
+	default:
+		return fmt.Errorf("unknown jsonNaming option: %s, expected camel or snake", gOpts.JSONNaming)
+	}
+	switch gOpts.Mocks {
+	case "", mockFuncs, mockGomock:
+	default:
+		return fmt.Errorf("unknown mocks option: %s, expected funcs or gomock", gOpts.Mocks)
+	}
+	for _, k := range gOpts.Tags {
+		if !validTagKey(k) || k == "json" {
+			return fmt.Errorf("invalid struct tag key: %q", k)
+		}
+	}
+	tags := tagger{naming: gOpts.JSONNaming, omitEmpty: gOpts.OmitEmpty, keys: gOpts.Tags}
+
+	g.scalars, err = parseScalars(gOpts.Scalars)
+	if err != nil {
+		return
+	}
+	err = g.checkScalars(doc)
+	if err != nil {
+		return
+	}
+
+	// Add the types and fields of a subgraph
+	var serviceSDL string
+	if gOpts.Federation {
+		g.log.Info("adding federation types")
+		serviceSDL = federation.SDL(doc)
+		doc = federation.Subgraph(doc, subgraphResolvers)
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	// Resolve package, and the directory it's written to
+	pCtx, isPath := gCtx.(gen.PathContext)
+	var outDir string
+	switch {
+	case isPath && gOpts.ImportPath != "":
+		outDir, err = gen.ModuleDir(pCtx, pCtx.Dir(), gOpts.ImportPath)
+	case isPath:
+		outDir = pCtx.Dir()
+		gOpts.ImportPath, err = gen.ImportPath(pCtx, outDir)
+	case gOpts.ImportPath != "":
+		err = fmt.Errorf("importPath can't be resolved by this generator context")
+	}
+	if err != nil {
+		return
+	}
+	if gOpts.Package == "" {
+		gOpts.Package = packageName(gOpts.ImportPath)
+	}
+	if len(gOpts.Split) > 0 {
+		g.layout, err = newLayout(gOpts.ImportPath, gOpts.Package, gOpts.Split)
+		if err != nil {
+			return
+		}
+	}
 
-	// Generate package and imports
-	g.log.Info("writing header")
-	g.writeHeader(g, []byte(gOpts.Package))
+	// Imports of the standard library by each package, the headers are
+	// written once the bound scalars and packages which are used are known.
+	//
+	stdImports := func(pkg string) (imports []string) {
+		if gOpts.Resolvers && hasResolvers(doc) && g.layout.place(pkgResolvers) == pkg {
+			imports = append(imports, "context")
+			if gOpts.Mocks == mockGomock {
+				imports = append(imports, "reflect", gomockPath)
+			}
+		}
+		if !gOpts.Optionals && !gOpts.Resolvers {
+			return
+		}
+
+		models := g.layout.place(pkgModels) == pkg
+		enums := hasEnums(doc) && g.layout.place(pkgEnums) == pkg
+		custom := len(g.customScalars(doc)) > 0 && models
+		if models || enums {
+			imports = append(imports, "encoding/json")
+		}
+		if enums || custom {
+			imports = append(imports, "fmt")
+		}
+		if custom {
+			imports = append(imports, "io", "strconv")
+		}
+		return
+	}
+
+	if gOpts.Generate {
+		var src string
+		if isPath {
+			src = pCtx.Source(doc)
+		}
+
+		if src == "" {
+			gen.Warnf(gCtx, doc, token.NoPos, "source of %s is unknown, so no go:generate directive will be written", doc.Name)
+		} else {
+			g.writeGenerate(outDir, src, opts)
+		}
+	}
+
+	if gOpts.Federation {
+		g.writeServiceSDL(serviceSDL)
+	}
 
 	// Generate types
 	g.log.Info("generating types")
@@ -123,37 +319,301 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 		}
 	}
 
-	// Extract generator context
-	gCtx := gen.Context(ctx)
+	// Generate input structs
+	if gOpts.Optionals || gOpts.Resolvers {
+		err = checkTags(doc)
+		if err != nil {
+			return
+		}
 
-	// Open file to write to
-	goFileName := doc.Name[:len(doc.Name)-len(filepath.Ext(doc.Name))]
-	goFile, err := gCtx.Open(goFileName + ".go")
-	defer goFile.Close()
-	if err != nil {
-		return
+		g.log.Info("generating scalar types")
+		g.section(pkgModels, func() { g.generateScalarTypes(doc, gOpts.Descriptions) })
+
+		g.log.Info("generating enum types")
+		g.section(pkgEnums, func() { g.generateEnumTypes(doc, gOpts.Descriptions) })
+
+		g.log.Info("generating input structs")
+		g.section(pkgModels, func() { g.generateInputStructs(doc, gOpts.Descriptions, tags) })
+	}
+
+	// Generate object structs and resolvers
+	if gOpts.Resolvers {
+		g.log.Info("generating resolvers")
+		g.generateResolvers(doc, gOpts.Descriptions, gOpts.DefaultResolvers, gOpts.Mocks, tags)
+	}
+
+	// Open file to write to, which may be in another module
+	goFileName := doc.Name[:len(doc.Name)-len(filepath.Ext(doc.Name))] + ".go"
+	if isPath && outDir != pCtx.Dir() {
+		rel, rerr := filepath.Rel(pCtx.Dir(), outDir)
+		if rerr != nil {
+			return rerr
+		}
+		goFileName = filepath.Join(rel, goFileName)
 	}
 
 	// Write generated output
-	_, err = g.WriteTo(goFile)
+	g.log.Info("writing file")
+	imports := append(stdImports(""), graphqlPath)
+	if g.layout == nil {
+		for _, b := range g.scalars {
+			if b.used && b.importPath != "" {
+				imports = append(imports, b.importSpec())
+			}
+		}
+	} else {
+		imports = append(imports, g.layout.imports[""]...)
+		g.Write(g.layout.code[""])
+	}
+	err = g.writeFile(gCtx, goFileName, gOpts.Package, g.Bytes(), imports, gOpts.FixImports)
+	if err != nil || g.layout == nil {
+		return
+	}
+
+	// Write sub-packages, into directories named after them
+	for _, pkg := range subPackages {
+		code := bytes.TrimLeft(g.layout.code[pkg], "\n")
+		if len(code) == 0 {
+			continue
+		}
+
+		g.log.Info("writing sub-package", zap.String("package", pkg))
+		err = g.writeFile(gCtx, filepath.Join(filepath.Dir(goFileName), pkg, filepath.Base(goFileName)), pkg, code, append(stdImports(pkg), g.layout.imports[pkg]...), gOpts.FixImports)
+		if err != nil {
+			return
+		}
+	}
 	return
 }
 
+// writeFile writes a file of generated code, which is formatted like gofmt
+// formats it, once it's given its header.
+//
+func (g *Generator) writeFile(gCtx gen.GeneratorContext, name, pkg string, code []byte, imports []string, fix bool) error {
+	var src bytes.Buffer
+	g.writeHeader(&src, []byte(pkg), imports...)
+	src.Write(code)
+
+	out, err := formatSource(name, src.Bytes())
+	if err != nil {
+		return err
+	}
+	if fix {
+		out, err = fixImports(out)
+		if err != nil {
+			return err
+		}
+	}
+
+	f, err := gCtx.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(out)
+	return err
+}
+
 var (
 	packagePrefix = []byte("package ")
-	importStmt    = []byte(`import "github.com/graphql-go/graphql"`)
+	importKeyword = []byte("import ")
 	newLines      = []byte{'\n', '\n'}
 )
 
-func (g *Generator) writeHeader(w io.Writer, packageName []byte) {
+const graphqlPath = "github.com/graphql-go/graphql"
+
+// writeHeader writes the package clause and imports. An import may be
+// named, by preceding its path with the name and a space. Imports are
+// grouped and sorted, like gofmt, with the standard library first.
+//
+func (g *Generator) writeHeader(w io.Writer, packageName []byte, imports ...string) {
 	w.Write(packagePrefix)
 	w.Write(packageName)
 	w.Write(newLines)
 
-	w.Write(importStmt)
+	std, other := []string{}, []string{}
+	seen := make(map[string]bool, len(imports))
+	for _, imp := range imports {
+		if seen[imp] {
+			continue
+		}
+		seen[imp] = true
+
+		p := importPath(imp)
+		if strings.Contains(strings.SplitN(p, "/", 2)[0], ".") {
+			other = append(other, imp)
+			continue
+		}
+		std = append(std, imp)
+	}
+
+	switch len(std) + len(other) {
+	case 0:
+		return
+	case 1:
+		w.Write(importKeyword)
+		writeImport(w, append(std, other...)[0])
+		w.Write(newLines)
+		return
+	}
+
+	io.WriteString(w, "import (\n")
+	for i, group := range [][]string{std, other} {
+		if i > 0 && len(std) > 0 && len(other) > 0 {
+			w.Write([]byte{'\n'})
+		}
+
+		sort.Slice(group, func(i, j int) bool { return importPath(group[i]) < importPath(group[j]) })
+		for _, imp := range group {
+			w.Write([]byte{'\t'})
+			writeImport(w, imp)
+			w.Write([]byte{'\n'})
+		}
+	}
+	io.WriteString(w, ")")
 	w.Write(newLines)
 }
 
+// writeImport writes an import spec, which may be named.
+func writeImport(w io.Writer, imp string) {
+	if sp := strings.IndexByte(imp, ' '); sp >= 0 {
+		fmt.Fprintf(w, "%s %q", imp[:sp], imp[sp+1:])
+		return
+	}
+	fmt.Fprintf(w, "%q", imp)
+}
+
+// importPath returns the path of an import, which may be named.
+func importPath(imp string) string { return imp[strings.IndexByte(imp, ' ')+1:] }
+
+// subgraphResolvers serve the SDL of a subgraph. Entities are left to be
+// resolved like any other field.
+//
+var subgraphResolvers = federation.Resolvers{
+	Service: "func(p graphql.ResolveParams) (interface{}, error) { return struct{}{}, nil }",
+	SDL:     "func(p graphql.ResolveParams) (interface{}, error) { return ServiceSDL, nil }",
+}
+
+// writeServiceSDL writes the SDL served by the _service field of a subgraph.
+func (g *Generator) writeServiceSDL(sdl string) {
+	g.P("// ServiceSDL is the schema of this subgraph, as it's served by _service.")
+	g.WriteString("const ServiceSDL = ")
+	if strings.Contains(sdl, "`") {
+		g.WriteString(strconv.Quote(sdl))
+	} else {
+		g.WriteByte('`')
+		g.WriteString(sdl)
+		g.WriteByte('`')
+	}
+	g.Write(newLines)
+}
+
+var generatePrefix = []byte("//go:generate gqlc --go_out .")
+
+// writeGenerate writes a go:generate directive, which regenerates the file
+// from its source. go generate runs in the directory of the file, so paths
+// are relative to it.
+//
+func (g *Generator) writeGenerate(dir, src string, opts map[string]interface{}) {
+	g.Write(generatePrefix)
+
+	if cliOpts := formatOptions(opts); cliOpts != "" {
+		g.WriteString(" --go_opt ")
+		g.WriteString(cliOpts)
+	}
+
+	rel, err := filepath.Rel(dir, src)
+	if err != nil {
+		rel = src
+	}
+	rel = filepath.ToSlash(rel)
+
+	if srcDir := path.Dir(rel); srcDir != "." {
+		g.WriteString(" -I ")
+		g.WriteString(srcDir)
+	}
+
+	g.WriteByte(' ')
+	g.WriteString(rel)
+	g.Write(newLines)
+}
+
+// formatOptions formats CLI options, so they're parsed back into the same
+// options by gqlc.
+//
+func formatOptions(opts map[string]interface{}) string {
+	keys := make([]string, 0, len(opts))
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		var vals []interface{}
+		switch v := opts[k].(type) {
+		case []string:
+			for _, s := range v {
+				vals = append(vals, s)
+			}
+		case []int64:
+			for _, i := range v {
+				vals = append(vals, i)
+			}
+		case []float64:
+			for _, f := range v {
+				vals = append(vals, f)
+			}
+		case []bool:
+			for _, t := range v {
+				vals = append(vals, t)
+			}
+		default:
+			vals = append(vals, v)
+		}
+
+		for _, v := range vals {
+			if b.Len() > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(&b, "%s=%v", k, v)
+		}
+	}
+	return b.String()
+}
+
+// packageName returns the name of the package with the given import path,
+// which is its last element, unless that's a major version suffix.
+//
+func packageName(importPath string) string {
+	if importPath == "" {
+		return "main"
+	}
+
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && isMajorVersion(name) {
+		name = elems[len(elems)-2]
+	}
+	name = strings.TrimPrefix(name, "go-")
+
+	return strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(s[1:])
+	return err == nil
+}
+
 func (g *Generator) generateScalar(name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
 	g.P("NewScalar(graphql.ScalarConfig{")
 	g.In()
@@ -188,7 +648,7 @@ func (g *Generator) generateObject(name string, descr bool, doc *ast.DocGroup, t
 	// Print interfaces
 	interLen := len(obj.Interfaces)
 	if interLen == 1 {
-		g.Write(g.indent)
+		g.WriteIndent()
 		g.WriteString("Interfaces: []*graphql.Interface{ ")
 		g.WriteString(obj.Interfaces[0].Name)
 		g.Write(typeSuffix)
@@ -198,7 +658,7 @@ func (g *Generator) generateObject(name string, descr bool, doc *ast.DocGroup, t
 		g.WriteByte('\n')
 	}
 	if interLen > 1 {
-		g.Write(g.indent)
+		g.WriteIndent()
 		g.WriteString("Interfaces: []*graphql.Interface{")
 		g.WriteByte('\n')
 		g.In()
@@ -260,7 +720,7 @@ func (g *Generator) generateFields(fields *ast.FieldList, descr, resolve bool) {
 		g.P('"', f.Name.Name, '"', ": &graphql.Field{")
 		g.In()
 
-		g.Write(g.indent)
+		g.WriteIndent()
 		g.WriteString("Type: ")
 
 		var fieldType interface{}
@@ -311,7 +771,7 @@ func (g *Generator) generateUnion(name string, descr bool, doc *ast.DocGroup, ts
 	// Print members
 	memsLen := len(union.Members)
 	if memsLen == 1 {
-		g.P("Types: []*graphql.Object{ ", union.Members[0], typeSuffix, " },")
+		g.P("Types: []*graphql.Object{ ", union.Members[0].Name, typeSuffix, " },")
 	}
 	if memsLen > 1 {
 		g.P("Types: []*graphql.Object{")
@@ -398,7 +858,7 @@ func (g *Generator) generateInput(name string, descr bool, doc *ast.DocGroup, ts
 	for _, f := range input.Fields.List {
 		g.P('"', f.Name.Name, '"', ": &graphql.InputObjectFieldConfig{")
 		g.In()
-		g.Write(g.indent)
+		g.WriteIndent()
 		g.WriteString("Type: ")
 
 		var fieldType interface{}
@@ -415,7 +875,7 @@ func (g *Generator) generateInput(name string, descr bool, doc *ast.DocGroup, ts
 		g.WriteByte('\n')
 
 		if f.Default != nil {
-			g.Write(g.indent)
+			g.WriteIndent()
 			g.WriteString("DefaultValue: ")
 
 			var defType interface{}
@@ -501,7 +961,7 @@ func (g *Generator) generateArgs(args []*ast.InputValue, descr bool) {
 		g.P('"', a.Name.Name, '"', ": &graphql.ArgumentConfig{")
 		g.In()
 
-		g.Write(g.indent)
+		g.WriteIndent()
 		g.WriteString("Type: ")
 
 		var fieldType interface{}
@@ -518,7 +978,7 @@ func (g *Generator) generateArgs(args []*ast.InputValue, descr bool) {
 		g.WriteByte('\n')
 
 		if a.Default != nil {
-			g.Write(g.indent)
+			g.WriteIndent()
 			g.WriteString("DefaultValue: ")
 
 			var defType interface{}
@@ -544,14 +1004,272 @@ func (g *Generator) generateArgs(args []*ast.InputValue, descr bool) {
 
 	g.Out()
 
-	g.Write(g.indent)
+	g.WriteIndent()
 	g.WriteByte('}')
 }
 
+// optionalDecl is the Go type of nullable input fields.
+const optionalDecl = `// Optional is a nullable input value, which tells an absent value apart from
+// an explicit null. Value is nil when the input is either.
+type Optional[T any] struct {
+	Value *T
+	Set   bool
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It's only called
+// for values which are present, so Set records whether one was given.
+func (o *Optional[T]) UnmarshalJSON(b []byte) error {
+	o.Set = true
+	if string(b) == "null" {
+		o.Value = nil
+		return nil
+	}
+
+	o.Value = new(T)
+	return json.Unmarshal(b, o.Value)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.Value)
+}
+`
+
+// generateInputStructs generates a struct for every input type, along with
+// the Optional type of their nullable fields. Absent fields are left out
+// when a struct is marshaled, so they stay absent on a round trip, which
+// makes omitempty needless.
+//
+func (g *Generator) generateInputStructs(doc *ast.Document, descr bool, tags tagger) {
+	kinds := make(map[string]interface{}, len(doc.Types))
+	var inputs []*ast.TypeDecl
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		kinds[ts.TypeSpec.Name.Name] = ts.TypeSpec.Type
+
+		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Input); ok {
+			inputs = append(inputs, d)
+		}
+	}
+	g.bindScalars(kinds)
+
+	g.P()
+	g.WriteString(optionalDecl)
+
+	for _, d := range inputs {
+		ts := d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+		input := ts.Type.(*ast.TypeSpec_Input).Input
+
+		var fields []*ast.InputValue
+		if input.Fields != nil {
+			fields = input.Fields.List
+		}
+
+		g.P()
+		if d.Doc != nil && descr {
+			g.printComment(d.Doc)
+		}
+		g.P("type ", ts.Name.Name, " struct {")
+		g.In()
+		for _, f := range fields {
+			if f.Doc != nil && descr {
+				g.printComment(f.Doc)
+			}
+
+			typ := g.goType(kinds, inputValueType(f), false)
+			if _, ok := f.Type.(*ast.InputValue_NonNull); !ok {
+				typ = "Optional[" + typ + "]"
+			}
+			g.P(exportName(f.Name.Name), " ", typ, " ", tags.tag(f.Name.Name, false, f.Directives))
+		}
+		g.Out()
+		g.P("}")
+
+		g.P()
+		g.P("// MarshalJSON implements the json.Marshaler interface, leaving out absent fields.")
+		g.P("func (in ", ts.Name.Name, ") MarshalJSON() ([]byte, error) {")
+		g.In()
+		g.P("m := make(map[string]interface{}, ", len(fields), ")")
+		for _, f := range fields {
+			name := exportName(f.Name.Name)
+			key := tags.jsonName(f.Name.Name)
+			if _, ok := f.Type.(*ast.InputValue_NonNull); ok {
+				g.P("m[\"", key, "\"] = in.", name)
+				continue
+			}
+
+			g.P("if in.", name, ".Set {")
+			g.In()
+			g.P("m[\"", key, "\"] = in.", name)
+			g.Out()
+			g.P("}")
+		}
+		g.P("return json.Marshal(m)")
+		g.Out()
+		g.P("}")
+	}
+}
+
+// builtinTypes maps the builtin scalars to their Go types.
+var builtinTypes = map[string]string{
+	"Int":     "int",
+	"Float":   "float64",
+	"String":  "string",
+	"Boolean": "bool",
+	"ID":      "string",
+}
+
+// goType returns the Go type of an input value, or field. Nullable values,
+// other than the value itself, are pointers. Enums and custom scalars are
+// their generated string types, unless a scalar is bound to a Go type, and
+// interfaces and unions are their Go interfaces.
+//
+func goType(kinds map[string]interface{}, typ interface{}, nullable bool) (name string) {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		switch k := kinds[v.Name].(type) {
+		case *ast.TypeSpec_Input:
+			name = v.Name
+		case *ast.TypeSpec_Object:
+			// Objects can reference themselves, so they're always pointers
+			name = "*" + v.Name
+			nullable = false
+		case *ast.TypeSpec_Enum, *ast.TypeSpec_Scalar:
+			name = v.Name
+		case *ast.TypeSpec_Interface, *ast.TypeSpec_Union:
+			// Interfaces can already be nil
+			name = v.Name
+			nullable = false
+		case *scalarBinding:
+			name = k.typ
+			k.used = true
+		default:
+			name = builtinTypes[v.Name]
+			if name == "" {
+				name = "interface{}"
+				nullable = false
+			}
+		}
+	case *ast.List:
+		var elem interface{}
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			elem = w.Ident
+		case *ast.List_List:
+			elem = w.List
+		case *ast.List_NonNull:
+			elem = w.NonNull
+		}
+
+		name = "[]" + goType(kinds, elem, true)
+		nullable = false
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return goType(kinds, w.Ident, false)
+		case *ast.NonNull_List:
+			return goType(kinds, w.List, false)
+		}
+	}
+
+	if nullable {
+		name = "*" + name
+	}
+	return
+}
+
+func inputValueType(a *ast.InputValue) interface{} {
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+// exportName returns the exported Go name of a field.
+func exportName(name string) string {
+	if name == "" {
+		return name
+	}
+
+	// Initialisms keep one case, e.g. userId is UserID
+	var b strings.Builder
+	start := 0
+	for i := 1; i <= len(name); i++ {
+		if i < len(name) && !unicode.IsUpper(rune(name[i])) {
+			continue
+		}
+
+		word := name[start:i]
+		if upper := strings.ToUpper(word); commonInitialisms[upper] {
+			word = upper
+		}
+		b.WriteString(strings.ToUpper(word[:1]))
+		b.WriteString(word[1:])
+		start = i
+	}
+	return b.String()
+}
+
+// commonInitialisms are the initialisms golint expects Go names to keep
+// in one case.
+//
+var commonInitialisms = map[string]bool{
+	"ACL":   true,
+	"API":   true,
+	"ASCII": true,
+	"CPU":   true,
+	"CSS":   true,
+	"DNS":   true,
+	"EOF":   true,
+	"GUID":  true,
+	"HTML":  true,
+	"HTTP":  true,
+	"HTTPS": true,
+	"ID":    true,
+	"IP":    true,
+	"JSON":  true,
+	"LHS":   true,
+	"QPS":   true,
+	"RAM":   true,
+	"RHS":   true,
+	"RPC":   true,
+	"SLA":   true,
+	"SMTP":  true,
+	"SQL":   true,
+	"SSH":   true,
+	"TCP":   true,
+	"TLS":   true,
+	"TTL":   true,
+	"UDP":   true,
+	"UI":    true,
+	"UID":   true,
+	"UUID":  true,
+	"URI":   true,
+	"URL":   true,
+	"UTF8":  true,
+	"VM":    true,
+	"XML":   true,
+	"XMPP":  true,
+	"XSRF":  true,
+	"XSS":   true,
+}
+
+func (g *Generator) printComment(doc *ast.DocGroup) {
+	g.PrintWrapped("// ", strings.TrimSpace(doc.Text()))
+}
+
 func (g *Generator) printDescr(doc *ast.DocGroup) {
 	text := doc.Text()
 	if len(text) > 0 {
-		g.Write(g.indent)
+		g.WriteIndent()
 		g.WriteString("Description: \"")
 
 		g.WriteString(text[:len(text)-1])
@@ -687,49 +1405,11 @@ func (g *Generator) printObject(v *ast.ObjLit) {
 	g.WriteByte('}')
 }
 
-// P prints the arguments to the generated output.
-func (g *Generator) P(str ...interface{}) {
-	g.Write(g.indent)
-	for _, s := range str {
-		switch v := s.(type) {
-		case []byte:
-			g.Write(v)
-		case byte:
-			g.WriteByte(v)
-		case rune:
-			g.WriteRune(v)
-		case string:
-			g.WriteString(v)
-		case bool:
-			fmt.Fprint(g, v)
-		case int:
-			fmt.Fprint(g, v)
-		case float64:
-			fmt.Fprint(g, v)
-		}
-	}
-	g.WriteByte('\n')
-}
-
-// In increases the indent.
-func (g *Generator) In() {
-	g.indent = append(g.indent, '\t')
-}
-
-// Out decreases the indent.
-func (g *Generator) Out() {
-	if len(g.indent) > 0 {
-		g.indent = g.indent[:len(g.indent)-1]
-	}
-}
-
 // getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
 // Precedence: CLI over Doc over Default
 //
 func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
-	gOpts = &Options{
-		Package: "main",
-	}
+	gOpts = &Options{}
 
 	// Extract document directive options
 	for _, d := range doc.Directives {
@@ -746,6 +1426,8 @@ func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options,
 			switch arg.Key.Name {
 			case "package":
 				gOpts.Package = arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+			case "importPath":
+				gOpts.ImportPath = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
 			case "descriptions":
 				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
 				if err != nil {
@@ -753,6 +1435,70 @@ func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options,
 				}
 
 				gOpts.Descriptions = b
+			case "commentWidth":
+				gOpts.CommentWidth, err = strconv.Atoi(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return
+				}
+			case "optionals":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Optionals = b
+			case "generate":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Generate = b
+			case "federation":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Federation = b
+			case "resolvers":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Resolvers = b
+			case "defaultResolvers":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.DefaultResolvers = b
+			case "mocks":
+				gOpts.Mocks = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			case "jsonNaming":
+				gOpts.JSONNaming = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			case "omitEmpty":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.OmitEmpty = b
+			case "tags":
+				gOpts.Tags = stringList(arg.Val)
+			case "scalars":
+				gOpts.Scalars = stringList(arg.Val)
+			case "split":
+				gOpts.Split = stringList(arg.Val)
+			case "fixImports":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.FixImports = b
 			}
 		}
 	}
@@ -764,17 +1510,107 @@ func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options,
 	if p, ok := opts["package"]; ok {
 		gOpts.Package, _ = p.(string)
 	}
+	if i, ok := opts["importPath"]; ok {
+		v, _ := i.(string)
+		gOpts.ImportPath = strings.Trim(v, `"`)
+	}
 	if d, ok := opts["descriptions"]; ok {
 		gOpts.Descriptions, _ = d.(bool)
 	}
+	if w, ok := opts["commentWidth"]; ok {
+		n, _ := w.(int64)
+		gOpts.CommentWidth = int(n)
+	}
+	if o, ok := opts["optionals"]; ok {
+		gOpts.Optionals, _ = o.(bool)
+	}
+	if gen, ok := opts["generate"]; ok {
+		gOpts.Generate, _ = gen.(bool)
+	}
+	if f, ok := opts["federation"]; ok {
+		gOpts.Federation, _ = f.(bool)
+	}
+	if r, ok := opts["resolvers"]; ok {
+		gOpts.Resolvers, _ = r.(bool)
+	}
+	if d, ok := opts["defaultResolvers"]; ok {
+		gOpts.DefaultResolvers, _ = d.(bool)
+	}
+	if m, ok := opts["mocks"]; ok {
+		v, _ := m.(string)
+		gOpts.Mocks = strings.Trim(v, `"`)
+	}
+	if n, ok := opts["jsonNaming"]; ok {
+		v, _ := n.(string)
+		gOpts.JSONNaming = strings.Trim(v, `"`)
+	}
+	if o, ok := opts["omitEmpty"]; ok {
+		gOpts.OmitEmpty, _ = o.(bool)
+	}
+	if t, ok := opts["tags"]; ok {
+		switch v := t.(type) {
+		case string:
+			gOpts.Tags = []string{strings.Trim(v, `"`)}
+		case []string:
+			gOpts.Tags = nil
+			for _, k := range v {
+				gOpts.Tags = append(gOpts.Tags, strings.Trim(k, `"`))
+			}
+		}
+	}
+	if sc, ok := opts["scalars"]; ok {
+		switch v := sc.(type) {
+		case string:
+			gOpts.Scalars = []string{strings.Trim(v, `"`)}
+		case []string:
+			gOpts.Scalars = nil
+			for _, b := range v {
+				gOpts.Scalars = append(gOpts.Scalars, strings.Trim(b, `"`))
+			}
+		}
+	}
+	if f, ok := opts["fixImports"]; ok {
+		gOpts.FixImports, _ = f.(bool)
+	}
+	if sp, ok := opts["split"]; ok {
+		switch v := sp.(type) {
+		case string:
+			gOpts.Split = []string{strings.Trim(v, `"`)}
+		case []string:
+			gOpts.Split = nil
+			for _, pkg := range v {
+				gOpts.Split = append(gOpts.Split, strings.Trim(pkg, `"`))
+			}
+		}
+	}
 
 	// Trim '"' from beginning and end of title string
-	if gOpts.Package[0] == '"' {
+	if len(gOpts.Package) > 1 && gOpts.Package[0] == '"' {
 		gOpts.Package = gOpts.Package[1 : len(gOpts.Package)-1]
 	}
 	return
 }
 
+// stringList returns the unquoted strings of a String or [String] value.
+func stringList(val *ast.CompositeLit) (strs []string) {
+	switch v := val.Value.(type) {
+	case *ast.CompositeLit_BasicLit:
+		strs = append(strs, strings.Trim(v.BasicLit.Value, `"`))
+	case *ast.CompositeLit_ListLit:
+		switch list := v.ListLit.List.(type) {
+		case *ast.ListLit_BasicList:
+			for _, lit := range list.BasicList.Values {
+				strs = append(strs, strings.Trim(lit.Value, `"`))
+			}
+		case *ast.ListLit_CompositeList:
+			for _, lit := range list.CompositeList.Values {
+				strs = append(strs, stringList(lit)...)
+			}
+		}
+	}
+	return
+}
+
 func getResolver(dirs []*ast.DirectiveLit) string {
 	for _, d := range dirs {
 		if d.Name != "resolver" {
@@ -112,8 +112,6 @@ func TestObject(t *testing.T) {
 	g := &Generator{}
 
 	t.Run("JustFields", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Object{
@@ -207,8 +205,6 @@ func TestObject(t *testing.T) {
 	})
 
 	t.Run("WithInterfaces", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Object{
@@ -262,8 +258,6 @@ func TestObject(t *testing.T) {
 	})
 
 	t.Run("WithCustomResolver", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Object{
@@ -412,8 +406,6 @@ func TestInput(t *testing.T) {
 	g := &Generator{}
 
 	t.Run("NoDefaults", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Input{
@@ -459,8 +451,6 @@ func TestInput(t *testing.T) {
 	})
 
 	t.Run("WithDefaults", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Input{
@@ -532,12 +522,739 @@ func TestInput(t *testing.T) {
 	})
 }
 
+func TestGoType(t *testing.T) {
+	kinds := map[string]interface{}{
+		"Point":     &ast.TypeSpec_Input{},
+		"Direction": &ast.TypeSpec_Enum{},
+		"Time":      &ast.TypeSpec_Scalar{},
+		"Date":      &scalarBinding{importPath: "time", typ: "time.Time"},
+		"Node":      &ast.TypeSpec_Interface{},
+		"Result":    &ast.TypeSpec_Union{},
+	}
+
+	testCases := []struct {
+		Name string
+		Type interface{}
+		Go   string
+	}{
+		{Name: "Builtin", Type: &ast.Ident{Name: "Float"}, Go: "float64"},
+		{Name: "Input", Type: &ast.Ident{Name: "Point"}, Go: "Point"},
+		{Name: "Enum", Type: &ast.Ident{Name: "Direction"}, Go: "Direction"},
+		{Name: "Scalar", Type: &ast.Ident{Name: "Time"}, Go: "Time"},
+		{Name: "BoundScalar", Type: &ast.Ident{Name: "Date"}, Go: "time.Time"},
+		{Name: "NullableBoundScalar", Type: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Date"}}}, Go: "[]*time.Time"},
+		{Name: "Interface", Type: &ast.Ident{Name: "Node"}, Go: "Node"},
+		{Name: "UnionList", Type: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Result"}}}, Go: "[]Result"},
+		{Name: "NonNull", Type: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}, Go: "string"},
+		{Name: "List", Type: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Int"}}}, Go: "[]*int"},
+		{
+			Name: "NestedList",
+			Type: &ast.List{Type: &ast.List_List{List: &ast.List{Type: &ast.List_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Point"}}}}}}},
+			Go:   "[][]Point",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			if typ := goType(kinds, testCase.Type, false); typ != testCase.Go {
+				subT.Errorf("expected Go type %s, but got: %s", testCase.Go, typ)
+			}
+		})
+	}
+}
+
+func TestEnumTypes(t *testing.T) {
+	gqlSrc := `"A Role."
+enum Role {
+	ADMIN
+	"Can only read."
+	READ_ONLY
+}
+
+type Query {
+	role: Role!
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), parser.ParseComments)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := &Generator{}
+	g.Reset()
+	g.generateEnumTypes(doc, true)
+
+	ex := `
+// A Role.
+type Role string
+
+const (
+	RoleAdmin Role = "ADMIN"
+	// Can only read.
+	RoleReadOnly Role = "READ_ONLY"
+)
+
+// IsValid reports whether e is a value of Role.
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleAdmin, RoleReadOnly:
+		return true
+	}
+	return false
+}
+
+// String implements the fmt.Stringer interface.
+func (e Role) String() string { return string(e) }
+
+// MarshalJSON implements the json.Marshaler interface, rejecting invalid values.
+func (e Role) MarshalJSON() ([]byte, error) {
+	if !e.IsValid() {
+		return nil, fmt.Errorf("%q is not a valid Role", string(e))
+	}
+	return json.Marshal(string(e))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, rejecting invalid values.
+func (e *Role) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if !Role(s).IsValid() {
+		return fmt.Errorf("%q is not a valid Role", s)
+	}
+	*e = Role(s)
+	return nil
+}
+`
+
+	gen.CompareBytes(t, []byte(ex), g.Bytes())
+
+	t.Run("Resolvers", func(subT *testing.T) {
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{"resolvers": true, "defaultResolvers": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		for _, ex := range []string{
+			"\t\"encoding/json\"\n\t\"fmt\"\n",
+			"Role(ctx context.Context) (Role, error)",
+			`func (DefaultQueryResolver) Role(ctx context.Context) (Role, error) { return "", nil }`,
+		} {
+			if !strings.Contains(b.String(), ex) {
+				subT.Errorf("expected output to contain:\n%s\nbut got:\n%s", ex, b.String())
+			}
+		}
+	})
+}
+
+func TestInterfaces(t *testing.T) {
+	gqlSrc := `"A Node."
+interface Node {
+	id: ID!
+}
+
+"A SearchResult."
+union SearchResult = User | Post
+
+type User implements Node {
+	id: ID!
+}
+
+type Post implements Node {
+	id: ID!
+}
+
+type Query {
+	node(id: ID!): Node
+	search(text: String!): [SearchResult!]!
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), parser.ParseComments)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := &Generator{}
+	g.Reset()
+	g.generateResolvers(doc, true, true, "", tagger{})
+
+	for _, ex := range []string{
+		"\n// A Node.\ntype Node interface {\n\tIsNode()\n}\n",
+		"\n// A SearchResult.\ntype SearchResult interface {\n\tisSearchResult()\n}\n",
+		"\ntype User struct {\n\tID string `json:\"id\"`\n}\n\nfunc (*User) isSearchResult() {}\nfunc (*User) IsNode() {}\n",
+		"\nfunc (*Post) isSearchResult() {}\nfunc (*Post) IsNode() {}\n",
+		"Node(ctx context.Context, id string) (Node, error)",
+		"Search(ctx context.Context, text string) ([]SearchResult, error)",
+		"func (DefaultQueryResolver) Node(ctx context.Context, id string) (Node, error) { return nil, nil }",
+	} {
+		if !strings.Contains(g.String(), ex) {
+			t.Errorf("expected output to contain:\n%s\nbut got:\n%s", ex, g.String())
+		}
+	}
+	if strings.Contains(g.String(), "Query) Is") || strings.Contains(g.String(), "Query) is") {
+		t.Errorf("expected root types not to implement markers, but got:\n%s", g.String())
+	}
+}
+
+func TestEnumConst(t *testing.T) {
+	testCases := map[string]string{
+		"ADMIN":      "RoleAdmin",
+		"READ_ONLY":  "RoleReadOnly",
+		"readOnly":   "RoleReadOnly",
+		"_INTERNAL_": "RoleInternal",
+		"V2":         "RoleV2",
+	}
+
+	for val, ex := range testCases {
+		if name := enumConst("Role", val); name != ex {
+			t.Errorf("expected the constant of %s to be: %s, but got: %s", val, ex, name)
+		}
+	}
+}
+
+func TestParseScalars(t *testing.T) {
+	testCases := []struct {
+		Binding string
+		Path    string
+		Type    string
+		Import  string
+		Err     bool
+	}{
+		{Binding: "DateTime=time.Time", Path: "time", Type: "time.Time", Import: "time"},
+		{Binding: "UUID=github.com/google/uuid.UUID", Path: "github.com/google/uuid", Type: "uuid.UUID", Import: "github.com/google/uuid"},
+		{Binding: "Map=gopkg.in/yaml.v2.MapSlice", Path: "gopkg.in/yaml.v2", Type: "yaml_v2.MapSlice", Import: "yaml_v2 gopkg.in/yaml.v2"},
+		{Binding: "Long=int64", Type: "int64"},
+		{Binding: "DateTime", Err: true},
+		{Binding: "=time.Time", Err: true},
+		{Binding: "DateTime=.Time", Err: true},
+		{Binding: "DateTime=time.", Err: true},
+		{Binding: "DateTime=github.com/a/b", Err: true},
+		{Binding: "DateTime=map[string]int", Err: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Binding, func(subT *testing.T) {
+			scalars, err := parseScalars([]string{testCase.Binding})
+			if testCase.Err {
+				if err == nil {
+					subT.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			b := scalars[strings.SplitN(testCase.Binding, "=", 2)[0]]
+			if b.importPath != testCase.Path || b.typ != testCase.Type {
+				subT.Errorf("expected %s from %q, but got: %s from %q", testCase.Type, testCase.Path, b.typ, b.importPath)
+			}
+			if b.importPath != "" && b.importSpec() != testCase.Import {
+				subT.Errorf("expected import %s, but got: %s", testCase.Import, b.importSpec())
+			}
+		})
+	}
+}
+
+func TestScalars(t *testing.T) {
+	gqlSrc := `"A DateTime."
+scalar DateTime
+scalar UUID
+scalar Money
+
+type Query {
+	at: DateTime!
+	price(currency: String): Money
+}
+
+input Filter {
+	after: DateTime
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Generated", func(subT *testing.T) {
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{"optionals": true, "descriptions": true})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		for _, ex := range []string{
+			"import (\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"io\"\n\t\"strconv\"\n\n\t\"github.com/graphql-go/graphql\"\n)",
+			"// A DateTime.\ntype DateTime string",
+			"func (s Money) MarshalGQL(w io.Writer) { io.WriteString(w, strconv.Quote(string(s))) }",
+			"func (s *UUID) UnmarshalGQL(v interface{}) error {",
+			"After Optional[DateTime]",
+		} {
+			if !strings.Contains(b.String(), ex) {
+				subT.Errorf("expected the generated code to contain:\n%s\n\nbut got:\n%s", ex, b.String())
+			}
+		}
+	})
+
+	t.Run("Bound", func(subT *testing.T) {
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{
+			"resolvers":        true,
+			"defaultResolvers": true,
+			"scalars":          []string{"DateTime=time.Time", "UUID=github.com/google/uuid.UUID", "Money=github.com/x/go-money.Amount"},
+		})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		for _, ex := range []string{
+			// UUID is never used, so it's not imported
+			"import (\n\t\"context\"\n\t\"encoding/json\"\n\t\"time\"\n\n\t\"github.com/graphql-go/graphql\"\n\tmoney \"github.com/x/go-money\"\n)",
+			"At(ctx context.Context) (time.Time, error)",
+			"At(ctx context.Context) (time.Time, error) { return *new(time.Time), nil }",
+			"Price(ctx context.Context, currency *string) (*money.Amount, error)",
+			"After Optional[time.Time]",
+		} {
+			if !strings.Contains(b.String(), ex) {
+				subT.Errorf("expected the generated code to contain:\n%s\n\nbut got:\n%s", ex, b.String())
+			}
+		}
+		if strings.Contains(b.String(), "MarshalGQL") {
+			subT.Error("expected no types to be generated for bound scalars")
+		}
+	})
+
+	t.Run("NotAScalar", func(subT *testing.T) {
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{"scalars": "Filter=time.Time"})
+		if err == nil || !strings.Contains(err.Error(), "Filter is bound to time.Time, but it isn't a scalar") {
+			subT.Errorf("expected only scalars to be bindable, but got: %v", err)
+		}
+	})
+}
+
+func TestInputStructs(t *testing.T) {
+	gqlSrc := `input Filter {
+	id: ID!
+	"name matches names."
+	name: String
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), parser.ParseComments)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := &Generator{}
+	g.Reset()
+	g.generateInputStructs(doc, true, tagger{})
+
+	ex := "\n" + optionalDecl + `
+type Filter struct {
+	ID string ` + "`json:\"id\"`" + `
+	// name matches names.
+	Name Optional[string] ` + "`json:\"name\"`" + `
+}
+
+// MarshalJSON implements the json.Marshaler interface, leaving out absent fields.
+func (in Filter) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, 2)
+	m["id"] = in.ID
+	if in.Name.Set {
+		m["name"] = in.Name
+	}
+	return json.Marshal(m)
+}
+`
+
+	gen.CompareBytes(t, []byte(ex), g.Bytes())
+}
+
+func TestResolvers(t *testing.T) {
+	gqlSrc := `type Query {
+	"hello greets."
+	hello: String!
+	user(id: ID!, type: String): User
+}
+
+"A User."
+type User {
+	id: ID!
+	best: User!
+	friends(first: Int = 10): [User]
+}
+
+type Empty {
+	a: Int
+	userId: ID
+}
+
+type Subscription {
+	userAdded(groupId: ID!): User!
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), parser.ParseComments)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := &Generator{}
+	g.Reset()
+	g.generateResolvers(doc, true, true, "", tagger{})
+
+	ex := `
+// A User.
+type User struct {
+	ID string ` + "`json:\"id\"`" + `
+	Best *User ` + "`json:\"best\"`" + `
+}
+
+type Empty struct {
+	A *int ` + "`json:\"a\"`" + `
+	UserID *string ` + "`json:\"userId\"`" + `
+}
+
+// QueryResolver resolves the fields of Query.
+type QueryResolver interface {
+	// hello greets.
+	Hello(ctx context.Context) (string, error)
+	User(ctx context.Context, id string, type_ *string) (*User, error)
+}
+
+// DefaultQueryResolver resolves every field of Query to its zero value. It
+// can be embedded by a QueryResolver, which only resolves some of them.
+type DefaultQueryResolver struct{}
+
+func (DefaultQueryResolver) Hello(ctx context.Context) (string, error) { return "", nil }
+
+func (DefaultQueryResolver) User(ctx context.Context, id string, type_ *string) (*User, error) { return nil, nil }
+
+// UserResolver resolves the fields of User.
+type UserResolver interface {
+	Friends(ctx context.Context, obj *User, first *int) ([]*User, error)
+}
+
+// DefaultUserResolver resolves every field of User to its zero value. It
+// can be embedded by a UserResolver, which only resolves some of them.
+type DefaultUserResolver struct{}
+
+func (DefaultUserResolver) Friends(ctx context.Context, obj *User, first *int) ([]*User, error) { return nil, nil }
+
+// SubscriptionResolver resolves the fields of Subscription.
+type SubscriptionResolver interface {
+	UserAdded(ctx context.Context, groupId string) (<-chan *User, error)
+}
+
+// DefaultSubscriptionResolver resolves every field of Subscription to its zero value. It
+// can be embedded by a SubscriptionResolver, which only resolves some of them.
+type DefaultSubscriptionResolver struct{}
+
+func (DefaultSubscriptionResolver) UserAdded(ctx context.Context, groupId string) (<-chan *User, error) { return nil, nil }
+`
+
+	gen.CompareBytes(t, []byte(ex), g.Bytes())
+
+	t.Run("Imports", func(subT *testing.T) {
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{"resolvers": true})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if !strings.HasPrefix(b.String(), "package main\n\nimport (\n\t\"context\"\n\t\"encoding/json\"\n\n\t\"github.com/graphql-go/graphql\"\n)\n") {
+			subT.Errorf("expected context and encoding/json to be imported, but got:\n%s", b.String())
+		}
+	})
+}
+
+func TestMocks(t *testing.T) {
+	gqlSrc := `type Query {
+	user(id: ID!): User
+}
+
+type User {
+	id: ID!
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	testCases := []struct {
+		Name  string
+		Mocks string
+		Ex    string
+	}{
+		{
+			Name:  "Funcs",
+			Mocks: "funcs",
+			Ex: `
+type MockQueryResolver struct {
+	UserFunc func(ctx context.Context, id string) (*User, error)
+}
+
+func (m *MockQueryResolver) User(ctx context.Context, id string) (*User, error) {
+	if m.UserFunc == nil {
+		panic("MockQueryResolver.UserFunc is nil, but User was called")
+	}
+	return m.UserFunc(ctx, id)
+}
+`,
+		},
+		{
+			Name:  "Gomock",
+			Mocks: "gomock",
+			Ex: `
+type MockQueryResolver struct {
+	ctrl *gomock.Controller
+	recorder *MockQueryResolverMockRecorder
+}
+
+type MockQueryResolverMockRecorder struct {
+	mock *MockQueryResolver
+}
+
+func NewMockQueryResolver(ctrl *gomock.Controller) *MockQueryResolver {
+	mock := &MockQueryResolver{ctrl: ctrl}
+	mock.recorder = &MockQueryResolverMockRecorder{mock}
+	return mock
+}
+
+func (m *MockQueryResolver) EXPECT() *MockQueryResolverMockRecorder {
+	return m.recorder
+}
+
+func (m *MockQueryResolver) User(ctx context.Context, id string) (*User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "User", ctx, id)
+	ret0, _ := ret[0].(*User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockQueryResolverMockRecorder) User(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "User", reflect.TypeOf((*MockQueryResolver)(nil).User), ctx, id)
+}
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			g := &Generator{}
+			g.Reset()
+			g.generateResolvers(doc, false, false, testCase.Mocks, tagger{})
+
+			ex := `
+type User struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+
+type QueryResolver interface {
+	User(ctx context.Context, id string) (*User, error)
+}
+` + testCase.Ex
+
+			gen.CompareBytes(subT, []byte(ex), g.Bytes())
+		})
+	}
+
+	t.Run("Imports", func(subT *testing.T) {
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{"resolvers": true, "mocks": "gomock"})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if !strings.HasPrefix(b.String(), "package main\n\nimport (\n\t\"context\"\n\t\"encoding/json\"\n\t\"reflect\"\n\n\t\"github.com/golang/mock/gomock\"\n\t\"github.com/graphql-go/graphql\"\n)\n") {
+			subT.Errorf("expected reflect and gomock to be imported, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("Unknown", func(subT *testing.T) {
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: ioutil.Discard})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{"resolvers": true, "mocks": "mockery"})
+		if err == nil || !strings.Contains(err.Error(), "unknown mocks option: mockery") {
+			subT.Errorf("expected unknown mocks option error, but got: %v", err)
+		}
+	})
+}
+
+func TestTags(t *testing.T) {
+	gqlSrc := `type User {
+	id: ID!
+	firstName: String @goTag(tags: ["validate:required", "db:given_name"])
+	userID: Int
+}
+
+input UserInput {
+	lastName: String @goTag(tags: "json:-")
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), parser.ParseComments)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tags := tagger{naming: namingSnake, omitEmpty: true, keys: []string{"db"}}
+
+	g := &Generator{}
+	g.Reset()
+	g.generateResolvers(doc, false, false, "", tags)
+
+	ex := `
+type User struct {
+	ID string ` + "`json:\"id\" db:\"id\"`" + `
+	FirstName *string ` + "`json:\"first_name,omitempty\" db:\"given_name\" validate:\"required\"`" + `
+	UserID *int ` + "`json:\"user_id,omitempty\" db:\"user_id\"`" + `
+}
+`
+
+	gen.CompareBytes(t, []byte(ex), g.Bytes())
+
+	t.Run("Inputs", func(subT *testing.T) {
+		g.Reset()
+		g.generateInputStructs(doc, false, tags)
+
+		for _, ex := range []string{
+			"LastName Optional[string] `json:\"-\" db:\"last_name\"`",
+			`m["last_name"] = in.LastName`,
+		} {
+			if !strings.Contains(g.String(), ex) {
+				subT.Errorf("expected output to contain:\n%s\nbut got:\n%s", ex, g.String())
+			}
+		}
+	})
+
+	t.Run("Invalid", func(subT *testing.T) {
+		testCases := []struct {
+			Name string
+			Src  string
+			Opts map[string]interface{}
+			Err  string
+		}{
+			{
+				Name: "Naming",
+				Src:  gqlSrc,
+				Opts: map[string]interface{}{"jsonNaming": "kebab"},
+				Err:  "unknown jsonNaming option: kebab, expected camel or snake",
+			},
+			{
+				Name: "Key",
+				Src:  gqlSrc,
+				Opts: map[string]interface{}{"tags": []string{"db", "json"}},
+				Err:  `invalid struct tag key: "json"`,
+			},
+			{
+				Name: "Directive",
+				Src:  `type User { name: String @goTag(tags: ["required"]) }`,
+				Opts: map[string]interface{}{"resolvers": true},
+				Err:  `User: malformed struct tag: "required", expected key:value`,
+			},
+		}
+
+		for _, testCase := range testCases {
+			subT.Run(testCase.Name, func(triT *testing.T) {
+				doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(testCase.Src), 0)
+				if err != nil {
+					triT.Fatal(err)
+				}
+
+				var b bytes.Buffer
+				ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+				err = new(Generator).Generate(ctx, doc, testCase.Opts)
+				if err == nil || !strings.HasSuffix(err.Error(), testCase.Err) {
+					triT.Errorf("expected error: %s, but got: %v", testCase.Err, err)
+				}
+			})
+		}
+	})
+}
+
+func TestJSONName(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		Camel string
+		Snake string
+	}{
+		{Name: "id", Camel: "id", Snake: "id"},
+		{Name: "firstName", Camel: "firstName", Snake: "first_name"},
+		{Name: "first_name", Camel: "firstName", Snake: "first_name"},
+		{Name: "FirstName", Camel: "firstName", Snake: "first_name"},
+		{Name: "userID", Camel: "userID", Snake: "user_id"},
+		{Name: "HTTPStatus", Camel: "httpStatus", Snake: "http_status"},
+		{Name: "ID", Camel: "id", Snake: "id"},
+		{Name: "address2Line", Camel: "address2Line", Snake: "address2_line"},
+		{Name: "_id", Camel: "id", Snake: "_id"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			if name := (tagger{naming: namingCamel}).jsonName(testCase.Name); name != testCase.Camel {
+				subT.Errorf("expected camel case: %s, but got: %s", testCase.Camel, name)
+			}
+			if name := (tagger{naming: namingSnake}).jsonName(testCase.Name); name != testCase.Snake {
+				subT.Errorf("expected snake case: %s, but got: %s", testCase.Snake, name)
+			}
+		})
+	}
+}
+
+func TestCommentWidth(t *testing.T) {
+	gqlSrc := `"A filter of users, by their name or email."
+input Filter {
+	"name matches names, ignoring case."
+	name: String
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), parser.ParseComments)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err = new(Generator).Generate(ctx, doc, map[string]interface{}{"descriptions": true, "optionals": true, "commentWidth": int64(32)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ex := `// A filter of users, by their
+// name or email.
+type Filter struct {
+	// name matches names,
+	// ignoring case.
+	Name Optional[string] ` + "`json:\"name\"`" + `
+}
+`
+	if !strings.Contains(b.String(), ex) {
+		t.Errorf("expected %q in:\n%s", ex, b.String())
+	}
+}
+
 func TestDirective(t *testing.T) {
 	g := &Generator{}
 
 	t.Run("NoArgs", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Directive{
@@ -566,8 +1283,6 @@ func TestDirective(t *testing.T) {
 	})
 
 	t.Run("WithArgs", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Directive{
@@ -637,6 +1352,41 @@ func TestDirective(t *testing.T) {
 	})
 }
 
+type depthCtx struct {
+	gen.TestCtx
+
+	maxDepth int
+}
+
+func (ctx depthCtx) MaxDepth() int { return ctx.maxDepth }
+
+func TestGenerate_MaxDepth(t *testing.T) {
+	l, r := strings.Repeat("[", 150), strings.Repeat("]", 150)
+	gqlSrc := fmt.Sprintf("type Query {\n\ta(b: %sInt%s = %s1%s): %sString%s\n}\n", l, r, l, r, l, r)
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err = new(Generator).Generate(ctx, doc, nil)
+	if err == nil || !strings.Contains(err.Error(), "type is nested deeper than the maximum depth of 100") {
+		t.Errorf("expected the document to be too deep, but got: %v", err)
+	}
+
+	b.Reset()
+	ctx = gen.WithContext(context.Background(), depthCtx{TestCtx: gen.TestCtx{Writer: &b}, maxDepth: 150})
+	err = new(Generator).Generate(ctx, doc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), strings.Repeat("graphql.NewList(", 150)+"graphql.String") {
+		t.Error("expected the nested type to be generated")
+	}
+}
+
 func TestGenerator_Generate(t *testing.T) {
 	g := &Generator{}
 
@@ -710,7 +1460,7 @@ type Query {
 	// 	Name: "Query",
 	//	Fields: graphql.Fields{
 	//		"hello": &graphql.Field{
-	//			Type: graphql.String,
+	//			Type:    graphql.String,
 	//			Resolve: func(p graphql.ResolveParams) (interface{}, error) { return nil, nil },
 	//		},
 	//	},
@@ -718,3 +1468,497 @@ type Query {
 	// })
 	//
 }
+
+func TestPackageName(t *testing.T) {
+	testCases := []struct {
+		ImportPath string
+		Package    string
+	}{
+		{ImportPath: "", Package: "main"},
+		{ImportPath: "example.com/api", Package: "api"},
+		{ImportPath: "example.com/api/v2", Package: "api"},
+		{ImportPath: "example.com/go-graphql", Package: "graphql"},
+		{ImportPath: "example.com/graph.ql", Package: "graph_ql"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.ImportPath, func(subT *testing.T) {
+			if name := packageName(testCase.ImportPath); name != testCase.Package {
+				subT.Errorf("expected package %s, but got: %s", testCase.Package, name)
+			}
+		})
+	}
+}
+
+func TestFormatOptions(t *testing.T) {
+	opts := map[string]interface{}{
+		"package":      `"api"`,
+		"descriptions": true,
+		"exclude":      []string{"A", "B"},
+	}
+
+	ex := `descriptions=true,exclude=A,exclude=B,package="api"`
+	if s := formatOptions(opts); s != ex {
+		t.Errorf("expected options %s, but got: %s", ex, s)
+	}
+}
+
+func TestGenerator_Generate_Module(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "schema", strings.NewReader(`scalar Time`), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	files := map[string][]byte{
+		"/src/go.mod": []byte("// Module api serves things\nmodule example.com/api // the api\n\ngo 1.18\n"),
+	}
+
+	testCases := []struct {
+		Name   string
+		Dir    string
+		Src    string
+		Opts   map[string]interface{}
+		Header string
+	}{
+		{
+			Name:   "NoModule",
+			Dir:    "/out",
+			Header: "package main\n\nimport \"github.com/graphql-go/graphql\"\n\n",
+		},
+		{
+			Name:   "Module",
+			Dir:    "/src/graph/gql",
+			Header: "package gql\n\nimport \"github.com/graphql-go/graphql\"\n\n",
+		},
+		{
+			Name:   "Package",
+			Dir:    "/src/graph/gql",
+			Opts:   map[string]interface{}{"package": `"graph"`},
+			Header: "package graph\n\nimport \"github.com/graphql-go/graphql\"\n\n",
+		},
+		{
+			Name:   "Generate",
+			Dir:    "/src/graph",
+			Src:    "/src/graph/schema.gql",
+			Opts:   map[string]interface{}{"generate": true},
+			Header: "package graph\n\nimport \"github.com/graphql-go/graphql\"\n\n//go:generate gqlc --go_out . --go_opt generate=true schema.gql\n\n",
+		},
+		{
+			Name:   "GenerateImportPath",
+			Dir:    "/src/graph",
+			Src:    "/src/schema/schema.gql",
+			Opts:   map[string]interface{}{"generate": true},
+			Header: "package graph\n\nimport \"github.com/graphql-go/graphql\"\n\n//go:generate gqlc --go_out . --go_opt generate=true -I ../schema ../schema/schema.gql\n\n",
+		},
+		{
+			Name:   "GenerateUnknownSource",
+			Dir:    "/src/graph",
+			Opts:   map[string]interface{}{"generate": true},
+			Header: "package graph\n\nimport \"github.com/graphql-go/graphql\"\n\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			var b bytes.Buffer
+			ctx := gen.WithContext(context.Background(), gen.TestPathCtx{
+				TestCtx: gen.TestCtx{Writer: &b},
+				DirPath: testCase.Dir,
+				Sources: map[string]string{"schema": testCase.Src},
+				Files:   files,
+			})
+
+			err := new(Generator).Generate(ctx, doc, testCase.Opts)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if !bytes.HasPrefix(b.Bytes(), []byte(testCase.Header)) {
+				subT.Errorf("expected header:\n%s\nbut got:\n%s", testCase.Header, b.String())
+			}
+		})
+	}
+}
+
+func TestFederation(t *testing.T) {
+	gqlSrc := `type Query {
+	me: User
+}
+
+type User @key(fields: "id") {
+	id: ID!
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err = new(Generator).Generate(ctx, doc, map[string]interface{}{"federation": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, ex := range []string{
+		"const ServiceSDL = `extend schema @link(",
+		"type User @key(fields: \"id\") {\n  id: ID!\n}\n`\n",
+		"\"_entities\": &graphql.Field{\n\t\t\tType: graphql.NewNonNull(graphql.NewList(_EntityType)),",
+		"\"_service\": &graphql.Field{\n\t\t\tType:    graphql.NewNonNull(_ServiceType),\n\t\t\tResolve: func(p graphql.ResolveParams) (interface{}, error) { return struct{}{}, nil },",
+		"Types:       []*graphql.Object{UserType},",
+		"Resolve: func(p graphql.ResolveParams) (interface{}, error) { return ServiceSDL, nil },",
+	} {
+		if !strings.Contains(b.String(), ex) {
+			t.Errorf("expected output to contain:\n%s\nbut got:\n%s", ex, b.String())
+		}
+	}
+}
+
+// openedCtx records the names of the files opened in it.
+type openedCtx struct {
+	gen.TestPathCtx
+
+	names *[]string
+}
+
+func (ctx openedCtx) Open(name string) (io.WriteCloser, error) {
+	*ctx.names = append(*ctx.names, filepath.ToSlash(name))
+	return ctx.TestPathCtx.Open(name)
+}
+
+func TestGenerator_Generate_Workspace(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "schema", strings.NewReader(`scalar Time`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workspace := map[string][]byte{
+		"/ws/go.work":       []byte("go 1.18\n\nuse (\n\t./api\n\t\"./api/v2\" // a major version\n)\n\nuse ./users\n"),
+		"/ws/api/go.mod":    []byte("module example.com/api\n"),
+		"/ws/api/v2/go.mod": []byte("module example.com/api/v2\n"),
+		"/ws/users/go.mod":  []byte("module example.com/users\n"),
+	}
+
+	testCases := []struct {
+		Name   string
+		Dir    string
+		Files  map[string][]byte
+		Opts   map[string]interface{}
+		File   string
+		Header string
+		Err    string
+	}{
+		{
+			Name:   "OtherModule",
+			Dir:    "/ws/api/graph",
+			Files:  workspace,
+			Opts:   map[string]interface{}{"importPath": `"example.com/users/graph/gql"`},
+			File:   "../../users/graph/gql/schema.go",
+			Header: "package gql\n",
+		},
+		{
+			Name:   "SameDir",
+			Dir:    "/ws/api/graph",
+			Files:  workspace,
+			Opts:   map[string]interface{}{"importPath": "example.com/api/graph"},
+			File:   "schema.go",
+			Header: "package graph\n",
+		},
+		{
+			Name:   "NestedModule",
+			Dir:    "/ws/api/graph",
+			Files:  workspace,
+			Opts:   map[string]interface{}{"importPath": "example.com/api/v2/graph", "package": "api"},
+			File:   "../v2/graph/schema.go",
+			Header: "package api\n",
+		},
+		{
+			Name:   "NoWorkspace",
+			Dir:    "/src/graph",
+			Files:  map[string][]byte{"/src/go.mod": []byte("module example.com/api\n")},
+			Opts:   map[string]interface{}{"importPath": "example.com/api/models"},
+			File:   "../models/schema.go",
+			Header: "package models\n",
+		},
+		{
+			Name:  "UnknownModule",
+			Dir:   "/ws/api/graph",
+			Files: workspace,
+			Opts:  map[string]interface{}{"importPath": "example.com/orders"},
+			Err:   "no module provides example.com/orders",
+		},
+		{
+			Name:  "MissingModule",
+			Dir:   "/ws/api",
+			Files: map[string][]byte{"/ws/go.work": []byte("use ./api\nuse ./gone\n"), "/ws/api/go.mod": []byte("module example.com/api\n")},
+			Opts:  map[string]interface{}{"importPath": "example.com/api"},
+			Err:   "/ws/go.work: module /ws/gone doesn't exist",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			var b bytes.Buffer
+			var names []string
+			ctx := gen.WithContext(context.Background(), openedCtx{
+				TestPathCtx: gen.TestPathCtx{
+					TestCtx: gen.TestCtx{Writer: &b},
+					DirPath: testCase.Dir,
+					Files:   testCase.Files,
+				},
+				names: &names,
+			})
+
+			err := new(Generator).Generate(ctx, doc, testCase.Opts)
+			if testCase.Err != "" {
+				if err == nil || !strings.HasSuffix(err.Error(), testCase.Err) {
+					subT.Errorf("expected error: %s, but got: %v", testCase.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if len(names) != 1 || names[0] != testCase.File {
+				subT.Errorf("expected %s to be written, but got: %v", testCase.File, names)
+			}
+			if !bytes.HasPrefix(b.Bytes(), []byte(testCase.Header)) {
+				subT.Errorf("expected header:\n%s\nbut got:\n%s", testCase.Header, b.String())
+			}
+		})
+	}
+}
+
+// filesCtx records the contents of the files opened in it.
+type filesCtx struct {
+	gen.TestPathCtx
+
+	files map[string]*bytes.Buffer
+}
+
+func (ctx filesCtx) Open(name string) (io.WriteCloser, error) {
+	b := new(bytes.Buffer)
+	ctx.files[filepath.ToSlash(name)] = b
+	return gen.TestCtx{Writer: b}, nil
+}
+
+func TestSplit(t *testing.T) {
+	gqlSrc := `scalar DateTime
+
+enum Role {
+	ADMIN
+}
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	role: Role!
+	joined: DateTime
+	friends(first: Int): [User!]
+}
+
+input UserInput {
+	role: Role
+}
+
+type Query {
+	node(id: ID!): Node
+	user(in: UserInput!): User
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "schema", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	module := map[string][]byte{"/src/go.mod": []byte("module example.com/api\n")}
+
+	testCases := []struct {
+		Name  string
+		Dir   string
+		Split []string
+		Files map[string][]string
+		Err   string
+	}{
+		{
+			Name:  "All",
+			Dir:   "/src/graph",
+			Split: []string{"models", "enums", "resolvers"},
+			Files: map[string][]string{
+				"schema.go": {"package graph\n\nimport \"github.com/graphql-go/graphql\"\n\n", "var UserType = "},
+				"enums/schema.go": {
+					"package enums\n\nimport (\n\t\"encoding/json\"\n\t\"fmt\"\n)\n\ntype Role string\n",
+				},
+				"models/schema.go": {
+					"package models\n\nimport (\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"io\"\n\t\"strconv\"\n\n\t\"example.com/api/graph/enums\"\n)\n\ntype DateTime string\n",
+					"\tRole Optional[enums.Role] `json:\"role\"`\n",
+					"\tRole   enums.Role `json:\"role\"`\n",
+					"\tJoined *DateTime  `json:\"joined\"`\n",
+					"func (*User) IsNode() {}\n",
+				},
+				"resolvers/schema.go": {
+					"package resolvers\n\nimport (\n\t\"context\"\n\n\t\"example.com/api/graph/models\"\n)\n",
+					"\tNode(ctx context.Context, id string) (models.Node, error)\n",
+					"\tUser(ctx context.Context, in models.UserInput) (*models.User, error)\n",
+					"\tFriends(ctx context.Context, obj *models.User, first *int) ([]*models.User, error)\n",
+				},
+			},
+		},
+		{
+			Name:  "Enums",
+			Dir:   "/src/graph",
+			Split: []string{"enums"},
+			Files: map[string][]string{
+				"schema.go": {
+					"package graph\n\nimport (\n\t\"context\"\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"io\"\n\t\"strconv\"\n\n\t\"example.com/api/graph/enums\"\n\t\"github.com/graphql-go/graphql\"\n)\n",
+					"\tRole   enums.Role `json:\"role\"`\n",
+				},
+				"enums/schema.go": {"package enums\n"},
+			},
+		},
+		{
+			Name:  "Resolvers",
+			Dir:   "/src/graph",
+			Split: []string{"resolvers"},
+			Files: map[string][]string{
+				"schema.go": {
+					"package graph\n\nimport (\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"io\"\n\t\"strconv\"\n\n\t\"github.com/graphql-go/graphql\"\n)\n",
+					"type User struct {\n",
+				},
+				"resolvers/schema.go": {
+					"package resolvers\n\nimport (\n\t\"context\"\n\n\t\"example.com/api/graph\"\n)\n",
+					"\tUser(ctx context.Context, in graph.UserInput) (*graph.User, error)\n",
+				},
+			},
+		},
+		{
+			Name:  "UnknownPackage",
+			Dir:   "/src/graph",
+			Split: []string{"types"},
+			Err:   `unknown split package: "types", expected models, enums or resolvers`,
+		},
+		{
+			Name:  "NoModule",
+			Dir:   "/out",
+			Split: []string{"models"},
+			Err:   "split needs the import path of the output directory, but it isn't in a Go module",
+		},
+		{
+			Name:  "Main",
+			Dir:   "/src",
+			Split: []string{"resolvers"},
+			Err:   "resolvers can't be split out of package main, since it can't be imported",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			ctx := filesCtx{
+				TestPathCtx: gen.TestPathCtx{DirPath: testCase.Dir, Files: module},
+				files:       make(map[string]*bytes.Buffer),
+			}
+
+			opts := map[string]interface{}{"resolvers": true, "split": testCase.Split}
+			if testCase.Name == "Main" {
+				opts["package"] = "main"
+			}
+
+			err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), doc, opts)
+			if testCase.Err != "" {
+				if err == nil || !strings.HasSuffix(err.Error(), testCase.Err) {
+					subT.Errorf("expected error: %s, but got: %v", testCase.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if len(ctx.files) != len(testCase.Files) {
+				subT.Errorf("expected %d files, but got: %d", len(testCase.Files), len(ctx.files))
+			}
+			for name, exs := range testCase.Files {
+				b, ok := ctx.files[name]
+				if !ok {
+					subT.Errorf("expected %s to be written", name)
+					continue
+				}
+
+				if !bytes.HasPrefix(b.Bytes(), []byte(exs[0])) {
+					subT.Errorf("expected %s to begin with:\n%s\nbut got:\n%s", name, exs[0], b)
+				}
+				for _, ex := range exs[1:] {
+					if !strings.Contains(b.String(), ex) {
+						subT.Errorf("expected %s to contain:\n%s\nbut got:\n%s", name, ex, b)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFormatSource(t *testing.T) {
+	src := "package api\n\nvar A = 1\n\nvar B = graphql.NewObject(graphql.ObjectConfig{\n\tName: \"B\"\n})\n\nvar C = 3\n"
+
+	_, err := formatSource("graph/schema.go", []byte(src))
+	if err == nil {
+		t.Fatal("expected invalid code not to format")
+	}
+
+	ex := `generated invalid Go, graph/schema.go:6:11: missing ',' before newline in composite literal
+     4 | 
+     5 | var B = graphql.NewObject(graphql.ObjectConfig{
+>    6 | 	Name: "B"
+     7 | })
+     8 | 
+`
+	if err.Error() != ex {
+		t.Errorf("expected error:\n%s\nbut got:\n%s", ex, err)
+	}
+}
+
+func TestFixImports(t *testing.T) {
+	src := `package api
+
+import (
+	"context"
+	"fmt"
+	_ "embed"
+
+	"example.com/api/enums"
+	uuid "github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+)
+
+var UserType = graphql.NewObject(graphql.ObjectConfig{Name: "User"})
+
+func (*User) Role() enums.Role { return "" }
+`
+
+	out, err := fixImports([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ex := `package api
+
+import (
+	_ "embed"
+
+	"example.com/api/enums"
+	"github.com/graphql-go/graphql"
+)
+`
+	if !strings.HasPrefix(string(out), ex) {
+		t.Errorf("expected imports:\n%s\nbut got:\n%s", ex, out)
+	}
+}
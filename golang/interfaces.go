@@ -0,0 +1,83 @@
+package golang
+
+import (
+	"github.com/gqlc/graphql/ast"
+)
+
+// generateInterfaceTypes generates a Go interface for every interface and
+// union. An interface X has an IsX marker method, while a union U is
+// sealed by an unexported isU method, so only the structs of its members
+// implement it. Either way, type switches over them are checked by the
+// compiler.
+//
+func (g *Generator) generateInterfaceTypes(doc *ast.Document, descr bool) {
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		var marker string
+		switch ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Interface:
+			marker = interfaceMarker(ts.TypeSpec.Name.Name)
+		case *ast.TypeSpec_Union:
+			marker = unionMarker(ts.TypeSpec.Name.Name)
+		default:
+			continue
+		}
+
+		g.P()
+		if d.Doc != nil && descr {
+			g.printComment(d.Doc)
+		}
+		g.P("type ", ts.TypeSpec.Name.Name, " interface {")
+		g.In()
+		g.P(marker, "()")
+		g.Out()
+		g.P("}")
+	}
+}
+
+// markers returns the marker methods of the interfaces and unions each
+// object belongs to, in the order they're declared.
+//
+func markers(doc *ast.Document) map[string][]string {
+	ms := make(map[string][]string)
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			for _, inter := range v.Object.Interfaces {
+				ms[ts.TypeSpec.Name.Name] = append(ms[ts.TypeSpec.Name.Name], interfaceMarker(inter.Name))
+			}
+		case *ast.TypeSpec_Union:
+			for _, mem := range v.Union.Members {
+				ms[mem.Name] = append(ms[mem.Name], unionMarker(ts.TypeSpec.Name.Name))
+			}
+		}
+	}
+	return ms
+}
+
+// generateMarkers implements the marker methods of an object's interfaces
+// and unions. They have pointer receivers, like the struct is used.
+//
+func (g *Generator) generateMarkers(name string, markers []string) {
+	if len(markers) == 0 {
+		return
+	}
+
+	g.P()
+	for _, m := range markers {
+		g.P("func (*", name, ") ", m, "() {}")
+	}
+}
+
+func interfaceMarker(name string) string { return "Is" + name }
+
+func unionMarker(name string) string { return "is" + name }
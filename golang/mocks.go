@@ -0,0 +1,120 @@
+package golang
+
+import "strings"
+
+// The kinds of mocks which can be generated for resolver interfaces.
+const (
+	mockFuncs  = "funcs"
+	mockGomock = "gomock"
+)
+
+const gomockPath = "github.com/golang/mock/gomock"
+
+// generateFuncMock generates a Mock<Type>Resolver, which resolves each
+// field with a func of the same signature as its method, e.g. HelloFunc
+// resolves Hello. Methods whose func isn't set panic, since the test didn't
+// expect them to be called.
+//
+func (g *Generator) generateFuncMock(name string, methods []*resolverMethod, descr bool) {
+	mock := "Mock" + name + "Resolver"
+
+	g.P()
+	if descr {
+		g.P("// ", mock, " is a ", name, "Resolver, which resolves each field with its func.")
+	}
+	g.P("type ", mock, " struct {")
+	g.In()
+	for _, m := range methods {
+		g.P(m.name, "Func ", m.funcType())
+	}
+	g.Out()
+	g.P("}")
+
+	for _, m := range methods {
+		g.P()
+		g.P("func (m *", mock, ") ", m.signature(), " {")
+		g.In()
+		g.P("if m.", m.name, "Func == nil {")
+		g.In()
+		g.P(`panic("`, mock, ".", m.name, "Func is nil, but ", m.name, ` was called")`)
+		g.Out()
+		g.P("}")
+		g.P("return m.", m.name, "Func(", strings.Join(m.params, ", "), ")")
+		g.Out()
+		g.P("}")
+	}
+}
+
+// generateGomock generates a Mock<Type>Resolver like mockgen does, so tests
+// expect calls to it with a gomock.Controller, and can resolve them with
+// DoAndReturn.
+//
+func (g *Generator) generateGomock(name string, methods []*resolverMethod, descr bool) {
+	mock := "Mock" + name + "Resolver"
+	recorder := mock + "MockRecorder"
+
+	g.P()
+	if descr {
+		g.P("// ", mock, " is a mock of ", name, "Resolver.")
+	}
+	g.P("type ", mock, " struct {")
+	g.In()
+	g.P("ctrl *gomock.Controller")
+	g.P("recorder *", recorder)
+	g.Out()
+	g.P("}")
+	g.P()
+	if descr {
+		g.P("// ", recorder, " records the calls expected of a ", mock, ".")
+	}
+	g.P("type ", recorder, " struct {")
+	g.In()
+	g.P("mock *", mock)
+	g.Out()
+	g.P("}")
+	g.P()
+	if descr {
+		g.P("// New", mock, " returns a mock ", name, "Resolver, which is controlled by ctrl.")
+	}
+	g.P("func New", mock, "(ctrl *gomock.Controller) *", mock, " {")
+	g.In()
+	g.P("mock := &", mock, "{ctrl: ctrl}")
+	g.P("mock.recorder = &", recorder, "{mock}")
+	g.P("return mock")
+	g.Out()
+	g.P("}")
+	g.P()
+	if descr {
+		g.P("// EXPECT returns a recorder of the calls expected of the mock.")
+	}
+	g.P("func (m *", mock, ") EXPECT() *", recorder, " {")
+	g.In()
+	g.P("return m.recorder")
+	g.Out()
+	g.P("}")
+
+	for _, m := range methods {
+		args := append([]string{"m", `"` + m.name + `"`}, m.params...)
+
+		g.P()
+		g.P("func (m *", mock, ") ", m.signature(), " {")
+		g.In()
+		g.P("m.ctrl.T.Helper()")
+		g.P("ret := m.ctrl.Call(", strings.Join(args, ", "), ")")
+		g.P("ret0, _ := ret[0].(", m.result, ")")
+		g.P("ret1, _ := ret[1].(error)")
+		g.P("return ret0, ret1")
+		g.Out()
+		g.P("}")
+
+		args = append([]string{"mr.mock", `"` + m.name + `"`, "reflect.TypeOf((*" + mock + ")(nil)." + m.name + ")"}, m.params...)
+
+		g.P()
+		g.P("func (mr *", recorder, ") ", m.name, "(", strings.Join(m.params, ", "), " interface{}) *gomock.Call {")
+		g.In()
+		g.P("mr.mock.ctrl.T.Helper()")
+		g.P("return mr.mock.ctrl.RecordCallWithMethodType(", strings.Join(args, ", "), ")")
+		g.Out()
+		g.P("}")
+	}
+}
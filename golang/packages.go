@@ -0,0 +1,171 @@
+package golang
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gqlc/graphql/ast"
+)
+
+// The sub-packages generated code can be split into.
+const (
+	pkgModels    = "models"
+	pkgEnums     = "enums"
+	pkgResolvers = "resolvers"
+)
+
+// subPackages are the sub-packages, in the order their files are written.
+var subPackages = []string{pkgEnums, pkgModels, pkgResolvers}
+
+// layout places generated code in the sub-packages of the output package
+// it's split into. Types referred to from another package are qualified
+// by it, and it's imported.
+//
+type layout struct {
+	// importPath and name are of the output package
+	importPath, name string
+
+	split map[string]bool
+
+	// pkg is the package being generated, which is empty for the output
+	// package itself.
+	//
+	pkg string
+
+	// code and imports are those of each package
+	code    map[string][]byte
+	imports map[string][]string
+}
+
+// newLayout returns the layout of an output package, which the named
+// sub-packages are split out of.
+//
+func newLayout(importPath, name string, split []string) (*layout, error) {
+	if importPath == "" {
+		return nil, fmt.Errorf("split needs the import path of the output directory, but it isn't in a Go module")
+	}
+
+	l := &layout{
+		importPath: importPath,
+		name:       name,
+		split:      make(map[string]bool, len(split)),
+		code:       make(map[string][]byte),
+		imports:    make(map[string][]string),
+	}
+	for _, pkg := range split {
+		switch pkg {
+		case pkgModels, pkgEnums, pkgResolvers:
+			l.split[pkg] = true
+		default:
+			return nil, fmt.Errorf("unknown split package: %q, expected models, enums or resolvers", pkg)
+		}
+	}
+
+	// Resolvers refer to models, which would be in package main
+	if l.split[pkgResolvers] && !l.split[pkgModels] && name == "main" {
+		return nil, fmt.Errorf("resolvers can't be split out of package main, since it can't be imported")
+	}
+	return l, nil
+}
+
+// place returns the package code of the given sub-package is generated
+// into. Enums go with the models, unless they're split out themselves.
+//
+func (l *layout) place(pkg string) string {
+	if l == nil {
+		return ""
+	}
+
+	if pkg == pkgEnums && !l.split[pkgEnums] {
+		pkg = pkgModels
+	}
+	if l.split[pkg] {
+		return pkg
+	}
+	return ""
+}
+
+// typePackage returns the package the Go type of a kind is generated into,
+// if one is generated for it.
+//
+func (l *layout) typePackage(kind interface{}) (string, bool) {
+	switch kind.(type) {
+	case *ast.TypeSpec_Enum:
+		return l.place(pkgEnums), true
+	case *ast.TypeSpec_Scalar, *ast.TypeSpec_Input, *ast.TypeSpec_Object, *ast.TypeSpec_Interface, *ast.TypeSpec_Union:
+		return l.place(pkgModels), true
+	}
+	return "", false
+}
+
+// qualify qualifies a Go type, e.g. []*User, by the package it's generated
+// into, if that isn't the one being generated, and imports it.
+//
+func (l *layout) qualify(kinds map[string]interface{}, typ string) string {
+	if l == nil {
+		return typ
+	}
+
+	name := strings.TrimLeft(typ, "[]*")
+	pkg, ok := l.typePackage(kinds[name])
+	if !ok || pkg == l.pkg {
+		return typ
+	}
+
+	imp, qual := l.importPath, l.name
+	if pkg != "" {
+		imp, qual = imp+"/"+pkg, pkg
+	}
+	if qual != path.Base(imp) {
+		imp = qual + " " + imp
+	}
+	l.imports[l.pkg] = append(l.imports[l.pkg], imp)
+
+	return typ[:len(typ)-len(name)] + qual + "." + name
+}
+
+// section generates code into the package of the given sub-package. It's
+// moved out of the buffer, into that package, unless the code isn't split.
+//
+func (g *Generator) section(pkg string, generate func()) {
+	l := g.layout
+	if l == nil {
+		generate()
+		return
+	}
+
+	// Bound scalars are imported by the packages which use them
+	for _, b := range g.scalars {
+		b.used = false
+	}
+
+	l.pkg = l.place(pkg)
+	start := g.Len()
+	generate()
+	l.code[l.pkg] = append(l.code[l.pkg], g.Bytes()[start:]...)
+	g.Truncate(start)
+
+	for _, b := range g.scalars {
+		if b.used && b.importPath != "" {
+			l.imports[l.pkg] = append(l.imports[l.pkg], b.importSpec())
+		}
+	}
+	l.pkg = ""
+}
+
+// goType returns the Go type of an input value, or field, qualified by its
+// package, if it's generated into another one.
+//
+func (g *Generator) goType(kinds map[string]interface{}, typ interface{}, nullable bool) string {
+	return g.layout.qualify(kinds, goType(kinds, typ, nullable))
+}
+
+// zeroValue returns the zero value of the Go type of a field.
+func (g *Generator) zeroValue(kinds map[string]interface{}, f *ast.Field) string {
+	zero := zeroValue(kinds, goType(kinds, fieldType(f), true))
+	if typ := strings.TrimSuffix(zero, "{}"); typ != zero {
+		return g.layout.qualify(kinds, typ) + "{}"
+	}
+	return zero
+}
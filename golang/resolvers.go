@@ -0,0 +1,307 @@
+package golang
+
+import (
+	gotoken "go/token"
+	"strings"
+
+	"github.com/gqlc/graphql/ast"
+)
+
+// generateResolvers generates a struct for every object type, other than
+// the root operation types, of the fields which take no arguments. Every
+// other field is resolved by a <Type>Resolver interface, as is every field
+// of a root operation type. Resolvers of non-root types are also passed the
+// object being resolved. Interfaces and unions are Go interfaces, which the
+// structs of their objects implement.
+//
+func (g *Generator) generateResolvers(doc *ast.Document, descr, defaults bool, mocks string, tags tagger) {
+	kinds := make(map[string]interface{}, len(doc.Types))
+	var objs []*ast.TypeDecl
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		kinds[ts.TypeSpec.Name.Name] = ts.TypeSpec.Type
+
+		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Object); ok {
+			objs = append(objs, d)
+		}
+	}
+	g.bindScalars(kinds)
+	roots := rootTypes(doc)
+	sub := subscriptionType(doc)
+
+	g.section(pkgModels, func() {
+		g.generateInterfaceTypes(doc, descr)
+
+		ms := markers(doc)
+		for _, d := range objs {
+			ts := d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+			if roots[ts.Name.Name] {
+				continue
+			}
+
+			g.P()
+			if d.Doc != nil && descr {
+				g.printComment(d.Doc)
+			}
+			g.P("type ", ts.Name.Name, " struct {")
+			g.In()
+			for _, f := range objectFields(ts) {
+				if len(fieldArgs(f)) > 0 {
+					continue
+				}
+
+				if f.Doc != nil && descr {
+					g.printComment(f.Doc)
+				}
+				_, nonNull := f.Type.(*ast.Field_NonNull)
+				g.P(exportName(f.Name.Name), " ", g.goType(kinds, fieldType(f), true), " ", tags.tag(f.Name.Name, !nonNull, f.Directives))
+			}
+			g.Out()
+			g.P("}")
+
+			g.generateMarkers(ts.Name.Name, ms[ts.Name.Name])
+		}
+	})
+
+	g.section(pkgResolvers, func() {
+		for _, d := range objs {
+			ts := d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+			name := ts.Name.Name
+			root := roots[name]
+
+			fields := resolverFields(ts, root)
+			if len(fields) == 0 {
+				continue
+			}
+
+			methods := make([]*resolverMethod, len(fields))
+			for i, f := range fields {
+				methods[i] = g.resolverMethod(kinds, name, !root, name == sub, f)
+			}
+
+			g.P()
+			if descr {
+				g.P("// ", name, "Resolver resolves the fields of ", name, ".")
+			}
+			g.P("type ", name, "Resolver interface {")
+			g.In()
+			for i, f := range fields {
+				if f.Doc != nil && descr {
+					g.printComment(f.Doc)
+				}
+				g.P(methods[i].signature())
+			}
+			g.Out()
+			g.P("}")
+
+			switch mocks {
+			case mockFuncs:
+				g.generateFuncMock(name, methods, descr)
+			case mockGomock:
+				g.generateGomock(name, methods, descr)
+			}
+
+			if !defaults {
+				continue
+			}
+
+			g.P()
+			if descr {
+				g.P("// Default", name, "Resolver resolves every field of ", name, " to its zero value. It")
+				g.P("// can be embedded by a ", name, "Resolver, which only resolves some of them.")
+			}
+			g.P("type Default", name, "Resolver struct{}")
+			for i, f := range fields {
+				zero := "nil"
+				if name != sub {
+					zero = g.zeroValue(kinds, f)
+				}
+
+				g.P()
+				g.P("func (Default", name, "Resolver) ", methods[i].signature(), " { return ", zero, ", nil }")
+			}
+		}
+	})
+}
+
+// resolverFields returns the fields of an object which must be resolved.
+// Every field of a root type is resolved, as are fields which take
+// arguments.
+//
+func resolverFields(ts *ast.TypeSpec, root bool) (fields []*ast.Field) {
+	for _, f := range objectFields(ts) {
+		if root || len(fieldArgs(f)) > 0 {
+			fields = append(fields, f)
+		}
+	}
+	return
+}
+
+// hasResolvers reports whether any fields of a document must be resolved,
+// and so whether resolvers need the context package.
+//
+func hasResolvers(doc *ast.Document) bool {
+	roots := rootTypes(doc)
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Object); ok && len(resolverFields(ts.TypeSpec, roots[ts.TypeSpec.Name.Name])) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// resolverMethod is the method of a resolver interface which resolves a
+// field. It returns the field's value, along with an error.
+//
+type resolverMethod struct {
+	name string
+
+	// params are the names of its parameters, and types their types
+	params, types []string
+
+	result string
+}
+
+// resolverMethod returns the method resolving a field, which is passed a
+// context, the object being resolved, if parent is set, and the field's
+// arguments. Subscriptions resolve a stream of values, as a channel, which
+// is closed once the subscription ends.
+//
+func (g *Generator) resolverMethod(kinds map[string]interface{}, name string, parent, stream bool, f *ast.Field) *resolverMethod {
+	m := &resolverMethod{
+		name:   exportName(f.Name.Name),
+		params: []string{"ctx"},
+		types:  []string{"context.Context"},
+		result: g.goType(kinds, fieldType(f), true),
+	}
+	if parent {
+		m.params = append(m.params, "obj")
+		m.types = append(m.types, g.layout.qualify(kinds, "*"+name))
+	}
+	for _, a := range fieldArgs(f) {
+		m.params = append(m.params, paramName(a.Name.Name))
+		m.types = append(m.types, g.goType(kinds, inputValueType(a), true))
+	}
+	if stream {
+		m.result = "<-chan " + m.result
+	}
+	return m
+}
+
+// signature returns the method's signature, without the func keyword.
+func (m *resolverMethod) signature() string {
+	return m.name + m.funcType()[len("func"):]
+}
+
+// funcType returns the type of a func with the method's signature.
+func (m *resolverMethod) funcType() string {
+	params := make([]string, len(m.params))
+	for i, p := range m.params {
+		params[i] = p + " " + m.types[i]
+	}
+	return "func(" + strings.Join(params, ", ") + ") (" + m.result + ", error)"
+}
+
+// paramName returns a parameter name for an argument, which doesn't clash
+// with Go keywords, or the context and object parameters of resolvers.
+//
+func paramName(name string) string {
+	if gotoken.IsKeyword(name) || name == "ctx" || name == "obj" {
+		return name + "_"
+	}
+	return name
+}
+
+// zeroValue returns the zero value of a Go type.
+func zeroValue(kinds map[string]interface{}, typ string) string {
+	switch kinds[typ].(type) {
+	case *ast.TypeSpec_Enum, *ast.TypeSpec_Scalar:
+		return `""`
+	case *ast.TypeSpec_Input:
+		return typ + "{}"
+	case *ast.TypeSpec_Interface, *ast.TypeSpec_Union:
+		return "nil"
+	}
+
+	switch {
+	case typ == "int" || typ == "float64":
+		return "0"
+	case typ == "string":
+		return `""`
+	case typ == "bool":
+		return "false"
+	case typ == "interface{}" || strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "[]"):
+		return "nil"
+	}
+
+	// Scalars bound to Go types may be of any kind
+	return "*new(" + typ + ")"
+}
+
+// rootTypes returns the names of the root operation types.
+func rootTypes(doc *ast.Document) map[string]bool {
+	roots := make(map[string]bool, 3)
+	if doc.Schema == nil {
+		roots["Query"] = true
+		roots["Mutation"] = true
+		roots["Subscription"] = true
+		return roots
+	}
+
+	schema := doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Schema).Schema
+	for _, f := range schema.RootOps.List {
+		roots[f.Type.(*ast.Field_Ident).Ident.Name] = true
+	}
+	return roots
+}
+
+// subscriptionType returns the name of the subscription root operation
+// type, if there is one.
+//
+func subscriptionType(doc *ast.Document) string {
+	if doc.Schema == nil {
+		return "Subscription"
+	}
+
+	schema := doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Schema).Schema
+	for _, f := range schema.RootOps.List {
+		if f.Name.Name == "subscription" {
+			return f.Type.(*ast.Field_Ident).Ident.Name
+		}
+	}
+	return ""
+}
+
+func objectFields(ts *ast.TypeSpec) []*ast.Field {
+	fields := ts.Type.(*ast.TypeSpec_Object).Object.Fields
+	if fields == nil {
+		return nil
+	}
+	return fields.List
+}
+
+func fieldArgs(f *ast.Field) []*ast.InputValue {
+	if f.Args == nil {
+		return nil
+	}
+	return f.Args.List
+}
+
+func fieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package golang
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"strings"
+
+	"github.com/gqlc/graphql/ast"
+)
+
+// scalarBinding binds a scalar to an existing Go type, which structs and
+// resolvers use instead of a generated type.
+//
+type scalarBinding struct {
+	// importPath is the package of the type, or empty if it's predeclared
+	importPath string
+
+	// typ is the qualified type e.g. time.Time
+	typ string
+
+	// used is set once the type is referred to, so its package is only
+	// imported when it's needed.
+	//
+	used bool
+}
+
+// importSpec returns the import of the package of the type, which is named
+// explicitly when its name doesn't match its path.
+//
+func (b *scalarBinding) importSpec() string {
+	name := b.typ[:strings.IndexByte(b.typ, '.')]
+	if name == path.Base(b.importPath) {
+		return b.importPath
+	}
+	return name + " " + b.importPath
+}
+
+// parseScalars parses the bindings of scalars to Go types, which are
+// written Scalar=importpath.Type e.g. DateTime=time.Time, or
+// UUID=github.com/google/uuid.UUID. Predeclared types, e.g. Long=int64,
+// aren't imported.
+//
+func parseScalars(bindings []string) (map[string]*scalarBinding, error) {
+	scalars := make(map[string]*scalarBinding, len(bindings))
+	for _, s := range bindings {
+		kv := strings.SplitN(s, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid scalar binding: %q, expected Scalar=importpath.Type", s)
+		}
+		name, typ := kv[0], kv[1]
+
+		b := &scalarBinding{typ: typ}
+		valid := true
+		if i := strings.LastIndexByte(typ, '.'); i > strings.LastIndexByte(typ, '/') {
+			b.importPath, typ = typ[:i], typ[i+1:]
+			b.typ = packageName(b.importPath) + "." + typ
+			valid = b.importPath != "" && !strings.HasSuffix(b.importPath, "/")
+		}
+		if !valid || !token.IsIdentifier(typ) {
+			return nil, fmt.Errorf("invalid scalar binding: %q, expected Scalar=importpath.Type", s)
+		}
+		scalars[name] = b
+	}
+	return scalars, nil
+}
+
+// bindScalars overrides the kinds of the scalars bound to Go types.
+func (g *Generator) bindScalars(kinds map[string]interface{}) {
+	for name, b := range g.scalars {
+		kinds[name] = b
+	}
+}
+
+// checkScalars checks that only scalars are bound to Go types.
+func (g *Generator) checkScalars(doc *ast.Document) error {
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil || g.scalars[ts.TypeSpec.Name.Name] == nil {
+			continue
+		}
+		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Scalar); !ok {
+			return fmt.Errorf("%s is bound to %s, but it isn't a scalar", ts.TypeSpec.Name.Name, g.scalars[ts.TypeSpec.Name.Name].typ)
+		}
+	}
+	return nil
+}
+
+// customScalars returns the scalars a document declares which aren't bound
+// to Go types, and so have types generated for them.
+//
+func (g *Generator) customScalars(doc *ast.Document) (scalars []*ast.TypeDecl) {
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil || g.scalars[ts.TypeSpec.Name.Name] != nil {
+			continue
+		}
+		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Scalar); ok {
+			scalars = append(scalars, d)
+		}
+	}
+	return
+}
+
+// generateScalarTypes generates a named string type for every custom scalar
+// which isn't bound to a Go type. They implement the MarshalGQL and
+// UnmarshalGQL methods of gqlgen's Marshaler and Unmarshaler interfaces,
+// reading and writing the scalar as a string, and marshal to JSON as one.
+//
+func (g *Generator) generateScalarTypes(doc *ast.Document, descr bool) {
+	for _, d := range g.customScalars(doc) {
+		name := d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Name.Name
+
+		g.P()
+		if d.Doc != nil && descr {
+			g.printComment(d.Doc)
+		}
+		g.P("type ", name, " string")
+
+		g.P()
+		g.P("// MarshalGQL writes the ", name, " as a GraphQL string.")
+		g.P("func (s ", name, ") MarshalGQL(w io.Writer) { io.WriteString(w, strconv.Quote(string(s))) }")
+
+		g.P()
+		g.P("// UnmarshalGQL reads the ", name, " from a GraphQL string.")
+		g.P("func (s *", name, ") UnmarshalGQL(v interface{}) error {")
+		g.In()
+		g.P("str, ok := v.(string)")
+		g.P("if !ok {")
+		g.In()
+		g.P("return fmt.Errorf(\"%T is not a valid ", name, ", expected a string\", v)")
+		g.Out()
+		g.P("}")
+		g.P("*s = ", name, "(str)")
+		g.P("return nil")
+		g.Out()
+		g.P("}")
+	}
+}
@@ -0,0 +1,205 @@
+package golang
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gqlc/graphql/ast"
+)
+
+// The naming strategies of json tags. Field names are kept as they're
+// declared, unless one is given.
+//
+const (
+	namingCamel = "camel"
+	namingSnake = "snake"
+)
+
+// tagDirective is the directive giving a field extra struct tags.
+const tagDirective = "goTag"
+
+// tagger writes the struct tags of the fields of generated structs.
+type tagger struct {
+	naming    string
+	omitEmpty bool
+	keys      []string
+}
+
+// tag returns the struct tag of a field, or input field. Its json tag, and
+// those of the extra keys, are named after the field, while its goTag
+// directive adds, or overrides, tags.
+//
+func (t tagger) tag(name string, nullable bool, dirs []*ast.DirectiveLit) string {
+	name = t.jsonName(name)
+
+	keys := []string{"json"}
+	vals := map[string]string{"json": name}
+	if nullable && t.omitEmpty {
+		vals["json"] += ",omitempty"
+	}
+	for _, k := range t.keys {
+		if _, ok := vals[k]; !ok {
+			keys = append(keys, k)
+		}
+		vals[k] = name
+	}
+
+	tags, _ := directiveTags(dirs)
+	for _, kv := range tags {
+		if _, ok := vals[kv[0]]; !ok {
+			keys = append(keys, kv[0])
+		}
+		vals[kv[0]] = kv[1]
+	}
+
+	var b strings.Builder
+	b.WriteByte('`')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%s:%q", k, vals[k])
+	}
+	b.WriteByte('`')
+	return b.String()
+}
+
+// jsonName returns the name of a field in JSON.
+func (t tagger) jsonName(name string) string {
+	switch t.naming {
+	case namingCamel:
+		return camelCase(name)
+	case namingSnake:
+		return snakeCase(name)
+	}
+	return name
+}
+
+// directiveTags returns the key value pairs given by a goTag directive,
+// which are written key:value e.g. @goTag(tags: ["validate:required"]).
+//
+func directiveTags(dirs []*ast.DirectiveLit) (tags [][2]string, err error) {
+	for _, d := range dirs {
+		if d.Name != tagDirective || d.Args == nil {
+			continue
+		}
+
+		var vals []string
+		for _, arg := range d.Args.Args {
+			switch v := arg.Value.(type) {
+			case *ast.Arg_BasicLit:
+				vals = append(vals, strings.Trim(v.BasicLit.Value, `"`))
+			case *ast.Arg_CompositeLit:
+				vals = append(vals, stringList(v.CompositeLit)...)
+			}
+		}
+
+		for _, val := range vals {
+			i := strings.IndexByte(val, ':')
+			if i < 0 || !validTagKey(val[:i]) {
+				return nil, fmt.Errorf("malformed struct tag: %q, expected key:value", val)
+			}
+			tags = append(tags, [2]string{val[:i], val[i+1:]})
+		}
+	}
+	return
+}
+
+// checkTags checks the goTag directives of a document's fields are well
+// formed, before any struct is generated.
+//
+func checkTags(doc *ast.Document) error {
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		var err error
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			for _, f := range objectFields(ts.TypeSpec) {
+				if _, err = directiveTags(f.Directives); err != nil {
+					break
+				}
+			}
+		case *ast.TypeSpec_Input:
+			if v.Input.Fields == nil {
+				continue
+			}
+			for _, f := range v.Input.Fields.List {
+				if _, err = directiveTags(f.Directives); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %s", ts.TypeSpec.Name.Name, err)
+		}
+	}
+	return nil
+}
+
+// validTagKey reports whether a struct tag key is well formed, i.e. it's
+// non-empty and has no spaces, quotes or colons.
+//
+func validTagKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '"' || r == ':' || r == '`' || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// camelCase converts a name to camelCase e.g. first_name is firstName, and
+// HTTPStatus is httpStatus.
+//
+func camelCase(name string) string {
+	var rs []rune
+	upper := false
+	for _, r := range name {
+		switch {
+		case r == '_':
+			upper = len(rs) > 0
+		case upper:
+			rs = append(rs, unicode.ToUpper(r))
+			upper = false
+		default:
+			rs = append(rs, r)
+		}
+	}
+
+	// Lower the leading word, or acronym
+	for i := 0; i < len(rs) && unicode.IsUpper(rs[i]); i++ {
+		if i > 0 && i+1 < len(rs) && unicode.IsLower(rs[i+1]) {
+			break
+		}
+		rs[i] = unicode.ToLower(rs[i])
+	}
+	return string(rs)
+}
+
+// snakeCase converts a name to snake_case e.g. firstName is first_name,
+// and userID is user_id.
+//
+func snakeCase(name string) string {
+	rs := []rune(name)
+
+	var b strings.Builder
+	for i, r := range rs {
+		if unicode.IsUpper(r) && i > 0 && rs[i-1] != '_' {
+			prevLower := unicode.IsLower(rs[i-1]) || unicode.IsDigit(rs[i-1])
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if prevLower || nextLower && unicode.IsUpper(rs[i-1]) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -35,20 +35,128 @@ var goTypes = []*ast.TypeDecl{
 					List: []*ast.InputValue{
 						{
 							Name: &ast.Ident{Name: "package"},
-							Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{
-								Type: &ast.NonNull_Ident{
-									Ident: &ast.Ident{
-										Name: "String",
-									},
-								},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+						},
+						{
+							Name: &ast.Ident{Name: "importPath"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+						},
+						{
+							Name: &ast.Ident{Name: "descriptions"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
 							}},
+						},
+						{
+							Name: &ast.Ident{Name: "commentWidth"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Int"},
+							},
 							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
-								Kind:  token.Token_STRING,
-								Value: `"main"`,
+								Kind:  token.Token_INT,
+								Value: "0",
 							}},
 						},
 						{
-							Name: &ast.Ident{Name: "descriptions"},
+							Name: &ast.Ident{Name: "generate"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "federation"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "optionals"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "resolvers"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "defaultResolvers"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "mocks"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+						},
+						{
+							Name: &ast.Ident{Name: "jsonNaming"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+						},
+						{
+							Name: &ast.Ident{Name: "omitEmpty"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "tags"},
+							Type: &ast.InputValue_List{List: &ast.List{
+								Type: &ast.List_Ident{Ident: &ast.Ident{Name: "String"}},
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "scalars"},
+							Type: &ast.InputValue_List{List: &ast.List{
+								Type: &ast.List_Ident{Ident: &ast.Ident{Name: "String"}},
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "split"},
+							Type: &ast.InputValue_List{List: &ast.List{
+								Type: &ast.List_Ident{Ident: &ast.Ident{Name: "String"}},
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "fixImports"},
 							Type: &ast.InputValue_Ident{
 								Ident: &ast.Ident{Name: "Boolean"},
 							},
@@ -62,6 +170,32 @@ var goTypes = []*ast.TypeDecl{
 			}},
 		}},
 	},
+	{
+		Tok: token.Token_DIRECTIVE,
+		Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{
+			Name: &ast.Ident{Name: "goTag"},
+			Type: &ast.TypeSpec_Directive{Directive: &ast.DirectiveType{
+				Locs: []*ast.DirectiveLocation{
+					{Loc: ast.DirectiveLocation_FIELD_DEFINITION},
+					{Loc: ast.DirectiveLocation_INPUT_FIELD_DEFINITION},
+					// The compiler checks input fields as argument definitions
+					{Loc: ast.DirectiveLocation_ARGUMENT_DEFINITION},
+				},
+				Args: &ast.InputValueList{
+					List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "tags"},
+							Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{
+								Type: &ast.NonNull_List{List: &ast.List{
+									Type: &ast.List_Ident{Ident: &ast.Ident{Name: "String"}},
+								}},
+							}},
+						},
+					},
+				},
+			}},
+		}},
+	},
 }
 
 func init() {
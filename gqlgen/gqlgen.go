@@ -0,0 +1,658 @@
+// Package gqlgen contains a generator of Go models for GraphQL Documents, in
+// the style of gqlgen's models_gen.go, along with a gqlgen.yml which binds
+// gqlgen to them.
+//
+package gqlgen
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/gen/printer"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+const (
+	modelsFile = "models_gen.go"
+	configFile = "gqlgen.yml"
+
+	// gqlgenModelsFile is where gqlgen writes any models it can't
+	// autobind, so it doesn't overwrite the models generated by gqlc.
+	//
+	gqlgenModelsFile = "models_gqlgen.go"
+
+	// scalarModel is the gqlgen type custom scalars are bound to.
+	scalarModel = "github.com/99designs/gqlgen/graphql.String"
+)
+
+// Options contains the options for the gqlgen generator.
+type Options struct {
+	// Package is the name of the package the models belong to.
+	Package string
+
+	// ImportPath is the import path of the output directory, if it's in a
+	// Go module. Models are only autobound when it's known.
+	//
+	ImportPath string
+
+	// Write a gqlgen.yml, which binds gqlgen to the models.
+	Config bool
+}
+
+// Generator generates gqlgen models for a GraphQL schema.
+type Generator struct {
+	printer.Printer
+
+	log *zap.Logger
+}
+
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Printer.Reset()
+}
+
+// Generate generates models_gen.go, and gqlgen.yml, for the given document.
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "gqlgen",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("gqlgen").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	gOpts, oerr := getOptions(doc, opts)
+	if oerr != nil {
+		return oerr
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	pCtx, isPath := gCtx.(gen.PathContext)
+	if isPath {
+		gOpts.ImportPath, err = gen.ImportPath(pCtx, pCtx.Dir())
+		if err != nil {
+			return
+		}
+	}
+
+	// Generate models
+	g.log.Info("generating models")
+	m := newModels(doc)
+	g.generateModels(gOpts.Package, m)
+
+	modelFile, err := gCtx.Open(modelsFile)
+	if err != nil {
+		return
+	}
+	defer modelFile.Close()
+
+	_, err = g.WriteTo(modelFile)
+	if err != nil || !gOpts.Config {
+		return
+	}
+
+	// Generate config
+	g.log.Info("generating config")
+	schema := doc.Name + ".gql"
+	if isPath {
+		if src := pCtx.Source(doc); src != "" {
+			schema = src
+			if rel, rerr := filepath.Rel(pCtx.Dir(), src); rerr == nil {
+				schema = rel
+			}
+			schema = filepath.ToSlash(schema)
+		}
+	}
+	if gOpts.ImportPath == "" {
+		gen.Warnf(gCtx, doc, token.NoPos, "output directory isn't in a Go module, so models won't be autobound")
+	}
+
+	g.Reset()
+	g.writeConfig(gOpts, schema, m.scalars)
+
+	cfgFile, err := gCtx.Open(configFile)
+	if err != nil {
+		return
+	}
+	defer cfgFile.Close()
+
+	_, err = g.WriteTo(cfgFile)
+	return
+}
+
+// models are the types of a document which are generated as Go types.
+type models struct {
+	decls []*ast.TypeDecl
+
+	// kinds maps every named type to its kind
+	kinds map[string]interface{}
+
+	// unions maps object names to the unions they're a member of
+	unions map[string][]string
+
+	// scalars are the custom scalars, which gqlgen must be told about
+	scalars []string
+}
+
+// newModels collects the types to generate. Root operation types are
+// resolved by gqlgen, so they don't have models.
+//
+func newModels(doc *ast.Document) *models {
+	m := &models{
+		kinds:  make(map[string]interface{}, len(doc.Types)),
+		unions: make(map[string][]string),
+	}
+
+	roots := map[string]bool{"Query": true, "Mutation": true, "Subscription": true}
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+
+		if s, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Schema); ok {
+			roots = make(map[string]bool)
+			if s.Schema.RootOps != nil {
+				for _, op := range s.Schema.RootOps.List {
+					roots[op.Type.(*ast.Field_Ident).Ident.Name] = true
+				}
+			}
+		}
+	}
+
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+		m.kinds[name] = ts.TypeSpec.Type
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar:
+			m.scalars = append(m.scalars, name)
+			continue
+		case *ast.TypeSpec_Directive:
+			continue
+		case *ast.TypeSpec_Object:
+			if roots[name] {
+				continue
+			}
+		case *ast.TypeSpec_Union:
+			for _, mem := range v.Union.Members {
+				m.unions[mem.Name] = append(m.unions[mem.Name], name)
+			}
+		}
+
+		m.decls = append(m.decls, d)
+	}
+	return m
+}
+
+var header = []byte("// Code generated by gqlc, DO NOT EDIT.\n\npackage ")
+
+func (g *Generator) generateModels(pkg string, m *models) {
+	g.Write(header)
+	g.WriteString(pkg)
+	g.WriteByte('\n')
+
+	var hasEnum bool
+	for _, d := range m.decls {
+		ts := d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+		if _, ok := ts.Type.(*ast.TypeSpec_Enum); ok {
+			hasEnum = true
+			break
+		}
+	}
+	if hasEnum {
+		g.WriteString("\nimport (\n\t\"fmt\"\n\t\"io\"\n\t\"strconv\"\n)\n")
+	}
+
+	for _, d := range m.decls {
+		ts := d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+		name := goName(ts.Name.Name)
+
+		g.WriteByte('\n')
+		if d.Doc != nil {
+			g.printComment(d.Doc)
+		}
+
+		switch v := ts.Type.(type) {
+		case *ast.TypeSpec_Object:
+			var fields []*ast.Field
+			if v.Object.Fields != nil {
+				fields = v.Object.Fields.List
+			}
+
+			sf := make([]structField, len(fields))
+			for i, f := range fields {
+				sf[i] = structField{name: f.Name.Name, typ: m.goType(fieldType(f), true), doc: f.Doc}
+			}
+			g.generateStruct(name, sf)
+
+			for _, inter := range v.Object.Interfaces {
+				g.WriteByte('\n')
+				g.P("func (", name, ") Is", goName(inter.Name), "() {}")
+			}
+			for _, union := range m.unions[ts.Name.Name] {
+				g.WriteByte('\n')
+				g.P("func (", name, ") Is", goName(union), "() {}")
+			}
+		case *ast.TypeSpec_Input:
+			var fields []*ast.InputValue
+			if v.Input.Fields != nil {
+				fields = v.Input.Fields.List
+			}
+
+			sf := make([]structField, len(fields))
+			for i, f := range fields {
+				sf[i] = structField{name: f.Name.Name, typ: m.goType(inputValueType(f), true), doc: f.Doc}
+			}
+			g.generateStruct(name, sf)
+		case *ast.TypeSpec_Interface, *ast.TypeSpec_Union:
+			g.P("type ", name, " interface {")
+			g.In()
+			g.P("Is", name, "()")
+			g.Out()
+			g.P("}")
+		case *ast.TypeSpec_Enum:
+			g.generateEnum(name, v.Enum)
+		}
+	}
+}
+
+// structField is a field of a generated struct.
+type structField struct {
+	name, typ string
+	doc       *ast.DocGroup
+}
+
+// generateStruct generates a struct whose fields are aligned, as gofmt
+// would, and tagged with their GraphQL names.
+//
+func (g *Generator) generateStruct(name string, fields []structField) {
+	g.P("type ", name, " struct {")
+	g.In()
+	for _, run := range runs(len(fields), func(i int) bool { return fields[i].doc != nil }) {
+		var nameLen, typLen int
+		for _, f := range fields[run[0]:run[1]] {
+			if l := len(goName(f.name)); l > nameLen {
+				nameLen = l
+			}
+			if l := len(f.typ); l > typLen {
+				typLen = l
+			}
+		}
+
+		for _, f := range fields[run[0]:run[1]] {
+			if f.doc != nil {
+				g.printComment(f.doc)
+			}
+
+			fname := goName(f.name)
+			g.P(
+				fname, strings.Repeat(" ", nameLen-len(fname)+1),
+				f.typ, strings.Repeat(" ", typLen-len(f.typ)+1),
+				"`json:\"", f.name, "\"`",
+			)
+		}
+	}
+	g.Out()
+	g.P("}")
+}
+
+// runs splits n lines into the runs gofmt aligns together. A comment
+// ends a run, so every commented line starts a new one.
+//
+func runs(n int, commented func(int) bool) (rs [][2]int) {
+	start := 0
+	for i := 1; i <= n; i++ {
+		if i == n || commented(i) {
+			rs = append(rs, [2]int{start, i})
+			start = i
+		}
+	}
+	return
+}
+
+var enumMethods = `
+func (e %[1]s) IsValid() bool {
+	switch e {
+	case %[2]s:
+		return true
+	}
+	return false
+}
+
+func (e %[1]s) String() string {
+	return string(e)
+}
+
+func (e *%[1]s) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = %[1]s(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%%s is not a valid %[1]s", str)
+	}
+	return nil
+}
+
+func (e %[1]s) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+`
+
+// generateEnum generates a string type for an enum, with a constant for
+// each value and the methods gqlgen uses to marshal it.
+//
+func (g *Generator) generateEnum(name string, enum *ast.EnumType) {
+	var vals []*ast.Field
+	if enum.Values != nil {
+		vals = enum.Values.List
+	}
+
+	consts := make([]string, len(vals))
+	for i, v := range vals {
+		consts[i] = name + goName(v.Name.Name)
+	}
+
+	g.P("type ", name, " string")
+	g.WriteByte('\n')
+
+	g.P("const (")
+	g.In()
+	for _, run := range runs(len(vals), func(i int) bool { return vals[i].Doc != nil }) {
+		var constLen int
+		for _, c := range consts[run[0]:run[1]] {
+			if len(c) > constLen {
+				constLen = len(c)
+			}
+		}
+
+		for i := run[0]; i < run[1]; i++ {
+			if vals[i].Doc != nil {
+				g.printComment(vals[i].Doc)
+			}
+			g.P(consts[i], strings.Repeat(" ", constLen-len(consts[i])+1), name, " = ", strconv.Quote(vals[i].Name.Name))
+		}
+	}
+	g.Out()
+	g.P(")")
+	g.WriteByte('\n')
+
+	g.P("var All", name, " = []", name, "{")
+	g.In()
+	for _, c := range consts {
+		g.P(c, ",")
+	}
+	g.Out()
+	g.P("}")
+
+	fmt.Fprintf(g, enumMethods, name, strings.Join(consts, ", "))
+}
+
+// writeConfig writes a gqlgen.yml which autobinds the models, and binds
+// custom scalars to strings. Paths are relative to the output directory,
+// which is where gqlgen.yml is written.
+//
+func (g *Generator) writeConfig(opts *Options, schema string, scalars []string) {
+	g.P("# Code generated by gqlc, DO NOT EDIT.")
+	g.WriteByte('\n')
+
+	g.P("schema:")
+	g.P("  - ", schema)
+	g.WriteByte('\n')
+
+	g.P("exec:")
+	g.P("  filename: generated/generated.go")
+	g.P("  package: generated")
+	g.WriteByte('\n')
+
+	g.P("model:")
+	g.P("  filename: ", gqlgenModelsFile)
+	g.P("  package: ", opts.Package)
+
+	if opts.ImportPath != "" {
+		g.WriteByte('\n')
+		g.P("autobind:")
+		g.P("  - ", opts.ImportPath)
+	}
+
+	if len(scalars) == 0 {
+		return
+	}
+
+	g.WriteByte('\n')
+	g.P("models:")
+	for _, s := range scalars {
+		g.P("  ", s, ":")
+		g.P("    model:")
+		g.P("      - ", scalarModel)
+	}
+}
+
+// builtinTypes maps the builtin scalars to their Go types.
+var builtinTypes = map[string]string{
+	"Int":     "int",
+	"Float":   "float64",
+	"String":  "string",
+	"Boolean": "bool",
+	"ID":      "string",
+}
+
+// goType returns the Go type of a GraphQL type, as gqlgen would model it.
+// Structs are always pointers, interfaces never are and everything else
+// is a pointer when it's nullable.
+//
+func (m *models) goType(typ interface{}, nullable bool) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		name := goName(v.Name)
+		switch m.kinds[v.Name].(type) {
+		case *ast.TypeSpec_Object, *ast.TypeSpec_Input:
+			return "*" + name
+		case *ast.TypeSpec_Interface, *ast.TypeSpec_Union:
+			return name
+		case *ast.TypeSpec_Enum:
+		default:
+			// Custom scalars are bound to strings
+			name = builtinTypes[v.Name]
+			if name == "" {
+				name = "string"
+			}
+		}
+
+		if nullable {
+			return "*" + name
+		}
+		return name
+	case *ast.List:
+		var elem interface{}
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			elem = w.Ident
+		case *ast.List_List:
+			elem = w.List
+		case *ast.List_NonNull:
+			elem = w.NonNull
+		}
+		return "[]" + m.goType(elem, true)
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return m.goType(w.Ident, false)
+		case *ast.NonNull_List:
+			return m.goType(w.List, false)
+		}
+	}
+	return "interface{}"
+}
+
+func fieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func inputValueType(a *ast.InputValue) interface{} {
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+// initialisms are kept upper case in Go names, like golint suggests.
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true,
+	"DNS": true, "EOF": true, "GUID": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "QPS": true,
+	"RAM": true, "RPC": true, "SLA": true, "SMTP": true, "SQL": true,
+	"SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true,
+	"UI": true, "UID": true, "UUID": true, "URI": true, "URL": true,
+	"UTF8": true, "VM": true, "XML": true, "XMPP": true, "XSRF": true,
+	"XSS": true,
+}
+
+// goName converts a GraphQL name into an exported Go name, the way gqlgen
+// does e.g. user_id and userId become UserID, and NORTH becomes North.
+//
+func goName(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		if up := strings.ToUpper(w); initialisms[up] {
+			b.WriteString(up)
+			continue
+		}
+
+		b.WriteString(strings.ToUpper(w[:1]))
+		b.WriteString(strings.ToLower(w[1:]))
+	}
+	return b.String()
+}
+
+// words splits a name at underscores and changes of case e.g. userID is
+// split into user and ID, and HTTPServer into HTTP and Server.
+//
+func words(name string) (ws []string) {
+	runes := []rune(name)
+
+	start := 0
+	for i, r := range runes {
+		if r == '_' {
+			if i > start {
+				ws = append(ws, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if i == start || !unicode.IsUpper(r) {
+			continue
+		}
+
+		prev := runes[i-1]
+		lowerNext := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && lowerNext {
+			ws = append(ws, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	if start < len(runes) {
+		ws = append(ws, string(runes[start:]))
+	}
+	return
+}
+
+func (g *Generator) printComment(doc *ast.DocGroup) {
+	for _, line := range strings.Split(strings.TrimSpace(doc.Text()), "\n") {
+		g.P("// ", line)
+	}
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Package: "model",
+		Config:  true,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "gqlgen" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		docOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range docOpts.Fields {
+			switch arg.Key.Name {
+			case "package":
+				gOpts.Package = arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+			case "config":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Config = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts != nil {
+		if p, ok := opts["package"]; ok {
+			gOpts.Package, _ = p.(string)
+		}
+		if c, ok := opts["config"]; ok {
+			gOpts.Config, _ = c.(bool)
+		}
+	}
+
+	// Trim '"' from beginning and end of package name
+	gOpts.Package = strings.Trim(gOpts.Package, "\"")
+	if gOpts.Package == "" {
+		gOpts.Package = "model"
+	}
+	return
+}
@@ -0,0 +1,376 @@
+package gqlgen
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "test.gotxt", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected gqlgen output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected gqlgen output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, map[string]interface{}{"config": false})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+// filesCtx keeps each file written by a generator, and implements
+// gen.PathContext with an output directory at /mod/graph.
+//
+type filesCtx struct {
+	gen.TestPathCtx
+
+	files map[string]*bytes.Buffer
+}
+
+func newFilesCtx(files map[string][]byte) *filesCtx {
+	return &filesCtx{
+		TestPathCtx: gen.TestPathCtx{
+			DirPath: "/mod/graph",
+			Sources: map[string]string{"test": "/mod/schema/test.gql"},
+			Files:   files,
+		},
+		files: make(map[string]*bytes.Buffer),
+	}
+}
+
+func (ctx *filesCtx) Open(name string) (io.WriteCloser, error) {
+	b := new(bytes.Buffer)
+	ctx.files[name] = b
+	return gen.TestCtx{Writer: b}, nil
+}
+
+func TestGoName(t *testing.T) {
+	testCases := map[string]string{
+		"id":          "ID",
+		"userId":      "UserID",
+		"user_id":     "UserID",
+		"homePageURL": "HomePageURL",
+		"HTTPServer":  "HTTPServer",
+		"NORTH":       "North",
+		"READ_ONLY":   "ReadOnly",
+		"v2Api":       "V2API",
+		"createdAt":   "CreatedAt",
+	}
+
+	for name, ex := range testCases {
+		if out := goName(name); out != ex {
+			t.Errorf("expected %s to be %s, but got: %s", name, ex, out)
+		}
+	}
+}
+
+func TestGoType(t *testing.T) {
+	m := newModels(testDoc)
+
+	testCases := []struct {
+		Field string
+		Ex    string
+	}{
+		{Field: "id", Ex: "string"},
+		{Field: "name", Ex: "*string"},
+		{Field: "role", Ex: "Role"},
+		{Field: "friends", Ex: "[]*User"},
+		{Field: "createdAt", Ex: "string"},
+		{Field: "lastSeen", Ex: "*string"},
+	}
+
+	var user *ast.ObjectType
+	for _, d := range testDoc.Types {
+		ts := d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+		if ts.Name != nil && ts.Name.Name == "User" {
+			user = ts.Type.(*ast.TypeSpec_Object).Object
+		}
+	}
+
+	for _, testCase := range testCases {
+		for _, f := range user.Fields.List {
+			if f.Name.Name != testCase.Field {
+				continue
+			}
+
+			if typ := m.goType(fieldType(f), true); typ != testCase.Ex {
+				t.Errorf("expected %s to be %s, but got: %s", testCase.Field, testCase.Ex, typ)
+			}
+		}
+	}
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "types", strings.NewReader(`interface Node {
+	id: ID!
+}
+
+type Query {
+	a: [[Int]!]
+	b: Node!
+	c: [Node]
+}`), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	m = newModels(doc)
+	if len(m.decls) != 1 {
+		t.Errorf("expected the Query type to not be modelled, but got %d models", len(m.decls))
+	}
+
+	query := doc.Types[1].Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Object).Object
+	for i, ex := range []string{"[][]*int", "Node", "[]Node"} {
+		if typ := m.goType(fieldType(query.Fields.List[i]), true); typ != ex {
+			t.Errorf("expected %s, but got: %s", ex, typ)
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	t.Run("Module", func(subT *testing.T) {
+		ctx := newFilesCtx(map[string][]byte{"/mod/go.mod": []byte("module example.com/api\n")})
+
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), testDoc, nil)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ex := `# Code generated by gqlc, DO NOT EDIT.
+
+schema:
+  - ../schema/test.gql
+
+exec:
+  filename: generated/generated.go
+  package: generated
+
+model:
+  filename: models_gqlgen.go
+  package: graph
+
+autobind:
+  - example.com/api/graph
+
+models:
+  Time:
+    model:
+      - github.com/99designs/gqlgen/graphql.String
+`
+		cfg, ok := ctx.files[configFile]
+		if !ok {
+			subT.Fatalf("expected %s to be written", configFile)
+		}
+		gen.CompareBytes(subT, []byte(ex), cfg.Bytes())
+
+		if _, ok = ctx.files[modelsFile]; !ok {
+			subT.Errorf("expected %s to be written", modelsFile)
+		}
+	})
+
+	t.Run("NoModule", func(subT *testing.T) {
+		ctx := newFilesCtx(nil)
+
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), testDoc, nil)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if cfg := ctx.files[configFile].String(); strings.Contains(cfg, "autobind") {
+			subT.Errorf("expected models to not be autobound, but got:\n%s", cfg)
+		}
+	})
+
+	t.Run("Disabled", func(subT *testing.T) {
+		ctx := newFilesCtx(nil)
+
+		err := new(Generator).Generate(gen.WithContext(context.Background(), ctx), testDoc, map[string]interface{}{"config": false})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if _, ok := ctx.files[configFile]; ok {
+			subT.Errorf("expected %s to not be written", configFile)
+		}
+	})
+}
+
+func TestOptions(t *testing.T) {
+	gOpts, err := getOptions(testDoc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if gOpts.Package != "graph" || !gOpts.Config {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+
+	gOpts, err = getOptions(testDoc, map[string]interface{}{"package": "models", "config": false})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if gOpts.Package != "models" || gOpts.Config {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+
+	gOpts, err = getOptions(&ast.Document{}, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if gOpts.Package != "model" {
+		t.Errorf("expected default package model, but got: %s", gOpts.Package)
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := &Generator{}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"config": false})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := &Generator{}
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `type Query {
+	me: User
+}
+
+type User {
+	id: ID!
+	name: String
+	friends: [User!]!
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, map[string]interface{}{"config": false})
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Print(b.String())
+	// Output:
+	// // Code generated by gqlc, DO NOT EDIT.
+	//
+	// package model
+	//
+	// type User struct {
+	// 	ID      string  `json:"id"`
+	// 	Name    *string `json:"name"`
+	// 	Friends []*User `json:"friends"`
+	// }
+}
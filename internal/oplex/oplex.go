@@ -0,0 +1,125 @@
+// Package oplex splits executable GraphQL documents, i.e. documents
+// containing operations and fragments, into tokens.
+package oplex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind classifies the tokens of an executable document.
+type Kind int
+
+const (
+	EOF Kind = iota
+	Name
+	Punct
+	Value
+)
+
+// Token is a lexical token of an executable document. Off is the offset
+// of its value in the document.
+//
+type Token struct {
+	Kind Kind
+	Val  string
+	Off  int
+}
+
+// Error is an error at an offset in an executable document.
+type Error struct {
+	Off int
+	Msg string
+}
+
+func (e Error) Error() string { return e.Msg }
+
+// Lex splits an executable document into tokens. Insignificant characters
+// e.g. whitespace, commas and comments are dropped. The last token is
+// always an EOF.
+//
+func Lex(src string) (toks []Token, err error) {
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' && src[i] != '\r' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "\uFEFF"):
+			i += len("\uFEFF")
+		case strings.HasPrefix(src[i:], "..."):
+			toks = append(toks, Token{Kind: Punct, Val: "...", Off: i})
+			i += 3
+		case strings.IndexByte("!$&():=@[]{}|", c) >= 0:
+			toks = append(toks, Token{Kind: Punct, Val: src[i : i+1], Off: i})
+			i++
+		case c == '_' || IsLetter(c):
+			start := i
+			for i < len(src) && (src[i] == '_' || IsLetter(src[i]) || IsDigit(src[i])) {
+				i++
+			}
+			toks = append(toks, Token{Kind: Name, Val: src[start:i], Off: start})
+		case c == '-' || IsDigit(c):
+			start := i
+			i++
+			for i < len(src) && (IsDigit(src[i]) || strings.IndexByte(".eE+-", src[i]) >= 0) {
+				i++
+			}
+			toks = append(toks, Token{Kind: Value, Val: src[start:i], Off: start})
+		case c == '"':
+			start := i
+			end, ok := ScanString(src, i)
+			if !ok {
+				return nil, Error{Off: start, Msg: "unterminated string"}
+			}
+			i = end
+			toks = append(toks, Token{Kind: Value, Val: src[start:i], Off: start})
+		default:
+			return nil, Error{Off: i, Msg: fmt.Sprintf("unexpected character: %q", c)}
+		}
+	}
+
+	toks = append(toks, Token{Kind: EOF, Off: len(src)})
+	return
+}
+
+// ScanString scans the string, or block string, starting at src[i], and
+// returns the offset just past its end. It reports false if the string
+// isn't terminated.
+//
+func ScanString(src string, i int) (int, bool) {
+	if strings.HasPrefix(src[i:], `"""`) {
+		for i += 3; i < len(src); i++ {
+			if strings.HasPrefix(src[i:], `\"""`) {
+				i += 3
+				continue
+			}
+			if strings.HasPrefix(src[i:], `"""`) {
+				return i + 3, true
+			}
+		}
+		return i, false
+	}
+
+	for i++; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, true
+		case '\n', '\r':
+			return i, false
+		}
+	}
+	return i, false
+}
+
+// IsLetter reports whether c is an ASCII letter.
+func IsLetter(c byte) bool { return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' }
+
+// IsDigit reports whether c is an ASCII digit.
+func IsDigit(c byte) bool { return '0' <= c && c <= '9' }
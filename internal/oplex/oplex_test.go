@@ -0,0 +1,60 @@
+package oplex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLex(t *testing.T) {
+	src := `query Q($a: [Int!] = [1, -2.5e3]) { # comment
+	f(s: "a\"b", t: """x\"""y""") @skip(if: $a) { ...F }
+}`
+
+	toks, err := Lex(src)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var vals []string
+	for _, tok := range toks {
+		vals = append(vals, tok.Val)
+	}
+
+	ex := []string{
+		"query", "Q", "(", "$", "a", ":", "[", "Int", "!", "]", "=", "[", "1", "-2.5e3", "]", ")", "{",
+		"f", "(", "s", ":", `"a\"b"`, "t", ":", `"""x\"""y"""`, ")", "@", "skip", "(", "if", ":", "$", "a", ")", "{", "...", "F", "}",
+		"}", "",
+	}
+	if !reflect.DeepEqual(ex, vals) {
+		t.Errorf("expected: %q, but got: %q", ex, vals)
+	}
+}
+
+func TestLex_Errors(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Src  string
+		Err  Error
+	}{
+		{
+			Name: "Unterminated",
+			Src:  `{ a(s: "b) }`,
+			Err:  Error{Off: 7, Msg: "unterminated string"},
+		},
+		{
+			Name: "Unexpected",
+			Src:  `{ a; }`,
+			Err:  Error{Off: 3, Msg: `unexpected character: ';'`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			_, err := Lex(testCase.Src)
+			if err != testCase.Err {
+				subT.Errorf("expected error: %#v, but got: %#v", testCase.Err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,685 @@
+// Package introspection contains a generator for the result of the standard
+// introspection query. Tools, such as GraphiQL, Apollo codegen and
+// graphql-voyager, consume this format, so they can be fed a schema without
+// it being served first.
+//
+package introspection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/types"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+// Options contains the options for the introspection generator.
+type Options struct {
+	// Wrap the result in a "data" object, like a GraphQL response (default: true)
+	Data bool
+
+	// Copy descriptions to the result (default: true)
+	Descriptions bool
+}
+
+// Generator generates the introspection query result for a GraphQL schema.
+type Generator struct {
+	bytes.Buffer
+
+	opts  *Options
+	kinds map[string]string
+	used  map[string]bool
+	log   *zap.Logger
+}
+
+// Reset overrides the bytes.Buffer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Buffer.Reset()
+	g.opts = nil
+	g.kinds = make(map[string]string)
+	g.used = make(map[string]bool)
+}
+
+// Generate generates the introspection query result of the given document.
+// The result is named after the document e.g. test.gql generates
+// test.introspection.json.
+//
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "introspection",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("introspection").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	g.opts, err = getOptions(doc, opts)
+	if err != nil {
+		return
+	}
+
+	g.log.Info("generating result")
+	err = g.generateResult(doc)
+	if err != nil {
+		return
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	f, err := gCtx.Open(fileName(doc))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	_, err = g.WriteTo(f)
+	return
+}
+
+// fileName returns the name of the introspection result generated for a document.
+func fileName(doc *ast.Document) string {
+	base := filepath.Base(doc.Name)
+	name := base[:len(base)-len(filepath.Ext(base))]
+	if name == "" {
+		name = "schema"
+	}
+	return name + ".introspection.json"
+}
+
+// builtinScalars are the scalars every schema provides, in the order
+// they're listed by the result.
+//
+var builtinScalars = []struct {
+	name, descr string
+}{
+	{"Int", "The `Int` scalar type represents non-fractional signed whole numeric values. Int can represent values between -(2^31) and 2^31 - 1."},
+	{"Float", "The `Float` scalar type represents signed double-precision fractional values as specified by [IEEE 754](https://en.wikipedia.org/wiki/IEEE_floating_point)."},
+	{"String", "The `String` scalar type represents textual data, represented as UTF-8 character sequences. The String type is most often used by GraphQL to represent free-form human-readable text."},
+	{"Boolean", "The `Boolean` scalar type represents `true` or `false`."},
+	{"ID", "The `ID` scalar type represents a unique identifier, often used to refetch an object or as key for a cache. The ID type appears in a JSON response as a String; however, it is not intended to be human-readable. When expected as an input type, any string (such as `\"4\"` or `\"test\"`) is acceptable as an input type, not intended to be human-readable."},
+}
+
+const builtinDirectivesSrc = `"Directs the executor to include this field or fragment only when the ` + "`if`" + ` argument is true."
+directive @include("Included when true." if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
+
+"Directs the executor to skip this field or fragment when the ` + "`if`" + ` argument is true."
+directive @skip("Skipped when true." if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
+
+"Marks an element of a GraphQL schema as no longer supported."
+directive @deprecated("Explains why this element was deprecated, usually also including a suggestion for how to access supported similar data." reason: String = "No longer supported") on FIELD_DEFINITION | ENUM_VALUE`
+
+// builtinDirectives are the directives every schema provides.
+var builtinDirectives []*ast.TypeDecl
+
+func init() {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "builtins", strings.NewReader(builtinDirectivesSrc), parser.ParseComments)
+	if err != nil {
+		panic(err)
+	}
+	builtinDirectives = doc.Types
+}
+
+// rootTypes returns the root operation types of a document, by operation.
+func rootTypes(doc *ast.Document) map[string]string {
+	roots := make(map[string]string, 3)
+	if doc.Schema == nil {
+		roots["query"] = "Query"
+		roots["mutation"] = "Mutation"
+		roots["subscription"] = "Subscription"
+		return roots
+	}
+
+	schema := doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Schema).Schema
+	for _, f := range schema.RootOps.List {
+		roots[f.Name.Name] = f.Type.(*ast.Field_Ident).Ident.Name
+	}
+	return roots
+}
+
+// generateResult encodes the introspection query result to the underlying buffer.
+func (g *Generator) generateResult(doc *ast.Document) error {
+	var decls, dirs []*ast.TypeDecl
+	implements := make(map[string][]string)
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Directive:
+			dirs = append(dirs, d)
+			continue
+		case *ast.TypeSpec_Scalar:
+			g.kinds[name] = "SCALAR"
+		case *ast.TypeSpec_Object:
+			g.kinds[name] = "OBJECT"
+			for _, inter := range v.Object.Interfaces {
+				implements[inter.Name] = append(implements[inter.Name], name)
+			}
+		case *ast.TypeSpec_Interface:
+			g.kinds[name] = "INTERFACE"
+		case *ast.TypeSpec_Union:
+			g.kinds[name] = "UNION"
+		case *ast.TypeSpec_Enum:
+			g.kinds[name] = "ENUM"
+		case *ast.TypeSpec_Input:
+			g.kinds[name] = "INPUT_OBJECT"
+		default:
+			continue
+		}
+		decls = append(decls, d)
+	}
+
+	schema := object{}
+	roots := rootTypes(doc)
+	for _, op := range []string{"query", "mutation", "subscription"} {
+		var typ interface{}
+		if name, ok := roots[op]; ok && g.kinds[name] == "OBJECT" {
+			typ = object{{"name", name}}
+		}
+		schema = append(schema, member{op + "Type", typ})
+	}
+
+	types := []interface{}{}
+	for _, d := range decls {
+		types = append(types, g.fullType(d, implements))
+	}
+
+	directives := []interface{}{}
+	declared := make(map[string]bool, len(dirs))
+	for _, d := range dirs {
+		declared[d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Name.Name] = true
+		if dir := g.directive(d); dir != nil {
+			directives = append(directives, dir)
+		}
+	}
+	for _, d := range builtinDirectives {
+		if !declared[d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Name.Name] {
+			directives = append(directives, g.directive(d))
+		}
+	}
+
+	// Builtin scalars are only listed once they're known to be used
+	for _, s := range builtinScalars {
+		if g.used[s.name] && g.kinds[s.name] == "" {
+			types = append(types, g.typ("SCALAR", s.name, s.descr))
+		}
+	}
+
+	schema = append(schema, member{"types", types}, member{"directives", directives})
+
+	// The standard result has no directives applied to the schema, so
+	// they're only added, like graphql-java's appliedDirectives, if
+	// there are any.
+	if applied := schemaDirectives(doc); len(applied) > 0 {
+		schema = append(schema, member{"appliedDirectives", applied})
+	}
+
+	var result interface{} = object{{"__schema", schema}}
+	if g.opts.Data {
+		result = object{{"data", result}}
+	}
+
+	enc := json.NewEncoder(g)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+// typ returns a __Type, whose members are all null except for the ones
+// describing its kind.
+//
+func (g *Generator) typ(kind, name, descr string) object {
+	t := object{
+		{"kind", kind},
+		{"name", name},
+		{"description", nil},
+		{"fields", nil},
+		{"inputFields", nil},
+		{"interfaces", nil},
+		{"enumValues", nil},
+		{"possibleTypes", nil},
+	}
+	if descr != "" && g.opts.Descriptions {
+		t[2].val = descr
+	}
+	return t
+}
+
+// fullType returns the __Type of a type declaration.
+func (g *Generator) fullType(d *ast.TypeDecl, implements map[string][]string) object {
+	ts := d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+	name := ts.Name.Name
+
+	t := g.typ(g.kinds[name], name, g.description(d.Doc))
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Object:
+		interfaces := []interface{}{}
+		for _, inter := range v.Object.Interfaces {
+			interfaces = append(interfaces, g.typeRef(inter))
+		}
+
+		t[3].val = g.fields(v.Object.Fields)
+		t[5].val = interfaces
+	case *ast.TypeSpec_Interface:
+		possible := []interface{}{}
+		for _, obj := range implements[name] {
+			possible = append(possible, g.typeRef(&ast.Ident{Name: obj}))
+		}
+
+		t[3].val = g.fields(v.Interface.Fields)
+		t[5].val = []interface{}{}
+		t[7].val = possible
+	case *ast.TypeSpec_Union:
+		possible := []interface{}{}
+		for _, mem := range v.Union.Members {
+			possible = append(possible, g.typeRef(mem))
+		}
+		t[7].val = possible
+	case *ast.TypeSpec_Enum:
+		vals := []interface{}{}
+		if v.Enum.Values != nil {
+			for _, val := range v.Enum.Values.List {
+				vals = append(vals, append(object{
+					{"name", val.Name.Name},
+					{"description", nullable(g.description(val.Doc))},
+				}, deprecation(val.Directives)...))
+			}
+		}
+		t[6].val = vals
+	case *ast.TypeSpec_Input:
+		t[4].val = g.inputValues(v.Input.Fields)
+	}
+	return t
+}
+
+func (g *Generator) fields(fields *ast.FieldList) []interface{} {
+	fs := []interface{}{}
+	if fields == nil {
+		return fs
+	}
+
+	for _, f := range fields.List {
+		var typ interface{}
+		switch v := f.Type.(type) {
+		case *ast.Field_Ident:
+			typ = v.Ident
+		case *ast.Field_List:
+			typ = v.List
+		case *ast.Field_NonNull:
+			typ = v.NonNull
+		}
+
+		fs = append(fs, append(object{
+			{"name", f.Name.Name},
+			{"description", nullable(g.description(f.Doc))},
+			{"args", g.inputValues(f.Args)},
+			{"type", g.typeRef(typ)},
+		}, deprecation(f.Directives)...))
+	}
+	return fs
+}
+
+// inputValues returns the __InputValues of arguments or input fields.
+func (g *Generator) inputValues(vals *ast.InputValueList) []interface{} {
+	ivs := []interface{}{}
+	if vals == nil {
+		return ivs
+	}
+
+	for _, a := range vals.List {
+		var typ interface{}
+		switch v := a.Type.(type) {
+		case *ast.InputValue_Ident:
+			typ = v.Ident
+		case *ast.InputValue_List:
+			typ = v.List
+		case *ast.InputValue_NonNull:
+			typ = v.NonNull
+		}
+
+		var def interface{}
+		switch v := a.Default.(type) {
+		case *ast.InputValue_BasicLit:
+			def = valueString(v.BasicLit)
+		case *ast.InputValue_CompositeLit:
+			def = valueString(v.CompositeLit)
+		}
+
+		ivs = append(ivs, object{
+			{"name", a.Name.Name},
+			{"description", nullable(g.description(a.Doc))},
+			{"type", g.typeRef(typ)},
+			{"defaultValue", def},
+		})
+	}
+	return ivs
+}
+
+// directive returns the __Directive of a directive declaration, or nil if
+// it may only be applied to documents, which is a gqlc extension that
+// introspection has no location for.
+//
+func (g *Generator) directive(d *ast.TypeDecl) object {
+	ts := d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+	dir := ts.Type.(*ast.TypeSpec_Directive).Directive
+
+	locs := []interface{}{}
+	for _, loc := range dir.Locs {
+		if loc.Loc == ast.DirectiveLocation_DOCUMENT || loc.Loc == ast.DirectiveLocation_NoPos {
+			continue
+		}
+		locs = append(locs, loc.Loc.String())
+	}
+	if len(locs) == 0 {
+		return nil
+	}
+
+	return object{
+		{"name", ts.Name.Name},
+		{"description", nullable(g.description(d.Doc))},
+		{"locations", locs},
+		{"args", g.inputValues(dir.Args)},
+	}
+}
+
+// typeRef returns the __Type reference of a field or argument type, which
+// nests wrapping types through ofType.
+//
+func (g *Generator) typeRef(typ interface{}) object {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		g.used[v.Name] = true
+
+		kind := g.kinds[v.Name]
+		if kind == "" {
+			kind = "SCALAR"
+		}
+		return object{{"kind", kind}, {"name", v.Name}, {"ofType", nil}}
+	case *ast.List:
+		var elem interface{}
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			elem = w.Ident
+		case *ast.List_List:
+			elem = w.List
+		case *ast.List_NonNull:
+			elem = w.NonNull
+		}
+		return object{{"kind", "LIST"}, {"name", nil}, {"ofType", g.typeRef(elem)}}
+	case *ast.NonNull:
+		var elem interface{}
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			elem = w.Ident
+		case *ast.NonNull_List:
+			elem = w.List
+		}
+		return object{{"kind", "NON_NULL"}, {"name", nil}, {"ofType", g.typeRef(elem)}}
+	}
+	return nil
+}
+
+// deprecation returns the isDeprecated and deprecationReason members of a
+// field or enum value.
+//
+func deprecation(dirs []*ast.DirectiveLit) object {
+	for _, d := range dirs {
+		if d.Name != "deprecated" {
+			continue
+		}
+
+		reason := "No longer supported"
+		if d.Args != nil {
+			for _, arg := range d.Args.Args {
+				if b, ok := arg.Value.(*ast.Arg_BasicLit); ok && arg.Name.Name == "reason" {
+					reason = unquote(b.BasicLit.Value)
+				}
+			}
+		}
+		return object{{"isDeprecated", true}, {"deprecationReason", reason}}
+	}
+	return object{{"isDeprecated", false}, {"deprecationReason", nil}}
+}
+
+// schemaDirectives returns the directives applied to the schema, with their
+// args' values printed as GraphQL literals.
+//
+func schemaDirectives(doc *ast.Document) (applied []interface{}) {
+	if doc.Schema == nil {
+		return
+	}
+
+	for _, d := range doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Directives {
+		if types.IsGqlcDirective(d.Name) {
+			continue
+		}
+
+		args := []interface{}{}
+		if d.Args != nil {
+			for _, a := range d.Args.Args {
+				var val interface{}
+				switch v := a.Value.(type) {
+				case *ast.Arg_BasicLit:
+					val = v.BasicLit
+				case *ast.Arg_CompositeLit:
+					val = v.CompositeLit
+				}
+				args = append(args, object{{"name", a.Name.Name}, {"value", valueString(val)}})
+			}
+		}
+		applied = append(applied, object{{"name", d.Name}, {"args", args}})
+	}
+	return
+}
+
+// valueString prints a default value the way it's written in GraphQL, which
+// is how introspection represents them.
+//
+func valueString(val interface{}) string {
+	var b strings.Builder
+	writeVal(&b, val)
+	return b.String()
+}
+
+func writeVal(b *strings.Builder, val interface{}) {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		if v.Kind == token.Token_STRING && strings.HasPrefix(v.Value, `"""`) {
+			b.WriteString(strconv.Quote(unquote(v.Value)))
+			return
+		}
+		b.WriteString(v.Value)
+	case *ast.CompositeLit:
+		switch w := v.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			writeVal(b, w.BasicLit)
+		case *ast.CompositeLit_ListLit:
+			writeVal(b, w.ListLit)
+		case *ast.CompositeLit_ObjLit:
+			writeVal(b, w.ObjLit)
+		}
+	case *ast.ListLit:
+		var vals []interface{}
+		switch w := v.List.(type) {
+		case *ast.ListLit_BasicList:
+			for _, bval := range w.BasicList.Values {
+				vals = append(vals, bval)
+			}
+		case *ast.ListLit_CompositeList:
+			for _, cval := range w.CompositeList.Values {
+				vals = append(vals, cval)
+			}
+		}
+
+		b.WriteByte('[')
+		for i, iv := range vals {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeVal(b, iv)
+		}
+		b.WriteByte(']')
+	case *ast.ObjLit:
+		b.WriteByte('{')
+		for i, p := range v.Fields {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(p.Key.Name)
+			b.WriteString(": ")
+			writeVal(b, p.Val)
+		}
+		b.WriteByte('}')
+	}
+}
+
+func unquote(s string) string {
+	if strings.HasPrefix(s, `"""`) {
+		return strings.TrimSpace(strings.Trim(s, `"`))
+	}
+
+	u, err := strconv.Unquote(s)
+	if err != nil {
+		return strings.Trim(s, `"`)
+	}
+	return u
+}
+
+func (g *Generator) description(doc *ast.DocGroup) string {
+	if doc == nil || !g.opts.Descriptions {
+		return ""
+	}
+	return strings.TrimSpace(doc.Text())
+}
+
+// nullable returns nil for an empty string, since introspection uses null
+// for missing descriptions.
+//
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// member is a single key/value pair of an object.
+type member struct {
+	key string
+	val interface{}
+}
+
+// object is a JSON object which keeps the order of its members, so results
+// read like the response of the introspection query.
+//
+type object []member
+
+// MarshalJSON implements the json.Marshaler interface.
+func (o object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+
+		val, err := marshal(m.val)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(val)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Data:         true,
+		Descriptions: true,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "introspection" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		inOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range inOpts.Fields {
+			switch arg.Key.Name {
+			case "data":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Data = b
+			case "descriptions":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Descriptions = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts != nil {
+		if d, ok := opts["data"]; ok {
+			gOpts.Data, _ = d.(bool)
+		}
+		if d, ok := opts["descriptions"]; ok {
+			gOpts.Descriptions, _ = d.(bool)
+		}
+	}
+	return
+}
@@ -0,0 +1,508 @@
+package introspection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "test.introspection.json", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected introspection output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected introspection output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestTypeRef(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Type interface{}
+		JSON string
+	}{
+		{Name: "Named", Type: &ast.Ident{Name: "User"}, JSON: `{"kind":"OBJECT","name":"User","ofType":null}`},
+		{Name: "Scalar", Type: &ast.Ident{Name: "Int"}, JSON: `{"kind":"SCALAR","name":"Int","ofType":null}`},
+		{
+			Name: "NonNull",
+			Type: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}},
+			JSON: `{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID","ofType":null}}`,
+		},
+		{
+			Name: "List",
+			Type: &ast.NonNull{Type: &ast.NonNull_List{List: &ast.List{Type: &ast.List_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "User"}}}}}}},
+			JSON: `{"kind":"NON_NULL","name":null,"ofType":{"kind":"LIST","name":null,"ofType":{"kind":"NON_NULL","name":null,"ofType":{"kind":"OBJECT","name":"User","ofType":null}}}}`,
+		},
+	}
+
+	g := new(Generator)
+	g.Reset()
+	g.kinds["User"] = "OBJECT"
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			b, err := json.Marshal(g.typeRef(testCase.Type))
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if string(b) != testCase.JSON {
+				subT.Errorf("expected: %s, but got: %s", testCase.JSON, b)
+			}
+		})
+	}
+}
+
+func TestValueString(t *testing.T) {
+	gqlSrc := `input Filter {
+	a: String = "a"
+	b: [Float] = [1, 2.5]
+	c: Point = {x: 1, y: 2}
+	d: Direction = NORTH
+	e: String = """block"""
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := []string{`"a"`, `[1, 2.5]`, `{x: 1, y: 2}`, `NORTH`, `"block"`}
+	fields := doc.Types[0].Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Input).Input.Fields.List
+	for i, f := range fields {
+		var s string
+		switch v := f.Default.(type) {
+		case *ast.InputValue_BasicLit:
+			s = valueString(v.BasicLit)
+		case *ast.InputValue_CompositeLit:
+			s = valueString(v.CompositeLit)
+		}
+
+		if s != expected[i] {
+			t.Errorf("expected default value %s for %s, but got: %s", expected[i], f.Name.Name, s)
+		}
+	}
+}
+
+func TestBuiltins(t *testing.T) {
+	gqlSrc := `type Query {
+	count: Int
+}
+
+directive @deprecated(reason: String) on FIELD_DEFINITION
+
+directive @gen on DOCUMENT`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var b bytes.Buffer
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err = g.Generate(ctx, doc, map[string]interface{}{"data": false})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var result struct {
+		Schema struct {
+			QueryType *struct {
+				Name string `json:"name"`
+			} `json:"queryType"`
+			MutationType *struct{} `json:"mutationType"`
+			Types        []struct {
+				Name string `json:"name"`
+			} `json:"types"`
+			Directives []struct {
+				Name string `json:"name"`
+			} `json:"directives"`
+		} `json:"__schema"`
+	}
+	err = json.Unmarshal(b.Bytes(), &result)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if result.Schema.QueryType == nil || result.Schema.QueryType.Name != "Query" || result.Schema.MutationType != nil {
+		t.Errorf("expected only a query type, but got: %s", b.String())
+	}
+
+	var types, dirs []string
+	for _, typ := range result.Schema.Types {
+		types = append(types, typ.Name)
+	}
+	for _, dir := range result.Schema.Directives {
+		dirs = append(dirs, dir.Name)
+	}
+
+	if strings.Join(types, ",") != "Query,Int,String,Boolean" {
+		t.Errorf("expected only the builtin scalars which are used, but got: %v", types)
+	}
+	if strings.Join(dirs, ",") != "deprecated,include,skip" {
+		t.Errorf("expected the declared @deprecated and no document directives, but got: %v", dirs)
+	}
+}
+
+func TestSchemaDirectives(t *testing.T) {
+	gqlSrc := `schema @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key"]) @public {
+	query: Query
+}
+
+type Query {
+	count: Int
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err = new(Generator).Generate(ctx, doc, map[string]interface{}{"data": false})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := `  "appliedDirectives": [
+      {
+        "name": "link",
+        "args": [
+          {
+            "name": "url",
+            "value": "\"https://specs.apollo.dev/federation/v2.0\""
+          },
+          {
+            "name": "import",
+            "value": "[\"@key\"]"
+          }
+        ]
+      },
+      {
+        "name": "public",
+        "args": []
+      }
+    ]
+  }
+}
+`
+	if !strings.HasSuffix(b.String(), ex) {
+		t.Errorf("expected the result to end with %q, but got:\n%s", ex, b.String())
+	}
+}
+
+func TestOptions(t *testing.T) {
+	doc := &ast.Document{
+		Directives: []*ast.DirectiveLit{
+			{
+				Name: "introspection",
+				Args: &ast.CallExpr{Args: []*ast.Arg{{
+					Name: &ast.Ident{Name: "options"},
+					Value: &ast.Arg_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+						Fields: []*ast.ObjLit_Pair{
+							{
+								Key: &ast.Ident{Name: "data"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_BOOL, Value: "false"}}},
+							},
+							{
+								Key: &ast.Ident{Name: "descriptions"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_BOOL, Value: "false"}}},
+							},
+						},
+					}}}},
+				}}},
+			},
+		},
+	}
+
+	gOpts, err := getOptions(doc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.Data || !gOpts.Descriptions {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+
+	if name := fileName(&ast.Document{Name: "dir/api.gql"}); name != "api.introspection.json" {
+		t.Errorf("expected file name api.introspection.json, but got: %s", name)
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := &Generator{}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := &Generator{}
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `type Query {
+	name: String!
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, map[string]interface{}{"data": false, "descriptions": false})
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Print(b.String())
+	// Output:
+	// {
+	//   "__schema": {
+	//     "queryType": {
+	//       "name": "Query"
+	//     },
+	//     "mutationType": null,
+	//     "subscriptionType": null,
+	//     "types": [
+	//       {
+	//         "kind": "OBJECT",
+	//         "name": "Query",
+	//         "description": null,
+	//         "fields": [
+	//           {
+	//             "name": "name",
+	//             "description": null,
+	//             "args": [],
+	//             "type": {
+	//               "kind": "NON_NULL",
+	//               "name": null,
+	//               "ofType": {
+	//                 "kind": "SCALAR",
+	//                 "name": "String",
+	//                 "ofType": null
+	//               }
+	//             },
+	//             "isDeprecated": false,
+	//             "deprecationReason": null
+	//           }
+	//         ],
+	//         "inputFields": null,
+	//         "interfaces": [],
+	//         "enumValues": null,
+	//         "possibleTypes": null
+	//       },
+	//       {
+	//         "kind": "SCALAR",
+	//         "name": "String",
+	//         "description": null,
+	//         "fields": null,
+	//         "inputFields": null,
+	//         "interfaces": null,
+	//         "enumValues": null,
+	//         "possibleTypes": null
+	//       },
+	//       {
+	//         "kind": "SCALAR",
+	//         "name": "Boolean",
+	//         "description": null,
+	//         "fields": null,
+	//         "inputFields": null,
+	//         "interfaces": null,
+	//         "enumValues": null,
+	//         "possibleTypes": null
+	//       }
+	//     ],
+	//     "directives": [
+	//       {
+	//         "name": "include",
+	//         "description": null,
+	//         "locations": [
+	//           "FIELD",
+	//           "FRAGMENT_SPREAD",
+	//           "INLINE_FRAGMENT"
+	//         ],
+	//         "args": [
+	//           {
+	//             "name": "if",
+	//             "description": null,
+	//             "type": {
+	//               "kind": "NON_NULL",
+	//               "name": null,
+	//               "ofType": {
+	//                 "kind": "SCALAR",
+	//                 "name": "Boolean",
+	//                 "ofType": null
+	//               }
+	//             },
+	//             "defaultValue": null
+	//           }
+	//         ]
+	//       },
+	//       {
+	//         "name": "skip",
+	//         "description": null,
+	//         "locations": [
+	//           "FIELD",
+	//           "FRAGMENT_SPREAD",
+	//           "INLINE_FRAGMENT"
+	//         ],
+	//         "args": [
+	//           {
+	//             "name": "if",
+	//             "description": null,
+	//             "type": {
+	//               "kind": "NON_NULL",
+	//               "name": null,
+	//               "ofType": {
+	//                 "kind": "SCALAR",
+	//                 "name": "Boolean",
+	//                 "ofType": null
+	//               }
+	//             },
+	//             "defaultValue": null
+	//           }
+	//         ]
+	//       },
+	//       {
+	//         "name": "deprecated",
+	//         "description": null,
+	//         "locations": [
+	//           "FIELD_DEFINITION",
+	//           "ENUM_VALUE"
+	//         ],
+	//         "args": [
+	//           {
+	//             "name": "reason",
+	//             "description": null,
+	//             "type": {
+	//               "kind": "SCALAR",
+	//               "name": "String",
+	//               "ofType": null
+	//             },
+	//             "defaultValue": "\"No longer supported\""
+	//           }
+	//         ]
+	//       }
+	//     ]
+	//   }
+	// }
+}
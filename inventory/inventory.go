@@ -0,0 +1,245 @@
+// Package inventory contains a generator for a flat inventory of the fields
+// of GraphQL Documents. The inventory is written as CSV, or TSV, so it can be
+// imported into spreadsheets and data catalogs.
+//
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/ir"
+	"github.com/gqlc/graphql/ast"
+	"go.uber.org/zap"
+)
+
+// Formats an inventory can be written as.
+const (
+	CSV = "CSV"
+	TSV = "TSV"
+)
+
+// Options contains the options for the inventory generator.
+type Options struct {
+	// Format is either CSV or TSV (default: CSV)
+	Format string
+
+	// Write a header row naming the columns (default: true)
+	Header bool
+}
+
+// Columns are the columns of every row, in order.
+var Columns = []string{
+	"type",
+	"kind",
+	"field",
+	"argument",
+	"return_type",
+	"nullable",
+	"deprecated",
+	"deprecation_reason",
+	"description",
+}
+
+// Kinds of types which have rows.
+const (
+	objectKind    = "object"
+	interfaceKind = "interface"
+	inputKind     = "input"
+	enumKind      = "enum"
+)
+
+// Generator generates an inventory of the fields of a GraphQL schema.
+type Generator struct {
+	bytes.Buffer
+
+	log *zap.Logger
+}
+
+// Generate generates the inventory of the given document. The inventory is
+// named after the document and its format e.g. test.gql generates test.csv,
+// or test.tsv.
+//
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "inventory",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("inventory").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	gOpts, err := getOptions(doc, opts)
+	if err != nil {
+		return
+	}
+
+	g.log.Info("generating inventory")
+	w := csv.NewWriter(g)
+	switch gOpts.Format {
+	case CSV:
+	case TSV:
+		w.Comma = '\t'
+	default:
+		return fmt.Errorf("unknown inventory format: %s", gOpts.Format)
+	}
+
+	s, err := ir.Load(doc)
+	if err != nil {
+		return
+	}
+
+	rs := rows(s)
+	if gOpts.Format == TSV {
+		// TSV readers rarely understand quoting, so fields are kept to
+		// a single line instead.
+		//
+		for _, r := range rs {
+			r[len(r)-1] = strings.Join(strings.Fields(r[len(r)-1]), " ")
+		}
+	}
+	if gOpts.Header {
+		rs = append([][]string{Columns}, rs...)
+	}
+
+	err = w.WriteAll(rs)
+	if err != nil {
+		return
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	f, err := gCtx.Open(fileName(doc, gOpts.Format))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	_, err = g.WriteTo(f)
+	return
+}
+
+// fileName returns the name of the inventory generated for a document.
+func fileName(doc *ast.Document, format string) string {
+	base := filepath.Base(doc.Name)
+	name := base[:len(base)-len(filepath.Ext(base))]
+	if name == "" {
+		name = "schema"
+	}
+	return name + "." + strings.ToLower(format)
+}
+
+// rows returns a row for every field, argument, input field and enum
+// value of a schema, in the order they're declared. Arguments follow
+// their field, and their return_type is the type they accept.
+//
+func rows(s *ir.Schema) (rs [][]string) {
+	for _, t := range s.Types() {
+		switch t.Kind() {
+		case ir.Object:
+			rs = append(rs, fieldRows(t, objectKind)...)
+		case ir.Interface:
+			rs = append(rs, fieldRows(t, interfaceKind)...)
+		case ir.Input:
+			for _, f := range t.InputFields() {
+				rs = append(rs, row(t.Name(), inputKind, f.Name(), "", f.Type(), f))
+			}
+		case ir.Enum:
+			for _, v := range t.Values() {
+				rs = append(rs, row(t.Name(), enumKind, v.Name(), "", nil, v))
+			}
+		}
+	}
+	return
+}
+
+func fieldRows(t *ir.Named, kind string) (rs [][]string) {
+	for _, f := range t.Fields() {
+		rs = append(rs, row(t.Name(), kind, f.Name(), "", f.Type(), f))
+		for _, a := range f.Args() {
+			rs = append(rs, row(t.Name(), kind, f.Name(), a.Name(), a.Type(), a))
+		}
+	}
+	return
+}
+
+// member is a field, argument, input field or enum value.
+type member interface {
+	Description() string
+	Deprecated() (string, bool)
+}
+
+func row(typ, kind, field, arg string, t ir.Type, m member) []string {
+	var typeName, nullable string
+	if t != nil {
+		typeName = t.String()
+		nullable = strconv.FormatBool(ir.IsNullable(t))
+	}
+
+	reason, deprecated := m.Deprecated()
+	return []string{typ, kind, field, arg, typeName, nullable, strconv.FormatBool(deprecated), reason, m.Description()}
+}
+
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Format: CSV,
+		Header: true,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "inventory" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		iOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range iOpts.Fields {
+			switch arg.Key.Name {
+			case "format":
+				gOpts.Format = arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+			case "header":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Header = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts != nil {
+		if f, ok := opts["format"].(string); ok {
+			gOpts.Format = f
+		}
+		if h, ok := opts["header"]; ok {
+			gOpts.Header, _ = h.(bool)
+		}
+	}
+
+	gOpts.Format = strings.ToUpper(strings.Trim(gOpts.Format, `"`))
+	return
+}
@@ -0,0 +1,324 @@
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/ir"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "test.csv", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, parser.ParseComments)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected inventory output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected inventory output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+const rowsSrc = `type Query {
+	"Find users by name."
+	users(
+		"Part of their name."
+		name: String!
+		first: Int = 10 @deprecated
+	): [User!]!
+}
+
+interface Node {
+	id: ID!
+}
+
+input Filter {
+	role: Role
+}
+
+enum Role {
+	ADMIN
+	"Can only read."
+	USER @deprecated(reason: "Use ADMIN.")
+}
+
+scalar Time
+
+union Result = Query`
+
+func TestRows(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(rowsSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := [][]string{
+		{"Query", "object", "users", "", "[User!]!", "false", "false", "", "Find users by name."},
+		{"Query", "object", "users", "name", "String!", "false", "false", "", "Part of their name."},
+		{"Query", "object", "users", "first", "Int", "true", "true", "No longer supported", ""},
+		{"Node", "interface", "id", "", "ID!", "false", "false", "", ""},
+		{"Filter", "input", "role", "", "Role", "true", "false", "", ""},
+		{"Role", "enum", "ADMIN", "", "", "", "false", "", ""},
+		{"Role", "enum", "USER", "", "", "", "true", "Use ADMIN.", "Can only read."},
+	}
+
+	s, err := ir.Load(doc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	rs := rows(s)
+	if len(rs) != len(ex) {
+		t.Fatalf("expected %d rows, but got: %q", len(ex), rs)
+	}
+	for i := range ex {
+		if strings.Join(rs[i], "|") != strings.Join(ex[i], "|") {
+			t.Errorf("expected row %d to be: %q, but got: %q", i, ex[i], rs[i])
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(`type Query {
+	"Says hello, to someone."
+	hello(to: String): String
+}`), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	testCases := []struct {
+		Name string
+		Opts map[string]interface{}
+		Ex   string
+	}{
+		{
+			Name: "CSV",
+			Ex: `type,kind,field,argument,return_type,nullable,deprecated,deprecation_reason,description
+Query,object,hello,,String,true,false,,"Says hello, to someone."
+Query,object,hello,to,String,true,false,,
+`,
+		},
+		{
+			Name: "TSV",
+			Opts: map[string]interface{}{"format": "tsv", "header": false},
+			Ex: "Query\tobject\thello\t\tString\ttrue\tfalse\t\tSays hello, to someone.\n" +
+				"Query\tobject\thello\tto\tString\ttrue\tfalse\t\t\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			var b bytes.Buffer
+			ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+			err := new(Generator).Generate(ctx, doc, testCase.Opts)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			gen.CompareBytes(subT, []byte(testCase.Ex), b.Bytes())
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	doc := &ast.Document{
+		Directives: []*ast.DirectiveLit{
+			{
+				Name: "inventory",
+				Args: &ast.CallExpr{Args: []*ast.Arg{{
+					Name: &ast.Ident{Name: "options"},
+					Value: &ast.Arg_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+						Fields: []*ast.ObjLit_Pair{
+							{
+								Key: &ast.Ident{Name: "format"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_IDENT, Value: TSV}}},
+							},
+							{
+								Key: &ast.Ident{Name: "header"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_BOOL, Value: "false"}}},
+							},
+						},
+					}}}},
+				}}},
+			},
+		},
+	}
+
+	gOpts, err := getOptions(doc, map[string]interface{}{"header": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.Format != TSV || !gOpts.Header {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+
+	gOpts, err = getOptions(doc, map[string]interface{}{"format": "csv"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.Format != CSV {
+		t.Errorf("expected format %s, but got: %s", CSV, gOpts.Format)
+	}
+
+	if name := fileName(&ast.Document{Name: "dir/api.gql"}, TSV); name != "api.tsv" {
+		t.Errorf("expected file name api.tsv, but got: %s", name)
+	}
+
+	err = new(Generator).Generate(gen.WithContext(context.Background(), gen.TestCtx{Writer: ioutil.Discard}), doc, map[string]interface{}{"format": "xlsx"})
+	if err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := &Generator{}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := &Generator{}
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `type Query {
+	"The current user."
+	me: User
+}
+
+type User {
+	name: String!
+	nick: String @deprecated(reason: "Use name.")
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, nil)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Print(b.String())
+	// Output:
+	// type,kind,field,argument,return_type,nullable,deprecated,deprecation_reason,description
+	// Query,object,me,,User,true,false,,The current user.
+	// User,object,name,,String!,false,false,,
+	// User,object,nick,,String,true,true,Use name.,
+}
@@ -0,0 +1,200 @@
+package ir
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/graphql/token"
+)
+
+// Field is a field of an object or interface type.
+type Field struct {
+	name   string
+	descr  string
+	pos    token.Pos
+	typ    Type
+	args   []*InputValue
+	dirs   []*Directive
+	parent *Named
+}
+
+// Name returns the name of the field.
+func (f *Field) Name() string { return f.name }
+
+// Description returns the description of the field.
+func (f *Field) Description() string { return f.descr }
+
+// Pos returns where the field is declared.
+func (f *Field) Pos() token.Pos { return f.pos }
+
+// Type returns the type of the field.
+func (f *Field) Type() Type { return f.typ }
+
+// Args returns the arguments of the field.
+func (f *Field) Args() []*InputValue { return f.args }
+
+// Directives returns the directives applied to the field.
+func (f *Field) Directives() []*Directive { return f.dirs }
+
+// Parent returns the type the field belongs to.
+func (f *Field) Parent() *Named { return f.parent }
+
+// Deprecated returns the reason the field is deprecated, if it is.
+func (f *Field) Deprecated() (string, bool) { return deprecation(f.dirs) }
+
+// InputValue is an argument, of a field or directive, or a field of an
+// input type.
+//
+type InputValue struct {
+	name  string
+	descr string
+	pos   token.Pos
+	typ   Type
+	def   string
+	dirs  []*Directive
+}
+
+// Name returns the name of the value.
+func (v *InputValue) Name() string { return v.name }
+
+// Description returns the description of the value.
+func (v *InputValue) Description() string { return v.descr }
+
+// Pos returns where the value is declared.
+func (v *InputValue) Pos() token.Pos { return v.pos }
+
+// Type returns the type of the value.
+func (v *InputValue) Type() Type { return v.typ }
+
+// Default returns the GraphQL syntax of the default value, or "" if the
+// value has none.
+//
+func (v *InputValue) Default() string { return v.def }
+
+// Directives returns the directives applied to the value.
+func (v *InputValue) Directives() []*Directive { return v.dirs }
+
+// Deprecated returns the reason the value is deprecated, if it is.
+func (v *InputValue) Deprecated() (string, bool) { return deprecation(v.dirs) }
+
+// EnumValue is a value of an enum.
+type EnumValue struct {
+	name  string
+	descr string
+	pos   token.Pos
+	dirs  []*Directive
+}
+
+// Name returns the name of the value.
+func (v *EnumValue) Name() string { return v.name }
+
+// Description returns the description of the value.
+func (v *EnumValue) Description() string { return v.descr }
+
+// Pos returns where the value is declared.
+func (v *EnumValue) Pos() token.Pos { return v.pos }
+
+// Directives returns the directives applied to the value.
+func (v *EnumValue) Directives() []*Directive { return v.dirs }
+
+// Deprecated returns the reason the value is deprecated, if it is.
+func (v *EnumValue) Deprecated() (string, bool) { return deprecation(v.dirs) }
+
+// Directive is a directive applied to a type, field or value.
+type Directive struct {
+	name string
+	pos  token.Pos
+	args []*Argument
+	def  *DirectiveDef
+}
+
+// Argument is an argument given to a directive.
+type Argument struct {
+	// Name is the name of the argument.
+	Name string
+
+	// Value is the GraphQL syntax of its value, e.g. "reason" with its
+	// quotes, or { a: 1 }.
+	//
+	Value string
+}
+
+// Name returns the name of the directive, without its @.
+func (d *Directive) Name() string { return d.name }
+
+// Pos returns where the directive is applied.
+func (d *Directive) Pos() token.Pos { return d.pos }
+
+// Args returns the arguments given to the directive.
+func (d *Directive) Args() []*Argument { return d.args }
+
+// Arg returns the GraphQL syntax of the value of an argument, if it's
+// given.
+//
+func (d *Directive) Arg(name string) (string, bool) {
+	for _, a := range d.args {
+		if a.Name == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// Def returns the declaration of the directive, or nil if none of the
+// documents loaded declare it.
+//
+func (d *Directive) Def() *DirectiveDef { return d.def }
+
+// DirectiveDef is the declaration of a directive.
+type DirectiveDef struct {
+	name  string
+	descr string
+	pos   token.Pos
+	doc   string
+	args  []*InputValue
+	locs  []string
+}
+
+// Name returns the name of the directive, without its @.
+func (d *DirectiveDef) Name() string { return d.name }
+
+// Description returns the description of the directive.
+func (d *DirectiveDef) Description() string { return d.descr }
+
+// Pos returns where the directive is declared.
+func (d *DirectiveDef) Pos() token.Pos { return d.pos }
+
+// Document returns the name of the document declaring the directive.
+func (d *DirectiveDef) Document() string { return d.doc }
+
+// Args returns the arguments of the directive.
+func (d *DirectiveDef) Args() []*InputValue { return d.args }
+
+// Locations returns where the directive can be applied, e.g. FIELD_DEFINITION.
+func (d *DirectiveDef) Locations() []string { return d.locs }
+
+// deprecation returns the reason given to a @deprecated directive, or the
+// default reason if none is given.
+//
+func deprecation(dirs []*Directive) (string, bool) {
+	for _, d := range dirs {
+		if d.name != "deprecated" {
+			continue
+		}
+
+		reason, ok := d.Arg("reason")
+		if !ok {
+			return "No longer supported", true
+		}
+		return unquote(reason), true
+	}
+	return "", false
+}
+
+func unquote(s string) string {
+	u, err := strconv.Unquote(s)
+	if err != nil {
+		return strings.Trim(s, `"`)
+	}
+	return u
+}
@@ -0,0 +1,164 @@
+// Package ir provides the types, fields, directives and references of a
+// GraphQL schema as a Go API, in the spirit of go/types, so generators and
+// other tools, e.g. linters, can work with a schema without walking the AST
+// of the documents declaring it.
+//
+// A Schema is loaded from one or more documents, with extensions merged
+// into the types they extend, and every reference to a type resolved to
+// the *Named type declaring it:
+//
+//	s, err := ir.Load(doc)
+//	if err != nil {
+//		return err
+//	}
+//
+//	for _, t := range s.Types() {
+//		for _, f := range t.Fields() {
+//			fmt.Println(t.Name(), f.Name(), f.Type(), ir.Unwrap(f.Type()).Kind())
+//		}
+//	}
+//
+// Positions are the token.Pos of the parser, which are resolved by the
+// token.DocSet the documents were parsed with, or by a gen.PositionContext.
+//
+package ir
+
+import "github.com/gqlc/graphql/token"
+
+// Schema is the types and directives declared by a set of documents.
+type Schema struct {
+	types []*Named
+	names map[string]*Named
+	dirs  []*DirectiveDef
+
+	query        *Named
+	mutation     *Named
+	subscription *Named
+
+	refs map[*Named][]Ref
+	uses map[*Named][]*Named
+}
+
+// Ref is a reference to a type, from the definition of another.
+type Ref struct {
+	// From is the type referring to the type.
+	From *Named
+
+	// Field is the field of From whose type, or argument, is the type.
+	// It's nil for references by the fields of input types, interfaces
+	// and union members.
+	//
+	Field *Field
+
+	// Arg is the argument of Field, or the field of an input type, whose
+	// type is the type.
+	//
+	Arg *InputValue
+}
+
+// Types returns the types declared by the documents, in the order they're
+// declared. The built-in scalars are left out, unless they're declared.
+//
+func (s *Schema) Types() []*Named { return s.types }
+
+// Lookup returns the type with the given name, or nil if there's none.
+// The built-in scalars can always be looked up.
+//
+func (s *Schema) Lookup(name string) *Named {
+	if t, ok := s.names[name]; ok {
+		return t
+	}
+	if isBuiltin(name) {
+		return builtins[name]
+	}
+	return nil
+}
+
+// Directives returns the directives declared by the documents, in the
+// order they're declared.
+//
+func (s *Schema) Directives() []*DirectiveDef { return s.dirs }
+
+// Directive returns the declaration of the directive with the given name,
+// or nil if there's none.
+//
+func (s *Schema) Directive(name string) *DirectiveDef {
+	for _, d := range s.dirs {
+		if d.name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// Query returns the root query type, or nil if there's none.
+func (s *Schema) Query() *Named { return s.query }
+
+// Mutation returns the root mutation type, or nil if there's none.
+func (s *Schema) Mutation() *Named { return s.mutation }
+
+// Subscription returns the root subscription type, or nil if there's none.
+func (s *Schema) Subscription() *Named { return s.subscription }
+
+// IsRoot reports whether a type is one of the root operation types.
+func (s *Schema) IsRoot(t *Named) bool {
+	return t != nil && (t == s.query || t == s.mutation || t == s.subscription)
+}
+
+// Refs returns the references to a type from the other types, in the order
+// they're declared.
+//
+func (s *Schema) Refs(t *Named) []Ref { return s.refs[t] }
+
+// Uses returns the types a type refers to, by its fields, arguments,
+// interfaces or members, in the order they're first referred to.
+//
+func (s *Schema) Uses(t *Named) []*Named { return s.uses[t] }
+
+// Reachable returns the declared types which can be reached from the given
+// types by following their references, including the types themselves, in
+// the order they're declared. Without any types, the root operation types
+// are started from.
+//
+func (s *Schema) Reachable(from ...*Named) []*Named {
+	if len(from) == 0 {
+		for _, root := range []*Named{s.query, s.mutation, s.subscription} {
+			if root != nil {
+				from = append(from, root)
+			}
+		}
+	}
+
+	seen := make(map[*Named]bool)
+	for len(from) > 0 {
+		t := from[len(from)-1]
+		from = from[:len(from)-1]
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		from = append(from, s.uses[t]...)
+	}
+
+	var reached []*Named
+	for _, t := range s.types {
+		if seen[t] {
+			reached = append(reached, t)
+		}
+	}
+	return reached
+}
+
+// builtins are the scalars every schema has.
+var builtins = map[string]*Named{
+	"Int":     {name: "Int", kind: Scalar, pos: token.NoPos},
+	"Float":   {name: "Float", kind: Scalar, pos: token.NoPos},
+	"String":  {name: "String", kind: Scalar, pos: token.NoPos},
+	"Boolean": {name: "Boolean", kind: Scalar, pos: token.NoPos},
+	"ID":      {name: "ID", kind: Scalar, pos: token.NoPos},
+}
+
+func isBuiltin(name string) bool {
+	_, ok := builtins[name]
+	return ok
+}
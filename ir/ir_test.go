@@ -0,0 +1,282 @@
+package ir
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+const schemaSrc = `schema {
+	query: Root
+}
+
+"Root of the queries."
+type Root {
+	"Users, by their role."
+	users(role: Role = ADMIN, first: Int = 10): [User!]! @cost(weight: 2)
+	node(id: ID!): Node
+}
+
+directive @cost(weight: Int) on FIELD_DEFINITION | OBJECT
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node @cost(weight: 1) {
+	id: ID!
+	name: String @deprecated(reason: "Use fullName.")
+	profile: Profile
+}
+
+enum Role {
+	ADMIN
+	USER @deprecated
+}
+
+union Result = User | Missing
+
+input Filter {
+	roles: [Role] = [ADMIN, USER]
+	nested: Filter
+}
+
+extend type User {
+	fullName: String
+}
+
+scalar Orphan
+`
+
+func parse(t *testing.T, name, src string) *ast.Document {
+	doc, err := parser.ParseDoc(token.NewDocSet(), name, strings.NewReader(src), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestLoad(t *testing.T) {
+	s, err := Load(parse(t, "schema.gql", schemaSrc))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("Types", func(subT *testing.T) {
+		var names []string
+		for _, typ := range s.Types() {
+			names = append(names, typ.Name()+":"+typ.Kind().String())
+		}
+
+		ex := "Root:object Node:interface User:object Role:enum Result:union Filter:input Orphan:scalar"
+		if got := strings.Join(names, " "); got != ex {
+			subT.Errorf("expected types: %s, but got: %s", ex, got)
+		}
+	})
+
+	t.Run("Fields", func(subT *testing.T) {
+		root := s.Lookup("Root")
+		if root.Description() != "Root of the queries." || root.Document() != "schema.gql" || !root.Pos().IsValid() {
+			subT.Errorf("unexpected type: %q %q %v", root.Description(), root.Document(), root.Pos())
+			return
+		}
+
+		users := root.Field("users")
+		if users.Type().String() != "[User!]!" || users.Description() != "Users, by their role." || users.Parent() != root {
+			subT.Errorf("unexpected field: %s %q", users.Type(), users.Description())
+		}
+		if Unwrap(users.Type()) != s.Lookup("User") || !IsList(users.Type()) || IsNullable(users.Type()) {
+			subT.Errorf("expected the field's type to be a non-null list of User")
+		}
+
+		args := users.Args()
+		if len(args) != 2 || args[0].Default() != "ADMIN" || args[1].Type() != s.Lookup("Int") || !s.Lookup("Int").Builtin() {
+			subT.Errorf("unexpected arguments: %v", args)
+		}
+
+		dirs := users.Directives()
+		if len(dirs) != 1 || dirs[0].Def() != s.Directive("cost") {
+			subT.Errorf("expected @cost to be resolved to its declaration")
+			return
+		}
+		if v, ok := dirs[0].Arg("weight"); !ok || v != "2" {
+			subT.Errorf("expected weight: 2, but got: %q", v)
+		}
+	})
+
+	t.Run("Extensions", func(subT *testing.T) {
+		user := s.Lookup("User")
+		var names []string
+		for _, f := range user.Fields() {
+			names = append(names, f.Name())
+		}
+		if got := strings.Join(names, " "); got != "id name profile fullName" {
+			subT.Errorf("expected the extension's fields to be merged, but got: %s", got)
+		}
+		if len(user.Interfaces()) != 1 || user.Interfaces()[0] != s.Lookup("Node") {
+			subT.Errorf("expected User to implement Node")
+		}
+	})
+
+	t.Run("Deprecated", func(subT *testing.T) {
+		if reason, ok := s.Lookup("User").Field("name").Deprecated(); !ok || reason != "Use fullName." {
+			subT.Errorf("expected the field to be deprecated, but got: %q %v", reason, ok)
+		}
+		if reason, ok := s.Lookup("Role").Values()[1].Deprecated(); !ok || reason != "No longer supported" {
+			subT.Errorf("expected the default reason, but got: %q %v", reason, ok)
+		}
+		if _, ok := s.Lookup("User").Field("id").Deprecated(); ok {
+			subT.Error("expected the field not to be deprecated")
+		}
+	})
+
+	t.Run("Unresolved", func(subT *testing.T) {
+		missing := s.Lookup("Result").Members()[1]
+		if missing.Kind() != Invalid || missing.Pos().IsValid() || s.Lookup("Missing") != nil {
+			subT.Errorf("expected Missing to be unresolved")
+		}
+	})
+
+	t.Run("Roots", func(subT *testing.T) {
+		if s.Query() != s.Lookup("Root") || s.Mutation() != nil || !s.IsRoot(s.Lookup("Root")) {
+			subT.Error("expected Root to be the query type")
+		}
+	})
+
+	t.Run("Directives", func(subT *testing.T) {
+		d := s.Directive("cost")
+		if d == nil || len(d.Args()) != 1 || strings.Join(d.Locations(), " ") != "FIELD_DEFINITION OBJECT" {
+			subT.Errorf("unexpected directive: %v", d)
+		}
+	})
+
+	t.Run("Input", func(subT *testing.T) {
+		fields := s.Lookup("Filter").InputFields()
+		if len(fields) != 2 || fields[0].Default() != "[ADMIN, USER]" || fields[1].Type() != s.Lookup("Filter") {
+			subT.Errorf("unexpected input fields: %v", fields)
+		}
+	})
+}
+
+func TestLoad_Documents(t *testing.T) {
+	a := parse(t, "a.gql", "type A {\n\tb: B\n}\n")
+	b := parse(t, "b.gql", "type B {\n\ta: A\n}\n\ntype A {\n\tc: String\n}\n")
+
+	s, err := Load(a, b)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if s.Lookup("A").Field("b").Type() != s.Lookup("B") || s.Lookup("B").Document() != "b.gql" {
+		t.Error("expected references to be resolved across documents")
+	}
+	if len(s.Types()) != 2 || s.Lookup("A").Document() != "a.gql" {
+		t.Error("expected the first declaration of A to be kept")
+	}
+	if s.Query() != nil {
+		t.Error("expected no query type")
+	}
+}
+
+func TestLoad_Malformed(t *testing.T) {
+	doc := &ast.Document{Name: "bad.gql", Types: []*ast.TypeDecl{{}}}
+	_, err := Load(doc)
+	if err == nil || !strings.HasPrefix(err.Error(), "ir: bad.gql: ") {
+		t.Errorf("expected a shape error, but got: %v", err)
+	}
+}
+
+func TestRefs(t *testing.T) {
+	s, err := Load(parse(t, "schema.gql", schemaSrc))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	testCases := []struct {
+		Name string
+		Refs []string
+	}{
+		{Name: "User", Refs: []string{"Root.users", "Result"}},
+		{Name: "Role", Refs: []string{"Root.users(role)", "Filter.roles"}},
+		{Name: "Node", Refs: []string{"Root.node", "User"}},
+		{Name: "Filter", Refs: []string{"Filter.nested"}},
+		{Name: "Orphan"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			var refs []string
+			for _, r := range s.Refs(s.Lookup(testCase.Name)) {
+				ref := r.From.Name()
+				switch {
+				case r.Field != nil && r.Arg != nil:
+					ref += "." + r.Field.Name() + "(" + r.Arg.Name() + ")"
+				case r.Field != nil:
+					ref += "." + r.Field.Name()
+				case r.Arg != nil:
+					ref += "." + r.Arg.Name()
+				}
+				refs = append(refs, ref)
+			}
+
+			if fmt.Sprint(refs) != fmt.Sprint(testCase.Refs) {
+				subT.Errorf("expected refs: %v, but got: %v", testCase.Refs, refs)
+			}
+		})
+	}
+
+	t.Run("Uses", func(subT *testing.T) {
+		var names []string
+		for _, u := range s.Uses(s.Lookup("Root")) {
+			names = append(names, u.Name())
+		}
+		if got := strings.Join(names, " "); got != "User Role Int Node ID" {
+			subT.Errorf("unexpected uses: %s", got)
+		}
+	})
+
+	t.Run("Reachable", func(subT *testing.T) {
+		var names []string
+		for _, typ := range s.Reachable() {
+			names = append(names, typ.Name())
+		}
+		if got := strings.Join(names, " "); got != "Root Node User Role" {
+			subT.Errorf("unexpected reachable types: %s", got)
+		}
+	})
+}
+
+func ExampleLoad() {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(`type Query {
+	user(id: ID!): User
+}
+
+type User {
+	friends: [User!]
+}`), 0)
+	if err != nil {
+		return
+	}
+
+	s, err := Load(doc)
+	if err != nil {
+		return
+	}
+
+	for _, t := range s.Types() {
+		for _, f := range t.Fields() {
+			fmt.Println(t.Name(), f.Name(), f.Type(), Unwrap(f.Type()).Kind())
+		}
+	}
+
+	// Output:
+	// Query user User object
+	// User friends [User!] object
+}
@@ -0,0 +1,473 @@
+package ir
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+// Load loads the schema declared by the given documents, which are checked
+// to be well formed first. A type declared by more than one of them is the
+// one declared first, and types which aren't declared by any of them, e.g.
+// those imported from another document, are of kind Invalid.
+//
+func Load(docs ...*ast.Document) (*Schema, error) {
+	l := &loader{
+		s: &Schema{
+			names: make(map[string]*Named),
+			refs:  make(map[*Named][]Ref),
+			uses:  make(map[*Named][]*Named),
+		},
+		specs:      make(map[*Named]*ast.TypeDecl),
+		dirSpecs:   make(map[*DirectiveDef]*ast.TypeSpec),
+		unresolved: make(map[string]*Named),
+	}
+
+	for _, doc := range docs {
+		if err := gen.ValidateShape(doc); err != nil {
+			return nil, fmt.Errorf("ir: %s: %s", doc.Name, err)
+		}
+		l.declare(doc)
+	}
+
+	for _, t := range l.s.types {
+		l.define(t, l.specs[t].Spec.(*ast.TypeDecl_TypeSpec).TypeSpec)
+	}
+	for _, d := range l.s.dirs {
+		l.defineDirective(d, l.dirSpecs[d])
+	}
+	for _, ext := range l.exts {
+		l.extend(ext)
+	}
+	l.roots()
+	l.link()
+	return l.s, nil
+}
+
+// loader loads a Schema, by declaring every type and directive before
+// defining them, so references to them can be resolved in any order.
+//
+type loader struct {
+	s *Schema
+
+	specs    map[*Named]*ast.TypeDecl
+	dirSpecs map[*DirectiveDef]*ast.TypeSpec
+	exts     []extension
+	schemas  []*ast.TypeSpec
+
+	unresolved map[string]*Named
+}
+
+type extension struct {
+	doc  string
+	spec *ast.TypeSpec
+}
+
+func (l *loader) declare(doc *ast.Document) {
+	decls := doc.Types
+	if doc.Schema != nil && !containsDecl(decls, doc.Schema) {
+		decls = append([]*ast.TypeDecl{doc.Schema}, decls...)
+	}
+
+	for _, decl := range decls {
+		switch s := decl.Spec.(type) {
+		case *ast.TypeDecl_TypeExtSpec:
+			l.exts = append(l.exts, extension{doc: doc.Name, spec: s.TypeExtSpec.Type})
+		case *ast.TypeDecl_TypeSpec:
+			ts := s.TypeSpec
+			switch ts.Type.(type) {
+			case *ast.TypeSpec_Schema:
+				l.schemas = append(l.schemas, ts)
+				continue
+			case *ast.TypeSpec_Directive:
+				d := &DirectiveDef{
+					name:  ts.Name.Name,
+					descr: description(decl.Doc),
+					pos:   token.Pos(ts.Name.NamePos),
+					doc:   doc.Name,
+				}
+				l.dirSpecs[d] = ts
+				l.s.dirs = append(l.s.dirs, d)
+				continue
+			}
+
+			if _, ok := l.s.names[ts.Name.Name]; ok {
+				continue
+			}
+
+			t := &Named{
+				name:  ts.Name.Name,
+				kind:  kindOf(ts),
+				descr: description(decl.Doc),
+				pos:   token.Pos(ts.Name.NamePos),
+				doc:   doc.Name,
+			}
+			l.specs[t] = decl
+			l.s.names[t.name] = t
+			l.s.types = append(l.s.types, t)
+		}
+	}
+}
+
+func containsDecl(decls []*ast.TypeDecl, decl *ast.TypeDecl) bool {
+	for _, d := range decls {
+		if d == decl {
+			return true
+		}
+	}
+	return false
+}
+
+func kindOf(ts *ast.TypeSpec) Kind {
+	switch ts.Type.(type) {
+	case *ast.TypeSpec_Scalar:
+		return Scalar
+	case *ast.TypeSpec_Object:
+		return Object
+	case *ast.TypeSpec_Interface:
+		return Interface
+	case *ast.TypeSpec_Union:
+		return Union
+	case *ast.TypeSpec_Enum:
+		return Enum
+	case *ast.TypeSpec_Input:
+		return Input
+	}
+	return Invalid
+}
+
+// define defines a type by its declaration, or merges an extension of it.
+func (l *loader) define(t *Named, ts *ast.TypeSpec) {
+	t.dirs = append(t.dirs, l.directives(ts.Directives)...)
+
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Object:
+		for _, i := range v.Object.Interfaces {
+			t.interfaces = append(t.interfaces, l.resolve(i.Name))
+		}
+		t.fields = append(t.fields, l.fields(t, v.Object.Fields)...)
+	case *ast.TypeSpec_Interface:
+		t.fields = append(t.fields, l.fields(t, v.Interface.Fields)...)
+	case *ast.TypeSpec_Union:
+		for _, m := range v.Union.Members {
+			t.members = append(t.members, l.resolve(m.Name))
+		}
+	case *ast.TypeSpec_Enum:
+		if v.Enum.Values == nil {
+			break
+		}
+
+		for _, f := range v.Enum.Values.List {
+			t.values = append(t.values, &EnumValue{
+				name:  f.Name.Name,
+				descr: description(f.Doc),
+				pos:   token.Pos(f.Name.NamePos),
+				dirs:  l.directives(f.Directives),
+			})
+		}
+	case *ast.TypeSpec_Input:
+		t.inputFields = append(t.inputFields, l.inputValues(v.Input.Fields)...)
+	}
+}
+
+// extend merges an extension into the type it extends, which is declared
+// by it if no document declares the type.
+//
+func (l *loader) extend(ext extension) {
+	ts := ext.spec
+	t, ok := l.s.names[ts.Name.Name]
+	if !ok {
+		t = &Named{
+			name: ts.Name.Name,
+			kind: kindOf(ts),
+			pos:  token.Pos(ts.Name.NamePos),
+			doc:  ext.doc,
+		}
+		l.s.names[t.name] = t
+		l.s.types = append(l.s.types, t)
+	}
+	l.define(t, ts)
+}
+
+func (l *loader) defineDirective(d *DirectiveDef, ts *ast.TypeSpec) {
+	dt := ts.Type.(*ast.TypeSpec_Directive).Directive
+	d.args = l.inputValues(dt.Args)
+	for _, loc := range dt.Locs {
+		d.locs = append(d.locs, loc.Loc.String())
+	}
+}
+
+func (l *loader) fields(parent *Named, fields *ast.FieldList) (fs []*Field) {
+	if fields == nil {
+		return
+	}
+
+	for _, f := range fields.List {
+		fs = append(fs, &Field{
+			name:   f.Name.Name,
+			descr:  description(f.Doc),
+			pos:    token.Pos(f.Name.NamePos),
+			typ:    l.typeOf(fieldType(f)),
+			args:   l.inputValues(f.Args),
+			dirs:   l.directives(f.Directives),
+			parent: parent,
+		})
+	}
+	return
+}
+
+func (l *loader) inputValues(vals *ast.InputValueList) (vs []*InputValue) {
+	if vals == nil {
+		return
+	}
+
+	for _, a := range vals.List {
+		v := &InputValue{
+			name:  a.Name.Name,
+			descr: description(a.Doc),
+			pos:   token.Pos(a.Name.NamePos),
+			typ:   l.typeOf(inputValueType(a)),
+			dirs:  l.directives(a.Directives),
+		}
+		switch d := a.Default.(type) {
+		case *ast.InputValue_BasicLit:
+			v.def = valueString(d.BasicLit)
+		case *ast.InputValue_CompositeLit:
+			v.def = valueString(d.CompositeLit)
+		}
+		vs = append(vs, v)
+	}
+	return
+}
+
+func (l *loader) directives(lits []*ast.DirectiveLit) (dirs []*Directive) {
+	for _, lit := range lits {
+		d := &Directive{
+			name: lit.Name,
+			pos:  token.Pos(lit.AtPos),
+			def:  l.s.Directive(lit.Name),
+		}
+		if lit.Args != nil {
+			for _, a := range lit.Args.Args {
+				arg := &Argument{Name: a.Name.Name}
+				switch v := a.Value.(type) {
+				case *ast.Arg_BasicLit:
+					arg.Value = valueString(v.BasicLit)
+				case *ast.Arg_CompositeLit:
+					arg.Value = valueString(v.CompositeLit)
+				}
+				d.args = append(d.args, arg)
+			}
+		}
+		dirs = append(dirs, d)
+	}
+	return
+}
+
+// resolve returns the type with the given name, which is of kind Invalid
+// if no document declares it.
+//
+func (l *loader) resolve(name string) *Named {
+	if t := l.s.Lookup(name); t != nil {
+		return t
+	}
+
+	t, ok := l.unresolved[name]
+	if !ok {
+		t = &Named{name: name, kind: Invalid}
+		l.unresolved[name] = t
+	}
+	return t
+}
+
+// typeOf converts the type of a field or input value, i.e. an *ast.Ident,
+// *ast.List or *ast.NonNull.
+//
+func (l *loader) typeOf(typ interface{}) Type {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		return l.resolve(v.Name)
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			return &List{Elem: l.typeOf(w.Ident)}
+		case *ast.List_List:
+			return &List{Elem: l.typeOf(w.List)}
+		case *ast.List_NonNull:
+			return &List{Elem: l.typeOf(w.NonNull)}
+		}
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return &NonNull{Elem: l.typeOf(w.Ident)}
+		case *ast.NonNull_List:
+			return &NonNull{Elem: l.typeOf(w.List)}
+		}
+	}
+	return nil
+}
+
+// roots sets the root operation types, by the first schema declared, or
+// by their conventional names if there's none.
+//
+func (l *loader) roots() {
+	if len(l.schemas) == 0 {
+		l.s.query = l.object("Query")
+		l.s.mutation = l.object("Mutation")
+		l.s.subscription = l.object("Subscription")
+		return
+	}
+
+	schema := l.schemas[0].Type.(*ast.TypeSpec_Schema).Schema
+	if schema.RootOps == nil {
+		return
+	}
+
+	for _, f := range schema.RootOps.List {
+		t := l.resolve(Unwrap(l.typeOf(fieldType(f))).name)
+		switch f.Name.Name {
+		case "query":
+			l.s.query = t
+		case "mutation":
+			l.s.mutation = t
+		case "subscription":
+			l.s.subscription = t
+		}
+	}
+}
+
+func (l *loader) object(name string) *Named {
+	t := l.s.names[name]
+	if t == nil || t.kind != Object {
+		return nil
+	}
+	return t
+}
+
+// link builds the reference graph between the declared types.
+func (l *loader) link() {
+	for _, t := range l.s.types {
+		for _, i := range t.interfaces {
+			l.ref(i, Ref{From: t})
+		}
+		for _, m := range t.members {
+			l.ref(m, Ref{From: t})
+		}
+		for _, f := range t.fields {
+			l.ref(Unwrap(f.typ), Ref{From: t, Field: f})
+			for _, a := range f.args {
+				l.ref(Unwrap(a.typ), Ref{From: t, Field: f, Arg: a})
+			}
+		}
+		for _, f := range t.inputFields {
+			l.ref(Unwrap(f.typ), Ref{From: t, Arg: f})
+		}
+	}
+}
+
+func (l *loader) ref(t *Named, r Ref) {
+	if t == nil {
+		return
+	}
+	l.s.refs[t] = append(l.s.refs[t], r)
+
+	for _, u := range l.s.uses[r.From] {
+		if u == t {
+			return
+		}
+	}
+	l.s.uses[r.From] = append(l.s.uses[r.From], t)
+}
+
+func description(doc *ast.DocGroup) string {
+	if doc == nil {
+		return ""
+	}
+	return strings.TrimSpace(doc.Text())
+}
+
+func fieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func inputValueType(a *ast.InputValue) interface{} {
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+// valueString returns the GraphQL syntax of a value.
+func valueString(val interface{}) string {
+	var b strings.Builder
+	writeVal(&b, val)
+	return b.String()
+}
+
+func writeVal(b *strings.Builder, val interface{}) {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		b.WriteString(v.Value)
+	case *ast.ListLit:
+		b.WriteByte('[')
+
+		var vals []interface{}
+		switch w := v.List.(type) {
+		case *ast.ListLit_BasicList:
+			for _, bval := range w.BasicList.Values {
+				vals = append(vals, bval)
+			}
+		case *ast.ListLit_CompositeList:
+			for _, cval := range w.CompositeList.Values {
+				vals = append(vals, cval)
+			}
+		}
+
+		for i, iv := range vals {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeVal(b, iv)
+		}
+
+		b.WriteByte(']')
+	case *ast.ObjLit:
+		b.WriteString("{ ")
+		for i, p := range v.Fields {
+			b.WriteString(p.Key.Name)
+			b.WriteString(": ")
+			writeVal(b, p.Val)
+
+			if i != len(v.Fields)-1 {
+				b.WriteByte(',')
+			}
+			b.WriteByte(' ')
+		}
+		b.WriteByte('}')
+	case *ast.CompositeLit:
+		switch w := v.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			writeVal(b, w.BasicLit)
+		case *ast.CompositeLit_ListLit:
+			writeVal(b, w.ListLit)
+		case *ast.CompositeLit_ObjLit:
+			writeVal(b, w.ObjLit)
+		}
+	}
+}
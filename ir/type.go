@@ -0,0 +1,177 @@
+package ir
+
+import (
+	"strconv"
+
+	"github.com/gqlc/graphql/token"
+)
+
+// Kind is the kind of a named type.
+type Kind int
+
+// Kinds of named types. Invalid is the kind of types which are referred to,
+// but declared by none of the documents loaded.
+//
+const (
+	Invalid Kind = iota
+	Scalar
+	Object
+	Interface
+	Union
+	Enum
+	Input
+)
+
+var kinds = [...]string{
+	Invalid:   "invalid",
+	Scalar:    "scalar",
+	Object:    "object",
+	Interface: "interface",
+	Union:     "union",
+	Enum:      "enum",
+	Input:     "input",
+}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kinds) {
+		return "Kind(" + strconv.Itoa(int(k)) + ")"
+	}
+	return kinds[k]
+}
+
+// Type is the type of a field, argument or input field: a named type, or a
+// list or non-null type wrapping one. Its String is its GraphQL syntax,
+// e.g. [User!]!.
+//
+type Type interface {
+	String() string
+
+	// Only the types of this package are Types
+	isType()
+}
+
+// List is a list of Elem.
+type List struct {
+	Elem Type
+}
+
+func (t *List) String() string { return "[" + t.Elem.String() + "]" }
+func (*List) isType()          {}
+
+// NonNull is Elem, which can't be null.
+type NonNull struct {
+	Elem Type
+}
+
+func (t *NonNull) String() string { return t.Elem.String() + "!" }
+func (*NonNull) isType()          {}
+
+// Unwrap returns the named type at the bottom of a type, e.g. User for
+// [User!]!.
+//
+func Unwrap(t Type) *Named {
+	for {
+		switch v := t.(type) {
+		case *Named:
+			return v
+		case *List:
+			t = v.Elem
+		case *NonNull:
+			t = v.Elem
+		default:
+			return nil
+		}
+	}
+}
+
+// IsList reports whether a type is a list, whether or not it's null.
+func IsList(t Type) bool {
+	if nn, ok := t.(*NonNull); ok {
+		t = nn.Elem
+	}
+	_, ok := t.(*List)
+	return ok
+}
+
+// IsNullable reports whether a type can be null.
+func IsNullable(t Type) bool {
+	_, ok := t.(*NonNull)
+	return !ok
+}
+
+// Named is a named type: a scalar, object, interface, union, enum or input
+// type. Its extensions are merged into it.
+//
+type Named struct {
+	name  string
+	kind  Kind
+	descr string
+	pos   token.Pos
+	doc   string
+	dirs  []*Directive
+
+	fields      []*Field
+	inputFields []*InputValue
+	interfaces  []*Named
+	members     []*Named
+	values      []*EnumValue
+}
+
+func (t *Named) String() string { return t.name }
+func (*Named) isType()          {}
+
+// Name returns the name of the type.
+func (t *Named) Name() string { return t.name }
+
+// Kind returns the kind of the type.
+func (t *Named) Kind() Kind { return t.kind }
+
+// Description returns the description of the type, without surrounding
+// whitespace.
+//
+func (t *Named) Description() string { return t.descr }
+
+// Pos returns where the type is declared, which is token.NoPos for the
+// built-in scalars and types which aren't declared.
+//
+func (t *Named) Pos() token.Pos { return t.pos }
+
+// Document returns the name of the document declaring the type.
+func (t *Named) Document() string { return t.doc }
+
+// Builtin reports whether the type is one of the scalars every schema has,
+// e.g. String, without being declared.
+//
+func (t *Named) Builtin() bool { return t.kind == Scalar && t.doc == "" }
+
+// Directives returns the directives applied to the type, and its
+// extensions.
+//
+func (t *Named) Directives() []*Directive { return t.dirs }
+
+// Fields returns the fields of an object or interface type.
+func (t *Named) Fields() []*Field { return t.fields }
+
+// Field returns the field of an object or interface type with the given
+// name, or nil if it has none.
+//
+func (t *Named) Field(name string) *Field {
+	for _, f := range t.fields {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// InputFields returns the fields of an input type.
+func (t *Named) InputFields() []*InputValue { return t.inputFields }
+
+// Interfaces returns the interfaces an object type implements.
+func (t *Named) Interfaces() []*Named { return t.interfaces }
+
+// Members returns the possible types of a union.
+func (t *Named) Members() []*Named { return t.members }
+
+// Values returns the values of an enum.
+func (t *Named) Values() []*EnumValue { return t.values }
@@ -0,0 +1,214 @@
+package com.example.graphql;
+
+import graphql.schema.Coercing;
+import graphql.schema.GraphQLScalarType;
+import graphql.schema.idl.RuntimeWiring;
+import graphql.schema.idl.TypeRuntimeWiring;
+import java.util.List;
+
+public final class Test {
+    private Test() {}
+
+    /** Version represents an API version. */
+    public static final GraphQLScalarType Version = GraphQLScalarType.newScalar()
+            .name("Version")
+            .description("Version represents an API version.")
+            .coercing(new Coercing<Object, Object>() {
+                @Override
+                public Object serialize(Object dataFetcherResult) {
+                    // TODO
+                    return null;
+                }
+
+                @Override
+                public Object parseValue(Object input) {
+                    // TODO
+                    return null;
+                }
+
+                @Override
+                public Object parseLiteral(Object input) {
+                    // TODO
+                    return null;
+                }
+            })
+            .build();
+
+    /** Echo represents an echo message. */
+    public static class Echo implements SearchResult {
+        /** msg contains the provided message. */
+        private String msg;
+
+        public String getMsg() {
+            return msg;
+        }
+
+        public void setMsg(String msg) {
+            this.msg = msg;
+        }
+    }
+
+    /** Result represents a search result. */
+    public static class Result implements Connection, SearchResult {
+        /** total yields the total number of search results. */
+        private Integer total;
+        /** edges contains the search results. */
+        private List<Node> edges;
+        /** hasNextPage tells if there are more search results. */
+        private Boolean hasNextPage;
+
+        public Integer getTotal() {
+            return total;
+        }
+
+        public void setTotal(Integer total) {
+            this.total = total;
+        }
+
+        public List<Node> getEdges() {
+            return edges;
+        }
+
+        public void setEdges(List<Node> edges) {
+            this.edges = edges;
+        }
+
+        public Boolean getHasNextPage() {
+            return hasNextPage;
+        }
+
+        public void setHasNextPage(Boolean hasNextPage) {
+            this.hasNextPage = hasNextPage;
+        }
+    }
+
+    /** Connection represents a set of edges, which are meant to be paginated. */
+    public interface Connection {
+        /** total returns the total number of edges. */
+        Integer getTotal();
+
+        /** edges contains the current page of edges. */
+        List<Node> getEdges();
+
+        /** hasNextPage tells if there exists more edges. */
+        Boolean getHasNextPage();
+    }
+
+    /** Node represents a node. */
+    public interface Node {
+        /** id uniquely identifies the node. */
+        String getId();
+    }
+
+    /** SearchResult is a test union type */
+    public interface SearchResult {}
+
+    /** Direction represents a cardinal direction. */
+    public enum Direction {
+        /** EnumValue description */
+        NORTH,
+        EAST,
+        SOUTH,
+        /** EnumValue Description and Directives. */
+        WEST
+    }
+
+    /** Point represents a 2-D geo point. */
+    public static class Point {
+        private Double x;
+        private Double y;
+        private String label = "origin";
+        private Boolean visible = true;
+        private Direction heading = Direction.NORTH;
+        private List<Double> weights = java.util.Arrays.asList(1.0, 2.5);
+
+        public Double getX() {
+            return x;
+        }
+
+        public void setX(Double x) {
+            this.x = x;
+        }
+
+        public Double getY() {
+            return y;
+        }
+
+        public void setY(Double y) {
+            this.y = y;
+        }
+
+        public String getLabel() {
+            return label;
+        }
+
+        public void setLabel(String label) {
+            this.label = label;
+        }
+
+        public Boolean getVisible() {
+            return visible;
+        }
+
+        public void setVisible(Boolean visible) {
+            this.visible = visible;
+        }
+
+        public Direction getHeading() {
+            return heading;
+        }
+
+        public void setHeading(Direction heading) {
+            this.heading = heading;
+        }
+
+        public List<Double> getWeights() {
+            return weights;
+        }
+
+        public void setWeights(List<Double> weights) {
+            this.weights = weights;
+        }
+    }
+
+    /**
+     * newRuntimeWiring returns a RuntimeWiring builder which wires up
+     * every scalar, data fetcher and type resolver of the schema.
+     */
+    public static RuntimeWiring.Builder newRuntimeWiring() {
+        return RuntimeWiring.newRuntimeWiring()
+                .scalar(Version)
+                .type(TypeRuntimeWiring.newTypeWiring("Query")
+                        .dataFetcher("version", env -> {
+                            // TODO
+                            return null;
+                        })
+                        .dataFetcher("echo", env -> {
+                            // TODO
+                            return null;
+                        })
+                        .dataFetcher("search", env -> {
+                            // TODO
+                            return null;
+                        })
+                )
+                .type(TypeRuntimeWiring.newTypeWiring("Connection")
+                        .typeResolver(env -> {
+                            // TODO
+                            return null;
+                        })
+                )
+                .type(TypeRuntimeWiring.newTypeWiring("Node")
+                        .typeResolver(env -> {
+                            // TODO
+                            return null;
+                        })
+                )
+                .type(TypeRuntimeWiring.newTypeWiring("SearchResult")
+                        .typeResolver(env -> {
+                            // TODO
+                            return null;
+                        })
+                );
+    }
+}
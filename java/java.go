@@ -0,0 +1,819 @@
+// Package java contains a Java generator for GraphQL Documents.
+// The generated code consists of POJOs along with a RuntimeWiring
+// skeleton for the graphql-java library.
+//
+package java
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/gen/printer"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+// Options contains the options for the Java generator.
+type Options struct {
+	// Package is the Java package of the generated class
+	Package string
+
+	// Copy descriptions to Javadoc comments (default: true)
+	Descriptions bool
+}
+
+// Generator generates Java code for a GraphQL schema.
+type Generator struct {
+	printer.Printer
+
+	log *zap.Logger
+
+	// unions maps a type to the unions it's a member of
+	unions map[string][]string
+
+	// scalars contains the custom scalars, which have no Java class
+	scalars map[string]bool
+}
+
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Printer.Reset()
+	g.Indent = "    "
+	g.unions = make(map[string][]string)
+	g.scalars = make(map[string]bool)
+}
+
+// Generate generates Java code for the given document.
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "java",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("java").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	gOpts, oerr := getOptions(doc, opts)
+	if oerr != nil {
+		return oerr
+	}
+
+	// Java requires the file name to match its public class
+	// so every type is nested inside a single outer class.
+	//
+	className := outerClassName(doc)
+
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar:
+			g.scalars[ts.TypeSpec.Name.Name] = true
+		case *ast.TypeSpec_Union:
+			for _, mem := range v.Union.Members {
+				g.unions[mem.Name] = append(g.unions[mem.Name], ts.TypeSpec.Name.Name)
+			}
+		}
+	}
+
+	if gOpts.Package != "" {
+		g.P("package ", gOpts.Package, ";")
+		g.P()
+	}
+	g.P("import graphql.schema.Coercing;")
+	g.P("import graphql.schema.GraphQLScalarType;")
+	g.P("import graphql.schema.idl.RuntimeWiring;")
+	g.P("import graphql.schema.idl.TypeRuntimeWiring;")
+	g.P("import java.util.List;")
+	g.P()
+
+	g.P("public final class ", className, " {")
+	g.In()
+	g.P("private ", className, "() {}")
+
+	// Generate types
+	g.log.Info("generating types")
+	roots := rootTypes(doc)
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		name := ts.TypeSpec.Name.Name
+		if roots[name] {
+			continue
+		}
+
+		descr := d.Doc
+		if !gOpts.Descriptions {
+			descr = nil
+		}
+
+		switch ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar:
+			g.P()
+			g.generateScalar(name, descr, ts.TypeSpec)
+		case *ast.TypeSpec_Object:
+			g.P()
+			g.generateObject(name, descr, gOpts.Descriptions, ts.TypeSpec)
+		case *ast.TypeSpec_Interface:
+			g.P()
+			g.generateInterface(name, descr, gOpts.Descriptions, ts.TypeSpec)
+		case *ast.TypeSpec_Union:
+			g.P()
+			g.generateUnion(name, descr, ts.TypeSpec)
+		case *ast.TypeSpec_Enum:
+			g.P()
+			g.generateEnum(name, descr, gOpts.Descriptions, ts.TypeSpec)
+		case *ast.TypeSpec_Input:
+			g.P()
+			g.generateInput(name, descr, gOpts.Descriptions, ts.TypeSpec)
+		}
+	}
+
+	// Generate wiring
+	g.log.Info("generating runtime wiring")
+	g.P()
+	g.generateWiring(doc, roots)
+
+	g.Out()
+	g.P("}")
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	// Open file to write to
+	javaFile, err := gCtx.Open(filepath.Join(filepath.Dir(doc.Name), className+".java"))
+	if err != nil {
+		return
+	}
+	defer javaFile.Close()
+
+	// Write generated output
+	_, err = g.WriteTo(javaFile)
+	return
+}
+
+// outerClassName returns the name of the class enclosing all generated types.
+// It's derived from the document name and suffixed with Schema when that
+// would clash with a type name.
+//
+func outerClassName(doc *ast.Document) string {
+	base := filepath.Base(doc.Name)
+	name := pascalCase(base[:len(base)-len(filepath.Ext(base))])
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "Schema" + name
+	}
+
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if ok && ts.TypeSpec.Name != nil && ts.TypeSpec.Name.Name == name {
+			return name + "Schema"
+		}
+	}
+	return name
+}
+
+// rootTypes returns the names of the root operation types.
+func rootTypes(doc *ast.Document) map[string]bool {
+	roots := make(map[string]bool, 3)
+	if doc.Schema == nil {
+		roots["Query"] = true
+		roots["Mutation"] = true
+		roots["Subscription"] = true
+		return roots
+	}
+
+	schema := doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Schema).Schema
+	for _, f := range schema.RootOps.List {
+		roots[f.Type.(*ast.Field_Ident).Ident.Name] = true
+	}
+	return roots
+}
+
+func (g *Generator) generateScalar(name string, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	g.printJavadoc(doc)
+	g.P("public static final GraphQLScalarType ", name, " = GraphQLScalarType.newScalar()")
+	g.In()
+	g.In()
+	g.P(".name(", quote(name), ")")
+	if text := docText(doc); text != "" {
+		g.P(".description(", quote(text), ")")
+	}
+	g.P(".coercing(new Coercing<Object, Object>() {")
+	g.In()
+
+	for _, m := range []string{"serialize(Object dataFetcherResult)", "parseValue(Object input)", "parseLiteral(Object input)"} {
+		g.P("@Override")
+		g.P("public Object ", m, " {")
+		g.In()
+		g.P("// TODO")
+		g.P("return null;")
+		g.Out()
+		g.P("}")
+
+		if !strings.HasPrefix(m, "parseLiteral") {
+			g.P()
+		}
+	}
+
+	g.Out()
+	g.P("})")
+	g.P(".build();")
+	g.Out()
+	g.Out()
+}
+
+func (g *Generator) generateObject(name string, doc *ast.DocGroup, descr bool, ts *ast.TypeSpec) {
+	obj := ts.Type.(*ast.TypeSpec_Object).Object
+
+	var impls []string
+	for _, inter := range obj.Interfaces {
+		impls = append(impls, inter.Name)
+	}
+	impls = append(impls, g.unions[name]...)
+
+	g.printJavadoc(doc)
+	g.WriteIndent()
+	g.WriteString("public static class ")
+	g.WriteString(name)
+	if len(impls) > 0 {
+		g.WriteString(" implements ")
+		g.WriteString(strings.Join(impls, ", "))
+	}
+	g.WriteString(" {\n")
+	g.In()
+
+	var props []property
+	if obj.Fields != nil {
+		for _, f := range obj.Fields.List {
+			props = append(props, property{
+				name: f.Name.Name,
+				typ:  fieldType(f),
+				doc:  f.Doc,
+			})
+		}
+	}
+	g.generateProperties(props, descr)
+
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateInterface(name string, doc *ast.DocGroup, descr bool, ts *ast.TypeSpec) {
+	inter := ts.Type.(*ast.TypeSpec_Interface).Interface
+
+	g.printJavadoc(doc)
+	g.P("public interface ", name, " {")
+	g.In()
+
+	if inter.Fields != nil {
+		for i, f := range inter.Fields.List {
+			if i > 0 {
+				g.P()
+			}
+
+			if descr {
+				g.printJavadoc(f.Doc)
+			}
+			g.P(g.javaType(fieldType(f)), " ", getter(f.Name.Name), "();")
+		}
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateUnion(name string, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	g.printJavadoc(doc)
+	g.P("public interface ", name, " {}")
+}
+
+func (g *Generator) generateEnum(name string, doc *ast.DocGroup, descr bool, ts *ast.TypeSpec) {
+	enum := ts.Type.(*ast.TypeSpec_Enum).Enum
+
+	g.printJavadoc(doc)
+	g.P("public enum ", name, " {")
+	g.In()
+
+	var values []*ast.Field
+	if enum.Values != nil {
+		values = enum.Values.List
+	}
+	for i, v := range values {
+		if descr {
+			g.printJavadoc(v.Doc)
+		}
+
+		sep := ","
+		if i == len(values)-1 {
+			sep = ""
+		}
+		g.P(identifier(v.Name.Name), sep)
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateInput(name string, doc *ast.DocGroup, descr bool, ts *ast.TypeSpec) {
+	input := ts.Type.(*ast.TypeSpec_Input).Input
+
+	g.printJavadoc(doc)
+	g.P("public static class ", name, " {")
+	g.In()
+
+	var props []property
+	if input.Fields != nil {
+		for _, f := range input.Fields.List {
+			props = append(props, property{
+				name: f.Name.Name,
+				typ:  inputValueType(f),
+				doc:  f.Doc,
+				def:  f.Default,
+			})
+		}
+	}
+	g.generateProperties(props, descr)
+
+	g.Out()
+	g.P("}")
+}
+
+// property is a field of a generated POJO.
+type property struct {
+	name string
+	typ  interface{}
+	doc  *ast.DocGroup
+	def  interface{}
+}
+
+// generateProperties generates the private fields, along with their
+// getters and setters, for a POJO.
+//
+func (g *Generator) generateProperties(props []property, descr bool) {
+	for _, p := range props {
+		if descr {
+			g.printJavadoc(p.doc)
+		}
+
+		g.WriteIndent()
+		g.WriteString("private ")
+		g.WriteString(g.javaType(p.typ))
+		g.WriteByte(' ')
+		g.WriteString(identifier(p.name))
+		if p.def != nil {
+			g.WriteString(" = ")
+			g.printDefault(p.typ, p.def)
+		}
+		g.WriteString(";\n")
+	}
+
+	for _, p := range props {
+		typ, name := g.javaType(p.typ), identifier(p.name)
+
+		g.P()
+		g.P("public ", typ, " ", getter(p.name), "() {")
+		g.In()
+		g.P("return ", name, ";")
+		g.Out()
+		g.P("}")
+
+		g.P()
+		g.P("public void set", upperFirst(p.name), "(", typ, " ", name, ") {")
+		g.In()
+		g.P("this.", name, " = ", name, ";")
+		g.Out()
+		g.P("}")
+	}
+}
+
+// generateWiring generates a RuntimeWiring builder with data fetcher stubs
+// for all root fields and fields with arguments, along with type resolver
+// stubs for all interfaces and unions.
+//
+func (g *Generator) generateWiring(doc *ast.Document, roots map[string]bool) {
+	g.P("/**")
+	g.P(" * newRuntimeWiring returns a RuntimeWiring builder which wires up")
+	g.P(" * every scalar, data fetcher and type resolver of the schema.")
+	g.P(" */")
+	g.P("public static RuntimeWiring.Builder newRuntimeWiring() {")
+	g.In()
+	g.P("return RuntimeWiring.newRuntimeWiring()")
+	g.In()
+	g.In()
+
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar:
+			g.P(".scalar(", name, ")")
+		case *ast.TypeSpec_Object:
+			if v.Object.Fields == nil {
+				break
+			}
+
+			var fields []*ast.Field
+			for _, f := range v.Object.Fields.List {
+				if roots[name] || (f.Args != nil && len(f.Args.List) > 0) {
+					fields = append(fields, f)
+				}
+			}
+			if len(fields) == 0 {
+				break
+			}
+
+			g.P(".type(TypeRuntimeWiring.newTypeWiring(", quote(name), ")")
+			g.In()
+			g.In()
+			for _, f := range fields {
+				g.P(".dataFetcher(", quote(f.Name.Name), ", env -> {")
+				g.In()
+				g.P("// TODO")
+				g.P("return null;")
+				g.Out()
+				g.P("})")
+			}
+			g.Out()
+			g.Out()
+			g.P(")")
+		case *ast.TypeSpec_Interface, *ast.TypeSpec_Union:
+			g.P(".type(TypeRuntimeWiring.newTypeWiring(", quote(name), ")")
+			g.In()
+			g.In()
+			g.P(".typeResolver(env -> {")
+			g.In()
+			g.P("// TODO")
+			g.P("return null;")
+			g.Out()
+			g.P("})")
+			g.Out()
+			g.Out()
+			g.P(")")
+		}
+	}
+
+	// Terminate the builder chain
+	g.Truncate(g.Len() - 1)
+	g.WriteString(";\n")
+
+	g.Out()
+	g.Out()
+	g.Out()
+	g.P("}")
+}
+
+func fieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func inputValueType(a *ast.InputValue) interface{} {
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+var javaTypes = map[string]string{
+	"Int":     "Integer",
+	"Float":   "Double",
+	"String":  "String",
+	"Boolean": "Boolean",
+	"ID":      "String",
+}
+
+// javaType returns the Java type of a GraphQL type. Boxed types are
+// always used since nullability isn't tracked by Java and custom
+// scalars are left as Object.
+//
+func (g *Generator) javaType(typ interface{}) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		if t, ok := javaTypes[v.Name]; ok {
+			return t
+		}
+		if g.scalars[v.Name] {
+			return "Object"
+		}
+		return v.Name
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			typ = w.Ident
+		case *ast.List_List:
+			typ = w.List
+		case *ast.List_NonNull:
+			typ = w.NonNull
+		}
+		return "List<" + g.javaType(typ) + ">"
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			typ = w.Ident
+		case *ast.NonNull_List:
+			typ = w.List
+		}
+		return g.javaType(typ)
+	}
+	return "Object"
+}
+
+// namedType returns the name of the underlying named type.
+func namedType(typ interface{}) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		return v.Name
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			return w.Ident.Name
+		case *ast.List_List:
+			return namedType(w.List)
+		case *ast.List_NonNull:
+			return namedType(w.NonNull)
+		}
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return w.Ident.Name
+		case *ast.NonNull_List:
+			return namedType(w.List)
+		}
+	}
+	return ""
+}
+
+// printDefault prints the default value of an input field.
+func (g *Generator) printDefault(typ, def interface{}) {
+	var val interface{}
+	switch v := def.(type) {
+	case *ast.InputValue_BasicLit:
+		val = v.BasicLit
+	case *ast.InputValue_CompositeLit:
+		val = v.CompositeLit
+	}
+	g.printVal(namedType(typ), val)
+}
+
+// printVal prints a value of the given named type
+func (g *Generator) printVal(typ string, val interface{}) {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		switch v.Kind {
+		case token.Token_STRING:
+			s, err := strconv.Unquote(v.Value)
+			if err != nil {
+				s = strings.Trim(v.Value, `"`)
+			}
+			g.WriteString(quote(s))
+		case token.Token_IDENT:
+			g.WriteString(typ)
+			g.WriteByte('.')
+			g.WriteString(identifier(v.Value))
+		case token.Token_INT:
+			g.WriteString(v.Value)
+			if typ == "Float" {
+				g.WriteString(".0")
+			}
+		default:
+			g.WriteString(v.Value)
+		}
+	case *ast.ListLit:
+		g.printList(typ, v)
+	case *ast.ObjLit:
+		// Input objects have no literal syntax in Java
+		g.WriteString("null")
+	case *ast.CompositeLit:
+		switch w := v.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			g.printVal(typ, w.BasicLit)
+		case *ast.CompositeLit_ListLit:
+			g.printVal(typ, w.ListLit)
+		case *ast.CompositeLit_ObjLit:
+			g.printVal(typ, w.ObjLit)
+		}
+	}
+}
+
+func (g *Generator) printList(typ string, v *ast.ListLit) {
+	g.WriteString("java.util.Arrays.asList(")
+
+	var vals []interface{}
+	switch w := v.List.(type) {
+	case *ast.ListLit_BasicList:
+		for _, bval := range w.BasicList.Values {
+			vals = append(vals, bval)
+		}
+	case *ast.ListLit_CompositeList:
+		for _, cval := range w.CompositeList.Values {
+			vals = append(vals, cval)
+		}
+	}
+
+	vLen := len(vals) - 1
+	for i, iv := range vals {
+		g.printVal(typ, iv)
+		if i != vLen {
+			g.WriteByte(',')
+			g.WriteByte(' ')
+		}
+	}
+
+	g.WriteByte(')')
+}
+
+// docText returns the text of a description with surrounding whitespace removed.
+func docText(doc *ast.DocGroup) string {
+	if doc == nil {
+		return ""
+	}
+	return strings.TrimSpace(doc.Text())
+}
+
+// printJavadoc prints a description as a Javadoc comment.
+func (g *Generator) printJavadoc(doc *ast.DocGroup) {
+	text := docText(doc)
+	if text == "" {
+		return
+	}
+	text = strings.Replace(text, "*/", "*&#47;", -1)
+
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		g.P("/** ", lines[0], " */")
+		return
+	}
+
+	g.P("/**")
+	for _, line := range lines {
+		if len(line) == 0 {
+			g.P(" *")
+			continue
+		}
+		g.P(" * ", line)
+	}
+	g.P(" */")
+}
+
+// quote returns s as a double quoted Java string literal.
+func quote(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+var keywords = map[string]bool{
+	"abstract": true, "assert": true, "boolean": true, "break": true, "byte": true,
+	"case": true, "catch": true, "char": true, "class": true, "const": true,
+	"continue": true, "default": true, "do": true, "double": true, "else": true,
+	"enum": true, "extends": true, "final": true, "finally": true, "float": true,
+	"for": true, "goto": true, "if": true, "implements": true, "import": true,
+	"instanceof": true, "int": true, "interface": true, "long": true, "native": true,
+	"new": true, "package": true, "private": true, "protected": true, "public": true,
+	"return": true, "short": true, "static": true, "strictfp": true, "super": true,
+	"switch": true, "synchronized": true, "this": true, "throw": true, "throws": true,
+	"transient": true, "try": true, "void": true, "volatile": true, "while": true,
+	"true": true, "false": true, "null": true, "_": true,
+}
+
+// identifier returns a valid Java identifier for a GraphQL name.
+func identifier(name string) string {
+	if keywords[name] {
+		return name + "_"
+	}
+	return name
+}
+
+func getter(name string) string { return "get" + upperFirst(name) }
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// pascalCase converts a file name e.g. star-wars to a class name e.g. StarWars.
+func pascalCase(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Descriptions: true,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "java" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		javaOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range javaOpts.Fields {
+			switch arg.Key.Name {
+			case "package":
+				gOpts.Package = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			case "descriptions":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Descriptions = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts == nil {
+		return
+	}
+	if p, ok := opts["package"]; ok {
+		gOpts.Package, _ = p.(string)
+	}
+	if d, ok := opts["descriptions"]; ok {
+		gOpts.Descriptions, _ = d.(bool)
+	}
+
+	return
+}
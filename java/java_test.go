@@ -0,0 +1,456 @@
+package java
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "Test.java", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected java output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected java output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestNames(t *testing.T) {
+	testCases := []struct {
+		Doc   string
+		Types []string
+		Class string
+	}{
+		{Doc: "test.gql", Class: "Test"},
+		{Doc: "star-wars.graphql", Class: "StarWars"},
+		{Doc: "dir/api_v2.gql", Class: "ApiV2"},
+		{Doc: "1.gql", Class: "Schema1"},
+		{Doc: "user.gql", Types: []string{"User"}, Class: "UserSchema"},
+	}
+
+	for _, testCase := range testCases {
+		doc := &ast.Document{Name: testCase.Doc}
+		for _, name := range testCase.Types {
+			doc.Types = append(doc.Types, &ast.TypeDecl{
+				Spec: &ast.TypeDecl_TypeSpec{TypeSpec: &ast.TypeSpec{Name: &ast.Ident{Name: name}}},
+			})
+		}
+
+		if class := outerClassName(doc); class != testCase.Class {
+			t.Errorf("expected class name %s for %s, but got: %s", testCase.Class, testCase.Doc, class)
+		}
+	}
+
+	if id := identifier("class"); id != "class_" {
+		t.Errorf("expected keyword to be escaped, but got: %s", id)
+	}
+}
+
+func TestJavaType(t *testing.T) {
+	g := &Generator{scalars: map[string]bool{"Time": true}}
+
+	testCases := []struct {
+		Type interface{}
+		Java string
+	}{
+		{Type: &ast.Ident{Name: "Int"}, Java: "Integer"},
+		{Type: &ast.Ident{Name: "ID"}, Java: "String"},
+		{Type: &ast.Ident{Name: "Time"}, Java: "Object"},
+		{Type: &ast.Ident{Name: "User"}, Java: "User"},
+		{
+			Type: &ast.NonNull{Type: &ast.NonNull_List{List: &ast.List{Type: &ast.List_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Float"}}}}}}},
+			Java: "List<Double>",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if typ := g.javaType(testCase.Type); typ != testCase.Java {
+			t.Errorf("expected: %s, but got: %s", testCase.Java, typ)
+		}
+	}
+}
+
+func TestObject(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+	g.unions["Test"] = []string{"Result"}
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Object{
+		Object: &ast.ObjectType{
+			Interfaces: []*ast.Ident{{Name: "Node"}},
+			Fields: &ast.FieldList{
+				List: []*ast.Field{
+					{
+						Name: &ast.Ident{Name: "id"},
+						Type: &ast.Field_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}},
+					},
+					{
+						Name: &ast.Ident{Name: "default"},
+						Type: &ast.Field_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Test"}}}},
+					},
+				},
+			},
+		},
+	}}
+
+	g.generateObject("Test", nil, false, ts)
+
+	ex := []byte(`public static class Test implements Node, Result {
+    private String id;
+    private List<Test> default_;
+
+    public String getId() {
+        return id;
+    }
+
+    public void setId(String id) {
+        this.id = id;
+    }
+
+    public List<Test> getDefault() {
+        return default_;
+    }
+
+    public void setDefault(List<Test> default_) {
+        this.default_ = default_;
+    }
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestInterface(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Interface{
+		Interface: &ast.InterfaceType{
+			Fields: &ast.FieldList{
+				List: []*ast.Field{
+					{
+						Name: &ast.Ident{Name: "id"},
+						Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "ID"}},
+					},
+				},
+			},
+		},
+	}}
+
+	g.generateInterface("Node", nil, false, ts)
+
+	ex := []byte(`public interface Node {
+    String getId();
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestEnum(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Enum{
+		Enum: &ast.EnumType{
+			Values: &ast.FieldList{
+				List: []*ast.Field{
+					{Name: &ast.Ident{Name: "A"}},
+					{Name: &ast.Ident{Name: "B"}},
+				},
+			},
+		},
+	}}
+
+	g.generateEnum("Test", nil, false, ts)
+
+	ex := []byte(`public enum Test {
+    A,
+    B
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestInput(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Input{
+		Input: &ast.InputType{
+			Fields: &ast.InputValueList{
+				List: []*ast.InputValue{
+					{
+						Name: &ast.Ident{Name: "ratio"},
+						Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "Float"}},
+						Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+							Kind:  token.Token_INT,
+							Value: "1",
+						}},
+					},
+					{
+						Name: &ast.Ident{Name: "dirs"},
+						Type: &ast.InputValue_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Direction"}}}},
+						Default: &ast.InputValue_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ListLit{
+							ListLit: &ast.ListLit{
+								List: &ast.ListLit_BasicList{BasicList: &ast.ListLit_Basic{Values: []*ast.BasicLit{
+									{Kind: token.Token_IDENT, Value: "NORTH"},
+									{Kind: token.Token_IDENT, Value: "SOUTH"},
+								}}},
+							},
+						}}},
+					},
+				},
+			},
+		},
+	}}
+
+	g.generateInput("Test", nil, false, ts)
+
+	ex := []byte(`public static class Test {
+    private Double ratio = 1.0;
+    private List<Direction> dirs = java.util.Arrays.asList(Direction.NORTH, Direction.SOUTH);
+
+    public Double getRatio() {
+        return ratio;
+    }
+
+    public void setRatio(Double ratio) {
+        this.ratio = ratio;
+    }
+
+    public List<Direction> getDirs() {
+        return dirs;
+    }
+
+    public void setDirs(List<Direction> dirs) {
+        this.dirs = dirs;
+    }
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestJavadoc(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	doc := &ast.DocGroup{List: []*ast.DocGroup_Doc{
+		{Text: "# First line.", Char: '#'},
+		{Text: "# Ends a comment */ early.", Char: '#'},
+	}}
+
+	g.printJavadoc(doc)
+
+	ex := []byte(`/**
+ * First line.
+ * Ends a comment *&#47; early.
+ */
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestOptions(t *testing.T) {
+	doc := &ast.Document{
+		Directives: []*ast.DirectiveLit{
+			{
+				Name: "java",
+				Args: &ast.CallExpr{Args: []*ast.Arg{{
+					Name: &ast.Ident{Name: "options"},
+					Value: &ast.Arg_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+						Fields: []*ast.ObjLit_Pair{
+							{
+								Key: &ast.Ident{Name: "package"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: `"com.example"`}}},
+							},
+							{
+								Key: &ast.Ident{Name: "descriptions"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_BOOL, Value: "false"}}},
+							},
+						},
+					}}}},
+				}}},
+			},
+		},
+	}
+
+	gOpts, err := getOptions(doc, map[string]interface{}{"package": "org.example"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.Package != "org.example" || gOpts.Descriptions {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := new(Generator)
+	g.Reset()
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `schema {
+	query: Query
+}
+
+"Query represents the queries this example provides."
+type Query {
+	hello: String
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, nil)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Println(b.String())
+	// Output:
+	// import graphql.schema.Coercing;
+	// import graphql.schema.GraphQLScalarType;
+	// import graphql.schema.idl.RuntimeWiring;
+	// import graphql.schema.idl.TypeRuntimeWiring;
+	// import java.util.List;
+	//
+	// public final class Example {
+	//     private Example() {}
+	//
+	//     /**
+	//      * newRuntimeWiring returns a RuntimeWiring builder which wires up
+	//      * every scalar, data fetcher and type resolver of the schema.
+	//      */
+	//     public static RuntimeWiring.Builder newRuntimeWiring() {
+	//         return RuntimeWiring.newRuntimeWiring()
+	//                 .type(TypeRuntimeWiring.newTypeWiring("Query")
+	//                         .dataFetcher("hello", env -> {
+	//                             // TODO
+	//                             return null;
+	//                         })
+	//                 );
+	//     }
+	// }
+}
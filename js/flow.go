@@ -0,0 +1,74 @@
+package js
+
+import "github.com/gqlc/graphql/ast"
+
+// Flow signatures of the resolver stubs, which match the function types
+// graphql-js declares for them.
+const (
+	flowResolveParams     = "(source: mixed, args: { [argument: string]: mixed }, context: mixed, info: GraphQLResolveInfo): mixed"
+	flowSerializeParams   = "(value: mixed): mixed"
+	flowResolveTypeParams = "(value: mixed, context: mixed, info: GraphQLResolveInfo): ?string"
+
+	flowInfoImport = "import type { GraphQLResolveInfo } from 'graphql';"
+)
+
+// flowClass returns the graphql-js class a type is constructed with.
+func flowClass(ts *ast.TypeSpec) string {
+	switch ts.Type.(type) {
+	case *ast.TypeSpec_Schema:
+		return "GraphQLSchema"
+	case *ast.TypeSpec_Scalar:
+		return "GraphQLScalarType"
+	case *ast.TypeSpec_Object:
+		return "GraphQLObjectType"
+	case *ast.TypeSpec_Interface:
+		return "GraphQLInterfaceType"
+	case *ast.TypeSpec_Union:
+		return "GraphQLUnionType"
+	case *ast.TypeSpec_Enum:
+		return "GraphQLEnumType"
+	case *ast.TypeSpec_Input:
+		return "GraphQLInputObjectType"
+	case *ast.TypeSpec_Directive:
+		return "GraphQLDirectiveType"
+	}
+	return ""
+}
+
+// annotate returns the Flow annotation of a declaration, if it's annotated.
+func (g *Generator) annotate(typ string) string {
+	if !g.flow || typ == "" {
+		return ""
+	}
+	return ": " + typ
+}
+
+// resolveParams returns the parameters of a field's resolver stub, which
+// are only listed when they're annotated.
+//
+func (g *Generator) resolveParams() string {
+	if !g.flow {
+		return "()"
+	}
+	g.resolveInfo = true
+	return flowResolveParams
+}
+
+// serializeParams returns the parameters of a scalar's serialize stub.
+func (g *Generator) serializeParams() string {
+	if !g.flow {
+		return "(value)"
+	}
+	return flowSerializeParams
+}
+
+// resolveTypeParams returns the parameters of an abstract type's
+// resolveType stub.
+//
+func (g *Generator) resolveTypeParams() string {
+	if !g.flow {
+		return "(value)"
+	}
+	g.resolveInfo = true
+	return flowResolveTypeParams
+}
@@ -0,0 +1,253 @@
+package js
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/gqlc/gqlc/gen"
+)
+
+// style is how generated code is indented and quoted. Code is always
+// generated with two spaces and single quotes, and rewritten to the style
+// as it's written.
+//
+type style struct {
+	// indent replaces each two spaces of indentation
+	indent []byte
+
+	// quote delimits every string literal
+	quote byte
+}
+
+// parseStyle returns the style given by the indent and quotes options, or
+// nil if they're the defaults the code is generated with.
+//
+func parseStyle(indent, quotes string) (*style, error) {
+	s := &style{indent: []byte("  "), quote: '\''}
+	switch indent {
+	case "tab", "tabs":
+		s.indent = []byte{'\t'}
+	default:
+		n, err := strconv.Atoi(indent)
+		if err != nil || n < 1 || n > 8 {
+			return nil, fmt.Errorf("unknown indent option: %s, must be a number of spaces or tab", indent)
+		}
+		s.indent = make([]byte, n)
+		for i := range s.indent {
+			s.indent[i] = ' '
+		}
+	}
+
+	switch quotes {
+	case "single":
+	case "double":
+		s.quote = '"'
+	default:
+		return nil, fmt.Errorf("unknown quotes option: %s", quotes)
+	}
+
+	if string(s.indent) == "  " && s.quote == '\'' {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// open opens a file to write generated code to in the style.
+func (g *Generator) open(gCtx gen.GeneratorContext, name string) (io.WriteCloser, error) {
+	return g.styled(gCtx.Open(name))
+}
+
+// scaffold opens a file to scaffold code in, in the style, which returns
+// gen.ErrScaffolded if it already exists.
+//
+func (g *Generator) scaffold(gCtx gen.GeneratorContext, name string) (io.WriteCloser, error) {
+	return g.styled(gen.Scaffold(gCtx, name))
+}
+
+func (g *Generator) styled(f io.WriteCloser, err error) (io.WriteCloser, error) {
+	if err != nil || g.style == nil {
+		return f, err
+	}
+	return &styleWriter{WriteCloser: f, style: g.style, lineStart: true}, nil
+}
+
+const (
+	inCode = iota
+	inString
+	inTemplate
+	inLineComment
+	inBlockComment
+)
+
+// styleWriter rewrites the indentation and string literals of the code
+// written to it. It scans the code as it's written, so code may be
+// written in any number of pieces.
+//
+// The contents of template literals are left alone, since they're part
+// of the string, e.g. the SDL of the sdl style.
+//
+type styleWriter struct {
+	io.WriteCloser
+	*style
+
+	buf []byte
+
+	mode  int
+	delim byte
+	prev  byte
+
+	// escaped is set after a backslash in a string, which is held back
+	// until the escaped character is known.
+	//
+	escaped bool
+
+	// lineStart is set until the indentation of a line is over, and
+	// spaces counts it.
+	//
+	lineStart bool
+	spaces    int
+
+	// braces counts the open braces of each template literal placeholder
+	// being scanned.
+	//
+	braces []int
+}
+
+func (w *styleWriter) Write(p []byte) (int, error) {
+	w.buf = w.buf[:0]
+	for _, c := range p {
+		w.scan(c)
+	}
+
+	if _, err := w.WriteCloser.Write(w.buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close writes any indentation left, before closing the file.
+func (w *styleWriter) Close() error {
+	w.buf = w.buf[:0]
+	w.writeIndent()
+	if len(w.buf) > 0 {
+		w.WriteCloser.Write(w.buf)
+	}
+	return w.WriteCloser.Close()
+}
+
+func (w *styleWriter) writeIndent() {
+	for ; w.spaces > 1; w.spaces -= 2 {
+		w.buf = append(w.buf, w.indent...)
+	}
+	if w.spaces == 1 {
+		w.buf = append(w.buf, ' ')
+	}
+	w.spaces = 0
+}
+
+func (w *styleWriter) scan(c byte) {
+	prev := w.prev
+	w.prev = c
+
+	if w.lineStart && (w.mode == inCode || w.mode == inBlockComment) {
+		if c == ' ' {
+			w.spaces++
+			return
+		}
+		if c != '\n' {
+			w.writeIndent()
+		}
+		w.spaces = 0
+		w.lineStart = false
+	}
+
+	switch w.mode {
+	case inCode:
+		switch {
+		case c == '\'' || c == '"':
+			w.mode, w.delim = inString, c
+			c = w.quote
+		case c == '`':
+			w.mode = inTemplate
+		case c == '/' && prev == '/':
+			w.mode = inLineComment
+		case c == '*' && prev == '/':
+			w.mode = inBlockComment
+			w.prev = 0
+		case c == '{' && len(w.braces) > 0:
+			w.braces[len(w.braces)-1]++
+		case c == '}' && len(w.braces) > 0:
+			n := len(w.braces) - 1
+			if w.braces[n] == 0 {
+				w.braces = w.braces[:n]
+				w.mode = inTemplate
+			} else {
+				w.braces[n]--
+			}
+		}
+	case inString:
+		w.scanString(c)
+		return
+	case inTemplate:
+		switch {
+		case w.escaped:
+			// An escaped $ doesn't start a placeholder
+			w.escaped = false
+			w.prev = 0
+		case c == '\\':
+			w.escaped = true
+		case c == '`':
+			w.mode = inCode
+		case c == '{' && prev == '$':
+			w.braces = append(w.braces, 0)
+			w.mode = inCode
+		}
+	case inLineComment:
+		if c == '\n' {
+			w.mode = inCode
+		}
+	case inBlockComment:
+		if c == '/' && prev == '*' {
+			w.mode = inCode
+			w.prev = 0
+		}
+	}
+
+	if c == '\n' && (w.mode == inCode || w.mode == inBlockComment) {
+		w.lineStart = true
+	}
+	w.buf = append(w.buf, c)
+}
+
+// scanString requotes a string literal, so only the new quote needs
+// escaping in it.
+//
+func (w *styleWriter) scanString(c byte) {
+	if w.escaped {
+		w.escaped = false
+		if c != w.delim || c == w.quote {
+			w.buf = append(w.buf, '\\')
+		}
+		w.buf = append(w.buf, c)
+		return
+	}
+
+	switch c {
+	case '\\':
+		w.escaped = true
+	case w.delim:
+		w.mode = inCode
+		w.prev = 0
+		w.buf = append(w.buf, w.quote)
+	case '\n':
+		// Unterminated, so leave the rest of the line alone
+		w.mode = inCode
+		w.lineStart = true
+		w.buf = append(w.buf, c)
+	case w.quote:
+		w.buf = append(w.buf, '\\', c)
+	default:
+		w.buf = append(w.buf, c)
+	}
+}
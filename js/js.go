@@ -4,14 +4,20 @@ package js
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
-	"sync"
 
+	"github.com/gqlc/gqlc/federation"
 	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/gen/printer"
+	"github.com/gqlc/gqlc/sdl"
+	"github.com/gqlc/gqlc/types"
 	"github.com/gqlc/graphql/ast"
 	"github.com/gqlc/graphql/token"
 	"go.uber.org/zap"
@@ -48,11 +54,71 @@ type Options struct {
 	// Add @flow comment
 	UseFlow bool
 
+	// Annotate declarations and resolver stubs with Flow types, which
+	// implies UseFlow
+	FlowTypes bool
+
 	// Copy descriptions to Javascript
 	Descriptions bool
 
+	// Emit a TypeScript declaration file alongside the Javascript
+	Dts bool
+
+	// Comment each declaration with where it's defined in the schema
+	Positions bool
+
+	// Serve the document as an Apollo Federation subgraph
+	Federation bool
+
+	// Write each type to its own module, along with an index.js
+	FilePerType bool
+
+	// Either "inline" or "separate", which writes resolver stubs to a
+	// <name>.resolvers.js file
+	Resolvers string
+
+	// Scaffold a server for the schema, which can only be "apollo"
+	Framework string
+
+	// Either "graphql", which constructs graphql-js types, or "nexus",
+	// which defines them with nexus
+	Style string
+
+	// Maps scalar names to the module and export which implements them,
+	// formatted as module:export e.g. graphql-iso-date:GraphQLDateTime.
+	// Without an export, the module itself is the implementation.
+	//
+	Scalars map[string]string
+
+	// Either "source", "alpha" or "topo", the order types are declared in
+	Order string
+
+	// Wrap fields, interfaces and union members in functions, so types
+	// can reference types which are declared after them. ES6 modules
+	// always do.
+	//
+	Thunks bool
+
+	// Declare every type with export const, which needs an ES6 module
+	NamedExports bool
+
+	// The extension of the generated modules, either "js", "mjs" or "cjs"
+	Extension string
+
+	// Either "node", which imports modules the way Node resolves them, or
+	// "bundler", which imports them without their extension, for bundlers
+	// e.g. webpack or vite to resolve
+	Target string
+
+	// Either "tab" or the number of spaces to indent by, which is 2 by default
+	Indent string
+
+	// Either "single" or "double"
+	Quotes string
+
 	imports [][]byte
 	declStr []byte
+	style   *style
 }
 
 var bits = []struct {
@@ -86,25 +152,51 @@ func (o *Options) setImports(mask uint16) {
 
 // Generator generates Javascript code for a GraphQL schema.
 type Generator struct {
-	sync.Mutex
-	bytes.Buffer
-
-	indent []byte
-	log    *zap.Logger
+	printer.Printer
+
+	log *zap.Logger
+
+	// separate is set when resolver stubs are written to their own file,
+	// instead of inline.
+	//
+	separate bool
+
+	// thunks is set when the types a type references are wrapped in
+	// functions, which graphql-js calls once every type is declared.
+	//
+	thunks bool
+
+	// forward holds the types which reference a type declared after them,
+	// or themselves, when they're ordered topologically, so they're
+	// wrapped in thunks even when thunks isn't set.
+	//
+	forward map[string]bool
+
+	// flow is set when declarations and resolver stubs are annotated with
+	// Flow types, and resolveInfo once a stub needs GraphQLResolveInfo.
+	//
+	flow        bool
+	resolveInfo bool
+
+	// style rewrites the generated code, if it's not indented and quoted
+	// the default way.
+	//
+	style *style
 }
 
-// Reset overrides the bytes.Buffer Reset method to assist in cleaning up some Generator state.
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
 func (g *Generator) Reset() {
-	g.Buffer.Reset()
-	if g.indent == nil {
-		g.indent = make([]byte, 0, 10)
-	}
-	g.indent = g.indent[0:0]
+	g.Printer.Reset()
+	g.resolveInfo = false
+	g.forward = nil
 }
 
 // Generate generates Javascript code for the given document.
-func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
-	g.Lock()
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
 	defer func() {
 		if err != nil {
 			err = gen.GeneratorError{
@@ -114,11 +206,13 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 			}
 		}
 	}()
-	defer g.Unlock()
 	g.Reset()
 
-	if g.log == nil {
-		g.log = zap.L().Named("js").With(zap.String("doc", doc.Name))
+	g.log = zap.L().Named("js").With(zap.String("doc", doc.Name))
+
+	// Check the document is well formed
+	if verr := gen.ValidateShapeContext(gen.Context(ctx), doc); verr != nil {
+		return verr
 	}
 
 	// Get generator options
@@ -127,18 +221,51 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 	if oerr != nil {
 		return oerr
 	}
+	g.separate = gOpts.Resolvers == "separate"
+	g.thunks = gOpts.Thunks
+	g.flow = gOpts.FlowTypes
+	g.style = gOpts.style
 
 	// Create bit mask for tracking imports
 	mask := schemaBit | scalarBit | objectBit | interfaceBit | unionBit | enumBit | inputObjectBit | directiveBit
 	mask |= listBit | nonNullBit
 	mask |= intBit | floatBit | stringBit | booleanBit | idBit
 
-	// Generate schema
-	if doc.Schema != nil {
-		g.log.Info("generating schema")
-		mask &= ^schemaBit
-		g.generateSchema(gOpts, doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec)
-		g.P()
+	// Add the types and fields of a subgraph
+	var serviceSDL string
+	if gOpts.Federation {
+		g.log.Info("adding federation types")
+		serviceSDL = federation.SDL(doc)
+		doc = federation.Subgraph(doc, subgraphResolvers)
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	doc = g.orderDoc(gCtx, doc, gOpts.Order)
+
+	var posCtx gen.PositionContext
+	if gOpts.Positions {
+		var ok bool
+		posCtx, ok = gCtx.(gen.PositionContext)
+		if !ok {
+			gen.Warnf(gCtx, doc, token.NoPos, "token positions are unknown, so no position comments will be written")
+		}
+	}
+
+	switch gOpts.Style {
+	case "nexus":
+		return g.generateNexus(gCtx, posCtx, gOpts, doc)
+	case "sdl":
+		return g.generateTypeDefs(gCtx, gOpts, doc)
+	}
+
+	if gOpts.FilePerType {
+		return g.generateFiles(gCtx, posCtx, gOpts, doc, serviceSDL)
+	}
+
+	if gOpts.Federation {
+		g.writeServiceSDL(gOpts, serviceSDL)
 	}
 
 	// Generate types
@@ -153,77 +280,926 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 			continue
 		}
 
-		// Generate variable declaration
-		name := ts.TypeSpec.Name.Name
-		g.Write(gOpts.declStr)
-		g.WriteByte(' ')
-		g.WriteString(name)
-		g.WriteString("Type")
-		g.WriteByte(' ')
-		g.WriteByte('=')
-		g.WriteByte(' ')
-		g.WriteString("new")
-		g.WriteByte(' ')
+		g.writePosition(posCtx, d.TokPos)
+		g.thunks = gOpts.Thunks || g.forward[declName(d)]
+		g.generateType(&mask, gOpts, d, ts.TypeSpec)
+		g.thunks = gOpts.Thunks
 
-		// Generate GraphQL*Type construction
-		switch ts.TypeSpec.Type.(type) {
-		case *ast.TypeSpec_Scalar:
-			g.generateScalar(&mask, name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+		if i != totalTypes {
+			g.P()
+		}
+	}
 
-			mask &= ^scalarBit
-		case *ast.TypeSpec_Object:
-			g.generateObject(&mask, name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+	// Generate schema, which comes after every type, since it uses the
+	// root operation types as it's constructed
+	if doc.Schema != nil {
+		g.log.Info("generating schema")
+		mask &= ^schemaBit
+		g.P()
+		g.writePosition(posCtx, doc.Schema.TokPos)
+		g.generateSchema(gOpts, doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec)
+	}
+
+	// Export types for the resolvers and server to import
+	if (g.separate || gOpts.Framework != "") && !gOpts.NamedExports {
+		g.WriteByte('\n')
+		g.writeExports(gOpts, exportNames(doc)...)
+	}
+
+	// Open file to write to
+	jsFileName := doc.Name[:len(doc.Name)-len(filepath.Ext(doc.Name))]
+	jsFile, err := g.open(gCtx, jsFileName+gOpts.ext())
+	if err != nil {
+		return
+	}
+	defer jsFile.Close()
+
+	// Write module import statement
+	g.log.Info("writing module import statement")
+	gOpts.setImports(mask)
+	_, err = g.writeImports(jsFile, gOpts)
+	if err != nil {
+		return
+	}
+
+	// Write generated output
+	_, err = g.WriteTo(jsFile)
+	if err != nil {
+		return
+	}
+
+	// Write TypeScript declarations
+	if gOpts.Dts {
+		g.log.Info("writing typescript declarations")
+		err = g.writeDtsFile(gCtx, jsFileName+gOpts.dtsExt(), doc)
+		if err != nil {
+			return
+		}
+	}
+
+	if g.separate {
+		err = g.writeResolvers(gCtx, gOpts, doc, jsFileName, filepath.Base(jsFileName))
+		if err != nil {
+			return
+		}
+	}
 
-			mask &= ^objectBit
-		case *ast.TypeSpec_Interface:
-			g.generateInterface(&mask, name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+	if gOpts.Framework == "apollo" {
+		err = g.writeServer(gCtx, gOpts, doc, filepath.Join(filepath.Dir(jsFileName), "index"+gOpts.ext()), filepath.Base(jsFileName))
+	}
+	return
+}
+
+// scalarImpl returns the implementation a scalar is mapped to, if any.
+func (o *Options) scalarImpl(ts *ast.TypeSpec) (string, bool) {
+	if _, ok := ts.Type.(*ast.TypeSpec_Scalar); !ok {
+		return "", false
+	}
+
+	impl, ok := o.Scalars[ts.Name.Name]
+	return impl, ok
+}
+
+// writeScalarImport imports the implementation of a scalar as its type,
+// instead of declaring it.
+//
+func (g *Generator) writeScalarImport(opts *Options, name, impl string) {
+	mod, export := impl, ""
+	if i := strings.LastIndex(impl, ":"); i > 0 {
+		mod, export = impl[:i], impl[i+1:]
+	}
+
+	switch {
+	case opts.Module == "ES6" && export == "":
+		g.P("import ", name, "Type from '", mod, "';")
+	case opts.Module == "ES6":
+		g.P("import { ", export, " as ", name, "Type } from '", mod, "';")
+	case export == "":
+		g.P(opts.declStr, " ", name, "Type = require('", mod, "');")
+	default:
+		g.P(opts.declStr, " { ", export, ": ", name, "Type } = require('", mod, "');")
+	}
+}
 
-			mask &= ^interfaceBit
+// parseScalars adds scalar mappings, formatted as Name=module:export, to
+// the options. A mapping may list several, separated by commas.
+//
+func (o *Options) parseScalars(mappings ...string) error {
+	if o.Scalars == nil {
+		o.Scalars = make(map[string]string)
+	}
+
+	for _, m := range mappings {
+		for _, kv := range strings.Split(strings.Trim(m, `"`), ",") {
+			kv = strings.TrimSpace(kv)
+			if kv == "" {
+				continue
+			}
+
+			i := strings.Index(kv, "=")
+			if i <= 0 || i == len(kv)-1 || strings.HasPrefix(kv[i+1:], ":") {
+				return fmt.Errorf("invalid scalars option: %s, must be formatted as Name=module:export", kv)
+			}
+			o.Scalars[kv[:i]] = kv[i+1:]
+		}
+	}
+	return nil
+}
+
+func (g *Generator) writeDtsFile(gCtx gen.GeneratorContext, name string, doc *ast.Document) error {
+	dtsFile, err := g.open(gCtx, name)
+	if err != nil {
+		return err
+	}
+	defer dtsFile.Close()
+
+	_, err = writeDts(dtsFile, doc)
+	return err
+}
+
+// exportNames returns the names of the constants declared for a document.
+func exportNames(doc *ast.Document) []string {
+	var names []string
+	if doc.Schema != nil {
+		names = append(names, "Schema")
+	}
+
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Schema); ok {
+			continue
+		}
+		names = append(names, ts.TypeSpec.Name.Name+"Type")
+	}
+	return names
+}
+
+// writeResolvers writes resolver stubs, which are attached to the generated
+// types, to <name>.resolvers.js. It's only written if it doesn't exist yet,
+// so the stubs can be filled in and the types regenerated without losing them.
+//
+func (g *Generator) writeResolvers(gCtx gen.GeneratorContext, opts *Options, doc *ast.Document, name, from string) error {
+	name += ".resolvers" + opts.ext()
+	f, err := g.scaffold(gCtx, name)
+	if err == gen.ErrScaffolded {
+		g.log.Info("resolvers already exist, so they won't be overwritten", zap.String("file", name))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	g.log.Info("writing resolver stubs", zap.String("file", name))
+
+	// Stubs are annotated with Flow types, if they're set
+	sig := func(params, flow string) string {
+		if g.flow {
+			return flow
+		}
+		return params
+	}
+
+	var stubs bytes.Buffer
+	var names []string
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		typ := ts.TypeSpec.Name.Name + "Type"
+
+		n := stubs.Len()
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar:
+			if _, ok = opts.scalarImpl(ts.TypeSpec); ok {
+				continue
+			}
+			fmt.Fprintf(&stubs, "%s.serialize = function%s { /* TODO */ };\n", typ, sig("(value)", flowSerializeParams))
 		case *ast.TypeSpec_Union:
-			g.generateUnion(&mask, name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+			fmt.Fprintf(&stubs, "%s.resolveType = function%s { /* TODO */ };\n", typ, sig("(value, context, info)", flowResolveTypeParams))
+		case *ast.TypeSpec_Object:
+			if v.Object.Fields == nil {
+				continue
+			}
+
+			for _, f := range v.Object.Fields.List {
+				if getResolver(f.Directives) != "" {
+					continue
+				}
+				fmt.Fprintf(&stubs, "%s.getFields().%s.resolve = function%s { /* TODO */ };\n", typ, f.Name.Name, sig("(source, args, context, info)", flowResolveParams))
+			}
+		}
+		if stubs.Len() == n {
+			continue
+		}
+
+		stubs.WriteByte('\n')
+		names = append(names, typ)
+	}
 
-			mask &= ^unionBit
-		case *ast.TypeSpec_Enum:
-			g.generateEnum(&mask, name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+	var b bytes.Buffer
+	if opts.UseFlow {
+		b.Write(flowDirective)
+		b.WriteString("\n\n")
+	}
+	if len(names) > 0 {
+		if g.flow {
+			b.WriteString(flowInfoImport)
+			b.WriteByte('\n')
+		}
+
+		switch opts.Module {
+		case "ES6":
+			fmt.Fprintf(&b, "import { %s } from '%s';\n\n", strings.Join(names, ", "), opts.specifier("./"+from))
+		default:
+			fmt.Fprintf(&b, "var { %s } = require('%s');\n\n", strings.Join(names, ", "), opts.specifier("./"+from))
+		}
+		stubs.Truncate(stubs.Len() - 1)
+	}
+	stubs.WriteTo(&b)
+
+	_, err = b.WriteTo(f)
+	return err
+}
+
+// writeServer writes a starter Apollo Server, which serves the types
+// imported from the given module. Unless the resolvers are written
+// separately, the server declares a map of resolver stubs for the root
+// operations, which it attaches to the schema. Like the resolvers, it's
+// only written if it doesn't exist yet.
+//
+func (g *Generator) writeServer(gCtx gen.GeneratorContext, opts *Options, doc *ast.Document, name, from string) error {
+	roots := rootTypes(doc)
+	if len(roots) == 0 {
+		return fmt.Errorf("an apollo server needs a query type to serve")
+	}
 
-			mask &= ^enumBit
-		case *ast.TypeSpec_Input:
-			g.generateInput(&mask, name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+	f, err := g.scaffold(gCtx, name)
+	if err == gen.ErrScaffolded {
+		g.log.Info("server already exists, so it won't be overwritten", zap.String("file", name))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	g.log.Info("writing apollo server", zap.String("file", name))
 
-			mask &= ^inputObjectBit
-		case *ast.TypeSpec_Directive:
-			g.generateDirective(&mask, name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+	var types []string
+	for _, r := range roots {
+		types = append(types, r.typ+"Type")
+	}
+
+	resolvers := filepath.Base(doc.Name[:len(doc.Name)-len(filepath.Ext(doc.Name))]) + ".resolvers"
 
-			mask &= ^directiveBit
+	var b bytes.Buffer
+	if opts.UseFlow {
+		b.Write(flowDirective)
+		b.WriteString("\n\n")
+	}
+
+	es6 := opts.Module == "ES6"
+	decl := "var"
+	if es6 {
+		decl = "const"
+		b.WriteString("import { GraphQLSchema } from 'graphql';\n")
+		b.WriteString("import { ApolloServer } from '@apollo/server';\n")
+		b.WriteString("import { startStandaloneServer } from '@apollo/server/standalone';\n")
+		fmt.Fprintf(&b, "import { %s } from '%s';\n", strings.Join(types, ", "), opts.specifier("./"+from))
+		if g.separate {
+			fmt.Fprintf(&b, "import '%s';\n", opts.specifier("./"+resolvers))
+		}
+	} else {
+		b.WriteString("var { GraphQLSchema } = require('graphql');\n")
+		b.WriteString("var { ApolloServer } = require('@apollo/server');\n")
+		b.WriteString("var { startStandaloneServer } = require('@apollo/server/standalone');\n")
+		fmt.Fprintf(&b, "var { %s } = require('%s');\n", strings.Join(types, ", "), opts.specifier("./"+from))
+		if g.separate {
+			fmt.Fprintf(&b, "require('%s');\n", opts.specifier("./"+resolvers))
 		}
+	}
+	b.WriteByte('\n')
 
-		if i != totalTypes {
-			g.P()
+	fmt.Fprintf(&b, "%s schema = new GraphQLSchema({\n", decl)
+	for i, r := range roots {
+		fmt.Fprintf(&b, "  %s: %sType%s\n", r.op, r.typ, sep(i != len(roots)-1))
+	}
+	b.WriteString("});\n\n")
+
+	if !g.separate {
+		fmt.Fprintf(&b, "%s resolvers = {\n", decl)
+		for i, r := range roots {
+			fmt.Fprintf(&b, "  %s: {\n", r.typ)
+			for j, f := range r.fields {
+				fmt.Fprintf(&b, "    %s(source, args, context, info) { /* TODO */ }%s\n", f, sep(j != len(r.fields)-1))
+			}
+			fmt.Fprintf(&b, "  }%s\n", sep(i != len(roots)-1))
 		}
+		b.WriteString("};\n\n")
+
+		b.WriteString("// Attach the resolvers to the fields of the schema\n")
+		b.WriteString("Object.keys(resolvers).forEach((typeName) => {\n")
+		b.WriteString("  const fields = schema.getType(typeName).getFields();\n")
+		b.WriteString("  Object.keys(resolvers[typeName]).forEach((fieldName) => {\n")
+		b.WriteString("    fields[fieldName].resolve = resolvers[typeName][fieldName];\n")
+		b.WriteString("  });\n")
+		b.WriteString("});\n\n")
 	}
 
-	// Extract generator context
-	gCtx := gen.Context(ctx)
+	fmt.Fprintf(&b, "%s server = new ApolloServer({ schema });\n\n", decl)
+	b.WriteString("startStandaloneServer(server, { listen: { port: 4000 } }).then(({ url }) => {\n")
+	b.WriteString("  console.log(`Server ready at ${url}`);\n")
+	b.WriteString("});\n")
 
-	// Open file to write to
+	_, err = b.WriteTo(f)
+	return err
+}
+
+type rootType struct {
+	op, typ string
+
+	// fields are those without a @resolver
+	fields []string
+}
+
+// rootTypes returns the declared root operation types of a document, in
+// query, mutation and subscription order. Without a schema declaration,
+// the types named Query, Mutation and Subscription are its root types.
+//
+func rootTypes(doc *ast.Document) (roots []rootType) {
+	names := map[string]string{
+		"query":        "Query",
+		"mutation":     "Mutation",
+		"subscription": "Subscription",
+	}
+	if doc.Schema != nil {
+		names = make(map[string]string)
+
+		schema := doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Schema).Schema
+		for _, f := range schema.RootOps.List {
+			if ident, ok := f.Type.(*ast.Field_Ident); ok {
+				names[strings.ToLower(f.Name.Name)] = ident.Ident.Name
+			}
+		}
+	}
+
+	objs := make(map[string]*ast.ObjectType)
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		if obj, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Object); ok {
+			objs[ts.TypeSpec.Name.Name] = obj.Object
+		}
+	}
+
+	for _, op := range rootOps {
+		obj, ok := objs[names[op]]
+		if !ok {
+			continue
+		}
+
+		r := rootType{op: op, typ: names[op]}
+		if obj.Fields != nil {
+			for _, f := range obj.Fields.List {
+				if getResolver(f.Directives) == "" {
+					r.fields = append(r.fields, f.Name.Name)
+				}
+			}
+		}
+		roots = append(roots, r)
+	}
+
+	if len(roots) > 0 && roots[0].op != "query" {
+		return nil
+	}
+	return
+}
+
+// generateType generates the variable declaration of a type.
+func (g *Generator) generateType(mask *uint16, opts *Options, d *ast.TypeDecl, ts *ast.TypeSpec) {
+	name := ts.Name.Name
+	if impl, ok := opts.scalarImpl(ts); ok {
+		g.writeScalarImport(opts, name, impl)
+		if opts.NamedExports {
+			g.P("export { ", name, "Type };")
+		}
+		return
+	}
+
+	g.Write(opts.declStr)
+	g.WriteByte(' ')
+	g.WriteString(name)
+	g.WriteString("Type")
+	g.WriteString(g.annotate(flowClass(ts)))
+	g.WriteByte(' ')
+	g.WriteByte('=')
+	g.WriteByte(' ')
+	g.WriteString("new")
+	g.WriteByte(' ')
+
+	// Generate GraphQL*Type construction
+	switch ts.Type.(type) {
+	case *ast.TypeSpec_Scalar:
+		g.generateScalar(mask, name, opts.Descriptions, d.Doc, ts)
+
+		*mask &= ^scalarBit
+	case *ast.TypeSpec_Object:
+		g.generateObject(mask, name, opts.Descriptions, d.Doc, ts)
+
+		*mask &= ^objectBit
+	case *ast.TypeSpec_Interface:
+		g.generateInterface(mask, name, opts.Descriptions, d.Doc, ts)
+
+		*mask &= ^interfaceBit
+	case *ast.TypeSpec_Union:
+		g.generateUnion(mask, name, opts.Descriptions, d.Doc, ts)
+
+		*mask &= ^unionBit
+	case *ast.TypeSpec_Enum:
+		g.generateEnum(mask, name, opts.Descriptions, d.Doc, ts)
+
+		*mask &= ^enumBit
+	case *ast.TypeSpec_Input:
+		g.generateInput(mask, name, opts.Descriptions, d.Doc, ts)
+
+		*mask &= ^inputObjectBit
+	case *ast.TypeSpec_Directive:
+		g.generateDirective(mask, name, opts.Descriptions, d.Doc, ts)
+
+		*mask &= ^directiveBit
+	}
+}
+
+// typesDir is the directory types are written to, when each has its own module.
+const typesDir = "types"
+
+// generateFiles writes each type of a document to its own module and an
+// index.js, which declares the schema and re-exports every type.
+//
+func (g *Generator) generateFiles(gCtx gen.GeneratorContext, posCtx gen.PositionContext, opts *Options, doc *ast.Document, serviceSDL string) error {
 	jsFileName := doc.Name[:len(doc.Name)-len(filepath.Ext(doc.Name))]
-	jsFile, err := gCtx.Open(jsFileName + ".js")
-	defer jsFile.Close()
+	dir := filepath.Dir(jsFileName)
+
+	// Relative scalar implementations are relative to the index, not the types
+	typeOpts := opts
+	if len(opts.Scalars) > 0 {
+		o := *opts
+		o.Scalars = make(map[string]string, len(opts.Scalars))
+		for name, impl := range opts.Scalars {
+			if strings.HasPrefix(impl, ".") {
+				impl = path.Join("..", impl)
+			}
+			o.Scalars[name] = impl
+		}
+		typeOpts = &o
+	}
+
+	declared := make(map[string]bool, len(doc.Types))
+	var names []string
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Schema); ok {
+			continue
+		}
+
+		name := ts.TypeSpec.Name.Name
+		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Directive); !ok {
+			declared[name] = true
+		}
+		names = append(names, name)
+	}
+
+	g.log.Info("generating types", zap.Int("files", len(names)))
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Schema); ok {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+
+		g.Reset()
+		if name == "_Service" && serviceSDL != "" {
+			g.writeServiceSDL(opts, serviceSDL)
+		}
+
+		mask := ^uint16(0)
+		g.writePosition(posCtx, d.TokPos)
+		g.generateType(&mask, typeOpts, d, ts.TypeSpec)
+		if !opts.NamedExports {
+			g.P()
+			g.writeExports(opts, name+"Type")
+		}
+
+		var refs []string
+		for _, ref := range typeRefs(ts.TypeSpec) {
+			if declared[ref] && ref != name {
+				refs = append(refs, ref)
+			}
+		}
+
+		err := g.writeFile(gCtx, filepath.Join(dir, typesDir, name+opts.ext()), opts, mask, ".", refs, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Generate index
+	g.log.Info("generating index")
+	g.Reset()
+
+	mask := ^uint16(0)
+	var refs []string
+	if doc.Schema != nil {
+		mask &= ^schemaBit
+
+		schema := doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+		for _, ref := range typeRefs(schema) {
+			if declared[ref] {
+				refs = append(refs, ref)
+			}
+		}
+
+		g.writePosition(posCtx, doc.Schema.TokPos)
+		g.generateSchema(opts, schema)
+		g.P()
+	}
+	g.writeIndexExports(opts, doc.Schema != nil, names)
+
+	err := g.writeFile(gCtx, filepath.Join(dir, "index"+opts.ext()), opts, mask, typesDir, refs, false)
 	if err != nil {
+		return err
+	}
+
+	if opts.Dts {
+		g.log.Info("writing typescript declarations")
+		err = g.writeDtsFile(gCtx, filepath.Join(dir, "index"+opts.dtsExt()), doc)
+		if err != nil {
+			return err
+		}
+	}
+
+	if g.separate {
+		err = g.writeResolvers(gCtx, opts, doc, jsFileName, "index")
+		if err != nil {
+			return err
+		}
+	}
+
+	// index.js is taken by the types, so the server gets its own file
+	if opts.Framework == "apollo" {
+		return g.writeServer(gCtx, opts, doc, filepath.Join(dir, "server"+opts.ext()), "index")
+	}
+	return nil
+}
+
+// writeFile writes the generated output to the named file, preceded by
+// the graphql-js classes it uses and the types it references, which are
+// imported from the modules in the given directory.
+//
+// Modules which require each other only get each other's exports once
+// they've been assigned, so with late, types are required after the output,
+// which only uses them in thunks. ES6 imports are bound to the exports
+// themselves, so they're always first.
+//
+func (g *Generator) writeFile(gCtx gen.GeneratorContext, name string, opts *Options, mask uint16, dir string, refs []string, late bool) error {
+	var b bytes.Buffer
+	if mask != ^uint16(0) {
+		opts.imports = opts.imports[:0]
+		opts.setImports(mask)
+		g.writeImports(&b, opts)
+		b.Truncate(b.Len() - 1)
+	} else if opts.UseFlow {
+		b.Write(flowDirective)
+		b.WriteByte('\n')
+	}
+
+	late = late && opts.Module != "ES6"
+
+	var reqs bytes.Buffer
+	sort.Strings(refs)
+	for _, ref := range refs {
+		mod := opts.specifier("./" + path.Join(dir, ref))
+
+		switch opts.Module {
+		case "ES6":
+			fmt.Fprintf(&b, "import { %sType } from '%s';\n", ref, mod)
+		default:
+			fmt.Fprintf(&reqs, "var { %sType } = require('%s');\n", ref, mod)
+		}
+	}
+	if !late {
+		reqs.WriteTo(&b)
+	}
+	if b.Len() > 0 {
+		b.WriteByte('\n')
+	}
+
+	f, err := g.open(gCtx, name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err = b.WriteTo(f); err != nil {
+		return err
+	}
+	if _, err = g.WriteTo(f); err != nil || !late || reqs.Len() == 0 {
+		return err
+	}
+
+	if _, err = f.Write([]byte{'\n'}); err != nil {
+		return err
+	}
+	_, err = reqs.WriteTo(f)
+	return err
+}
+
+// writeExports exports the given names from a module.
+func (g *Generator) writeExports(opts *Options, names ...string) {
+	if opts.Module == "ES6" {
+		g.P("export { ", strings.Join(names, ", "), " };")
+		return
+	}
+	g.P("module.exports = { ", strings.Join(names, ", "), " };")
+}
+
+// writeIndexExports re-exports every type from index.js.
+func (g *Generator) writeIndexExports(opts *Options, schema bool, names []string) {
+	if opts.Module == "ES6" {
+		if schema && !opts.NamedExports {
+			g.writeExports(opts, "Schema")
+		}
+		for _, name := range names {
+			g.P("export { ", name, "Type } from '", opts.specifier("./"+typesDir+"/"+name), "';")
+		}
+		return
+	}
+
+	g.P("module.exports = {")
+	g.In()
+	if schema {
+		g.P("Schema", sep(len(names) > 0))
+	}
+	for i, name := range names {
+		g.P("...require('", opts.specifier("./"+typesDir+"/"+name), "')", sep(i != len(names)-1))
+	}
+	g.Out()
+	g.P("};")
+}
+
+func sep(more bool) string {
+	if more {
+		return ","
+	}
+	return ""
+}
+
+// typeRefs returns the names of the types a type declaration refers to,
+// in the order they're first referred to.
+//
+func typeRefs(ts *ast.TypeSpec) (refs []string) {
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			refs = append(refs, name)
+		}
+	}
+	args := func(vals []*ast.InputValue) {
+		for _, v := range vals {
+			add(unwrapInputType(v))
+		}
+	}
+	fields := func(fields *ast.FieldList) {
+		if fields == nil {
+			return
+		}
+		for _, f := range fields.List {
+			add(unwrapFieldType(f))
+			if f.Args != nil {
+				args(f.Args.List)
+			}
+		}
+	}
+
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Schema:
+		fields(v.Schema.RootOps)
+	case *ast.TypeSpec_Object:
+		for _, inter := range v.Object.Interfaces {
+			add(inter.Name)
+		}
+		fields(v.Object.Fields)
+	case *ast.TypeSpec_Interface:
+		fields(v.Interface.Fields)
+	case *ast.TypeSpec_Union:
+		for _, mem := range v.Union.Members {
+			add(mem.Name)
+		}
+	case *ast.TypeSpec_Input:
+		if v.Input.Fields != nil {
+			args(v.Input.Fields.List)
+		}
+	case *ast.TypeSpec_Directive:
+		if v.Directive.Args != nil {
+			args(v.Directive.Args.List)
+		}
+	}
+	return
+}
+
+// unwrapFieldType returns the name of the named type of a field.
+func unwrapFieldType(f *ast.Field) string {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident.Name
+	case *ast.Field_List:
+		return unwrapType(v.List)
+	case *ast.Field_NonNull:
+		return unwrapType(v.NonNull)
+	}
+	return ""
+}
+
+// unwrapInputType returns the name of the named type of an input value.
+func unwrapInputType(v *ast.InputValue) string {
+	switch w := v.Type.(type) {
+	case *ast.InputValue_Ident:
+		return w.Ident.Name
+	case *ast.InputValue_List:
+		return unwrapType(w.List)
+	case *ast.InputValue_NonNull:
+		return unwrapType(w.NonNull)
+	}
+	return ""
+}
+
+func unwrapType(typ interface{}) string {
+	for {
+		switch v := typ.(type) {
+		case *ast.Ident:
+			return v.Name
+		case *ast.List:
+			switch w := v.Type.(type) {
+			case *ast.List_Ident:
+				typ = w.Ident
+			case *ast.List_List:
+				typ = w.List
+			case *ast.List_NonNull:
+				typ = w.NonNull
+			}
+		case *ast.NonNull:
+			switch w := v.Type.(type) {
+			case *ast.NonNull_Ident:
+				typ = w.Ident
+			case *ast.NonNull_List:
+				typ = w.List
+			}
+		default:
+			return ""
+		}
+	}
+}
+
+// subgraphResolvers serve the SDL of a subgraph. Entities are left to be
+// resolved like any other field.
+//
+var subgraphResolvers = federation.Resolvers{
+	Service: "() => ({})",
+	SDL:     "() => ServiceSDL",
+}
+
+// writeServiceSDL writes the SDL served by the _service field of a subgraph.
+func (g *Generator) writeServiceSDL(opts *Options, sdl string) {
+	b, _ := json.Marshal(sdl)
+
+	g.Write(opts.declStr)
+	g.WriteString(" ServiceSDL")
+	g.WriteString(g.annotate("string"))
+	g.WriteString(" = ")
+	g.Write(b)
+	g.WriteByte(';')
+	g.WriteByte('\n')
+	g.WriteByte('\n')
+}
+
+// writePosition writes a comment with the source file and line of a token,
+// if its position is known.
+//
+func (g *Generator) writePosition(ctx gen.PositionContext, pos int64) {
+	if ctx == nil || pos <= 0 {
+		return
+	}
+
+	p := ctx.Position(token.Pos(pos))
+	if !p.IsValid() {
 		return
 	}
 
-	// Write module import statement
-	g.log.Info("writing module import statement")
-	gOpts.setImports(mask)
-	_, err = g.writeImports(jsFile, gOpts)
-	if err != nil {
-		return
+	g.WriteString("// defined in ")
+	g.WriteString(filepath.Base(p.Filename))
+	g.WriteByte(':')
+	g.WriteString(strconv.Itoa(p.Line))
+	g.WriteByte('\n')
+}
+
+// dtsTypes maps a type declaration to its graphql-js class.
+var dtsTypes = []struct {
+	bit uint16
+	typ string
+}{
+	{bit: schemaBit, typ: "GraphQLSchema"},
+	{bit: scalarBit, typ: "GraphQLScalarType"},
+	{bit: objectBit, typ: "GraphQLObjectType"},
+	{bit: interfaceBit, typ: "GraphQLInterfaceType"},
+	{bit: unionBit, typ: "GraphQLUnionType"},
+	{bit: enumBit, typ: "GraphQLEnumType"},
+	{bit: inputObjectBit, typ: "GraphQLInputObjectType"},
+	{bit: directiveBit, typ: "GraphQLDirective"},
+}
+
+func dtsType(ts *ast.TypeSpec) uint16 {
+	switch ts.Type.(type) {
+	case *ast.TypeSpec_Scalar:
+		return scalarBit
+	case *ast.TypeSpec_Object:
+		return objectBit
+	case *ast.TypeSpec_Interface:
+		return interfaceBit
+	case *ast.TypeSpec_Union:
+		return unionBit
+	case *ast.TypeSpec_Enum:
+		return enumBit
+	case *ast.TypeSpec_Input:
+		return inputObjectBit
+	case *ast.TypeSpec_Directive:
+		return directiveBit
+	}
+	return 0
+}
+
+// writeDts writes a TypeScript declaration file describing the
+// generated GraphQL*Type constants and schema.
+//
+func writeDts(w io.Writer, doc *ast.Document) (int64, error) {
+	var used uint16
+	var decls bytes.Buffer
+
+	if doc.Schema != nil {
+		used |= schemaBit
+		decls.WriteString("\nexport declare const Schema: GraphQLSchema;\n")
+	}
+
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+
+		bit := dtsType(ts.TypeSpec)
+		if bit == 0 {
+			continue
+		}
+		used |= bit
+
+		for _, t := range dtsTypes {
+			if t.bit != bit {
+				continue
+			}
+
+			fmt.Fprintf(&decls, "\nexport declare const %sType: %s;\n", ts.TypeSpec.Name.Name, t.typ)
+			break
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("import {")
+	for _, t := range dtsTypes {
+		if used&t.bit == 0 {
+			continue
+		}
+
+		if b.Len() > len("import {") {
+			b.WriteByte(',')
+		}
+		b.WriteString("\n  ")
+		b.WriteString(t.typ)
 	}
+	b.WriteString("\n} from 'graphql';\n")
+	decls.WriteTo(&b)
 
-	// Write generated output
-	_, err = g.WriteTo(jsFile)
-	return
+	return b.WriteTo(w)
 }
 
 var (
@@ -233,6 +1209,7 @@ var (
 	commonJSImport = []byte("= require('graphql');")
 
 	es6Decl       = []byte("let")
+	es6ExportDecl = []byte("export const")
 	es6ImportDecl = []byte("import")
 	es6Import     = []byte("from 'graphql';")
 
@@ -288,36 +1265,53 @@ func (g *Generator) writeImports(w io.Writer, opts *Options) (int, error) {
 		b.Write(es6Import)
 	}
 	b.WriteByte('\n')
+	if g.resolveInfo {
+		b.WriteString(flowInfoImport)
+		b.WriteByte('\n')
+	}
 	b.WriteByte('\n')
 
 	return w.Write(b.Bytes())
 }
 
+// rootOps are the root operations a GraphQLSchema is configured with, in order.
+var rootOps = []string{"query", "mutation", "subscription"}
+
 func (g *Generator) generateSchema(opts *Options, ts *ast.TypeSpec) {
 	schema := ts.Type.(*ast.TypeSpec_Schema).Schema
 
-	var query, mutation *ast.Field
+	ops := make(map[string]*ast.Field, len(schema.RootOps.List))
 	for _, f := range schema.RootOps.List {
 		lname := strings.ToLower(f.Name.Name)
-		if lname == "query" {
-			query = f
-		}
-		if lname == "mutation" {
-			mutation = f
+		if isRootOp(lname) {
+			ops[lname] = f
 		}
 	}
 
-	g.P(opts.declStr, " Schema = new GraphQLSchema({")
+	g.P(opts.declStr, " Schema", g.annotate("GraphQLSchema"), " = new GraphQLSchema({")
 	g.In()
 
-	g.Write(g.indent)
-	g.WriteString("query: " + query.Type.(*ast.Field_Ident).Ident.Name)
+	first := true
+	for _, op := range rootOps {
+		f, ok := ops[op]
+		if !ok {
+			continue
+		}
+
+		if !first {
+			g.WriteByte(',')
+			g.WriteByte('\n')
+		}
+		first = false
+
+		g.WriteIndent()
+		g.WriteString(op + ": " + f.Type.(*ast.Field_Ident).Ident.Name + "Type")
+	}
 
-	if mutation != nil {
+	if dirs := appliedDirectives(ts.Directives); len(dirs) > 0 {
 		g.WriteByte(',')
 		g.WriteByte('\n')
-		g.Write(g.indent)
-		g.WriteString("mutation: " + mutation.Type.(*ast.Field_Ident).Ident.Name)
+		g.printSchemaDirectives(dirs)
 	}
 
 	g.Out()
@@ -325,19 +1319,101 @@ func (g *Generator) generateSchema(opts *Options, ts *ast.TypeSpec) {
 	g.P("});")
 }
 
+// printSchemaDirectives prints the directives applied to the schema as its
+// extensions, since graphql-js doesn't otherwise keep them. They're keyed
+// by name, to the args of each, or a list of them if it's repeated.
+//
+func (g *Generator) printSchemaDirectives(dirs []*ast.DirectiveLit) {
+	var names []string
+	applied := make(map[string][]*ast.DirectiveLit)
+	for _, d := range dirs {
+		if _, ok := applied[d.Name]; !ok {
+			names = append(names, d.Name)
+		}
+		applied[d.Name] = append(applied[d.Name], d)
+	}
+
+	g.WriteIndent()
+	g.WriteString("extensions: { directives: { ")
+	for i, name := range names {
+		if i > 0 {
+			g.WriteString(", ")
+		}
+		g.WriteString(name)
+		g.WriteString(": ")
+
+		ds := applied[name]
+		if len(ds) > 1 {
+			g.WriteByte('[')
+		}
+		for j, d := range ds {
+			if j > 0 {
+				g.WriteString(", ")
+			}
+			g.printDirectiveArgs(d)
+		}
+		if len(ds) > 1 {
+			g.WriteByte(']')
+		}
+	}
+	g.WriteString(" } }")
+}
+
+// appliedDirectives returns the directives, except for those of gqlc.
+func appliedDirectives(dirs []*ast.DirectiveLit) (applied []*ast.DirectiveLit) {
+	for _, d := range dirs {
+		if !types.IsGqlcDirective(d.Name) {
+			applied = append(applied, d)
+		}
+	}
+	return
+}
+
+func (g *Generator) printDirectiveArgs(d *ast.DirectiveLit) {
+	if d.Args == nil || len(d.Args.Args) == 0 {
+		g.WriteString("{}")
+		return
+	}
+
+	g.WriteString("{ ")
+	for i, a := range d.Args.Args {
+		if i > 0 {
+			g.WriteString(", ")
+		}
+		g.WriteString(a.Name.Name)
+		g.WriteString(": ")
+
+		switch v := a.Value.(type) {
+		case *ast.Arg_BasicLit:
+			g.printVal(v.BasicLit)
+		case *ast.Arg_CompositeLit:
+			g.printVal(v.CompositeLit)
+		}
+	}
+	g.WriteString(" }")
+}
+
+func isRootOp(name string) bool {
+	for _, op := range rootOps {
+		if op == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (g *Generator) generateScalar(imports *uint16, name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
 	g.P("GraphQLScalarType({")
 	g.In()
 	g.P("name: '", name, "',")
 
-	if doc != nil && descr {
-		text := doc.Text()
-		if len(text) > 0 {
-			g.P("description: '", text[:len(text)-1], "',")
-		}
+	if text := sdl.Description(doc); descr && text != "" {
+		g.P("description: ", jsString(text), ",")
 	}
 
-	g.P("serialize(value) { /* TODO */ }")
+	if !g.separate {
+		g.P("serialize", g.serializeParams(), " { /* TODO */ }")
+	}
 	g.Out()
 
 	g.P("});")
@@ -354,15 +1430,15 @@ func (g *Generator) generateObject(imports *uint16, name string, descr bool, doc
 	// Print interfaces
 	interLen := len(obj.Interfaces)
 	if interLen == 1 {
-		g.P("interfaces: [ ", obj.Interfaces[0].Name, " ],")
+		g.P("interfaces: ", g.arrow(), "[ ", obj.Interfaces[0].Name, "Type ],")
 	}
 	if interLen > 1 {
-		g.P("interfaces: [")
+		g.P("interfaces: ", g.arrow(), "[")
 		g.In()
 
 		str := []interface{}{"", ","}
 		for i, inter := range obj.Interfaces {
-			str[0] = inter.Name
+			str[0] = inter.Name + "Type"
 			if i == interLen-1 {
 				str[1] = ""
 			}
@@ -373,15 +1449,14 @@ func (g *Generator) generateObject(imports *uint16, name string, descr bool, doc
 		g.P("],")
 	}
 
-	g.P("fields: {")
-	g.In()
+	g.openFields()
 
 	g.generateFields(obj.Fields, imports, descr, true)
 
 	g.Out()
 
-	g.Write(g.indent)
-	g.WriteByte('}')
+	g.WriteIndent()
+	g.closeFields()
 
 	if doc != nil && descr {
 		g.printDescr(doc)
@@ -393,6 +1468,36 @@ func (g *Generator) generateObject(imports *uint16, name string, descr bool, doc
 	g.P("});")
 }
 
+// openFields opens the fields of a type, which are wrapped in a function
+// with thunks.
+//
+func (g *Generator) openFields() {
+	if g.thunks {
+		g.P("fields: () => ({")
+	} else {
+		g.P("fields: {")
+	}
+	g.In()
+}
+
+// closeFields closes the fields opened by openFields.
+func (g *Generator) closeFields() {
+	g.WriteByte('}')
+	if g.thunks {
+		g.WriteByte(')')
+	}
+}
+
+// arrow returns what starts a list of types, so it's wrapped in a function
+// with thunks.
+//
+func (g *Generator) arrow() string {
+	if g.thunks {
+		return "() => "
+	}
+	return ""
+}
+
 func (g *Generator) generateInterface(imports *uint16, name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
 	inter := ts.Type.(*ast.TypeSpec_Interface).Interface
 
@@ -401,15 +1506,14 @@ func (g *Generator) generateInterface(imports *uint16, name string, descr bool,
 
 	g.P("name: '", name, "',")
 
-	g.P("fields: {")
-	g.In()
+	g.openFields()
 
 	g.generateFields(inter.Fields, imports, descr, false)
 
 	g.Out()
 
-	g.Write(g.indent)
-	g.WriteByte('}')
+	g.WriteIndent()
+	g.closeFields()
 
 	if doc != nil && descr {
 		g.printDescr(doc)
@@ -427,7 +1531,7 @@ func (g *Generator) generateFields(fields *ast.FieldList, imports *uint16, descr
 		g.P(f.Name.Name, ": {")
 		g.In()
 
-		g.Write(g.indent)
+		g.WriteIndent()
 		g.WriteString("type: ")
 
 		var fieldType interface{}
@@ -451,26 +1555,34 @@ func (g *Generator) generateFields(fields *ast.FieldList, imports *uint16, descr
 			g.generateArgs(f.Args.List, imports, descr)
 
 			g.Out()
-			g.Write(g.indent)
+			g.WriteIndent()
 			g.WriteByte('}')
 		}
 
-		if resolve {
+		if resolver := getResolver(f.Directives); resolve && (resolver != "" || !g.separate) {
 			g.WriteByte(',')
 			g.WriteByte('\n')
 
-			g.Write(g.indent)
-			g.WriteString("resolve() { /* TODO */ }")
+			g.WriteIndent()
+			if resolver != "" {
+				g.WriteString("resolve: ")
+				g.WriteString(resolver)
+			} else {
+				g.WriteString("resolve")
+				g.WriteString(g.resolveParams())
+				g.WriteString(" { /* TODO */ }")
+			}
 		}
 
 		if descr {
 			g.printDescr(f.Doc)
 		}
+		g.printDeprecation(f.Directives)
 
 		g.WriteByte('\n')
 
 		g.Out()
-		g.Write(g.indent)
+		g.WriteIndent()
 		g.WriteByte('}')
 		if i != fLen-1 {
 			g.WriteByte(',')
@@ -490,10 +1602,10 @@ func (g *Generator) generateUnion(imports *uint16, name string, descr bool, doc
 	// Print members
 	memsLen := len(union.Members)
 	if memsLen == 1 {
-		g.P("types: [ ", union.Members[0], " ],")
+		g.P("types: ", g.arrow(), "[ ", union.Members[0].Name, "Type ],")
 	}
 	if memsLen > 1 {
-		g.P("types: [")
+		g.P("types: ", g.arrow(), "[")
 		g.In()
 
 		sep := ","
@@ -501,15 +1613,27 @@ func (g *Generator) generateUnion(imports *uint16, name string, descr bool, doc
 			if i == memsLen-1 {
 				sep = ""
 			}
-			g.P(mem.Name, sep)
+			g.P(mem.Name, "Type", sep)
 		}
 
 		g.Out()
 		g.P("],")
 	}
 
-	g.Write(g.indent)
-	g.WriteString("resolveType(value) { /* TODO */ }")
+	if g.separate {
+		if text := sdl.Description(doc); descr && text != "" {
+			g.P("description: ", jsString(text))
+		}
+
+		g.Out()
+		g.P("});")
+		return
+	}
+
+	g.WriteIndent()
+	g.WriteString("resolveType")
+	g.WriteString(g.resolveTypeParams())
+	g.WriteString(" { /* TODO */ }")
 
 	if doc != nil && descr {
 		g.printDescr(doc)
@@ -529,11 +1653,8 @@ func (g *Generator) generateEnum(imports *uint16, name string, descr bool, doc *
 
 	g.P("name: '", name, "',")
 
-	if doc != nil && descr {
-		text := doc.Text()
-		if len(text) > 0 {
-			g.P("description: '", text[:len(text)-1], "',")
-		}
+	if text := sdl.Description(doc); descr && text != "" {
+		g.P("description: ", jsString(text), ",")
 	}
 
 	g.P("values: {")
@@ -544,21 +1665,21 @@ func (g *Generator) generateEnum(imports *uint16, name string, descr bool, doc *
 		g.P(v.Name.Name, ": {")
 		g.In()
 
-		g.Write(g.indent)
+		g.WriteIndent()
 		g.WriteString("value: '")
 		g.WriteString(v.Name.Name)
 		g.WriteByte('\'')
 
 		if descr {
 			g.printDescr(v.Doc)
-
 		}
+		g.printDeprecation(v.Directives)
 
 		g.WriteByte('\n')
 
 		g.Out()
 
-		g.Write(g.indent)
+		g.WriteIndent()
 		g.WriteByte('}')
 		if i != valsLen-1 {
 			g.WriteByte(',')
@@ -581,14 +1702,13 @@ func (g *Generator) generateInput(imports *uint16, name string, descr bool, doc
 
 	g.P("name: '", name, "',")
 
-	g.P("fields: {")
-	g.In()
+	g.openFields()
 
 	g.generateArgs(input.Fields.List, imports, descr)
 
 	g.Out()
-	g.Write(g.indent)
-	g.WriteByte('}')
+	g.WriteIndent()
+	g.closeFields()
 
 	if doc != nil && descr {
 		g.printDescr(doc)
@@ -609,11 +1729,8 @@ func (g *Generator) generateDirective(imports *uint16, name string, descr bool,
 
 	g.P("name: '", name, "',")
 
-	if doc != nil && descr {
-		text := doc.Text()
-		if len(text) > 0 {
-			g.P("description: '", text[:len(text)-1], "',")
-		}
+	if text := sdl.Description(doc); descr && text != "" {
+		g.P("description: ", jsString(text), ",")
 	}
 
 	// Print locations
@@ -634,7 +1751,7 @@ func (g *Generator) generateDirective(imports *uint16, name string, descr bool,
 		}
 
 		g.Out()
-		g.Write(g.indent)
+		g.WriteIndent()
 		g.WriteByte(']')
 	}
 
@@ -649,7 +1766,7 @@ func (g *Generator) generateDirective(imports *uint16, name string, descr bool,
 		g.generateArgs(directive.Args.List, imports, descr)
 
 		g.Out()
-		g.Write(g.indent)
+		g.WriteIndent()
 		g.WriteByte('}')
 	}
 
@@ -664,7 +1781,7 @@ func (g *Generator) generateArgs(args []*ast.InputValue, imports *uint16, descr
 		g.P(a.Name.Name, ": {")
 		g.In()
 
-		g.Write(g.indent)
+		g.WriteIndent()
 		g.WriteString("type: ")
 
 		var fieldType interface{}
@@ -682,7 +1799,7 @@ func (g *Generator) generateArgs(args []*ast.InputValue, imports *uint16, descr
 			g.WriteByte(',')
 			g.WriteByte('\n')
 
-			g.Write(g.indent)
+			g.WriteIndent()
 			g.WriteString("defaultValue: ")
 
 			var defType interface{}
@@ -703,7 +1820,7 @@ func (g *Generator) generateArgs(args []*ast.InputValue, imports *uint16, descr
 
 		g.Out()
 
-		g.Write(g.indent)
+		g.WriteIndent()
 		g.WriteByte('}')
 		if i != aLen {
 			g.WriteByte(',')
@@ -713,18 +1830,78 @@ func (g *Generator) generateArgs(args []*ast.InputValue, imports *uint16, descr
 }
 
 func (g *Generator) printDescr(doc *ast.DocGroup) {
-	text := doc.Text()
-	if len(text) > 0 {
+	text := sdl.Description(doc)
+	if text != "" {
 		g.WriteByte(',')
 		g.WriteByte('\n')
 
-		g.Write(g.indent)
-		g.WriteString("description: '")
+		g.WriteIndent()
+		g.WriteString("description: ")
+		g.WriteString(jsString(text))
+	}
+}
+
+// printDeprecation prints the deprecationReason of a field or enum value,
+// if it's marked @deprecated.
+//
+func (g *Generator) printDeprecation(dirs []*ast.DirectiveLit) {
+	reason, ok := getDeprecation(dirs)
+	if !ok {
+		return
+	}
 
-		g.WriteString(text[:len(text)-1])
+	g.WriteByte(',')
+	g.WriteByte('\n')
 
-		g.WriteByte('\'')
+	g.WriteIndent()
+	g.WriteString("deprecationReason: ")
+	g.WriteString(jsString(reason))
+}
+
+// jsString returns s as a Javascript string literal. Multi-line strings
+// become template literals, so descriptions keep their line breaks in the
+// generated code.
+//
+func jsString(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+
+	multiline := strings.Contains(s, "\n")
+	if multiline {
+		b.WriteByte('`')
+	} else {
+		b.WriteByte('\'')
+	}
+
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteByte('\n')
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\'' && !multiline:
+			b.WriteString(`\'`)
+		case r == '`' && multiline:
+			b.WriteString("\\`")
+		case r == '$' && multiline && strings.HasPrefix(s[i+1:], "{"):
+			b.WriteString(`\$`)
+		case r < ' ', r == '\u2028', r == '\u2029':
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
 	}
+
+	if multiline {
+		b.WriteByte('`')
+	} else {
+		b.WriteByte('\'')
+	}
+	return b.String()
 }
 
 // printType prints a field type
@@ -749,6 +1926,8 @@ func (g *Generator) printType(imports *uint16, typ interface{}) {
 		case "ID":
 			name = "GraphQLID"
 			*imports &= ^idBit
+		default:
+			name += "Type"
 		}
 
 		g.WriteString(name)
@@ -861,36 +2040,12 @@ func (g *Generator) printObject(v *ast.ObjLit) {
 	g.WriteByte('}')
 }
 
-// P prints the arguments to the generated output.
-func (g *Generator) P(str ...interface{}) {
-	g.Write(g.indent)
-	for _, s := range str {
-		switch v := s.(type) {
-		case []byte:
-			g.Write(v)
-		case string:
-			g.WriteString(v)
-		case bool:
-			fmt.Fprint(g, v)
-		case int:
-			fmt.Fprint(g, v)
-		case float64:
-			fmt.Fprint(g, v)
-		}
-	}
-	g.WriteByte('\n')
-}
-
-// In increases the indent.
+// In increases the indent by two spaces, which the style replaces with its
+// own indentation.
+//
 func (g *Generator) In() {
-	g.indent = append(g.indent, ' ', ' ')
-}
-
-// Out decreases the indent.
-func (g *Generator) Out() {
-	if len(g.indent) > 0 {
-		g.indent = g.indent[:len(g.indent)-2]
-	}
+	g.Indent = "  "
+	g.Printer.In()
 }
 
 // getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
@@ -898,9 +2053,16 @@ func (g *Generator) Out() {
 //
 func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
 	gOpts = &Options{
-		Module:  "COMMONJS",
-		declStr: commonJSDecl,
-		imports: make([][]byte, 0, 15),
+		Module:    "COMMONJS",
+		Resolvers: "inline",
+		Style:     "graphql",
+		Extension: "js",
+		Target:    "node",
+		Order:     "source",
+		Indent:    "2",
+		Quotes:    "single",
+		declStr:   commonJSDecl,
+		imports:   make([][]byte, 0, 15),
 	}
 
 	// Extract document directive options
@@ -923,6 +2085,13 @@ func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options,
 				if v == "true" {
 					gOpts.UseFlow = true
 				}
+			case "flowTypes":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.FlowTypes = b
 			case "descriptions":
 				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
 				if err != nil {
@@ -930,6 +2099,68 @@ func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options,
 				}
 
 				gOpts.Descriptions = b
+			case "dts":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Dts = b
+			case "positions":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Positions = b
+			case "federation":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Federation = b
+			case "filePerType":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.FilePerType = b
+			case "resolvers":
+				gOpts.Resolvers = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, "\"")
+			case "framework":
+				gOpts.Framework = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, "\"")
+			case "order":
+				gOpts.Order = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, "\"")
+			case "thunks":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Thunks = b
+			case "style":
+				gOpts.Style = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, "\"")
+			case "scalars":
+				if err = gOpts.parseScalars(stringList(arg.Val)...); err != nil {
+					return
+				}
+			case "namedExports":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.NamedExports = b
+			case "extension":
+				gOpts.Extension = strings.TrimPrefix(strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, "\""), ".")
+			case "target":
+				gOpts.Target = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, "\"")
+			case "indent":
+				gOpts.Indent = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, "\"")
+			case "quotes":
+				gOpts.Quotes = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, "\"")
 			}
 		}
 	}
@@ -947,10 +2178,246 @@ func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options,
 	if u, ok := opts["useFlow"]; ok {
 		gOpts.UseFlow, _ = u.(bool)
 	}
+	if f, ok := opts["flowTypes"]; ok {
+		gOpts.FlowTypes, _ = f.(bool)
+	}
+	if d, ok := opts["dts"]; ok {
+		gOpts.Dts, _ = d.(bool)
+	}
+	if p, ok := opts["positions"]; ok {
+		gOpts.Positions, _ = p.(bool)
+	}
+	if f, ok := opts["federation"]; ok {
+		gOpts.Federation, _ = f.(bool)
+	}
+	if f, ok := opts["filePerType"]; ok {
+		gOpts.FilePerType, _ = f.(bool)
+	}
+	if r, ok := opts["resolvers"]; ok {
+		gOpts.Resolvers, _ = r.(string)
+	}
+
+	if f, ok := opts["framework"]; ok {
+		gOpts.Framework, _ = f.(string)
+	}
+	if o, ok := opts["order"]; ok {
+		gOpts.Order, _ = o.(string)
+	}
+	if t, ok := opts["thunks"]; ok {
+		gOpts.Thunks, _ = t.(bool)
+	}
+	if s, ok := opts["style"]; ok {
+		gOpts.Style, _ = s.(string)
+	}
+	if s, ok := opts["scalars"]; ok {
+		switch v := s.(type) {
+		case string:
+			err = gOpts.parseScalars(v)
+		case []string:
+			err = gOpts.parseScalars(v...)
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	if n, ok := opts["namedExports"]; ok {
+		gOpts.NamedExports, _ = n.(bool)
+	}
+	if e, ok := opts["extension"]; ok {
+		e, _ := e.(string)
+		gOpts.Extension = strings.TrimPrefix(strings.Trim(e, `"`), ".")
+	}
+	if t, ok := opts["target"]; ok {
+		t, _ := t.(string)
+		gOpts.Target = strings.Trim(t, `"`)
+	}
+	if i, ok := opts["indent"]; ok {
+		switch v := i.(type) {
+		case int64:
+			gOpts.Indent = strconv.FormatInt(v, 10)
+		case string:
+			gOpts.Indent = strings.Trim(v, `"`)
+		}
+	}
+	if q, ok := opts["quotes"]; ok {
+		q, _ := q.(string)
+		gOpts.Quotes = strings.Trim(q, `"`)
+	}
+
+	if gOpts.FlowTypes {
+		gOpts.UseFlow = true
+	}
+
+	switch gOpts.Resolvers {
+	case "inline", "separate":
+	default:
+		return gOpts, fmt.Errorf("unknown resolvers option: %s", gOpts.Resolvers)
+	}
+
+	switch gOpts.Framework {
+	case "", "apollo":
+	default:
+		return gOpts, fmt.Errorf("unknown framework option: %s", gOpts.Framework)
+	}
+
+	switch gOpts.Style {
+	case "graphql":
+	case "nexus", "sdl":
+		if err = gOpts.checkStyle(); err != nil {
+			return
+		}
+	default:
+		return gOpts, fmt.Errorf("unknown style option: %s", gOpts.Style)
+	}
+
+	if gOpts.style, err = parseStyle(gOpts.Indent, gOpts.Quotes); err != nil {
+		return
+	}
+
+	switch gOpts.Order {
+	case "source", "alpha", "topo":
+	default:
+		return gOpts, fmt.Errorf("unknown order option: %s", gOpts.Order)
+	}
+
+	switch gOpts.Extension {
+	case "js", "mjs", "cjs":
+	default:
+		return gOpts, fmt.Errorf("unknown extension option: %s", gOpts.Extension)
+	}
+
+	switch gOpts.Target {
+	case "node", "bundler":
+	default:
+		return gOpts, fmt.Errorf("unknown target option: %s", gOpts.Target)
+	}
 
-	if gOpts.Module == "ES6" {
+	switch {
+	case gOpts.NamedExports && gOpts.Module != "ES6":
+		return gOpts, fmt.Errorf("the namedExports option needs an ES6 module")
+	case gOpts.NamedExports:
+		gOpts.declStr = es6ExportDecl
+	case gOpts.Module == "ES6":
 		gOpts.declStr = es6Decl
 	}
 
+	// Types declared with let or const can't be used before they're
+	// initialized, so they refer to those declared after them with thunks,
+	// as do types in their own modules, which may import each other.
+	//
+	if gOpts.Module == "ES6" || gOpts.FilePerType {
+		gOpts.Thunks = true
+	}
+
+	return
+}
+
+// ext returns the file extension of the generated modules.
+func (o *Options) ext() string { return "." + o.Extension }
+
+// dtsExt returns the file extension of the TypeScript declarations for
+// the generated modules e.g. .d.mts for .mjs.
+//
+func (o *Options) dtsExt() string {
+	if o.Extension == "js" {
+		return ".d.ts"
+	}
+	return ".d." + o.Extension[:1] + "ts"
+}
+
+// specifier returns how a generated module is imported from another.
+// Node resolves ES6 imports by their exact path, and so are requires of
+// anything other than .js, whereas bundlers resolve the extension.
+//
+func (o *Options) specifier(mod string) string {
+	if o.Target == "bundler" {
+		return mod
+	}
+	if o.Module == "ES6" || o.Extension != "js" {
+		return mod + o.ext()
+	}
+	return mod
+}
+
+// checkStyle returns an error if an option can't be used with the style,
+// since only the graphql style declares a constant for every type.
+//
+func (o *Options) checkStyle() error {
+	var opt string
+	switch {
+	case o.Dts:
+		opt = "dts"
+	case o.Federation:
+		opt = "federation"
+	case o.FilePerType:
+		opt = "filePerType"
+	case o.Resolvers == "separate" && o.Style == "nexus":
+		opt = "resolvers=separate"
+	case o.Framework != "":
+		opt = "framework"
+	case o.FlowTypes:
+		opt = "flowTypes"
+	default:
+		return nil
+	}
+	return fmt.Errorf("the %s style doesn't support the %s option", o.Style, opt)
+}
+
+// stringList returns the strings of a String or [String] value.
+func stringList(val *ast.CompositeLit) (strs []string) {
+	switch v := val.Value.(type) {
+	case *ast.CompositeLit_BasicLit:
+		strs = append(strs, v.BasicLit.Value)
+	case *ast.CompositeLit_ListLit:
+		switch list := v.ListLit.List.(type) {
+		case *ast.ListLit_BasicList:
+			for _, lit := range list.BasicList.Values {
+				strs = append(strs, lit.Value)
+			}
+		case *ast.ListLit_CompositeList:
+			for _, lit := range list.CompositeList.Values {
+				if b, ok := lit.Value.(*ast.CompositeLit_BasicLit); ok {
+					strs = append(strs, b.BasicLit.Value)
+				}
+			}
+		}
+	}
 	return
 }
+
+// defaultDeprecationReason is the reason given by GraphQL to a @deprecated
+// without one.
+//
+const defaultDeprecationReason = "No longer supported"
+
+// getDeprecation returns the reason given to @deprecated, if it's in dirs.
+func getDeprecation(dirs []*ast.DirectiveLit) (string, bool) {
+	for _, d := range dirs {
+		if d.Name != "deprecated" {
+			continue
+		}
+
+		if d.Args != nil {
+			for _, a := range d.Args.Args {
+				lit, ok := a.Value.(*ast.Arg_BasicLit)
+				if a.Name.Name == "reason" && ok {
+					return sdl.StringValue(lit.BasicLit.Value), true
+				}
+			}
+		}
+		return defaultDeprecationReason, true
+	}
+	return "", false
+}
+
+func getResolver(dirs []*ast.DirectiveLit) string {
+	for _, d := range dirs {
+		if d.Name != "resolver" {
+			continue
+		}
+
+		return strings.Trim(d.Args.Args[0].Value.(*ast.Arg_BasicLit).BasicLit.Value, "\"")
+	}
+	return ""
+}
@@ -9,10 +9,13 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/gqlc/gqlc/diag"
 	"github.com/gqlc/gqlc/gen"
 	"github.com/gqlc/graphql/ast"
 	"github.com/gqlc/graphql/parser"
@@ -160,8 +163,6 @@ func TestSchema(t *testing.T) {
 	g := &Generator{}
 
 	t.Run("WithoutMutation", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{
@@ -177,7 +178,7 @@ func TestSchema(t *testing.T) {
 		g.generateSchema(&Options{Module: "COMMONJS", declStr: commonJSDecl}, ts)
 
 		ex := []byte(`var Schema = new GraphQLSchema({
-  query: Query
+  query: QueryType
 });
 `)
 
@@ -185,8 +186,6 @@ func TestSchema(t *testing.T) {
 	})
 
 	t.Run("WithMutation", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{
@@ -203,14 +202,118 @@ func TestSchema(t *testing.T) {
 		g.generateSchema(&Options{Module: "COMMONJS", declStr: commonJSDecl}, ts)
 
 		ex := []byte(`var Schema = new GraphQLSchema({
-  query: Query,
-  mutation: Mutation
+  query: QueryType,
+  mutation: MutationType
+});
+`)
+
+		gen.CompareBytes(subT, ex, g.Bytes())
+	})
+
+	t.Run("WithSubscription", func(subT *testing.T) {
+		g.Reset()
+
+		ts := &ast.TypeSpec{
+			Type: &ast.TypeSpec_Schema{
+				Schema: &ast.SchemaType{
+					RootOps: &ast.FieldList{List: []*ast.Field{
+						{Name: &ast.Ident{Name: "subscription"}, Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "Subscription"}}},
+						{Name: &ast.Ident{Name: "unknown"}, Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "Unknown"}}},
+						{Name: &ast.Ident{Name: "query"}, Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "Query"}}},
+						{Name: &ast.Ident{Name: "mutation"}, Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "Mutation"}}},
+					}},
+				},
+			},
+		}
+
+		g.generateSchema(&Options{Module: "COMMONJS", declStr: commonJSDecl}, ts)
+
+		ex := []byte(`var Schema = new GraphQLSchema({
+  query: QueryType,
+  mutation: MutationType,
+  subscription: SubscriptionType
+});
+`)
+
+		gen.CompareBytes(subT, ex, g.Bytes())
+	})
+
+	t.Run("WithDirectives", func(subT *testing.T) {
+		g.Reset()
+
+		url := &ast.Arg{
+			Name:  &ast.Ident{Name: "url"},
+			Value: &ast.Arg_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: `"https://specs.apollo.dev/federation/v2.0"`}},
+		}
+		ts := &ast.TypeSpec{
+			Type: &ast.TypeSpec_Schema{
+				Schema: &ast.SchemaType{
+					RootOps: &ast.FieldList{List: []*ast.Field{
+						{Name: &ast.Ident{Name: "query"}, Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "Query"}}},
+					}},
+				},
+			},
+			Directives: []*ast.DirectiveLit{
+				{Name: "link", Args: &ast.CallExpr{Args: []*ast.Arg{url}}},
+				{Name: "tag", Args: &ast.CallExpr{Args: []*ast.Arg{{Name: &ast.Ident{Name: "name"}, Value: &ast.Arg_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: `"a"`}}}}}},
+				{Name: "tag", Args: &ast.CallExpr{Args: []*ast.Arg{{Name: &ast.Ident{Name: "name"}, Value: &ast.Arg_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: `"b"`}}}}}},
+				{Name: "public"},
+			},
+		}
+
+		g.generateSchema(&Options{Module: "COMMONJS", declStr: commonJSDecl}, ts)
+
+		ex := []byte(`var Schema = new GraphQLSchema({
+  query: QueryType,
+  extensions: { directives: { link: { url: 'https://specs.apollo.dev/federation/v2.0' }, tag: [{ name: 'a' }, { name: 'b' }], public: {} } }
 });
 `)
 
 		gen.CompareBytes(subT, ex, g.Bytes())
 	})
+	t.Run("Evaluate", func(subT *testing.T) {
+		doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(`schema {
+	query: Query
+	mutation: Mutation
+	subscription: Subscription
+}
+
+type Query {
+	hello: String
+}
+
+type Mutation {
+	greet(name: String!): String
+}
+
+type Subscription {
+	greetings: String
+}`), 0)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err = new(Generator).Generate(ctx, doc, nil)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		out := b.String()
+		if !strings.HasSuffix(out, `var Schema = new GraphQLSchema({
+  query: QueryType,
+  mutation: MutationType,
+  subscription: SubscriptionType
+});
+`) {
+			subT.Errorf("expected the schema to be declared after the root operation types, but got:\n%s", out)
+		}
 
+		evalJS(subT, map[string]*bytes.Buffer{"test.js": &b}, "test.js")
+	})
 }
 
 func TestScalar(t *testing.T) {
@@ -235,8 +338,6 @@ func TestObject(t *testing.T) {
 	g := &Generator{}
 
 	t.Run("JustFields", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Object{
@@ -298,7 +399,7 @@ func TestObject(t *testing.T) {
       resolve() { /* TODO */ }
     },
     list: {
-      type: new GraphQLList(Test),
+      type: new GraphQLList(TestType),
       resolve() { /* TODO */ }
     },
     withDefaultVal: {
@@ -315,7 +416,7 @@ func TestObject(t *testing.T) {
       type: GraphQLString,
       args: {
         val: {
-          type: TestEnum,
+          type: TestEnumType,
           defaultValue: 'A_ENUM_VALUE'
         }
       },
@@ -329,8 +430,6 @@ func TestObject(t *testing.T) {
 	})
 
 	t.Run("WithInterfaces", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Object{
@@ -360,8 +459,8 @@ func TestObject(t *testing.T) {
 		ex := []byte(`GraphQLObjectType({
   name: 'Test',
   interfaces: [
-    A,
-    B
+    AType,
+    BType
   ],
   fields: {
     one: {
@@ -373,7 +472,7 @@ func TestObject(t *testing.T) {
       resolve() { /* TODO */ }
     },
     list: {
-      type: new GraphQLList(Test),
+      type: new GraphQLList(TestType),
       resolve() { /* TODO */ }
     }
   }
@@ -420,7 +519,7 @@ func TestInterface(t *testing.T) {
       type: GraphQLString
     },
     list: {
-      type: new GraphQLList(Test)
+      type: new GraphQLList(TestType)
     }
   }
 });
@@ -443,8 +542,8 @@ func TestUnion(t *testing.T) {
 	ex := []byte(`GraphQLUnionType({
   name: 'Test',
   types: [
-    A,
-    B
+    AType,
+    BType
   ],
   resolveType(value) { /* TODO */ }
 });
@@ -493,8 +592,6 @@ func TestInput(t *testing.T) {
 	g := &Generator{}
 
 	t.Run("NoDefaults", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Input{
@@ -530,7 +627,7 @@ func TestInput(t *testing.T) {
       type: GraphQLString
     },
     list: {
-      type: new GraphQLList(Test)
+      type: new GraphQLList(TestType)
     }
   }
 });
@@ -540,8 +637,6 @@ func TestInput(t *testing.T) {
 	})
 
 	t.Run("WithDefaults", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Input{
@@ -617,8 +712,6 @@ func TestDirective(t *testing.T) {
 	g := &Generator{}
 
 	t.Run("NoArgs", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Directive{
@@ -647,8 +740,6 @@ func TestDirective(t *testing.T) {
 	})
 
 	t.Run("WithArgs", func(subT *testing.T) {
-		g.Lock()
-		defer g.Unlock()
 		g.Reset()
 
 		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Directive{
@@ -718,91 +809,2213 @@ func TestDirective(t *testing.T) {
 	})
 }
 
-func TestGenerator_Generate(t *testing.T) {
-	g := &Generator{}
+func TestDts(t *testing.T) {
+	gqlSrc := `schema {
+	query: Query
+}
 
-	var b bytes.Buffer
-	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
-	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+scalar Time
+
+type Query {
+	now: Time
+}
+
+directive @test on FIELD`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), 0)
 	if err != nil {
 		t.Error(err)
 		return
 	}
 
-	ex, err := ioutil.ReadAll(exDoc)
+	var b bytes.Buffer
+	_, err = writeDts(&b, doc)
 	if err != nil {
 		t.Error(err)
 		return
 	}
 
+	ex := []byte(`import {
+  GraphQLSchema,
+  GraphQLScalarType,
+  GraphQLObjectType,
+  GraphQLDirective
+} from 'graphql';
+
+export declare const Schema: GraphQLSchema;
+
+export declare const TimeType: GraphQLScalarType;
+
+export declare const QueryType: GraphQLObjectType;
+
+export declare const testType: GraphQLDirective;
+`)
+
 	gen.CompareBytes(t, ex, b.Bytes())
 }
 
-func BenchmarkGenerator_Generate(b *testing.B) {
-	g := &Generator{}
+type posCtx struct {
+	gen.TestCtx
 
-	var buf bytes.Buffer
-	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+	dset *token.DocSet
+}
 
-	for i := 0; i < b.N; i++ {
-		buf.Reset()
+func (ctx posCtx) Position(pos token.Pos) token.Position { return ctx.dset.Position(pos) }
 
-		err := g.Generate(ctx, testDoc, nil)
+func TestPositions(t *testing.T) {
+	gqlSrc := `schema {
+	query: Query
+}
+
+"Time is a timestamp."
+scalar Time
+
+type Query {
+	now: Time
+}`
+
+	dset := token.NewDocSet()
+	doc, err := parser.ParseDoc(dset, "schema.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), posCtx{TestCtx: gen.TestCtx{Writer: &b}, dset: dset})
+	err = new(Generator).Generate(ctx, doc, map[string]interface{}{"positions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, ex := range []string{
+		"// defined in schema.gql:1\nvar Schema = new GraphQLSchema({",
+		"// defined in schema.gql:6\nvar TimeType = new GraphQLScalarType({",
+		"// defined in schema.gql:8\nvar QueryType = new GraphQLObjectType({",
+	} {
+		if !strings.Contains(b.String(), ex) {
+			t.Errorf("expected output to contain:\n%s\nbut got:\n%s", ex, b.String())
+		}
+	}
+
+	t.Run("UnknownPositions", func(subT *testing.T) {
+		b.Reset()
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err = new(Generator).Generate(ctx, doc, map[string]interface{}{"positions": true})
 		if err != nil {
-			b.Error(err)
+			subT.Error(err)
 			return
 		}
+
+		if strings.Contains(b.String(), "// defined in") {
+			subT.Errorf("expected no position comments, but got:\n%s", b.String())
+		}
+	})
+}
+
+func TestFederation(t *testing.T) {
+	gqlSrc := `type Query {
+	me: User
+}
+
+type User @key(fields: "id") {
+	id: ID!
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err = new(Generator).Generate(ctx, doc, map[string]interface{}{"federation": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, ex := range []string{
+		"var ServiceSDL = \"extend schema @link(",
+		"_service: {\n      type: new GraphQLNonNull(_ServiceType),\n      resolve: () => ({})\n    }",
+		"types: [ UserType ],",
+		"sdl: {\n      type: GraphQLString,\n      resolve: () => ServiceSDL\n    }",
+	} {
+		if !strings.Contains(b.String(), ex) {
+			t.Errorf("expected output to contain:\n%s\nbut got:\n%s", ex, b.String())
+		}
 	}
 }
 
-func ExampleGenerator_Generate() {
-	g := new(Generator)
+type filesCtx struct {
+	gen.TestCtx
+
+	files map[string]*bytes.Buffer
+}
+
+func (ctx *filesCtx) Open(name string) (io.WriteCloser, error) {
+	b := new(bytes.Buffer)
+	ctx.files[filepath.ToSlash(name)] = b
+	return gen.TestCtx{Writer: b}, nil
+}
+
+// graphqlStub stands in for graphql-js when generated modules are evaluated.
+// Its schema resolves every type it refers to, calling thunks like
+// graphql-js does, and fails on those which are undefined, since they were
+// used before they were declared.
+//
+const graphqlStub = `'use strict';
+
+function thunk(v) {
+  return typeof v === 'function' ? v() : v;
+}
+
+function defined(type, where) {
+  if (type === undefined || type === null) {
+    throw new Error(where + ' is undefined');
+  }
+  return type;
+}
+
+class GraphQLNamedType {
+  constructor(config) {
+    this.name = config.name;
+    this.config = config;
+  }
+}
+
+class GraphQLScalarType extends GraphQLNamedType {}
+class GraphQLObjectType extends GraphQLNamedType {
+  getFields() {
+    if (!this.fields) {
+      this.fields = thunk(this.config.fields);
+    }
+    return this.fields;
+  }
+}
+
+class GraphQLInterfaceType extends GraphQLObjectType {}
+class GraphQLUnionType extends GraphQLNamedType {}
+class GraphQLEnumType extends GraphQLNamedType {}
+class GraphQLInputObjectType extends GraphQLNamedType {}
+class GraphQLDirectiveType extends GraphQLNamedType {}
 
+class GraphQLList {
+  constructor(ofType) {
+    this.ofType = defined(ofType, 'list item type');
+  }
+}
+
+class GraphQLNonNull {
+  constructor(ofType) {
+    this.ofType = defined(ofType, 'non-null type');
+  }
+}
+
+class GraphQLSchema {
+  constructor(config) {
+    const seen = new Set();
+    const visit = (type, where) => {
+      defined(type, where);
+      if (type.ofType) {
+        return visit(type.ofType, where);
+      }
+      if (seen.has(type)) {
+        return;
+      }
+      seen.add(type);
+
+      const c = type.config || {};
+      const fields = type.getFields ? type.getFields() : thunk(c.fields);
+      for (const [name, f] of Object.entries(fields || {})) {
+        visit(f.type, type.name + '.' + name);
+        for (const [arg, a] of Object.entries(f.args || {})) {
+          visit(a.type, type.name + '.' + name + '(' + arg + ')');
+        }
+      }
+      for (const i of thunk(c.interfaces) || []) {
+        visit(i, type.name + ' interface');
+      }
+      for (const m of thunk(c.types) || []) {
+        visit(m, type.name + ' member');
+      }
+    };
+
+    for (const op of ['query', 'mutation', 'subscription']) {
+      if (op in config) {
+        visit(config[op], 'schema ' + op);
+      }
+    }
+  }
+}
+
+exports.GraphQLSchema = GraphQLSchema;
+exports.GraphQLScalarType = GraphQLScalarType;
+exports.GraphQLObjectType = GraphQLObjectType;
+exports.GraphQLInterfaceType = GraphQLInterfaceType;
+exports.GraphQLUnionType = GraphQLUnionType;
+exports.GraphQLEnumType = GraphQLEnumType;
+exports.GraphQLInputObjectType = GraphQLInputObjectType;
+exports.GraphQLDirectiveType = GraphQLDirectiveType;
+exports.GraphQLList = GraphQLList;
+exports.GraphQLNonNull = GraphQLNonNull;
+exports.GraphQLInt = new GraphQLScalarType({ name: 'Int' });
+exports.GraphQLFloat = new GraphQLScalarType({ name: 'Float' });
+exports.GraphQLString = new GraphQLScalarType({ name: 'String' });
+exports.GraphQLBoolean = new GraphQLScalarType({ name: 'Boolean' });
+exports.GraphQLID = new GraphQLScalarType({ name: 'ID' });
+`
+
+// evalJS writes generated modules to a temporary directory and evaluates
+// the main one with node, against graphqlStub. It's skipped without node.
+//
+func evalJS(t *testing.T, files map[string]*bytes.Buffer, main string) {
+	t.Helper()
+
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node isn't installed, so the generated modules can't be evaluated")
+	}
+
+	dir, err := ioutil.TempDir("", "gqlc-js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, src string) {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, b := range files {
+		write(name, b.String())
+	}
+	write("node_modules/graphql/package.json", `{ "name": "graphql", "main": "index.js" }`)
+	write("node_modules/graphql/index.js", graphqlStub)
+
+	// Dynamic imports load CommonJS and ES modules alike
+	write("run.mjs", "import { pathToFileURL } from 'url';\n\nawait import(pathToFileURL(process.argv[2]).href);\n")
+
+	out, err := exec.Command(node, filepath.Join(dir, "run.mjs"), filepath.Join(dir, filepath.FromSlash(main))).CombinedOutput()
+	if err != nil {
+		t.Fatalf("expected %s to evaluate, but got: %s\n%s\n%s", main, err, out, files[main])
+	}
+}
+
+func TestFilePerType(t *testing.T) {
 	gqlSrc := `schema {
 	query: Query
 }
 
-"Query represents the queries this example provides."
 type Query {
-	hello: String
+	user(id: ID!): User
+}
+
+type User implements Node {
+	id: ID!
+	friends: [User!]
+	role: Role
+}
+
+interface Node {
+	id: ID!
+}
+
+enum Role {
+	ADMIN
 }`
 
-	doc, err := parser.ParseDoc(token.NewDocSet(), "example", strings.NewReader(gqlSrc), 0)
+	doc, err := parser.ParseDoc(token.NewDocSet(), "api/schema.gql", strings.NewReader(gqlSrc), 0)
 	if err != nil {
-		log.Fatal(err)
+		t.Error(err)
 		return
 	}
 
-	var b bytes.Buffer
-	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
-	err = g.Generate(ctx, doc, map[string]interface{}{"module": "COMMONJS", "useFlow": true})
-	if err != nil {
-		log.Fatal(err)
-		return
+	testCases := []struct {
+		Name  string
+		Opts  map[string]interface{}
+		Files map[string]string
+	}{
+		{
+			Name: "COMMONJS",
+			Opts: map[string]interface{}{"filePerType": true},
+			Files: map[string]string{
+				"api/types/Query.js": `var {
+  GraphQLObjectType,
+  GraphQLNonNull,
+  GraphQLID
+} = require('graphql');
+
+var QueryType = new GraphQLObjectType({
+  name: 'Query',
+  fields: () => ({
+    user: {
+      type: UserType,
+      args: {
+        id: {
+          type: new GraphQLNonNull(GraphQLID)
+        }
+      },
+      resolve() { /* TODO */ }
+    }
+  })
+});
+
+module.exports = { QueryType };
+
+var { UserType } = require('./User');
+`,
+				"api/types/User.js": `var {
+  GraphQLObjectType,
+  GraphQLList,
+  GraphQLNonNull,
+  GraphQLID
+} = require('graphql');
+
+var UserType = new GraphQLObjectType({
+  name: 'User',
+  interfaces: () => [ NodeType ],
+  fields: () => ({
+    id: {
+      type: new GraphQLNonNull(GraphQLID),
+      resolve() { /* TODO */ }
+    },
+    friends: {
+      type: new GraphQLList(new GraphQLNonNull(UserType)),
+      resolve() { /* TODO */ }
+    },
+    role: {
+      type: RoleType,
+      resolve() { /* TODO */ }
+    }
+  })
+});
+
+module.exports = { UserType };
+
+var { NodeType } = require('./Node');
+var { RoleType } = require('./Role');
+`,
+				"api/index.js": `var { GraphQLSchema } = require('graphql');
+var { QueryType } = require('./types/Query');
+
+var Schema = new GraphQLSchema({
+  query: QueryType
+});
+
+module.exports = {
+  Schema,
+  ...require('./types/Query'),
+  ...require('./types/User'),
+  ...require('./types/Node'),
+  ...require('./types/Role')
+};
+`,
+			},
+		},
+		{
+			Name: "ES6",
+			Opts: map[string]interface{}{"filePerType": true, "module": "ES6"},
+			Files: map[string]string{
+				"api/types/Role.js": `import { GraphQLEnumType } from 'graphql';
+
+let RoleType = new GraphQLEnumType({
+  name: 'Role',
+  values: {
+    ADMIN: {
+      value: 'ADMIN'
+    }
+  }
+});
+
+export { RoleType };
+`,
+				"api/index.js": `import { GraphQLSchema } from 'graphql';
+import { QueryType } from './types/Query.js';
+
+let Schema = new GraphQLSchema({
+  query: QueryType
+});
+
+export { Schema };
+export { QueryType } from './types/Query.js';
+export { UserType } from './types/User.js';
+export { NodeType } from './types/Node.js';
+export { RoleType } from './types/Role.js';
+`,
+			},
+		},
 	}
 
-	fmt.Println(b.String())
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			fCtx := &filesCtx{files: make(map[string]*bytes.Buffer)}
+			ctx := gen.WithContext(context.Background(), fCtx)
+			err := new(Generator).Generate(ctx, doc, testCase.Opts)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
 
-	// Output:
-	// // @flow
-	//
-	// var {
-	//   GraphQLSchema,
-	//   GraphQLObjectType,
-	//   GraphQLString
-	// } = require('graphql');
-	//
-	// var Schema = new GraphQLSchema({
-	//   query: Query
-	// });
-	//
-	// var QueryType = new GraphQLObjectType({
-	//   name: 'Query',
-	//   fields: {
-	//     hello: {
-	//       type: GraphQLString,
+			if len(fCtx.files) != 5 {
+				subT.Errorf("expected a file per type and an index, but got: %d files", len(fCtx.files))
+			}
+
+			for name, ex := range testCase.Files {
+				b, ok := fCtx.files[name]
+				if !ok {
+					subT.Errorf("expected file: %s", name)
+					continue
+				}
+				gen.CompareBytes(subT, []byte(ex), b.Bytes())
+			}
+		})
+	}
+
+	t.Run("Cycle", func(subT *testing.T) {
+		doc, err := parser.ParseDoc(token.NewDocSet(), "api/schema.gql", strings.NewReader(`schema {
+	query: Query
+}
+
+type Query {
+	user: User
+}
+
+type User {
+	posts: [Post]
+}
+
+type Post {
+	author: User
+}`), 0)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		for _, opts := range []map[string]interface{}{
+			{"filePerType": true},
+			{"filePerType": true, "module": "ES6", "extension": "mjs"},
+		} {
+			fCtx := &filesCtx{files: make(map[string]*bytes.Buffer)}
+			ctx := gen.WithContext(context.Background(), fCtx)
+			err = new(Generator).Generate(ctx, doc, opts)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			// The modules of types which refer to each other import each other
+			main := "api/index.js"
+			if opts["module"] == "ES6" {
+				main = "api/index.mjs"
+			}
+			evalJS(subT, fCtx.files, main)
+		}
+	})
+}
+
+type pathFilesCtx struct {
+	*filesCtx
+
+	existing map[string][]byte
+}
+
+func (ctx pathFilesCtx) Dir() string                     { return "/out" }
+func (ctx pathFilesCtx) Source(doc *ast.Document) string { return "" }
+func (ctx pathFilesCtx) ReadFile(name string) ([]byte, error) {
+	b, ok := ctx.existing[filepath.ToSlash(name)]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return b, nil
+}
+
+func TestResolvers(t *testing.T) {
+	gqlSrc := `schema {
+	query: Query
+}
+
+"Time is a timestamp."
+scalar Time
+
+type Query {
+	now: Time
+	name: String @resolver(name: "() => 'gqlc'")
+}
+
+union Result = Query`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	fCtx := &filesCtx{files: make(map[string]*bytes.Buffer)}
+	ctx := gen.WithContext(context.Background(), pathFilesCtx{filesCtx: fCtx})
+	err = new(Generator).Generate(ctx, doc, map[string]interface{}{"resolvers": "separate", "descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := []byte(`var {
+  GraphQLSchema,
+  GraphQLScalarType,
+  GraphQLObjectType,
+  GraphQLUnionType,
+  GraphQLString
+} = require('graphql');
+
+var TimeType = new GraphQLScalarType({
+  name: 'Time',
+  description: 'Time is a timestamp.',
+});
+
+var QueryType = new GraphQLObjectType({
+  name: 'Query',
+  fields: {
+    now: {
+      type: TimeType
+    },
+    name: {
+      type: GraphQLString,
+      resolve: () => 'gqlc'
+    }
+  }
+});
+
+var ResultType = new GraphQLUnionType({
+  name: 'Result',
+  types: [ QueryType ],
+});
+
+var Schema = new GraphQLSchema({
+  query: QueryType
+});
+
+module.exports = { Schema, TimeType, QueryType, ResultType };
+`)
+	gen.CompareBytes(t, ex, fCtx.files["test.js"].Bytes())
+
+	ex = []byte(`var { TimeType, QueryType, ResultType } = require('./test');
+
+TimeType.serialize = function(value) { /* TODO */ };
+
+QueryType.getFields().now.resolve = function(source, args, context, info) { /* TODO */ };
+
+ResultType.resolveType = function(value, context, info) { /* TODO */ };
+`)
+	gen.CompareBytes(t, ex, fCtx.files["test.resolvers.js"].Bytes())
+
+	t.Run("Existing", func(subT *testing.T) {
+		fCtx := &filesCtx{files: make(map[string]*bytes.Buffer)}
+		ctx := gen.WithContext(context.Background(), pathFilesCtx{
+			filesCtx: fCtx,
+			existing: map[string][]byte{"/out/test.resolvers.js": []byte("// mine")},
+		})
+		err = new(Generator).Generate(ctx, doc, map[string]interface{}{"resolvers": "separate"})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if _, ok := fCtx.files["test.resolvers.js"]; ok {
+			subT.Error("expected existing resolvers to not be overwritten")
+		}
+	})
+
+	t.Run("Unknown", func(subT *testing.T) {
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: ioutil.Discard})
+		err = new(Generator).Generate(ctx, doc, map[string]interface{}{"resolvers": "elsewhere"})
+		if err == nil {
+			subT.Error("expected an error for an unknown resolvers option")
+		}
+	})
+}
+
+func TestApollo(t *testing.T) {
+	gqlSrc := `schema {
+	query: Query
+	mutation: Mutation
+}
+
+type Query {
+	user(id: ID!): String
+	name: String @resolver(name: "() => 'gqlc'")
+}
+
+type Mutation {
+	rename(name: String!): String
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	generate := func(opts map[string]interface{}) (*filesCtx, error) {
+		fCtx := &filesCtx{files: make(map[string]*bytes.Buffer)}
+		ctx := gen.WithContext(context.Background(), pathFilesCtx{filesCtx: fCtx})
+		return fCtx, new(Generator).Generate(ctx, doc, opts)
+	}
+
+	t.Run("CommonJS", func(subT *testing.T) {
+		fCtx, err := generate(map[string]interface{}{"framework": "apollo"})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if !strings.HasSuffix(fCtx.files["test.js"].String(), "module.exports = { Schema, QueryType, MutationType };\n") {
+			subT.Errorf("expected types to be exported, but got:\n%s", fCtx.files["test.js"])
+		}
+
+		ex := []byte(`var { GraphQLSchema } = require('graphql');
+var { ApolloServer } = require('@apollo/server');
+var { startStandaloneServer } = require('@apollo/server/standalone');
+var { QueryType, MutationType } = require('./test');
+
+var schema = new GraphQLSchema({
+  query: QueryType,
+  mutation: MutationType
+});
+
+var resolvers = {
+  Query: {
+    user(source, args, context, info) { /* TODO */ }
+  },
+  Mutation: {
+    rename(source, args, context, info) { /* TODO */ }
+  }
+};
+
+// Attach the resolvers to the fields of the schema
+Object.keys(resolvers).forEach((typeName) => {
+  const fields = schema.getType(typeName).getFields();
+  Object.keys(resolvers[typeName]).forEach((fieldName) => {
+    fields[fieldName].resolve = resolvers[typeName][fieldName];
+  });
+});
+
+var server = new ApolloServer({ schema });
+
+startStandaloneServer(server, { listen: { port: 4000 } }).then(({ url }) => {
+  console.log(` + "`Server ready at ${url}`" + `);
+});
+`)
+		gen.CompareBytes(subT, ex, fCtx.files["index.js"].Bytes())
+	})
+
+	t.Run("ES6", func(subT *testing.T) {
+		fCtx, err := generate(map[string]interface{}{"framework": "apollo", "module": "ES6"})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		out := fCtx.files["index.js"].String()
+		for _, s := range []string{
+			"import { ApolloServer } from '@apollo/server';\n",
+			"import { QueryType, MutationType } from './test.js';\n",
+			"const server = new ApolloServer({ schema });\n",
+		} {
+			if !strings.Contains(out, s) {
+				subT.Errorf("expected server to contain %q, but got:\n%s", s, out)
+			}
+		}
+	})
+
+	t.Run("SeparateResolvers", func(subT *testing.T) {
+		fCtx, err := generate(map[string]interface{}{"framework": "apollo", "resolvers": "separate"})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		out := fCtx.files["index.js"].String()
+		if !strings.Contains(out, "require('./test.resolvers');\n") || strings.Contains(out, "resolvers = {") {
+			subT.Errorf("expected server to use the separate resolvers, but got:\n%s", out)
+		}
+	})
+
+	t.Run("FilePerType", func(subT *testing.T) {
+		fCtx, err := generate(map[string]interface{}{"framework": "apollo", "filePerType": true})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		b, ok := fCtx.files["server.js"]
+		if !ok || !strings.Contains(b.String(), "var { QueryType, MutationType } = require('./index');\n") {
+			subT.Errorf("expected server.js to import from the index, but got files: %v", fCtx.files)
+		}
+	})
+
+	t.Run("Unknown", func(subT *testing.T) {
+		_, err := generate(map[string]interface{}{"framework": "express"})
+		if err == nil {
+			subT.Error("expected an error for an unknown framework")
+		}
+	})
+}
+
+func TestNexus(t *testing.T) {
+	gqlSrc := `schema {
+	query: Query
+}
+
+"A point in time."
+scalar Time
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	"The name of the user."
+	name: String @deprecated(reason: "Use fullName")
+	friends(first: Int = 10, after: String): [User!]!
+}
+
+type Query {
+	user(id: ID!, filter: [UserFilter!]): User
+	version: String @resolver(name: "() => '1.0'")
+	matrix: [[Float]]
+}
+
+union Result = User | Query
+
+enum Direction {
+	UP
+	"Go down."
+	DOWN @deprecated
+}
+
+input UserFilter {
+	name: String = "a"
+	tags: [String!]!
+	nested: UserFilter
+}
+
+directive @a on FIELD`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(gqlSrc), parser.ParseComments)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("CommonJS", func(subT *testing.T) {
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{"style": "nexus", "descriptions": true})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ex := []byte(`var {
+  makeSchema,
+  scalarType,
+  objectType,
+  interfaceType,
+  unionType,
+  enumType,
+  inputObjectType,
+  arg,
+  list,
+  nonNull,
+  intArg,
+  stringArg,
+  idArg
+} = require('nexus');
+
+var TimeType = scalarType({
+  name: 'Time',
+  description: 'A point in time.',
+  serialize(value) { /* TODO */ }
+});
+
+var NodeType = interfaceType({
+  name: 'Node',
+  definition(t) {
+    t.nonNull.id('id');
+  },
+  resolveType(value) { /* TODO */ }
+});
+
+var UserType = objectType({
+  name: 'User',
+  definition(t) {
+    t.implements('Node');
+    t.nonNull.id('id', {
+      resolve() { /* TODO */ }
+    });
+    t.string('name', {
+      description: 'The name of the user.',
+      deprecation: 'Use fullName',
+      resolve() { /* TODO */ }
+    });
+    t.nonNull.list.nonNull.field('friends', {
+      type: 'User',
+      args: {
+        first: intArg({ default: 10 }),
+        after: stringArg()
+      },
+      resolve() { /* TODO */ }
+    });
+  }
+});
+
+var QueryType = objectType({
+  name: 'Query',
+  definition(t) {
+    t.field('user', {
+      type: 'User',
+      args: {
+        id: nonNull(idArg()),
+        filter: list(nonNull(arg({ type: 'UserFilter' })))
+      },
+      resolve() { /* TODO */ }
+    });
+    t.string('version', {
+      resolve: () => '1.0'
+    });
+    t.list.list.float('matrix', {
+      resolve() { /* TODO */ }
+    });
+  }
+});
+
+var ResultType = unionType({
+  name: 'Result',
+  definition(t) {
+    t.members('User', 'Query');
+  },
+  resolveType(value) { /* TODO */ }
+});
+
+var DirectionType = enumType({
+  name: 'Direction',
+  members: [
+    'UP',
+    { name: 'DOWN', value: 'DOWN', description: 'Go down.', deprecation: 'No longer supported' }
+  ]
+});
+
+var UserFilterType = inputObjectType({
+  name: 'UserFilter',
+  definition(t) {
+    t.string('name', {
+      default: 'a'
+    });
+    t.nonNull.list.nonNull.string('tags');
+    t.field('nested', {
+      type: 'UserFilter'
+    });
+  }
+});
+
+var Schema = makeSchema({
+  types: [ TimeType, NodeType, UserType, QueryType, ResultType, DirectionType, UserFilterType ]
+});
+`)
+		gen.CompareBytes(subT, ex, b.Bytes())
+	})
+
+	t.Run("ES6", func(subT *testing.T) {
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{"style": "nexus", "module": "ES6"})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		out := b.String()
+		if !strings.HasPrefix(out, "import {\n  makeSchema,") || !strings.Contains(out, "} from 'nexus';\n\nlet TimeType = scalarType({") {
+			subT.Errorf("expected nexus to be imported, but got:\n%s", out)
+		}
+	})
+
+	t.Run("UnsupportedOptions", func(subT *testing.T) {
+		for _, opt := range []string{"dts", "filePerType", "federation"} {
+			ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: ioutil.Discard})
+			err := new(Generator).Generate(ctx, doc, map[string]interface{}{"style": "nexus", opt: true})
+			if err == nil || !strings.Contains(err.Error(), "doesn't support the "+opt+" option") {
+				subT.Errorf("expected %s to be unsupported, but got: %v", opt, err)
+			}
+		}
+	})
+
+	t.Run("UnknownStyle", func(subT *testing.T) {
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: ioutil.Discard})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{"style": "pothos"})
+		if err == nil || !strings.Contains(err.Error(), "unknown style option: pothos") {
+			subT.Errorf("expected unknown style error, but got: %v", err)
+		}
+	})
+}
+
+func TestScalars(t *testing.T) {
+	gqlSrc := `scalar DateTime
+
+scalar Money
+
+scalar Version
+
+type Query {
+	now: DateTime
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("CommonJS", func(subT *testing.T) {
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{
+			"scalars": []string{`"DateTime=graphql-iso-date:GraphQLDateTime"`, `"Money=./money"`},
+		})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ex := []byte(`var {
+  GraphQLScalarType,
+  GraphQLObjectType
+} = require('graphql');
+
+var { GraphQLDateTime: DateTimeType } = require('graphql-iso-date');
+
+var MoneyType = require('./money');
+
+var VersionType = new GraphQLScalarType({
+  name: 'Version',
+  serialize(value) { /* TODO */ }
+});
+
+var QueryType = new GraphQLObjectType({
+  name: 'Query',
+  fields: {
+    now: {
+      type: DateTimeType,
+      resolve() { /* TODO */ }
+    }
+  }
+});
+`)
+		gen.CompareBytes(subT, ex, b.Bytes())
+	})
+
+	t.Run("ES6", func(subT *testing.T) {
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{
+			"module":  "ES6",
+			"scalars": `"DateTime=graphql-iso-date:GraphQLDateTime,Money=./money.js"`,
+		})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		out := b.String()
+		for _, imp := range []string{
+			"import { GraphQLDateTime as DateTimeType } from 'graphql-iso-date';\n",
+			"import MoneyType from './money.js';\n",
+		} {
+			if !strings.Contains(out, imp) {
+				subT.Errorf("expected %q in:\n%s", imp, out)
+			}
+		}
+	})
+
+	t.Run("Doc", func(subT *testing.T) {
+		docSrc := `@js(options: {
+	scalars: ["DateTime=graphql-iso-date:GraphQLDateTime"]
+})
+
+` + gqlSrc
+
+		doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(docSrc), 0)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err = new(Generator).Generate(ctx, doc, nil)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if !strings.Contains(b.String(), "var { GraphQLDateTime: DateTimeType } = require('graphql-iso-date');\n") {
+			subT.Errorf("expected DateTime to be imported, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("FilePerType", func(subT *testing.T) {
+		fCtx := &filesCtx{files: make(map[string]*bytes.Buffer)}
+		ctx := gen.WithContext(context.Background(), fCtx)
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{
+			"filePerType": true,
+			"scalars":     `"Money=./money"`,
+		})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ex := []byte(`var MoneyType = require('../money');
+
+module.exports = { MoneyType };
+`)
+		gen.CompareBytes(subT, ex, fCtx.files["types/Money.js"].Bytes())
+	})
+
+	t.Run("SeparateResolvers", func(subT *testing.T) {
+		fCtx := &filesCtx{files: make(map[string]*bytes.Buffer)}
+		ctx := gen.WithContext(context.Background(), fCtx)
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{
+			"resolvers": "separate",
+			"scalars":   `"DateTime=graphql-iso-date:GraphQLDateTime"`,
+		})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		resolvers := fCtx.files["test.resolvers.js"].String()
+		if strings.Contains(resolvers, "DateTimeType") || !strings.Contains(resolvers, "VersionType.serialize") {
+			subT.Errorf("expected only unmapped scalars to get stubs, but got:\n%s", resolvers)
+		}
+	})
+
+	t.Run("Invalid", func(subT *testing.T) {
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: ioutil.Discard})
+		for _, m := range []string{`"DateTime"`, `"=graphql-iso-date"`, `"DateTime=:GraphQLDateTime"`} {
+			err := new(Generator).Generate(ctx, doc, map[string]interface{}{"scalars": m})
+			if err == nil || !strings.Contains(err.Error(), "invalid scalars option") {
+				subT.Errorf("expected %s to be invalid, but got: %v", m, err)
+			}
+		}
+	})
+}
+
+func TestSDL(t *testing.T) {
+	gqlSrc := `schema {
+	query: Query
+}
+
+"A point in time."
+scalar Time
+
+scalar Money
+
+interface Node {
+	id: ID!
+}
+
+type Query implements Node {
+	id: ID!
+	"The version of the API."
+	version: String @resolver(name: "() => '1.0'")
+	now(zone: String = "UTC"): Time @deprecated(reason: "Use clock")
+}
+
+union Result = Query`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(gqlSrc), parser.ParseComments)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("Inline", func(subT *testing.T) {
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{
+			"style":        "sdl",
+			"descriptions": true,
+			"scalars":      `"Money=./money:Money"`,
+		})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ex := []byte(`var { makeExecutableSchema } = require('@graphql-tools/schema');
+var { GraphQLScalarType } = require('graphql');
+var { Money: MoneyType } = require('./money');
+
+var typeDefs = ` + "`" + `
+schema {
+  query: Query
+}
+
+scalar Money
+
+"A point in time."
+scalar Time
+
+type Query implements Node {
+  id: ID!
+  "The version of the API."
+  version: String
+  now(zone: String = "UTC"): Time @deprecated(reason: "Use clock")
+}
+
+interface Node {
+  id: ID!
+}
+
+union Result = Query
+` + "`" + `;
+
+var resolvers = {
+  Time: new GraphQLScalarType({
+    name: 'Time',
+    serialize(value) { /* TODO */ }
+  }),
+  Money: MoneyType,
+  Node: {
+    __resolveType(value, context, info) { /* TODO */ }
+  },
+  Query: {
+    id(source, args, context, info) { /* TODO */ },
+    version: () => '1.0',
+    now(source, args, context, info) { /* TODO */ }
+  },
+  Result: {
+    __resolveType(value, context, info) { /* TODO */ }
+  }
+};
+
+var Schema = makeExecutableSchema({ typeDefs, resolvers });
+
+module.exports = { typeDefs, resolvers, Schema };
+`)
+		gen.CompareBytes(subT, ex, b.Bytes())
+	})
+
+	t.Run("Separate", func(subT *testing.T) {
+		fCtx := &filesCtx{files: make(map[string]*bytes.Buffer)}
+		ctx := gen.WithContext(context.Background(), pathFilesCtx{filesCtx: fCtx})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{
+			"style":     "sdl",
+			"module":    "ES6",
+			"resolvers": "separate",
+		})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		out := fCtx.files["test.js"].String()
+		if !strings.HasPrefix(out, "import { makeExecutableSchema } from '@graphql-tools/schema';\nimport resolvers from './test.resolvers.js';\n\nlet typeDefs = `") {
+			subT.Errorf("expected the resolvers to be imported, but got:\n%s", out)
+		}
+		if strings.Contains(out, "GraphQLScalarType") || !strings.HasSuffix(out, "export { typeDefs, resolvers, Schema };\n") {
+			subT.Errorf("expected the resolvers to be left out, but got:\n%s", out)
+		}
+
+		resolvers := fCtx.files["test.resolvers.js"].String()
+		if !strings.HasPrefix(resolvers, "import { GraphQLScalarType } from 'graphql';\n\nlet resolvers = {\n") || !strings.HasSuffix(resolvers, "};\n\nexport default resolvers;\n") {
+			subT.Errorf("unexpected resolvers:\n%s", resolvers)
+		}
+
+		// Existing resolvers are kept
+		fCtx = &filesCtx{files: make(map[string]*bytes.Buffer)}
+		ctx = gen.WithContext(context.Background(), pathFilesCtx{
+			filesCtx: fCtx,
+			existing: map[string][]byte{"/out/test.resolvers.js": nil},
+		})
+		err = new(Generator).Generate(ctx, doc, map[string]interface{}{"style": "sdl", "resolvers": "separate"})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+		if _, ok := fCtx.files["test.resolvers.js"]; ok {
+			subT.Error("expected existing resolvers to not be overwritten")
+		}
+	})
+
+	t.Run("UnsupportedOptions", func(subT *testing.T) {
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: ioutil.Discard})
+		err := new(Generator).Generate(ctx, doc, map[string]interface{}{"style": "sdl", "filePerType": true})
+		if err == nil || !strings.Contains(err.Error(), "the sdl style doesn't support the filePerType option") {
+			subT.Errorf("expected filePerType to be unsupported, but got: %v", err)
+		}
+	})
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := &Generator{}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func TestDescriptions(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Text string
+		Ex   string
+	}{
+		{
+			Name: "Plain",
+			Text: "A user.",
+			Ex:   `'A user.'`,
+		},
+		{
+			Name: "Quotes",
+			Text: `The user's "name".`,
+			Ex:   `'The user\'s "name".'`,
+		},
+		{
+			Name: "Backslash",
+			Text: `Matches \d+.`,
+			Ex:   `'Matches \\d+.'`,
+		},
+		{
+			Name: "ControlChars",
+			Text: "Tab\tseparated\r\u2028",
+			Ex:   `'Tab\tseparated\r\u2028'`,
+		},
+		{
+			Name: "MultiLine",
+			Text: "A user's\n`name`, in ${locale}.",
+			Ex:   "`A user's\n\\`name\\`, in \\${locale}.`",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			out := jsString(testCase.Text)
+			if out != testCase.Ex {
+				subT.Errorf("expected %s, but got: %s", testCase.Ex, out)
+			}
+		})
+	}
+
+	t.Run("BlockString", func(subT *testing.T) {
+		gqlSrc := `"""
+A user's account.
+
+Created by signup, see C:\Users.
+"""
+type User {
+	"The user's name, e.g. O'Brien."
+	name: String @deprecated(reason: "Use the user's fullName.")
+}`
+
+		doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), 0)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err = new(Generator).Generate(ctx, doc, map[string]interface{}{"descriptions": true})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ex := "var UserType = new GraphQLObjectType({\n" +
+			"  name: 'User',\n" +
+			"  fields: {\n" +
+			"    name: {\n" +
+			"      type: GraphQLString,\n" +
+			"      resolve() { /* TODO */ },\n" +
+			"      description: 'The user\\'s name, e.g. O\\'Brien.',\n" +
+			"      deprecationReason: 'Use the user\\'s fullName.'\n" +
+			"    }\n" +
+			"  },\n" +
+			"  description: `A user's account.\n" +
+			"\n" +
+			"Created by signup, see C:\\\\Users.`\n" +
+			"});"
+		if !strings.Contains(b.String(), ex) {
+			subT.Errorf("expected output to contain:\n%s\nbut got:\n%s", ex, b.String())
+		}
+	})
+}
+
+func TestGenerator_GenerateConcurrently(t *testing.T) {
+	g := new(Generator)
+
+	var ex bytes.Buffer
+	err := g.Generate(gen.WithContext(context.Background(), gen.TestCtx{Writer: &ex}), testDoc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	outs := make([]bytes.Buffer, 4)
+	errs := make([]error, len(outs))
+
+	var wg sync.WaitGroup
+	for i := range outs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &outs[i]})
+			errs[i] = g.Generate(ctx, testDoc, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range outs {
+		if errs[i] != nil {
+			t.Error(errs[i])
+			continue
+		}
+		gen.CompareBytes(t, ex.Bytes(), outs[i].Bytes())
+	}
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := &Generator{}
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `schema {
+	query: Query
+}
+
+"Query represents the queries this example provides."
+type Query {
+	hello: String
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, map[string]interface{}{"module": "COMMONJS", "useFlow": true})
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Println(b.String())
+
+	// Output:
+	// // @flow
+	//
+	// var {
+	//   GraphQLSchema,
+	//   GraphQLObjectType,
+	//   GraphQLString
+	// } = require('graphql');
+	//
+	// var QueryType = new GraphQLObjectType({
+	//   name: 'Query',
+	//   fields: {
+	//     hello: {
+	//       type: GraphQLString,
 	//       resolve() { /* TODO */ }
 	//     }
 	//   }
 	// });
+	//
+	// var Schema = new GraphQLSchema({
+	//   query: QueryType
+	// });
+}
+
+func TestStyle(t *testing.T) {
+	gqlSrc := `schema {
+	query: Query
+}
+
+type Query {
+	"Don't quote me."
+	hello: String @deprecated(reason: "It's old")
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(gqlSrc), parser.ParseComments)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	testCases := []struct {
+		Name string
+		Opts map[string]interface{}
+		Ex   string
+	}{
+		{
+			Name: "Default",
+			Opts: map[string]interface{}{},
+			Ex: `var {
+  GraphQLSchema,
+  GraphQLObjectType,
+  GraphQLString
+} = require('graphql');
+
+var QueryType = new GraphQLObjectType({
+  name: 'Query',
+  fields: {
+    hello: {
+      type: GraphQLString,
+      resolve() { /* TODO */ },
+      description: 'Don\'t quote me.',
+      deprecationReason: 'It\'s old'
+    }
+  }
+});
+
+var Schema = new GraphQLSchema({
+  query: QueryType
+});
+`,
+		},
+		{
+			Name: "TabsAndDoubleQuotes",
+			Opts: map[string]interface{}{"indent": "tab", "quotes": "double"},
+			Ex: `var {
+	GraphQLSchema,
+	GraphQLObjectType,
+	GraphQLString
+} = require("graphql");
+
+var QueryType = new GraphQLObjectType({
+	name: "Query",
+	fields: {
+		hello: {
+			type: GraphQLString,
+			resolve() { /* TODO */ },
+			description: "Don't quote me.",
+			deprecationReason: "It's old"
+		}
+	}
+});
+
+var Schema = new GraphQLSchema({
+	query: QueryType
+});
+`,
+		},
+		{
+			Name: "FourSpaces",
+			Opts: map[string]interface{}{"indent": int64(4)},
+			Ex: `var {
+    GraphQLSchema,
+    GraphQLObjectType,
+    GraphQLString
+} = require('graphql');
+
+var QueryType = new GraphQLObjectType({
+    name: 'Query',
+    fields: {
+        hello: {
+            type: GraphQLString,
+            resolve() { /* TODO */ },
+            description: 'Don\'t quote me.',
+            deprecationReason: 'It\'s old'
+        }
+    }
+});
+
+var Schema = new GraphQLSchema({
+    query: QueryType
+});
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			testCase.Opts["descriptions"] = true
+
+			var b bytes.Buffer
+			ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+			err := new(Generator).Generate(ctx, doc, testCase.Opts)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if b.String() != testCase.Ex {
+				subT.Errorf("expected:\n%s\nbut got:\n%s", testCase.Ex, b.String())
+			}
+		})
+	}
+
+	t.Run("Invalid", func(subT *testing.T) {
+		for _, opts := range []map[string]interface{}{
+			{"indent": "none"},
+			{"indent": int64(0)},
+			{"quotes": "backtick"},
+		} {
+			ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: ioutil.Discard})
+			if err := new(Generator).Generate(ctx, doc, opts); err == nil {
+				subT.Errorf("expected an error for: %v", opts)
+			}
+		}
+	})
+}
+
+func TestStyleWriter(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		In    string
+		Ex    string
+		Style style
+	}{
+		{
+			Name:  "Escapes",
+			In:    `var a = ['it\'s', "say \"hi\"", '\\', 'a"b'];` + "\n",
+			Ex:    `var a = ["it's", "say \"hi\"", "\\", "a\"b"];` + "\n",
+			Style: style{indent: []byte("  "), quote: '"'},
+		},
+		{
+			Name:  "Templates",
+			In:    "var a = `\n  '${b('c')}' \\${d}\n`;\n  e('f');\n",
+			Ex:    "var a = `\n  '${b(\"c\")}' \\${d}\n`;\n\te(\"f\");\n",
+			Style: style{indent: []byte{'\t'}, quote: '"'},
+		},
+		{
+			Name:  "Comments",
+			In:    "  // don't\n  /* it's\n    fine */ 'a';\n",
+			Ex:    "\t// don't\n\t/* it's\n\t\tfine */ \"a\";\n",
+			Style: style{indent: []byte{'\t'}, quote: '"'},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			var b bytes.Buffer
+			w := &styleWriter{WriteCloser: gen.TestCtx{Writer: &b}, style: &testCase.Style, lineStart: true}
+
+			// Code may be written in any number of pieces
+			for i := range testCase.In {
+				w.Write([]byte(testCase.In[i : i+1]))
+			}
+			w.Close()
+
+			if b.String() != testCase.Ex {
+				subT.Errorf("expected:\n%s\nbut got:\n%s", testCase.Ex, b.String())
+			}
+		})
+	}
+}
+
+func TestESM(t *testing.T) {
+	gqlSrc := `schema {
+	query: Query
+}
+
+scalar Money
+
+type Query {
+	user: User
+	balance: Money
+}
+
+type User {
+	name: String
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(gqlSrc), parser.ParseComments)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	generate := func(opts map[string]interface{}) (*filesCtx, error) {
+		fCtx := &filesCtx{files: make(map[string]*bytes.Buffer)}
+		ctx := gen.WithContext(context.Background(), pathFilesCtx{filesCtx: fCtx})
+		return fCtx, new(Generator).Generate(ctx, doc, opts)
+	}
+
+	t.Run("NamedExports", func(subT *testing.T) {
+		fCtx, err := generate(map[string]interface{}{
+			"module":       "ES6",
+			"namedExports": true,
+			"extension":    "mjs",
+			"resolvers":    "separate",
+			"scalars":      `"Money=./money.mjs"`,
+		})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if len(fCtx.files) != 2 || fCtx.files["test.mjs"] == nil || fCtx.files["test.resolvers.mjs"] == nil {
+			subT.Fatalf("expected test.mjs and test.resolvers.mjs, but got: %v", fCtx.files)
+		}
+
+		out := fCtx.files["test.mjs"].String()
+		for _, s := range []string{
+			"export const Schema = new GraphQLSchema({\n",
+			"import MoneyType from './money.mjs';\nexport { MoneyType };\n",
+			"export const QueryType = new GraphQLObjectType({\n",
+			"export const UserType = new GraphQLObjectType({\n",
+		} {
+			if !strings.Contains(out, s) {
+				subT.Errorf("expected %q in:\n%s", s, out)
+			}
+		}
+		if strings.Contains(out, "export { Schema") {
+			subT.Errorf("expected no export list, since every type is exported, but got:\n%s", out)
+		}
+
+		resolvers := fCtx.files["test.resolvers.mjs"].String()
+		if !strings.HasPrefix(resolvers, "import { QueryType, UserType } from './test.mjs';\n") {
+			subT.Errorf("expected the types to be imported with their extension, but got:\n%s", resolvers)
+		}
+
+		fCtx.files["money.mjs"] = bytes.NewBufferString("import { GraphQLScalarType } from 'graphql';\n\nexport default new GraphQLScalarType({ name: 'Money' });\n")
+		evalJS(subT, fCtx.files, "test.resolvers.mjs")
+	})
+
+	t.Run("FilePerType", func(subT *testing.T) {
+		fCtx, err := generate(map[string]interface{}{
+			"module":       "ES6",
+			"namedExports": true,
+			"extension":    ".mjs",
+			"filePerType":  true,
+			"dts":          true,
+		})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		for _, name := range []string{"index.mjs", "index.d.mts", "types/Query.mjs", "types/User.mjs", "types/Money.mjs"} {
+			if fCtx.files[name] == nil {
+				subT.Errorf("expected file: %s", name)
+			}
+		}
+
+		query := fCtx.files["types/Query.mjs"].String()
+		if !strings.Contains(query, "import { UserType } from './User.mjs';\n") {
+			subT.Errorf("expected the type references to be imported with their extension, but got:\n%s", query)
+		}
+		if strings.Contains(query, "export { QueryType }") {
+			subT.Errorf("expected no export list, but got:\n%s", query)
+		}
+
+		index := fCtx.files["index.mjs"].String()
+		if !strings.Contains(index, "export { QueryType } from './types/Query.mjs';\n") {
+			subT.Errorf("expected the types to be re-exported with their extension, but got:\n%s", index)
+		}
+
+		evalJS(subT, fCtx.files, "index.mjs")
+	})
+
+	t.Run("CommonJS", func(subT *testing.T) {
+		fCtx, err := generate(map[string]interface{}{"extension": "cjs", "resolvers": "separate"})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		resolvers := fCtx.files["test.resolvers.cjs"]
+		if fCtx.files["test.cjs"] == nil || resolvers == nil {
+			subT.Fatalf("expected test.cjs and test.resolvers.cjs, but got: %v", fCtx.files)
+		}
+		if !strings.HasPrefix(resolvers.String(), "var { MoneyType, QueryType, UserType } = require('./test.cjs');\n") {
+			subT.Errorf("expected .cjs to be required with its extension, but got:\n%s", resolvers)
+		}
+	})
+
+	t.Run("Bundler", func(subT *testing.T) {
+		fCtx, err := generate(map[string]interface{}{
+			"module":       "ES6",
+			"namedExports": true,
+			"extension":    "mjs",
+			"target":       "bundler",
+			"filePerType":  true,
+		})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if fCtx.files["index.mjs"] == nil || fCtx.files["types/Query.mjs"] == nil {
+			subT.Fatalf("expected the modules to keep their extension, but got: %v", fCtx.files)
+		}
+
+		query := fCtx.files["types/Query.mjs"].String()
+		if !strings.Contains(query, "import { UserType } from './User';\n") {
+			subT.Errorf("expected the type references to be imported without their extension, but got:\n%s", query)
+		}
+
+		index := fCtx.files["index.mjs"].String()
+		if !strings.Contains(index, "export { QueryType } from './types/Query';\n") {
+			subT.Errorf("expected the types to be re-exported without their extension, but got:\n%s", index)
+		}
+	})
+
+	t.Run("Invalid", func(subT *testing.T) {
+		for _, opts := range []map[string]interface{}{
+			{"namedExports": true},
+			{"extension": "ts"},
+			{"target": "deno"},
+		} {
+			if _, err := generate(opts); err == nil {
+				subT.Errorf("expected an error for: %v", opts)
+			}
+		}
+	})
+}
+
+func TestThunks(t *testing.T) {
+	gqlSrc := `schema {
+	query: Query
+}
+
+type Query {
+	node(id: ID!): Node
+	search(filter: Filter): [Result]
+}
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	posts: [Post]
+}
+
+type Post implements Node {
+	id: ID!
+	author: User
+}
+
+union Result = User | Post
+
+input Filter {
+	and: [Filter]
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(gqlSrc), parser.ParseComments)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err = new(Generator).Generate(ctx, doc, map[string]interface{}{"thunks": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	out := b.String()
+	for _, s := range []string{
+		`var NodeType = new GraphQLInterfaceType({
+  name: 'Node',
+  fields: () => ({
+    id: {
+      type: new GraphQLNonNull(GraphQLID)
+    }
+  })
+});
+`,
+		`var UserType = new GraphQLObjectType({
+  name: 'User',
+  interfaces: () => [ NodeType ],
+  fields: () => ({
+    id: {
+      type: new GraphQLNonNull(GraphQLID),
+      resolve() { /* TODO */ }
+    },
+    posts: {
+      type: new GraphQLList(PostType),
+      resolve() { /* TODO */ }
+    }
+  })
+});
+`,
+		`  types: () => [
+    UserType,
+    PostType
+  ],
+`,
+		`var FilterType = new GraphQLInputObjectType({
+  name: 'Filter',
+  fields: () => ({
+    and: {
+      type: new GraphQLList(FilterType)
+    }
+  })
+});
+`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected output to contain:\n%s\nbut got:\n%s", s, out)
+		}
+	}
+
+	// Types, and the schema, may refer to types declared after them
+	evalJS(t, map[string]*bytes.Buffer{"test.js": &b}, "test.js")
+}
+
+func TestOrder(t *testing.T) {
+	gqlSrc := `schema {
+	query: Query
+}
+
+type Query {
+	user: User
+}
+
+type User {
+	profile: Profile
+}
+
+type Profile {
+	name: String
+}`
+
+	decls := func(out string, names ...string) []int {
+		idx := make([]int, len(names))
+		for i, name := range names {
+			idx[i] = strings.Index(out, "var "+name+" =")
+		}
+		return idx
+	}
+
+	testCases := []struct {
+		Name  string
+		Order string
+		Decls []string
+	}{
+		{
+			Name:  "source",
+			Order: "source",
+			Decls: []string{"QueryType", "UserType", "ProfileType", "Schema"},
+		},
+		{
+			Name:  "alpha",
+			Order: "alpha",
+			Decls: []string{"ProfileType", "QueryType", "UserType", "Schema"},
+		},
+		{
+			Name:  "topo",
+			Order: "topo",
+			Decls: []string{"ProfileType", "UserType", "QueryType", "Schema"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(gqlSrc), parser.ParseComments)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			var b bytes.Buffer
+			ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+			err = new(Generator).Generate(ctx, doc, map[string]interface{}{"order": testCase.Order})
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			out := b.String()
+			idx := decls(out, testCase.Decls...)
+			for i := range idx {
+				if idx[i] < 0 {
+					subT.Errorf("expected %s to be declared:\n%s", testCase.Decls[i], out)
+					return
+				}
+				if i > 0 && idx[i] < idx[i-1] {
+					subT.Errorf("expected %s to be declared before %s:\n%s", testCase.Decls[i-1], testCase.Decls[i], out)
+				}
+			}
+		})
+	}
+
+	t.Run("Evaluate", func(subT *testing.T) {
+		doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(`schema {
+	query: Query
+}
+
+type Query {
+	node(id: ID!): Node
+	search(filter: Filter): [Result]
+}
+
+union Result = User | Group
+
+type Group implements Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	role: Role
+}
+
+interface Node {
+	id: ID!
+}
+
+enum Role {
+	ADMIN
+}
+
+input Filter {
+	role: Role
+}`), parser.ParseComments)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err = new(Generator).Generate(ctx, doc, map[string]interface{}{"order": "topo"})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		evalJS(subT, map[string]*bytes.Buffer{"test.js": &b}, "test.js")
+	})
+
+	cycles := []struct {
+		Name   string
+		Src    string
+		Thunks []string
+		Warn   string
+	}{
+		{
+			Name: "SelfReference",
+			Src: `schema {
+	query: Query
+}
+
+type Query {
+	me: User
+}
+
+type User {
+	name: String
+	friends: [User!]!
+}`,
+			Thunks: []string{"UserType"},
+			Warn:   "types reference each other (User -> User), so User is wrapped in thunks",
+		},
+		{
+			Name: "Cycle",
+			Src: `schema {
+	query: Query
+}
+
+type Query {
+	user: User
+}
+
+type User {
+	posts: [Post]
+}
+
+type Post {
+	author: User
+}`,
+			Thunks: []string{"PostType"},
+			Warn:   "types reference each other (User -> Post -> User), so Post is wrapped in thunks",
+		},
+	}
+
+	for _, testCase := range cycles {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(testCase.Src), parser.ParseComments)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			var b bytes.Buffer
+			var diags []diag.Diagnostic
+			ctx := gen.WithContext(context.Background(), gen.TestDiagnosticCtx{TestCtx: gen.TestCtx{Writer: &b}, Diags: &diags})
+			err = new(Generator).Generate(ctx, doc, map[string]interface{}{"order": "topo"})
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			out := b.String()
+			for _, name := range testCase.Thunks {
+				i := strings.Index(out, "var "+name+" =")
+				if i < 0 || !strings.Contains(out[i:], "fields: () => ({") {
+					subT.Errorf("expected the fields of %s to be wrapped in a thunk:\n%s", name, out)
+				}
+			}
+			if strings.Contains(out[:strings.Index(out, "var "+testCase.Thunks[0]+" =")], "() =>") {
+				subT.Errorf("expected only %v to be wrapped in thunks:\n%s", testCase.Thunks, out)
+			}
+
+			if len(diags) != 1 || diags[0].Severity != diag.Warning || diags[0].Msg != testCase.Warn {
+				subT.Errorf("expected a warning: %q, but got: %v", testCase.Warn, diags)
+			}
+
+			evalJS(subT, map[string]*bytes.Buffer{"test.js": &b}, "test.js")
+		})
+	}
+
+	t.Run("unknown", func(subT *testing.T) {
+		doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(gqlSrc), parser.ParseComments)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: new(bytes.Buffer)})
+		err = new(Generator).Generate(ctx, doc, map[string]interface{}{"order": "random"})
+		if err == nil {
+			subT.Error("expected an error for an unknown order")
+		}
+	})
+}
+
+func TestFlowTypes(t *testing.T) {
+	gqlSrc := `schema {
+	query: Query
+}
+
+scalar Time
+
+type Query {
+	now: Time
+}
+
+union Result = Query`
+
+	t.Run("Inline", func(subT *testing.T) {
+		doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(gqlSrc), parser.ParseComments)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		var b bytes.Buffer
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+		err = new(Generator).Generate(ctx, doc, map[string]interface{}{"flowTypes": true})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		out := b.String()
+		for _, s := range []string{
+			"// @flow\n",
+			"} = require('graphql');\nimport type { GraphQLResolveInfo } from 'graphql';\n",
+			"var Schema: GraphQLSchema = new GraphQLSchema({",
+			"var TimeType: GraphQLScalarType = new GraphQLScalarType({",
+			"  serialize(value: mixed): mixed { /* TODO */ }\n",
+			"var QueryType: GraphQLObjectType = new GraphQLObjectType({",
+			"      resolve(source: mixed, args: { [argument: string]: mixed }, context: mixed, info: GraphQLResolveInfo): mixed { /* TODO */ }\n",
+			"var ResultType: GraphQLUnionType = new GraphQLUnionType({",
+			"  resolveType(value: mixed, context: mixed, info: GraphQLResolveInfo): ?string { /* TODO */ }\n",
+		} {
+			if !strings.Contains(out, s) {
+				subT.Errorf("expected output to contain:\n%s\nbut got:\n%s", s, out)
+			}
+		}
+	})
+
+	t.Run("Separate", func(subT *testing.T) {
+		doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(gqlSrc), parser.ParseComments)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		fCtx := &filesCtx{files: make(map[string]*bytes.Buffer)}
+		ctx := gen.WithContext(context.Background(), fCtx)
+		err = new(Generator).Generate(ctx, doc, map[string]interface{}{"flowTypes": true, "resolvers": "separate"})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		out := fCtx.files["test.resolvers.js"].String()
+		for _, s := range []string{
+			"// @flow\n\nimport type { GraphQLResolveInfo } from 'graphql';\nvar { TimeType, QueryType, ResultType } = require('./test');\n",
+			"TimeType.serialize = function(value: mixed): mixed { /* TODO */ };\n",
+			"QueryType.getFields().now.resolve = function(source: mixed, args: { [argument: string]: mixed }, context: mixed, info: GraphQLResolveInfo): mixed { /* TODO */ };\n",
+			"ResultType.resolveType = function(value: mixed, context: mixed, info: GraphQLResolveInfo): ?string { /* TODO */ };\n",
+		} {
+			if !strings.Contains(out, s) {
+				subT.Errorf("expected resolvers to contain:\n%s\nbut got:\n%s", s, out)
+			}
+		}
+	})
+
+	t.Run("Nexus", func(subT *testing.T) {
+		doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(gqlSrc), parser.ParseComments)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: new(bytes.Buffer)})
+		err = new(Generator).Generate(ctx, doc, map[string]interface{}{"flowTypes": true, "style": "nexus"})
+		if err == nil {
+			subT.Error("expected the nexus style to not support flowTypes")
+		}
+	})
 }
@@ -0,0 +1,469 @@
+package js
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/sdl"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+// nexusImports are the nexus functions the generated code may use, in
+// the order they're imported.
+//
+var nexusImports = []string{
+	"makeSchema",
+	"scalarType",
+	"objectType",
+	"interfaceType",
+	"unionType",
+	"enumType",
+	"inputObjectType",
+	"arg",
+	"list",
+	"nonNull",
+	"intArg",
+	"floatArg",
+	"stringArg",
+	"booleanArg",
+	"idArg",
+}
+
+// nexusScalars maps the builtin scalars to their nexus field methods.
+var nexusScalars = map[string]string{
+	"Int":     "int",
+	"Float":   "float",
+	"String":  "string",
+	"Boolean": "boolean",
+	"ID":      "id",
+}
+
+// generateNexus writes the types of a document as nexus definitions,
+// followed by the schema nexus makes from them.
+//
+func (g *Generator) generateNexus(gCtx gen.GeneratorContext, posCtx gen.PositionContext, opts *Options, doc *ast.Document) error {
+	used := make(map[string]bool)
+
+	g.log.Info("generating nexus types")
+	var names []string
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+
+		switch ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Schema:
+			continue
+		case *ast.TypeSpec_Directive:
+			gen.Warnf(gCtx, doc, token.Pos(d.TokPos), "nexus can't define directives, so %s will be skipped", ts.TypeSpec.Name.Name)
+			continue
+		}
+
+		if len(names) > 0 {
+			g.P()
+		}
+		names = append(names, ts.TypeSpec.Name.Name+"Type")
+
+		g.writePosition(posCtx, d.TokPos)
+		g.generateNexusType(used, opts, d, ts.TypeSpec)
+	}
+
+	if doc.Schema != nil {
+		g.log.Info("generating schema")
+		used["makeSchema"] = true
+
+		g.P()
+		g.writePosition(posCtx, doc.Schema.TokPos)
+		g.P(opts.declStr, " Schema = makeSchema({")
+		g.In()
+		g.P("types: [ ", strings.Join(names, ", "), " ]")
+		g.Out()
+		g.P("});")
+	}
+
+	jsFileName := doc.Name[:len(doc.Name)-len(filepath.Ext(doc.Name))]
+	f, err := g.open(gCtx, jsFileName+opts.ext())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var imports []string
+	for _, imp := range nexusImports {
+		if used[imp] {
+			imports = append(imports, imp)
+		}
+	}
+
+	var b bytes.Buffer
+	if opts.UseFlow {
+		b.Write(flowDirective)
+		b.WriteString("\n\n")
+	}
+	if len(imports) > 0 {
+		switch opts.Module {
+		case "ES6":
+			fmt.Fprintf(&b, "import {\n  %s\n} from 'nexus';\n\n", strings.Join(imports, ",\n  "))
+		default:
+			fmt.Fprintf(&b, "var {\n  %s\n} = require('nexus');\n\n", strings.Join(imports, ",\n  "))
+		}
+	}
+
+	if _, err = b.WriteTo(f); err != nil {
+		return err
+	}
+	_, err = g.WriteTo(f)
+	return err
+}
+
+// generateNexusType generates the nexus definition of a type.
+func (g *Generator) generateNexusType(used map[string]bool, opts *Options, d *ast.TypeDecl, ts *ast.TypeSpec) {
+	if impl, ok := opts.scalarImpl(ts); ok {
+		g.writeScalarImport(opts, ts.Name.Name, impl)
+		if opts.NamedExports {
+			g.P("export { ", ts.Name.Name, "Type };")
+		}
+		return
+	}
+
+	var fn string
+	switch ts.Type.(type) {
+	case *ast.TypeSpec_Scalar:
+		fn = "scalarType"
+	case *ast.TypeSpec_Object:
+		fn = "objectType"
+	case *ast.TypeSpec_Interface:
+		fn = "interfaceType"
+	case *ast.TypeSpec_Union:
+		fn = "unionType"
+	case *ast.TypeSpec_Enum:
+		fn = "enumType"
+	case *ast.TypeSpec_Input:
+		fn = "inputObjectType"
+	}
+	used[fn] = true
+
+	g.P(opts.declStr, " ", ts.Name.Name, "Type = ", fn, "({")
+	g.In()
+
+	var props [][]interface{}
+	props = append(props, []interface{}{"name: '", ts.Name.Name, "'"})
+	if text := sdl.Description(d.Doc); opts.Descriptions && text != "" {
+		props = append(props, []interface{}{"description: ", jsString(text)})
+	}
+
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Scalar:
+		props = append(props, []interface{}{"serialize(value) { /* TODO */ }"})
+	case *ast.TypeSpec_Union:
+		var mems []string
+		for _, mem := range v.Union.Members {
+			mems = append(mems, "'"+mem.Name+"'")
+		}
+
+		props = append(props, []interface{}{"definition(t) {\n", g.Indentation(), "  t.members(", strings.Join(mems, ", "), ");\n", g.Indentation(), "}"})
+		props = append(props, []interface{}{"resolveType(value) { /* TODO */ }"})
+	case *ast.TypeSpec_Enum:
+		props = append(props, []interface{}{"members: ", g.nexusMembers(opts, v.Enum.Values)})
+	}
+
+	// Objects, interfaces and inputs are followed by their definition
+	var def bool
+	switch ts.Type.(type) {
+	case *ast.TypeSpec_Object, *ast.TypeSpec_Interface, *ast.TypeSpec_Input:
+		def = true
+	}
+	for i, p := range props {
+		g.P(append(p, sep(def || i != len(props)-1))...)
+	}
+
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Object:
+		g.P("definition(t) {")
+		g.In()
+		for _, inter := range v.Object.Interfaces {
+			g.P("t.implements('", inter.Name, "');")
+		}
+		g.generateNexusFields(used, opts, v.Object.Fields, true)
+		g.Out()
+		g.P("}")
+	case *ast.TypeSpec_Interface:
+		g.P("definition(t) {")
+		g.In()
+		g.generateNexusFields(used, opts, v.Interface.Fields, false)
+		g.Out()
+		g.P("},")
+		g.P("resolveType(value) { /* TODO */ }")
+	case *ast.TypeSpec_Input:
+		g.P("definition(t) {")
+		g.In()
+		if v.Input.Fields != nil {
+			for _, f := range v.Input.Fields.List {
+				g.generateNexusInputField(used, opts, f)
+			}
+		}
+		g.Out()
+		g.P("}")
+	}
+
+	g.Out()
+	g.P("});")
+}
+
+// nexusMembers returns the members of an enum. Members are given by name,
+// unless they have a description or deprecation.
+//
+func (g *Generator) nexusMembers(opts *Options, vals *ast.FieldList) string {
+	var b strings.Builder
+	b.WriteByte('[')
+
+	indent := string(g.Indentation()) + "  "
+	for i, v := range vals.List {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString("\n" + indent)
+
+		info := []string{"name: '" + v.Name.Name + "'", "value: '" + v.Name.Name + "'"}
+		if text := sdl.Description(v.Doc); opts.Descriptions && text != "" {
+			info = append(info, "description: "+jsString(text))
+		}
+		if reason, ok := getDeprecation(v.Directives); ok {
+			info = append(info, "deprecation: "+jsString(reason))
+		}
+
+		if len(info) == 2 {
+			b.WriteString("'" + v.Name.Name + "'")
+			continue
+		}
+		b.WriteString("{ " + strings.Join(info, ", ") + " }")
+	}
+
+	b.WriteString("\n" + string(g.Indentation()) + "]")
+	return b.String()
+}
+
+// generateNexusFields generates the fields of an object or interface
+// definition. Object fields get resolver stubs.
+//
+func (g *Generator) generateNexusFields(used map[string]bool, opts *Options, fields *ast.FieldList, resolve bool) {
+	if fields == nil {
+		return
+	}
+
+	for _, f := range fields.List {
+		var typ interface{}
+		switch v := f.Type.(type) {
+		case *ast.Field_Ident:
+			typ = v.Ident
+		case *ast.Field_List:
+			typ = v.List
+		case *ast.Field_NonNull:
+			typ = v.NonNull
+		}
+
+		var props [][]interface{}
+		if f.Args != nil {
+			props = append(props, []interface{}{"args: {"})
+		}
+		if text := sdl.Description(f.Doc); opts.Descriptions && text != "" {
+			props = append(props, []interface{}{"description: ", jsString(text)})
+		}
+		if reason, ok := getDeprecation(f.Directives); ok {
+			props = append(props, []interface{}{"deprecation: ", jsString(reason)})
+		}
+		if resolver := getResolver(f.Directives); resolve && resolver != "" {
+			props = append(props, []interface{}{"resolve: ", resolver})
+		} else if resolve {
+			props = append(props, []interface{}{"resolve() { /* TODO */ }"})
+		}
+
+		g.generateNexusField(f.Name.Name, typ, props, func() {
+			g.In()
+			for i, a := range f.Args.List {
+				g.P(a.Name.Name, ": ", g.nexusArg(used, opts, a), sep(i != len(f.Args.List)-1))
+			}
+			g.Out()
+		})
+	}
+}
+
+// generateNexusInputField generates a field of an input object definition.
+func (g *Generator) generateNexusInputField(used map[string]bool, opts *Options, f *ast.InputValue) {
+	var typ interface{}
+	switch v := f.Type.(type) {
+	case *ast.InputValue_Ident:
+		typ = v.Ident
+	case *ast.InputValue_List:
+		typ = v.List
+	case *ast.InputValue_NonNull:
+		typ = v.NonNull
+	}
+
+	var props [][]interface{}
+	if f.Default != nil {
+		props = append(props, []interface{}{"default: ", g.nexusDefault(f)})
+	}
+	if text := sdl.Description(f.Doc); opts.Descriptions && text != "" {
+		props = append(props, []interface{}{"description: ", jsString(text)})
+	}
+
+	g.generateNexusField(f.Name.Name, typ, props, nil)
+}
+
+// generateNexusField generates a field definition e.g. t.nonNull.string('name').
+// Wrapping types are chained before the method, which is field for all
+// but the builtin scalars. A field with args has its args printed by
+// printArgs, since their first prop opens the args object.
+//
+func (g *Generator) generateNexusField(name string, typ interface{}, props [][]interface{}, printArgs func()) {
+	g.WriteIndent()
+	g.WriteString("t.")
+
+	named := ""
+	for named == "" {
+		switch v := typ.(type) {
+		case *ast.Ident:
+			named = v.Name
+		case *ast.List:
+			g.WriteString("list.")
+			switch w := v.Type.(type) {
+			case *ast.List_Ident:
+				typ = w.Ident
+			case *ast.List_List:
+				typ = w.List
+			case *ast.List_NonNull:
+				typ = w.NonNull
+			}
+		case *ast.NonNull:
+			g.WriteString("nonNull.")
+			switch w := v.Type.(type) {
+			case *ast.NonNull_Ident:
+				typ = w.Ident
+			case *ast.NonNull_List:
+				typ = w.List
+			}
+		}
+	}
+
+	method, ok := nexusScalars[named]
+	if !ok {
+		method = "field"
+		props = append([][]interface{}{{"type: '", named, "'"}}, props...)
+	}
+	g.WriteString(method)
+	g.WriteString("('")
+	g.WriteString(name)
+	g.WriteByte('\'')
+
+	if len(props) == 0 {
+		g.WriteString(");\n")
+		return
+	}
+
+	g.WriteString(", {\n")
+	g.In()
+	for i, p := range props {
+		more := sep(i != len(props)-1)
+		if p[0] != "args: {" {
+			g.P(append(p, more)...)
+			continue
+		}
+
+		g.P(p...)
+		printArgs()
+		g.P("}", more)
+	}
+	g.Out()
+	g.P("});")
+}
+
+// nexusArg returns the definition of an arg e.g. nonNull(idArg()).
+func (g *Generator) nexusArg(used map[string]bool, opts *Options, a *ast.InputValue) string {
+	var typ interface{}
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		typ = v.Ident
+	case *ast.InputValue_List:
+		typ = v.List
+	case *ast.InputValue_NonNull:
+		typ = v.NonNull
+	}
+
+	var props []string
+	if a.Default != nil {
+		props = append(props, "default: "+g.nexusDefault(a))
+	}
+	if text := sdl.Description(a.Doc); opts.Descriptions && text != "" {
+		props = append(props, "description: "+jsString(text))
+	}
+
+	var wrappers []string
+	named := ""
+	for named == "" {
+		switch v := typ.(type) {
+		case *ast.Ident:
+			named = v.Name
+		case *ast.List:
+			wrappers = append(wrappers, "list")
+			switch w := v.Type.(type) {
+			case *ast.List_Ident:
+				typ = w.Ident
+			case *ast.List_List:
+				typ = w.List
+			case *ast.List_NonNull:
+				typ = w.NonNull
+			}
+		case *ast.NonNull:
+			wrappers = append(wrappers, "nonNull")
+			switch w := v.Type.(type) {
+			case *ast.NonNull_Ident:
+				typ = w.Ident
+			case *ast.NonNull_List:
+				typ = w.List
+			}
+		}
+	}
+
+	fn := "arg"
+	if s, ok := nexusScalars[named]; ok {
+		fn = s + "Arg"
+	} else {
+		props = append([]string{"type: '" + named + "'"}, props...)
+	}
+	used[fn] = true
+
+	s := fn + "()"
+	if len(props) > 0 {
+		s = fn + "({ " + strings.Join(props, ", ") + " })"
+	}
+
+	for i := len(wrappers) - 1; i >= 0; i-- {
+		used[wrappers[i]] = true
+		s = wrappers[i] + "(" + s + ")"
+	}
+	return s
+}
+
+// nexusDefault returns the default value of an input value as Javascript.
+func (g *Generator) nexusDefault(v *ast.InputValue) string {
+	var val interface{}
+	switch d := v.Default.(type) {
+	case *ast.InputValue_BasicLit:
+		val = d.BasicLit
+	case *ast.InputValue_CompositeLit:
+		val = d.CompositeLit
+	}
+
+	// printVal writes to the generator, so borrow its buffer
+	n := g.Len()
+	g.printVal(val)
+	s := string(g.Bytes()[n:])
+	g.Truncate(n)
+	return s
+}
@@ -0,0 +1,124 @@
+package js
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+// orderDoc returns a document whose types are in the given order: "source"
+// keeps the order they're given in, "alpha" sorts them by name, so adding a
+// type doesn't move any others, and "topo" moves types after the types they
+// reference, so they're declared before they're used.
+//
+func (g *Generator) orderDoc(gCtx gen.GeneratorContext, doc *ast.Document, order string) *ast.Document {
+	if order == "source" {
+		return doc
+	}
+
+	d := &ast.Document{
+		Name:       doc.Name,
+		Doc:        doc.Doc,
+		Directives: doc.Directives,
+		Schema:     doc.Schema,
+		Types:      make([]*ast.TypeDecl, len(doc.Types)),
+	}
+	copy(d.Types, doc.Types)
+
+	if order == "topo" {
+		d.Types = g.topoSort(gCtx, d)
+		return d
+	}
+
+	sort.SliceStable(d.Types, func(i, j int) bool {
+		return declName(d.Types[i]) < declName(d.Types[j])
+	})
+	return d
+}
+
+// topoSort orders decls so every type comes after the types it references,
+// and otherwise keeps their order. Types which reference each other, or
+// themselves, can't come first, so the cycle is broken where it's found, and
+// the type which references one declared after it is added to g.forward,
+// so it's wrapped in thunks.
+//
+func (g *Generator) topoSort(gCtx gen.GeneratorContext, doc *ast.Document) []*ast.TypeDecl {
+	decls := doc.Types
+	byName := make(map[string]*ast.TypeDecl, len(decls))
+	for _, d := range decls {
+		byName[declName(d)] = d
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(decls))
+	sorted := make([]*ast.TypeDecl, 0, len(decls))
+
+	var path []string
+	var visit func(d *ast.TypeDecl)
+	visit = func(d *ast.TypeDecl) {
+		name := declName(d)
+		state[name] = visiting
+		path = append(path, name)
+
+		if ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec); ok {
+			for _, ref := range typeRefs(ts.TypeSpec) {
+				dep, ok := byName[ref]
+				if !ok {
+					continue
+				}
+
+				switch state[ref] {
+				case visiting:
+					var cycle []string
+					for i := len(path) - 1; i >= 0; i-- {
+						cycle = append([]string{path[i]}, cycle...)
+						if path[i] == ref {
+							break
+						}
+					}
+					if !g.thunks && !g.forward[name] {
+						gen.Warnf(gCtx, doc, token.Pos(d.TokPos), "types reference each other (%s), so %s is wrapped in thunks", strings.Join(append(cycle, ref), " -> "), name)
+					}
+					if g.forward == nil {
+						g.forward = make(map[string]bool)
+					}
+					g.forward[name] = true
+				case 0:
+					visit(dep)
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = visited
+		sorted = append(sorted, d)
+	}
+
+	for _, d := range decls {
+		if state[declName(d)] == 0 {
+			visit(d)
+		}
+	}
+	return sorted
+}
+
+// declName returns the name of a declared type, or schema for the schema.
+func declName(d *ast.TypeDecl) string {
+	var ts *ast.TypeSpec
+	switch v := d.Spec.(type) {
+	case *ast.TypeDecl_TypeSpec:
+		ts = v.TypeSpec
+	case *ast.TypeDecl_TypeExtSpec:
+		ts = v.TypeExtSpec.Type
+	}
+	if ts == nil || ts.Name == nil {
+		return "schema"
+	}
+	return ts.Name.Name
+}
@@ -18,10 +18,6 @@ var {
   GraphQLID
 } = require('graphql');
 
-var Schema = new GraphQLSchema({
-  query: Query
-});
-
 var VersionType = new GraphQLScalarType({
   name: 'Version',
   description: 'Version represents an API version.',
@@ -35,6 +31,12 @@ var EchoType = new GraphQLObjectType({
       type: new GraphQLNonNull(GraphQLString),
       resolve() { /* TODO */ },
       description: 'msg contains the provided message.'
+    },
+    text: {
+      type: GraphQLString,
+      resolve() { /* TODO */ },
+      description: 'text is the old name of msg.',
+      deprecationReason: 'Use msg.'
     }
   },
   description: 'Echo represents an echo message.'
@@ -44,12 +46,12 @@ var QueryType = new GraphQLObjectType({
   name: 'Query',
   fields: {
     version: {
-      type: Version,
+      type: VersionType,
       resolve() { /* TODO */ },
       description: 'version returns the current API version.'
     },
     echo: {
-      type: Echo,
+      type: EchoType,
       args: {
         text: {
           type: new GraphQLNonNull(GraphQLString)
@@ -59,7 +61,7 @@ var QueryType = new GraphQLObjectType({
       description: 'echo echos a message.'
     },
     search: {
-      type: Result,
+      type: ResultType,
       args: {
         text: {
           type: GraphQLString,
@@ -79,7 +81,7 @@ var QueryType = new GraphQLObjectType({
 
 var ResultType = new GraphQLObjectType({
   name: 'Result',
-  interfaces: [ Connection ],
+  interfaces: [ ConnectionType ],
   fields: {
     total: {
       type: GraphQLInt,
@@ -87,7 +89,7 @@ var ResultType = new GraphQLObjectType({
       description: 'total yields the total number of search results.'
     },
     edges: {
-      type: new GraphQLList(Node),
+      type: new GraphQLList(NodeType),
       resolve() { /* TODO */ },
       description: 'edges contains the search results.'
     },
@@ -108,7 +110,7 @@ var ConnectionType = new GraphQLInterfaceType({
       description: 'total returns the total number of edges.'
     },
     edges: {
-      type: new GraphQLList(Node),
+      type: new GraphQLList(NodeType),
       description: 'edges contains the current page of edges.'
     },
     hasNextPage: {
@@ -133,8 +135,8 @@ var NodeType = new GraphQLInterfaceType({
 var SearchResultType = new GraphQLUnionType({
   name: 'SearchResult',
   types: [
-    Echo,
-    Result
+    EchoType,
+    ResultType
   ],
   resolveType(value) { /* TODO */ },
   description: 'SearchResult is a test union type'
@@ -157,6 +159,11 @@ var DirectionType = new GraphQLEnumType({
     WEST: {
       value: 'WEST',
       description: 'EnumValue Description and Directives.'
+    },
+    UP: {
+      value: 'UP',
+      description: 'UP is no longer a direction.',
+      deprecationReason: 'No longer supported'
     }
   }
 });
@@ -188,3 +195,7 @@ var deprecateType = new GraphQLDirectiveType({
     }
   }
 });
+
+var Schema = new GraphQLSchema({
+  query: QueryType
+});
@@ -0,0 +1,219 @@
+package js
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/sdl"
+	"github.com/gqlc/graphql/ast"
+	"go.uber.org/zap"
+)
+
+// generateTypeDefs writes a document as SDL type definitions, along with a
+// map of resolver stubs, which graphql-tools makes an executable schema of.
+// With separate resolvers, the map is written to <name>.resolvers.js, unless
+// it already exists, and imported from it.
+//
+func (g *Generator) generateTypeDefs(gCtx gen.GeneratorContext, opts *Options, doc *ast.Document) error {
+	jsFileName := doc.Name[:len(doc.Name)-len(filepath.Ext(doc.Name))]
+	from := filepath.Base(jsFileName)
+	es6 := opts.Module == "ES6"
+
+	g.log.Info("generating type definitions")
+	if opts.UseFlow {
+		g.P(flowDirective)
+		g.P()
+	}
+
+	imports, resolvers := g.resolverMap(opts, doc)
+
+	roots := rootTypes(doc)
+	if len(roots) > 0 {
+		if es6 {
+			g.P("import { makeExecutableSchema } from '@graphql-tools/schema';")
+		} else {
+			g.P("var { makeExecutableSchema } = require('@graphql-tools/schema');")
+		}
+	}
+	if g.separate {
+		if es6 {
+			g.P("import resolvers from '", opts.specifier("./"+from+".resolvers"), "';")
+		} else {
+			g.P("var resolvers = require('", opts.specifier("./"+from+".resolvers"), "');")
+		}
+	} else {
+		g.WriteString(imports)
+	}
+	if g.Len() > 0 {
+		g.P()
+	}
+
+	g.P(opts.declStr, " typeDefs = ", jsString("\n"+string(sdl.Format(typeDefsDoc(doc), &sdl.Options{Descriptions: opts.Descriptions}))), ";")
+	g.P()
+
+	if !g.separate {
+		g.WriteString(resolvers)
+		g.P()
+	}
+
+	exports := []string{"typeDefs", "resolvers"}
+	if len(roots) > 0 {
+		exports = append(exports, "Schema")
+		g.P(opts.declStr, " Schema = makeExecutableSchema({ typeDefs, resolvers });")
+		g.P()
+	}
+	if opts.NamedExports {
+		// Only imported resolvers aren't declared with an export
+		exports = exports[1:1]
+		if g.separate {
+			exports = append(exports, "resolvers")
+		}
+	}
+	if len(exports) > 0 {
+		g.writeExports(opts, exports...)
+	} else {
+		g.Truncate(g.Len() - 1)
+	}
+
+	f, err := g.open(gCtx, jsFileName+opts.ext())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err = g.WriteTo(f); err != nil || !g.separate {
+		return err
+	}
+
+	name := jsFileName + ".resolvers" + opts.ext()
+	rf, err := g.scaffold(gCtx, name)
+	if err == gen.ErrScaffolded {
+		g.log.Info("resolvers already exist, so they won't be overwritten", zap.String("file", name))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer rf.Close()
+	g.log.Info("writing resolver stubs", zap.String("file", name))
+
+	var b bytes.Buffer
+	if opts.UseFlow {
+		b.Write(flowDirective)
+		b.WriteString("\n\n")
+	}
+	if imports != "" {
+		b.WriteString(imports)
+		b.WriteByte('\n')
+	}
+	b.WriteString(resolvers)
+	if es6 {
+		b.WriteString("\nexport default resolvers;\n")
+	} else {
+		b.WriteString("\nmodule.exports = resolvers;\n")
+	}
+	_, err = b.WriteTo(rf)
+	return err
+}
+
+// typeDefsDoc returns the types of a document as graphql-tools expects
+// them, without the directives only the generator understands.
+//
+func typeDefsDoc(doc *ast.Document) *ast.Document {
+	d := &ast.Document{Name: doc.Name}
+	if doc.Schema != nil {
+		d.Schema = proto.Clone(doc.Schema).(*ast.TypeDecl)
+	}
+
+	for _, decl := range doc.Types {
+		decl = proto.Clone(decl).(*ast.TypeDecl)
+		d.Types = append(d.Types, decl)
+
+		ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+		obj, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Object)
+		if !ok || obj.Object.Fields == nil {
+			continue
+		}
+
+		for _, f := range obj.Object.Fields.List {
+			dirs := f.Directives[:0]
+			for _, dir := range f.Directives {
+				if dir.Name != "resolver" {
+					dirs = append(dirs, dir)
+				}
+			}
+			f.Directives = dirs
+		}
+	}
+	return d
+}
+
+// resolverMap returns the declaration of the resolvers of a document, keyed
+// by type name, and the imports it needs. Object fields, abstract types and
+// scalars get stubs, unless a field has a @resolver or a scalar is mapped to
+// an implementation.
+//
+func (g *Generator) resolverMap(opts *Options, doc *ast.Document) (imports, resolvers string) {
+	var scalars bool
+	var impls, entries []string
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+		name := ts.TypeSpec.Name.GetName()
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar:
+			if impl, ok := opts.scalarImpl(ts.TypeSpec); ok {
+				n := g.Len()
+				g.writeScalarImport(opts, name, impl)
+				impls = append(impls, string(g.Bytes()[n:]))
+				g.Truncate(n)
+
+				entries = append(entries, fmt.Sprintf("  %s: %sType", name, name))
+				continue
+			}
+
+			scalars = true
+			entries = append(entries, fmt.Sprintf("  %s: new GraphQLScalarType({\n    name: '%s',\n    serialize(value) { /* TODO */ }\n  })", name, name))
+		case *ast.TypeSpec_Interface, *ast.TypeSpec_Union:
+			entries = append(entries, fmt.Sprintf("  %s: {\n    __resolveType(value, context, info) { /* TODO */ }\n  }", name))
+		case *ast.TypeSpec_Object:
+			if v.Object.Fields == nil {
+				continue
+			}
+
+			var fields []string
+			for _, f := range v.Object.Fields.List {
+				if resolver := getResolver(f.Directives); resolver != "" {
+					fields = append(fields, fmt.Sprintf("    %s: %s", f.Name.Name, resolver))
+					continue
+				}
+				fields = append(fields, fmt.Sprintf("    %s(source, args, context, info) { /* TODO */ }", f.Name.Name))
+			}
+			entries = append(entries, fmt.Sprintf("  %s: {\n%s\n  }", name, strings.Join(fields, ",\n")))
+		}
+	}
+
+	if scalars {
+		if opts.Module == "ES6" {
+			impls = append([]string{"import { GraphQLScalarType } from 'graphql';\n"}, impls...)
+		} else {
+			impls = append([]string{"var { GraphQLScalarType } = require('graphql');\n"}, impls...)
+		}
+	}
+	imports = strings.Join(impls, "")
+
+	if len(entries) == 0 {
+		return imports, fmt.Sprintf("%s resolvers = {};\n", opts.declStr)
+	}
+	return imports, fmt.Sprintf("%s resolvers = {\n%s\n};\n", opts.declStr, strings.Join(entries, ",\n"))
+}
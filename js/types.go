@@ -59,6 +59,16 @@ var jsTypes = []*ast.TypeDecl{
 								Value: "false",
 							}},
 						},
+						{
+							Name: &ast.Ident{Name: "flowTypes"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
 						{
 							Name: &ast.Ident{Name: "descriptions"},
 							Type: &ast.InputValue_Ident{
@@ -69,6 +79,148 @@ var jsTypes = []*ast.TypeDecl{
 								Value: "false",
 							}},
 						},
+						{
+							Name: &ast.Ident{Name: "dts"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "federation"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "positions"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "filePerType"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "resolvers"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_STRING,
+								Value: "\"inline\"",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "framework"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+						},
+						{
+							Name: &ast.Ident{Name: "scalars"},
+							Type: &ast.InputValue_List{List: &ast.List{
+								Type: &ast.List_Ident{Ident: &ast.Ident{Name: "String"}},
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "style"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_STRING,
+								Value: `"graphql"`,
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "order"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_STRING,
+								Value: `"source"`,
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "thunks"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "namedExports"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "extension"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_STRING,
+								Value: `"js"`,
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "target"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_STRING,
+								Value: `"node"`,
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "indent"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_STRING,
+								Value: `"2"`,
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "quotes"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "String"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_STRING,
+								Value: `"single"`,
+							}},
+						},
 					},
 				},
 			}},
@@ -0,0 +1,602 @@
+// Package jsonschema contains a JSON Schema generator for GraphQL Documents.
+// Every object and input type is written to its own JSON Schema document,
+// which follows draft 2020-12.
+//
+package jsonschema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+// Draft is the JSON Schema dialect of generated documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Options contains the options for the JSON Schema generator.
+type Options struct {
+	// BaseURI prefixes the $id of every document
+	BaseURI string
+
+	// Disallow properties which aren't declared by a type (default: true)
+	Strict bool
+
+	// Copy descriptions to schemas (default: true)
+	Descriptions bool
+}
+
+// Generator generates JSON Schemas for a GraphQL schema.
+type Generator struct {
+	bytes.Buffer
+
+	opts *Options
+	log  *zap.Logger
+
+	// decls maps the types declared in a document to their declaration
+	decls map[string]*ast.TypeDecl
+
+	// root is the type of the current document
+	root string
+
+	// refs contains the types referenced by the current document
+	refs map[string]bool
+}
+
+// Reset overrides the bytes.Buffer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Buffer.Reset()
+	g.decls = make(map[string]*ast.TypeDecl)
+	g.refs = make(map[string]bool)
+}
+
+// Generate generates a JSON Schema document for every object and input
+// type in the given document, except for the root operation types.
+//
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "jsonschema",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("jsonschema").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	g.opts, err = getOptions(doc, opts)
+	if err != nil {
+		return
+	}
+
+	var names []string
+	roots := rootTypes(doc)
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		name := ts.TypeSpec.Name.Name
+		g.decls[name] = d
+
+		switch ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			if !roots[name] {
+				names = append(names, name)
+			}
+		case *ast.TypeSpec_Input:
+			names = append(names, name)
+		}
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	g.log.Info("generating schemas")
+	dir := filepath.Dir(doc.Name)
+	for _, name := range names {
+		g.Buffer.Reset()
+		err = g.generateDocument(name)
+		if err != nil {
+			return
+		}
+
+		err = g.write(gCtx, filepath.Join(dir, fileName(name)))
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (g *Generator) write(gCtx gen.GeneratorContext, filename string) error {
+	f, err := gCtx.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = g.WriteTo(f)
+	return err
+}
+
+// fileName returns the name of the document generated for a type.
+func fileName(name string) string {
+	return name + ".schema.json"
+}
+
+// rootTypes returns the root operation types of a document.
+func rootTypes(doc *ast.Document) map[string]bool {
+	if doc.Schema == nil {
+		return map[string]bool{"Query": true, "Mutation": true, "Subscription": true}
+	}
+
+	roots := make(map[string]bool, 3)
+	schema := doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Schema).Schema
+	for _, f := range schema.RootOps.List {
+		roots[f.Type.(*ast.Field_Ident).Ident.Name] = true
+	}
+	return roots
+}
+
+// generateDocument encodes the schema of a type, along with the types it
+// references under $defs, to the underlying buffer.
+//
+func (g *Generator) generateDocument(name string) error {
+	g.root = name
+	g.refs = make(map[string]bool)
+
+	schema := object{{"$schema", Draft}}
+	if g.opts.BaseURI != "" {
+		schema = append(schema, member{"$id", strings.TrimSuffix(g.opts.BaseURI, "/") + "/" + fileName(name)})
+	}
+	schema = append(schema, g.typeSchema(name)...)
+
+	// Referenced types may reference others in turn
+	defs := make(map[string]object)
+	for {
+		var pending []string
+		for ref := range g.refs {
+			if _, ok := defs[ref]; !ok {
+				pending = append(pending, ref)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		for _, ref := range pending {
+			defs[ref] = g.typeSchema(ref)
+		}
+	}
+
+	if len(defs) > 0 {
+		refs := make([]string, 0, len(defs))
+		for ref := range defs {
+			refs = append(refs, ref)
+		}
+		sort.Strings(refs)
+
+		defsObj := make(object, len(refs))
+		for i, ref := range refs {
+			defsObj[i] = member{ref, defs[ref]}
+		}
+		schema = append(schema, member{"$defs", defsObj})
+	}
+
+	enc := json.NewEncoder(g)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schema)
+}
+
+// typeSchema returns the schema of a named type.
+func (g *Generator) typeSchema(name string) object {
+	d := g.decls[name]
+	ts := d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+
+	schema := object{{"title", name}}
+	if descr := g.description(d.Doc); descr != "" {
+		schema = append(schema, member{"description", descr})
+	}
+
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Object:
+		schema = append(schema, g.objectSchema(v.Object.Fields, g.opts.Strict)...)
+	case *ast.TypeSpec_Interface:
+		// Implementations may declare more fields than their interfaces
+		schema = append(schema, g.objectSchema(v.Interface.Fields, false)...)
+	case *ast.TypeSpec_Union:
+		var members []interface{}
+		for _, mem := range v.Union.Members {
+			members = append(members, g.ref(mem.Name))
+		}
+		schema = append(schema, member{"oneOf", members})
+	case *ast.TypeSpec_Enum:
+		var vals []interface{}
+		if v.Enum.Values != nil {
+			for _, val := range v.Enum.Values.List {
+				vals = append(vals, val.Name.Name)
+			}
+		}
+		schema = append(schema, member{"type", "string"}, member{"enum", vals})
+	case *ast.TypeSpec_Input:
+		schema = append(schema, g.inputSchema(v.Input.Fields)...)
+	}
+	return schema
+}
+
+func (g *Generator) objectSchema(fields *ast.FieldList, strict bool) object {
+	props := object{}
+	var required []interface{}
+	if fields != nil {
+		for _, f := range fields.List {
+			typ := fieldType(f)
+			if _, ok := typ.(*ast.NonNull); ok {
+				required = append(required, f.Name.Name)
+			}
+
+			props = append(props, member{f.Name.Name, g.propSchema(typ, f.Doc, f.Directives, nil)})
+		}
+	}
+
+	return properties(props, required, strict)
+}
+
+// inputSchema returns the schema of an input type, whose non-null fields
+// are required unless they have a default.
+//
+func (g *Generator) inputSchema(fields *ast.InputValueList) object {
+	props := object{}
+	var required []interface{}
+	if fields != nil {
+		for _, f := range fields.List {
+			typ := inputValueType(f)
+			if _, ok := typ.(*ast.NonNull); ok && f.Default == nil {
+				required = append(required, f.Name.Name)
+			}
+
+			props = append(props, member{f.Name.Name, g.propSchema(typ, f.Doc, f.Directives, f.Default)})
+		}
+	}
+
+	return properties(props, required, g.opts.Strict)
+}
+
+func properties(props object, required []interface{}, strict bool) object {
+	schema := object{{"type", "object"}, {"properties", props}}
+	if len(required) > 0 {
+		schema = append(schema, member{"required", required})
+	}
+	if strict {
+		schema = append(schema, member{"additionalProperties", false})
+	}
+	return schema
+}
+
+// propSchema returns the schema of a field along with its annotations.
+func (g *Generator) propSchema(typ interface{}, doc *ast.DocGroup, dirs []*ast.DirectiveLit, def interface{}) object {
+	schema := g.schemaOf(typ)
+
+	if descr := g.description(doc); descr != "" {
+		schema = append(schema, member{"description", descr})
+	}
+	if def != nil {
+		schema = append(schema, member{"default", value(defaultValue(def))})
+	}
+	for _, d := range dirs {
+		if d.Name == "deprecated" {
+			schema = append(schema, member{"deprecated", true})
+			break
+		}
+	}
+	return schema
+}
+
+var jsonTypes = map[string]string{
+	"Int":     "integer",
+	"Float":   "number",
+	"String":  "string",
+	"Boolean": "boolean",
+	"ID":      "string",
+}
+
+// schemaOf returns the schema of a GraphQL type. Nullable types also
+// accept null.
+//
+func (g *Generator) schemaOf(typ interface{}) object {
+	if nn, ok := typ.(*ast.NonNull); ok {
+		switch w := nn.Type.(type) {
+		case *ast.NonNull_Ident:
+			return g.namedSchema(w.Ident.Name)
+		case *ast.NonNull_List:
+			return g.listSchema(w.List)
+		}
+	}
+
+	switch v := typ.(type) {
+	case *ast.Ident:
+		return nullable(g.namedSchema(v.Name))
+	case *ast.List:
+		return nullable(g.listSchema(v))
+	}
+	return object{}
+}
+
+func (g *Generator) listSchema(l *ast.List) object {
+	var elem interface{}
+	switch w := l.Type.(type) {
+	case *ast.List_Ident:
+		elem = w.Ident
+	case *ast.List_List:
+		elem = w.List
+	case *ast.List_NonNull:
+		elem = w.NonNull
+	}
+
+	return object{{"type", "array"}, {"items", g.schemaOf(elem)}}
+}
+
+func (g *Generator) namedSchema(name string) object {
+	if t, ok := jsonTypes[name]; ok {
+		return object{{"type", t}}
+	}
+	return g.ref(name)
+}
+
+// ref returns a reference to a type, which is either the type of the
+// document itself or one of its $defs.
+//
+func (g *Generator) ref(name string) object {
+	if name == g.root {
+		return object{{"$ref", "#"}}
+	}
+
+	g.refs[name] = true
+	return object{{"$ref", "#/$defs/" + name}}
+}
+
+// nullable allows a schema to also be null.
+func nullable(schema object) object {
+	if len(schema) > 0 && schema[0].key == "type" {
+		return append(object{{"type", []interface{}{schema[0].val, "null"}}}, schema[1:]...)
+	}
+	return object{{"anyOf", []interface{}{schema, object{{"type", "null"}}}}}
+}
+
+func fieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func inputValueType(a *ast.InputValue) interface{} {
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func defaultValue(def interface{}) interface{} {
+	switch v := def.(type) {
+	case *ast.InputValue_BasicLit:
+		return v.BasicLit
+	case *ast.InputValue_CompositeLit:
+		return v.CompositeLit
+	}
+	return nil
+}
+
+// value converts a GraphQL literal to its JSON value. Enum values are
+// strings and numbers are normalized, so 1 and 1.0 are both encoded as 1.
+//
+func value(val interface{}) interface{} {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		switch v.Kind {
+		case token.Token_STRING:
+			return unquote(v.Value)
+		case token.Token_INT, token.Token_FLOAT:
+			return json.Number(normNumber(v.Value))
+		case token.Token_BOOL:
+			return v.Value == "true"
+		case token.Token_NULL:
+			return nil
+		}
+		return v.Value
+	case *ast.CompositeLit:
+		switch w := v.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			return value(w.BasicLit)
+		case *ast.CompositeLit_ListLit:
+			return listValue(w.ListLit)
+		case *ast.CompositeLit_ObjLit:
+			obj := make(object, len(w.ObjLit.Fields))
+			for i, pair := range w.ObjLit.Fields {
+				obj[i] = member{pair.Key.Name, value(pair.Val)}
+			}
+			return obj
+		}
+	}
+	return nil
+}
+
+func listValue(l *ast.ListLit) []interface{} {
+	vals := []interface{}{}
+	switch w := l.List.(type) {
+	case *ast.ListLit_BasicList:
+		for _, bval := range w.BasicList.Values {
+			vals = append(vals, value(bval))
+		}
+	case *ast.ListLit_CompositeList:
+		for _, cval := range w.CompositeList.Values {
+			vals = append(vals, value(cval))
+		}
+	}
+	return vals
+}
+
+func normNumber(s string) string {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func unquote(s string) string {
+	if strings.HasPrefix(s, `"""`) {
+		return strings.TrimSpace(strings.Trim(s, `"`))
+	}
+
+	u, err := strconv.Unquote(s)
+	if err != nil {
+		return strings.Trim(s, `"`)
+	}
+	return u
+}
+
+func (g *Generator) description(doc *ast.DocGroup) string {
+	if doc == nil || !g.opts.Descriptions {
+		return ""
+	}
+	return strings.TrimSpace(doc.Text())
+}
+
+// member is a single key/value pair of an object.
+type member struct {
+	key string
+	val interface{}
+}
+
+// object is a JSON object which keeps the order of its members, since
+// properties are easier to read in the order they're declared.
+//
+type object []member
+
+// MarshalJSON implements the json.Marshaler interface.
+func (o object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+
+		val, err := marshal(m.val)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(val)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Strict:       true,
+		Descriptions: true,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "jsonschema" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		schemaOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range schemaOpts.Fields {
+			switch arg.Key.Name {
+			case "baseUri":
+				gOpts.BaseURI = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			case "strict":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Strict = b
+			case "descriptions":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Descriptions = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts != nil {
+		if u, ok := opts["baseUri"]; ok {
+			gOpts.BaseURI, _ = u.(string)
+		}
+		if s, ok := opts["strict"]; ok {
+			gOpts.Strict, _ = s.(bool)
+		}
+		if d, ok := opts["descriptions"]; ok {
+			gOpts.Descriptions, _ = d.(bool)
+		}
+	}
+
+	if gOpts.BaseURI != "" && !strings.Contains(gOpts.BaseURI, "://") {
+		return gOpts, fmt.Errorf("baseUri must be an absolute URI: %s", gOpts.BaseURI)
+	}
+	return
+}
@@ -0,0 +1,443 @@
+package jsonschema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output files")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDirName   = flag.String("expectedDir", "testdata", "Specify a directory which contains the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output directory is in the current working directory
+	if !filepath.IsAbs(*exDirName) {
+		*exDirName = filepath.Join(wd, *exDirName)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+type noopCloser struct {
+	io.Writer
+}
+
+func (noopCloser) Close() error { return nil }
+
+// testCtx collects every file a generator writes.
+type testCtx map[string]*bytes.Buffer
+
+func (ctx testCtx) Open(name string) (io.WriteCloser, error) {
+	b := new(bytes.Buffer)
+	ctx[name] = b
+	return noopCloser{b}, nil
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected jsonschema output files: %s", *exDirName)
+		return
+	}
+	t.Logf("updating expected jsonschema output files: %s", *exDirName)
+
+	files := make(testCtx)
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), files)
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = os.MkdirAll(*exDirName, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for name, b := range files {
+		err = ioutil.WriteFile(filepath.Join(*exDirName, filepath.Base(name)), b.Bytes(), 0644)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+}
+
+func TestSchemaOf(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+
+	testCases := []struct {
+		Name string
+		Type interface{}
+		JSON string
+	}{
+		{Name: "Nullable", Type: &ast.Ident{Name: "Int"}, JSON: `{"type":["integer","null"]}`},
+		{Name: "NonNull", Type: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}, JSON: `{"type":"string"}`},
+		{Name: "Ref", Type: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "User"}}}, JSON: `{"$ref":"#/$defs/User"}`},
+		{Name: "NullableRef", Type: &ast.Ident{Name: "User"}, JSON: `{"anyOf":[{"$ref":"#/$defs/User"},{"type":"null"}]}`},
+		{
+			Name: "List",
+			Type: &ast.NonNull{Type: &ast.NonNull_List{List: &ast.List{Type: &ast.List_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Float"}}}}}}},
+			JSON: `{"type":"array","items":{"type":"number"}}`,
+		},
+		{
+			Name: "NullableList",
+			Type: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Boolean"}}},
+			JSON: `{"type":["array","null"],"items":{"type":["boolean","null"]}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			b, err := json.Marshal(g.schemaOf(testCase.Type))
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if string(b) != testCase.JSON {
+				subT.Errorf("expected: %s, but got: %s", testCase.JSON, b)
+			}
+		})
+	}
+
+	if !g.refs["User"] {
+		t.Error("expected User to be referenced")
+	}
+}
+
+func TestValue(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		Value interface{}
+		JSON  string
+	}{
+		{Name: "String", Value: &ast.BasicLit{Kind: token.Token_STRING, Value: `"a\"b"`}, JSON: `"a\"b"`},
+		{Name: "Int", Value: &ast.BasicLit{Kind: token.Token_INT, Value: "1"}, JSON: `1`},
+		{Name: "Float", Value: &ast.BasicLit{Kind: token.Token_FLOAT, Value: "1.0"}, JSON: `1`},
+		{Name: "Bool", Value: &ast.BasicLit{Kind: token.Token_BOOL, Value: "false"}, JSON: `false`},
+		{Name: "Enum", Value: &ast.BasicLit{Kind: token.Token_IDENT, Value: "NORTH"}, JSON: `"NORTH"`},
+		{
+			Name: "List",
+			Value: &ast.CompositeLit{Value: &ast.CompositeLit_ListLit{ListLit: &ast.ListLit{
+				List: &ast.ListLit_BasicList{BasicList: &ast.ListLit_Basic{Values: []*ast.BasicLit{
+					{Kind: token.Token_INT, Value: "1"},
+					{Kind: token.Token_FLOAT, Value: "2.5"},
+				}}},
+			}}},
+			JSON: `[1,2.5]`,
+		},
+		{
+			Name: "Object",
+			Value: &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+				Fields: []*ast.ObjLit_Pair{
+					{
+						Key: &ast.Ident{Name: "y"},
+						Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_INT, Value: "2"}}},
+					},
+					{
+						Key: &ast.Ident{Name: "x"},
+						Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_NULL, Value: "null"}}},
+					},
+				},
+			}}},
+			JSON: `{"y":2,"x":null}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			b, err := json.Marshal(value(testCase.Value))
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if string(b) != testCase.JSON {
+				subT.Errorf("expected: %s, but got: %s", testCase.JSON, b)
+			}
+		})
+	}
+}
+
+func TestDocument(t *testing.T) {
+	gqlSrc := `"A node in a tree."
+type Node {
+	value: Int!
+	children: [Node!]!
+	kind: Kind @deprecated
+}
+
+enum Kind {
+	LEAF
+	BRANCH
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	files := make(testCtx)
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), files)
+	err = g.Generate(ctx, doc, map[string]interface{}{"strict": false})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(files) != 1 || files["Node.schema.json"] == nil {
+		t.Fatalf("expected only Node.schema.json to be generated, but got: %v", files)
+	}
+
+	ex := []byte(`{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "Node",
+  "description": "A node in a tree.",
+  "type": "object",
+  "properties": {
+    "value": {
+      "type": "integer"
+    },
+    "children": {
+      "type": "array",
+      "items": {
+        "$ref": "#"
+      }
+    },
+    "kind": {
+      "anyOf": [
+        {
+          "$ref": "#/$defs/Kind"
+        },
+        {
+          "type": "null"
+        }
+      ],
+      "deprecated": true
+    }
+  },
+  "required": [
+    "value",
+    "children"
+  ],
+  "$defs": {
+    "Kind": {
+      "title": "Kind",
+      "type": "string",
+      "enum": [
+        "LEAF",
+        "BRANCH"
+      ]
+    }
+  }
+}
+`)
+
+	gen.CompareBytes(t, ex, files["Node.schema.json"].Bytes())
+}
+
+func TestOptions(t *testing.T) {
+	doc := &ast.Document{
+		Directives: []*ast.DirectiveLit{
+			{
+				Name: "jsonschema",
+				Args: &ast.CallExpr{Args: []*ast.Arg{{
+					Name: &ast.Ident{Name: "options"},
+					Value: &ast.Arg_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+						Fields: []*ast.ObjLit_Pair{
+							{
+								Key: &ast.Ident{Name: "baseUri"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: `"https://example.com"`}}},
+							},
+							{
+								Key: &ast.Ident{Name: "strict"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_BOOL, Value: "false"}}},
+							},
+						},
+					}}}},
+				}}},
+			},
+		},
+	}
+
+	gOpts, err := getOptions(doc, map[string]interface{}{"descriptions": false})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.BaseURI != "https://example.com" || gOpts.Strict || gOpts.Descriptions {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+
+	_, err = getOptions(&ast.Document{}, map[string]interface{}{"baseUri": "schemas"})
+	if err == nil {
+		t.Error("expected relative base uri to fail")
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	files := make(testCtx)
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), files)
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	exFiles, err := filepath.Glob(filepath.Join(*exDirName, "*.schema.json"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(exFiles) != len(files) {
+		t.Fatalf("expected %d files, but got: %d", len(exFiles), len(files))
+	}
+
+	for _, exFile := range exFiles {
+		name := filepath.Base(exFile)
+		t.Run(name, func(subT *testing.T) {
+			b, ok := files[name]
+			if !ok {
+				subT.Fatalf("expected %s to be generated", name)
+			}
+
+			ex, err := ioutil.ReadFile(exFile)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			gen.CompareBytes(subT, ex, b.Bytes())
+		})
+	}
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := &Generator{}
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `"A user of the service."
+input User {
+	name: String!
+	age: Int = 18
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	files := make(testCtx)
+	ctx := gen.WithContext(context.Background(), files) // Pass in an actual
+	err = g.Generate(ctx, doc, nil)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Println(name)
+		fmt.Print(files[name].String())
+	}
+	// Output:
+	// User.schema.json
+	// {
+	//   "$schema": "https://json-schema.org/draft/2020-12/schema",
+	//   "title": "User",
+	//   "description": "A user of the service.",
+	//   "type": "object",
+	//   "properties": {
+	//     "name": {
+	//       "type": "string"
+	//     },
+	//     "age": {
+	//       "type": [
+	//         "integer",
+	//         "null"
+	//       ],
+	//       "default": 18
+	//     }
+	//   },
+	//   "required": [
+	//     "name"
+	//   ],
+	//   "additionalProperties": false
+	// }
+}
@@ -0,0 +1,73 @@
+package com.example.graphql
+
+/** Version represents an API version. */
+typealias Version = Any
+
+/** Echo represents an echo message. */
+data class Echo(
+    /** msg contains the provided message. */
+    val msg: String,
+) : SearchResult
+
+/** Result represents a search result. */
+data class Result(
+    /** total yields the total number of search results. */
+    override val total: Int? = null,
+    /** edges contains the search results. */
+    override val edges: List<Node?>? = null,
+    /** hasNextPage tells if there are more search results. */
+    override val hasNextPage: Boolean? = null,
+) : Connection, SearchResult
+
+/** Connection represents a set of edges, which are meant to be paginated. */
+interface Connection {
+    /** total returns the total number of edges. */
+    val total: Int?
+
+    /** edges contains the current page of edges. */
+    val edges: List<Node?>?
+
+    /** hasNextPage tells if there exists more edges. */
+    val hasNextPage: Boolean?
+}
+
+/** Node represents a node. */
+interface Node {
+    /** id uniquely identifies the node. */
+    val id: String
+}
+
+/** SearchResult is a test union type */
+sealed interface SearchResult
+
+/** Direction represents a cardinal direction. */
+enum class Direction {
+    /** EnumValue description */
+    NORTH,
+    EAST,
+    SOUTH,
+    /** EnumValue Description and Directives. */
+    WEST
+}
+
+/** Point represents a 2-D geo point. */
+data class Point(
+    val x: Double,
+    val y: Double,
+    val label: String? = "origin",
+    val visible: Boolean? = true,
+    val heading: Direction? = Direction.NORTH,
+    val weights: List<Double?>? = listOf(1.0, 2.5),
+)
+
+/** QueryResolver resolves the fields of [Query]. */
+interface QueryResolver {
+    /** version returns the current API version. */
+    suspend fun version(): Version?
+
+    /** echo echos a message. */
+    suspend fun echo(text: String): Echo?
+
+    /** search performs a search over some data set. */
+    suspend fun search(text: String?, terms: List<String?>?): Result?
+}
@@ -0,0 +1,816 @@
+// Package kotlin contains a Kotlin generator for GraphQL Documents.
+// The generated code consists of data classes for the schema types
+// along with resolver interfaces for fields which must be resolved.
+//
+package kotlin
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/gen/printer"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+// Options contains the options for the Kotlin generator.
+type Options struct {
+	// Package is the Kotlin package of the generated file
+	Package string
+
+	// Copy descriptions to KDoc comments (default: true)
+	Descriptions bool
+
+	// Coroutines generates suspend resolver functions (default: false)
+	Coroutines bool
+}
+
+// Generator generates Kotlin code for a GraphQL schema.
+type Generator struct {
+	printer.Printer
+
+	log *zap.Logger
+
+	// unions maps a type to the unions it's a member of
+	unions map[string][]string
+
+	// ifaceFields maps an interface to the names of its properties
+	ifaceFields map[string]map[string]bool
+}
+
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Printer.Reset()
+	g.Indent = "    "
+	g.unions = make(map[string][]string)
+	g.ifaceFields = make(map[string]map[string]bool)
+}
+
+// Generate generates Kotlin code for the given document.
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "kotlin",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("kotlin").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	gOpts, oerr := getOptions(doc, opts)
+	if oerr != nil {
+		return oerr
+	}
+
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Union:
+			for _, mem := range v.Union.Members {
+				g.unions[mem.Name] = append(g.unions[mem.Name], name)
+			}
+		case *ast.TypeSpec_Interface:
+			props := make(map[string]bool)
+			for _, f := range properties(v.Interface.Fields) {
+				props[f.Name.Name] = true
+			}
+			g.ifaceFields[name] = props
+		}
+	}
+
+	if gOpts.Package != "" {
+		g.P("package ", gOpts.Package)
+		g.P()
+	}
+
+	// Generate types
+	g.log.Info("generating types")
+	roots := rootTypes(doc)
+	first := true
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		name := ts.TypeSpec.Name.Name
+		if roots[name] {
+			continue
+		}
+
+		descr := d.Doc
+		if !gOpts.Descriptions {
+			descr = nil
+		}
+
+		switch ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Directive, *ast.TypeSpec_Schema:
+			continue
+		}
+
+		if !first {
+			g.P()
+		}
+		first = false
+
+		switch ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar:
+			g.generateScalar(name, descr)
+		case *ast.TypeSpec_Object:
+			g.generateObject(name, descr, gOpts.Descriptions, ts.TypeSpec)
+		case *ast.TypeSpec_Interface:
+			g.generateInterface(name, descr, gOpts.Descriptions, ts.TypeSpec)
+		case *ast.TypeSpec_Union:
+			g.generateUnion(name, descr)
+		case *ast.TypeSpec_Enum:
+			g.generateEnum(name, descr, gOpts.Descriptions, ts.TypeSpec)
+		case *ast.TypeSpec_Input:
+			g.generateInput(name, descr, gOpts.Descriptions, ts.TypeSpec)
+		}
+	}
+
+	// Generate resolvers
+	g.log.Info("generating resolvers")
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		var fields *ast.FieldList
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			fields = v.Object.Fields
+		case *ast.TypeSpec_Interface:
+			fields = v.Interface.Fields
+		default:
+			continue
+		}
+
+		name := ts.TypeSpec.Name.Name
+		resolvers := resolverFields(fields, roots[name])
+		if len(resolvers) == 0 {
+			continue
+		}
+
+		if !first {
+			g.P()
+		}
+		first = false
+		g.generateResolver(name, !roots[name], resolvers, gOpts)
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	// Open file to write to
+	ktFile, err := gCtx.Open(filepath.Join(filepath.Dir(doc.Name), fileName(doc)+".kt"))
+	if err != nil {
+		return
+	}
+	defer ktFile.Close()
+
+	// Write generated output
+	_, err = g.WriteTo(ktFile)
+	return
+}
+
+// fileName returns the name, without extension, of the generated file
+// which follows the Kotlin convention of PascalCase file names.
+//
+func fileName(doc *ast.Document) string {
+	base := filepath.Base(doc.Name)
+	name := pascalCase(base[:len(base)-len(filepath.Ext(base))])
+	if name == "" {
+		return "Schema"
+	}
+	return name
+}
+
+// rootTypes returns the names of the root operation types.
+func rootTypes(doc *ast.Document) map[string]bool {
+	roots := make(map[string]bool, 3)
+	if doc.Schema == nil {
+		roots["Query"] = true
+		roots["Mutation"] = true
+		roots["Subscription"] = true
+		return roots
+	}
+
+	schema := doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Schema).Schema
+	for _, f := range schema.RootOps.List {
+		roots[f.Type.(*ast.Field_Ident).Ident.Name] = true
+	}
+	return roots
+}
+
+// properties returns the fields which take no arguments and are
+// therefore generated as properties instead of resolvers.
+//
+func properties(fields *ast.FieldList) (props []*ast.Field) {
+	if fields == nil {
+		return
+	}
+
+	for _, f := range fields.List {
+		if f.Args == nil || len(f.Args.List) == 0 {
+			props = append(props, f)
+		}
+	}
+	return
+}
+
+// resolverFields returns the fields which must be resolved. Every field
+// of a root type is resolved, as are fields which take arguments.
+//
+func resolverFields(fields *ast.FieldList, root bool) (resolvers []*ast.Field) {
+	if fields == nil {
+		return
+	}
+
+	for _, f := range fields.List {
+		if root || (f.Args != nil && len(f.Args.List) > 0) {
+			resolvers = append(resolvers, f)
+		}
+	}
+	return
+}
+
+func (g *Generator) generateScalar(name string, doc *ast.DocGroup) {
+	g.printKDoc(doc)
+	g.P("typealias ", name, " = Any")
+}
+
+func (g *Generator) generateObject(name string, doc *ast.DocGroup, descr bool, ts *ast.TypeSpec) {
+	obj := ts.Type.(*ast.TypeSpec_Object).Object
+
+	var supers []string
+	overrides := make(map[string]bool)
+	for _, inter := range obj.Interfaces {
+		supers = append(supers, inter.Name)
+		for f := range g.ifaceFields[inter.Name] {
+			overrides[f] = true
+		}
+	}
+	supers = append(supers, g.unions[name]...)
+
+	var params []param
+	for _, f := range properties(obj.Fields) {
+		params = append(params, param{
+			name:     f.Name.Name,
+			typ:      fieldType(f),
+			doc:      f.Doc,
+			override: overrides[f.Name.Name],
+		})
+	}
+
+	g.printKDoc(doc)
+	g.generateClass(name, params, supers, descr)
+}
+
+func (g *Generator) generateInterface(name string, doc *ast.DocGroup, descr bool, ts *ast.TypeSpec) {
+	inter := ts.Type.(*ast.TypeSpec_Interface).Interface
+
+	g.printKDoc(doc)
+
+	props := properties(inter.Fields)
+	if len(props) == 0 {
+		g.P("interface ", name)
+		return
+	}
+
+	g.P("interface ", name, " {")
+	g.In()
+	for i, f := range props {
+		if i > 0 {
+			g.P()
+		}
+
+		if descr {
+			g.printKDoc(f.Doc)
+		}
+		g.P("val ", identifier(f.Name.Name), ": ", kotlinType(fieldType(f)))
+	}
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateUnion(name string, doc *ast.DocGroup) {
+	g.printKDoc(doc)
+	g.P("sealed interface ", name)
+}
+
+func (g *Generator) generateEnum(name string, doc *ast.DocGroup, descr bool, ts *ast.TypeSpec) {
+	enum := ts.Type.(*ast.TypeSpec_Enum).Enum
+
+	g.printKDoc(doc)
+	g.P("enum class ", name, " {")
+	g.In()
+
+	var values []*ast.Field
+	if enum.Values != nil {
+		values = enum.Values.List
+	}
+	for i, v := range values {
+		if descr {
+			g.printKDoc(v.Doc)
+		}
+
+		sep := ","
+		if i == len(values)-1 {
+			sep = ""
+		}
+		g.P(identifier(v.Name.Name), sep)
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateInput(name string, doc *ast.DocGroup, descr bool, ts *ast.TypeSpec) {
+	input := ts.Type.(*ast.TypeSpec_Input).Input
+
+	var params []param
+	if input.Fields != nil {
+		for _, f := range input.Fields.List {
+			params = append(params, param{
+				name: f.Name.Name,
+				typ:  inputValueType(f),
+				doc:  f.Doc,
+				def:  f.Default,
+			})
+		}
+	}
+
+	g.printKDoc(doc)
+	g.generateClass(name, params, nil, descr)
+}
+
+// param is a constructor parameter of a generated data class.
+type param struct {
+	name     string
+	typ      interface{}
+	doc      *ast.DocGroup
+	def      interface{}
+	override bool
+}
+
+// generateClass generates a data class with a property for every param.
+// Data classes require at least one property so a plain class is generated
+// when there are none.
+//
+func (g *Generator) generateClass(name string, params []param, supers []string, descr bool) {
+	var impls string
+	if len(supers) > 0 {
+		impls = " : " + strings.Join(supers, ", ")
+	}
+
+	if len(params) == 0 {
+		g.P("class ", name, impls)
+		return
+	}
+
+	g.P("data class ", name, "(")
+	g.In()
+	for _, p := range params {
+		if descr {
+			g.printKDoc(p.doc)
+		}
+
+		g.WriteIndent()
+		if p.override {
+			g.WriteString("override ")
+		}
+		g.WriteString("val ")
+		g.WriteString(identifier(p.name))
+		g.WriteString(": ")
+		g.WriteString(kotlinType(p.typ))
+		g.printDefault(p.typ, p.def)
+		g.WriteString(",\n")
+	}
+	g.Out()
+	g.P(")", impls)
+}
+
+// generateResolver generates an interface with a function for every field
+// which must be resolved. Functions of non-root types are also passed
+// the parent object.
+//
+func (g *Generator) generateResolver(name string, parent bool, fields []*ast.Field, gOpts *Options) {
+	fun := "fun "
+	if gOpts.Coroutines {
+		fun = "suspend fun "
+	}
+
+	if gOpts.Descriptions {
+		g.P("/** ", name, "Resolver resolves the fields of [", name, "]. */")
+	}
+	g.P("interface ", name, "Resolver {")
+	g.In()
+
+	for i, f := range fields {
+		if i > 0 {
+			g.P()
+		}
+		if gOpts.Descriptions {
+			g.printKDoc(f.Doc)
+		}
+
+		var args []*ast.InputValue
+		if f.Args != nil {
+			args = f.Args.List
+		}
+
+		var params []string
+		if parent {
+			params = append(params, parentName(args)+": "+name)
+		}
+		for _, a := range args {
+			params = append(params, identifier(a.Name.Name)+": "+kotlinType(inputValueType(a)))
+		}
+
+		g.P(fun, identifier(f.Name.Name), "(", strings.Join(params, ", "), "): ", kotlinType(fieldType(f)))
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+// parentName returns a name for the parent parameter of a resolver
+// which doesn't clash with any of the field arguments.
+//
+func parentName(args []*ast.InputValue) string {
+	name := "parent"
+	for {
+		clash := false
+		for _, a := range args {
+			clash = clash || a.Name.Name == name
+		}
+		if !clash {
+			return name
+		}
+		name += "_"
+	}
+}
+
+func fieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func inputValueType(a *ast.InputValue) interface{} {
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+var kotlinTypes = map[string]string{
+	"Int":     "Int",
+	"Float":   "Double",
+	"String":  "String",
+	"Boolean": "Boolean",
+	"ID":      "String",
+}
+
+// kotlinType returns the Kotlin type of a GraphQL type. Nullable GraphQL
+// types map to nullable Kotlin types and non-null types drop the ?.
+//
+func kotlinType(typ interface{}) string {
+	if nn, ok := typ.(*ast.NonNull); ok {
+		switch w := nn.Type.(type) {
+		case *ast.NonNull_Ident:
+			return baseType(w.Ident)
+		case *ast.NonNull_List:
+			return baseType(w.List)
+		}
+	}
+	return baseType(typ) + "?"
+}
+
+// baseType returns the Kotlin type of a GraphQL type without any nullability.
+func baseType(typ interface{}) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		if t, ok := kotlinTypes[v.Name]; ok {
+			return t
+		}
+		return v.Name
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			typ = w.Ident
+		case *ast.List_List:
+			typ = w.List
+		case *ast.List_NonNull:
+			typ = w.NonNull
+		}
+		return "List<" + kotlinType(typ) + ">"
+	}
+	return "Any"
+}
+
+// namedType returns the name of the underlying named type.
+func namedType(typ interface{}) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		return v.Name
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			return w.Ident.Name
+		case *ast.List_List:
+			return namedType(w.List)
+		case *ast.List_NonNull:
+			return namedType(w.NonNull)
+		}
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return w.Ident.Name
+		case *ast.NonNull_List:
+			return namedType(w.List)
+		}
+	}
+	return ""
+}
+
+// printDefault prints the default value of an input field, if any.
+// Nullable fields without a default value default to null.
+//
+func (g *Generator) printDefault(typ, def interface{}) {
+	var val interface{}
+	switch v := def.(type) {
+	case *ast.InputValue_BasicLit:
+		val = v.BasicLit
+	case *ast.InputValue_CompositeLit:
+		val = v.CompositeLit
+		if _, ok := v.CompositeLit.Value.(*ast.CompositeLit_ObjLit); ok {
+			// Input objects have no literal syntax in Kotlin
+			val = nil
+		}
+	}
+
+	if val == nil {
+		if _, ok := typ.(*ast.NonNull); !ok {
+			g.WriteString(" = null")
+		}
+		return
+	}
+
+	g.WriteString(" = ")
+	g.printVal(namedType(typ), val)
+}
+
+// printVal prints a value of the given named type
+func (g *Generator) printVal(typ string, val interface{}) {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		switch v.Kind {
+		case token.Token_STRING:
+			s, err := strconv.Unquote(v.Value)
+			if err != nil {
+				s = strings.Trim(v.Value, `"`)
+			}
+			g.WriteString(quote(s))
+		case token.Token_IDENT:
+			g.WriteString(typ)
+			g.WriteByte('.')
+			g.WriteString(identifier(v.Value))
+		case token.Token_INT:
+			g.WriteString(v.Value)
+			if typ == "Float" {
+				g.WriteString(".0")
+			}
+		default:
+			g.WriteString(v.Value)
+		}
+	case *ast.ListLit:
+		g.printList(typ, v)
+	case *ast.ObjLit:
+		g.WriteString("null")
+	case *ast.CompositeLit:
+		switch w := v.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			g.printVal(typ, w.BasicLit)
+		case *ast.CompositeLit_ListLit:
+			g.printVal(typ, w.ListLit)
+		case *ast.CompositeLit_ObjLit:
+			g.printVal(typ, w.ObjLit)
+		}
+	}
+}
+
+func (g *Generator) printList(typ string, v *ast.ListLit) {
+	g.WriteString("listOf(")
+
+	var vals []interface{}
+	switch w := v.List.(type) {
+	case *ast.ListLit_BasicList:
+		for _, bval := range w.BasicList.Values {
+			vals = append(vals, bval)
+		}
+	case *ast.ListLit_CompositeList:
+		for _, cval := range w.CompositeList.Values {
+			vals = append(vals, cval)
+		}
+	}
+
+	vLen := len(vals) - 1
+	for i, iv := range vals {
+		g.printVal(typ, iv)
+		if i != vLen {
+			g.WriteByte(',')
+			g.WriteByte(' ')
+		}
+	}
+
+	g.WriteByte(')')
+}
+
+// printKDoc prints a description as a KDoc comment. Kotlin block
+// comments nest, so both comment delimiters are escaped.
+//
+func (g *Generator) printKDoc(doc *ast.DocGroup) {
+	if doc == nil {
+		return
+	}
+
+	text := strings.TrimSpace(doc.Text())
+	if text == "" {
+		return
+	}
+	text = strings.Replace(text, "*/", "*&#47;", -1)
+	text = strings.Replace(text, "/*", "&#47;*", -1)
+
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		g.P("/** ", lines[0], " */")
+		return
+	}
+
+	g.P("/**")
+	for _, line := range lines {
+		if len(line) == 0 {
+			g.P(" *")
+			continue
+		}
+		g.P(" * ", line)
+	}
+	g.P(" */")
+}
+
+// quote returns s as a double quoted Kotlin string literal.
+func quote(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '$':
+			b.WriteString(`\$`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+var keywords = map[string]bool{
+	"as": true, "break": true, "class": true, "continue": true, "do": true,
+	"else": true, "false": true, "for": true, "fun": true, "if": true,
+	"in": true, "interface": true, "is": true, "null": true, "object": true,
+	"package": true, "return": true, "super": true, "this": true, "throw": true,
+	"true": true, "try": true, "typealias": true, "typeof": true, "val": true,
+	"var": true, "when": true, "while": true,
+}
+
+// identifier returns a valid Kotlin identifier for a GraphQL name.
+func identifier(name string) string {
+	if keywords[name] {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// pascalCase converts a file name e.g. star-wars to a PascalCase name e.g. StarWars.
+func pascalCase(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Descriptions: true,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "kotlin" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		ktOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range ktOpts.Fields {
+			switch arg.Key.Name {
+			case "package":
+				gOpts.Package = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			case "descriptions":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Descriptions = b
+			case "coroutines":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Coroutines = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts == nil {
+		return
+	}
+	if p, ok := opts["package"]; ok {
+		gOpts.Package, _ = p.(string)
+	}
+	if d, ok := opts["descriptions"]; ok {
+		gOpts.Descriptions, _ = d.(bool)
+	}
+	if c, ok := opts["coroutines"]; ok {
+		gOpts.Coroutines, _ = c.(bool)
+	}
+
+	return
+}
@@ -0,0 +1,497 @@
+package kotlin
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "Test.kt", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected kotlin output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected kotlin output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestNames(t *testing.T) {
+	testCases := []struct {
+		Doc  string
+		File string
+	}{
+		{Doc: "test.gql", File: "Test"},
+		{Doc: "star-wars.graphql", File: "StarWars"},
+		{Doc: "dir/api_v2.gql", File: "ApiV2"},
+		{Doc: "_.gql", File: "Schema"},
+	}
+
+	for _, testCase := range testCases {
+		if name := fileName(&ast.Document{Name: testCase.Doc}); name != testCase.File {
+			t.Errorf("expected file name %s for %s, but got: %s", testCase.File, testCase.Doc, name)
+		}
+	}
+
+	if id := identifier("object"); id != "`object`" {
+		t.Errorf("expected keyword to be escaped, but got: %s", id)
+	}
+
+	args := []*ast.InputValue{{Name: &ast.Ident{Name: "parent"}}}
+	if name := parentName(args); name != "parent_" {
+		t.Errorf("expected parent parameter to not clash with arguments, but got: %s", name)
+	}
+}
+
+func TestKotlinType(t *testing.T) {
+	testCases := []struct {
+		Type   interface{}
+		Kotlin string
+	}{
+		{Type: &ast.Ident{Name: "Int"}, Kotlin: "Int?"},
+		{Type: &ast.Ident{Name: "ID"}, Kotlin: "String?"},
+		{Type: &ast.Ident{Name: "Time"}, Kotlin: "Time?"},
+		{Type: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "User"}}}, Kotlin: "User"},
+		{
+			Type:   &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Boolean"}}},
+			Kotlin: "List<Boolean?>?",
+		},
+		{
+			Type:   &ast.NonNull{Type: &ast.NonNull_List{List: &ast.List{Type: &ast.List_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Float"}}}}}}},
+			Kotlin: "List<Double>",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if typ := kotlinType(testCase.Type); typ != testCase.Kotlin {
+			t.Errorf("expected: %s, but got: %s", testCase.Kotlin, typ)
+		}
+	}
+}
+
+func TestObject(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+	g.unions["Test"] = []string{"Result"}
+	g.ifaceFields["Node"] = map[string]bool{"id": true}
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Object{
+		Object: &ast.ObjectType{
+			Interfaces: []*ast.Ident{{Name: "Node"}},
+			Fields: &ast.FieldList{
+				List: []*ast.Field{
+					{
+						Name: &ast.Ident{Name: "id"},
+						Type: &ast.Field_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}},
+					},
+					{
+						Name: &ast.Ident{Name: "in"},
+						Type: &ast.Field_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Test"}}}},
+					},
+					{
+						Name: &ast.Ident{Name: "friends"},
+						Type: &ast.Field_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Test"}}}},
+						Args: &ast.InputValueList{List: []*ast.InputValue{
+							{
+								Name: &ast.Ident{Name: "first"},
+								Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "Int"}},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}}
+
+	g.generateObject("Test", nil, false, ts)
+
+	ex := []byte("data class Test(\n    override val id: String,\n    val `in`: List<Test?>? = null,\n) : Node, Result\n")
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestEmptyObject(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+	g.unions["Test"] = []string{"Result"}
+
+	g.generateObject("Test", nil, false, &ast.TypeSpec{Type: &ast.TypeSpec_Object{Object: &ast.ObjectType{}}})
+
+	gen.CompareBytes(t, []byte("class Test : Result\n"), g.Bytes())
+}
+
+func TestInterface(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Interface{
+		Interface: &ast.InterfaceType{
+			Fields: &ast.FieldList{
+				List: []*ast.Field{
+					{
+						Name: &ast.Ident{Name: "id"},
+						Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "ID"}},
+					},
+				},
+			},
+		},
+	}}
+
+	g.generateInterface("Node", nil, false, ts)
+
+	ex := []byte(`interface Node {
+    val id: String?
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestEnum(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Enum{
+		Enum: &ast.EnumType{
+			Values: &ast.FieldList{
+				List: []*ast.Field{
+					{Name: &ast.Ident{Name: "A"}},
+					{Name: &ast.Ident{Name: "B"}},
+				},
+			},
+		},
+	}}
+
+	g.generateEnum("Test", nil, false, ts)
+
+	ex := []byte(`enum class Test {
+    A,
+    B
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestInput(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Input{
+		Input: &ast.InputType{
+			Fields: &ast.InputValueList{
+				List: []*ast.InputValue{
+					{
+						Name: &ast.Ident{Name: "id"},
+						Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}},
+					},
+					{
+						Name: &ast.Ident{Name: "label"},
+						Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "String"}},
+					},
+					{
+						Name: &ast.Ident{Name: "pattern"},
+						Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "String"}},
+						Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+							Kind:  token.Token_STRING,
+							Value: `"$name"`,
+						}},
+					},
+					{
+						Name: &ast.Ident{Name: "ratio"},
+						Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Float"}}}},
+						Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+							Kind:  token.Token_INT,
+							Value: "1",
+						}},
+					},
+					{
+						Name: &ast.Ident{Name: "dirs"},
+						Type: &ast.InputValue_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Direction"}}}},
+						Default: &ast.InputValue_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ListLit{
+							ListLit: &ast.ListLit{
+								List: &ast.ListLit_BasicList{BasicList: &ast.ListLit_Basic{Values: []*ast.BasicLit{
+									{Kind: token.Token_IDENT, Value: "NORTH"},
+									{Kind: token.Token_IDENT, Value: "SOUTH"},
+								}}},
+							},
+						}}},
+					},
+				},
+			},
+		},
+	}}
+
+	g.generateInput("Test", nil, false, ts)
+
+	ex := []byte(`data class Test(
+    val id: String,
+    val label: String? = null,
+    val pattern: String? = "\$name",
+    val ratio: Double = 1.0,
+    val dirs: List<Direction?>? = listOf(Direction.NORTH, Direction.SOUTH),
+)
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestResolver(t *testing.T) {
+	fields := []*ast.Field{
+		{
+			Name: &ast.Ident{Name: "friends"},
+			Type: &ast.Field_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "User"}}}}}},
+			Args: &ast.InputValueList{List: []*ast.InputValue{
+				{
+					Name: &ast.Ident{Name: "first"},
+					Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "Int"}},
+				},
+			}},
+		},
+	}
+
+	testCases := []struct {
+		Name string
+		Opts Options
+		Ex   string
+	}{
+		{
+			Name: "Blocking",
+			Ex: `interface UserResolver {
+    fun friends(parent: User, first: Int?): List<User?>
+}
+`,
+		},
+		{
+			Name: "Coroutines",
+			Opts: Options{Coroutines: true},
+			Ex: `interface UserResolver {
+    suspend fun friends(parent: User, first: Int?): List<User?>
+}
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			g := &Generator{}
+			g.Reset()
+
+			g.generateResolver("User", true, fields, &testCase.Opts)
+
+			gen.CompareBytes(subT, []byte(testCase.Ex), g.Bytes())
+		})
+	}
+}
+
+func TestKDoc(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	doc := &ast.DocGroup{List: []*ast.DocGroup_Doc{
+		{Text: "# First line.", Char: '#'},
+		{Text: "# Opens /* and ends */ a comment.", Char: '#'},
+	}}
+
+	g.printKDoc(doc)
+
+	ex := []byte(`/**
+ * First line.
+ * Opens &#47;* and ends *&#47; a comment.
+ */
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestOptions(t *testing.T) {
+	doc := &ast.Document{
+		Directives: []*ast.DirectiveLit{
+			{
+				Name: "kotlin",
+				Args: &ast.CallExpr{Args: []*ast.Arg{{
+					Name: &ast.Ident{Name: "options"},
+					Value: &ast.Arg_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+						Fields: []*ast.ObjLit_Pair{
+							{
+								Key: &ast.Ident{Name: "package"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: `"com.example"`}}},
+							},
+							{
+								Key: &ast.Ident{Name: "coroutines"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_BOOL, Value: "true"}}},
+							},
+						},
+					}}}},
+				}}},
+			},
+		},
+	}
+
+	gOpts, err := getOptions(doc, map[string]interface{}{"package": "org.example"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.Package != "org.example" || !gOpts.Coroutines || !gOpts.Descriptions {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := new(Generator)
+	g.Reset()
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `schema {
+	query: Query
+}
+
+"Query represents the queries this example provides."
+type Query {
+	hello: String
+}
+
+"A user of the service."
+type User {
+	name: String!
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, map[string]interface{}{"coroutines": true})
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Println(b.String())
+	// Output:
+	// /** A user of the service. */
+	// data class User(
+	//     val name: String,
+	// )
+	//
+	// /** QueryResolver resolves the fields of [Query]. */
+	// interface QueryResolver {
+	//     suspend fun hello(): String?
+	// }
+}
@@ -4,10 +4,30 @@ package main
 import (
 	"os"
 
+	"github.com/gqlc/gqlc/catalog"
 	"github.com/gqlc/gqlc/cmd"
+	"github.com/gqlc/gqlc/csharp"
+	"github.com/gqlc/gqlc/diagram"
 	"github.com/gqlc/gqlc/doc"
+	"github.com/gqlc/gqlc/fragments"
+	"github.com/gqlc/gqlc/gateway"
 	"github.com/gqlc/gqlc/golang"
+	"github.com/gqlc/gqlc/gqlgen"
+	"github.com/gqlc/gqlc/introspection"
+	"github.com/gqlc/gqlc/inventory"
+	"github.com/gqlc/gqlc/java"
 	"github.com/gqlc/gqlc/js"
+	"github.com/gqlc/gqlc/jsonschema"
+	"github.com/gqlc/gqlc/kotlin"
+	"github.com/gqlc/gqlc/openapi"
+	"github.com/gqlc/gqlc/proto"
+	"github.com/gqlc/gqlc/python"
+	"github.com/gqlc/gqlc/rust"
+	"github.com/gqlc/gqlc/sdl"
+	"github.com/gqlc/gqlc/sql"
+	"github.com/gqlc/gqlc/swift"
+	"github.com/gqlc/gqlc/terraform"
+	"github.com/gqlc/gqlc/ts"
 	"go.uber.org/zap"
 )
 
@@ -15,6 +35,27 @@ func main() {
 	cli := cmd.NewCLI()
 	cli.AllowPlugins("gqlc-gen-")
 
+	// Register Catalog generator
+	cli.RegisterGenerator(&catalog.Generator{},
+		"catalog_out",
+		"catalog_opt",
+		"Generate DataHub or OpenLineage metadata describing the schema's types.",
+	)
+
+	// Register C# generator
+	cli.RegisterGenerator(&csharp.Generator{},
+		"cs_out",
+		"cs_opt",
+		"Generate C# source.",
+	)
+
+	// Register Diagram generator
+	cli.RegisterGenerator(&diagram.Generator{},
+		"diagram_out",
+		"diagram_opt",
+		"Generate Mermaid or Graphviz class diagrams.",
+	)
+
 	// Register Documentation generator
 	cli.RegisterGenerator(&doc.Generator{},
 		"doc_out",
@@ -22,6 +63,20 @@ func main() {
 		"Generate Documentation from GraphQL schema.",
 	)
 
+	// Register Fragments generator
+	cli.RegisterGenerator(&fragments.Generator{},
+		"fragments_out",
+		"fragments_opt",
+		"Generate fragment constants for Go, TypeScript or Javascript.",
+	)
+
+	// Register Gateway generator
+	cli.RegisterGenerator(&gateway.Generator{},
+		"gateway_out",
+		"gateway_opt",
+		"Generate gateway route manifests from @service directives.",
+	)
+
 	// Register Go generator
 	cli.RegisterGenerator(&golang.Generator{},
 		"go_out",
@@ -29,6 +84,34 @@ func main() {
 		"Generate Go source.",
 	)
 
+	// Register gqlgen generator
+	cli.RegisterGenerator(&gqlgen.Generator{},
+		"gqlgen_out",
+		"gqlgen_opt",
+		"Generate gqlgen models and config.",
+	)
+
+	// Register Introspection generator
+	cli.RegisterGenerator(&introspection.Generator{},
+		"introspection_out",
+		"introspection_opt",
+		"Generate introspection query results.",
+	)
+
+	// Register Inventory generator
+	cli.RegisterGenerator(&inventory.Generator{},
+		"inventory_out",
+		"inventory_opt",
+		"Generate a CSV inventory of the schema's fields.",
+	)
+
+	// Register Java generator
+	cli.RegisterGenerator(&java.Generator{},
+		"java_out",
+		"java_opt",
+		"Generate Java source.",
+	)
+
 	// Register Javascript generator
 	cli.RegisterGenerator(&js.Generator{},
 		"js_out",
@@ -36,7 +119,88 @@ func main() {
 		"Generate Javascript source.",
 	)
 
+	// Register JSON Schema generator
+	cli.RegisterGenerator(&jsonschema.Generator{},
+		"jsonschema_out",
+		"jsonschema_opt",
+		"Generate JSON Schemas.",
+	)
+
+	// Register Kotlin generator
+	cli.RegisterGenerator(&kotlin.Generator{},
+		"kt_out",
+		"kt_opt",
+		"Generate Kotlin source.",
+	)
+
+	// Register OpenAPI generator
+	cli.RegisterGenerator(&openapi.Generator{},
+		"openapi_out",
+		"openapi_opt",
+		"Generate OpenAPI documents.",
+	)
+
+	// Register Protocol Buffers generator
+	cli.RegisterGenerator(&proto.Generator{},
+		"proto_out",
+		"proto_opt",
+		"Generate Protocol Buffers definitions.",
+	)
+
+	// Register Python generator
+	cli.RegisterGenerator(&python.Generator{},
+		"py_out",
+		"py_opt",
+		"Generate Python source.",
+	)
+
+	// Register Rust generator
+	cli.RegisterGenerator(&rust.Generator{},
+		"rs_out",
+		"rs_opt",
+		"Generate Rust source.",
+	)
+
+	// Register SDL generator
+	cli.RegisterGenerator(&sdl.Generator{},
+		"sdl_out",
+		"sdl_opt",
+		"Generate formatted GraphQL SDL.",
+	)
+
+	// Register SQL generator
+	cli.RegisterGenerator(&sql.Generator{},
+		"sql_out",
+		"sql_opt",
+		"Generate SQL tables.",
+	)
+
+	// Register Swift generator
+	cli.RegisterGenerator(&swift.Generator{},
+		"swift_out",
+		"swift_opt",
+		"Generate Swift source.",
+	)
+
+	// Register Terraform generator
+	cli.RegisterGenerator(&terraform.Generator{},
+		"terraform_out",
+		"terraform_opt",
+		"Generate Terraform provider schemas.",
+	)
+
+	// Register TypeScript generator
+	cli.RegisterGenerator(&ts.Generator{},
+		"ts_out",
+		"ts_opt",
+		"Generate TypeScript source.",
+	)
+
 	if err := cli.Run(os.Args); err != nil {
+		if cmd.Reported(err) {
+			os.Exit(1)
+		}
+
 		l, _ := zap.NewDevelopment()
 		l.Sugar().Fatal(err)
 	}
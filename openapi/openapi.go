@@ -0,0 +1,690 @@
+// Package openapi contains an OpenAPI generator for GraphQL Documents. The
+// types of a document become the component schemas of an OpenAPI 3.1
+// document and, optionally, each query and mutation field becomes a path.
+//
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+// Version is the OpenAPI version of generated documents.
+const Version = "3.1.0"
+
+// Options contains the options for the OpenAPI generator.
+type Options struct {
+	// Title of the API (default: the document name)
+	Title string
+
+	// Version of the API (default: 1.0.0)
+	Version string
+
+	// Generate a path stub for every query and mutation field
+	Paths bool
+
+	// Copy descriptions to schemas and operations (default: true)
+	Descriptions bool
+}
+
+// Generator generates an OpenAPI document for a GraphQL schema.
+type Generator struct {
+	bytes.Buffer
+
+	opts *Options
+	log  *zap.Logger
+}
+
+// Reset overrides the bytes.Buffer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Buffer.Reset()
+	g.opts = nil
+}
+
+// Generate generates an OpenAPI document from the given document. The
+// document is named after the GraphQL document e.g. test.gql generates
+// test.openapi.json.
+//
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "openapi",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("openapi").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	g.opts, err = getOptions(doc, opts)
+	if err != nil {
+		return
+	}
+
+	g.log.Info("generating document")
+	err = g.generateDocument(doc)
+	if err != nil {
+		return
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	f, err := gCtx.Open(fileName(doc))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	_, err = g.WriteTo(f)
+	return
+}
+
+// fileName returns the name of the OpenAPI document generated for a document.
+func fileName(doc *ast.Document) string {
+	base := filepath.Base(doc.Name)
+	name := base[:len(base)-len(filepath.Ext(base))]
+	if name == "" {
+		name = "schema"
+	}
+	return name + ".openapi.json"
+}
+
+// rootTypes maps the root operation types to their operation.
+func rootTypes(doc *ast.Document) map[string]string {
+	roots := make(map[string]string, 3)
+	if doc.Schema == nil {
+		roots["Query"] = "query"
+		roots["Mutation"] = "mutation"
+		roots["Subscription"] = "subscription"
+		return roots
+	}
+
+	schema := doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Schema).Schema
+	for _, f := range schema.RootOps.List {
+		roots[f.Type.(*ast.Field_Ident).Ident.Name] = f.Name.Name
+	}
+	return roots
+}
+
+// generateDocument encodes the OpenAPI document to the underlying buffer.
+func (g *Generator) generateDocument(doc *ast.Document) error {
+	info := object{{"title", g.opts.Title}, {"version", g.opts.Version}}
+	if doc.Schema != nil {
+		if descr := g.description(doc.Schema.Doc); descr != "" {
+			info = append(info, member{"description", descr})
+		}
+	}
+
+	roots := rootTypes(doc)
+	schemas := object{}
+	paths := &pathSet{index: make(map[string]int)}
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+
+		if op, ok := roots[name]; ok {
+			obj, isObj := ts.TypeSpec.Type.(*ast.TypeSpec_Object)
+			if isObj && g.opts.Paths && obj.Object.Fields != nil {
+				g.addPaths(paths, name, op, obj.Object.Fields.List)
+			}
+			continue
+		}
+
+		if schema := g.typeSchema(d); schema != nil {
+			schemas = append(schemas, member{name, schema})
+		}
+	}
+
+	root := object{{"openapi", Version}, {"info", info}}
+	if g.opts.Paths {
+		root = append(root, member{"paths", paths.paths})
+	}
+	root = append(root, member{"components", object{{"schemas", schemas}}})
+
+	enc := json.NewEncoder(g)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(root)
+}
+
+// typeSchema returns the component schema of a type declaration, or nil if
+// the declaration doesn't describe data.
+//
+func (g *Generator) typeSchema(d *ast.TypeDecl) object {
+	ts := d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+
+	var schema object
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Scalar:
+		// Custom scalars may be any value
+		schema = object{}
+	case *ast.TypeSpec_Object:
+		schema = g.objectSchema(v.Object.Fields)
+	case *ast.TypeSpec_Interface:
+		schema = g.objectSchema(v.Interface.Fields)
+	case *ast.TypeSpec_Union:
+		var members []interface{}
+		for _, mem := range v.Union.Members {
+			members = append(members, ref(mem.Name))
+		}
+		schema = object{{"oneOf", members}}
+	case *ast.TypeSpec_Enum:
+		var vals []interface{}
+		if v.Enum.Values != nil {
+			for _, val := range v.Enum.Values.List {
+				vals = append(vals, val.Name.Name)
+			}
+		}
+		schema = object{{"type", "string"}, {"enum", vals}}
+	case *ast.TypeSpec_Input:
+		schema = g.inputSchema(v.Input.Fields)
+	default:
+		return nil
+	}
+
+	if descr := g.description(d.Doc); descr != "" {
+		schema = append(object{{"description", descr}}, schema...)
+	}
+	if isDeprecated(ts.Directives) {
+		schema = append(schema, member{"deprecated", true})
+	}
+	return schema
+}
+
+func (g *Generator) objectSchema(fields *ast.FieldList) object {
+	props := object{}
+	var required []interface{}
+	if fields != nil {
+		for _, f := range fields.List {
+			typ := fieldType(f)
+			if _, ok := typ.(*ast.NonNull); ok {
+				required = append(required, f.Name.Name)
+			}
+
+			props = append(props, member{f.Name.Name, g.propSchema(typ, f.Doc, f.Directives, nil)})
+		}
+	}
+
+	return properties(props, required)
+}
+
+// inputSchema returns the schema of an input type, or of arguments, whose
+// non-null fields are required unless they have a default.
+//
+func (g *Generator) inputSchema(fields *ast.InputValueList) object {
+	props := object{}
+	var required []interface{}
+	if fields != nil {
+		for _, f := range fields.List {
+			typ := inputValueType(f)
+			if isRequired(f) {
+				required = append(required, f.Name.Name)
+			}
+
+			props = append(props, member{f.Name.Name, g.propSchema(typ, f.Doc, f.Directives, f.Default)})
+		}
+	}
+
+	return properties(props, required)
+}
+
+func properties(props object, required []interface{}) object {
+	schema := object{{"type", "object"}, {"properties", props}}
+	if len(required) > 0 {
+		schema = append(schema, member{"required", required})
+	}
+	return schema
+}
+
+// propSchema returns the schema of a field along with its annotations.
+func (g *Generator) propSchema(typ interface{}, doc *ast.DocGroup, dirs []*ast.DirectiveLit, def interface{}) object {
+	schema := schemaOf(typ)
+
+	if descr := g.description(doc); descr != "" {
+		schema = append(schema, member{"description", descr})
+	}
+	if def != nil {
+		schema = append(schema, member{"default", value(defaultValue(def))})
+	}
+	if isDeprecated(dirs) {
+		schema = append(schema, member{"deprecated", true})
+	}
+	return schema
+}
+
+// pathSet is the paths object of a document, which keeps paths in the order
+// they were first added.
+//
+type pathSet struct {
+	paths object
+	index map[string]int
+}
+
+func (ps *pathSet) add(path, method string, op object) {
+	i, ok := ps.index[path]
+	if !ok {
+		i = len(ps.paths)
+		ps.index[path] = i
+		ps.paths = append(ps.paths, member{path, object{}})
+	}
+
+	ps.paths[i].val = append(ps.paths[i].val.(object), member{method, op})
+}
+
+// addPaths adds a path for each field of a root operation type. Queries are
+// GET operations, whose arguments are query parameters, and mutations are
+// POST operations, whose arguments are the request body. Subscriptions
+// can't be described as a single request and are skipped.
+//
+func (g *Generator) addPaths(paths *pathSet, typ, op string, fields []*ast.Field) {
+	var method string
+	switch op {
+	case "query":
+		method = "get"
+	case "mutation":
+		method = "post"
+	default:
+		return
+	}
+
+	for _, f := range fields {
+		operation := object{{"tags", []interface{}{typ}}, {"operationId", f.Name.Name}}
+		if descr := g.description(f.Doc); descr != "" {
+			operation = append(operation, member{"description", descr})
+		}
+
+		if f.Args != nil && len(f.Args.List) > 0 {
+			if method == "get" {
+				operation = append(operation, member{"parameters", g.parameters(f.Args.List)})
+			} else {
+				operation = append(operation, member{"requestBody", g.requestBody(f.Args)})
+			}
+		}
+
+		operation = append(operation, member{"responses", object{
+			{"200", object{
+				{"description", "Successful response."},
+				{"content", content(schemaOf(fieldType(f)))},
+			}},
+		}})
+
+		if isDeprecated(f.Directives) {
+			operation = append(operation, member{"deprecated", true})
+		}
+
+		paths.add("/"+f.Name.Name, method, operation)
+	}
+}
+
+func (g *Generator) parameters(args []*ast.InputValue) []interface{} {
+	params := make([]interface{}, len(args))
+	for i, a := range args {
+		param := object{{"name", a.Name.Name}, {"in", "query"}}
+		if descr := g.description(a.Doc); descr != "" {
+			param = append(param, member{"description", descr})
+		}
+		if isRequired(a) {
+			param = append(param, member{"required", true})
+		}
+		if isDeprecated(a.Directives) {
+			param = append(param, member{"deprecated", true})
+		}
+
+		schema := schemaOf(inputValueType(a))
+		if a.Default != nil {
+			schema = append(schema, member{"default", value(defaultValue(a.Default))})
+		}
+		params[i] = append(param, member{"schema", schema})
+	}
+	return params
+}
+
+func (g *Generator) requestBody(args *ast.InputValueList) object {
+	body := object{}
+	for _, a := range args.List {
+		if isRequired(a) {
+			body = append(body, member{"required", true})
+			break
+		}
+	}
+	return append(body, member{"content", content(g.inputSchema(args))})
+}
+
+func content(schema object) object {
+	return object{{"application/json", object{{"schema", schema}}}}
+}
+
+var jsonTypes = map[string]string{
+	"Int":     "integer",
+	"Float":   "number",
+	"String":  "string",
+	"Boolean": "boolean",
+	"ID":      "string",
+}
+
+// schemaOf returns the schema of a GraphQL type. Nullable types also
+// accept null.
+//
+func schemaOf(typ interface{}) object {
+	if nn, ok := typ.(*ast.NonNull); ok {
+		switch w := nn.Type.(type) {
+		case *ast.NonNull_Ident:
+			return namedSchema(w.Ident.Name)
+		case *ast.NonNull_List:
+			return listSchema(w.List)
+		}
+	}
+
+	switch v := typ.(type) {
+	case *ast.Ident:
+		return nullable(namedSchema(v.Name))
+	case *ast.List:
+		return nullable(listSchema(v))
+	}
+	return object{}
+}
+
+func listSchema(l *ast.List) object {
+	var elem interface{}
+	switch w := l.Type.(type) {
+	case *ast.List_Ident:
+		elem = w.Ident
+	case *ast.List_List:
+		elem = w.List
+	case *ast.List_NonNull:
+		elem = w.NonNull
+	}
+
+	return object{{"type", "array"}, {"items", schemaOf(elem)}}
+}
+
+func namedSchema(name string) object {
+	if t, ok := jsonTypes[name]; ok {
+		return object{{"type", t}}
+	}
+	return ref(name)
+}
+
+// ref returns a reference to the component schema of a type.
+func ref(name string) object {
+	return object{{"$ref", "#/components/schemas/" + name}}
+}
+
+// nullable allows a schema to also be null.
+func nullable(schema object) object {
+	if len(schema) > 0 && schema[0].key == "type" {
+		return append(object{{"type", []interface{}{schema[0].val, "null"}}}, schema[1:]...)
+	}
+	return object{{"anyOf", []interface{}{schema, object{{"type", "null"}}}}}
+}
+
+func isRequired(a *ast.InputValue) bool {
+	_, ok := a.Type.(*ast.InputValue_NonNull)
+	return ok && a.Default == nil
+}
+
+func isDeprecated(dirs []*ast.DirectiveLit) bool {
+	for _, d := range dirs {
+		if d.Name == "deprecated" {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func inputValueType(a *ast.InputValue) interface{} {
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func defaultValue(def interface{}) interface{} {
+	switch v := def.(type) {
+	case *ast.InputValue_BasicLit:
+		return v.BasicLit
+	case *ast.InputValue_CompositeLit:
+		return v.CompositeLit
+	}
+	return nil
+}
+
+// value converts a GraphQL literal to its JSON value. Enum values are
+// strings and numbers are normalized, so 1 and 1.0 are both encoded as 1.
+//
+func value(val interface{}) interface{} {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		switch v.Kind {
+		case token.Token_STRING:
+			return unquote(v.Value)
+		case token.Token_INT, token.Token_FLOAT:
+			return json.Number(normNumber(v.Value))
+		case token.Token_BOOL:
+			return v.Value == "true"
+		case token.Token_NULL:
+			return nil
+		}
+		return v.Value
+	case *ast.CompositeLit:
+		switch w := v.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			return value(w.BasicLit)
+		case *ast.CompositeLit_ListLit:
+			return listValue(w.ListLit)
+		case *ast.CompositeLit_ObjLit:
+			obj := make(object, len(w.ObjLit.Fields))
+			for i, pair := range w.ObjLit.Fields {
+				obj[i] = member{pair.Key.Name, value(pair.Val)}
+			}
+			return obj
+		}
+	}
+	return nil
+}
+
+func listValue(l *ast.ListLit) []interface{} {
+	vals := []interface{}{}
+	switch w := l.List.(type) {
+	case *ast.ListLit_BasicList:
+		for _, bval := range w.BasicList.Values {
+			vals = append(vals, value(bval))
+		}
+	case *ast.ListLit_CompositeList:
+		for _, cval := range w.CompositeList.Values {
+			vals = append(vals, value(cval))
+		}
+	}
+	return vals
+}
+
+func normNumber(s string) string {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func unquote(s string) string {
+	if strings.HasPrefix(s, `"""`) {
+		return strings.TrimSpace(strings.Trim(s, `"`))
+	}
+
+	u, err := strconv.Unquote(s)
+	if err != nil {
+		return strings.Trim(s, `"`)
+	}
+	return u
+}
+
+func (g *Generator) description(doc *ast.DocGroup) string {
+	if doc == nil || !g.opts.Descriptions {
+		return ""
+	}
+	return strings.TrimSpace(doc.Text())
+}
+
+// member is a single key/value pair of an object.
+type member struct {
+	key string
+	val interface{}
+}
+
+// object is a JSON object which keeps the order of its members, so the
+// document reads in the same order as the schema it was generated from.
+//
+type object []member
+
+// MarshalJSON implements the json.Marshaler interface.
+func (o object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+
+		val, err := marshal(m.val)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(val)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Title:        strings.TrimSuffix(fileName(doc), ".openapi.json"),
+		Version:      "1.0.0",
+		Descriptions: true,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "openapi" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		apiOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range apiOpts.Fields {
+			switch arg.Key.Name {
+			case "title":
+				gOpts.Title = unquote(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+			case "version":
+				gOpts.Version = unquote(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+			case "paths":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Paths = b
+			case "descriptions":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Descriptions = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts != nil {
+		if t, ok := opts["title"]; ok {
+			gOpts.Title = optString(t)
+		}
+		if v, ok := opts["version"]; ok {
+			gOpts.Version = optString(v)
+		}
+		if p, ok := opts["paths"]; ok {
+			gOpts.Paths, _ = p.(bool)
+		}
+		if d, ok := opts["descriptions"]; ok {
+			gOpts.Descriptions, _ = d.(bool)
+		}
+	}
+	return
+}
+
+// optString returns a CLI option as a string. Numbers are allowed, since
+// versions like 2 or 1.5 are parsed as such when they aren't quoted.
+//
+func optString(v interface{}) string {
+	switch w := v.(type) {
+	case string:
+		return strings.Trim(w, `"`)
+	case int64:
+		return strconv.FormatInt(w, 10)
+	case float64:
+		return strconv.FormatFloat(w, 'f', -1, 64)
+	}
+	return ""
+}
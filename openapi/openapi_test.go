@@ -0,0 +1,321 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "test.openapi.json", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected openapi output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected openapi output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestSchemaOf(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Type interface{}
+		JSON string
+	}{
+		{Name: "Nullable", Type: &ast.Ident{Name: "Int"}, JSON: `{"type":["integer","null"]}`},
+		{Name: "NonNull", Type: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}, JSON: `{"type":"string"}`},
+		{Name: "Ref", Type: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "User"}}}, JSON: `{"$ref":"#/components/schemas/User"}`},
+		{Name: "NullableRef", Type: &ast.Ident{Name: "User"}, JSON: `{"anyOf":[{"$ref":"#/components/schemas/User"},{"type":"null"}]}`},
+		{
+			Name: "List",
+			Type: &ast.NonNull{Type: &ast.NonNull_List{List: &ast.List{Type: &ast.List_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Float"}}}}}}},
+			JSON: `{"type":"array","items":{"type":"number"}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			b, err := json.Marshal(schemaOf(testCase.Type))
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if string(b) != testCase.JSON {
+				subT.Errorf("expected: %s, but got: %s", testCase.JSON, b)
+			}
+		})
+	}
+}
+
+func TestPaths(t *testing.T) {
+	gqlSrc := `type Query {
+	"user returns a user by id."
+	user(id: ID!, active: Boolean = true): User
+}
+
+type Mutation {
+	user(name: String!): User!
+}
+
+type User {
+	name: String!
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "users.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var b bytes.Buffer
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err = g.Generate(ctx, doc, map[string]interface{}{"paths": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var api struct {
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Parameters  []struct {
+				Name     string `json:"name"`
+				In       string `json:"in"`
+				Required bool   `json:"required"`
+			} `json:"parameters"`
+			RequestBody *struct {
+				Required bool `json:"required"`
+			} `json:"requestBody"`
+		} `json:"paths"`
+	}
+	err = json.Unmarshal(b.Bytes(), &api)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ops := api.Paths["/user"]
+	if len(api.Paths) != 1 || len(ops) != 2 {
+		t.Fatalf("expected a GET and POST operation for /user, but got: %v", api.Paths)
+	}
+
+	get := ops["get"]
+	if len(get.Parameters) != 2 || !get.Parameters[0].Required || get.Parameters[1].Required || get.Parameters[0].In != "query" {
+		t.Errorf("unexpected parameters: %+v", get.Parameters)
+	}
+
+	post := ops["post"]
+	if post.RequestBody == nil || !post.RequestBody.Required || len(post.Parameters) != 0 {
+		t.Errorf("expected a required request body, but got: %+v", post)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	doc := &ast.Document{
+		Name: "dir/api.gql",
+		Directives: []*ast.DirectiveLit{
+			{
+				Name: "openapi",
+				Args: &ast.CallExpr{Args: []*ast.Arg{{
+					Name: &ast.Ident{Name: "options"},
+					Value: &ast.Arg_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+						Fields: []*ast.ObjLit_Pair{
+							{
+								Key: &ast.Ident{Name: "version"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: `"2.0.0"`}}},
+							},
+							{
+								Key: &ast.Ident{Name: "paths"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_BOOL, Value: "true"}}},
+							},
+						},
+					}}}},
+				}}},
+			},
+		},
+	}
+
+	gOpts, err := getOptions(doc, map[string]interface{}{"version": int64(3), "descriptions": false})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.Title != "api" || gOpts.Version != "3" || !gOpts.Paths || gOpts.Descriptions {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+
+	if name := fileName(doc); name != "api.openapi.json" {
+		t.Errorf("expected file name api.openapi.json, but got: %s", name)
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := &Generator{}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := &Generator{}
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `"A user of the service."
+type User {
+	name: String!
+	nickname: String
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, map[string]interface{}{"title": "Example"})
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Print(b.String())
+	// Output:
+	// {
+	//   "openapi": "3.1.0",
+	//   "info": {
+	//     "title": "Example",
+	//     "version": "1.0.0"
+	//   },
+	//   "components": {
+	//     "schemas": {
+	//       "User": {
+	//         "description": "A user of the service.",
+	//         "type": "object",
+	//         "properties": {
+	//           "name": {
+	//             "type": "string"
+	//           },
+	//           "nickname": {
+	//             "type": [
+	//               "string",
+	//               "null"
+	//             ]
+	//           }
+	//         },
+	//         "required": [
+	//           "name"
+	//         ]
+	//       }
+	//     }
+	//   }
+	// }
+}
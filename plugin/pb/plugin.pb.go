@@ -27,11 +27,20 @@ type Request struct {
 	FileToGenerate []string `protobuf:"bytes,1,rep,name=file_to_generate,json=fileToGenerate,proto3" json:"file_to_generate,omitempty"`
 	// The generator parameter passed on the command-line encoded as JSON.
 	Parameter string `protobuf:"bytes,2,opt,name=parameter,proto3" json:"parameter,omitempty"`
-	// Documents are all the parsed documents to be generated.
-	Documents            []*ast.Document `protobuf:"bytes,3,rep,name=documents,proto3" json:"documents,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	// Documents are all the parsed documents to be generated. They're the
+	// same as SchemaDocuments, which plugins should read instead.
+	//
+	Documents []*ast.Document `protobuf:"bytes,3,rep,name=documents,proto3" json:"documents,omitempty"` // Deprecated: Do not use.
+	// SchemaDocuments are the type system documents to be generated.
+	SchemaDocuments []*ast.Document `protobuf:"bytes,4,rep,name=schema_documents,json=schemaDocuments,proto3" json:"schema_documents,omitempty"`
+	// OperationDocuments are the executable documents, i.e. the operations
+	// and fragments of clients, given alongside the schema. They've been
+	// validated against the schema documents.
+	//
+	OperationDocuments   []*OperationDocument `protobuf:"bytes,5,rep,name=operation_documents,json=operationDocuments,proto3" json:"operation_documents,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *Request) Reset()         { *m = Request{} }
@@ -73,6 +82,7 @@ func (m *Request) GetParameter() string {
 	return ""
 }
 
+// Deprecated: Do not use.
 func (m *Request) GetDocuments() []*ast.Document {
 	if m != nil {
 		return m.Documents
@@ -80,6 +90,20 @@ func (m *Request) GetDocuments() []*ast.Document {
 	return nil
 }
 
+func (m *Request) GetSchemaDocuments() []*ast.Document {
+	if m != nil {
+		return m.SchemaDocuments
+	}
+	return nil
+}
+
+func (m *Request) GetOperationDocuments() []*OperationDocument {
+	if m != nil {
+		return m.OperationDocuments
+	}
+	return nil
+}
+
 // The plugin writes an encoded PluginResponse to stdout.
 type Response struct {
 	// Error message. If non-empty code generation failed. The plugin
@@ -140,6 +164,10 @@ type Response_File struct {
 	// the path separator, not "\".
 	//
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Scaffold marks a file, e.g. of resolver implementations, which is only
+	// written if it doesn't exist yet, so it's safe to edit.
+	//
+	Scaffold bool `protobuf:"varint,2,opt,name=scaffold,proto3" json:"scaffold,omitempty"`
 	// The file contents.
 	Content              string   `protobuf:"bytes,15,opt,name=content,proto3" json:"content,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -179,6 +207,13 @@ func (m *Response_File) GetName() string {
 	return ""
 }
 
+func (m *Response_File) GetScaffold() bool {
+	if m != nil {
+		return m.Scaffold
+	}
+	return false
+}
+
 func (m *Response_File) GetContent() string {
 	if m != nil {
 		return m.Content
@@ -186,30 +221,89 @@ func (m *Response_File) GetContent() string {
 	return ""
 }
 
+// An executable document, which is given to the plugin as it was written,
+// since it isn't part of the type system documents.
+//
+type OperationDocument struct {
+	// The path the document was read from.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The document itself.
+	Source               string   `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OperationDocument) Reset()         { *m = OperationDocument{} }
+func (m *OperationDocument) String() string { return proto.CompactTextString(m) }
+func (*OperationDocument) ProtoMessage()    {}
+func (*OperationDocument) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{2}
+}
+
+func (m *OperationDocument) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OperationDocument.Unmarshal(m, b)
+}
+func (m *OperationDocument) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OperationDocument.Marshal(b, m, deterministic)
+}
+func (m *OperationDocument) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OperationDocument.Merge(m, src)
+}
+func (m *OperationDocument) XXX_Size() int {
+	return xxx_messageInfo_OperationDocument.Size(m)
+}
+func (m *OperationDocument) XXX_DiscardUnknown() {
+	xxx_messageInfo_OperationDocument.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OperationDocument proto.InternalMessageInfo
+
+func (m *OperationDocument) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *OperationDocument) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Request)(nil), "Request")
 	proto.RegisterType((*Response)(nil), "Response")
 	proto.RegisterType((*Response_File)(nil), "Response.File")
+	proto.RegisterType((*OperationDocument)(nil), "OperationDocument")
 }
 
 func init() { proto.RegisterFile("plugin.proto", fileDescriptor_22a625af4bc1cc87) }
 
 var fileDescriptor_22a625af4bc1cc87 = []byte{
-	// 256 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x44, 0x50, 0xc1, 0x6a, 0xeb, 0x30,
-	0x10, 0xc4, 0xb1, 0x5f, 0x62, 0xed, 0x2b, 0x69, 0x11, 0x85, 0x08, 0x53, 0xa8, 0xf1, 0xc9, 0xbd,
-	0x28, 0x90, 0xb6, 0x7f, 0x50, 0xda, 0x6b, 0x11, 0xbd, 0x07, 0xc5, 0xdd, 0x18, 0x83, 0x2d, 0x39,
-	0xd2, 0xfa, 0xd2, 0x2f, 0xe8, 0x67, 0x17, 0x2b, 0x76, 0x72, 0xdb, 0x99, 0x59, 0x66, 0x67, 0x07,
-	0x6e, 0xfa, 0x76, 0xa8, 0x1b, 0x23, 0x7b, 0x67, 0xc9, 0x66, 0x9b, 0xfa, 0xd4, 0x56, 0xdb, 0x30,
-	0x1f, 0x86, 0xe3, 0x56, 0x7b, 0x3a, 0x0b, 0xc5, 0x6f, 0x04, 0x2b, 0x85, 0xa7, 0x01, 0x3d, 0xf1,
-	0x12, 0xee, 0x8e, 0x4d, 0x8b, 0x7b, 0xb2, 0xfb, 0x1a, 0x0d, 0x3a, 0x4d, 0x28, 0xa2, 0x3c, 0x2e,
-	0x99, 0x5a, 0x8f, 0xfc, 0x97, 0xfd, 0x98, 0x58, 0xfe, 0x00, 0xac, 0xd7, 0x4e, 0x77, 0x48, 0xe8,
-	0xc4, 0x22, 0x8f, 0x4a, 0xa6, 0xae, 0x04, 0x7f, 0x05, 0xf6, 0x6d, 0xab, 0xa1, 0x43, 0x43, 0x5e,
-	0xc4, 0x79, 0x5c, 0xfe, 0xdf, 0x6d, 0xe4, 0x18, 0x40, 0xce, 0x01, 0xe4, 0xdb, 0xa4, 0xab, 0xeb,
-	0x66, 0xf1, 0x03, 0xa9, 0x42, 0xdf, 0x5b, 0xe3, 0x91, 0xdf, 0xc3, 0x3f, 0x74, 0xce, 0x3a, 0x11,
-	0x05, 0xf3, 0x33, 0xe0, 0x05, 0x24, 0x63, 0x10, 0xb1, 0x08, 0x9e, 0x6b, 0x39, 0xaf, 0xcb, 0xf7,
-	0xa6, 0x45, 0x15, 0xb4, 0xec, 0x05, 0x92, 0x11, 0x71, 0x0e, 0x89, 0xd1, 0x1d, 0x4e, 0x06, 0x61,
-	0xe6, 0x02, 0x56, 0x95, 0x35, 0x84, 0x86, 0xc4, 0x6d, 0xa0, 0x67, 0xb8, 0x7b, 0x82, 0xe5, 0x67,
-	0xe8, 0x8b, 0x3f, 0x42, 0x7a, 0x79, 0x33, 0x95, 0x53, 0x35, 0x19, 0xbb, 0xdc, 0x3a, 0x2c, 0xc3,
-	0x13, 0xcf, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x44, 0x71, 0x9d, 0x1c, 0x61, 0x01, 0x00, 0x00,
+	// 339 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0x41, 0x6b, 0xe3, 0x30,
+	0x10, 0x85, 0xb1, 0xe3, 0x24, 0xf6, 0xec, 0x92, 0x64, 0xb5, 0xcb, 0x46, 0x98, 0x85, 0x0d, 0x3e,
+	0xb9, 0x17, 0x05, 0xd2, 0x53, 0x4f, 0x85, 0x34, 0xb4, 0xc7, 0x06, 0xd1, 0x7b, 0x70, 0x9c, 0x71,
+	0x6a, 0xb0, 0x25, 0x47, 0x92, 0x7f, 0x4a, 0xfb, 0x7b, 0x8b, 0x15, 0xdb, 0x29, 0x6d, 0xe9, 0x4d,
+	0xef, 0x3d, 0xe9, 0x63, 0x66, 0x34, 0xf0, 0xb3, 0x2a, 0xea, 0x63, 0x2e, 0x58, 0xa5, 0xa4, 0x91,
+	0xe1, 0xfc, 0x78, 0x2a, 0xd2, 0xa5, 0x3d, 0xef, 0xeb, 0x6c, 0x99, 0x68, 0x73, 0x0e, 0xa2, 0x17,
+	0x17, 0xc6, 0x1c, 0x4f, 0x35, 0x6a, 0x43, 0x62, 0x98, 0x65, 0x79, 0x81, 0x3b, 0x23, 0x77, 0x47,
+	0x14, 0xa8, 0x12, 0x83, 0xd4, 0x59, 0x0c, 0xe2, 0x80, 0x4f, 0x1a, 0xff, 0x49, 0x3e, 0xb4, 0x2e,
+	0xf9, 0x07, 0x41, 0x95, 0xa8, 0xa4, 0x44, 0x83, 0x8a, 0xba, 0x0b, 0x27, 0x0e, 0xf8, 0xc5, 0x20,
+	0x37, 0x10, 0x1c, 0x64, 0x5a, 0x97, 0x28, 0x8c, 0xa6, 0x83, 0xc5, 0x20, 0xfe, 0xb1, 0x9a, 0xb3,
+	0xa6, 0x00, 0xd6, 0x15, 0xc0, 0x36, 0x6d, 0xbe, 0x76, 0xa9, 0xc3, 0x2f, 0xb7, 0xc9, 0x1a, 0x66,
+	0x3a, 0x7d, 0xc6, 0x32, 0xd9, 0x5d, 0x08, 0xde, 0xb7, 0x04, 0x3e, 0x3d, 0x3f, 0xd8, 0xf4, 0x8c,
+	0x3b, 0xf8, 0x2d, 0xab, 0xa6, 0xce, 0x5c, 0x8a, 0x77, 0x98, 0xa1, 0xc5, 0x10, 0xf6, 0xd8, 0x65,
+	0x3d, 0x81, 0xc8, 0x8f, 0x96, 0x8e, 0x5e, 0x1d, 0xf0, 0x39, 0xea, 0x4a, 0x0a, 0x8d, 0xe4, 0x0f,
+	0x0c, 0x51, 0x29, 0xa9, 0xa8, 0x63, 0x5b, 0x3d, 0x0b, 0x12, 0x81, 0xd7, 0x8c, 0x85, 0xba, 0x16,
+	0x3c, 0x61, 0xdd, 0x75, 0x76, 0x9f, 0x17, 0xc8, 0x6d, 0x16, 0x6e, 0xc1, 0x6b, 0x14, 0x21, 0xe0,
+	0x89, 0xa4, 0xc4, 0x16, 0x60, 0xcf, 0x24, 0x04, 0x5f, 0xa7, 0x49, 0x96, 0xc9, 0xe2, 0x60, 0x67,
+	0xe8, 0xf3, 0x5e, 0x13, 0x0a, 0xe3, 0x54, 0x0a, 0x83, 0xc2, 0xd0, 0xa9, 0x7d, 0xd2, 0xc9, 0xe8,
+	0x16, 0x7e, 0x7d, 0xea, 0xe0, 0x4b, 0xfc, 0x5f, 0x18, 0x69, 0x59, 0xab, 0x14, 0xdb, 0x0f, 0x6a,
+	0xd5, 0xea, 0x0a, 0x46, 0x5b, 0xbb, 0x1a, 0xe4, 0x3f, 0xf8, 0xfd, 0x8f, 0xfa, 0xac, 0xdd, 0x82,
+	0x30, 0xe8, 0x1b, 0xd9, 0x8f, 0xec, 0xb4, 0xaf, 0xdf, 0x06, 0x00, 0x40, 0x11, 0xf2, 0x6b, 0x4c,
+	0x02, 0x00, 0x00,
 }
@@ -6,8 +6,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"os/exec"
 	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/gqlc/gqlc/gen"
@@ -25,10 +27,20 @@ type Generator struct {
 	Name   string
 	Prefix string
 
+	// Retries is how many more times the plugin is executed when it exits
+	// with an error, e.g. because it depends on the network. Backoff is
+	// how long to wait before the first retry, and doubles after each.
+	// Errors the plugin responds with aren't retried.
+	//
+	Retries int
+	Backoff time.Duration
+
 	lookOnce    sync.Once
 	path        string
 	lookPathErr error
-	log         *zap.Logger
+
+	// mu guards Cmd, which is taken by the first call to Generate
+	mu sync.Mutex
 }
 
 // Generate executes a plugin given the GraphQL Document.
@@ -43,12 +55,10 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 		}
 	}()
 
-	if g.log == nil {
-		g.log = zap.L().Named(g.Name).With(zap.String("doc", doc.Name))
-	}
+	log := zap.L().Named(g.Name).With(zap.String("doc", doc.Name))
 
 	// Encode options to JSON
-	g.log.Info("marshalling options")
+	log.Info("marshalling options")
 	b, err := json.Marshal(opts)
 	if err != nil {
 		return err
@@ -64,12 +74,19 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 		return
 	}
 
-	// Marshall doc
-	g.log.Info("marshalling request")
+	// Marshall doc, along with any operations
+	log.Info("marshalling request")
+	gCtx := gen.Context(ctx)
+	var ops []*pb.OperationDocument
+	for _, op := range gen.Operations(gCtx) {
+		ops = append(ops, &pb.OperationDocument{Name: op.Name, Source: op.Source})
+	}
 	b, perr := proto.Marshal(&pb.Request{
-		FileToGenerate: []string{doc.Name},
-		Parameter:      string(b),
-		Documents:      []*ast.Document{doc},
+		FileToGenerate:     []string{doc.Name},
+		Parameter:          string(b),
+		Documents:          []*ast.Document{doc},
+		SchemaDocuments:    []*ast.Document{doc},
+		OperationDocuments: ops,
 	})
 	if perr != nil {
 		err = perr
@@ -77,23 +94,45 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 	}
 
 	// Configure plugin command
-	if g.Cmd == nil {
-		g.Cmd = exec.CommandContext(ctx, g.path)
+	g.mu.Lock()
+	cmd := g.Cmd
+	g.Cmd = nil
+	g.mu.Unlock()
+	if cmd == nil {
+		cmd = exec.CommandContext(ctx, g.path)
 	}
-	out := new(bytes.Buffer)
-	g.Stdin = bytes.NewReader(b)
-	g.Stdout = out
 
-	// Exec plugin
-	g.log.Info("executing plugin")
-	err = g.Run()
-	g.Cmd = nil
-	if err != nil {
-		return
+	// Exec plugin, until it succeeds or runs out of retries. Nothing is
+	// written until it succeeds, so a failed attempt leaves no files behind.
+	//
+	var out *bytes.Buffer
+	for attempt := 0; ; attempt++ {
+		log.Info("executing plugin", zap.Int("attempt", attempt+1))
+		out, err = run(cmd, b)
+		if err == nil {
+			break
+		}
+		if _, ok := err.(*exec.ExitError); !ok || attempt >= g.Retries {
+			return
+		}
+
+		backoff := g.Backoff << uint(attempt)
+		log.Info("retrying plugin", zap.Error(err), zap.Duration("backoff", backoff))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		// A command can only be run once
+		next := exec.CommandContext(ctx, cmd.Path, cmd.Args[1:]...)
+		next.Env = cmd.Env
+		next.Dir = cmd.Dir
+		cmd = next
 	}
 
 	// Unmarshall response
-	g.log.Info("unmarshalling response")
+	log.Info("unmarshalling response")
 	var resp pb.Response
 	err = proto.Unmarshal(out.Bytes(), &resp)
 	if err != nil {
@@ -106,11 +145,19 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 	}
 
 	// Write plugin files
-	gCtx := gen.Context(ctx)
 	for _, f := range resp.File {
-		g.log.Info("writing content from plugin", zap.String("file", f.Name))
+		log.Info("writing content from plugin", zap.String("file", f.Name))
 
-		w, ferr := gCtx.Open(f.Name)
+		open := gCtx.Open
+		if f.Scaffold {
+			open = func(name string) (io.WriteCloser, error) { return gen.Scaffold(gCtx, name) }
+		}
+
+		w, ferr := open(f.Name)
+		if ferr == gen.ErrScaffolded {
+			log.Info("file has already been scaffolded", zap.String("file", f.Name))
+			continue
+		}
 		if ferr != nil {
 			err = ferr
 			return
@@ -123,3 +170,11 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 	}
 	return
 }
+
+// run runs the plugin command with the request, and returns its output.
+func run(cmd *exec.Cmd, req []byte) (*bytes.Buffer, error) {
+	out := new(bytes.Buffer)
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = out
+	return out, cmd.Run()
+}
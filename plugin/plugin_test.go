@@ -8,8 +8,10 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/gqlc/gqlc/gen"
@@ -211,6 +213,136 @@ func TestContextErrors(t *testing.T) {
 	})
 }
 
+type scaffoldCtx struct {
+	files map[string]*bytes.Buffer
+}
+
+func (ctx *scaffoldCtx) Open(filename string) (io.WriteCloser, error) {
+	b := new(bytes.Buffer)
+	ctx.files[filename] = b
+	return gen.TestCtx{Writer: b}, nil
+}
+
+func (ctx *scaffoldCtx) Scaffold(filename string) (io.WriteCloser, error) {
+	if _, ok := ctx.files[filename]; ok {
+		return nil, gen.ErrScaffolded
+	}
+	return ctx.Open(filename)
+}
+
+func TestScaffold(t *testing.T) {
+	sCtx := &scaffoldCtx{files: make(map[string]*bytes.Buffer)}
+	for i := 0; i < 2; i++ {
+		g := &Generator{
+			Name: "test",
+			Cmd:  helperCommand(t, "scaffold"),
+		}
+		err := g.Generate(gen.WithContext(context.Background(), sCtx), testDoc, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if i == 0 {
+			sCtx.files["resolvers.txt"].Reset()
+			sCtx.files["resolvers.txt"].WriteString("implemented")
+		}
+	}
+
+	if s := sCtx.files["models.txt"].String(); s != "models" {
+		t.Errorf("expected models to be regenerated, but got: %s", s)
+	}
+	if s := sCtx.files["resolvers.txt"].String(); s != "implemented" {
+		t.Errorf("expected resolvers to only be scaffolded once, but got: %s", s)
+	}
+}
+
+func TestOperations(t *testing.T) {
+	var b bytes.Buffer
+	g := &Generator{
+		Name: "test",
+		Cmd:  helperCommand(t, "operations"),
+	}
+
+	oCtx := gen.TestOperationCtx{
+		TestCtx: gen.TestCtx{Writer: &b},
+		Docs:    []gen.OperationDocument{{Name: "ops.graphql", Source: "{ hello }"}},
+	}
+	err := g.Generate(gen.WithContext(context.Background(), oCtx), testDoc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := b.String(); s != "ops.graphql: { hello }" {
+		t.Errorf("expected the plugin to be given the operations, but got: %s", s)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	testCases := []struct {
+		Name    string
+		Retries int
+		Err     bool
+	}{
+		{Name: "Succeeds", Retries: 2},
+		{Name: "RunsOut", Retries: 1, Err: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			// The plugin fails twice, after partially responding
+			count, err := ioutil.TempFile("", "attempts")
+			if err != nil {
+				subT.Fatal(err)
+			}
+			count.Close()
+			defer os.Remove(count.Name())
+
+			cmd := helperCommand(subT, "flaky", count.Name(), "2")
+			g := &Generator{
+				Name:    "test",
+				Cmd:     cmd,
+				Retries: testCase.Retries,
+				Backoff: time.Millisecond,
+			}
+
+			var opened []string
+			ctx := gen.WithContext(context.Background(), &testCtx{opener: func(name string) (io.WriteCloser, error) {
+				opened = append(opened, name)
+				return gen.TestCtx{Writer: ioutil.Discard}, nil
+			}})
+			err = g.Generate(ctx, testDoc, nil)
+			if testCase.Err {
+				if err == nil {
+					subT.Error("expected plugin to fail")
+				}
+				if len(opened) > 0 {
+					subT.Errorf("expected no files to be written, but got: %v", opened)
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if len(opened) != 1 || opened[0] != "test.txt" {
+				subT.Errorf("expected test.txt to be written once, but got: %v", opened)
+			}
+		})
+	}
+
+	t.Run("ResponseErrors", func(subT *testing.T) {
+		g := &Generator{
+			Name:    "test",
+			Cmd:     helperCommand(subT, "error"),
+			Retries: 2,
+		}
+		err := g.Generate(gen.WithContext(context.Background(), gen.TestCtx{Writer: ioutil.Discard}), testDoc, nil)
+		if err == nil || err.(gen.GeneratorError).Msg != "testing error response" {
+			subT.Errorf("expected the response error, without retrying, but got: %v", err)
+		}
+	})
+}
+
 // TestHelperProcess isn't a real test. It's used as a helper process
 // for TestParameterRun.
 //
@@ -273,6 +405,93 @@ func TestHelperProcess(t *testing.T) {
 			fmt.Fprintln(os.Stdout, err)
 			os.Exit(0)
 		}
+	case "scaffold":
+		if _, err := ioutil.ReadAll(os.Stdin); err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+
+		resp := &pb.Response{
+			File: []*pb.Response_File{
+				{
+					Name:    "models.txt",
+					Content: "models",
+				},
+				{
+					Name:     "resolvers.txt",
+					Scaffold: true,
+					Content:  "resolvers",
+				},
+			},
+		}
+		b, err := proto.Marshal(resp)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+
+		_, err = os.Stdout.Write(b)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+	case "operations":
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+
+		var req pb.Request
+		err = proto.Unmarshal(b, &req)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+
+		resp := new(pb.Response)
+		switch {
+		case len(req.SchemaDocuments) != 1 || len(req.Documents) != 1:
+			resp.Error = "expected one schema document"
+		case len(req.OperationDocuments) != 1:
+			resp.Error = "expected one operation document"
+		default:
+			op := req.OperationDocuments[0]
+			resp.File = []*pb.Response_File{{Name: "ops.txt", Content: op.Name + ": " + op.Source}}
+		}
+		b, err = proto.Marshal(resp)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+
+		_, err = os.Stdout.Write(b)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+	case "flaky":
+		if _, err := ioutil.ReadAll(os.Stdin); err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+
+		// Fail until args[0] records more attempts than args[1]
+		b, _ := ioutil.ReadFile(args[0])
+		b = append(b, '.')
+		ioutil.WriteFile(args[0], b, 0644)
+		if n, _ := strconv.Atoi(args[1]); len(b) <= n {
+			resp, _ := proto.Marshal(&pb.Response{File: []*pb.Response_File{{Name: "partial.txt", Content: "partial"}}})
+			os.Stdout.Write(resp[:len(resp)/2])
+			os.Exit(1)
+		}
+
+		resp, err := proto.Marshal(&pb.Response{File: []*pb.Response_File{{Name: "test.txt", Content: outDoc}}})
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+		os.Stdout.Write(resp)
 	case "malformed":
 		b, err := ioutil.ReadAll(os.Stdin)
 		if err != nil {
@@ -0,0 +1,694 @@
+// Package proto contains a Protocol Buffers generator for GraphQL Documents.
+// Object, input, interface, union and enum types are mapped to proto3
+// messages and enums, and root operation types to gRPC services.
+//
+package proto
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/gen/printer"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+// Supported mappings of nullable scalars
+const (
+	Optional = "OPTIONAL"
+	Wrappers = "WRAPPERS"
+)
+
+// Options contains the options for the Protocol Buffers generator.
+type Options struct {
+	// Package is the proto package of the generated file
+	Package string
+
+	// GoPackage sets the go_package file option
+	GoPackage string
+
+	// Either "OPTIONAL" or "WRAPPERS" (default: OPTIONAL)
+	Nullable string
+
+	// Copy descriptions to comments (default: true)
+	Descriptions bool
+}
+
+// Generator generates Protocol Buffers for a GraphQL schema.
+type Generator struct {
+	printer.Printer
+
+	opts *Options
+	log  *zap.Logger
+
+	// kinds maps the types declared in a document to their kind
+	kinds map[string]token.Token
+
+	// impls maps an interface to the objects which implement it
+	impls map[string][]string
+
+	// imports contains the well-known types used by the generated file
+	imports map[string]bool
+
+	// lists contains the wrapper messages of nested lists
+	lists map[string]interface{}
+}
+
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Printer.Reset()
+	g.Indent = "  "
+	g.kinds = make(map[string]token.Token)
+	g.impls = make(map[string][]string)
+	g.imports = make(map[string]bool)
+	g.lists = make(map[string]interface{})
+}
+
+// Generate generates a proto3 file for the given document.
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "proto",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("proto").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	g.opts, err = getOptions(doc, opts)
+	if err != nil {
+		return
+	}
+
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		g.kinds[ts.TypeSpec.Name.Name] = d.Tok
+
+		if obj, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Object); ok {
+			for _, inter := range obj.Object.Interfaces {
+				g.impls[inter.Name] = append(g.impls[inter.Name], ts.TypeSpec.Name.Name)
+			}
+		}
+	}
+
+	// Generate types
+	g.log.Info("generating types")
+	roots := rootTypes(doc)
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		name := ts.TypeSpec.Name.Name
+		descr := d.Doc
+		if !g.opts.Descriptions {
+			descr = nil
+		}
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			g.P()
+			if op, ok := roots[name]; ok {
+				g.generateService(name, descr, op, v.Object)
+				break
+			}
+			g.generateObject(name, descr, v.Object)
+		case *ast.TypeSpec_Interface:
+			g.P()
+			g.generateOneof(name, descr, g.impls[name])
+		case *ast.TypeSpec_Union:
+			var members []string
+			for _, mem := range v.Union.Members {
+				members = append(members, mem.Name)
+			}
+
+			g.P()
+			g.generateOneof(name, descr, members)
+		case *ast.TypeSpec_Enum:
+			g.P()
+			g.generateEnum(name, descr, v.Enum)
+		case *ast.TypeSpec_Input:
+			g.P()
+			g.generateInput(name, descr, v.Input)
+		}
+	}
+	g.generateLists()
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	// Open file to write to
+	protoFile, err := gCtx.Open(filepath.Join(filepath.Dir(doc.Name), fileName(doc)+".proto"))
+	if err != nil {
+		return
+	}
+	defer protoFile.Close()
+
+	// Write generated output
+	_, err = g.header().WriteTo(protoFile)
+	if err != nil {
+		return
+	}
+	_, err = g.WriteTo(protoFile)
+	return
+}
+
+// header returns the syntax, package, imports and options of the file,
+// which are only known once the types have been generated.
+//
+func (g *Generator) header() *bytes.Buffer {
+	var b bytes.Buffer
+	b.WriteString("syntax = \"proto3\";\n")
+
+	if g.opts.Package != "" {
+		fmt.Fprintf(&b, "\npackage %s;\n", g.opts.Package)
+	}
+
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for imp := range g.imports {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+
+		b.WriteByte('\n')
+		for _, imp := range imports {
+			fmt.Fprintf(&b, "import %q;\n", imp)
+		}
+	}
+
+	if g.opts.GoPackage != "" {
+		fmt.Fprintf(&b, "\noption go_package = %q;\n", g.opts.GoPackage)
+	}
+	return &b
+}
+
+// fileName returns the name, without extension, of the generated file
+// which follows the protobuf convention of lower snake case file names.
+//
+func fileName(doc *ast.Document) string {
+	base := filepath.Base(doc.Name)
+	name := snakeCase(base[:len(base)-len(filepath.Ext(base))])
+	if name == "" {
+		return "schema"
+	}
+	return name
+}
+
+// rootTypes maps the root operation types to their operation.
+func rootTypes(doc *ast.Document) map[string]string {
+	roots := make(map[string]string, 3)
+	if doc.Schema == nil {
+		roots["Query"] = "query"
+		roots["Mutation"] = "mutation"
+		roots["Subscription"] = "subscription"
+		return roots
+	}
+
+	schema := doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Schema).Schema
+	for _, f := range schema.RootOps.List {
+		roots[f.Type.(*ast.Field_Ident).Ident.Name] = f.Name.Name
+	}
+	return roots
+}
+
+func (g *Generator) generateObject(name string, doc *ast.DocGroup, obj *ast.ObjectType) {
+	var fields []field
+	if obj.Fields != nil {
+		for _, f := range obj.Fields.List {
+			fields = append(fields, field{name: f.Name.Name, typ: fieldType(f), doc: f.Doc, dirs: f.Directives})
+		}
+	}
+
+	g.printComment(doc)
+	g.generateMessage(name, fields)
+}
+
+func (g *Generator) generateInput(name string, doc *ast.DocGroup, input *ast.InputType) {
+	g.printComment(doc)
+	g.generateMessage(name, inputFields(input.Fields))
+}
+
+// generateOneof generates a message for an interface or union which
+// holds exactly one of its possible types.
+//
+func (g *Generator) generateOneof(name string, doc *ast.DocGroup, members []string) {
+	g.printComment(doc)
+	if len(members) == 0 {
+		g.P("message ", name, " {}")
+		return
+	}
+
+	g.P("message ", name, " {")
+	g.In()
+	g.P("oneof value {")
+	g.In()
+	for i, mem := range members {
+		g.P(mem, " ", snakeCase(mem), " = ", i+1, ";")
+	}
+	g.Out()
+	g.P("}")
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateEnum(name string, doc *ast.DocGroup, enum *ast.EnumType) {
+	prefix := strings.ToUpper(snakeCase(name)) + "_"
+
+	g.printComment(doc)
+	g.P("enum ", name, " {")
+	g.In()
+	g.P(prefix, "UNSPECIFIED = 0;")
+
+	if enum.Values != nil {
+		for i, v := range enum.Values.List {
+			if g.opts.Descriptions {
+				g.printComment(v.Doc)
+			}
+			g.P(prefix, v.Name.Name, " = ", i+1, fieldOpts(v.Directives), ";")
+		}
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+// generateService generates a service for a root operation type with an
+// rpc for every field. The arguments of a field are passed as a request
+// message and any result which isn't a single message is wrapped in a
+// response message.
+//
+func (g *Generator) generateService(name string, doc *ast.DocGroup, op string, obj *ast.ObjectType) {
+	var fields []*ast.Field
+	if obj.Fields != nil {
+		fields = obj.Fields.List
+	}
+
+	stream := ""
+	if op == "subscription" {
+		stream = "stream "
+	}
+
+	g.printComment(doc)
+	g.P("service ", name, " {")
+	g.In()
+	for _, f := range fields {
+		if g.opts.Descriptions {
+			g.printComment(f.Doc)
+		}
+
+		req := "google.protobuf.Empty"
+		if f.Args != nil && len(f.Args.List) > 0 {
+			req = pascalCase(f.Name.Name) + "Request"
+		} else {
+			g.imports["google/protobuf/empty.proto"] = true
+		}
+
+		res := g.messageType(fieldType(f))
+		if res == "" {
+			res = pascalCase(f.Name.Name) + "Response"
+		}
+
+		g.P("rpc ", pascalCase(f.Name.Name), "(", req, ") returns (", stream, res, ")", fieldOpts(f.Directives), ";")
+	}
+	g.Out()
+	g.P("}")
+
+	for _, f := range fields {
+		if f.Args != nil && len(f.Args.List) > 0 {
+			g.P()
+			g.generateMessage(pascalCase(f.Name.Name)+"Request", inputFields(f.Args))
+		}
+
+		if g.messageType(fieldType(f)) == "" {
+			g.P()
+			g.generateMessage(pascalCase(f.Name.Name)+"Response", []field{{name: "value", typ: fieldType(f)}})
+		}
+	}
+}
+
+// messageType returns the name of a type if it's a single message.
+func (g *Generator) messageType(typ interface{}) string {
+	if nn, ok := typ.(*ast.NonNull); ok {
+		id, ok := nn.Type.(*ast.NonNull_Ident)
+		if !ok {
+			return ""
+		}
+		typ = id.Ident
+	}
+
+	id, ok := typ.(*ast.Ident)
+	if !ok || !g.isMessage(id.Name) {
+		return ""
+	}
+	return id.Name
+}
+
+// field is a single field of a generated message.
+type field struct {
+	name string
+	typ  interface{}
+	doc  *ast.DocGroup
+	dirs []*ast.DirectiveLit
+}
+
+func inputFields(list *ast.InputValueList) (fields []field) {
+	if list == nil {
+		return
+	}
+
+	for _, f := range list.List {
+		fields = append(fields, field{name: f.Name.Name, typ: inputValueType(f), doc: f.Doc, dirs: f.Directives})
+	}
+	return
+}
+
+// generateMessage generates a message whose fields are numbered in order.
+func (g *Generator) generateMessage(name string, fields []field) {
+	if len(fields) == 0 {
+		g.P("message ", name, " {}")
+		return
+	}
+
+	g.P("message ", name, " {")
+	g.In()
+	for i, f := range fields {
+		if g.opts.Descriptions {
+			g.printComment(f.doc)
+		}
+
+		label, typ := g.protoType(f.typ)
+		g.P(label, typ, " ", snakeCase(f.name), " = ", i+1, fieldOpts(f.dirs), ";")
+	}
+	g.Out()
+	g.P("}")
+}
+
+// generateLists generates the wrapper messages of nested lists, since
+// repeated fields can't be repeated themselves.
+//
+func (g *Generator) generateLists() {
+	for len(g.lists) > 0 {
+		names := make([]string, 0, len(g.lists))
+		for name := range g.lists {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			typ := g.lists[name]
+			delete(g.lists, name)
+
+			g.P()
+			g.generateMessage(name, []field{{name: "values", typ: typ}})
+		}
+	}
+}
+
+// fieldOpts returns the options of a field, rpc or enum value.
+func fieldOpts(dirs []*ast.DirectiveLit) string {
+	for _, d := range dirs {
+		if d.Name == "deprecated" {
+			return " [deprecated = true]"
+		}
+	}
+	return ""
+}
+
+func fieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func inputValueType(a *ast.InputValue) interface{} {
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+var scalarTypes = map[string]string{
+	"Int":     "int32",
+	"Float":   "double",
+	"String":  "string",
+	"Boolean": "bool",
+	"ID":      "string",
+}
+
+var wrapperTypes = map[string]string{
+	"Int":     "google.protobuf.Int32Value",
+	"Float":   "google.protobuf.DoubleValue",
+	"String":  "google.protobuf.StringValue",
+	"Boolean": "google.protobuf.BoolValue",
+	"ID":      "google.protobuf.StringValue",
+}
+
+// protoType returns the label and type of a message field. Lists are
+// repeated, which drops both their own and their elements nullability, and
+// nullable scalars and enums are either optional or, for scalars, wrapped.
+//
+func (g *Generator) protoType(typ interface{}) (label, name string) {
+	nonNull := false
+	if nn, ok := typ.(*ast.NonNull); ok {
+		nonNull = true
+		switch w := nn.Type.(type) {
+		case *ast.NonNull_Ident:
+			typ = w.Ident
+		case *ast.NonNull_List:
+			typ = w.List
+		}
+	}
+
+	switch v := typ.(type) {
+	case *ast.Ident:
+		if nonNull || g.isMessage(v.Name) {
+			return "", g.baseType(v.Name)
+		}
+
+		if g.opts.Nullable == Wrappers && g.kinds[v.Name] != token.Token_ENUM {
+			g.imports["google/protobuf/wrappers.proto"] = true
+
+			if w, ok := wrapperTypes[v.Name]; ok {
+				return "", w
+			}
+			return "", wrapperTypes["String"]
+		}
+		return "optional ", g.baseType(v.Name)
+	case *ast.List:
+		return "repeated ", g.elemType(v)
+	}
+	return "", "string"
+}
+
+// elemType returns the type of the elements of a list. Lists of lists
+// are wrapped in a message e.g. [[Int]] is repeated Int32List.
+//
+func (g *Generator) elemType(l *ast.List) string {
+	switch w := l.Type.(type) {
+	case *ast.List_Ident:
+		return g.baseType(w.Ident.Name)
+	case *ast.List_List:
+		return g.listMessage(w.List)
+	case *ast.List_NonNull:
+		switch x := w.NonNull.Type.(type) {
+		case *ast.NonNull_Ident:
+			return g.baseType(x.Ident.Name)
+		case *ast.NonNull_List:
+			return g.listMessage(x.List)
+		}
+	}
+	return "string"
+}
+
+func (g *Generator) listMessage(l *ast.List) string {
+	name := pascalCase(g.elemType(l)) + "List"
+	g.lists[name] = l
+	return name
+}
+
+// baseType returns the proto type of a named GraphQL type. Custom
+// scalars are serialized as strings.
+//
+func (g *Generator) baseType(name string) string {
+	if t, ok := scalarTypes[name]; ok {
+		return t
+	}
+	if g.kinds[name] == token.Token_SCALAR {
+		return "string"
+	}
+	return name
+}
+
+// isMessage reports whether a named type is generated as a message.
+func (g *Generator) isMessage(name string) bool {
+	switch g.kinds[name] {
+	case token.Token_TYPE, token.Token_INTERFACE, token.Token_UNION, token.Token_INPUT:
+		return true
+	}
+	return false
+}
+
+func (g *Generator) printComment(doc *ast.DocGroup) {
+	if doc == nil {
+		return
+	}
+
+	text := strings.TrimSpace(doc.Text())
+	if text == "" {
+		return
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if len(line) == 0 {
+			g.P("//")
+			continue
+		}
+		g.P("// ", line)
+	}
+}
+
+// snakeCase converts a name e.g. hasNextPage or userID to snake case
+// e.g. has_next_page or user_id.
+//
+func snakeCase(s string) string {
+	rs := []rune(s)
+
+	var b strings.Builder
+	for i, r := range rs {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteByte('_')
+			}
+			continue
+		}
+
+		if unicode.IsUpper(r) && i > 0 {
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.Trim(b.String(), "_")
+}
+
+// pascalCase converts a name e.g. hasNextPage or int32 to PascalCase e.g. HasNextPage or Int32.
+func pascalCase(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func normNullable(s string) string {
+	return strings.ToUpper(strings.Trim(s, `"`))
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Nullable:     Optional,
+		Descriptions: true,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "proto" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		protoOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range protoOpts.Fields {
+			switch arg.Key.Name {
+			case "package":
+				gOpts.Package = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			case "goPackage":
+				gOpts.GoPackage = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			case "nullable":
+				gOpts.Nullable = normNullable(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+			case "descriptions":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Descriptions = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts != nil {
+		if p, ok := opts["package"]; ok {
+			gOpts.Package, _ = p.(string)
+		}
+		if p, ok := opts["goPackage"]; ok {
+			gOpts.GoPackage, _ = p.(string)
+		}
+		if n, ok := opts["nullable"]; ok {
+			s, _ := n.(string)
+			gOpts.Nullable = normNullable(s)
+		}
+		if d, ok := opts["descriptions"]; ok {
+			gOpts.Descriptions, _ = d.(bool)
+		}
+	}
+
+	if gOpts.Nullable != Optional && gOpts.Nullable != Wrappers {
+		return gOpts, fmt.Errorf("unsupported nullable mapping: %s", gOpts.Nullable)
+	}
+	return
+}
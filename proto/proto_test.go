@@ -0,0 +1,446 @@
+package proto
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "test.proto", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected proto output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected proto output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestNames(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		Snake string
+	}{
+		{Name: "hasNextPage", Snake: "has_next_page"},
+		{Name: "userID", Snake: "user_id"},
+		{Name: "HTMLParser", Snake: "html_parser"},
+		{Name: "SearchResult", Snake: "search_result"},
+		{Name: "star-wars", Snake: "star_wars"},
+		{Name: "api_v2", Snake: "api_v2"},
+	}
+
+	for _, testCase := range testCases {
+		if name := snakeCase(testCase.Name); name != testCase.Snake {
+			t.Errorf("expected snake case %s for %s, but got: %s", testCase.Snake, testCase.Name, name)
+		}
+	}
+
+	if name := fileName(&ast.Document{Name: "dir/StarWars.graphql"}); name != "star_wars" {
+		t.Errorf("expected file name star_wars, but got: %s", name)
+	}
+
+	if name := pascalCase("has_next_page"); name != "HasNextPage" {
+		t.Errorf("expected name to be PascalCase, but got: %s", name)
+	}
+}
+
+func TestProtoType(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Nullable string
+		Type     interface{}
+		Label    string
+		Proto    string
+	}{
+		{Name: "Optional", Nullable: Optional, Type: &ast.Ident{Name: "Int"}, Label: "optional ", Proto: "int32"},
+		{Name: "Wrapper", Nullable: Wrappers, Type: &ast.Ident{Name: "Int"}, Proto: "google.protobuf.Int32Value"},
+		{Name: "WrappedScalar", Nullable: Wrappers, Type: &ast.Ident{Name: "Time"}, Proto: "google.protobuf.StringValue"},
+		{Name: "OptionalEnum", Nullable: Wrappers, Type: &ast.Ident{Name: "Direction"}, Label: "optional ", Proto: "Direction"},
+		{Name: "Message", Nullable: Optional, Type: &ast.Ident{Name: "Node"}, Proto: "Node"},
+		{Name: "NonNull", Nullable: Wrappers, Type: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}, Proto: "string"},
+		{
+			Name:     "List",
+			Nullable: Wrappers,
+			Type:     &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Boolean"}}},
+			Label:    "repeated ",
+			Proto:    "bool",
+		},
+		{
+			Name:     "NestedList",
+			Nullable: Optional,
+			Type:     &ast.NonNull{Type: &ast.NonNull_List{List: &ast.List{Type: &ast.List_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Float"}}}}}}},
+			Label:    "repeated ",
+			Proto:    "DoubleList",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			g := &Generator{opts: &Options{Nullable: testCase.Nullable}}
+			g.Reset()
+			g.kinds["Time"] = token.Token_SCALAR
+			g.kinds["Direction"] = token.Token_ENUM
+			g.kinds["Node"] = token.Token_INTERFACE
+
+			label, typ := g.protoType(testCase.Type)
+			if label != testCase.Label || typ != testCase.Proto {
+				subT.Errorf("expected: %s%s, but got: %s%s", testCase.Label, testCase.Proto, label, typ)
+			}
+		})
+	}
+}
+
+func TestObject(t *testing.T) {
+	g := &Generator{opts: &Options{Nullable: Wrappers, Descriptions: true}}
+	g.Reset()
+	g.kinds["Node"] = token.Token_INTERFACE
+
+	obj := &ast.ObjectType{
+		Fields: &ast.FieldList{
+			List: []*ast.Field{
+				{
+					Doc:  &ast.DocGroup{List: []*ast.DocGroup_Doc{{Text: `"id uniquely identifies the node."`, Char: '"'}}},
+					Name: &ast.Ident{Name: "id"},
+					Type: &ast.Field_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}},
+				},
+				{
+					Name: &ast.Ident{Name: "pageCount"},
+					Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "Int"}},
+					Directives: []*ast.DirectiveLit{
+						{Name: "deprecated"},
+					},
+				},
+				{
+					Name: &ast.Ident{Name: "matrix"},
+					Type: &ast.Field_List{List: &ast.List{Type: &ast.List_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Node"}}}}}},
+				},
+			},
+		},
+	}
+
+	g.generateObject("Test", nil, obj)
+	g.generateLists()
+
+	ex := []byte(`message Test {
+  // id uniquely identifies the node.
+  string id = 1;
+  google.protobuf.Int32Value page_count = 2 [deprecated = true];
+  repeated NodeList matrix = 3;
+}
+
+message NodeList {
+  repeated Node values = 1;
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+
+	if !g.imports["google/protobuf/wrappers.proto"] {
+		t.Error("expected wrappers to be imported")
+	}
+}
+
+func TestOneof(t *testing.T) {
+	g := &Generator{opts: &Options{}}
+	g.Reset()
+
+	g.generateOneof("SearchResult", nil, []string{"Echo", "SearchResult"})
+	g.P()
+	g.generateOneof("Node", nil, nil)
+
+	ex := []byte(`message SearchResult {
+  oneof value {
+    Echo echo = 1;
+    SearchResult search_result = 2;
+  }
+}
+
+message Node {}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestEnum(t *testing.T) {
+	g := &Generator{opts: &Options{}}
+	g.Reset()
+
+	enum := &ast.EnumType{
+		Values: &ast.FieldList{
+			List: []*ast.Field{
+				{Name: &ast.Ident{Name: "NORTH_EAST"}},
+				{
+					Name: &ast.Ident{Name: "WEST"},
+					Directives: []*ast.DirectiveLit{
+						{Name: "deprecated"},
+					},
+				},
+			},
+		},
+	}
+
+	g.generateEnum("CardinalDirection", nil, enum)
+
+	ex := []byte(`enum CardinalDirection {
+  CARDINAL_DIRECTION_UNSPECIFIED = 0;
+  CARDINAL_DIRECTION_NORTH_EAST = 1;
+  CARDINAL_DIRECTION_WEST = 2 [deprecated = true];
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestService(t *testing.T) {
+	g := &Generator{opts: &Options{Nullable: Optional}}
+	g.Reset()
+	g.kinds["User"] = token.Token_TYPE
+
+	obj := &ast.ObjectType{
+		Fields: &ast.FieldList{
+			List: []*ast.Field{
+				{
+					Name: &ast.Ident{Name: "user"},
+					Type: &ast.Field_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "User"}}}},
+					Args: &ast.InputValueList{List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "id"},
+							Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}},
+						},
+					}},
+				},
+				{
+					Name: &ast.Ident{Name: "users"},
+					Type: &ast.Field_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "User"}}}},
+				},
+			},
+		},
+	}
+
+	g.generateService("Subscription", nil, "subscription", obj)
+
+	ex := []byte(`service Subscription {
+  rpc User(UserRequest) returns (stream User);
+  rpc Users(google.protobuf.Empty) returns (stream UsersResponse);
+}
+
+message UserRequest {
+  string id = 1;
+}
+
+message UsersResponse {
+  repeated User value = 1;
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestOptions(t *testing.T) {
+	doc := &ast.Document{
+		Directives: []*ast.DirectiveLit{
+			{
+				Name: "proto",
+				Args: &ast.CallExpr{Args: []*ast.Arg{{
+					Name: &ast.Ident{Name: "options"},
+					Value: &ast.Arg_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+						Fields: []*ast.ObjLit_Pair{
+							{
+								Key: &ast.Ident{Name: "package"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: `"example"`}}},
+							},
+							{
+								Key: &ast.Ident{Name: "nullable"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_IDENT, Value: "WRAPPERS"}}},
+							},
+						},
+					}}}},
+				}}},
+			},
+		},
+	}
+
+	gOpts, err := getOptions(doc, map[string]interface{}{"package": "other.example"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.Package != "other.example" || gOpts.Nullable != Wrappers || !gOpts.Descriptions {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+
+	_, err = getOptions(&ast.Document{}, map[string]interface{}{"nullable": "pointers"})
+	if err == nil {
+		t.Error("expected unsupported nullable mapping to fail")
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := new(Generator)
+	g.Reset()
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `schema {
+	query: Query
+}
+
+"Query represents the queries this example provides."
+type Query {
+	hello: String
+}
+
+"A user of the service."
+type User {
+	name: String!
+	nickname: String
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, map[string]interface{}{"package": "example"})
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Println(b.String())
+	// Output:
+	// syntax = "proto3";
+	//
+	// package example;
+	//
+	// import "google/protobuf/empty.proto";
+	//
+	// // Query represents the queries this example provides.
+	// service Query {
+	//   rpc Hello(google.protobuf.Empty) returns (HelloResponse);
+	// }
+	//
+	// message HelloResponse {
+	//   optional string value = 1;
+	// }
+	//
+	// // A user of the service.
+	// message User {
+	//   string name = 1;
+	//   optional string nickname = 2;
+	// }
+}
@@ -0,0 +1,635 @@
+// Package python contains a Python generator for GraphQL Documents.
+// The generated code targets the graphene library.
+package python
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/gen/printer"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+// Options contains the options for the Python generator.
+type Options struct {
+	// Copy descriptions to Python docstrings and description arguments (default: true)
+	Descriptions bool
+}
+
+// Generator generates Python code for a GraphQL schema.
+type Generator struct {
+	printer.Printer
+
+	log *zap.Logger
+}
+
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Printer.Reset()
+	g.Indent = "    "
+}
+
+// declOrder is the order in which types are written. Python
+// resolves class references eagerly in Meta classes so interfaces
+// must precede objects and objects must precede unions.
+//
+var declOrder = []func(*ast.TypeSpec) bool{
+	func(ts *ast.TypeSpec) bool { _, ok := ts.Type.(*ast.TypeSpec_Scalar); return ok },
+	func(ts *ast.TypeSpec) bool { _, ok := ts.Type.(*ast.TypeSpec_Enum); return ok },
+	func(ts *ast.TypeSpec) bool { _, ok := ts.Type.(*ast.TypeSpec_Interface); return ok },
+	func(ts *ast.TypeSpec) bool { _, ok := ts.Type.(*ast.TypeSpec_Object); return ok },
+	func(ts *ast.TypeSpec) bool { _, ok := ts.Type.(*ast.TypeSpec_Input); return ok },
+	func(ts *ast.TypeSpec) bool { _, ok := ts.Type.(*ast.TypeSpec_Union); return ok },
+}
+
+// Generate generates Python code for the given document.
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "py",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("python").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	gOpts, oerr := getOptions(doc, opts)
+	if oerr != nil {
+		return oerr
+	}
+
+	g.P("import graphene")
+
+	// Generate types
+	g.log.Info("generating types")
+	for _, isKind := range declOrder {
+		for _, d := range doc.Types {
+			ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+			if !ok || !isKind(ts.TypeSpec) {
+				continue
+			}
+
+			g.P()
+			g.P()
+
+			name := ts.TypeSpec.Name.Name
+			switch ts.TypeSpec.Type.(type) {
+			case *ast.TypeSpec_Scalar:
+				g.generateScalar(name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+			case *ast.TypeSpec_Object:
+				g.generateObject(name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+			case *ast.TypeSpec_Interface:
+				g.generateInterface(name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+			case *ast.TypeSpec_Union:
+				g.generateUnion(name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+			case *ast.TypeSpec_Enum:
+				g.generateEnum(name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+			case *ast.TypeSpec_Input:
+				g.generateInput(name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+			}
+		}
+	}
+
+	// Generate schema
+	if doc.Schema != nil {
+		g.log.Info("generating schema")
+		g.P()
+		g.P()
+		g.generateSchema(doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec)
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	// Open file to write to
+	pyFileName := doc.Name[:len(doc.Name)-len(filepath.Ext(doc.Name))]
+	pyFile, err := gCtx.Open(pyFileName + ".py")
+	if err != nil {
+		return
+	}
+	defer pyFile.Close()
+
+	// Write generated output
+	_, err = g.WriteTo(pyFile)
+	return
+}
+
+func (g *Generator) generateSchema(ts *ast.TypeSpec) {
+	schema := ts.Type.(*ast.TypeSpec_Schema).Schema
+
+	g.WriteIndent()
+	g.WriteString("schema = graphene.Schema(")
+	for i, f := range schema.RootOps.List {
+		if i > 0 {
+			g.WriteString(", ")
+		}
+		g.WriteString(strings.ToLower(f.Name.Name))
+		g.WriteByte('=')
+		g.WriteString(f.Type.(*ast.Field_Ident).Ident.Name)
+	}
+	g.WriteString(")\n")
+}
+
+func (g *Generator) generateScalar(name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	g.P("class ", name, "(graphene.Scalar):")
+	g.In()
+
+	if descr && g.printDocstring(doc) {
+		g.P()
+	}
+
+	g.P("@staticmethod")
+	g.P("def serialize(value):")
+	g.In()
+	g.P("# TODO")
+	g.P("return value")
+	g.Out()
+	g.P()
+
+	g.P("@staticmethod")
+	g.P("def parse_literal(node):")
+	g.In()
+	g.P("# TODO")
+	g.P("return node.value")
+	g.Out()
+	g.P()
+
+	g.P("@staticmethod")
+	g.P("def parse_value(value):")
+	g.In()
+	g.P("# TODO")
+	g.P("return value")
+	g.Out()
+
+	g.Out()
+}
+
+func (g *Generator) generateObject(name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	obj := ts.Type.(*ast.TypeSpec_Object).Object
+
+	g.P("class ", name, "(graphene.ObjectType):")
+	g.In()
+
+	hasDoc := descr && g.printDocstring(doc)
+
+	if len(obj.Interfaces) > 0 {
+		if hasDoc {
+			g.P()
+		}
+
+		g.P("class Meta:")
+		g.In()
+		g.WriteIndent()
+		g.WriteString("interfaces = (")
+		for i, inter := range obj.Interfaces {
+			if i > 0 {
+				g.WriteString(", ")
+			}
+			g.WriteString(inter.Name)
+		}
+		if len(obj.Interfaces) == 1 {
+			g.WriteByte(',')
+		}
+		g.WriteString(")\n")
+		g.Out()
+		hasDoc = true
+	}
+
+	g.generateFields(obj.Fields, descr, hasDoc)
+
+	g.Out()
+}
+
+func (g *Generator) generateInterface(name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	inter := ts.Type.(*ast.TypeSpec_Interface).Interface
+
+	g.P("class ", name, "(graphene.Interface):")
+	g.In()
+
+	hasDoc := descr && g.printDocstring(doc)
+	g.generateFields(inter.Fields, descr, hasDoc)
+
+	g.Out()
+}
+
+func (g *Generator) generateFields(fields *ast.FieldList, descr, sep bool) {
+	if fields == nil || len(fields.List) == 0 {
+		if !sep {
+			g.P("pass")
+		}
+		return
+	}
+
+	if sep {
+		g.P()
+	}
+
+	for _, f := range fields.List {
+		var fieldType interface{}
+		switch v := f.Type.(type) {
+		case *ast.Field_Ident:
+			fieldType = v.Ident
+		case *ast.Field_List:
+			fieldType = v.List
+		case *ast.Field_NonNull:
+			fieldType = v.NonNull
+		}
+
+		g.WriteIndent()
+		g.WriteString(f.Name.Name)
+		g.WriteString(" = graphene.Field(")
+		g.printType(fieldType)
+
+		if f.Args != nil && len(f.Args.List) > 0 {
+			g.WriteString(", args={")
+			for i, a := range f.Args.List {
+				if i > 0 {
+					g.WriteString(", ")
+				}
+				g.WriteString(quote(a.Name.Name))
+				g.WriteString(": ")
+				g.printInputValue("graphene.Argument", a, descr)
+			}
+			g.WriteByte('}')
+		}
+
+		if descr {
+			g.printDescr(f.Doc)
+		}
+		g.WriteString(")\n")
+	}
+}
+
+func (g *Generator) generateUnion(name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	union := ts.Type.(*ast.TypeSpec_Union).Union
+
+	g.P("class ", name, "(graphene.Union):")
+	g.In()
+
+	if descr && g.printDocstring(doc) {
+		g.P()
+	}
+
+	g.P("class Meta:")
+	g.In()
+	g.WriteIndent()
+	g.WriteString("types = (")
+	for i, mem := range union.Members {
+		if i > 0 {
+			g.WriteString(", ")
+		}
+		g.WriteString(mem.Name)
+	}
+	if len(union.Members) == 1 {
+		g.WriteByte(',')
+	}
+	g.WriteString(")\n")
+	g.Out()
+
+	g.Out()
+}
+
+func (g *Generator) generateEnum(name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	enum := ts.Type.(*ast.TypeSpec_Enum).Enum
+
+	g.P("class ", name, "(graphene.Enum):")
+	g.In()
+
+	if descr && g.printDocstring(doc) {
+		g.P()
+	}
+
+	for _, v := range enum.Values.List {
+		if descr && v.Doc != nil {
+			for _, line := range strings.Split(strings.TrimSpace(v.Doc.Text()), "\n") {
+				g.P("# ", line)
+			}
+		}
+
+		g.P(v.Name.Name, " = ", quote(v.Name.Name))
+	}
+
+	g.Out()
+}
+
+func (g *Generator) generateInput(name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	input := ts.Type.(*ast.TypeSpec_Input).Input
+
+	g.P("class ", name, "(graphene.InputObjectType):")
+	g.In()
+
+	hasDoc := descr && g.printDocstring(doc)
+
+	if input.Fields == nil || len(input.Fields.List) == 0 {
+		if !hasDoc {
+			g.P("pass")
+		}
+		g.Out()
+		return
+	}
+
+	if hasDoc {
+		g.P()
+	}
+
+	for _, f := range input.Fields.List {
+		g.WriteIndent()
+		g.WriteString(f.Name.Name)
+		g.WriteString(" = ")
+		g.printInputValue("graphene.InputField", f, descr)
+		g.WriteByte('\n')
+	}
+
+	g.Out()
+}
+
+// printInputValue prints an argument or input field constructor.
+func (g *Generator) printInputValue(ctor string, a *ast.InputValue, descr bool) {
+	var typ interface{}
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		typ = v.Ident
+	case *ast.InputValue_List:
+		typ = v.List
+	case *ast.InputValue_NonNull:
+		typ = v.NonNull
+	}
+
+	g.WriteString(ctor)
+	g.WriteByte('(')
+	g.printType(typ)
+
+	if a.Default != nil {
+		g.WriteString(", default_value=")
+
+		var defType interface{}
+		switch v := a.Default.(type) {
+		case *ast.InputValue_BasicLit:
+			defType = v.BasicLit
+		case *ast.InputValue_CompositeLit:
+			defType = v.CompositeLit
+		}
+		g.printVal(defType)
+	}
+
+	if descr {
+		g.printDescr(a.Doc)
+	}
+	g.WriteByte(')')
+}
+
+// printDocstring prints a class docstring and reports whether anything was printed.
+func (g *Generator) printDocstring(doc *ast.DocGroup) bool {
+	if doc == nil {
+		return false
+	}
+
+	text := strings.TrimSpace(doc.Text())
+	if len(text) == 0 {
+		return false
+	}
+
+	text = strings.Replace(text, `\`, `\\`, -1)
+	text = strings.Replace(text, `"""`, `\"\"\"`, -1)
+
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		g.P(`"""`, lines[0], `"""`)
+		return true
+	}
+
+	g.P(`"""`, lines[0])
+	for _, line := range lines[1:] {
+		if len(line) == 0 {
+			g.P()
+			continue
+		}
+		g.P(line)
+	}
+	g.P(`"""`)
+	return true
+}
+
+// printDescr prints a description keyword argument.
+func (g *Generator) printDescr(doc *ast.DocGroup) {
+	if doc == nil {
+		return
+	}
+
+	text := doc.Text()
+	if len(text) == 0 {
+		return
+	}
+
+	g.WriteString(", description=")
+	g.WriteString(quote(text[:len(text)-1]))
+}
+
+// quote returns s as a double quoted Python string literal.
+func quote(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// printType prints a field type
+func (g *Generator) printType(typ interface{}) {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		switch v.Name {
+		case "Int", "Float", "String", "Boolean", "ID":
+			g.WriteString("graphene.")
+			g.WriteString(v.Name)
+		default:
+			// Lambdas allow for referencing types which are declared later on
+			g.WriteString("lambda: ")
+			g.WriteString(v.Name)
+		}
+	case *ast.List:
+		g.WriteString("graphene.List(")
+
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			typ = w.Ident
+		case *ast.List_List:
+			typ = w.List
+		case *ast.List_NonNull:
+			typ = w.NonNull
+		}
+		g.printType(typ)
+
+		g.WriteByte(')')
+	case *ast.NonNull:
+		g.WriteString("graphene.NonNull(")
+
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			typ = w.Ident
+		case *ast.NonNull_List:
+			typ = w.List
+		}
+		g.printType(typ)
+
+		g.WriteByte(')')
+	}
+}
+
+// printVal prints a value
+func (g *Generator) printVal(val interface{}) {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		switch v.Kind {
+		case token.Token_STRING:
+			g.WriteString(quote(strings.Trim(v.Value, `"`)))
+		case token.Token_IDENT:
+			g.WriteString(quote(v.Value))
+		case token.Token_BOOL:
+			if v.Value == "true" {
+				g.WriteString("True")
+			} else {
+				g.WriteString("False")
+			}
+		case token.Token_NULL:
+			g.WriteString("None")
+		default:
+			g.WriteString(v.Value)
+		}
+	case *ast.ListLit:
+		g.printList(v)
+	case *ast.ObjLit:
+		g.printObject(v)
+	case *ast.CompositeLit:
+		switch w := v.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			g.printVal(w.BasicLit)
+		case *ast.CompositeLit_ListLit:
+			g.printVal(w.ListLit)
+		case *ast.CompositeLit_ObjLit:
+			g.printVal(w.ObjLit)
+		}
+	}
+}
+
+func (g *Generator) printList(v *ast.ListLit) {
+	g.WriteByte('[')
+
+	var vals []interface{}
+	switch w := v.List.(type) {
+	case *ast.ListLit_BasicList:
+		for _, bval := range w.BasicList.Values {
+			vals = append(vals, bval)
+		}
+	case *ast.ListLit_CompositeList:
+		for _, cval := range w.CompositeList.Values {
+			vals = append(vals, cval)
+		}
+	}
+
+	vLen := len(vals) - 1
+	for i, iv := range vals {
+		g.printVal(iv)
+		if i != vLen {
+			g.WriteByte(',')
+			g.WriteByte(' ')
+		}
+	}
+
+	g.WriteByte(']')
+}
+
+func (g *Generator) printObject(v *ast.ObjLit) {
+	g.WriteByte('{')
+
+	pLen := len(v.Fields) - 1
+	for i, p := range v.Fields {
+		g.WriteString(quote(p.Key.Name))
+		g.WriteString(": ")
+
+		g.printVal(p.Val)
+
+		if i != pLen {
+			g.WriteByte(',')
+			g.WriteByte(' ')
+		}
+	}
+
+	g.WriteByte('}')
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Descriptions: true,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "py" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		pyOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range pyOpts.Fields {
+			switch arg.Key.Name {
+			case "descriptions":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Descriptions = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts == nil {
+		return
+	}
+	if d, ok := opts["descriptions"]; ok {
+		gOpts.Descriptions, _ = d.(bool)
+	}
+
+	return
+}
@@ -0,0 +1,376 @@
+package python
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "test.py", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected python output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected python output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestScalar(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	g.generateScalar("Test", false, nil, &ast.TypeSpec{})
+
+	ex := []byte(`class Test(graphene.Scalar):
+    @staticmethod
+    def serialize(value):
+        # TODO
+        return value
+
+    @staticmethod
+    def parse_literal(node):
+        # TODO
+        return node.value
+
+    @staticmethod
+    def parse_value(value):
+        # TODO
+        return value
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestObject(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	t.Run("JustFields", func(subT *testing.T) {
+		g.Reset()
+
+		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Object{
+			Object: &ast.ObjectType{
+				Fields: &ast.FieldList{
+					List: []*ast.Field{
+						{
+							Name: &ast.Ident{Name: "one"},
+							Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "Int"}},
+						},
+						{
+							Name: &ast.Ident{Name: "list"},
+							Type: &ast.Field_List{List: &ast.List{Type: &ast.List_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Test"}}}}}},
+						},
+						{
+							Name: &ast.Ident{Name: "withArgs"},
+							Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "String"}},
+							Args: &ast.InputValueList{List: []*ast.InputValue{
+								{
+									Name: &ast.Ident{Name: "str"},
+									Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "String"}},
+									Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+										Kind:  token.Token_STRING,
+										Value: `"hello"`,
+									}},
+								},
+								{
+									Name: &ast.Ident{Name: "flag"},
+									Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "Boolean"}},
+									Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+										Kind:  token.Token_BOOL,
+										Value: "false",
+									}},
+								},
+							}},
+						},
+					},
+				},
+			},
+		}}
+
+		g.generateObject("Test", false, nil, ts)
+
+		ex := []byte(`class Test(graphene.ObjectType):
+    one = graphene.Field(graphene.Int)
+    list = graphene.Field(graphene.List(graphene.NonNull(lambda: Test)))
+    withArgs = graphene.Field(graphene.String, args={"str": graphene.Argument(graphene.String, default_value="hello"), "flag": graphene.Argument(graphene.Boolean, default_value=False)})
+`)
+
+		gen.CompareBytes(subT, ex, g.Bytes())
+	})
+
+	t.Run("WithInterfaces", func(subT *testing.T) {
+		g.Reset()
+
+		ts := &ast.TypeSpec{Type: &ast.TypeSpec_Object{
+			Object: &ast.ObjectType{
+				Interfaces: []*ast.Ident{{Name: "A"}},
+				Fields: &ast.FieldList{
+					List: []*ast.Field{
+						{
+							Name: &ast.Ident{Name: "one"},
+							Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "Int"}},
+						},
+					},
+				},
+			},
+		}}
+
+		g.generateObject("Test", false, nil, ts)
+
+		ex := []byte(`class Test(graphene.ObjectType):
+    class Meta:
+        interfaces = (A,)
+
+    one = graphene.Field(graphene.Int)
+`)
+
+		gen.CompareBytes(subT, ex, g.Bytes())
+	})
+}
+
+func TestUnion(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Union{
+		Union: &ast.UnionType{
+			Members: []*ast.Ident{{Name: "A"}, {Name: "B"}},
+		},
+	}}
+
+	g.generateUnion("Test", false, nil, ts)
+
+	ex := []byte(`class Test(graphene.Union):
+    class Meta:
+        types = (A, B)
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestEnum(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Enum{
+		Enum: &ast.EnumType{
+			Values: &ast.FieldList{
+				List: []*ast.Field{
+					{Name: &ast.Ident{Name: "A"}},
+					{Name: &ast.Ident{Name: "B"}},
+				},
+			},
+		},
+	}}
+
+	g.generateEnum("Test", false, nil, ts)
+
+	ex := []byte(`class Test(graphene.Enum):
+    A = "A"
+    B = "B"
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestInput(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Input{
+		Input: &ast.InputType{
+			Fields: &ast.InputValueList{
+				List: []*ast.InputValue{
+					{
+						Name: &ast.Ident{Name: "one"},
+						Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Int"}}}},
+					},
+					{
+						Name: &ast.Ident{Name: "list"},
+						Type: &ast.InputValue_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Int"}}}},
+						Default: &ast.InputValue_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ListLit{
+							ListLit: &ast.ListLit{
+								List: &ast.ListLit_BasicList{BasicList: &ast.ListLit_Basic{Values: []*ast.BasicLit{
+									{Kind: token.Token_INT, Value: "1"},
+									{Kind: token.Token_INT, Value: "2"},
+								}}},
+							},
+						}}},
+					},
+				},
+			},
+		},
+	}}
+
+	g.generateInput("Test", false, nil, ts)
+
+	ex := []byte(`class Test(graphene.InputObjectType):
+    one = graphene.InputField(graphene.NonNull(graphene.Int))
+    list = graphene.InputField(graphene.List(graphene.Int), default_value=[1, 2])
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestDocstring(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	doc := &ast.DocGroup{List: []*ast.DocGroup_Doc{
+		{Text: "# First line.", Char: '#'},
+		{Text: "# Second line with a \\ backslash.", Char: '#'},
+	}}
+
+	g.generateInput("Test", true, doc, &ast.TypeSpec{Type: &ast.TypeSpec_Input{Input: &ast.InputType{}}})
+
+	ex := []byte(`class Test(graphene.InputObjectType):
+    """First line.
+    Second line with a \\ backslash.
+    """
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := new(Generator)
+	g.Reset()
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `schema {
+	query: Query
+}
+
+"Query represents the queries this example provides."
+type Query {
+	hello: String
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, nil)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Println(b.String())
+}
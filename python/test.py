@@ -0,0 +1,89 @@
+import graphene
+
+
+class Version(graphene.Scalar):
+    """Version represents an API version."""
+
+    @staticmethod
+    def serialize(value):
+        # TODO
+        return value
+
+    @staticmethod
+    def parse_literal(node):
+        # TODO
+        return node.value
+
+    @staticmethod
+    def parse_value(value):
+        # TODO
+        return value
+
+
+class Direction(graphene.Enum):
+    """Direction represents a cardinal direction."""
+
+    # EnumValue description
+    NORTH = "NORTH"
+    EAST = "EAST"
+    SOUTH = "SOUTH"
+    # EnumValue Description and Directives.
+    WEST = "WEST"
+
+
+class Connection(graphene.Interface):
+    """Connection represents a set of edges, which are meant to be paginated."""
+
+    total = graphene.Field(graphene.Int, description="total returns the total number of edges.")
+    edges = graphene.Field(graphene.List(lambda: Node), description="edges contains the current page of edges.")
+    hasNextPage = graphene.Field(graphene.Boolean, description="hasNextPage tells if there exists more edges.")
+
+
+class Node(graphene.Interface):
+    """Node represents a node."""
+
+    id = graphene.Field(graphene.NonNull(graphene.ID), description="id uniquely identifies the node.")
+
+
+class Echo(graphene.ObjectType):
+    """Echo represents an echo message."""
+
+    msg = graphene.Field(graphene.NonNull(graphene.String), description="msg contains the provided message.")
+
+
+class Query(graphene.ObjectType):
+    """Query represents valid queries."""
+
+    version = graphene.Field(lambda: Version, description="version returns the current API version.")
+    echo = graphene.Field(lambda: Echo, args={"text": graphene.Argument(graphene.NonNull(graphene.String))}, description="echo echos a message.")
+    search = graphene.Field(lambda: Result, args={"text": graphene.Argument(graphene.String, description="text is a single text input to use for searching."), "terms": graphene.Argument(graphene.List(graphene.String), description="terms represent term based querying.")}, description="search performs a search over some data set.")
+
+
+class Result(graphene.ObjectType):
+    """Result represents a search result."""
+
+    class Meta:
+        interfaces = (Connection,)
+
+    total = graphene.Field(graphene.Int, description="total yields the total number of search results.")
+    edges = graphene.Field(graphene.List(lambda: Node), description="edges contains the search results.")
+    hasNextPage = graphene.Field(graphene.Boolean, description="hasNextPage tells if there are more search results.")
+
+
+class Point(graphene.InputObjectType):
+    """Point represents a 2-D geo point."""
+
+    x = graphene.InputField(graphene.NonNull(graphene.Float))
+    y = graphene.InputField(graphene.NonNull(graphene.Float))
+    label = graphene.InputField(graphene.String, default_value="origin")
+    visible = graphene.InputField(graphene.Boolean, default_value=True)
+
+
+class SearchResult(graphene.Union):
+    """SearchResult is a test union type"""
+
+    class Meta:
+        types = (Echo, Result)
+
+
+schema = graphene.Schema(query=Query)
@@ -0,0 +1,816 @@
+// Package rust contains a Rust generator for GraphQL Documents.
+// The generated code targets either async-graphql or juniper.
+package rust
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/gen/printer"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+// Supported frameworks
+const (
+	AsyncGraphQL = "ASYNC_GRAPHQL"
+	Juniper      = "JUNIPER"
+)
+
+// Options contains the options for the Rust generator.
+type Options struct {
+	// Either "ASYNC_GRAPHQL" or "JUNIPER" (default: ASYNC_GRAPHQL)
+	Framework string
+
+	// Copy descriptions to Rust doc comments (default: true)
+	Descriptions bool
+}
+
+// Generator generates Rust code for a GraphQL schema.
+type Generator struct {
+	printer.Printer
+
+	opts *Options
+	log  *zap.Logger
+}
+
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Printer.Reset()
+	g.Indent = "    "
+}
+
+// Generate generates Rust code for the given document.
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "rs",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("rust").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	g.opts, err = getOptions(doc, opts)
+	if err != nil {
+		return
+	}
+
+	// Root operation types are generated as resolver stubs
+	roots := make(map[string]bool)
+	if doc.Schema != nil {
+		schema := doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Schema).Schema
+		for _, f := range schema.RootOps.List {
+			roots[f.Type.(*ast.Field_Ident).Ident.Name] = true
+		}
+	}
+
+	g.writeHeader()
+
+	// Generate types
+	g.log.Info("generating types")
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Schema); ok {
+			continue
+		}
+
+		name := ts.TypeSpec.Name.Name
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar:
+			g.P()
+			g.generateScalar(name, d.Doc)
+		case *ast.TypeSpec_Object:
+			g.P()
+			if roots[name] || hasArgs(v.Object.Fields) {
+				g.generateResolver(name, d.Doc, v.Object)
+				break
+			}
+			g.generateObject(name, d.Doc, v.Object)
+		case *ast.TypeSpec_Interface:
+			g.P()
+			g.generateInterface(name, d.Doc, v.Interface, implementors(doc, name))
+		case *ast.TypeSpec_Union:
+			g.P()
+			g.generateUnion(name, d.Doc, v.Union)
+		case *ast.TypeSpec_Enum:
+			g.P()
+			g.generateEnum(name, d.Doc, v.Enum)
+		case *ast.TypeSpec_Input:
+			g.P()
+			g.generateInput(name, d.Doc, v.Input)
+		}
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	// Open file to write to
+	rsFileName := doc.Name[:len(doc.Name)-len(filepath.Ext(doc.Name))]
+	rsFile, err := gCtx.Open(rsFileName + ".rs")
+	if err != nil {
+		return
+	}
+	defer rsFile.Close()
+
+	// Write generated output
+	_, err = g.WriteTo(rsFile)
+	return
+}
+
+func (g *Generator) writeHeader() {
+	if g.opts.Framework == Juniper {
+		g.P("use juniper::*;")
+		return
+	}
+
+	g.P("use async_graphql::*;")
+}
+
+// hasArgs reports whether any field in the list takes arguments.
+func hasArgs(fields *ast.FieldList) bool {
+	if fields == nil {
+		return false
+	}
+
+	for _, f := range fields.List {
+		if f.Args != nil && len(f.Args.List) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// implementors returns the names of all objects which implement the given interface.
+func implementors(doc *ast.Document, inter string) (names []string) {
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+
+		obj, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Object)
+		if !ok {
+			continue
+		}
+
+		for _, i := range obj.Object.Interfaces {
+			if i.Name == inter {
+				names = append(names, ts.TypeSpec.Name.Name)
+				break
+			}
+		}
+	}
+	return
+}
+
+func (g *Generator) generateScalar(name string, doc *ast.DocGroup) {
+	g.printDoc(doc)
+
+	if g.opts.Framework == Juniper {
+		g.P("#[derive(GraphQLScalar)]")
+		g.P("#[graphql(transparent)]")
+		g.P("pub struct ", name, "(pub String);")
+		return
+	}
+
+	g.P("pub struct ", name, "(pub String);")
+	g.P()
+	g.P("#[Scalar]")
+	g.P("impl ScalarType for ", name, " {")
+	g.In()
+	g.P("fn parse(value: Value) -> InputValueResult<Self> {")
+	g.In()
+	g.P("todo!()")
+	g.Out()
+	g.P("}")
+	g.P()
+	g.P("fn to_value(&self) -> Value {")
+	g.In()
+	g.P("todo!()")
+	g.Out()
+	g.P("}")
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateObject(name string, doc *ast.DocGroup, obj *ast.ObjectType) {
+	g.printDoc(doc)
+
+	derive := "SimpleObject"
+	if g.opts.Framework == Juniper {
+		derive = "GraphQLObject"
+	}
+	g.P("#[derive(", derive, ")]")
+
+	if g.opts.Framework == Juniper && len(obj.Interfaces) > 0 {
+		g.P("#[graphql(impl = [", interfaceValues(obj.Interfaces), "])]")
+	}
+
+	g.P("pub struct ", name, " {")
+	g.In()
+
+	for _, f := range obj.Fields.List {
+		g.printDoc(f.Doc)
+		g.printAttrs(f.Name.Name, snakeCase(f.Name.Name), f.Directives, "")
+		g.P("pub ", fieldName(f.Name.Name), ": ", rustType(fieldType(f)), ",")
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+// interfaceValues lists the juniper generated value types for the given interfaces.
+func interfaceValues(inters []*ast.Ident) string {
+	vals := make([]string, len(inters))
+	for i, inter := range inters {
+		vals[i] = inter.Name + "Value"
+	}
+	return strings.Join(vals, ", ")
+}
+
+// generateResolver generates a resolver stub for objects whose
+// fields can't simply be derived e.g. root operation types.
+//
+func (g *Generator) generateResolver(name string, doc *ast.DocGroup, obj *ast.ObjectType) {
+	g.printDoc(doc)
+	g.P("pub struct ", name, ";")
+	g.P()
+
+	if g.opts.Framework == Juniper {
+		if len(obj.Interfaces) > 0 {
+			g.P("#[graphql_object(impl = [", interfaceValues(obj.Interfaces), "])]")
+		} else {
+			g.P("#[graphql_object]")
+		}
+	} else {
+		g.P("#[Object]")
+	}
+	g.P("impl ", name, " {")
+	g.In()
+
+	for i, f := range obj.Fields.List {
+		if i > 0 {
+			g.P()
+		}
+
+		g.printDoc(f.Doc)
+		g.printAttrs(f.Name.Name, snakeCase(f.Name.Name), f.Directives, "")
+
+		var sig bytes.Buffer
+		if g.opts.Framework == Juniper {
+			sig.WriteString("fn ")
+			sig.WriteString(fieldName(f.Name.Name))
+			sig.WriteString("(&self")
+		} else {
+			sig.WriteString("async fn ")
+			sig.WriteString(fieldName(f.Name.Name))
+			sig.WriteString("(&self, ctx: &async_graphql::Context<'_>")
+		}
+
+		if f.Args != nil {
+			for _, a := range f.Args.List {
+				sig.WriteString(", ")
+
+				typ := inputType(a)
+				if a.Default != nil {
+					sig.WriteString(g.defaultAttr(a, typ))
+					sig.WriteByte(' ')
+				}
+
+				sig.WriteString(fieldName(a.Name.Name))
+				sig.WriteString(": ")
+				sig.WriteString(rustType(typ))
+			}
+		}
+
+		if g.opts.Framework == Juniper {
+			sig.WriteString(") -> FieldResult<")
+		} else {
+			sig.WriteString(") -> async_graphql::Result<")
+		}
+		sig.WriteString(rustType(fieldType(f)))
+		sig.WriteString("> {")
+
+		g.P(sig.String())
+		g.In()
+		g.P("todo!()")
+		g.Out()
+		g.P("}")
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateInterface(name string, doc *ast.DocGroup, inter *ast.InterfaceType, impls []string) {
+	g.printDoc(doc)
+
+	if g.opts.Framework == Juniper {
+		g.WriteIndent()
+		g.WriteString("#[graphql_interface(for = [")
+		g.WriteString(strings.Join(impls, ", "))
+		g.WriteString("])]\n")
+
+		g.P("pub struct ", name, " {")
+		g.In()
+		for _, f := range inter.Fields.List {
+			g.printDoc(f.Doc)
+			g.printAttrs(f.Name.Name, snakeCase(f.Name.Name), f.Directives, "")
+			g.P("pub ", fieldName(f.Name.Name), ": ", rustType(fieldType(f)), ",")
+		}
+		g.Out()
+		g.P("}")
+		return
+	}
+
+	g.P("#[derive(Interface)]")
+	g.P("#[graphql(")
+	g.In()
+	for _, f := range inter.Fields.List {
+		g.P("field(name = ", strconv.Quote(f.Name.Name), ", ty = ", strconv.Quote(rustType(fieldType(f))), "),")
+	}
+	g.Out()
+	g.P(")]")
+
+	g.P("pub enum ", name, " {")
+	g.In()
+	for _, impl := range impls {
+		g.P(impl, "(", impl, "),")
+	}
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateUnion(name string, doc *ast.DocGroup, union *ast.UnionType) {
+	g.printDoc(doc)
+
+	if g.opts.Framework == Juniper {
+		g.P("#[derive(GraphQLUnion)]")
+	} else {
+		g.P("#[derive(Union)]")
+	}
+
+	g.P("pub enum ", name, " {")
+	g.In()
+	for _, mem := range union.Members {
+		g.P(mem.Name, "(", mem.Name, "),")
+	}
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateEnum(name string, doc *ast.DocGroup, enum *ast.EnumType) {
+	g.printDoc(doc)
+
+	if g.opts.Framework == Juniper {
+		g.P("#[derive(GraphQLEnum, Clone, Copy, Debug, Eq, PartialEq)]")
+	} else {
+		g.P("#[derive(Enum, Clone, Copy, Debug, Eq, PartialEq)]")
+	}
+
+	g.P("pub enum ", name, " {")
+	g.In()
+	for _, v := range enum.Values.List {
+		variant := pascalCase(v.Name.Name)
+
+		g.printDoc(v.Doc)
+		g.printAttrs(v.Name.Name, strings.ToUpper(snakeCase(variant)), v.Directives, "")
+		g.P(variant, ",")
+	}
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateInput(name string, doc *ast.DocGroup, input *ast.InputType) {
+	g.printDoc(doc)
+
+	if g.opts.Framework == Juniper {
+		g.P("#[derive(GraphQLInputObject)]")
+	} else {
+		g.P("#[derive(InputObject)]")
+	}
+
+	g.P("pub struct ", name, " {")
+	g.In()
+
+	if input.Fields != nil {
+		for _, f := range input.Fields.List {
+			typ := inputType(f)
+
+			var def string
+			if f.Default != nil {
+				def = g.defaultArg(f, typ)
+			}
+
+			g.printDoc(f.Doc)
+			g.printAttrs(f.Name.Name, snakeCase(f.Name.Name), f.Directives, def)
+			g.P("pub ", fieldName(f.Name.Name), ": ", rustType(typ), ",")
+		}
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+// printAttrs prints the #[graphql(...)] attribute for a field or enum value.
+// The name is only given when the frameworks naming convention wouldn't
+// produce the original GraphQL name.
+//
+func (g *Generator) printAttrs(gqlName, rsName string, dirs []*ast.DirectiveLit, extra string) {
+	var attrs []string
+
+	if gqlName != graphqlName(rsName) {
+		attrs = append(attrs, "name = "+strconv.Quote(gqlName))
+	}
+
+	if reason, ok := deprecation(dirs); ok {
+		key := "deprecation"
+		if g.opts.Framework == Juniper {
+			key = "deprecated"
+		}
+		attrs = append(attrs, key+" = "+strconv.Quote(reason))
+	}
+
+	if extra != "" {
+		attrs = append(attrs, extra)
+	}
+
+	if len(attrs) == 0 {
+		return
+	}
+	g.P("#[graphql(", strings.Join(attrs, ", "), ")]")
+}
+
+// defaultArg returns the attribute argument for an input values default.
+func (g *Generator) defaultArg(a *ast.InputValue, typ interface{}) string {
+	var val interface{}
+	switch v := a.Default.(type) {
+	case *ast.InputValue_BasicLit:
+		val = v.BasicLit
+	case *ast.InputValue_CompositeLit:
+		val = v.CompositeLit
+	}
+
+	expr := rustVal(val, typ)
+	if g.opts.Framework == Juniper {
+		return "default = " + expr
+	}
+	return "default_with = " + strconv.Quote(expr)
+}
+
+// defaultAttr returns the attribute for a resolver arguments default.
+func (g *Generator) defaultAttr(a *ast.InputValue, typ interface{}) string {
+	return "#[graphql(" + g.defaultArg(a, typ) + ")]"
+}
+
+// deprecation returns the reason given to a @deprecated directive.
+func deprecation(dirs []*ast.DirectiveLit) (string, bool) {
+	for _, d := range dirs {
+		if d.Name != "deprecated" {
+			continue
+		}
+
+		reason := "No longer supported"
+		if d.Args == nil {
+			return reason, true
+		}
+
+		for _, arg := range d.Args.Args {
+			if arg.Name.Name != "reason" {
+				continue
+			}
+
+			if b, ok := arg.Value.(*ast.Arg_BasicLit); ok {
+				reason = strings.Trim(b.BasicLit.Value, `"`)
+			}
+		}
+		return reason, true
+	}
+	return "", false
+}
+
+func (g *Generator) printDoc(doc *ast.DocGroup) {
+	if !g.opts.Descriptions || doc == nil {
+		return
+	}
+
+	text := strings.TrimSpace(doc.Text())
+	if len(text) == 0 {
+		return
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if len(line) == 0 {
+			g.P("///")
+			continue
+		}
+		g.P("/// ", line)
+	}
+}
+
+func fieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func inputType(a *ast.InputValue) interface{} {
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+// rustType returns the Rust type for a GraphQL type.
+func rustType(typ interface{}) string {
+	t, nonNull := rustTypeOf(typ)
+	if nonNull {
+		return t
+	}
+	return "Option<" + t + ">"
+}
+
+func rustTypeOf(typ interface{}) (string, bool) {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		switch v.Name {
+		case "Int":
+			return "i32", false
+		case "Float":
+			return "f64", false
+		case "String":
+			return "String", false
+		case "Boolean":
+			return "bool", false
+		case "ID":
+			return "ID", false
+		}
+		return v.Name, false
+	case *ast.List:
+		var elem interface{}
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			elem = w.Ident
+		case *ast.List_List:
+			elem = w.List
+		case *ast.List_NonNull:
+			elem = w.NonNull
+		}
+		return "Vec<" + rustType(elem) + ">", false
+	case *ast.NonNull:
+		var elem interface{}
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			elem = w.Ident
+		case *ast.NonNull_List:
+			elem = w.List
+		}
+		t, _ := rustTypeOf(elem)
+		return t, true
+	}
+	return "", false
+}
+
+// rustVal returns a Rust expression for a GraphQL value of the given type.
+func rustVal(val, typ interface{}) string {
+	if c, ok := val.(*ast.CompositeLit); ok {
+		switch w := c.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			val = w.BasicLit
+		case *ast.CompositeLit_ListLit:
+			val = w.ListLit
+		case *ast.CompositeLit_ObjLit:
+			val = w.ObjLit
+		}
+	}
+
+	if b, ok := val.(*ast.BasicLit); ok && b.Kind == token.Token_NULL {
+		return "None"
+	}
+
+	inner, nonNull := typ, false
+	if nn, ok := typ.(*ast.NonNull); ok {
+		nonNull = true
+		switch w := nn.Type.(type) {
+		case *ast.NonNull_Ident:
+			inner = w.Ident
+		case *ast.NonNull_List:
+			inner = w.List
+		}
+	}
+
+	var expr string
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		expr = basicVal(v, inner)
+	case *ast.ListLit:
+		var elem interface{}
+		if l, ok := inner.(*ast.List); ok {
+			switch w := l.Type.(type) {
+			case *ast.List_Ident:
+				elem = w.Ident
+			case *ast.List_List:
+				elem = w.List
+			case *ast.List_NonNull:
+				elem = w.NonNull
+			}
+		}
+
+		var vals []string
+		switch w := v.List.(type) {
+		case *ast.ListLit_BasicList:
+			for _, bval := range w.BasicList.Values {
+				vals = append(vals, rustVal(bval, elem))
+			}
+		case *ast.ListLit_CompositeList:
+			for _, cval := range w.CompositeList.Values {
+				vals = append(vals, rustVal(cval, elem))
+			}
+		}
+		expr = "vec![" + strings.Join(vals, ", ") + "]"
+	default:
+		expr = "Default::default()"
+	}
+
+	if nonNull {
+		return expr
+	}
+	return "Some(" + expr + ")"
+}
+
+func basicVal(v *ast.BasicLit, typ interface{}) string {
+	switch v.Kind {
+	case token.Token_STRING:
+		return strconv.Quote(strings.Trim(v.Value, `"`)) + ".to_string()"
+	case token.Token_IDENT:
+		if id, ok := typ.(*ast.Ident); ok {
+			return id.Name + "::" + pascalCase(v.Value)
+		}
+		return v.Value
+	case token.Token_FLOAT:
+		return v.Value
+	case token.Token_INT:
+		if id, ok := typ.(*ast.Ident); ok && id.Name == "Float" {
+			return v.Value + ".0"
+		}
+		return v.Value
+	}
+	return v.Value
+}
+
+// rustKeywords are reserved in Rust and must be written as raw identifiers.
+var rustKeywords = map[string]bool{
+	"as": true, "async": true, "await": true, "break": true, "const": true,
+	"continue": true, "crate": true, "dyn": true, "else": true, "enum": true,
+	"extern": true, "false": true, "fn": true, "for": true, "if": true,
+	"impl": true, "in": true, "let": true, "loop": true, "match": true,
+	"mod": true, "move": true, "mut": true, "pub": true, "ref": true,
+	"return": true, "static": true, "struct": true, "super": true,
+	"trait": true, "true": true, "type": true, "unsafe": true, "use": true,
+	"where": true, "while": true,
+}
+
+func fieldName(name string) string {
+	name = snakeCase(name)
+	if rustKeywords[name] {
+		return "r#" + name
+	}
+	return name
+}
+
+// snakeCase converts a camelCase name into snake_case.
+func snakeCase(name string) string {
+	var b strings.Builder
+	rs := []rune(name)
+	for i, r := range rs {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(rs[i-1]) || (i+1 < len(rs) && unicode.IsLower(rs[i+1]))) && rs[i-1] != '_' {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// pascalCase converts a SCREAMING_SNAKE_CASE enum value into PascalCase.
+func pascalCase(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(strings.ToLower(name), "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
+
+// graphqlName is the inverse of the frameworks renaming rules i.e.
+// snake_case fields become camelCase and SCREAMING_SNAKE_CASE is kept.
+//
+func graphqlName(name string) string {
+	if strings.ToUpper(name) == name {
+		return name
+	}
+
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] == "" {
+			continue
+		}
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// normFramework allows frameworks to be given as e.g. async-graphql or juniper.
+func normFramework(s string) string {
+	return strings.Replace(strings.ToUpper(strings.Trim(s, `"`)), "-", "_", -1)
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Framework:    AsyncGraphQL,
+		Descriptions: true,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "rs" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		rsOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range rsOpts.Fields {
+			switch arg.Key.Name {
+			case "framework":
+				gOpts.Framework = normFramework(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+			case "descriptions":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Descriptions = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts != nil {
+		if f, ok := opts["framework"]; ok {
+			s, _ := f.(string)
+			gOpts.Framework = normFramework(s)
+		}
+		if d, ok := opts["descriptions"]; ok {
+			gOpts.Descriptions, _ = d.(bool)
+		}
+	}
+
+	if gOpts.Framework != AsyncGraphQL && gOpts.Framework != Juniper {
+		return gOpts, fmt.Errorf("unsupported framework: %s", gOpts.Framework)
+	}
+	return
+}
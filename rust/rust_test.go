@@ -0,0 +1,410 @@
+package rust
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "test.rs", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected rust output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected rust output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestNames(t *testing.T) {
+	testCases := []struct {
+		In, Snake, Pascal string
+	}{
+		{In: "hasNextPage", Snake: "has_next_page", Pascal: "Hasnextpage"},
+		{In: "id", Snake: "id", Pascal: "Id"},
+		{In: "HTTPStatus", Snake: "http_status", Pascal: "Httpstatus"},
+		{In: "NORTH_EAST", Snake: "north_east", Pascal: "NorthEast"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.In, func(subT *testing.T) {
+			if s := snakeCase(testCase.In); s != testCase.Snake {
+				subT.Errorf("expected snake case: %s, but got: %s", testCase.Snake, s)
+			}
+			if p := pascalCase(testCase.In); p != testCase.Pascal {
+				subT.Errorf("expected pascal case: %s, but got: %s", testCase.Pascal, p)
+			}
+		})
+	}
+
+	if n := graphqlName("has_next_page"); n != "hasNextPage" {
+		t.Errorf("expected graphql name: hasNextPage, but got: %s", n)
+	}
+	if n := fieldName("type"); n != "r#type" {
+		t.Errorf("expected raw identifier: r#type, but got: %s", n)
+	}
+}
+
+func TestRustType(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Type interface{}
+		Ex   string
+	}{
+		{
+			Name: "Nullable",
+			Type: &ast.Ident{Name: "Int"},
+			Ex:   "Option<i32>",
+		},
+		{
+			Name: "NonNull",
+			Type: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "String"}}},
+			Ex:   "String",
+		},
+		{
+			Name: "List",
+			Type: &ast.NonNull{Type: &ast.NonNull_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Node"}}}}},
+			Ex:   "Vec<Option<Node>>",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			if typ := rustType(testCase.Type); typ != testCase.Ex {
+				subT.Errorf("expected: %s, but got: %s", testCase.Ex, typ)
+			}
+		})
+	}
+}
+
+func TestObject(t *testing.T) {
+	obj := &ast.ObjectType{
+		Interfaces: []*ast.Ident{{Name: "Node"}},
+		Fields: &ast.FieldList{
+			List: []*ast.Field{
+				{
+					Name: &ast.Ident{Name: "id"},
+					Type: &ast.Field_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}},
+				},
+				{
+					Name: &ast.Ident{Name: "old_name"},
+					Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "String"}},
+					Directives: []*ast.DirectiveLit{{
+						Name: "deprecated",
+						Args: &ast.CallExpr{Args: []*ast.Arg{{
+							Name:  &ast.Ident{Name: "reason"},
+							Value: &ast.Arg_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: `"gone"`}},
+						}}},
+					}},
+				},
+			},
+		},
+	}
+
+	t.Run("AsyncGraphQL", func(subT *testing.T) {
+		g := &Generator{opts: &Options{Framework: AsyncGraphQL}}
+		g.Reset()
+		g.generateObject("Test", nil, obj)
+
+		ex := []byte(`#[derive(SimpleObject)]
+pub struct Test {
+    pub id: ID,
+    #[graphql(name = "old_name", deprecation = "gone")]
+    pub old_name: Option<String>,
+}
+`)
+
+		gen.CompareBytes(subT, ex, g.Bytes())
+	})
+
+	t.Run("Juniper", func(subT *testing.T) {
+		g := &Generator{opts: &Options{Framework: Juniper}}
+		g.Reset()
+		g.generateObject("Test", nil, obj)
+
+		ex := []byte(`#[derive(GraphQLObject)]
+#[graphql(impl = [NodeValue])]
+pub struct Test {
+    pub id: ID,
+    #[graphql(name = "old_name", deprecated = "gone")]
+    pub old_name: Option<String>,
+}
+`)
+
+		gen.CompareBytes(subT, ex, g.Bytes())
+	})
+}
+
+func TestResolver(t *testing.T) {
+	obj := &ast.ObjectType{
+		Fields: &ast.FieldList{
+			List: []*ast.Field{
+				{
+					Name: &ast.Ident{Name: "echo"},
+					Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "String"}},
+					Args: &ast.InputValueList{List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "text"},
+							Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "String"}},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_STRING,
+								Value: `"hello"`,
+							}},
+						},
+						{
+							Name: &ast.Ident{Name: "times"},
+							Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Int"}}}},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	t.Run("AsyncGraphQL", func(subT *testing.T) {
+		g := &Generator{opts: &Options{Framework: AsyncGraphQL}}
+		g.Reset()
+		g.generateResolver("Query", nil, obj)
+
+		ex := []byte(`pub struct Query;
+
+#[Object]
+impl Query {
+    async fn echo(&self, ctx: &async_graphql::Context<'_>, #[graphql(default_with = "Some(\"hello\".to_string())")] text: Option<String>, times: i32) -> async_graphql::Result<Option<String>> {
+        todo!()
+    }
+}
+`)
+
+		gen.CompareBytes(subT, ex, g.Bytes())
+	})
+
+	t.Run("Juniper", func(subT *testing.T) {
+		g := &Generator{opts: &Options{Framework: Juniper}}
+		g.Reset()
+		g.generateResolver("Query", nil, obj)
+
+		ex := []byte(`pub struct Query;
+
+#[graphql_object]
+impl Query {
+    fn echo(&self, #[graphql(default = Some("hello".to_string()))] text: Option<String>, times: i32) -> FieldResult<Option<String>> {
+        todo!()
+    }
+}
+`)
+
+		gen.CompareBytes(subT, ex, g.Bytes())
+	})
+}
+
+func TestEnum(t *testing.T) {
+	g := &Generator{opts: &Options{Framework: AsyncGraphQL}}
+	g.Reset()
+
+	enum := &ast.EnumType{
+		Values: &ast.FieldList{
+			List: []*ast.Field{
+				{Name: &ast.Ident{Name: "NORTH_EAST"}},
+				{Name: &ast.Ident{Name: "south"}},
+			},
+		},
+	}
+
+	g.generateEnum("Test", nil, enum)
+
+	ex := []byte(`#[derive(Enum, Clone, Copy, Debug, Eq, PartialEq)]
+pub enum Test {
+    NorthEast,
+    #[graphql(name = "south")]
+    South,
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestInterface(t *testing.T) {
+	g := &Generator{opts: &Options{Framework: AsyncGraphQL}}
+	g.Reset()
+
+	inter := &ast.InterfaceType{
+		Fields: &ast.FieldList{
+			List: []*ast.Field{
+				{
+					Name: &ast.Ident{Name: "id"},
+					Type: &ast.Field_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}},
+				},
+			},
+		},
+	}
+
+	g.generateInterface("Node", nil, inter, []string{"A", "B"})
+
+	ex := []byte(`#[derive(Interface)]
+#[graphql(
+    field(name = "id", ty = "ID"),
+)]
+pub enum Node {
+    A(A),
+    B(B),
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := new(Generator)
+	g.Reset()
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `schema {
+	query: Query
+}
+
+"Query represents the queries this example provides."
+type Query {
+	hello: String
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, nil)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Println(b.String())
+
+	// Output:
+	// use async_graphql::*;
+	//
+	// /// Query represents the queries this example provides.
+	// pub struct Query;
+	//
+	// #[Object]
+	// impl Query {
+	//     async fn hello(&self, ctx: &async_graphql::Context<'_>) -> async_graphql::Result<Option<String>> {
+	//         todo!()
+	//     }
+	// }
+}
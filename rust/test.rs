@@ -0,0 +1,115 @@
+use async_graphql::*;
+
+/// Version represents an API version.
+pub struct Version(pub String);
+
+#[Scalar]
+impl ScalarType for Version {
+    fn parse(value: Value) -> InputValueResult<Self> {
+        todo!()
+    }
+
+    fn to_value(&self) -> Value {
+        todo!()
+    }
+}
+
+/// Echo represents an echo message.
+#[derive(SimpleObject)]
+pub struct Echo {
+    /// msg contains the provided message.
+    pub msg: String,
+}
+
+/// Mutation represents valid mutations.
+pub struct Mutation;
+
+#[Object]
+impl Mutation {
+    /// move moves a point in the given direction.
+    async fn r#move(&self, ctx: &async_graphql::Context<'_>, point: Point, #[graphql(default_with = "Some(Direction::North)")] dir: Option<Direction>, #[graphql(default_with = "Some(1)")] steps: Option<i32>) -> async_graphql::Result<Option<bool>> {
+        todo!()
+    }
+}
+
+/// Query represents valid queries.
+pub struct Query;
+
+#[Object]
+impl Query {
+    /// version returns the current API version.
+    async fn version(&self, ctx: &async_graphql::Context<'_>) -> async_graphql::Result<Option<Version>> {
+        todo!()
+    }
+
+    /// echo echos a message.
+    async fn echo(&self, ctx: &async_graphql::Context<'_>, text: String) -> async_graphql::Result<Option<Echo>> {
+        todo!()
+    }
+
+    /// search performs a search over some data set.
+    async fn search(&self, ctx: &async_graphql::Context<'_>, text: Option<String>, terms: Option<Vec<Option<String>>>) -> async_graphql::Result<Option<Result>> {
+        todo!()
+    }
+}
+
+/// Result represents a search result.
+#[derive(SimpleObject)]
+pub struct Result {
+    /// total yields the total number of search results.
+    pub total: Option<i32>,
+    /// edges contains the search results.
+    pub edges: Option<Vec<Option<Node>>>,
+    /// hasNextPage tells if there are more search results.
+    pub has_next_page: Option<bool>,
+    /// count is the old name of total.
+    #[graphql(deprecation = "Use total.")]
+    pub count: Option<i32>,
+}
+
+/// Connection represents a set of edges, which are meant to be paginated.
+#[derive(Interface)]
+#[graphql(
+    field(name = "total", ty = "Option<i32>"),
+    field(name = "edges", ty = "Option<Vec<Option<Node>>>"),
+    field(name = "hasNextPage", ty = "Option<bool>"),
+)]
+pub enum Connection {
+    Result(Result),
+}
+
+/// Node represents a node.
+#[derive(Interface)]
+#[graphql(
+    field(name = "id", ty = "ID"),
+)]
+pub enum Node {
+}
+
+/// SearchResult is a test union type
+#[derive(Union)]
+pub enum SearchResult {
+    Echo(Echo),
+    Result(Result),
+}
+
+/// Direction represents a cardinal direction.
+#[derive(Enum, Clone, Copy, Debug, Eq, PartialEq)]
+pub enum Direction {
+    /// EnumValue description
+    North,
+    East,
+    South,
+    /// EnumValue Description and Directives.
+    West,
+}
+
+/// Point represents a 2-D geo point.
+#[derive(InputObject)]
+pub struct Point {
+    pub x: f64,
+    #[graphql(default_with = "0.0")]
+    pub y: f64,
+    #[graphql(default_with = "Some(\"origin\".to_string())")]
+    pub label: Option<String>,
+}
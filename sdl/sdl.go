@@ -0,0 +1,725 @@
+// Package sdl contains a GraphQL SDL generator, which prints documents back
+// out in a canonical format, much like gofmt does for Go.
+//
+package sdl
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/gen/printer"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+// MaxWidth is the width at which argument lists and unions are wrapped.
+const MaxWidth = 80
+
+// Options contains the options for the SDL generator.
+type Options struct {
+	// Keep descriptions (default: true)
+	Descriptions bool
+
+	// Keep # comments (default: true)
+	Comments bool
+
+	// Extension of files, whose names don't already have one (default: graphql)
+	Extension string
+}
+
+// Generator generates canonical GraphQL SDL for a GraphQL schema.
+type Generator struct {
+	printer.Printer
+
+	opts *Options
+	log  *zap.Logger
+}
+
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Printer.Reset()
+	g.Indent = "  "
+	g.opts = nil
+}
+
+// Generate prints the given document as canonical SDL. The output is named
+// after the document, so it can replace its source file e.g. schema.gql
+// generates schema.gql, given the extension gql.
+//
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "sdl",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("sdl").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	g.opts, err = getOptions(doc, opts)
+	if err != nil {
+		return
+	}
+
+	g.log.Info("printing document")
+	g.printDocument(doc)
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	f, err := gCtx.Open(fileName(doc, g.opts.Extension))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	_, err = g.WriteTo(f)
+	return
+}
+
+// Format returns a document as canonical SDL.
+func Format(doc *ast.Document, opts *Options) []byte {
+	g := &Generator{}
+	g.Reset()
+	g.opts = opts
+	if g.opts == nil {
+		g.opts = &Options{Descriptions: true, Comments: true, Extension: "graphql"}
+	}
+
+	g.printDocument(doc)
+	return g.Bytes()
+}
+
+// fileName returns the name of the SDL file generated for a document. The
+// CLI strips the extension of document names, so it's given separately.
+//
+func fileName(doc *ast.Document, ext string) string {
+	name := filepath.Base(doc.Name)
+	if filepath.Ext(name) == "" {
+		name += "." + strings.TrimPrefix(ext, ".")
+	}
+	return name
+}
+
+// printDocument prints the document comments and directives, followed by
+// its declarations in canonical order.
+//
+func (g *Generator) printDocument(doc *ast.Document) {
+	if g.opts.Comments {
+		g.printComments(doc.Doc)
+	}
+
+	// Imports leave document directives unordered, so they're sorted
+	dirs := make([]*ast.DirectiveLit, len(doc.Directives))
+	copy(dirs, doc.Directives)
+	sort.SliceStable(dirs, func(i, j int) bool { return dirs[i].Name < dirs[j].Name })
+	for _, d := range dirs {
+		g.P(directiveString(d))
+	}
+
+	decls := make([]*ast.TypeDecl, 0, len(doc.Types)+1)
+	hasSchema := false
+	for _, d := range doc.Types {
+		if ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec); ok {
+			if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Schema); ok {
+				hasSchema = true
+			}
+		}
+		decls = append(decls, d)
+	}
+	if !hasSchema && doc.Schema != nil {
+		decls = append(decls, doc.Schema)
+	}
+	sortDecls(decls)
+
+	for i, d := range decls {
+		if i > 0 || g.Len() > 0 {
+			g.P()
+		}
+		g.printDecl(d)
+	}
+}
+
+// declOrder ranks declarations by kind.
+func declOrder(ts *ast.TypeSpec) int {
+	switch ts.Type.(type) {
+	case *ast.TypeSpec_Schema:
+		return 0
+	case *ast.TypeSpec_Scalar:
+		return 1
+	case *ast.TypeSpec_Object:
+		return 2
+	case *ast.TypeSpec_Interface:
+		return 3
+	case *ast.TypeSpec_Union:
+		return 4
+	case *ast.TypeSpec_Enum:
+		return 5
+	case *ast.TypeSpec_Input:
+		return 6
+	case *ast.TypeSpec_Directive:
+		return 7
+	}
+	return 8
+}
+
+// sortDecls sorts declarations into their canonical order: by kind, then
+// by name, with type extensions after every definition.
+//
+// Schema < Scalar < Object < Interface < Union < Enum < Input < Directive < Extensions
+//
+func sortDecls(decls []*ast.TypeDecl) {
+	sort.SliceStable(decls, func(i, j int) bool {
+		its, iext := declSpec(decls[i])
+		jts, jext := declSpec(decls[j])
+		if iext != jext {
+			return jext
+		}
+
+		io, jo := declOrder(its), declOrder(jts)
+		if io != jo {
+			return io < jo
+		}
+		return specName(its) < specName(jts)
+	})
+}
+
+func declSpec(d *ast.TypeDecl) (*ast.TypeSpec, bool) {
+	switch v := d.Spec.(type) {
+	case *ast.TypeDecl_TypeSpec:
+		return v.TypeSpec, false
+	case *ast.TypeDecl_TypeExtSpec:
+		return v.TypeExtSpec.Type, true
+	}
+	return &ast.TypeSpec{}, false
+}
+
+func specName(ts *ast.TypeSpec) string {
+	if ts.Name == nil {
+		return ""
+	}
+	return ts.Name.Name
+}
+
+func (g *Generator) printDecl(d *ast.TypeDecl) {
+	ts, ext := declSpec(d)
+	g.printDoc(d.Doc)
+
+	var b strings.Builder
+	if ext {
+		b.WriteString("extend ")
+	}
+
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Schema:
+		b.WriteString("schema")
+		b.WriteString(directivesString(ts.Directives))
+		g.printBlock(b.String(), func() {
+			if v.Schema.RootOps == nil {
+				return
+			}
+			for _, f := range v.Schema.RootOps.List {
+				g.P(f.Name.Name, ": ", typeString(fieldType(f)))
+			}
+		})
+	case *ast.TypeSpec_Scalar:
+		b.WriteString("scalar ")
+		b.WriteString(specName(ts))
+		b.WriteString(directivesString(ts.Directives))
+		g.P(b.String())
+	case *ast.TypeSpec_Object:
+		b.WriteString("type ")
+		b.WriteString(specName(ts))
+		for i, inter := range v.Object.Interfaces {
+			if i == 0 {
+				b.WriteString(" implements ")
+			} else {
+				b.WriteString(" & ")
+			}
+			b.WriteString(inter.Name)
+		}
+		b.WriteString(directivesString(ts.Directives))
+		g.printFields(b.String(), v.Object.Fields)
+	case *ast.TypeSpec_Interface:
+		b.WriteString("interface ")
+		b.WriteString(specName(ts))
+		b.WriteString(directivesString(ts.Directives))
+		g.printFields(b.String(), v.Interface.Fields)
+	case *ast.TypeSpec_Union:
+		b.WriteString("union ")
+		b.WriteString(specName(ts))
+		b.WriteString(directivesString(ts.Directives))
+		g.printUnion(b.String(), v.Union.Members)
+	case *ast.TypeSpec_Enum:
+		b.WriteString("enum ")
+		b.WriteString(specName(ts))
+		b.WriteString(directivesString(ts.Directives))
+		g.printBlock(b.String(), func() {
+			if v.Enum.Values == nil {
+				return
+			}
+			for _, val := range v.Enum.Values.List {
+				g.printDoc(val.Doc)
+				g.P(val.Name.Name, directivesString(val.Directives))
+			}
+		})
+	case *ast.TypeSpec_Input:
+		b.WriteString("input ")
+		b.WriteString(specName(ts))
+		b.WriteString(directivesString(ts.Directives))
+		g.printBlock(b.String(), func() {
+			if v.Input.Fields == nil {
+				return
+			}
+			for _, f := range v.Input.Fields.List {
+				g.printDoc(f.Doc)
+				g.P(inputValueString(f))
+			}
+		})
+	case *ast.TypeSpec_Directive:
+		b.WriteString("directive @")
+		b.WriteString(specName(ts))
+
+		var locs []string
+		for _, loc := range v.Directive.Locs {
+			locs = append(locs, loc.Loc.String())
+		}
+		g.printArgs(b.String(), v.Directive.Args, " on "+strings.Join(locs, " | "))
+	}
+}
+
+// printBlock prints a declaration followed by a braced body. Bodies which
+// print nothing are left out, which is how extensions only adding
+// directives are written.
+//
+func (g *Generator) printBlock(head string, body func()) {
+	start := g.Len()
+	g.P(head, " {")
+	mark := g.Len()
+
+	g.In()
+	body()
+	g.Out()
+
+	if g.Len() == mark {
+		g.Truncate(start)
+		g.P(head)
+		return
+	}
+	g.P("}")
+}
+
+func (g *Generator) printFields(head string, fields *ast.FieldList) {
+	g.printBlock(head, func() {
+		if fields == nil {
+			return
+		}
+
+		for _, f := range fields.List {
+			g.printDoc(f.Doc)
+			g.printArgs(f.Name.Name, f.Args, ": "+typeString(fieldType(f))+directivesString(f.Directives))
+		}
+	})
+}
+
+// printArgs prints a field or directive with its arguments. Arguments are
+// kept on one line, unless any of them is described or the line would be
+// wider than MaxWidth.
+//
+func (g *Generator) printArgs(head string, args *ast.InputValueList, tail string) {
+	if args == nil || len(args.List) == 0 {
+		g.P(head, tail)
+		return
+	}
+
+	wrap := false
+	strs := make([]string, len(args.List))
+	for i, a := range args.List {
+		strs[i] = inputValueString(a)
+		if g.description(a.Doc) != "" || g.opts.Comments && len(comments(a.Doc)) > 0 {
+			wrap = true
+		}
+	}
+
+	line := head + "(" + strings.Join(strs, ", ") + ")" + tail
+	if !wrap && len(g.Indentation())*2+len(line) <= MaxWidth {
+		g.P(line)
+		return
+	}
+
+	g.P(head, "(")
+	g.In()
+	for i, a := range args.List {
+		g.printDoc(a.Doc)
+		g.P(strs[i])
+	}
+	g.Out()
+	g.P(")", tail)
+}
+
+func (g *Generator) printUnion(head string, members []*ast.Ident) {
+	if len(members) == 0 {
+		g.P(head)
+		return
+	}
+
+	names := make([]string, len(members))
+	for i, mem := range members {
+		names[i] = mem.Name
+	}
+
+	line := head + " = " + strings.Join(names, " | ")
+	if len(line) <= MaxWidth {
+		g.P(line)
+		return
+	}
+
+	g.P(head, " =")
+	g.In()
+	for _, name := range names {
+		g.P("| ", name)
+	}
+	g.Out()
+}
+
+// printDoc prints the comments and description of a node.
+func (g *Generator) printDoc(doc *ast.DocGroup) {
+	if g.opts.Comments {
+		g.printComments(doc)
+	}
+
+	descr := g.description(doc)
+	if descr == "" {
+		return
+	}
+
+	if !strings.ContainsAny(descr, "\n\"\\") && len(g.Indentation())*2+len(descr)+2 <= MaxWidth {
+		g.P(`"`, descr, `"`)
+		return
+	}
+
+	g.P(`"""`)
+	for _, line := range strings.Split(strings.Replace(descr, `"""`, `\"""`, -1), "\n") {
+		if line == "" {
+			g.WriteByte('\n')
+			continue
+		}
+		g.P(line)
+	}
+	g.P(`"""`)
+}
+
+func (g *Generator) printComments(doc *ast.DocGroup) {
+	for _, c := range comments(doc) {
+		if c == "" {
+			g.P("#")
+			continue
+		}
+		g.P("# ", c)
+	}
+}
+
+// comments returns the lines of the # comments of a node.
+func comments(doc *ast.DocGroup) (lines []string) {
+	if doc == nil {
+		return
+	}
+
+	for _, d := range doc.List {
+		if !d.Comment {
+			continue
+		}
+
+		for _, line := range strings.Split(strings.TrimRight(d.Text, "\n"), "\n") {
+			line = strings.TrimPrefix(strings.TrimSpace(line), "#")
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	return
+}
+
+// description returns the normalized description of a node, if descriptions
+// are enabled.
+//
+func (g *Generator) description(doc *ast.DocGroup) string {
+	if !g.opts.Descriptions {
+		return ""
+	}
+	return Description(doc)
+}
+
+// Description returns the normalized description of a node. Block strings
+// are dedented and escapes in strings are resolved, so every description is
+// printed the same way regardless of how it was written. Comments are left out.
+//
+func Description(doc *ast.DocGroup) string {
+	if doc == nil {
+		return ""
+	}
+
+	var descrs []string
+	for _, d := range doc.List {
+		if d.Comment {
+			continue
+		}
+
+		if s := strings.TrimSpace(StringValue(strings.TrimSpace(d.Text))); s != "" {
+			descrs = append(descrs, s)
+		}
+	}
+	return strings.Join(descrs, "\n\n")
+}
+
+// StringValue returns the value of a GraphQL string or block string literal.
+func StringValue(s string) string {
+	if strings.HasPrefix(s, `"""`) && strings.HasSuffix(s, `"""`) && len(s) >= 6 {
+		return blockValue(strings.Replace(s[3:len(s)-3], `\"""`, `"""`, -1))
+	}
+
+	u, err := strconv.Unquote(s)
+	if err != nil {
+		return strings.Trim(s, `"`)
+	}
+	return u
+}
+
+// blockValue removes the common indentation and the leading and trailing
+// blank lines of a block string, as described by the GraphQL spec.
+//
+func blockValue(raw string) string {
+	lines := strings.Split(strings.Replace(raw, "\r\n", "\n", -1), "\n")
+
+	common := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+
+		if n := len(line) - len(trimmed); common == -1 || n < common {
+			common = n
+		}
+	}
+	if common > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= common {
+				lines[i] = lines[i][common:]
+			} else {
+				lines[i] = strings.TrimLeft(lines[i], " \t")
+			}
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func inputValueString(a *ast.InputValue) string {
+	s := a.Name.Name + ": " + typeString(inputValueType(a))
+
+	switch v := a.Default.(type) {
+	case *ast.InputValue_BasicLit:
+		s += " = " + valueString(v.BasicLit)
+	case *ast.InputValue_CompositeLit:
+		s += " = " + valueString(v.CompositeLit)
+	}
+	return s + directivesString(a.Directives)
+}
+
+// typeString prints a type reference.
+func typeString(typ interface{}) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		return v.Name
+	case *ast.List:
+		var elem interface{}
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			elem = w.Ident
+		case *ast.List_List:
+			elem = w.List
+		case *ast.List_NonNull:
+			elem = w.NonNull
+		}
+		return "[" + typeString(elem) + "]"
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return typeString(w.Ident) + "!"
+		case *ast.NonNull_List:
+			return typeString(w.List) + "!"
+		}
+	}
+	return ""
+}
+
+// directivesString prints applied directives, each preceded by a space.
+func directivesString(dirs []*ast.DirectiveLit) string {
+	var b strings.Builder
+	for _, d := range dirs {
+		b.WriteByte(' ')
+		b.WriteString(directiveString(d))
+	}
+	return b.String()
+}
+
+func directiveString(d *ast.DirectiveLit) string {
+	if d.Args == nil || len(d.Args.Args) == 0 {
+		return "@" + d.Name
+	}
+
+	args := make([]string, len(d.Args.Args))
+	for i, arg := range d.Args.Args {
+		var val interface{}
+		switch v := arg.Value.(type) {
+		case *ast.Arg_BasicLit:
+			val = v.BasicLit
+		case *ast.Arg_CompositeLit:
+			val = v.CompositeLit
+		}
+		args[i] = arg.Name.Name + ": " + valueString(val)
+	}
+	return "@" + d.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// valueString prints a value literal.
+func valueString(val interface{}) string {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		if v.Kind == token.Token_STRING && strings.HasPrefix(v.Value, `"""`) {
+			return strconv.Quote(StringValue(v.Value))
+		}
+		return v.Value
+	case *ast.CompositeLit:
+		switch w := v.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			return valueString(w.BasicLit)
+		case *ast.CompositeLit_ListLit:
+			return valueString(w.ListLit)
+		case *ast.CompositeLit_ObjLit:
+			return valueString(w.ObjLit)
+		}
+	case *ast.ListLit:
+		var vals []string
+		switch w := v.List.(type) {
+		case *ast.ListLit_BasicList:
+			for _, bval := range w.BasicList.Values {
+				vals = append(vals, valueString(bval))
+			}
+		case *ast.ListLit_CompositeList:
+			for _, cval := range w.CompositeList.Values {
+				vals = append(vals, valueString(cval))
+			}
+		}
+		return "[" + strings.Join(vals, ", ") + "]"
+	case *ast.ObjLit:
+		fields := make([]string, len(v.Fields))
+		for i, p := range v.Fields {
+			fields[i] = p.Key.Name + ": " + valueString(p.Val)
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	}
+	return ""
+}
+
+func fieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func inputValueType(a *ast.InputValue) interface{} {
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Descriptions: true,
+		Comments:     true,
+		Extension:    "graphql",
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "sdl" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		sdlOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range sdlOpts.Fields {
+			switch arg.Key.Name {
+			case "descriptions":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Descriptions = b
+			case "comments":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Comments = b
+			case "extension":
+				gOpts.Extension = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, `"`)
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts != nil {
+		if d, ok := opts["descriptions"]; ok {
+			gOpts.Descriptions, _ = d.(bool)
+		}
+		if c, ok := opts["comments"]; ok {
+			gOpts.Comments, _ = c.(bool)
+		}
+		if e, ok := opts["extension"].(string); ok {
+			gOpts.Extension = strings.Trim(e, `"`)
+		}
+	}
+	return
+}
@@ -0,0 +1,342 @@
+package sdl
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "testdata/test.gql", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, parser.ParseComments)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected sdl output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected sdl output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestFormat(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Src  string
+		SDL  string
+	}{
+		{
+			Name: "Descriptions",
+			Src: `"""
+	Echo represents
+	  an echo message.
+"""
+type Echo {
+	"""msg contains the message."""
+	msg: String!
+	"echoed tells whether the message has already been echoed, which is wider than a line."
+	echoed: Boolean
+}`,
+			SDL: `"""
+Echo represents
+  an echo message.
+"""
+type Echo {
+  "msg contains the message."
+  msg: String!
+  """
+  echoed tells whether the message has already been echoed, which is wider than a line.
+  """
+  echoed: Boolean
+}
+`,
+		},
+		{
+			Name: "WrapArgs",
+			Src: `type Query {
+	search(text: String, terms: [String], first: Int = 10, after: String, before: String): [String]
+	echo(
+		"text is echoed."
+		text: String!
+	): String
+}`,
+			SDL: `type Query {
+  search(
+    text: String
+    terms: [String]
+    first: Int = 10
+    after: String
+    before: String
+  ): [String]
+  echo(
+    "text is echoed."
+    text: String!
+  ): String
+}
+`,
+		},
+		{
+			Name: "WrapUnion",
+			Src:  `union SearchResult = Organization | Repository | User | PullRequest | Discussion | Issue`,
+			SDL: `union SearchResult =
+  | Organization
+  | Repository
+  | User
+  | PullRequest
+  | Discussion
+  | Issue
+`,
+		},
+		{
+			Name: "Extensions",
+			Src: `extend type User @key
+extend enum Role {
+	GUEST
+}
+type User { id: ID! }`,
+			SDL: `type User {
+  id: ID!
+}
+
+extend type User @key
+
+extend enum Role {
+  GUEST
+}
+`,
+		},
+		{
+			Name: "Comments",
+			Src: `# Users of the service.
+type User {
+	id: ID!
+}`,
+			SDL: `# Users of the service.
+type User {
+  id: ID!
+}
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(testCase.Src), parser.ParseComments)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			out := Format(doc, nil)
+			if string(out) != testCase.SDL {
+				subT.Errorf("expected:\n%s\nbut got:\n%s", testCase.SDL, out)
+			}
+		})
+	}
+}
+
+func TestFormat_Idempotent(t *testing.T) {
+	ex, err := ioutil.ReadFile(*exDocName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", bytes.NewReader(ex), parser.ParseComments)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if out := Format(doc, nil); !bytes.Equal(ex, out) {
+		t.Errorf("expected formatted output to be unchanged, but got:\n%s", out)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	doc := &ast.Document{
+		Directives: []*ast.DirectiveLit{
+			{
+				Name: "sdl",
+				Args: &ast.CallExpr{Args: []*ast.Arg{{
+					Name: &ast.Ident{Name: "options"},
+					Value: &ast.Arg_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+						Fields: []*ast.ObjLit_Pair{
+							{
+								Key: &ast.Ident{Name: "comments"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_BOOL, Value: "false"}}},
+							},
+						},
+					}}}},
+				}}},
+			},
+		},
+	}
+
+	gOpts, err := getOptions(doc, map[string]interface{}{"descriptions": false, "extension": "gql"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.Comments || gOpts.Descriptions || gOpts.Extension != "gql" {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+
+	if name := fileName(&ast.Document{Name: "dir/schema.gql"}, "graphql"); name != "schema.gql" {
+		t.Errorf("expected file name schema.gql, but got: %s", name)
+	}
+	if name := fileName(&ast.Document{Name: "schema"}, ".gql"); name != "schema.gql" {
+		t.Errorf("expected file name schema.gql, but got: %s", name)
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := new(Generator)
+	g.Reset()
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `type Query { user(id: ID!): User }
+
+"A user of the service."
+type User {
+	"""
+		name of the user.
+	"""
+	name: String!
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, nil)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Print(b.String())
+	// Output:
+	// type Query {
+	//   user(id: ID!): User
+	// }
+	//
+	// "A user of the service."
+	// type User {
+	//   "name of the user."
+	//   name: String!
+	// }
+}
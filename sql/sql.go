@@ -0,0 +1,513 @@
+// Package sql contains a SQL generator for GraphQL Documents. Object types
+// are mapped to CREATE TABLE statements, which scaffold a persistence layer
+// for a schema-first design.
+//
+package sql
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/gen/printer"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+// Supported SQL dialects
+const (
+	Postgres = "POSTGRES"
+	MySQL    = "MYSQL"
+	SQLite   = "SQLITE"
+)
+
+// Options contains the options for the SQL generator.
+type Options struct {
+	// Either "POSTGRES", "MYSQL" or "SQLITE" (default: POSTGRES)
+	Dialect string
+
+	// Copy descriptions to comments (default: true)
+	Descriptions bool
+}
+
+// Generator generates SQL DDL for a GraphQL schema.
+type Generator struct {
+	printer.Printer
+
+	opts *Options
+	log  *zap.Logger
+
+	// decls maps the types declared in a document to their declaration
+	decls map[string]*ast.TypeDecl
+
+	// keys maps the tables of a document to their primary key
+	keys map[string]*ast.Field
+
+	// created contains the tables which have already been generated
+	created map[string]bool
+
+	// deferred contains the foreign keys which must be added once
+	// the table they reference has been created.
+	//
+	deferred []foreignKey
+}
+
+// foreignKey is a column which references the primary key of another table.
+type foreignKey struct {
+	table, column string
+	ref           string
+}
+
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Printer.Reset()
+	g.Indent = "  "
+	g.decls = make(map[string]*ast.TypeDecl)
+	g.keys = make(map[string]*ast.Field)
+	g.created = make(map[string]bool)
+	g.deferred = g.deferred[:0]
+}
+
+// Generate generates a SQL file with a table for every object type in the
+// given document, except for the root operation types.
+//
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "sql",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("sql").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	g.opts, err = getOptions(doc, opts)
+	if err != nil {
+		return
+	}
+
+	var tables, enums []string
+	roots := rootTypes(doc)
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		name := ts.TypeSpec.Name.Name
+		g.decls[name] = d
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			if roots[name] {
+				break
+			}
+
+			tables = append(tables, name)
+			if pk := primaryKey(v.Object); pk != nil {
+				g.keys[name] = pk
+			}
+		case *ast.TypeSpec_Enum:
+			enums = append(enums, name)
+		}
+	}
+
+	// Postgres enums are types of their own, which tables depend on
+	g.log.Info("generating tables")
+	if g.opts.Dialect == Postgres {
+		for _, name := range enums {
+			g.generateEnumType(name)
+		}
+	}
+
+	for _, name := range g.tableOrder(tables) {
+		g.generateTable(name)
+	}
+
+	for _, fk := range g.deferred {
+		g.P()
+		g.P("ALTER TABLE ", g.quote(fk.table), " ADD FOREIGN KEY (", g.quote(fk.column), ") REFERENCES ", g.quote(snakeCase(fk.ref)), " (", g.quote(snakeCase(g.keys[fk.ref].Name.Name)), ");")
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	// Open file to write to
+	sqlFile, err := gCtx.Open(filepath.Join(filepath.Dir(doc.Name), fileName(doc)+".sql"))
+	if err != nil {
+		return
+	}
+	defer sqlFile.Close()
+
+	// Skip the leading blank line
+	if g.Len() > 0 {
+		g.Next(1)
+	}
+
+	_, err = g.WriteTo(sqlFile)
+	return
+}
+
+// fileName returns the name, without extension, of the generated file.
+func fileName(doc *ast.Document) string {
+	base := filepath.Base(doc.Name)
+	name := snakeCase(base[:len(base)-len(filepath.Ext(base))])
+	if name == "" {
+		return "schema"
+	}
+	return name
+}
+
+// rootTypes returns the root operation types of a document.
+func rootTypes(doc *ast.Document) map[string]bool {
+	if doc.Schema == nil {
+		return map[string]bool{"Query": true, "Mutation": true, "Subscription": true}
+	}
+
+	roots := make(map[string]bool, 3)
+	schema := doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Schema).Schema
+	for _, f := range schema.RootOps.List {
+		roots[f.Type.(*ast.Field_Ident).Ident.Name] = true
+	}
+	return roots
+}
+
+// primaryKey returns the first field of an object whose type is ID.
+func primaryKey(obj *ast.ObjectType) *ast.Field {
+	if obj.Fields == nil {
+		return nil
+	}
+
+	for _, f := range obj.Fields.List {
+		if name, isList := namedType(f); !isList && name == "ID" && f.Args == nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// tableOrder orders tables so that the tables they reference are created
+// first. Otherwise, tables keep the order they were declared in.
+//
+func (g *Generator) tableOrder(tables []string) []string {
+	order := make([]string, 0, len(tables))
+	visited := make(map[string]bool, len(tables))
+
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+
+		for _, f := range g.columnFields(name) {
+			ref, _ := namedType(f)
+			if _, ok := g.keys[ref]; ok {
+				visit(ref)
+			}
+		}
+		order = append(order, name)
+	}
+
+	for _, name := range tables {
+		visit(name)
+	}
+	return order
+}
+
+// columnFields returns the fields of an object which are stored as columns.
+// Fields with arguments are resolved rather than stored, and lists, along
+// with abstract types and objects without a primary key, can't be stored
+// in a single column.
+//
+func (g *Generator) columnFields(name string) (fields []*ast.Field) {
+	obj := g.decls[name].Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Object).Object
+	if obj.Fields == nil {
+		return
+	}
+
+	for _, f := range obj.Fields.List {
+		typ, isList := namedType(f)
+		if isList || f.Args != nil {
+			continue
+		}
+
+		d, ok := g.decls[typ]
+		if ok && d.Tok != token.Token_SCALAR && d.Tok != token.Token_ENUM {
+			if _, ok = g.keys[typ]; !ok {
+				continue
+			}
+		}
+
+		fields = append(fields, f)
+	}
+	return
+}
+
+func (g *Generator) generateEnumType(name string) {
+	d := g.decls[name]
+
+	g.P()
+	g.printComment(d.Doc)
+	g.P("CREATE TYPE ", g.quote(snakeCase(name)), " AS ENUM (", enumValues(d), ");")
+}
+
+func (g *Generator) generateTable(name string) {
+	d := g.decls[name]
+	table := snakeCase(name)
+
+	g.P()
+	g.printComment(d.Doc)
+	g.P("CREATE TABLE ", g.quote(table), " (")
+	g.In()
+
+	var fks []foreignKey
+	fields := g.columnFields(name)
+	for i, f := range fields {
+		column := snakeCase(f.Name.Name)
+		typ, _ := namedType(f)
+
+		var def string
+		if _, ok := g.keys[typ]; ok {
+			column += "_id"
+			def = g.columnType(g.keys[typ], column)
+
+			fk := foreignKey{table: table, column: column, ref: typ}
+			if g.created[typ] || typ == name || g.opts.Dialect == SQLite {
+				fks = append(fks, fk)
+			} else {
+				g.deferred = append(g.deferred, fk)
+			}
+		} else {
+			def = g.columnType(f, column)
+		}
+
+		if f == g.keys[name] {
+			def += " PRIMARY KEY"
+		} else if _, ok := f.Type.(*ast.Field_NonNull); ok {
+			def += " NOT NULL"
+		}
+
+		sep := ","
+		if i == len(fields)-1 && len(fks) == 0 {
+			sep = ""
+		}
+
+		g.printComment(f.Doc)
+		g.P(g.quote(column), " ", def, sep)
+	}
+
+	for i, fk := range fks {
+		sep := ","
+		if i == len(fks)-1 {
+			sep = ""
+		}
+
+		g.P("FOREIGN KEY (", g.quote(fk.column), ") REFERENCES ", g.quote(snakeCase(fk.ref)), " (", g.quote(snakeCase(g.keys[fk.ref].Name.Name)), ")", sep)
+	}
+
+	g.Out()
+	g.P(");")
+
+	g.created[name] = true
+}
+
+var columnTypes = map[string]map[string]string{
+	Postgres: {
+		"Int":     "INTEGER",
+		"Float":   "DOUBLE PRECISION",
+		"String":  "TEXT",
+		"Boolean": "BOOLEAN",
+		"ID":      "TEXT",
+	},
+	MySQL: {
+		"Int":     "INT",
+		"Float":   "DOUBLE",
+		"String":  "TEXT",
+		"Boolean": "BOOLEAN",
+		"ID":      "VARCHAR(255)",
+	},
+	SQLite: {
+		"Int":     "INTEGER",
+		"Float":   "REAL",
+		"String":  "TEXT",
+		"Boolean": "BOOLEAN",
+		"ID":      "TEXT",
+	},
+}
+
+// columnType returns the SQL type of a field. Custom scalars are stored as
+// text, since their representation is up to the server.
+//
+func (g *Generator) columnType(f *ast.Field, column string) string {
+	name, _ := namedType(f)
+	if t, ok := columnTypes[g.opts.Dialect][name]; ok {
+		return t
+	}
+
+	d, ok := g.decls[name]
+	if !ok || d.Tok != token.Token_ENUM {
+		return "TEXT"
+	}
+
+	switch g.opts.Dialect {
+	case Postgres:
+		return g.quote(snakeCase(name))
+	case MySQL:
+		return "ENUM(" + enumValues(d) + ")"
+	}
+	return "TEXT CHECK (" + g.quote(column) + " IN (" + enumValues(d) + "))"
+}
+
+// enumValues returns the values of an enum as a list of SQL strings.
+func enumValues(d *ast.TypeDecl) string {
+	enum := d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Enum).Enum
+	if enum.Values == nil {
+		return ""
+	}
+
+	vals := make([]string, len(enum.Values.List))
+	for i, v := range enum.Values.List {
+		vals[i] = "'" + v.Name.Name + "'"
+	}
+	return strings.Join(vals, ", ")
+}
+
+// namedType returns the name of a field's type and whether it's a list.
+func namedType(f *ast.Field) (string, bool) {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident.Name, false
+	case *ast.Field_NonNull:
+		if w, ok := v.NonNull.Type.(*ast.NonNull_Ident); ok {
+			return w.Ident.Name, false
+		}
+	}
+	return "", true
+}
+
+// quote quotes an identifier, so it may be a reserved word e.g. user.
+func (g *Generator) quote(ident string) string {
+	if g.opts.Dialect == MySQL {
+		return "`" + ident + "`"
+	}
+	return `"` + ident + `"`
+}
+
+func (g *Generator) printComment(doc *ast.DocGroup) {
+	if doc == nil || !g.opts.Descriptions {
+		return
+	}
+
+	text := strings.TrimSpace(doc.Text())
+	if text == "" {
+		return
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if len(line) == 0 {
+			g.P("--")
+			continue
+		}
+		g.P("-- ", line)
+	}
+}
+
+// snakeCase converts a name e.g. hasNextPage or userID to snake case
+// e.g. has_next_page or user_id.
+//
+func snakeCase(s string) string {
+	rs := []rune(s)
+
+	var b strings.Builder
+	for i, r := range rs {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteByte('_')
+			}
+			continue
+		}
+
+		if unicode.IsUpper(r) && i > 0 {
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.Trim(b.String(), "_")
+}
+
+func normDialect(s string) string {
+	return strings.ToUpper(strings.Trim(s, `"`))
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Dialect:      Postgres,
+		Descriptions: true,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "sql" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		sqlOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range sqlOpts.Fields {
+			switch arg.Key.Name {
+			case "dialect":
+				gOpts.Dialect = normDialect(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+			case "descriptions":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Descriptions = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts != nil {
+		if d, ok := opts["dialect"]; ok {
+			s, _ := d.(string)
+			gOpts.Dialect = normDialect(s)
+		}
+		if d, ok := opts["descriptions"]; ok {
+			gOpts.Descriptions, _ = d.(bool)
+		}
+	}
+
+	switch gOpts.Dialect {
+	case Postgres, MySQL, SQLite:
+		return
+	}
+	return gOpts, fmt.Errorf("unsupported dialect: %s", gOpts.Dialect)
+}
@@ -0,0 +1,378 @@
+package sql
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "test.sql", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected sql output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected sql output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestNames(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		Snake string
+	}{
+		{Name: "createdAt", Snake: "created_at"},
+		{Name: "userID", Snake: "user_id"},
+		{Name: "HTTPRequest", Snake: "http_request"},
+		{Name: "star-wars", Snake: "star_wars"},
+	}
+
+	for _, testCase := range testCases {
+		if name := snakeCase(testCase.Name); name != testCase.Snake {
+			t.Errorf("expected snake case %s for %s, but got: %s", testCase.Snake, testCase.Name, name)
+		}
+	}
+
+	if name := fileName(&ast.Document{Name: "dir/StarWars.graphql"}); name != "star_wars" {
+		t.Errorf("expected file name star_wars, but got: %s", name)
+	}
+}
+
+const dialectSrc = `type User {
+	id: ID!
+	score: Float
+	role: Role!
+	team: Team
+}
+
+type Team {
+	id: ID!
+	owner: User!
+}
+
+enum Role {
+	ADMIN
+	GUEST
+}`
+
+func TestDialects(t *testing.T) {
+	testCases := []struct {
+		Dialect string
+		SQL     string
+	}{
+		{
+			Dialect: "postgres",
+			SQL: `CREATE TYPE "role" AS ENUM ('ADMIN', 'GUEST');
+
+CREATE TABLE "team" (
+  "id" TEXT PRIMARY KEY,
+  "owner_id" TEXT NOT NULL
+);
+
+CREATE TABLE "user" (
+  "id" TEXT PRIMARY KEY,
+  "score" DOUBLE PRECISION,
+  "role" "role" NOT NULL,
+  "team_id" TEXT,
+  FOREIGN KEY ("team_id") REFERENCES "team" ("id")
+);
+
+ALTER TABLE "team" ADD FOREIGN KEY ("owner_id") REFERENCES "user" ("id");
+`,
+		},
+		{
+			Dialect: "mysql",
+			SQL: "CREATE TABLE `team` (\n" +
+				"  `id` VARCHAR(255) PRIMARY KEY,\n" +
+				"  `owner_id` VARCHAR(255) NOT NULL\n" +
+				");\n" +
+				"\n" +
+				"CREATE TABLE `user` (\n" +
+				"  `id` VARCHAR(255) PRIMARY KEY,\n" +
+				"  `score` DOUBLE,\n" +
+				"  `role` ENUM('ADMIN', 'GUEST') NOT NULL,\n" +
+				"  `team_id` VARCHAR(255),\n" +
+				"  FOREIGN KEY (`team_id`) REFERENCES `team` (`id`)\n" +
+				");\n" +
+				"\n" +
+				"ALTER TABLE `team` ADD FOREIGN KEY (`owner_id`) REFERENCES `user` (`id`);\n",
+		},
+		{
+			Dialect: "sqlite",
+			SQL: `CREATE TABLE "team" (
+  "id" TEXT PRIMARY KEY,
+  "owner_id" TEXT NOT NULL,
+  FOREIGN KEY ("owner_id") REFERENCES "user" ("id")
+);
+
+CREATE TABLE "user" (
+  "id" TEXT PRIMARY KEY,
+  "score" REAL,
+  "role" TEXT CHECK ("role" IN ('ADMIN', 'GUEST')) NOT NULL,
+  "team_id" TEXT,
+  FOREIGN KEY ("team_id") REFERENCES "team" ("id")
+);
+`,
+		},
+	}
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(dialectSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Dialect, func(subT *testing.T) {
+			var b bytes.Buffer
+			g := new(Generator)
+			ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+			err := g.Generate(ctx, doc, map[string]interface{}{"dialect": testCase.Dialect})
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			gen.CompareBytes(subT, []byte(testCase.SQL), b.Bytes())
+		})
+	}
+}
+
+func TestColumnFields(t *testing.T) {
+	gqlSrc := `type User {
+	id: ID!
+	friends: [User]
+	avatar(size: Int): String
+	node: Node
+	point: Point
+	user: User
+}
+
+interface Node {
+	id: ID!
+}
+
+type Point {
+	x: Float
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := &Generator{opts: &Options{Dialect: Postgres}}
+	g.Reset()
+	for _, d := range doc.Types {
+		ts := d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+		g.decls[ts.Name.Name] = d
+	}
+	g.keys["User"] = g.decls["User"].Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Type.(*ast.TypeSpec_Object).Object.Fields.List[0]
+
+	var names []string
+	for _, f := range g.columnFields("User") {
+		names = append(names, f.Name.Name)
+	}
+
+	if strings.Join(names, ",") != "id,user" {
+		t.Errorf("expected only id and user to be stored, but got: %v", names)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	doc := &ast.Document{
+		Directives: []*ast.DirectiveLit{
+			{
+				Name: "sql",
+				Args: &ast.CallExpr{Args: []*ast.Arg{{
+					Name: &ast.Ident{Name: "options"},
+					Value: &ast.Arg_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+						Fields: []*ast.ObjLit_Pair{
+							{
+								Key: &ast.Ident{Name: "dialect"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_IDENT, Value: "MYSQL"}}},
+							},
+							{
+								Key: &ast.Ident{Name: "descriptions"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_BOOL, Value: "false"}}},
+							},
+						},
+					}}}},
+				}}},
+			},
+		},
+	}
+
+	gOpts, err := getOptions(doc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.Dialect != MySQL || gOpts.Descriptions {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+
+	gOpts, err = getOptions(doc, map[string]interface{}{"dialect": "sqlite"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gOpts.Dialect != SQLite {
+		t.Errorf("expected cli dialect to take precedence, but got: %s", gOpts.Dialect)
+	}
+
+	_, err = getOptions(&ast.Document{}, map[string]interface{}{"dialect": "oracle"})
+	if err == nil {
+		t.Error("expected unsupported dialect to fail")
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := new(Generator)
+	g.Reset()
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `"A user of the service."
+type User {
+	id: ID!
+	name: String!
+	nickname: String
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, map[string]interface{}{"dialect": "sqlite"})
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Print(b.String())
+	// Output:
+	// -- A user of the service.
+	// CREATE TABLE "user" (
+	//   "id" TEXT PRIMARY KEY,
+	//   "name" TEXT NOT NULL,
+	//   "nickname" TEXT
+	// );
+}
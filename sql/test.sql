@@ -0,0 +1,43 @@
+-- Role represents the permissions of a user.
+CREATE TYPE "role" AS ENUM ('ADMIN', 'MEMBER', 'GUEST');
+
+-- Point has no id, so it can't be referenced.
+CREATE TABLE "point" (
+  "x" DOUBLE PRECISION NOT NULL,
+  "y" DOUBLE PRECISION NOT NULL
+);
+
+-- Team is a group of users.
+CREATE TABLE "team" (
+  "id" TEXT PRIMARY KEY,
+  "name" TEXT NOT NULL,
+  "lead_id" TEXT
+);
+
+-- User is a user of the service.
+CREATE TABLE "user" (
+  -- id uniquely identifies the user.
+  "id" TEXT PRIMARY KEY,
+  "name" TEXT NOT NULL,
+  "age" INTEGER,
+  "rating" DOUBLE PRECISION,
+  "active" BOOLEAN NOT NULL,
+  "role" "role" NOT NULL,
+  "created_at" TEXT,
+  -- team is the team the user belongs to.
+  "team_id" TEXT,
+  -- manager is the user's manager.
+  "manager_id" TEXT,
+  FOREIGN KEY ("team_id") REFERENCES "team" ("id"),
+  FOREIGN KEY ("manager_id") REFERENCES "user" ("id")
+);
+
+-- Post is something a user wrote.
+CREATE TABLE "post" (
+  "id" TEXT PRIMARY KEY,
+  "author_id" TEXT NOT NULL,
+  "body" TEXT,
+  FOREIGN KEY ("author_id") REFERENCES "user" ("id")
+);
+
+ALTER TABLE "team" ADD FOREIGN KEY ("lead_id") REFERENCES "user" ("id");
@@ -0,0 +1,213 @@
+import Foundation
+
+/// Version represents an API version.
+public typealias Version = String
+
+/// Echo represents an echo message.
+public struct Echo: Codable, Hashable {
+    /// msg contains the provided message.
+    public var msg: String
+    /// reply is the echo of this echo.
+    @Indirect public var reply: Echo?
+
+    public init(msg: String, reply: Echo? = nil) {
+        self.msg = msg
+        self.reply = reply
+    }
+}
+
+/// Query represents valid queries.
+public struct Query: Codable, Hashable {
+    /// version returns the current API version.
+    public var version: Version?
+    /// echo echos a message.
+    public var echo: Echo?
+    /// search performs a search over some data set.
+    public var search: Result?
+
+    public init(version: Version? = nil, echo: Echo? = nil, search: Result? = nil) {
+        self.version = version
+        self.echo = echo
+        self.search = search
+    }
+}
+
+/// Result represents a search result.
+public struct Result: Codable, Hashable {
+    /// total yields the total number of search results.
+    public var total: Int?
+    /// edges contains the search results.
+    public var edges: [Node?]?
+    /// hasNextPage tells if there are more search results.
+    public var hasNextPage: Bool?
+
+    public init(total: Int? = nil, edges: [Node?]? = nil, hasNextPage: Bool? = nil) {
+        self.total = total
+        self.edges = edges
+        self.hasNextPage = hasNextPage
+    }
+}
+
+/// Connection represents a set of edges, which are meant to be paginated.
+public indirect enum Connection: Codable, Hashable {
+    case result(Result)
+
+    private enum CodingKeys: String, CodingKey {
+        case __typename
+    }
+
+    /// total returns the total number of edges.
+    public var total: Int? {
+        switch self {
+        case .result(let value):
+            return value.total
+        }
+    }
+
+    /// edges contains the current page of edges.
+    public var edges: [Node?]? {
+        switch self {
+        case .result(let value):
+            return value.edges
+        }
+    }
+
+    /// hasNextPage tells if there exists more edges.
+    public var hasNextPage: Bool? {
+        switch self {
+        case .result(let value):
+            return value.hasNextPage
+        }
+    }
+
+    public init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        let typename = try container.decode(String.self, forKey: .__typename)
+        switch typename {
+        case "Result":
+            self = .result(try Result(from: decoder))
+        default:
+            throw DecodingError.dataCorruptedError(forKey: .__typename, in: container, debugDescription: "unknown Connection type: \(typename)")
+        }
+    }
+
+    public func encode(to encoder: Encoder) throws {
+        var container = encoder.container(keyedBy: CodingKeys.self)
+        switch self {
+        case .result(let value):
+            try container.encode("Result", forKey: .__typename)
+            try value.encode(to: encoder)
+        }
+    }
+}
+
+/// Node represents a node.
+public enum Node: Codable, Hashable {
+    private enum CodingKeys: String, CodingKey {
+        case __typename
+    }
+
+    public init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        let typename = try container.decode(String.self, forKey: .__typename)
+        switch typename {
+        default:
+            throw DecodingError.dataCorruptedError(forKey: .__typename, in: container, debugDescription: "unknown Node type: \(typename)")
+        }
+    }
+
+    public func encode(to encoder: Encoder) throws {
+        switch self {
+        }
+    }
+}
+
+/// SearchResult is a test union type
+public indirect enum SearchResult: Codable, Hashable {
+    case echo(Echo)
+    case result(Result)
+
+    private enum CodingKeys: String, CodingKey {
+        case __typename
+    }
+
+    public init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        let typename = try container.decode(String.self, forKey: .__typename)
+        switch typename {
+        case "Echo":
+            self = .echo(try Echo(from: decoder))
+        case "Result":
+            self = .result(try Result(from: decoder))
+        default:
+            throw DecodingError.dataCorruptedError(forKey: .__typename, in: container, debugDescription: "unknown SearchResult type: \(typename)")
+        }
+    }
+
+    public func encode(to encoder: Encoder) throws {
+        var container = encoder.container(keyedBy: CodingKeys.self)
+        switch self {
+        case .echo(let value):
+            try container.encode("Echo", forKey: .__typename)
+            try value.encode(to: encoder)
+        case .result(let value):
+            try container.encode("Result", forKey: .__typename)
+            try value.encode(to: encoder)
+        }
+    }
+}
+
+/// Direction represents a cardinal direction.
+public enum Direction: String, Codable, Hashable, CaseIterable {
+    /// EnumValue description
+    case north = "NORTH"
+    case east = "EAST"
+    case south = "SOUTH"
+    /// EnumValue Description and Directives.
+    case west = "WEST"
+}
+
+/// Point represents a 2-D geo point.
+public struct Point: Codable, Hashable {
+    public var x: Double
+    public var y: Double
+    public var label: String?
+    public var visible: Bool?
+    public var heading: Direction?
+    public var weights: [Double?]?
+
+    public init(x: Double, y: Double, label: String? = "origin", visible: Bool? = true, heading: Direction? = .north, weights: [Double?]? = [1.0, 2.5]) {
+        self.x = x
+        self.y = y
+        self.label = label
+        self.visible = visible
+        self.heading = heading
+        self.weights = weights
+    }
+}
+
+/// Indirect boxes a value to break up recursive structs.
+@propertyWrapper
+public final class Indirect<Value: Codable & Hashable>: Codable, Hashable {
+    public var wrappedValue: Value
+
+    public init(wrappedValue: Value) {
+        self.wrappedValue = wrappedValue
+    }
+
+    public init(from decoder: Decoder) throws {
+        wrappedValue = try Value(from: decoder)
+    }
+
+    public func encode(to encoder: Encoder) throws {
+        try wrappedValue.encode(to: encoder)
+    }
+
+    public static func == (lhs: Indirect, rhs: Indirect) -> Bool {
+        return lhs.wrappedValue == rhs.wrappedValue
+    }
+
+    public func hash(into hasher: inout Hasher) {
+        hasher.combine(wrappedValue)
+    }
+}
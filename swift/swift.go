@@ -0,0 +1,840 @@
+// Package swift contains a Swift generator for GraphQL Documents.
+// The generated code consists of Codable structs and enums which
+// can be used to consume GraphQL responses on Apple platforms.
+//
+package swift
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/gen/printer"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+// Options contains the options for the Swift generator.
+type Options struct {
+	// Copy descriptions to doc comments (default: true)
+	Descriptions bool
+}
+
+// Generator generates Swift code for a GraphQL schema.
+type Generator struct {
+	printer.Printer
+
+	log *zap.Logger
+
+	// kinds maps a type name to its declaration token
+	kinds map[string]token.Token
+
+	// impls maps an interface to the objects implementing it
+	impls map[string][]string
+
+	// boxed contains the fields, by coordinate, which must be
+	// boxed to break up recursive structs
+	boxed map[string]bool
+}
+
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Printer.Reset()
+	g.Indent = "    "
+	g.kinds = make(map[string]token.Token)
+	g.impls = make(map[string][]string)
+	g.boxed = make(map[string]bool)
+}
+
+// Generate generates Swift code for the given document.
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "swift",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("swift").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	gOpts, oerr := getOptions(doc, opts)
+	if oerr != nil {
+		return oerr
+	}
+
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		g.kinds[ts.TypeSpec.Name.Name] = d.Tok
+
+		if obj, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Object); ok {
+			for _, inter := range obj.Object.Interfaces {
+				g.impls[inter.Name] = append(g.impls[inter.Name], ts.TypeSpec.Name.Name)
+			}
+		}
+	}
+	g.findRecursiveFields(doc)
+
+	g.P("import Foundation")
+
+	// Generate types
+	g.log.Info("generating types")
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		name := ts.TypeSpec.Name.Name
+		descr := d.Doc
+		if !gOpts.Descriptions {
+			descr = nil
+		}
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar:
+			g.P()
+			g.printDoc(descr)
+			g.P("public typealias ", name, " = String")
+		case *ast.TypeSpec_Object:
+			var props []property
+			if v.Object.Fields != nil {
+				for _, f := range v.Object.Fields.List {
+					props = append(props, property{
+						name:  f.Name.Name,
+						typ:   fieldType(f),
+						doc:   f.Doc,
+						boxed: g.boxed[name+"."+f.Name.Name],
+					})
+				}
+			}
+
+			g.P()
+			g.printDoc(descr)
+			g.generateStruct(name, props, gOpts.Descriptions)
+		case *ast.TypeSpec_Input:
+			var props []property
+			if v.Input.Fields != nil {
+				for _, f := range v.Input.Fields.List {
+					props = append(props, property{
+						name:  f.Name.Name,
+						typ:   inputValueType(f),
+						doc:   f.Doc,
+						def:   f.Default,
+						boxed: g.boxed[name+"."+f.Name.Name],
+					})
+				}
+			}
+
+			g.P()
+			g.printDoc(descr)
+			g.generateStruct(name, props, gOpts.Descriptions)
+		case *ast.TypeSpec_Interface:
+			var fields []*ast.Field
+			if v.Interface.Fields != nil {
+				fields = v.Interface.Fields.List
+			}
+
+			g.P()
+			g.printDoc(descr)
+			g.generatePolymorphic(name, g.impls[name], fields, gOpts.Descriptions)
+		case *ast.TypeSpec_Union:
+			var members []string
+			for _, mem := range v.Union.Members {
+				members = append(members, mem.Name)
+			}
+
+			g.P()
+			g.printDoc(descr)
+			g.generatePolymorphic(name, members, nil, gOpts.Descriptions)
+		case *ast.TypeSpec_Enum:
+			g.P()
+			g.printDoc(descr)
+			g.generateEnum(name, gOpts.Descriptions, v.Enum)
+		}
+	}
+
+	if len(g.boxed) > 0 {
+		g.P()
+		g.generateIndirect()
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	// Open file to write to
+	swiftFile, err := gCtx.Open(filepath.Join(filepath.Dir(doc.Name), fileName(doc)+".swift"))
+	if err != nil {
+		return
+	}
+	defer swiftFile.Close()
+
+	// Write generated output
+	_, err = g.WriteTo(swiftFile)
+	return
+}
+
+// fileName returns the name, without extension, of the generated file
+// which follows the Swift convention of PascalCase file names.
+//
+func fileName(doc *ast.Document) string {
+	base := filepath.Base(doc.Name)
+	name := pascalCase(base[:len(base)-len(filepath.Ext(base))])
+	if name == "" {
+		return "Schema"
+	}
+	return name
+}
+
+// findRecursiveFields finds the struct fields which would make a struct
+// contain itself. Swift structs can't be recursive so these fields are
+// boxed. Lists and enums, which are indirect, already break up any cycle.
+//
+func (g *Generator) findRecursiveFields(doc *ast.Document) {
+	edges := make(map[string][]string)
+	var coords [][2]string
+	addEdge := func(parent, field string, typ interface{}) {
+		if isList(typ) {
+			return
+		}
+
+		switch child := namedType(typ); g.kinds[child] {
+		case token.Token_TYPE, token.Token_INPUT:
+			edges[parent] = append(edges[parent], child)
+			coords = append(coords, [2]string{parent + "." + field, child})
+		}
+	}
+
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			if v.Object.Fields == nil {
+				break
+			}
+			for _, f := range v.Object.Fields.List {
+				addEdge(name, f.Name.Name, fieldType(f))
+			}
+		case *ast.TypeSpec_Input:
+			if v.Input.Fields == nil {
+				break
+			}
+			for _, f := range v.Input.Fields.List {
+				addEdge(name, f.Name.Name, inputValueType(f))
+			}
+		}
+	}
+
+	for _, c := range coords {
+		parent := c[0][:strings.IndexByte(c[0], '.')]
+		if reaches(edges, c[1], parent, make(map[string]bool)) {
+			g.boxed[c[0]] = true
+		}
+	}
+}
+
+// reaches reports whether there's a path from one type to another.
+func reaches(edges map[string][]string, from, to string, seen map[string]bool) bool {
+	if from == to {
+		return true
+	}
+	if seen[from] {
+		return false
+	}
+	seen[from] = true
+
+	for _, next := range edges[from] {
+		if reaches(edges, next, to, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// property is a stored property of a generated struct.
+type property struct {
+	name  string
+	typ   interface{}
+	doc   *ast.DocGroup
+	def   interface{}
+	boxed bool
+}
+
+// generateStruct generates a Codable struct along with a public memberwise
+// initializer, since the synthesized one is internal.
+//
+func (g *Generator) generateStruct(name string, props []property, descr bool) {
+	g.P("public struct ", name, ": Codable, Hashable {")
+	g.In()
+
+	for _, p := range props {
+		if descr {
+			g.printDoc(p.doc)
+		}
+
+		wrapper := ""
+		if p.boxed {
+			wrapper = "@Indirect "
+		}
+		g.P(wrapper, "public var ", identifier(p.name), ": ", swiftType(p.typ))
+	}
+	if len(props) > 0 {
+		g.P()
+	}
+
+	g.WriteIndent()
+	g.WriteString("public init(")
+	for i, p := range props {
+		if i > 0 {
+			g.WriteString(", ")
+		}
+
+		g.WriteString(identifier(p.name))
+		g.WriteString(": ")
+		g.WriteString(swiftType(p.typ))
+		g.printDefault(p.typ, p.def)
+	}
+	g.WriteString(") {")
+	if len(props) == 0 {
+		g.WriteString("}\n")
+	} else {
+		g.WriteByte('\n')
+		g.In()
+		for _, p := range props {
+			g.P("self.", strings.Trim(identifier(p.name), "`"), " = ", identifier(p.name))
+		}
+		g.Out()
+		g.P("}")
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+// generatePolymorphic generates an enum for an interface or union with a case
+// for every possible type, which is decoded according to __typename. The
+// fields of an interface are exposed as computed properties.
+//
+func (g *Generator) generatePolymorphic(name string, members []string, fields []*ast.Field, descr bool) {
+	indirect := ""
+	if len(members) > 0 {
+		indirect = "indirect "
+	}
+	g.P("public ", indirect, "enum ", name, ": Codable, Hashable {")
+	g.In()
+
+	for _, mem := range members {
+		g.P("case ", memberCase(mem), "(", mem, ")")
+	}
+	if len(members) > 0 {
+		g.P()
+	}
+
+	g.P("private enum CodingKeys: String, CodingKey {")
+	g.In()
+	g.P("case __typename")
+	g.Out()
+	g.P("}")
+
+	if len(members) > 0 {
+		for _, f := range fields {
+			g.P()
+			if descr {
+				g.printDoc(f.Doc)
+			}
+
+			g.P("public var ", identifier(f.Name.Name), ": ", swiftType(fieldType(f)), " {")
+			g.In()
+			g.P("switch self {")
+			for _, mem := range members {
+				g.P("case .", memberCase(mem), "(let value):")
+				g.In()
+				g.P("return value.", strings.Trim(identifier(f.Name.Name), "`"))
+				g.Out()
+			}
+			g.P("}")
+			g.Out()
+			g.P("}")
+		}
+	}
+
+	g.P()
+	g.P("public init(from decoder: Decoder) throws {")
+	g.In()
+	g.P("let container = try decoder.container(keyedBy: CodingKeys.self)")
+	g.P("let typename = try container.decode(String.self, forKey: .__typename)")
+	g.P("switch typename {")
+	for _, mem := range members {
+		g.P("case ", quote(mem), ":")
+		g.In()
+		g.P("self = .", memberCase(mem), "(try ", mem, "(from: decoder))")
+		g.Out()
+	}
+	g.P("default:")
+	g.In()
+	g.P(`throw DecodingError.dataCorruptedError(forKey: .__typename, in: container, debugDescription: "unknown `, name, ` type: \(typename)")`)
+	g.Out()
+	g.P("}")
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("public func encode(to encoder: Encoder) throws {")
+	g.In()
+	if len(members) > 0 {
+		g.P("var container = encoder.container(keyedBy: CodingKeys.self)")
+	}
+	g.P("switch self {")
+	for _, mem := range members {
+		g.P("case .", memberCase(mem), "(let value):")
+		g.In()
+		g.P("try container.encode(", quote(mem), ", forKey: .__typename)")
+		g.P("try value.encode(to: encoder)")
+		g.Out()
+	}
+	g.P("}")
+	g.Out()
+	g.P("}")
+
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateEnum(name string, descr bool, enum *ast.EnumType) {
+	g.P("public enum ", name, ": String, Codable, Hashable, CaseIterable {")
+	g.In()
+
+	if enum.Values != nil {
+		for _, v := range enum.Values.List {
+			if descr {
+				g.printDoc(v.Doc)
+			}
+			g.P("case ", caseName(v.Name.Name), " = ", quote(v.Name.Name))
+		}
+	}
+
+	g.Out()
+	g.P("}")
+}
+
+// generateIndirect generates the property wrapper used to box recursive fields.
+func (g *Generator) generateIndirect() {
+	g.P("/// Indirect boxes a value to break up recursive structs.")
+	g.P("@propertyWrapper")
+	g.P("public final class Indirect<Value: Codable & Hashable>: Codable, Hashable {")
+	g.In()
+	g.P("public var wrappedValue: Value")
+	g.P()
+	g.P("public init(wrappedValue: Value) {")
+	g.In()
+	g.P("self.wrappedValue = wrappedValue")
+	g.Out()
+	g.P("}")
+	g.P()
+	g.P("public init(from decoder: Decoder) throws {")
+	g.In()
+	g.P("wrappedValue = try Value(from: decoder)")
+	g.Out()
+	g.P("}")
+	g.P()
+	g.P("public func encode(to encoder: Encoder) throws {")
+	g.In()
+	g.P("try wrappedValue.encode(to: encoder)")
+	g.Out()
+	g.P("}")
+	g.P()
+	g.P("public static func == (lhs: Indirect, rhs: Indirect) -> Bool {")
+	g.In()
+	g.P("return lhs.wrappedValue == rhs.wrappedValue")
+	g.Out()
+	g.P("}")
+	g.P()
+	g.P("public func hash(into hasher: inout Hasher) {")
+	g.In()
+	g.P("hasher.combine(wrappedValue)")
+	g.Out()
+	g.P("}")
+	g.Out()
+	g.P("}")
+}
+
+func fieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func inputValueType(a *ast.InputValue) interface{} {
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+var swiftTypes = map[string]string{
+	"Int":     "Int",
+	"Float":   "Double",
+	"String":  "String",
+	"Boolean": "Bool",
+	"ID":      "String",
+}
+
+// swiftType returns the Swift type of a GraphQL type. Nullable GraphQL
+// types map to optionals.
+//
+func swiftType(typ interface{}) string {
+	if nn, ok := typ.(*ast.NonNull); ok {
+		switch w := nn.Type.(type) {
+		case *ast.NonNull_Ident:
+			return baseType(w.Ident)
+		case *ast.NonNull_List:
+			return baseType(w.List)
+		}
+	}
+	return baseType(typ) + "?"
+}
+
+// baseType returns the Swift type of a GraphQL type without any optionality.
+func baseType(typ interface{}) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		if t, ok := swiftTypes[v.Name]; ok {
+			return t
+		}
+		return v.Name
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			typ = w.Ident
+		case *ast.List_List:
+			typ = w.List
+		case *ast.List_NonNull:
+			typ = w.NonNull
+		}
+		return "[" + swiftType(typ) + "]"
+	}
+	return "Any"
+}
+
+// isList reports whether a type is a list, ignoring nullability.
+func isList(typ interface{}) bool {
+	switch v := typ.(type) {
+	case *ast.List:
+		return true
+	case *ast.NonNull:
+		_, ok := v.Type.(*ast.NonNull_List)
+		return ok
+	}
+	return false
+}
+
+// namedType returns the name of the underlying named type.
+func namedType(typ interface{}) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		return v.Name
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			return w.Ident.Name
+		case *ast.List_List:
+			return namedType(w.List)
+		case *ast.List_NonNull:
+			return namedType(w.NonNull)
+		}
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return w.Ident.Name
+		case *ast.NonNull_List:
+			return namedType(w.List)
+		}
+	}
+	return ""
+}
+
+// printDefault prints the default value of an initializer parameter.
+// Optionals without a default value default to nil.
+//
+func (g *Generator) printDefault(typ, def interface{}) {
+	var val interface{}
+	switch v := def.(type) {
+	case *ast.InputValue_BasicLit:
+		val = v.BasicLit
+	case *ast.InputValue_CompositeLit:
+		val = v.CompositeLit
+		if _, ok := v.CompositeLit.Value.(*ast.CompositeLit_ObjLit); ok {
+			// Input objects have no literal syntax in Swift
+			val = nil
+		}
+	}
+
+	if val == nil {
+		if _, ok := typ.(*ast.NonNull); !ok {
+			g.WriteString(" = nil")
+		}
+		return
+	}
+
+	g.WriteString(" = ")
+	g.printVal(namedType(typ), val)
+}
+
+// printVal prints a value of the given named type
+func (g *Generator) printVal(typ string, val interface{}) {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		switch v.Kind {
+		case token.Token_STRING:
+			s, err := strconv.Unquote(v.Value)
+			if err != nil {
+				s = strings.Trim(v.Value, `"`)
+			}
+			g.WriteString(quote(s))
+		case token.Token_IDENT:
+			g.WriteByte('.')
+			g.WriteString(caseName(v.Value))
+		case token.Token_INT:
+			g.WriteString(v.Value)
+			if typ == "Float" {
+				g.WriteString(".0")
+			}
+		case token.Token_NULL:
+			g.WriteString("nil")
+		default:
+			g.WriteString(v.Value)
+		}
+	case *ast.ListLit:
+		g.printList(typ, v)
+	case *ast.ObjLit:
+		g.WriteString("nil")
+	case *ast.CompositeLit:
+		switch w := v.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			g.printVal(typ, w.BasicLit)
+		case *ast.CompositeLit_ListLit:
+			g.printVal(typ, w.ListLit)
+		case *ast.CompositeLit_ObjLit:
+			g.printVal(typ, w.ObjLit)
+		}
+	}
+}
+
+func (g *Generator) printList(typ string, v *ast.ListLit) {
+	g.WriteByte('[')
+
+	var vals []interface{}
+	switch w := v.List.(type) {
+	case *ast.ListLit_BasicList:
+		for _, bval := range w.BasicList.Values {
+			vals = append(vals, bval)
+		}
+	case *ast.ListLit_CompositeList:
+		for _, cval := range w.CompositeList.Values {
+			vals = append(vals, cval)
+		}
+	}
+
+	vLen := len(vals) - 1
+	for i, iv := range vals {
+		g.printVal(typ, iv)
+		if i != vLen {
+			g.WriteByte(',')
+			g.WriteByte(' ')
+		}
+	}
+
+	g.WriteByte(']')
+}
+
+// printDoc prints a description as a Swift doc comment.
+func (g *Generator) printDoc(doc *ast.DocGroup) {
+	if doc == nil {
+		return
+	}
+
+	text := strings.TrimSpace(doc.Text())
+	if text == "" {
+		return
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if len(line) == 0 {
+			g.P("///")
+			continue
+		}
+		g.P("/// ", line)
+	}
+}
+
+// quote returns s as a double quoted Swift string literal.
+func quote(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+var keywords = map[string]bool{
+	"associatedtype": true, "class": true, "deinit": true, "enum": true, "extension": true,
+	"fileprivate": true, "func": true, "import": true, "init": true, "inout": true,
+	"internal": true, "let": true, "open": true, "operator": true, "private": true,
+	"protocol": true, "public": true, "rethrows": true, "static": true, "struct": true,
+	"subscript": true, "typealias": true, "var": true, "break": true, "case": true,
+	"continue": true, "default": true, "defer": true, "do": true, "else": true,
+	"fallthrough": true, "for": true, "guard": true, "if": true, "in": true,
+	"repeat": true, "return": true, "switch": true, "where": true, "while": true,
+	"as": true, "Any": true, "catch": true, "false": true, "is": true, "nil": true,
+	"super": true, "self": true, "Self": true, "throw": true, "throws": true,
+	"true": true, "try": true,
+}
+
+// identifier returns a valid Swift identifier for a GraphQL name.
+func identifier(name string) string {
+	if keywords[name] {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// caseName converts an enum value e.g. NOT_FOUND to a Swift case name e.g. notFound.
+func caseName(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		if part == strings.ToUpper(part) {
+			part = strings.ToLower(part)
+		}
+
+		if b.Len() == 0 {
+			b.WriteString(strings.ToLower(part[:1]) + part[1:])
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	if b.Len() == 0 {
+		return identifier(s)
+	}
+	return identifier(b.String())
+}
+
+// memberCase returns the enum case name for a member type of an interface or union.
+func memberCase(name string) string {
+	return identifier(strings.ToLower(name[:1]) + name[1:])
+}
+
+// pascalCase converts a file name e.g. star-wars to a PascalCase name e.g. StarWars.
+func pascalCase(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Descriptions: true,
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "swift" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		swiftOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range swiftOpts.Fields {
+			switch arg.Key.Name {
+			case "descriptions":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Descriptions = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts == nil {
+		return
+	}
+	if d, ok := opts["descriptions"]; ok {
+		gOpts.Descriptions, _ = d.(bool)
+	}
+
+	return
+}
@@ -0,0 +1,458 @@
+package swift
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "Test.swift", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected swift output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected swift output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestNames(t *testing.T) {
+	testCases := []struct {
+		Doc  string
+		File string
+	}{
+		{Doc: "test.gql", File: "Test"},
+		{Doc: "star-wars.graphql", File: "StarWars"},
+		{Doc: "_.gql", File: "Schema"},
+	}
+
+	for _, testCase := range testCases {
+		if name := fileName(&ast.Document{Name: testCase.Doc}); name != testCase.File {
+			t.Errorf("expected file name %s for %s, but got: %s", testCase.File, testCase.Doc, name)
+		}
+	}
+
+	cases := map[string]string{
+		"NORTH":       "north",
+		"NOT_FOUND":   "notFound",
+		"inProgress":  "inProgress",
+		"DEFAULT":     "`default`",
+		"_":           "_",
+		"HTTP_2_ONLY": "http2Only",
+	}
+	for value, ex := range cases {
+		if name := caseName(value); name != ex {
+			t.Errorf("expected case name %s for %s, but got: %s", ex, value, name)
+		}
+	}
+
+	if id := identifier("self"); id != "`self`" {
+		t.Errorf("expected keyword to be escaped, but got: %s", id)
+	}
+}
+
+func TestSwiftType(t *testing.T) {
+	testCases := []struct {
+		Type  interface{}
+		Swift string
+	}{
+		{Type: &ast.Ident{Name: "Int"}, Swift: "Int?"},
+		{Type: &ast.Ident{Name: "Boolean"}, Swift: "Bool?"},
+		{Type: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "User"}}}, Swift: "User"},
+		{
+			Type:  &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "ID"}}},
+			Swift: "[String?]?",
+		},
+		{
+			Type:  &ast.NonNull{Type: &ast.NonNull_List{List: &ast.List{Type: &ast.List_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Float"}}}}}}},
+			Swift: "[Double]",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if typ := swiftType(testCase.Type); typ != testCase.Swift {
+			t.Errorf("expected: %s, but got: %s", testCase.Swift, typ)
+		}
+	}
+}
+
+func TestRecursiveFields(t *testing.T) {
+	src := `type A {
+	b: B
+	as: [A]
+}
+
+type B {
+	a: A!
+	c: C
+}
+
+type C {
+	u: U
+}
+
+union U = A`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(src), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := &Generator{}
+	g.Reset()
+	for _, d := range doc.Types {
+		g.kinds[d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Name.Name] = d.Tok
+	}
+	g.findRecursiveFields(doc)
+
+	ex := map[string]bool{"A.b": true, "B.a": true}
+	if !reflect.DeepEqual(g.boxed, ex) {
+		t.Errorf("expected: %v, but got: %v", ex, g.boxed)
+	}
+}
+
+func TestStruct(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+
+	props := []property{
+		{
+			name: "id",
+			typ:  &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}},
+		},
+		{
+			name:  "default",
+			typ:   &ast.Ident{Name: "Test"},
+			boxed: true,
+		},
+		{
+			name: "dirs",
+			typ:  &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Direction"}}},
+			def: &ast.InputValue_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ListLit{
+				ListLit: &ast.ListLit{
+					List: &ast.ListLit_BasicList{BasicList: &ast.ListLit_Basic{Values: []*ast.BasicLit{
+						{Kind: token.Token_IDENT, Value: "NORTH"},
+						{Kind: token.Token_IDENT, Value: "SOUTH_WEST"},
+					}}},
+				},
+			}}},
+		},
+	}
+
+	g.generateStruct("Test", props, false)
+
+	ex := []byte("public struct Test: Codable, Hashable {\n" +
+		"    public var id: String\n" +
+		"    @Indirect public var `default`: Test?\n" +
+		"    public var dirs: [Direction?]?\n" +
+		"\n" +
+		"    public init(id: String, `default`: Test? = nil, dirs: [Direction?]? = [.north, .southWest]) {\n" +
+		"        self.id = id\n" +
+		"        self.default = `default`\n" +
+		"        self.dirs = dirs\n" +
+		"    }\n" +
+		"}\n")
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestEmptyStruct(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+
+	g.generateStruct("Test", nil, false)
+
+	ex := []byte(`public struct Test: Codable, Hashable {
+    public init() {}
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestEnum(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	enum := &ast.EnumType{
+		Values: &ast.FieldList{
+			List: []*ast.Field{
+				{Name: &ast.Ident{Name: "A"}},
+				{Name: &ast.Ident{Name: "NOT_FOUND"}},
+			},
+		},
+	}
+
+	g.generateEnum("Test", false, enum)
+
+	ex := []byte(`public enum Test: String, Codable, Hashable, CaseIterable {
+    case a = "A"
+    case notFound = "NOT_FOUND"
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestPolymorphic(t *testing.T) {
+	g := &Generator{}
+	g.Reset()
+
+	fields := []*ast.Field{
+		{
+			Name: &ast.Ident{Name: "id"},
+			Type: &ast.Field_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "ID"}}}},
+		},
+	}
+
+	g.generatePolymorphic("Node", []string{"User"}, fields, false)
+
+	ex := []byte(`public indirect enum Node: Codable, Hashable {
+    case user(User)
+
+    private enum CodingKeys: String, CodingKey {
+        case __typename
+    }
+
+    public var id: String {
+        switch self {
+        case .user(let value):
+            return value.id
+        }
+    }
+
+    public init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        let typename = try container.decode(String.self, forKey: .__typename)
+        switch typename {
+        case "User":
+            self = .user(try User(from: decoder))
+        default:
+            throw DecodingError.dataCorruptedError(forKey: .__typename, in: container, debugDescription: "unknown Node type: \(typename)")
+        }
+    }
+
+    public func encode(to encoder: Encoder) throws {
+        var container = encoder.container(keyedBy: CodingKeys.self)
+        switch self {
+        case .user(let value):
+            try container.encode("User", forKey: .__typename)
+            try value.encode(to: encoder)
+        }
+    }
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestDoc(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	doc := &ast.DocGroup{List: []*ast.DocGroup_Doc{
+		{Text: "# First line.", Char: '#'},
+		{Text: "# Second line.", Char: '#'},
+	}}
+
+	g.printDoc(doc)
+
+	ex := []byte(`/// First line.
+/// Second line.
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestOptions(t *testing.T) {
+	doc := &ast.Document{
+		Directives: []*ast.DirectiveLit{
+			{
+				Name: "swift",
+				Args: &ast.CallExpr{Args: []*ast.Arg{{
+					Name: &ast.Ident{Name: "options"},
+					Value: &ast.Arg_CompositeLit{CompositeLit: &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: &ast.ObjLit{
+						Fields: []*ast.ObjLit_Pair{
+							{
+								Key: &ast.Ident{Name: "descriptions"},
+								Val: &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_BOOL, Value: "false"}}},
+							},
+						},
+					}}}},
+				}}},
+			},
+		},
+	}
+
+	gOpts, err := getOptions(doc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if gOpts.Descriptions {
+		t.Errorf("unexpected options: %#v", gOpts)
+	}
+
+	gOpts, err = getOptions(doc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !gOpts.Descriptions {
+		t.Errorf("expected cli options to take precedence: %#v", gOpts)
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := new(Generator)
+	g.Reset()
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `"A user of the service."
+type User {
+	name: String!
+	friends: [User!]
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example.gql", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, nil)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Println(b.String())
+	// Output:
+	// import Foundation
+	//
+	// /// A user of the service.
+	// public struct User: Codable, Hashable {
+	//     public var name: String
+	//     public var friends: [User]?
+	//
+	//     public init(name: String, friends: [User]? = nil) {
+	//         self.name = name
+	//         self.friends = friends
+	//     }
+	// }
+}
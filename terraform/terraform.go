@@ -0,0 +1,456 @@
+// Package terraform contains a generator of Terraform provider schemas for
+// GraphQL Documents. Objects which are created from an input type become
+// resources, whose arguments are the fields of the input, so a provider
+// managing them through the GraphQL API can be scaffolded from its schema.
+//
+package terraform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/ir"
+	"github.com/gqlc/graphql/ast"
+	"go.uber.org/zap"
+)
+
+// FormatVersion is the version of the provider schema format, as it's
+// printed by terraform providers schema -json.
+//
+const FormatVersion = "1.0"
+
+// defaultRegistry is the hostname of provider sources which don't give one.
+const defaultRegistry = "registry.terraform.io"
+
+// resourceDirective is the directive naming an object's resource, or the
+// input type it's created from.
+//
+const resourceDirective = "resource"
+
+// Options contains the options for the Terraform generator.
+type Options struct {
+	// Source is the source address of the provider, [hostname/]namespace/type,
+	// whose type prefixes the names of its resources (default: gqlc/<document>)
+	Source string
+}
+
+// Schemas are the schemas of providers, by their source address.
+type Schemas struct {
+	FormatVersion   string                     `json:"format_version"`
+	ProviderSchemas map[string]*ProviderSchema `json:"provider_schemas"`
+}
+
+// ProviderSchema is the schema of a provider's configuration, along with
+// the schemas of the resources it manages, by their names.
+//
+type ProviderSchema struct {
+	Provider        *Schema            `json:"provider"`
+	ResourceSchemas map[string]*Schema `json:"resource_schemas"`
+}
+
+// Schema is the schema of a provider's configuration, or a resource.
+type Schema struct {
+	Version int64  `json:"version"`
+	Block   *Block `json:"block"`
+}
+
+// Block is a block of attributes.
+type Block struct {
+	Attributes      map[string]*Attribute `json:"attributes,omitempty"`
+	Description     string                `json:"description,omitempty"`
+	DescriptionKind string                `json:"description_kind,omitempty"`
+}
+
+// Attribute is an attribute of a block. Its Type is a Terraform type
+// constraint, in JSON e.g. "string", or ["list", "number"].
+//
+type Attribute struct {
+	Type            interface{} `json:"type"`
+	Description     string      `json:"description,omitempty"`
+	DescriptionKind string      `json:"description_kind,omitempty"`
+	Required        bool        `json:"required,omitempty"`
+	Optional        bool        `json:"optional,omitempty"`
+	Computed        bool        `json:"computed,omitempty"`
+	Deprecated      bool        `json:"deprecated,omitempty"`
+}
+
+// Generator generates a Terraform provider schema for a GraphQL schema.
+type Generator struct {
+	bytes.Buffer
+
+	log *zap.Logger
+}
+
+// Generate generates the provider schema of the given document. It's named
+// after the document e.g. test.gql generates test.provider.json.
+//
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "terraform",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("terraform").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	gOpts, err := getOptions(doc, opts)
+	if err != nil {
+		return
+	}
+	if gOpts.Source == "" {
+		gOpts.Source = "gqlc/" + strings.Replace(snakeCase(baseName(doc)), "_", "-", -1)
+	}
+
+	source, typ, err := parseSource(gOpts.Source)
+	if err != nil {
+		return
+	}
+
+	s, err := ir.Load(doc)
+	if err != nil {
+		return
+	}
+
+	g.log.Info("mapping resources")
+	ps, err := BuildProviderSchema(s, typ)
+	if err != nil {
+		return
+	}
+
+	enc := json.NewEncoder(g)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(&Schemas{
+		FormatVersion:   FormatVersion,
+		ProviderSchemas: map[string]*ProviderSchema{source: ps},
+	})
+	if err != nil {
+		return
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	f, err := gCtx.Open(baseName(doc) + ".provider.json")
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	_, err = g.WriteTo(f)
+	return
+}
+
+// baseName returns the name of a document, without its extension.
+func baseName(doc *ast.Document) string {
+	base := filepath.Base(doc.Name)
+	name := base[:len(base)-len(filepath.Ext(base))]
+	if name == "" {
+		name = "schema"
+	}
+	return name
+}
+
+// parseSource returns the full source address of a provider, along with its
+// type.
+//
+func parseSource(source string) (addr, typ string, err error) {
+	elems := strings.Split(source, "/")
+	for _, e := range elems {
+		if e == "" {
+			return "", "", fmt.Errorf("invalid provider source: %q, expected [hostname/]namespace/type", source)
+		}
+	}
+
+	switch len(elems) {
+	case 2:
+		return defaultRegistry + "/" + source, elems[1], nil
+	case 3:
+		return source, elems[2], nil
+	}
+	return "", "", fmt.Errorf("invalid provider source: %q, expected [hostname/]namespace/type", source)
+}
+
+// BuildProviderSchema maps the objects of a schema to the resources of a
+// provider of the given type, which prefixes their names. An object is a
+// resource when it's created from an input type: the one named by its
+// @resource directive, or else <Object>Input. The fields of the input are
+// its arguments, and the fields of the object which aren't are computed.
+// Fields of objects, interfaces and unions, and fields which take arguments,
+// aren't attributes, since they're resolved, rather than stored.
+//
+func BuildProviderSchema(s *ir.Schema, typ string) (*ProviderSchema, error) {
+	ps := &ProviderSchema{
+		Provider: &Schema{Block: &Block{
+			Attributes: map[string]*Attribute{
+				"endpoint": {
+					Type:            "string",
+					Description:     "The URL of the GraphQL API.",
+					DescriptionKind: "plain",
+					Optional:        true,
+				},
+			},
+		}},
+		ResourceSchemas: make(map[string]*Schema),
+	}
+
+	for _, t := range s.Types() {
+		if t.Kind() != ir.Object || s.IsRoot(t) {
+			continue
+		}
+
+		name, input, err := resource(s, t)
+		if err != nil {
+			return nil, err
+		}
+		if input == nil {
+			continue
+		}
+
+		block, err := resourceBlock(t, input)
+		if err != nil {
+			return nil, err
+		}
+
+		name = typ + "_" + name
+		if _, ok := ps.ResourceSchemas[name]; ok {
+			return nil, fmt.Errorf("%s: resource %s is declared more than once", t.Name(), name)
+		}
+		ps.ResourceSchemas[name] = &Schema{Block: block}
+	}
+	return ps, nil
+}
+
+// resource returns the name of an object's resource, and the input type it's
+// created from, if it's a resource.
+//
+func resource(s *ir.Schema, t *ir.Named) (name string, input *ir.Named, err error) {
+	name = snakeCase(t.Name())
+	inputName := t.Name() + "Input"
+
+	d := findDirective(t.Directives())
+	if d != nil {
+		if v, ok := d.Arg("name"); ok {
+			name = unquote(v)
+		}
+		if v, ok := d.Arg("from"); ok {
+			inputName = unquote(v)
+		}
+	}
+
+	input = s.Lookup(inputName)
+	switch {
+	case input != nil && input.Kind() == ir.Input:
+		return name, input, nil
+	case d != nil:
+		return "", nil, fmt.Errorf("%s: %s isn't an input type, see @%s", t.Name(), inputName, resourceDirective)
+	}
+	return "", nil, nil
+}
+
+func findDirective(dirs []*ir.Directive) *ir.Directive {
+	for _, d := range dirs {
+		if d.Name() == resourceDirective {
+			return d
+		}
+	}
+	return nil
+}
+
+// resourceBlock returns the block of a resource, whose arguments are the
+// fields of its input. Non-null fields without defaults are required, while
+// the rest are optional, and also computed, if the object has them.
+//
+func resourceBlock(obj, input *ir.Named) (*Block, error) {
+	block := &Block{Attributes: make(map[string]*Attribute)}
+	describe(&block.Description, &block.DescriptionKind, obj.Description())
+
+	for _, f := range input.InputFields() {
+		typ, err := attributeType(f.Type(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %s", input.Name(), f.Name(), err)
+		}
+
+		attr := &Attribute{Type: typ}
+		describe(&attr.Description, &attr.DescriptionKind, f.Description())
+		if !ir.IsNullable(f.Type()) && f.Default() == "" {
+			attr.Required = true
+		} else {
+			attr.Optional = true
+		}
+		_, attr.Deprecated = f.Deprecated()
+
+		block.Attributes[snakeCase(f.Name())] = attr
+	}
+
+	for _, f := range obj.Fields() {
+		if len(f.Args()) > 0 {
+			continue
+		}
+		switch ir.Unwrap(f.Type()).Kind() {
+		case ir.Object, ir.Interface, ir.Union:
+			continue
+		}
+
+		name := snakeCase(f.Name())
+		if attr, ok := block.Attributes[name]; ok {
+			attr.Computed = attr.Optional
+			continue
+		}
+
+		typ, err := attributeType(f.Type(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %s", obj.Name(), f.Name(), err)
+		}
+
+		attr := &Attribute{Type: typ, Computed: true}
+		describe(&attr.Description, &attr.DescriptionKind, f.Description())
+		_, attr.Deprecated = f.Deprecated()
+
+		block.Attributes[name] = attr
+	}
+	return block, nil
+}
+
+// describe sets a description, which is plain text.
+func describe(descr, kind *string, text string) {
+	if text == "" {
+		return
+	}
+	*descr, *kind = text, "plain"
+}
+
+// attributeType returns the Terraform type of a GraphQL type. Input types
+// are objects of their fields, which can't be recursive, so the input types
+// being mapped are tracked by visiting.
+//
+func attributeType(t ir.Type, visiting map[*ir.Named]bool) (interface{}, error) {
+	switch v := t.(type) {
+	case *ir.NonNull:
+		return attributeType(v.Elem, visiting)
+	case *ir.List:
+		elem, err := attributeType(v.Elem, visiting)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{"list", elem}, nil
+	case *ir.Named:
+		switch v.Kind() {
+		case ir.Scalar:
+			switch v.Name() {
+			case "Int", "Float":
+				return "number", nil
+			case "Boolean":
+				return "bool", nil
+			}
+			return "string", nil
+		case ir.Enum:
+			return "string", nil
+		case ir.Input:
+			if visiting[v] {
+				return nil, fmt.Errorf("%s is recursive, which Terraform object types can't be", v.Name())
+			}
+			if visiting == nil {
+				visiting = make(map[*ir.Named]bool)
+			}
+			visiting[v] = true
+			defer delete(visiting, v)
+
+			attrs := make(map[string]interface{}, len(v.InputFields()))
+			for _, f := range v.InputFields() {
+				typ, err := attributeType(f.Type(), visiting)
+				if err != nil {
+					return nil, err
+				}
+				attrs[snakeCase(f.Name())] = typ
+			}
+			return []interface{}{"object", attrs}, nil
+		}
+	}
+	return "dynamic", nil
+}
+
+// snakeCase returns the snake case of a name, which is how Terraform names
+// resources and attributes.
+//
+func snakeCase(s string) string {
+	rs := []rune(s)
+
+	var b strings.Builder
+	for i, r := range rs {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteByte('_')
+			}
+			continue
+		}
+
+		if unicode.IsUpper(r) && i > 0 {
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.Trim(b.String(), "_")
+}
+
+// unquote returns the value of a GraphQL string.
+func unquote(s string) string {
+	if v, err := strconv.Unquote(s); err == nil {
+		return v
+	}
+	return strings.Trim(s, `"`)
+}
+
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "terraform" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		tfOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range tfOpts.Fields {
+			switch arg.Key.Name {
+			case "source":
+				gOpts.Source = arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts != nil {
+		if s, ok := opts["source"].(string); ok {
+			gOpts.Source = s
+		}
+	}
+
+	gOpts.Source = strings.Trim(gOpts.Source, `"`)
+	return
+}
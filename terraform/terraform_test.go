@@ -0,0 +1,323 @@
+package terraform
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/ir"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "test.provider.json", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, parser.ParseComments)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected terraform output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected terraform output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := new(Generator).Generate(ctx, testDoc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func TestBuildProviderSchema(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Src  string
+		Ex   map[string]map[string]string
+		Err  string
+	}{
+		{
+			Name: "InputSuffix",
+			Src: `type Thing {
+	id: ID!
+	name: String
+	size: Int!
+}
+
+input ThingInput {
+	name: String
+	size: Int!
+}`,
+			Ex: map[string]map[string]string{
+				"api_thing": {"id": "computed", "name": "optional,computed", "size": "required"},
+			},
+		},
+		{
+			Name: "Directive",
+			Src: `type Thing @resource(name: "widget", from: "NewThing") {
+	id: ID!
+}
+
+input NewThing {
+	id: ID!
+}`,
+			Ex: map[string]map[string]string{
+				"api_widget": {"id": "required"},
+			},
+		},
+		{
+			Name: "NoInput",
+			Src: `type Thing {
+	id: ID!
+}`,
+			Ex: map[string]map[string]string{},
+		},
+		{
+			Name: "RootTypes",
+			Src: `type Query {
+	thing: Thing
+}
+
+type Thing {
+	id: ID!
+}
+
+input QueryInput {
+	id: ID
+}
+
+input ThingInput {
+	id: ID
+}`,
+			Ex: map[string]map[string]string{
+				"api_thing": {"id": "optional,computed"},
+			},
+		},
+		{
+			Name: "MissingInput",
+			Src: `type Thing @resource(from: "NewThing") {
+	id: ID!
+}`,
+			Err: "Thing: NewThing isn't an input type, see @resource",
+		},
+		{
+			Name: "Recursive",
+			Src: `type Thing {
+	id: ID!
+}
+
+input ThingInput {
+	parent: ThingInput
+}`,
+			Err: "ThingInput.parent: ThingInput is recursive, which Terraform object types can't be",
+		},
+		{
+			Name: "Duplicate",
+			Src: `type A @resource(name: "thing") {
+	id: ID!
+}
+
+type B @resource(name: "thing", from: "AInput") {
+	id: ID!
+}
+
+input AInput {
+	id: ID
+}`,
+			Err: "B: resource api_thing is declared more than once",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(testCase.Src), 0)
+			if err != nil {
+				subT.Fatal(err)
+			}
+			s, err := ir.Load(doc)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			ps, err := BuildProviderSchema(s, "api")
+			if testCase.Err != "" {
+				if err == nil || err.Error() != testCase.Err {
+					subT.Errorf("expected error: %s, but got: %v", testCase.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if len(ps.ResourceSchemas) != len(testCase.Ex) {
+				subT.Errorf("expected %d resources, but got: %d", len(testCase.Ex), len(ps.ResourceSchemas))
+			}
+			for name, attrs := range testCase.Ex {
+				r, ok := ps.ResourceSchemas[name]
+				if !ok {
+					subT.Errorf("expected resource: %s", name)
+					continue
+				}
+				if len(r.Block.Attributes) != len(attrs) {
+					subT.Errorf("expected %d attributes of %s, but got: %d", len(attrs), name, len(r.Block.Attributes))
+				}
+				for attrName, ex := range attrs {
+					attr, ok := r.Block.Attributes[attrName]
+					if !ok {
+						subT.Errorf("expected attribute %s of %s", attrName, name)
+						continue
+					}
+					if got := modes(attr); got != ex {
+						subT.Errorf("expected %s.%s to be %s, but got: %s", name, attrName, ex, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+// modes returns whether an attribute is required, optional and computed.
+func modes(attr *Attribute) string {
+	var ms []string
+	for _, m := range []struct {
+		name string
+		set  bool
+	}{{"required", attr.Required}, {"optional", attr.Optional}, {"computed", attr.Computed}} {
+		if m.set {
+			ms = append(ms, m.name)
+		}
+	}
+	return strings.Join(ms, ",")
+}
+
+func TestSource(t *testing.T) {
+	testCases := []struct {
+		Source string
+		Addr   string
+		Type   string
+		Err    bool
+	}{
+		{Source: "example/users", Addr: "registry.terraform.io/example/users", Type: "users"},
+		{Source: "tf.example.com/example/users", Addr: "tf.example.com/example/users", Type: "users"},
+		{Source: "users", Err: true},
+		{Source: "example//users", Err: true},
+		{Source: "a/b/c/d", Err: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Source, func(subT *testing.T) {
+			addr, typ, err := parseSource(testCase.Source)
+			if testCase.Err {
+				if err == nil {
+					subT.Errorf("expected source %s to be invalid", testCase.Source)
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if addr != testCase.Addr || typ != testCase.Type {
+				subT.Errorf("expected %s and %s, but got: %s and %s", testCase.Addr, testCase.Type, addr, typ)
+			}
+		})
+	}
+}
+
+func TestDefaultSource(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "my_api.gql", strings.NewReader("type A {\n\tid: ID!\n}\n\ninput AInput {\n\tid: ID!\n}"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err = new(Generator).Generate(ctx, doc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ex := range []string{`"registry.terraform.io/gqlc/my-api": {`, `"my-api_a": {`} {
+		if !strings.Contains(b.String(), ex) {
+			t.Errorf("expected output to contain:\n%s\nbut got:\n%s", ex, b.String())
+		}
+	}
+}
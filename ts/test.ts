@@ -0,0 +1,220 @@
+import {
+  GraphQLSchema,
+  GraphQLScalarType,
+  GraphQLObjectType,
+  GraphQLInterfaceType,
+  GraphQLUnionType,
+  GraphQLEnumType,
+  GraphQLInputObjectType,
+  GraphQLDirective,
+  DirectiveLocation,
+  GraphQLList,
+  GraphQLNonNull,
+  GraphQLInt,
+  GraphQLFloat,
+  GraphQLString,
+  GraphQLBoolean,
+  GraphQLID
+} from 'graphql';
+
+export type Context = any;
+
+export type Version = any;
+
+export const VersionType: GraphQLScalarType = new GraphQLScalarType({
+  name: 'Version',
+  description: 'Version represents an API version.',
+  serialize(value: unknown) { /* TODO */ },
+});
+
+export interface EchoSource {
+  msg: string;
+}
+
+export const EchoType: GraphQLObjectType<EchoSource, Context> = new GraphQLObjectType<EchoSource, Context>({
+  name: 'Echo',
+  description: 'Echo represents an echo message.',
+  fields: () => ({
+    msg: {
+      type: new GraphQLNonNull(GraphQLString),
+      description: 'msg contains the provided message.',
+      resolve(source: EchoSource, args: {}, context: Context) { /* TODO */ },
+    },
+  }),
+});
+
+export interface QuerySource {
+  version?: Version | null;
+}
+
+export const QueryType: GraphQLObjectType<QuerySource, Context> = new GraphQLObjectType<QuerySource, Context>({
+  name: 'Query',
+  description: 'Query represents valid queries.',
+  fields: () => ({
+    version: {
+      type: VersionType,
+      description: 'version returns the current API version.',
+      resolve(source: QuerySource, args: {}, context: Context) { /* TODO */ },
+    },
+    echo: {
+      type: EchoType,
+      description: 'echo echos a message.',
+      args: {
+        text: {
+          type: new GraphQLNonNull(GraphQLString),
+        },
+      },
+      resolve(source: QuerySource, args: { text: string }, context: Context) { /* TODO */ },
+    },
+    search: {
+      type: ResultType,
+      description: 'search performs a search over some data set.',
+      args: {
+        text: {
+          type: GraphQLString,
+          description: 'text is a single text input to use for searching.',
+        },
+        terms: {
+          type: new GraphQLList(GraphQLString),
+          description: 'terms represent term based querying.',
+        },
+      },
+      resolve(source: QuerySource, args: { text?: string | null; terms?: Array<string | null> | null }, context: Context) { /* TODO */ },
+    },
+  }),
+});
+
+export interface ResultSource {
+  total?: number | null;
+  edges?: Array<NodeSource | null> | null;
+  hasNextPage?: boolean | null;
+}
+
+export const ResultType: GraphQLObjectType<ResultSource, Context> = new GraphQLObjectType<ResultSource, Context>({
+  name: 'Result',
+  description: 'Result represents a search result.',
+  interfaces: () => [ConnectionType],
+  fields: () => ({
+    total: {
+      type: GraphQLInt,
+      description: 'total yields the total number of search results.',
+      resolve(source: ResultSource, args: {}, context: Context) { /* TODO */ },
+    },
+    edges: {
+      type: new GraphQLList(NodeType),
+      description: 'edges contains the search results.',
+      resolve(source: ResultSource, args: {}, context: Context) { /* TODO */ },
+    },
+    hasNextPage: {
+      type: GraphQLBoolean,
+      description: 'hasNextPage tells if there are more search results.',
+      resolve(source: ResultSource, args: {}, context: Context) { /* TODO */ },
+    },
+  }),
+});
+
+export interface ConnectionSource {
+  total?: number | null;
+  edges?: Array<NodeSource | null> | null;
+  hasNextPage?: boolean | null;
+}
+
+export const ConnectionType: GraphQLInterfaceType = new GraphQLInterfaceType({
+  name: 'Connection',
+  description: 'Connection represents a set of edges, which are meant to be paginated.',
+  fields: () => ({
+    total: {
+      type: GraphQLInt,
+      description: 'total returns the total number of edges.',
+    },
+    edges: {
+      type: new GraphQLList(NodeType),
+      description: 'edges contains the current page of edges.',
+    },
+    hasNextPage: {
+      type: GraphQLBoolean,
+      description: 'hasNextPage tells if there exists more edges.',
+    },
+  }),
+});
+
+export interface NodeSource {
+  id: string;
+}
+
+export const NodeType: GraphQLInterfaceType = new GraphQLInterfaceType({
+  name: 'Node',
+  description: 'Node represents a node.',
+  fields: () => ({
+    id: {
+      type: new GraphQLNonNull(GraphQLID),
+      description: 'id uniquely identifies the node.',
+    },
+  }),
+});
+
+export type SearchResultSource = EchoSource | ResultSource;
+
+export const SearchResultType: GraphQLUnionType = new GraphQLUnionType({
+  name: 'SearchResult',
+  description: 'SearchResult is a test union type',
+  types: () => [EchoType, ResultType],
+  resolveType(value: any, context: Context) { /* TODO */ return undefined; },
+});
+
+export type Direction = 'NORTH' | 'EAST' | 'SOUTH' | 'WEST';
+
+export const DirectionType: GraphQLEnumType = new GraphQLEnumType({
+  name: 'Direction',
+  description: 'Direction represents a cardinal direction.',
+  values: {
+    NORTH: {
+      value: 'NORTH',
+      description: 'EnumValue description',
+    },
+    EAST: {
+      value: 'EAST',
+    },
+    SOUTH: {
+      value: 'SOUTH',
+    },
+    WEST: {
+      value: 'WEST',
+      description: 'EnumValue Description and Directives.',
+    },
+  },
+});
+
+export interface Point {
+  x: number;
+  y: number;
+}
+
+export const PointType: GraphQLInputObjectType = new GraphQLInputObjectType({
+  name: 'Point',
+  description: 'Point represents a 2-D geo point.',
+  fields: () => ({
+    x: {
+      type: new GraphQLNonNull(GraphQLFloat),
+    },
+    y: {
+      type: new GraphQLNonNull(GraphQLFloat),
+    },
+  }),
+});
+
+export const deprecateType: GraphQLDirective = new GraphQLDirective({
+  name: 'deprecate',
+  description: 'deprecate signifies a type deprecation from the api.',
+  locations: [DirectiveLocation.SCHEMA, DirectiveLocation.FIELD],
+  args: {
+    msg: {
+      type: GraphQLString,
+      description: 'Arg description.',
+    },
+  },
+});
+
+export const Schema: GraphQLSchema = new GraphQLSchema({
+  query: QueryType,
+});
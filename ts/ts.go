@@ -0,0 +1,1089 @@
+// Package ts contains a TypeScript generator for GraphQL Documents.
+package ts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/gen/printer"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+const (
+	// Types
+	schemaBit uint16 = 1 << iota
+	scalarBit
+	objectBit
+	interfaceBit
+	unionBit
+	enumBit
+	inputObjectBit
+	directiveBit
+	directiveLocBit
+
+	// Wrapping Types
+	listBit
+	nonNullBit
+
+	// Builtin Scalar Types
+	intBit
+	floatBit
+	stringBit
+	booleanBit
+	idBit
+)
+
+// Options contains the options for the TypeScript generator.
+type Options struct {
+	// Either "COMMONJS" or "ES6"
+	Module string
+
+	// Copy descriptions to TypeScript
+	Descriptions bool
+
+	// Also emit a .d.ts declaration file
+	Declarations bool
+
+	imports [][]byte
+}
+
+var bits = []struct {
+	bit uint16
+	imp []byte
+}{
+	{bit: schemaBit, imp: []byte("GraphQLSchema")},
+	{bit: scalarBit, imp: []byte("GraphQLScalarType")},
+	{bit: objectBit, imp: []byte("GraphQLObjectType")},
+	{bit: interfaceBit, imp: []byte("GraphQLInterfaceType")},
+	{bit: unionBit, imp: []byte("GraphQLUnionType")},
+	{bit: enumBit, imp: []byte("GraphQLEnumType")},
+	{bit: inputObjectBit, imp: []byte("GraphQLInputObjectType")},
+	{bit: directiveBit, imp: []byte("GraphQLDirective")},
+	{bit: directiveLocBit, imp: []byte("DirectiveLocation")},
+	{bit: listBit, imp: []byte("GraphQLList")},
+	{bit: nonNullBit, imp: []byte("GraphQLNonNull")},
+	{bit: intBit, imp: []byte("GraphQLInt")},
+	{bit: floatBit, imp: []byte("GraphQLFloat")},
+	{bit: stringBit, imp: []byte("GraphQLString")},
+	{bit: booleanBit, imp: []byte("GraphQLBoolean")},
+	{bit: idBit, imp: []byte("GraphQLID")},
+}
+
+func (o *Options) setImports(mask uint16) {
+	for _, p := range bits {
+		if mask&p.bit == 0 {
+			o.imports = append(o.imports, p.imp)
+		}
+	}
+}
+
+// Generator generates TypeScript code for a GraphQL schema.
+type Generator struct {
+	printer.Printer
+
+	// decls holds the contents of the .d.ts file
+	decls bytes.Buffer
+
+	// qual is prepended to every graphql-js identifier
+	qual string
+
+	// sources holds the names of the types which resolve from a source,
+	// i.e. objects, interfaces and unions
+	sources map[string]bool
+
+	log *zap.Logger
+}
+
+// Reset overrides the printer.Printer Reset method to assist in cleaning up some Generator state.
+func (g *Generator) Reset() {
+	g.Printer.Reset()
+	g.Indent = "  "
+	g.decls.Reset()
+	g.qual = ""
+	g.sources = nil
+}
+
+// Generate generates TypeScript code for the given document.
+func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return new(Generator).generate(ctx, doc, opts)
+}
+
+func (g *Generator) generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: doc.Name,
+				GenName: "ts",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+	g.Reset()
+
+	g.log = zap.L().Named("ts").With(zap.String("doc", doc.Name))
+
+	// Get generator options
+	g.log.Info("getting options")
+	gOpts, oerr := getOptions(doc, opts)
+	if oerr != nil {
+		return oerr
+	}
+	if gOpts.Module == "COMMONJS" {
+		g.qual = "graphql."
+	}
+
+	// Create bit mask for tracking imports
+	mask := schemaBit | scalarBit | objectBit | interfaceBit | unionBit | enumBit | inputObjectBit | directiveBit | directiveLocBit
+	mask |= listBit | nonNullBit
+	mask |= intBit | floatBit | stringBit | booleanBit | idBit
+
+	g.P("export type Context = any;")
+	g.P()
+	g.decls.WriteString("export type Context = any;\n\n")
+
+	g.sources = make(map[string]bool)
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+
+		switch ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object, *ast.TypeSpec_Interface, *ast.TypeSpec_Union:
+			g.sources[ts.TypeSpec.Name.Name] = true
+		}
+	}
+
+	// Generate types
+	g.log.Info("generating types")
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Schema); ok {
+			continue
+		}
+
+		name := ts.TypeSpec.Name.Name
+
+		// Generate TypeScript type alias for types which can be used as arguments,
+		// or as the source of resolvers
+		switch ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar, *ast.TypeSpec_Enum, *ast.TypeSpec_Input:
+			g.generateAlias(name, ts.TypeSpec)
+			g.P()
+		case *ast.TypeSpec_Object, *ast.TypeSpec_Interface, *ast.TypeSpec_Union:
+			g.generateSource(name, ts.TypeSpec)
+			g.P()
+		}
+
+		// Generate GraphQL*Type construction
+		switch ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar:
+			g.generateScalar(&mask, name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+
+			mask &= ^scalarBit
+		case *ast.TypeSpec_Object:
+			g.generateObject(&mask, name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+
+			mask &= ^objectBit
+		case *ast.TypeSpec_Interface:
+			g.generateInterface(&mask, name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+
+			mask &= ^interfaceBit
+		case *ast.TypeSpec_Union:
+			g.generateUnion(&mask, name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+
+			mask &= ^unionBit
+		case *ast.TypeSpec_Enum:
+			g.generateEnum(&mask, name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+
+			mask &= ^enumBit
+		case *ast.TypeSpec_Input:
+			g.generateInput(&mask, name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+
+			mask &= ^inputObjectBit
+		case *ast.TypeSpec_Directive:
+			g.generateDirective(&mask, name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+
+			mask &= ^directiveBit
+		}
+		g.P()
+	}
+
+	// Generate schema last so every root operation type has been declared
+	if doc.Schema != nil {
+		g.log.Info("generating schema")
+		mask &= ^schemaBit
+		g.generateSchema(doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec)
+	}
+
+	// Extract generator context
+	gCtx := gen.Context(ctx)
+
+	// Open file to write to
+	tsFileName := doc.Name[:len(doc.Name)-len(filepath.Ext(doc.Name))]
+	tsFile, err := gCtx.Open(tsFileName + ".ts")
+	if err != nil {
+		return
+	}
+	defer tsFile.Close()
+
+	// Write module import statement
+	g.log.Info("writing module import statement")
+	gOpts.setImports(mask)
+	_, err = g.writeImports(tsFile, gOpts)
+	if err != nil {
+		return
+	}
+
+	// Write generated output
+	_, err = g.WriteTo(tsFile)
+	if err != nil || !gOpts.Declarations {
+		return
+	}
+
+	// Write declaration file. It's kept apart from the .ts file, since
+	// TypeScript resolves the .ts file over a .d.ts of the same name.
+	g.log.Info("writing declarations")
+	dtsFile, err := gCtx.Open(filepath.Join(filepath.Dir(tsFileName), "types", filepath.Base(tsFileName)+".d.ts"))
+	if err != nil {
+		return
+	}
+	defer dtsFile.Close()
+
+	_, err = g.writeImports(dtsFile, gOpts)
+	if err != nil {
+		return
+	}
+
+	_, err = g.decls.WriteTo(dtsFile)
+	return
+}
+
+var (
+	commonJSImport = []byte("import graphql = require('graphql');")
+
+	es6ImportDecl = []byte("import")
+	es6Import     = []byte("from 'graphql';")
+
+	indent = []byte{' ', ' '}
+)
+
+// writeImports writes the module import statement to the given io.Writer.
+func (g *Generator) writeImports(w io.Writer, opts *Options) (int, error) {
+	var b bytes.Buffer
+	b.Grow(350)
+
+	if opts.Module == "COMMONJS" {
+		b.Write(commonJSImport)
+		b.WriteByte('\n')
+		b.WriteByte('\n')
+
+		return w.Write(b.Bytes())
+	}
+
+	b.Write(es6ImportDecl)
+	b.WriteByte(' ')
+	b.WriteByte('{')
+
+	indent := indent
+	impLen := len(opts.imports)
+	if impLen > 1 {
+		b.WriteByte('\n')
+	} else {
+		indent = indent[:1]
+	}
+
+	for i, imp := range opts.imports {
+		b.Write(indent)
+		b.Write(imp)
+		if i != impLen-1 {
+			b.WriteByte(',')
+			b.WriteByte('\n')
+		}
+	}
+	if impLen == 1 {
+		b.WriteByte(' ')
+	} else {
+		b.WriteByte('\n')
+	}
+	b.WriteString("} ")
+	b.Write(es6Import)
+	b.WriteByte('\n')
+	b.WriteByte('\n')
+
+	return w.Write(b.Bytes())
+}
+
+// declare writes a constant declaration to both the source and the declarations.
+func (g *Generator) declare(name, typ, ctor string) {
+	g.P("export const ", name, "Type: ", g.qual, typ, " = new ", g.qual, ctor, "({")
+	fmt.Fprintf(&g.decls, "export declare const %sType: %s%s;\n\n", name, g.qual, typ)
+}
+
+func (g *Generator) generateSchema(ts *ast.TypeSpec) {
+	schema := ts.Type.(*ast.TypeSpec_Schema).Schema
+
+	g.P("export const Schema: ", g.qual, "GraphQLSchema = new ", g.qual, "GraphQLSchema({")
+	g.In()
+
+	for _, f := range schema.RootOps.List {
+		op := strings.ToLower(f.Name.Name)
+		if op != "query" && op != "mutation" && op != "subscription" {
+			continue
+		}
+
+		g.P(op, ": ", f.Type.(*ast.Field_Ident).Ident.Name, "Type,")
+	}
+
+	g.Out()
+	g.P("});")
+
+	fmt.Fprintf(&g.decls, "export declare const Schema: %sGraphQLSchema;\n", g.qual)
+}
+
+// generateAlias generates a TypeScript type for scalars, enums and input objects
+// so they can be used in typed resolver arguments.
+//
+func (g *Generator) generateAlias(name string, ts *ast.TypeSpec) {
+	var b bytes.Buffer
+	b.WriteString("export ")
+
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Scalar:
+		b.WriteString("type ")
+		b.WriteString(name)
+		b.WriteString(" = any;\n")
+	case *ast.TypeSpec_Enum:
+		b.WriteString("type ")
+		b.WriteString(name)
+		b.WriteString(" =")
+
+		for i, val := range v.Enum.Values.List {
+			if i > 0 {
+				b.WriteString(" |")
+			}
+			b.WriteString(" '")
+			b.WriteString(val.Name.Name)
+			b.WriteByte('\'')
+		}
+		b.WriteString(";\n")
+	case *ast.TypeSpec_Input:
+		b.WriteString("interface ")
+		b.WriteString(name)
+		b.WriteString(" {\n")
+
+		for _, f := range v.Input.Fields.List {
+			b.Write(indent)
+			writeProp(&b, f)
+			b.WriteString(";\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	g.Write(b.Bytes())
+	g.decls.Write(b.Bytes())
+	g.decls.WriteByte('\n')
+}
+
+// generateSource generates the TypeScript type of the source objects, interfaces
+// and unions are resolved from. Sources hold the fields which don't take args,
+// since the rest are resolved from them.
+//
+func (g *Generator) generateSource(name string, ts *ast.TypeSpec) {
+	var fields *ast.FieldList
+	var b bytes.Buffer
+	b.WriteString("export ")
+
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Object:
+		fields = v.Object.Fields
+	case *ast.TypeSpec_Interface:
+		fields = v.Interface.Fields
+	case *ast.TypeSpec_Union:
+		b.WriteString("type ")
+		b.WriteString(name)
+		b.WriteString("Source =")
+
+		for i, mem := range v.Union.Members {
+			if i > 0 {
+				b.WriteString(" |")
+			}
+			b.WriteByte(' ')
+			b.WriteString(mem.Name)
+			b.WriteString("Source")
+		}
+		b.WriteString(";\n")
+	}
+
+	if fields != nil {
+		b.WriteString("interface ")
+		b.WriteString(name)
+		b.WriteString("Source {\n")
+
+		for _, f := range fields.List {
+			if f.Args != nil && len(f.Args.List) > 0 {
+				continue
+			}
+
+			var typ interface{}
+			switch v := f.Type.(type) {
+			case *ast.Field_Ident:
+				typ = v.Ident
+			case *ast.Field_List:
+				typ = v.List
+			case *ast.Field_NonNull:
+				typ = v.NonNull
+			}
+
+			b.Write(indent)
+			b.WriteString(f.Name.Name)
+			if _, nonNull := typ.(*ast.NonNull); !nonNull {
+				b.WriteByte('?')
+			}
+			b.WriteString(": ")
+			writeTSType(&b, typ, g.sources)
+			b.WriteString(";\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	g.Write(b.Bytes())
+	g.decls.Write(b.Bytes())
+	g.decls.WriteByte('\n')
+}
+
+func (g *Generator) generateScalar(imports *uint16, name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	g.declare(name, "GraphQLScalarType", "GraphQLScalarType")
+	g.In()
+	g.P("name: '", name, "',")
+
+	if descr {
+		g.printDescr(doc)
+	}
+
+	g.P("serialize(value: unknown) { /* TODO */ },")
+	g.Out()
+
+	g.P("});")
+}
+
+func (g *Generator) generateObject(imports *uint16, name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	obj := ts.Type.(*ast.TypeSpec_Object).Object
+
+	typ := "GraphQLObjectType<" + name + "Source, Context>"
+	g.declare(name, typ, typ)
+	g.In()
+
+	g.P("name: '", name, "',")
+
+	if descr {
+		g.printDescr(doc)
+	}
+
+	// Print interfaces
+	if len(obj.Interfaces) > 0 {
+		g.WriteIndent()
+		g.WriteString("interfaces: () => [")
+		for i, inter := range obj.Interfaces {
+			if i > 0 {
+				g.WriteString(", ")
+			}
+			g.WriteString(inter.Name)
+			g.WriteString("Type")
+		}
+		g.WriteString("],\n")
+	}
+
+	g.P("fields: () => ({")
+	g.In()
+
+	g.generateFields(obj.Fields, imports, descr, name)
+
+	g.Out()
+	g.P("}),")
+
+	g.Out()
+	g.P("});")
+}
+
+func (g *Generator) generateInterface(imports *uint16, name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	inter := ts.Type.(*ast.TypeSpec_Interface).Interface
+
+	g.declare(name, "GraphQLInterfaceType", "GraphQLInterfaceType")
+	g.In()
+
+	g.P("name: '", name, "',")
+
+	if descr {
+		g.printDescr(doc)
+	}
+
+	g.P("fields: () => ({")
+	g.In()
+
+	g.generateFields(inter.Fields, imports, descr, "")
+
+	g.Out()
+	g.P("}),")
+
+	g.Out()
+	g.P("});")
+}
+
+// generateFields generates the fields of an object or interface. Fields of
+// objects are resolved from the source of their object, which is named by source.
+//
+func (g *Generator) generateFields(fields *ast.FieldList, imports *uint16, descr bool, source string) {
+	for _, f := range fields.List {
+		g.P(f.Name.Name, ": {")
+		g.In()
+
+		g.WriteIndent()
+		g.WriteString("type: ")
+
+		var fieldType interface{}
+		switch v := f.Type.(type) {
+		case *ast.Field_Ident:
+			fieldType = v.Ident
+		case *ast.Field_List:
+			fieldType = v.List
+		case *ast.Field_NonNull:
+			fieldType = v.NonNull
+		}
+		g.printType(imports, fieldType)
+		g.WriteByte(',')
+		g.WriteByte('\n')
+
+		if descr {
+			g.printDescr(f.Doc)
+		}
+
+		if f.Args != nil {
+			g.P("args: {")
+			g.In()
+
+			g.generateArgs(f.Args.List, imports, descr)
+
+			g.Out()
+			g.P("},")
+		}
+
+		if source != "" {
+			g.WriteIndent()
+			g.WriteString("resolve(source: ")
+			g.WriteString(source)
+			g.WriteString("Source, args: ")
+			writeArgsType(&g.Buffer, f.Args)
+			g.WriteString(", context: Context) { /* TODO */ },\n")
+		}
+
+		g.Out()
+		g.P("},")
+	}
+}
+
+func (g *Generator) generateUnion(imports *uint16, name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	union := ts.Type.(*ast.TypeSpec_Union).Union
+
+	g.declare(name, "GraphQLUnionType", "GraphQLUnionType")
+	g.In()
+
+	g.P("name: '", name, "',")
+
+	if descr {
+		g.printDescr(doc)
+	}
+
+	// Print members
+	g.WriteIndent()
+	g.WriteString("types: () => [")
+	for i, mem := range union.Members {
+		if i > 0 {
+			g.WriteString(", ")
+		}
+		g.WriteString(mem.Name)
+		g.WriteString("Type")
+	}
+	g.WriteString("],\n")
+
+	g.P("resolveType(value: any, context: Context) { /* TODO */ return undefined; },")
+
+	g.Out()
+	g.P("});")
+}
+
+func (g *Generator) generateEnum(imports *uint16, name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	enum := ts.Type.(*ast.TypeSpec_Enum).Enum
+
+	g.declare(name, "GraphQLEnumType", "GraphQLEnumType")
+	g.In()
+
+	g.P("name: '", name, "',")
+
+	if descr {
+		g.printDescr(doc)
+	}
+
+	g.P("values: {")
+	g.In()
+
+	for _, v := range enum.Values.List {
+		g.P(v.Name.Name, ": {")
+		g.In()
+
+		g.P("value: '", v.Name.Name, "',")
+
+		if descr {
+			g.printDescr(v.Doc)
+		}
+
+		g.Out()
+		g.P("},")
+	}
+
+	g.Out()
+	g.P("},")
+
+	g.Out()
+	g.P("});")
+}
+
+func (g *Generator) generateInput(imports *uint16, name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	input := ts.Type.(*ast.TypeSpec_Input).Input
+
+	g.declare(name, "GraphQLInputObjectType", "GraphQLInputObjectType")
+	g.In()
+
+	g.P("name: '", name, "',")
+
+	if descr {
+		g.printDescr(doc)
+	}
+
+	g.P("fields: () => ({")
+	g.In()
+
+	g.generateArgs(input.Fields.List, imports, descr)
+
+	g.Out()
+	g.P("}),")
+
+	g.Out()
+	g.P("});")
+}
+
+func (g *Generator) generateDirective(imports *uint16, name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
+	directive := ts.Type.(*ast.TypeSpec_Directive).Directive
+
+	g.declare(name, "GraphQLDirective", "GraphQLDirective")
+	g.In()
+
+	g.P("name: '", name, "',")
+
+	if descr {
+		g.printDescr(doc)
+	}
+
+	// Print locations
+	g.WriteIndent()
+	g.WriteString("locations: [")
+	for i, loc := range directive.Locs {
+		if i > 0 {
+			g.WriteString(", ")
+		}
+		g.WriteString(g.qual)
+		g.WriteString("DirectiveLocation.")
+		g.WriteString(loc.Loc.String())
+	}
+	g.WriteString("],\n")
+	*imports &= ^directiveLocBit
+
+	if directive.Args != nil {
+		g.P("args: {")
+		g.In()
+
+		g.generateArgs(directive.Args.List, imports, descr)
+
+		g.Out()
+		g.P("},")
+	}
+
+	g.Out()
+	g.P("});")
+}
+
+func (g *Generator) generateArgs(args []*ast.InputValue, imports *uint16, descr bool) {
+	for _, a := range args {
+		g.P(a.Name.Name, ": {")
+		g.In()
+
+		g.WriteIndent()
+		g.WriteString("type: ")
+
+		var fieldType interface{}
+		switch v := a.Type.(type) {
+		case *ast.InputValue_Ident:
+			fieldType = v.Ident
+		case *ast.InputValue_List:
+			fieldType = v.List
+		case *ast.InputValue_NonNull:
+			fieldType = v.NonNull
+		}
+		g.printType(imports, fieldType)
+		g.WriteByte(',')
+		g.WriteByte('\n')
+
+		if a.Default != nil {
+			g.WriteIndent()
+			g.WriteString("defaultValue: ")
+
+			var defType interface{}
+			switch v := a.Default.(type) {
+			case *ast.InputValue_BasicLit:
+				defType = v.BasicLit
+			case *ast.InputValue_CompositeLit:
+				defType = v.CompositeLit
+			}
+			g.printVal(defType)
+			g.WriteByte(',')
+			g.WriteByte('\n')
+		}
+
+		if descr {
+			g.printDescr(a.Doc)
+		}
+
+		g.Out()
+		g.P("},")
+	}
+}
+
+func (g *Generator) printDescr(doc *ast.DocGroup) {
+	text := doc.Text()
+	if len(text) == 0 {
+		return
+	}
+
+	g.P("description: ", quote(text[:len(text)-1]), ",")
+}
+
+// quote returns s as a single quoted TypeScript string literal.
+func quote(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\'':
+			b.WriteString(`\'`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('\'')
+
+	return b.String()
+}
+
+// printType prints a field type
+func (g *Generator) printType(imports *uint16, typ interface{}) {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		name := v.Name
+
+		switch name {
+		case "Int":
+			name = "GraphQLInt"
+			*imports &= ^intBit
+		case "Float":
+			name = "GraphQLFloat"
+			*imports &= ^floatBit
+		case "String":
+			name = "GraphQLString"
+			*imports &= ^stringBit
+		case "Boolean":
+			name = "GraphQLBoolean"
+			*imports &= ^booleanBit
+		case "ID":
+			name = "GraphQLID"
+			*imports &= ^idBit
+		default:
+			g.WriteString(name)
+			g.WriteString("Type")
+			return
+		}
+
+		g.WriteString(g.qual)
+		g.WriteString(name)
+	case *ast.List:
+		g.WriteString("new ")
+		g.WriteString(g.qual)
+		g.WriteString("GraphQLList(")
+
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			typ = w.Ident
+		case *ast.List_List:
+			typ = w.List
+		case *ast.List_NonNull:
+			typ = w.NonNull
+		}
+		g.printType(imports, typ)
+
+		g.WriteByte(')')
+
+		*imports &= ^listBit
+	case *ast.NonNull:
+		g.WriteString("new ")
+		g.WriteString(g.qual)
+		g.WriteString("GraphQLNonNull(")
+
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			typ = w.Ident
+		case *ast.NonNull_List:
+			typ = w.List
+		}
+		g.printType(imports, typ)
+
+		g.WriteByte(')')
+
+		*imports &= ^nonNullBit
+	}
+}
+
+// writeArgsType writes the TypeScript object type of a fields arguments.
+func writeArgsType(b *bytes.Buffer, args *ast.InputValueList) {
+	if args == nil || len(args.List) == 0 {
+		b.WriteString("{}")
+		return
+	}
+
+	b.WriteString("{ ")
+	for i, a := range args.List {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		writeProp(b, a)
+	}
+	b.WriteString(" }")
+}
+
+// writeProp writes an input value as a TypeScript property signature.
+func writeProp(b *bytes.Buffer, a *ast.InputValue) {
+	var typ interface{}
+	switch v := a.Type.(type) {
+	case *ast.InputValue_Ident:
+		typ = v.Ident
+	case *ast.InputValue_List:
+		typ = v.List
+	case *ast.InputValue_NonNull:
+		typ = v.NonNull
+	}
+
+	b.WriteString(a.Name.Name)
+	if _, nonNull := typ.(*ast.NonNull); !nonNull {
+		b.WriteByte('?')
+	}
+	b.WriteString(": ")
+	writeTSType(b, typ, nil)
+}
+
+// writeTSType writes the TypeScript type for a GraphQL type. Types named
+// in sources are written as their source type.
+//
+func writeTSType(b *bytes.Buffer, typ interface{}, sources map[string]bool) {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		writeTSName(b, v.Name)
+		if sources[v.Name] {
+			b.WriteString("Source")
+		}
+		b.WriteString(" | null")
+	case *ast.List:
+		b.WriteString("Array<")
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			writeTSType(b, w.Ident, sources)
+		case *ast.List_List:
+			writeTSType(b, w.List, sources)
+		case *ast.List_NonNull:
+			writeTSType(b, w.NonNull, sources)
+		}
+		b.WriteString("> | null")
+	case *ast.NonNull:
+		var nb bytes.Buffer
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			writeTSType(&nb, w.Ident, sources)
+		case *ast.NonNull_List:
+			writeTSType(&nb, w.List, sources)
+		}
+		b.Write(bytes.TrimSuffix(nb.Bytes(), []byte(" | null")))
+	}
+}
+
+func writeTSName(b *bytes.Buffer, name string) {
+	switch name {
+	case "Int", "Float":
+		b.WriteString("number")
+	case "String", "ID":
+		b.WriteString("string")
+	case "Boolean":
+		b.WriteString("boolean")
+	default:
+		b.WriteString(name)
+	}
+}
+
+// printVal prints a value
+func (g *Generator) printVal(val interface{}) {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		s := v.Value
+		if v.Kind == token.Token_STRING || v.Kind == token.Token_IDENT {
+			g.WriteString(quote(strings.Trim(s, "\"")))
+			return
+		}
+		g.WriteString(s)
+	case *ast.ListLit:
+		g.printList(v)
+	case *ast.ObjLit:
+		g.printObject(v)
+	case *ast.CompositeLit:
+		switch w := v.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			g.printVal(w.BasicLit)
+		case *ast.CompositeLit_ListLit:
+			g.printVal(w.ListLit)
+		case *ast.CompositeLit_ObjLit:
+			g.printVal(w.ObjLit)
+		}
+	}
+}
+
+func (g *Generator) printList(v *ast.ListLit) {
+	g.WriteByte('[')
+
+	var vals []interface{}
+	switch w := v.List.(type) {
+	case *ast.ListLit_BasicList:
+		for _, bval := range w.BasicList.Values {
+			vals = append(vals, bval)
+		}
+	case *ast.ListLit_CompositeList:
+		for _, cval := range w.CompositeList.Values {
+			vals = append(vals, cval)
+		}
+	}
+
+	vLen := len(vals) - 1
+	for i, iv := range vals {
+		g.printVal(iv)
+		if i != vLen {
+			g.WriteByte(',')
+			g.WriteByte(' ')
+		}
+	}
+
+	g.WriteByte(']')
+}
+
+func (g *Generator) printObject(v *ast.ObjLit) {
+	g.WriteByte('{')
+	g.WriteByte(' ')
+
+	pLen := len(v.Fields) - 1
+	for i, p := range v.Fields {
+		g.WriteString(p.Key.Name)
+		g.WriteString(": ")
+
+		g.printVal(p.Val)
+
+		if i != pLen {
+			g.WriteByte(',')
+		}
+		g.WriteByte(' ')
+	}
+
+	g.WriteByte('}')
+}
+
+// getOptions returns a generator options struct given all generator option metadata from the Doc and CLI.
+// Precedence: CLI over Doc over Default
+//
+func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
+	gOpts = &Options{
+		Module:  "ES6",
+		imports: make([][]byte, 0, 16),
+	}
+
+	// Extract document directive options
+	for _, d := range doc.Directives {
+		if d.Name != "ts" {
+			continue
+		}
+
+		if d.Args == nil {
+			break
+		}
+
+		tsOpts := d.Args.Args[0].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+		for _, arg := range tsOpts.Fields {
+			switch arg.Key.Name {
+			case "module":
+				gOpts.Module = arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+			case "descriptions":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Descriptions = b
+			case "declarations":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Declarations = b
+			}
+		}
+	}
+
+	// Unmarshal cli options
+	if opts == nil {
+		return
+	}
+	if m, ok := opts["module"]; ok {
+		gOpts.Module, _ = m.(string)
+	}
+	if d, ok := opts["descriptions"]; ok {
+		gOpts.Descriptions, _ = d.(bool)
+	}
+	if d, ok := opts["declarations"]; ok {
+		gOpts.Declarations, _ = d.(bool)
+	}
+
+	return
+}
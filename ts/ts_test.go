@@ -0,0 +1,563 @@
+package ts
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var (
+	update = flag.Bool("update", false, "Update expected output file")
+
+	// Flags are used here to allow for the input/output files to be changed during dev
+	// One use case of changing the files is to examine how Generate scales through the benchmark
+	//
+	gqlFileName = flag.String("gqlFile", "test.gql", "Specify a .gql file to use a input for testing.")
+	exDocName   = flag.String("expectedFile", "test.ts", "Specify a file which is the expected generator output from the given .gql file.")
+
+	testDoc *ast.Document
+	exDoc   io.Reader
+)
+
+func TestMain(m *testing.M) {
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	// Parse flags
+	flag.Parse()
+
+	// Assume the input file is in the current working directory
+	if !filepath.IsAbs(*gqlFileName) {
+		*gqlFileName = filepath.Join(wd, *gqlFileName)
+	}
+	f, err := os.Open(*gqlFileName)
+	if err != nil {
+		panic(err)
+	}
+
+	// Assume the output file is in the current working directory
+	if !filepath.IsAbs(*exDocName) {
+		*exDocName = filepath.Join(wd, *exDocName)
+	}
+	exDoc, err = os.Open(*exDocName)
+	if err != nil {
+		panic(err)
+	}
+
+	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", f, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestUpdate(t *testing.T) {
+	if !*update {
+		t.Skipf("not updating expected ts output file: %s", *exDocName)
+		return
+	}
+	t.Logf("updating expected ts output file: %s", *exDocName)
+
+	f, err := os.OpenFile(*exDocName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := new(Generator)
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: f})
+	err = g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestImports(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	t.Run("CommonJS", func(subT *testing.T) {
+		imports := [][]byte{[]byte("GraphQLSchema"), []byte("GraphQLScalarType")}
+
+		var b bytes.Buffer
+		_, err := g.writeImports(&b, &Options{Module: "COMMONJS", imports: imports})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		ex := []byte("import graphql = require('graphql');\n\n")
+		gen.CompareBytes(subT, ex, b.Bytes())
+	})
+
+	t.Run("ES6", func(subT *testing.T) {
+
+		subT.Run("Single", func(triT *testing.T) {
+			imports := [][]byte{[]byte("GraphQLSchema")}
+
+			var b bytes.Buffer
+			_, err := g.writeImports(&b, &Options{Module: "ES6", imports: imports})
+			if err != nil {
+				triT.Error(err)
+				return
+			}
+
+			ex := []byte("import { GraphQLSchema } from 'graphql';\n\n")
+			gen.CompareBytes(triT, ex, b.Bytes())
+		})
+
+		subT.Run("Multiple", func(triT *testing.T) {
+			imports := [][]byte{[]byte("GraphQLSchema"), []byte("GraphQLScalarType")}
+
+			var b bytes.Buffer
+			_, err := g.writeImports(&b, &Options{Module: "ES6", imports: imports})
+			if err != nil {
+				triT.Error(err)
+				return
+			}
+
+			ex := []byte("import {\n  GraphQLSchema,\n  GraphQLScalarType\n} from 'graphql';\n\n")
+			gen.CompareBytes(triT, ex, b.Bytes())
+		})
+	})
+}
+
+func TestSchema(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	ts := &ast.TypeSpec{
+		Type: &ast.TypeSpec_Schema{
+			Schema: &ast.SchemaType{
+				RootOps: &ast.FieldList{List: []*ast.Field{
+					{Name: &ast.Ident{Name: "query"}, Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "Query"}}},
+					{Name: &ast.Ident{Name: "mutation"}, Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "Mutation"}}},
+					{Name: &ast.Ident{Name: "subscription"}, Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "Subscription"}}},
+				}},
+			},
+		},
+	}
+
+	g.generateSchema(ts)
+
+	ex := []byte(`export const Schema: GraphQLSchema = new GraphQLSchema({
+  query: QueryType,
+  mutation: MutationType,
+  subscription: SubscriptionType,
+});
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestAlias(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	t.Run("Scalar", func(subT *testing.T) {
+		g.Reset()
+
+		g.generateAlias("Test", &ast.TypeSpec{Type: &ast.TypeSpec_Scalar{Scalar: &ast.ScalarType{}}})
+
+		ex := []byte("export type Test = any;\n")
+		gen.CompareBytes(subT, ex, g.Bytes())
+	})
+
+	t.Run("Enum", func(subT *testing.T) {
+		g.Reset()
+
+		g.generateAlias("Test", &ast.TypeSpec{Type: &ast.TypeSpec_Enum{
+			Enum: &ast.EnumType{
+				Values: &ast.FieldList{
+					List: []*ast.Field{
+						{Name: &ast.Ident{Name: "A"}},
+						{Name: &ast.Ident{Name: "B"}},
+					},
+				},
+			},
+		}})
+
+		ex := []byte("export type Test = 'A' | 'B';\n")
+		gen.CompareBytes(subT, ex, g.Bytes())
+	})
+
+	t.Run("Input", func(subT *testing.T) {
+		g.Reset()
+
+		g.generateAlias("Test", &ast.TypeSpec{Type: &ast.TypeSpec_Input{
+			Input: &ast.InputType{
+				Fields: &ast.InputValueList{
+					List: []*ast.InputValue{
+						{
+							Name: &ast.Ident{Name: "one"},
+							Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Int"}}}},
+						},
+						{
+							Name: &ast.Ident{Name: "list"},
+							Type: &ast.InputValue_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Point"}}}},
+						},
+					},
+				},
+			},
+		}})
+
+		ex := []byte(`export interface Test {
+  one: number;
+  list?: Array<Point | null> | null;
+}
+`)
+		gen.CompareBytes(subT, ex, g.Bytes())
+	})
+}
+
+func TestScalar(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	ts := &ast.TypeSpec{
+		Name: &ast.Ident{Name: "Test"},
+	}
+
+	g.generateScalar(nil, "Test", false, nil, ts)
+
+	ex := []byte(`export const TestType: GraphQLScalarType = new GraphQLScalarType({
+  name: 'Test',
+  serialize(value: unknown) { /* TODO */ },
+});
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestObject(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Object{
+		Object: &ast.ObjectType{
+			Interfaces: []*ast.Ident{{Name: "A"}},
+			Fields: &ast.FieldList{
+				List: []*ast.Field{
+					{
+						Name: &ast.Ident{Name: "one"},
+						Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "Int"}},
+					},
+					{
+						Name: &ast.Ident{Name: "list"},
+						Type: &ast.Field_List{List: &ast.List{Type: &ast.List_Ident{Ident: &ast.Ident{Name: "Test"}}}},
+					},
+					{
+						Name: &ast.Ident{Name: "withArgs"},
+						Type: &ast.Field_Ident{Ident: &ast.Ident{Name: "String"}},
+						Args: &ast.InputValueList{List: []*ast.InputValue{
+							{
+								Name: &ast.Ident{Name: "str"},
+								Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "String"}}}},
+							},
+							{
+								Name: &ast.Ident{Name: "val"},
+								Type: &ast.InputValue_Ident{Ident: &ast.Ident{Name: "TestEnum"}},
+								Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+									Kind:  token.Token_IDENT,
+									Value: `A_ENUM_VALUE`,
+								}},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}}
+
+	g.generateObject(new(uint16), "Test", false, nil, ts)
+
+	ex := []byte(`export const TestType: GraphQLObjectType<TestSource, Context> = new GraphQLObjectType<TestSource, Context>({
+  name: 'Test',
+  interfaces: () => [AType],
+  fields: () => ({
+    one: {
+      type: GraphQLInt,
+      resolve(source: TestSource, args: {}, context: Context) { /* TODO */ },
+    },
+    list: {
+      type: new GraphQLList(TestType),
+      resolve(source: TestSource, args: {}, context: Context) { /* TODO */ },
+    },
+    withArgs: {
+      type: GraphQLString,
+      args: {
+        str: {
+          type: new GraphQLNonNull(GraphQLString),
+        },
+        val: {
+          type: TestEnumType,
+          defaultValue: 'A_ENUM_VALUE',
+        },
+      },
+      resolve(source: TestSource, args: { str: string; val?: TestEnum | null }, context: Context) { /* TODO */ },
+    },
+  }),
+});
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestUnion(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Union{
+		Union: &ast.UnionType{
+			Members: []*ast.Ident{{Name: "A"}, {Name: "B"}},
+		},
+	}}
+
+	g.generateUnion(new(uint16), "Test", false, nil, ts)
+
+	ex := []byte(`export const TestType: GraphQLUnionType = new GraphQLUnionType({
+  name: 'Test',
+  types: () => [AType, BType],
+  resolveType(value: any, context: Context) { /* TODO */ return undefined; },
+});
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestDirective(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	ts := &ast.TypeSpec{Type: &ast.TypeSpec_Directive{
+		Directive: &ast.DirectiveType{
+			Locs: []*ast.DirectiveLocation{
+				{Loc: ast.DirectiveLocation_QUERY},
+				{Loc: ast.DirectiveLocation_FIELD},
+			},
+			Args: &ast.InputValueList{
+				List: []*ast.InputValue{
+					{
+						Name:    &ast.Ident{Name: "str"},
+						Type:    &ast.InputValue_Ident{Ident: &ast.Ident{Name: "String"}},
+						Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{Kind: token.Token_STRING, Value: "\"it's\""}},
+					},
+				},
+			},
+		},
+	}}
+
+	g.generateDirective(new(uint16), "Test", false, nil, ts)
+
+	ex := []byte(`export const TestType: GraphQLDirective = new GraphQLDirective({
+  name: 'Test',
+  locations: [DirectiveLocation.QUERY, DirectiveLocation.FIELD],
+  args: {
+    str: {
+      type: GraphQLString,
+      defaultValue: 'it\'s',
+    },
+  },
+});
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+func TestDeclarations(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	gqlSrc := `@ts(options: {declarations: true})
+
+enum Direction {
+	NORTH
+	SOUTH
+}
+
+type Query {
+	dir(from: Direction!): Direction
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err = g.Generate(ctx, doc, map[string]interface{}{"module": "COMMONJS"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// gen.TestCtx shares its io.Writer between files so the .d.ts
+	// contents directly follows the .ts contents.
+	ex := []byte(`import graphql = require('graphql');
+
+export type Context = any;
+
+export type Direction = 'NORTH' | 'SOUTH';
+
+export const DirectionType: graphql.GraphQLEnumType = new graphql.GraphQLEnumType({
+  name: 'Direction',
+  values: {
+    NORTH: {
+      value: 'NORTH',
+    },
+    SOUTH: {
+      value: 'SOUTH',
+    },
+  },
+});
+
+export interface QuerySource {
+}
+
+export const QueryType: graphql.GraphQLObjectType<QuerySource, Context> = new graphql.GraphQLObjectType<QuerySource, Context>({
+  name: 'Query',
+  fields: () => ({
+    dir: {
+      type: DirectionType,
+      args: {
+        from: {
+          type: new graphql.GraphQLNonNull(DirectionType),
+        },
+      },
+      resolve(source: QuerySource, args: { from: Direction }, context: Context) { /* TODO */ },
+    },
+  }),
+});
+
+import graphql = require('graphql');
+
+export type Context = any;
+
+export type Direction = 'NORTH' | 'SOUTH';
+
+export declare const DirectionType: graphql.GraphQLEnumType;
+
+export interface QuerySource {
+}
+
+export declare const QueryType: graphql.GraphQLObjectType<QuerySource, Context>;
+
+`)
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	g := new(Generator)
+	g.Reset()
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, map[string]interface{}{"descriptions": true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex, err := ioutil.ReadAll(exDoc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	gen.CompareBytes(t, ex, b.Bytes())
+}
+
+func BenchmarkGenerator_Generate(b *testing.B) {
+	g := new(Generator)
+	g.Reset()
+
+	var buf bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &buf})
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+
+		err := g.Generate(ctx, testDoc, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+	}
+}
+
+func ExampleGenerator_Generate() {
+	g := new(Generator)
+
+	gqlSrc := `schema {
+	query: Query
+}
+
+"Query represents the queries this example provides."
+type Query {
+	hello: String
+}`
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), "example", strings.NewReader(gqlSrc), 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b}) // Pass in an actual
+	err = g.Generate(ctx, doc, map[string]interface{}{"module": "ES6"})
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	fmt.Println(b.String())
+
+	// Output:
+	// import {
+	//   GraphQLSchema,
+	//   GraphQLObjectType,
+	//   GraphQLString
+	// } from 'graphql';
+	//
+	// export type Context = any;
+	//
+	// export interface QuerySource {
+	//   hello?: string | null;
+	// }
+	//
+	// export const QueryType: GraphQLObjectType<QuerySource, Context> = new GraphQLObjectType<QuerySource, Context>({
+	//   name: 'Query',
+	//   fields: () => ({
+	//     hello: {
+	//       type: GraphQLString,
+	//       resolve(source: QuerySource, args: {}, context: Context) { /* TODO */ },
+	//     },
+	//   }),
+	// });
+	//
+	// export const Schema: GraphQLSchema = new GraphQLSchema({
+	//   query: QueryType,
+	// });
+}
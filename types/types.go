@@ -10,11 +10,17 @@ import (
 	"github.com/gqlc/graphql/token"
 )
 
-var dirs []string
+var (
+	dirs     []string
+	registry = make(map[string]*ast.TypeDecl)
+)
 
 // Register registers the types with compiler and tracks custom directives.
 func Register(decls ...*ast.TypeDecl) {
 	for _, decl := range decls {
+		if ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec); ok && ts.TypeSpec.Name != nil {
+			registry[ts.TypeSpec.Name.Name] = decl
+		}
 		if decl.Tok != token.Token_DIRECTIVE {
 			continue
 		}
@@ -34,3 +40,11 @@ func IsGqlcDirective(name string) bool {
 	}
 	return false
 }
+
+// Lookup returns the registered declaration of a type, or directive, e.g.
+// the options a generator reads from its document directive, or nil if
+// there's none.
+//
+func Lookup(name string) *ast.TypeDecl {
+	return registry[name]
+}